@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/rohankatakam/coderisk/internal/clqs"
+)
+
+// runHistory implements `clqs-calculator history`: stored scores over time, and
+// with --fail-if-drop a non-zero exit when the latest run fell against the previous one
+func runHistory(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	repo := fs.String("repo", "", "Repository full name (e.g., 'omnara-ai/omnara')")
+	limit := fs.Int("limit", 30, "Number of most recent runs to show (0 = all)")
+	format := fs.String("format", "text", "Output format: 'text' or 'json'")
+	output := fs.String("output", "", "Output file path (default: stdout)")
+	recalculate := fs.Bool("recalculate", false, "Calculate and store a new score before showing history")
+	failIfDrop := fs.Float64("fail-if-drop", 0, "Exit 1 when CLQS dropped by more than this many points since the previous run (0 = off)")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	fs.Parse(args)
+
+	if *repo == "" {
+		fmt.Fprintf(os.Stderr, "Error: --repo flag is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: %s history --repo OWNER/REPO [OPTIONS]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format: %s (use 'text' or 'json')\n", *format)
+		os.Exit(1)
+	}
+	if *limit == 1 && *failIfDrop > 0 {
+		*limit = 2 // The previous run is needed for comparison
+	}
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	ctx := context.Background()
+	db := connectStagingDB(ctx)
+	defer db.Close()
+
+	repoID, err := db.GetRepositoryIDByFullName(ctx, *repo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Repository '%s' not found: %v\n", *repo, err)
+		os.Exit(1)
+	}
+
+	if *recalculate {
+		if *verbose {
+			fmt.Fprintf(os.Stderr, "Calculating CLQS for %s...\n", *repo)
+		}
+		if _, err := clqs.NewCalculator(db).Calculate(ctx, repoID); err != nil {
+			fmt.Fprintf(os.Stderr, "CLQS calculation failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	scores, err := db.GetCLQSScoreHistory(ctx, repoID, *limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load CLQS history: %v\n", err)
+		os.Exit(1)
+	}
+	history := clqs.BuildHistory(*repo, scores)
+
+	if err := outputHistory(history, *format, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to output history: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *failIfDrop <= 0 {
+		return
+	}
+	if len(history.Entries) < 2 {
+		fmt.Fprintf(os.Stderr, "Only one CLQS run stored; nothing to compare against\n")
+		return
+	}
+	regression := history.CheckDrop(*failIfDrop)
+	if regression == nil {
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\n❌ CLQS dropped %.1f points (%.1f → %.1f) since %s, more than the allowed %.1f\n",
+		regression.Drop, regression.Previous.CLQS, regression.Current.CLQS,
+		regression.Previous.ComputedAt.Format("2006-01-02 15:04"), *failIfDrop)
+	for _, c := range regression.Degraded {
+		fmt.Fprintf(os.Stderr, "   %-23s %.1f → %.1f (%+.1f)\n", c.Name, c.Score-c.Delta, c.Score, c.Delta)
+	}
+	os.Exit(1)
+}
+
+// outputHistory writes the history to the specified destination
+func outputHistory(history *clqs.History, format, outputPath string) error {
+	w := os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == "json" {
+		return clqs.FormatHistoryJSON(history, w)
+	}
+	return clqs.FormatHistory(history, w)
+}
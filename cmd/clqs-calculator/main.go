@@ -14,6 +14,11 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		runHistory(os.Args[2:])
+		return
+	}
+
 	// Command-line flags
 	repo := flag.String("repo", "", "Repository full name (e.g., 'omnara-ai/omnara')")
 	format := flag.String("format", "text", "Output format: 'text' or 'json'")
@@ -24,7 +29,8 @@ func main() {
 
 	if *repo == "" {
 		fmt.Fprintf(os.Stderr, "Error: --repo flag is required\n\n")
-		fmt.Fprintf(os.Stderr, "Usage: %s --repo OWNER/REPO [OPTIONS]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s --repo OWNER/REPO [OPTIONS]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s history --repo OWNER/REPO [OPTIONS]\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		os.Exit(1)
@@ -35,45 +41,9 @@ func main() {
 		log.SetOutput(io.Discard)
 	}
 
-	// Get database connection parameters from environment
-	pgHost := os.Getenv("POSTGRES_HOST")
-	if pgHost == "" {
-		pgHost = "localhost"
-	}
-
-	pgPortStr := os.Getenv("POSTGRES_PORT")
-	if pgPortStr == "" {
-		pgPortStr = "5433"
-	}
-	pgPort, err := strconv.Atoi(pgPortStr)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: invalid POSTGRES_PORT: %v\n", err)
-		os.Exit(1)
-	}
-
-	pgDB := os.Getenv("POSTGRES_DB")
-	if pgDB == "" {
-		pgDB = "coderisk"
-	}
-
-	pgUser := os.Getenv("POSTGRES_USER")
-	if pgUser == "" {
-		pgUser = "coderisk_user"
-	}
-
-	pgPassword := os.Getenv("POSTGRES_PASSWORD")
-	if pgPassword == "" {
-		fmt.Fprintf(os.Stderr, "Error: POSTGRES_PASSWORD environment variable is required\n")
-		os.Exit(1)
-	}
-
 	// Initialize database connection
 	ctx := context.Background()
-	db, err := database.NewStagingClient(ctx, pgHost, pgPort, pgDB, pgUser, pgPassword)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
-		os.Exit(1)
-	}
+	db := connectStagingDB(ctx)
 	defer db.Close()
 
 	// Get repository ID
@@ -119,6 +89,49 @@ func main() {
 	}
 }
 
+// connectStagingDB connects to PostgreSQL using the POSTGRES_* environment variables
+func connectStagingDB(ctx context.Context) *database.StagingClient {
+	// Get database connection parameters from environment
+	pgHost := os.Getenv("POSTGRES_HOST")
+	if pgHost == "" {
+		pgHost = "localhost"
+	}
+
+	pgPortStr := os.Getenv("POSTGRES_PORT")
+	if pgPortStr == "" {
+		pgPortStr = "5433"
+	}
+	pgPort, err := strconv.Atoi(pgPortStr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid POSTGRES_PORT: %v\n", err)
+		os.Exit(1)
+	}
+
+	pgDB := os.Getenv("POSTGRES_DB")
+	if pgDB == "" {
+		pgDB = "coderisk"
+	}
+
+	pgUser := os.Getenv("POSTGRES_USER")
+	if pgUser == "" {
+		pgUser = "coderisk_user"
+	}
+
+	pgPassword := os.Getenv("POSTGRES_PASSWORD")
+	if pgPassword == "" {
+		fmt.Fprintf(os.Stderr, "Error: POSTGRES_PASSWORD environment variable is required\n")
+		os.Exit(1)
+	}
+
+	// Initialize database connection
+	db, err := database.NewStagingClient(ctx, pgHost, pgPort, pgDB, pgUser, pgPassword)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	return db
+}
+
 // buildReportFromCached creates a minimal report from a cached CLQS score
 func buildReportFromCached(ctx context.Context, db *database.StagingClient, cached *database.CLQSScore) *clqs.CLQSReport {
 	repoInfo, _ := db.GetRepositoryInfo(ctx, cached.RepoID)
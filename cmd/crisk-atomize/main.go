@@ -199,6 +199,7 @@ func runAtomize(cmd *cobra.Command, args []string) error {
 			DiffContent: diff,
 			AuthorEmail: authorEmail,
 			Timestamp:   authorDate,
+			RepoPath:    repoPath,
 		})
 	}
 
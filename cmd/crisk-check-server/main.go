@@ -6,8 +6,11 @@ import (
 	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/rohankatakam/coderisk/internal/blastradius"
+	"github.com/rohankatakam/coderisk/internal/database"
 	mcpinternal "github.com/rohankatakam/coderisk/internal/mcp"
 	"github.com/rohankatakam/coderisk/internal/mcp/tools"
 	"github.com/rohankatakam/coderisk/internal/config"
@@ -241,6 +244,57 @@ func main() {
 
 	log.Println("✅ Registered tool: crisk.get_risk_summary")
 
+	// 8b. Add the blast radius tool (results cached per commit in PostgreSQL)
+	type BlastRadiusArgs struct {
+		FilePaths []string `json:"file_paths,omitempty" jsonschema:"changed files relative to the repository root (default: all uncommitted changes)"`
+		RepoRoot  string   `json:"repo_root,omitempty" jsonschema:"repository root path for git commands and path resolution"`
+		MaxDepth  int      `json:"max_depth,omitempty" jsonschema:"hops to follow through IMPORTS, CALLS and co-change edges (default: 3)"`
+	}
+
+	type BlastRadiusOutput struct {
+		Summary     string              `json:"summary,omitempty"`
+		BlastRadius *blastradius.Result `json:"blast_radius,omitempty"`
+		Message     string              `json:"message,omitempty"`
+	}
+
+	blastTool := tools.NewGetBlastRadiusTool(graphClient, database.NewBlastRadiusCache(stdlib.OpenDBFromPool(pgPool)), repoResolver)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "crisk.get_blast_radius",
+		Description: "Computes how far a change can reach: files depending on the changed files directly and transitively (imports, calls, co-change), weighted by distance, plus the HTTP handlers, CLI commands and exported APIs among them. Use when asked what a change could break or how many endpoints it affects.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args BlastRadiusArgs) (*mcp.CallToolResult, BlastRadiusOutput, error) {
+		log.Printf("📞 Blast radius tool called: files=%v, repo_root=%s, max_depth=%d", args.FilePaths, args.RepoRoot, args.MaxDepth)
+
+		effectiveRepoRoot := args.RepoRoot
+		if effectiveRepoRoot == "" {
+			effectiveRepoRoot = repoRoot
+		}
+
+		result, err := blastTool.Execute(ctx, map[string]interface{}{
+			"file_paths": args.FilePaths,
+			"repo_root":  effectiveRepoRoot,
+			"max_depth":  args.MaxDepth,
+		})
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Error: " + err.Error()},
+				},
+				IsError: true,
+			}, BlastRadiusOutput{}, nil
+		}
+
+		resultMap := result.(map[string]interface{})
+		output := BlastRadiusOutput{}
+		output.Summary, _ = resultMap["summary"].(string)
+		output.BlastRadius, _ = resultMap["blast_radius"].(*blastradius.Result)
+		output.Message, _ = resultMap["message"].(string)
+
+		return &mcp.CallToolResult{}, output, nil
+	})
+
+	log.Println("✅ Registered tool: crisk.get_blast_radius")
+
 	// 9. Start server on stdio transport
 	log.Println("🚀 MCP server started on stdio")
 	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil {
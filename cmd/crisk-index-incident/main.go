@@ -42,6 +42,7 @@ Calculates temporal risk signals based on incident history.
 Features:
   • File-level linking: Issue → Commit → File
   • Function-level linking: Issue → Commit → CodeBlock
+  • Bug-introducing commit detection (SZZ) via git blame on fix commits
   • LLM summarization of incident history per block
   • Stores temporal_summary property on CodeBlock nodes
 
@@ -51,15 +52,19 @@ Output: CodeBlocks with temporal_summary property`,
 }
 
 var (
-	repoID  int64
-	verbose bool
-	force   bool
+	repoID   int64
+	verbose  bool
+	force    bool
+	repoPath string
+	skipSZZ  bool
 )
 
 func init() {
 	rootCmd.Flags().Int64Var(&repoID, "repo-id", 0, "Repository ID from PostgreSQL (required)")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	rootCmd.Flags().BoolVar(&force, "force", false, "Force reprocessing of all blocks (ignore temporal_indexed_at)")
+	rootCmd.Flags().StringVar(&repoPath, "repo-path", ".", "Path to the local git clone (used for SZZ blame)")
+	rootCmd.Flags().BoolVar(&skipSZZ, "skip-szz", false, "Skip bug-introducing commit detection")
 
 	rootCmd.MarkFlagRequired("repo-id")
 
@@ -167,6 +172,21 @@ func runIndexIncident(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("  ✓ Updated %d blocks with incident counts (PostgreSQL + Neo4j)\n\n", blocksUpdated)
 
+	// Step 2b: Find the commits that introduced the fixed bugs (SZZ)
+	introducedCount := 0
+	if skipSZZ {
+		fmt.Printf("  ⚠️  Skipping bug-introducing commit detection (--skip-szz)\n\n")
+	} else {
+		fmt.Printf("  Detecting bug-introducing commits (SZZ) in %s...\n", repoPath)
+		szz := risk.NewSZZAnalyzer(db, neo4jBackend, repoPath, repoID)
+		introducedCount, err = szz.DetectBugIntroducingCommits(ctx)
+		if err != nil {
+			fmt.Printf("  ⚠️  Warning: SZZ analysis failed: %v\n\n", err)
+		} else {
+			fmt.Printf("  ✓ Recorded %d bug-introducing commits (INTRODUCED_BY edges)\n\n", introducedCount)
+		}
+	}
+
 	// Step 3: Generate temporal summaries using LLM and sync to Neo4j
 	fmt.Printf("[5/5] Generating temporal summaries...\n")
 	if llmClient != nil && llmClient.IsEnabled() {
@@ -239,6 +259,7 @@ func runIndexIncident(cmd *cobra.Command, args []string) error {
 	fmt.Printf("   Total time: %v\n", totalDuration)
 	fmt.Printf("   Incident links created: %d\n", linkedCount)
 	fmt.Printf("   Blocks updated: %d\n", blocksUpdated)
+	fmt.Printf("   Bug-introducing commits: %d\n", introducedCount)
 	fmt.Printf("   Blocks with incidents: %d/%d (%.1f%%)\n",
 		stats["blocks_with_incidents"],
 		stats["total_blocks"],
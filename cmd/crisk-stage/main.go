@@ -9,7 +9,9 @@ import (
 	"github.com/rohankatakam/coderisk/internal/config"
 	"github.com/rohankatakam/coderisk/internal/database"
 	"github.com/rohankatakam/coderisk/internal/github"
+	"github.com/rohankatakam/coderisk/internal/gitlab"
 	"github.com/rohankatakam/coderisk/internal/ingestion"
+	"github.com/rohankatakam/coderisk/internal/source"
 	"github.com/spf13/cobra"
 )
 
@@ -28,10 +30,11 @@ func main() {
 
 var rootCmd = &cobra.Command{
 	Use:   "crisk-stage",
-	Short: "Stage GitHub data into PostgreSQL",
+	Short: "Stage GitHub or GitLab data into PostgreSQL",
 	Long: `crisk-stage - Microservice 1: Fact Collector
 
-Downloads raw GitHub data and stores it in PostgreSQL staging tables.
+Downloads raw GitHub (or GitLab, with --provider gitlab) data and stores it
+in PostgreSQL staging tables.
 Builds file identity map using git log --follow for canonical path resolution.
 
 This service is idempotent and supports checkpointing for resume capability.
@@ -53,14 +56,18 @@ var (
 	repoPath  string
 	days      int
 	verbose   bool
+	provider  string
+	gitlabURL string
 )
 
 func init() {
-	rootCmd.Flags().StringVar(&repoOwner, "owner", "", "GitHub repository owner (required)")
+	rootCmd.Flags().StringVar(&repoOwner, "owner", "", "Repository owner or GitLab group path (required)")
 	rootCmd.Flags().StringVar(&repoName, "repo", "", "GitHub repository name (required)")
 	rootCmd.Flags().StringVar(&repoPath, "path", "", "Local repository path (required)")
 	rootCmd.Flags().IntVar(&days, "days", 0, "Fetch last N days only (0 = all history)")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	rootCmd.Flags().StringVar(&provider, "provider", "github", "Hosting provider: github, gitlab")
+	rootCmd.Flags().StringVar(&gitlabURL, "gitlab-url", config.GetString("GITLAB_URL", gitlab.DefaultBaseURL), "GitLab instance URL (env: GITLAB_URL)")
 
 	rootCmd.MarkFlagRequired("owner")
 	rootCmd.MarkFlagRequired("repo")
@@ -98,10 +105,19 @@ func runStage(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("configuration validation failed:\n%s", result.Error())
 	}
 
-	// Get GitHub token
-	githubToken := os.Getenv("GITHUB_TOKEN")
-	if githubToken == "" {
-		return fmt.Errorf("GITHUB_TOKEN environment variable not set")
+	sourceProvider, err := source.ParseProvider(provider)
+	if err != nil {
+		return err
+	}
+
+	// Get provider token
+	tokenVar := "GITHUB_TOKEN"
+	if sourceProvider == source.ProviderGitLab {
+		tokenVar = "GITLAB_TOKEN"
+	}
+	token := os.Getenv(tokenVar)
+	if token == "" {
+		return fmt.Errorf("%s environment variable not set", tokenVar)
 	}
 
 	// Connect to PostgreSQL
@@ -120,11 +136,17 @@ func runStage(cmd *cobra.Command, args []string) error {
 	defer stagingDB.Close()
 	fmt.Printf("  ✓ Connected to PostgreSQL\n\n")
 
-	// Fetch GitHub data
-	fmt.Printf("[2/3] Fetching GitHub API data...\n")
+	// Fetch provider data
+	var fetcher source.Fetcher
+	if sourceProvider == source.ProviderGitLab {
+		fmt.Printf("[2/3] Fetching GitLab API data...\n")
+		fetcher = gitlab.NewFetcher(gitlabURL, token, stagingDB)
+	} else {
+		fmt.Printf("[2/3] Fetching GitHub API data...\n")
+		fetcher = github.NewFetcher(token, stagingDB)
+	}
 	fetchStart := time.Now()
 
-	fetcher := github.NewFetcher(githubToken, stagingDB)
 	repoID, stats, err := fetcher.FetchAll(ctx, repoOwner, repoName, repoPath, days)
 	if err != nil {
 		return fmt.Errorf("fetch failed: %w", err)
@@ -10,6 +10,8 @@ import (
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/rohankatakam/coderisk/internal/config"
 	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/github"
+	"github.com/rohankatakam/coderisk/internal/source"
 	"github.com/rohankatakam/coderisk/internal/sync"
 	"github.com/rohankatakam/coderisk/internal/validation"
 	"github.com/spf13/cobra"
@@ -37,7 +39,8 @@ Validates and repairs data consistency between PostgreSQL (source of truth)
 and Neo4j (derived cache). Implements the Postgres-First Write Protocol.
 
 Modes:
-  incremental   Sync delta only (missing entities) - fast, minutes
+  incremental   Fetch new/updated GitHub data (ETags and since cursors),
+                then sync delta only (missing entities) - fast, minutes
   full          Rebuild entire Neo4j graph from Postgres - slow, hours
   validate-only Report discrepancies without modification - fast, seconds
 
@@ -48,6 +51,7 @@ Exit Codes:
 
 Examples:
   crisk-sync --repo-id 11 --mode incremental
+  crisk-sync --repo-id 11 --mode incremental --skip-fetch
   crisk-sync --repo-id 11 --mode full --dry-run
   crisk-sync --repo-id 11 --mode validate-only`,
 	Version: Version,
@@ -59,6 +63,7 @@ var (
 	mode         string
 	dryRun       bool
 	validateOnly bool
+	skipFetch    bool
 )
 
 func init() {
@@ -66,6 +71,7 @@ func init() {
 	rootCmd.Flags().StringVar(&mode, "mode", "incremental", "Sync mode: incremental, full, validate-only")
 	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Report actions without executing")
 	rootCmd.Flags().BoolVar(&validateOnly, "validate-only", false, "Validate only (alias for --mode validate-only)")
+	rootCmd.Flags().BoolVar(&skipFetch, "skip-fetch", false, "Skip the incremental GitHub fetch in incremental mode")
 
 	rootCmd.MarkFlagRequired("repo-id")
 
@@ -156,6 +162,11 @@ func runSync(cmd *cobra.Command, args []string) error {
 	var exitCode int
 	switch mode {
 	case "incremental":
+		if !skipFetch && !dryRun {
+			if err := fetchIncremental(ctx, stagingDB, repoID); err != nil {
+				return err
+			}
+		}
 		exitCode, err = syncIncremental(ctx, stagingDB.DB(), neoDriver, repoID, dryRun)
 	case "full":
 		exitCode, err = syncFull(ctx, stagingDB.DB(), neoDriver, repoID, dryRun)
@@ -177,6 +188,47 @@ func runSync(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// fetchIncremental pulls commits, issues and PRs created or updated since the last
+// run into the staging tables before the Neo4j delta sync
+// Skipped (with a notice) when GITHUB_TOKEN is unset or the repo was not staged from GitHub
+func fetchIncremental(ctx context.Context, stagingDB *database.StagingClient, repoID int64) error {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		fmt.Printf("  ℹ️  GITHUB_TOKEN not set, skipping incremental GitHub fetch\n")
+		return nil
+	}
+
+	provider, err := stagingDB.GetRepositoryProvider(ctx, repoID)
+	if err != nil {
+		return err
+	}
+	if provider != string(source.ProviderGitHub) {
+		fmt.Printf("  ℹ️  Repository was staged from %s, not GitHub, skipping incremental fetch\n", provider)
+		return nil
+	}
+
+	var owner, name, absolutePath string
+	err = stagingDB.QueryRow(ctx,
+		"SELECT owner, name, COALESCE(absolute_path, '') FROM github_repositories WHERE id = $1",
+		repoID,
+	).Scan(&owner, &name, &absolutePath)
+	if err != nil {
+		return fmt.Errorf("failed to load repository %d: %w", repoID, err)
+	}
+
+	fetcher := github.NewFetcher(token, stagingDB)
+	_, stats, err := fetcher.FetchIncremental(ctx, owner, name, absolutePath, 0)
+	if err != nil {
+		return fmt.Errorf("incremental GitHub fetch failed: %w", err)
+	}
+
+	fmt.Printf("\n📥 Incremental fetch: %d commits, %d issues, %d PRs new or updated\n",
+		stats.Commits, stats.Issues, stats.PRs)
+	fmt.Printf("   API calls: %d made, %d not modified (free), ~%d saved\n\n",
+		stats.APICalls, stats.NotModified, stats.APICallsSaved)
+	return nil
+}
+
 func syncIncremental(ctx context.Context, db *sql.DB, driver neo4j.DriverWithContext, repoID int64, dryRun bool) (int, error) {
 	validator := validation.NewConsistencyValidator(db, driver)
 
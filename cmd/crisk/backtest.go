@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/rohankatakam/coderisk/internal/backtest"
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var backtestCmd = &cobra.Command{
+	Use:   "backtest",
+	Short: "Validate CodeRisk against repository history",
+}
+
+var backtestRiskCmd = &cobra.Command{
+	Use:   "risk",
+	Short: "Measure how well risk verdicts predicted bug-introducing commits",
+	Long: `Replay commit history and score each commit with the metrics 'crisk check' would
+have computed at the time, using only earlier commits. Commits blamed by SZZ for a
+later fix (crisk-index-incident) are the positives.
+
+Reports AUC, precision and recall per metric and per risk config, plus the share of
+commits each config would have blocked. Commits from the last --horizon-days are
+replayed but not scored, since their bugs may not have been found yet.
+
+Examples:
+  # Summary for every config
+  crisk backtest risk
+
+  # Compare two configs, counting only confident SZZ results
+  crisk backtest risk --config go_backend --config default --min-confidence 0.5
+
+  # Full per-commit results for analysis
+  crisk backtest risk --format json -o risk_backtest.json`,
+	Args: cobra.NoArgs,
+	RunE: runBacktestRisk,
+}
+
+func init() {
+	defaults := backtest.DefaultRiskOptions()
+
+	backtestRiskCmd.Flags().Float64("min-confidence", defaults.MinConfidence, "SZZ confidence at which a commit counts as bug-introducing")
+	backtestRiskCmd.Flags().Int("horizon-days", defaults.HorizonDays, "Do not score commits from the last N days of history")
+	backtestRiskCmd.Flags().StringSlice("config", nil, "Risk configs to evaluate (default: all)")
+	backtestRiskCmd.Flags().String("format", "text", "Output format: text, json")
+	backtestRiskCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+
+	backtestCmd.AddCommand(backtestRiskCmd)
+}
+
+func runBacktestRisk(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	opts := backtest.DefaultRiskOptions()
+	opts.MinConfidence, _ = cmd.Flags().GetFloat64("min-confidence")
+	opts.HorizonDays, _ = cmd.Flags().GetInt("horizon-days")
+	opts.ConfigKeys, _ = cmd.Flags().GetStringSlice("config")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid format %q, must be: text or json", format)
+	}
+
+	db, err := initPostgresSQLX()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	repoID, repoName, err := cli.DetectRepoID(ctx, db.DB)
+	if err != nil {
+		return err
+	}
+
+	report, err := backtest.RunRiskBacktest(ctx, db, repoID, repoName, opts)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if format == "json" {
+		err = backtest.FormatRiskReportJSON(&buf, report)
+	} else {
+		err = backtest.FormatRiskReport(&buf, report)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	if outputPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+	fmt.Printf("✓ Wrote risk backtest of %d commits to %s\n", report.CommitsEvaluated, outputPath)
+	return nil
+}
@@ -3,22 +3,30 @@ package main
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/rohankatakam/coderisk/internal/agent"
+	"github.com/rohankatakam/coderisk/internal/apisurface"
 	"github.com/rohankatakam/coderisk/internal/config"
 	"github.com/rohankatakam/coderisk/internal/auth"
+	"github.com/rohankatakam/coderisk/internal/blastradius"
+	"github.com/rohankatakam/coderisk/internal/changetype"
 	appconfig "github.com/rohankatakam/coderisk/internal/config"
 	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/depgraph"
 	"github.com/rohankatakam/coderisk/internal/git"
 	"github.com/rohankatakam/coderisk/internal/graph"
 	"github.com/rohankatakam/coderisk/internal/incidents"
 	"github.com/rohankatakam/coderisk/internal/llm"
+	"github.com/rohankatakam/coderisk/internal/manifest"
 	"github.com/rohankatakam/coderisk/internal/metrics"
+	"github.com/rohankatakam/coderisk/internal/migration"
 	"github.com/rohankatakam/coderisk/internal/output"
 	_ "github.com/jackc/pgx/v5/stdlib" // pgx driver for sqlx
 	"github.com/jmoiron/sqlx"
@@ -47,6 +55,9 @@ func init() {
 	checkCmd.Flags().Bool("ai-mode", false, "Output machine-readable JSON for AI assistants")
 	checkCmd.Flags().Bool("pre-commit", false, "Run in pre-commit hook mode (checks staged files)")
 	checkCmd.Flags().Bool("no-ai", false, "Skip Phase 2 LLM investigation (Phase 1 quantitative metrics only)")
+	checkCmd.Flags().Int("blast-depth", 3, "Hops to follow when computing the transitive blast radius (0 disables it)")
+	checkCmd.Flags().Bool("assess-all", false, "Assess every file, including formatting-only, moved and generated changes")
+	checkCmd.Flags().StringSlice("large-tables", nil, "Tables large enough that blocking migration operations on them are HIGH risk")
 
 	// Mutually exclusive flags
 	checkCmd.MarkFlagsMutuallyExclusive("quiet", "explain", "ai-mode")
@@ -164,8 +175,8 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	}
 	defer sqlxDB.Close()
 
-	// Create incidents database for Phase 2
-	_ = incidents.NewDatabase(sqlxDB) // Not used in new agent system
+	// Create incidents database (manually recorded incidents feed Phase 1)
+	incidentsDB := incidents.NewDatabase(sqlxDB)
 
 	// Create hybrid client for combined Neo4j + Postgres queries
 	hybridClient := database.NewHybridClient(neo4jClient, stagingClient)
@@ -221,16 +232,35 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	}
 
 	// Load CLQS score for confidence display (if repository is in database)
+	var dbRepoID int64
 	if repoID != "local" && stagingClient != nil {
 		// Query database to get numeric repo ID
-		dbRepoID, err := stagingClient.GetRepositoryID(ctx, repoID)
-		if err == nil {
+		if id, err := stagingClient.GetRepositoryID(ctx, repoID); err == nil {
+			dbRepoID = id
 			// Load CLQS score (gracefully handles missing scores)
 			clqsScore, _ = loadCLQSScore(ctx, stagingClient, dbRepoID)
 		}
 	}
 
+	// Changed lines for coverage lookups (crisk coverage import); coverage reports describe
+	// HEAD, and uncommitted changes are diffed against it (only the staged ones in pre-commit)
+	var headSHA string
+	var diffHunks map[string][]git.Hunk
+	if dbRepoID != 0 {
+		headSHA, _ = git.GetCurrentCommitSHA()
+		if preCommit {
+			diffHunks, err = git.GetStagedDiffHunks()
+		} else {
+			diffHunks, err = git.GetDiffHunks("", "")
+		}
+		if err != nil {
+			slog.Warn("could not read diff hunks, skipping coverage", "error", err)
+			headSHA = ""
+		}
+	}
+
 	hasHighRisk := false
+	var blastPaths []string // Current and historical paths of every checked file
 
 	// Select adaptive configuration based on repository characteristics
 	// Reference: ADR-005 §2 - Adaptive Configuration Selection
@@ -251,6 +281,26 @@ func runCheck(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get repository root: %w", err)
 	}
+	// The working tree's import graph, built at most once for the analyses that need it
+	imports := &importGraph{repoRoot: repoRoot}
+
+	// Classify each file's change: formatting, pure moves, docs and generated code skip
+	// assessment, and mechanical refactors, test and dependency changes are scaled down.
+	// Pre-commit mode classifies the staged change, which is what gets committed.
+	assessAll, _ := cmd.Flags().GetBool("assess-all")
+	var changeTypes map[string]changetype.Classification
+	if diff, err := changeDiff(preCommit); err != nil {
+		slog.Warn("could not read diff, skipping change classification", "error", err)
+	} else {
+		src := changetype.Source{Before: readAtHead(repoRoot), After: readChanged(repoRoot, preCommit)}
+		changeTypes = changetype.ByPath(changetype.ClassifyDiff(diff, src))
+	}
+
+	largeTables, _ := cmd.Flags().GetStringSlice("large-tables")
+	migrationOpts := migration.Options{LargeTables: largeTables}
+
+	// Exported Go symbols and API specifications the change breaks, with their callers
+	apiReport := analyzeAPIChanges(ctx, neo4jClient, dbRepoID, repoRoot, imports, files, preCommit)
 
 	// Create file resolver to bridge current paths to historical graph data
 	// Uses 2-level strategy: exact match (100% confidence) -> git log --follow (95% confidence)
@@ -269,7 +319,20 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		"duration", resolveDuration,
 		"files_resolved", len(resolvedFilesMap))
 
+	// Phase 2 model client, created when the first file escalates
+	var phase2Client *llm.Client
+	var phase2Err error
+
 	for _, file := range files {
+		changeType, classified := changeTypes[repoRelativePath(repoRoot, file)]
+		if classified && changeType.Action == changetype.ActionSkip && !assessAll {
+			slog.Info("skipping file by change type", "file", file, "change_type", changeType.Kind)
+			if !quiet && !aiMode {
+				fmt.Printf("⏭️  Skipping %s: %s\n", file, changeType)
+			}
+			continue
+		}
+
 		matches := resolvedFilesMap[file]
 		slog.Info("processing file", "file", file, "matches", len(matches))
 
@@ -305,6 +368,8 @@ func runCheck(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		blastPaths = append(blastPaths, queryPaths...)
+
 		// Phase 1: Baseline Assessment with Adaptive Config
 		// CRITICAL: Pass ALL historical paths to capture full history across renames
 		slog.Info("=== PHASE 1 METRICS STAGE ===",
@@ -323,6 +388,29 @@ func runCheck(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Measured coverage of the changed lines replaces the test ratio heuristic when
+		// a report was imported for HEAD
+		if headSHA != "" {
+			rel := repoRelativePath(repoRoot, file)
+			applyChangedLineCoverage(ctx, stagingClient, dbRepoID, headSHA, rel, diffHunks[rel], adaptiveResult)
+		}
+
+		// Recorded incidents linked to this file (crisk incident link)
+		applyIncidents(ctx, incidentsDB, queryPaths, adaptiveResult.Phase1Result)
+
+		// Block complexity deltas of the uncommitted change against HEAD
+		complexityResult := applyComplexity(repoRoot, file, preCommit, adaptiveResult)
+
+		// Destructive and locking operations the change adds to a SQL migration
+		migrationResult := applyMigration(repoRoot, file, preCommit, migrationOpts, adaptiveResult)
+
+		// Breaking changes to the file's exported API or specification
+		apiResult := applyAPIChanges(apiReport, repoRelativePath(repoRoot, file), adaptiveResult)
+
+		if classified {
+			metrics.ApplyChangeType(adaptiveResult.Phase1Result, &changeType)
+		}
+
 		slog.Info("phase 1 complete",
 			"duration", phase1Duration,
 			"overall_risk", adaptiveResult.OverallRisk,
@@ -346,6 +434,16 @@ func runCheck(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("formatting error: %w", err)
 		}
 
+		if complexityResult != nil && !quiet && !aiMode {
+			printComplexityDeltas(complexityResult)
+		}
+		if migrationResult != nil && !quiet && !aiMode {
+			printMigrationFindings(migrationResult)
+		}
+		if apiResult != nil && !quiet && !aiMode {
+			printAPIChanges(apiResult)
+		}
+
 		if adaptiveResult.ShouldEscalate {
 			hasHighRisk = true
 
@@ -359,19 +457,28 @@ func runCheck(cmd *cobra.Command, args []string) error {
 				continue
 			}
 
-			// Use Gemini API key from cloud or environment
+			// Phase 2 uses the provider LLM_PROVIDER selects (Gemini by default), with the
+			// Gemini key from cloud credentials or the environment
 			// 12-factor: Factor 3 - Configuration from environment
-			if geminiAPIKey == "" {
+			if phase2Client == nil && phase2Err == nil {
+				phase2Client, phase2Err = newPhase2Client(ctx, geminiAPIKey)
+			}
+			if errors.Is(phase2Err, llm.ErrNoAPIKey) {
 				// No API key - show message and continue
 				if !preCommit {
 					fmt.Println("\n⚠️  HIGH RISK detected")
 					fmt.Println("    Enable Phase 2 LLM investigation by either:")
 					fmt.Println("      1. Running 'crisk login' to use cloud credentials")
-					fmt.Println("      2. Setting GEMINI_API_KEY environment variable")
+					fmt.Println("      2. Setting GEMINI_API_KEY (or LLM_PROVIDER=openai with OPENAI_API_KEY)")
 					fmt.Println("      3. Using --no-ai flag to skip Phase 2")
 				}
 				continue
 			}
+			if phase2Err != nil {
+				fmt.Printf("❌ LLM client error: %v\n", phase2Err)
+				slog.Error("failed to create LLM client", "error", phase2Err)
+				continue
+			}
 
 			// Phase 2: Agent-Based Investigation with Complete Pipeline
 			// 12-factor: Factor 8 - Own your control flow (selective investigation)
@@ -442,25 +549,14 @@ func runCheck(cmd *cobra.Command, args []string) error {
 				"prompt_length", len(kickoffPrompt),
 				"estimated_tokens", len(kickoffPrompt)/4)
 
-			// STEP 4: Create LLM client and investigator
-			slog.Info("STEP 4: Creating LLM investigator")
-			// Use Redis address from environment or default to docker-compose setup
-			redisAddr := os.Getenv("REDIS_ADDR")
-			if redisAddr == "" {
-				redisAddr = "localhost:6380" // Default from docker-compose.yml
-			}
-			geminiClient, err := llm.NewGeminiClient(ctx, geminiAPIKey, "gemini-2.0-flash", redisAddr)
-			if err != nil {
-				fmt.Printf("❌ LLM client error: %v\n", err)
-				slog.Error("failed to create LLM client", "error", err)
-				continue
-			}
+			// STEP 4: Create the investigator
+			slog.Info("STEP 4: Creating LLM investigator", "provider", phase2Client.GetProvider())
 
 			// Create Postgres adapter
 			pgAdapter := agent.NewPostgresAdapter(stagingClient)
 
-			// Create GeminiInvestigator with graph, postgres, and hybrid clients
-			investigator := agent.NewGeminiInvestigator(geminiClient, neo4jClient, pgAdapter, hybridClient)
+			// Create the tool-calling investigator with graph, postgres, and hybrid clients
+			investigator := agent.NewInvestigator(phase2Client, neo4jClient, pgAdapter, hybridClient)
 			slog.Info("STEP 4 complete", "investigator_created", true)
 
 			// STEP 5: Run agent investigation
@@ -631,6 +727,17 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Transitive blast radius of the change set as a whole
+	blastDepth, _ := cmd.Flags().GetInt("blast-depth")
+	if !quiet && !aiMode && blastDepth > 0 && len(blastPaths) > 0 {
+		reportBlastRadius(ctx, neo4jClient, stagingClient, dbRepoID, repoRoot, blastPaths, blastDepth)
+	}
+
+	// Packages added, removed or bumped in changed manifests and lockfiles
+	if !quiet && !aiMode {
+		reportDependencyChanges(repoRoot, imports, files, preCommit)
+	}
+
 	// Post usage telemetry if authenticated
 	if authManager != nil {
 		// TODO: Track actual OpenAI tokens used during Phase 2
@@ -639,7 +746,7 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		estimatedTokens := 0
 		estimatedCost := 0.0
 
-		if hasHighRisk && geminiAPIKey != "" {
+		if hasHighRisk && phase2Client != nil {
 			// Rough estimate: 1000 tokens per high-risk file with Phase 2
 			estimatedTokens = 1000 * totalFiles
 			// Estimate cost: Much cheaper with Gemini (~$0.001 per 1000 tokens)
@@ -738,6 +845,333 @@ func initPostgres(ctx context.Context) (*database.Client, error) {
 	return client, nil
 }
 
+// applyIncidents adds the incidents recorded against any of the file's historical paths
+// Lookup failures are logged and leave the metric unset.
+func applyIncidents(ctx context.Context, incidentsDB *incidents.Database, queryPaths []string, result *metrics.Phase1Result) {
+	incidentResult, err := metrics.CalculateIncidentHistoryMultiple(ctx, incidentsDB, queryPaths)
+	if err != nil {
+		slog.Warn("incident history lookup failed", "file", queryPaths[0], "error", err)
+		return
+	}
+	metrics.ApplyIncidents(result, incidentResult)
+}
+
+// applyChangedLineCoverage sets the changed-line coverage metric when a report exists for the commit
+// file is relative to the repository root, as coverage reports and diffs record it.
+// Lookup failures are logged and leave the test ratio heuristic in place.
+func applyChangedLineCoverage(ctx context.Context, stagingClient *database.StagingClient, repoID int64, commitSHA, file string, hunks []git.Hunk, result *metrics.AdaptivePhase1Result) {
+	fc, err := stagingClient.GetFileCoverage(ctx, repoID, commitSHA, file)
+	if err != nil {
+		slog.Warn("coverage lookup failed", "file", file, "error", err)
+		return
+	}
+	if fc == nil {
+		return // No report for this commit: keep the heuristic
+	}
+
+	blocks, err := stagingClient.GetBlockCoverage(ctx, repoID, commitSHA, file)
+	if err != nil {
+		slog.Warn("block coverage lookup failed", "file", file, "error", err)
+	}
+
+	cov := metrics.CalculateChangedLineCoverage(file, commitSHA, fc, blocks, hunks)
+	metrics.ApplyCoverage(result.Phase1Result, cov, result.SelectedConfig)
+}
+
+// applyComplexity sets the block complexity metric for a changed file, measuring the
+// staged version in pre-commit mode
+// Unsupported languages and read failures leave the result unchanged and return nil.
+func applyComplexity(repoRoot, file string, staged bool, result *metrics.AdaptivePhase1Result) *metrics.ComplexityResult {
+	cx, err := metrics.CalculateComplexity(repoRoot, "HEAD", repoRelativePath(repoRoot, file), staged)
+	if err != nil {
+		slog.Warn("complexity calculation failed", "file", file, "error", err)
+		return nil
+	}
+	if cx == nil {
+		return nil
+	}
+	metrics.ApplyComplexity(result.Phase1Result, cx)
+	return cx
+}
+
+// applyMigration sets the SQL migration metric for a changed migration file, analyzing
+// the staged version in pre-commit mode
+// Other files and read failures leave the result unchanged and return nil.
+func applyMigration(repoRoot, file string, staged bool, opts migration.Options, result *metrics.AdaptivePhase1Result) *metrics.MigrationResult {
+	m, err := metrics.CalculateMigration(repoRoot, "HEAD", repoRelativePath(repoRoot, file), staged, opts)
+	if err != nil {
+		slog.Warn("migration analysis failed", "file", file, "error", err)
+		return nil
+	}
+	if m == nil {
+		return nil
+	}
+	metrics.ApplyMigration(result.Phase1Result, m)
+	return m
+}
+
+// printMigrationFindings lists the risky operations found in a migration
+func printMigrationFindings(m *metrics.MigrationResult) {
+	if len(m.Findings) == 0 {
+		return
+	}
+	fmt.Printf("\n🗄️  Migration: %d risky operation(s) in %d statement(s)\n", len(m.Findings), m.Statements)
+	const maxShown = 10
+	for i, f := range m.Findings {
+		if i == maxShown {
+			fmt.Printf("   … and %d more\n", len(m.Findings)-maxShown)
+			break
+		}
+		fmt.Printf("   %s line %d: %s\n", riskMarker(f.Severity), f.Line, f.Message)
+	}
+}
+
+// repoRelativePath returns file relative to the repository root, as git reports paths
+func repoRelativePath(repoRoot, file string) string {
+	if filepath.IsAbs(file) {
+		if rel, err := filepath.Rel(repoRoot, file); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return file
+}
+
+// printComplexityDeltas lists how the change moved each block's complexity
+func printComplexityDeltas(cx *metrics.ComplexityResult) {
+	const limit = 5
+	for i, d := range cx.Deltas {
+		if i == limit {
+			fmt.Printf("   ... and %d more block(s)\n", len(cx.Deltas)-limit)
+			break
+		}
+		fmt.Printf("📈 Complexity: this change %s\n", d.Describe())
+	}
+}
+
+// reportBlastRadius prints the dependents and public entry points the change can reach
+// Results are cached per HEAD commit; failures are logged and do not fail the check.
+func reportBlastRadius(ctx context.Context, neo4jClient *graph.Client, stagingClient *database.StagingClient, repoID int64, repoRoot string, paths []string, depth int) {
+	var cache blastradius.Cache
+	if stagingClient != nil {
+		cache = database.NewBlastRadiusCache(stagingClient.DB())
+	}
+	headSHA, _ := git.GetCurrentCommitSHA()
+
+	opts := blastradius.DefaultOptions()
+	opts.MaxDepth = depth
+	result, err := blastradius.NewEngine(neo4jClient, cache, repoRoot, opts).Compute(ctx, repoID, headSHA, paths)
+	if err != nil {
+		slog.Warn("blast radius computation failed", "error", err)
+		return
+	}
+	if result.TotalDependents() == 0 {
+		return
+	}
+
+	fmt.Printf("\n💥 Blast radius: this change %s\n", result.Summary())
+	const maxShown = 5
+	for i, ep := range result.EntryPoints {
+		if i == maxShown {
+			fmt.Printf("   … and %d more\n", len(result.EntryPoints)-maxShown)
+			break
+		}
+		target := ep.File
+		if ep.Block != "" {
+			target += ":" + ep.Block
+		}
+		fmt.Printf("   • %-12s %s (%s, %d hop(s) away)\n", ep.Kind, target, ep.Reason, ep.Depth)
+	}
+}
+
+// importGraph builds the working tree's import graph on first use, so a check walks and
+// parses the repository once however many analyses ask for it
+type importGraph struct {
+	repoRoot string
+	built    bool
+	result   *depgraph.Result
+	err      error
+}
+
+// get returns the import graph, analyzing the repository on the first call
+func (g *importGraph) get() (*depgraph.Result, error) {
+	if !g.built {
+		g.result, g.err = depgraph.Analyze(g.repoRoot)
+		g.built = true
+	}
+	return g.result, g.err
+}
+
+// reportDependencyChanges prints the dependency-risk section for changed manifests and
+// lockfiles: package changes against HEAD (staged ones in pre-commit mode), their version
+// jumps, and the source files importing them. Failures are logged and do not fail the check.
+func reportDependencyChanges(repoRoot string, imports *importGraph, files []string, staged bool) {
+	var manifests []string
+	for _, f := range files {
+		if rel := repoRelativePath(repoRoot, f); manifest.IsManifest(rel) {
+			manifests = append(manifests, rel)
+		}
+	}
+	if len(manifests) == 0 {
+		return
+	}
+
+	graphResult, err := imports.get()
+	if err != nil {
+		slog.Warn("import analysis failed, dependency importers unavailable", "error", err)
+	}
+	report, err := manifest.Analyze(manifests, readAtHead(repoRoot), readChanged(repoRoot, staged), graphResult)
+	if err != nil {
+		slog.Warn("dependency analysis failed", "error", err)
+		return
+	}
+	if len(report.Changes) == 0 {
+		return
+	}
+
+	fmt.Printf("\n📦 Dependency changes (%s risk) in %s\n", report.Risk, strings.Join(report.Manifests, ", "))
+	const maxShown = 10
+	for i, c := range report.Changes {
+		if i == maxShown {
+			fmt.Printf("   … and %d more\n", len(report.Changes)-maxShown)
+			break
+		}
+		fmt.Printf("   %s %s\n", riskMarker(c.Risk), c.Describe())
+		if len(c.Importers) > 0 {
+			shown := c.Importers[:min(3, len(c.Importers))]
+			more := ""
+			if extra := len(c.Importers) - len(shown); extra > 0 {
+				more = fmt.Sprintf(" (+%d more)", extra)
+			}
+			fmt.Printf("      imported by %s%s\n", strings.Join(shown, ", "), more)
+		}
+	}
+}
+
+// analyzeAPIChanges compares the exported Go API and the OpenAPI/JSON Schema documents of
+// the changed files (staged versions in pre-commit mode) with HEAD, looking up callers of
+// breaking changes in the ingested graph and the working tree's import graph. Failures
+// are logged and return nil.
+func analyzeAPIChanges(ctx context.Context, neo4jClient *graph.Client, repoID int64, repoRoot string, imports *importGraph, files []string, staged bool) *apisurface.Report {
+	rel := make([]string, len(files))
+	hasGo := false
+	for i, f := range files {
+		rel[i] = repoRelativePath(repoRoot, f)
+		hasGo = hasGo || strings.HasSuffix(rel[i], ".go")
+	}
+
+	callers := &apisurface.Callers{RepoID: repoID}
+	if neo4jClient != nil {
+		callers.Graph = neo4jClient
+	}
+	var sources []string
+	if hasGo {
+		if graphResult, err := imports.get(); err != nil {
+			slog.Warn("import analysis failed, API callers limited to the graph", "error", err)
+		} else {
+			callers.Static = graphResult
+			sources = graphResult.Files
+		}
+	}
+
+	report, err := apisurface.Analyze(ctx, rel, readAtHead(repoRoot), readChanged(repoRoot, staged), sources, callers)
+	if err != nil {
+		slog.Warn("API surface analysis failed", "error", err)
+		return nil
+	}
+	return report
+}
+
+// applyAPIChanges sets the API surface metric for a file whose exported API changed
+func applyAPIChanges(report *apisurface.Report, file string, result *metrics.AdaptivePhase1Result) *metrics.APIChangeResult {
+	if report == nil {
+		return nil
+	}
+	api := metrics.NewAPIChangeResult(file, report.ForFile(file))
+	if api == nil {
+		return nil
+	}
+	metrics.ApplyAPIChanges(result.Phase1Result, api)
+	return api
+}
+
+// printAPIChanges lists a file's breaking API changes and who calls the changed symbols
+func printAPIChanges(api *metrics.APIChangeResult) {
+	if api.Breaking == 0 {
+		return
+	}
+	fmt.Printf("\n🔌 API: %d breaking change(s)\n", api.Breaking)
+	const maxShown = 10
+	shown := 0
+	for _, c := range api.Changes {
+		if !c.Breaking {
+			continue
+		}
+		if shown == maxShown {
+			fmt.Printf("   … and %d more\n", api.Breaking-maxShown)
+			break
+		}
+		shown++
+		fmt.Printf("   %s %s\n", riskMarker(c.Severity), c.Description)
+		if len(c.Callers) > 0 {
+			listed := c.Callers[:min(3, len(c.Callers))]
+			more := ""
+			if extra := len(c.Callers) - len(listed); extra > 0 {
+				more = fmt.Sprintf(" (+%d more)", extra)
+			}
+			fmt.Printf("      called from %s%s\n", strings.Join(listed, ", "), more)
+		}
+	}
+}
+
+// readAtHead reads files as of HEAD; files missing there were added by the change
+func readAtHead(repoRoot string) func(string) ([]byte, error) {
+	return func(file string) ([]byte, error) {
+		content, err := git.ShowFile(repoRoot, "HEAD", file)
+		if err != nil {
+			return nil, nil // Not in HEAD: added by this change
+		}
+		return content, nil
+	}
+}
+
+// newPhase2Client creates the model client for Phase 2 investigations from the configured
+// provider; geminiAPIKey comes from cloud credentials or GEMINI_API_KEY
+func newPhase2Client(ctx context.Context, geminiAPIKey string) (*llm.Client, error) {
+	cfg, err := appconfig.Load("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return llm.NewInvestigationClient(ctx, cfg, geminiAPIKey)
+}
+
+// changeDiff returns the change under check against HEAD: staged changes in pre-commit
+// mode, staged and unstaged ones otherwise
+func changeDiff(staged bool) (string, error) {
+	if staged {
+		return git.GetStagedDiff()
+	}
+	return git.GetWorkingTreeDiff()
+}
+
+// readChanged reads files with the change applied: from the index in pre-commit mode,
+// from the working tree otherwise. Files the change deletes read as nil.
+func readChanged(repoRoot string, staged bool) func(string) ([]byte, error) {
+	return func(file string) ([]byte, error) {
+		return git.ReadChangedFile(repoRoot, file, staged)
+	}
+}
+
+// riskMarker is the bullet for a LOW, MEDIUM or HIGH item
+func riskMarker(risk string) string {
+	switch risk {
+	case "HIGH":
+		return "🔴"
+	case "MEDIUM":
+		return "🟡"
+	}
+	return "🟢"
+}
+
 // initStagingClient creates a PostgreSQL staging client for GitHub data queries
 func initStagingClient(ctx context.Context) (*database.StagingClient, error) {
 	slog.Debug("initializing PostgreSQL staging client")
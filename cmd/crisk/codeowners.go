@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/rohankatakam/coderisk/internal/codeowners"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/spf13/cobra"
+)
+
+var codeownersCmd = &cobra.Command{
+	Use:   "codeowners",
+	Short: "Generate CODEOWNERS from ownership data and detect drift",
+	Long: `Generate a CODEOWNERS file from block-level ownership, or compare the existing
+one against who actually changes the code.
+
+Owners are commit emails, shown as @login when the GitHub login is known. A team
+mapping (YAML of email: team) pools members under their team handle.
+
+Examples:
+  # Propose a CODEOWNERS file
+  crisk codeowners generate --teams teams.yaml > .github/CODEOWNERS
+
+  # Require owners to cover 80% of edits, at most two directory levels deep
+  crisk codeowners generate --min-coverage 0.8 --max-depth 2
+
+  # Report owners who haven't touched their paths in a year, and unlisted major contributors
+  crisk codeowners diff
+
+  # Fail CI when CODEOWNERS has drifted
+  crisk codeowners diff --stale-days 180 --exit-code`,
+}
+
+var codeownersGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Propose a directory-level CODEOWNERS file",
+	Args:  cobra.NoArgs,
+	RunE:  runCodeownersGenerate,
+}
+
+var codeownersDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Report where CODEOWNERS disagrees with actual ownership",
+	Args:  cobra.NoArgs,
+	RunE:  runCodeownersDiff,
+}
+
+func init() {
+	defaults := codeowners.DefaultGenerateOptions()
+
+	codeownersCmd.PersistentFlags().String("teams", "", "YAML file mapping emails to team handles")
+
+	codeownersGenerateCmd.Flags().Float64("min-coverage", defaults.MinCoverage, "Share of a directory's edits its owners must cover")
+	codeownersGenerateCmd.Flags().Int("max-owners", defaults.MaxOwners, "Maximum owners per rule")
+	codeownersGenerateCmd.Flags().Int("min-edits", defaults.MinEdits, "Directories with fewer edits inherit their parent's owners")
+	codeownersGenerateCmd.Flags().Int("max-depth", defaults.MaxDepth, "Deepest directory level with its own rule (0 = unlimited)")
+	codeownersGenerateCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+
+	codeownersDiffCmd.Flags().String("file", "", "CODEOWNERS file (default: .github/CODEOWNERS, CODEOWNERS or docs/CODEOWNERS)")
+	codeownersDiffCmd.Flags().Int("stale-days", 365, "Listed owners inactive for longer are stale")
+	codeownersDiffCmd.Flags().Float64("min-share", 0.3, "Unlisted contributors above this share of edits are reported")
+	codeownersDiffCmd.Flags().String("format", "table", "Output format: table, json")
+	codeownersDiffCmd.Flags().Bool("exit-code", false, "Exit with status 1 when drift is found")
+
+	codeownersCmd.AddCommand(codeownersGenerateCmd)
+	codeownersCmd.AddCommand(codeownersDiffCmd)
+}
+
+func runCodeownersGenerate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	opts := codeowners.DefaultGenerateOptions()
+	opts.MinCoverage, _ = cmd.Flags().GetFloat64("min-coverage")
+	opts.MaxOwners, _ = cmd.Flags().GetInt("max-owners")
+	opts.MinEdits, _ = cmd.Flags().GetInt("min-edits")
+	opts.MaxDepth, _ = cmd.Flags().GetInt("max-depth")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	if opts.MinCoverage <= 0 || opts.MinCoverage > 1 {
+		return fmt.Errorf("--min-coverage must be in (0, 1], got %.2f", opts.MinCoverage)
+	}
+
+	files, ids, repoName, err := loadCodeownersData(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	rules := codeowners.Generate(files, ids, opts)
+	if len(rules) == 0 {
+		return fmt.Errorf("no ownership data for %s (run 'crisk init' to compute block ownership)", repoName)
+	}
+
+	header := fmt.Sprintf("Generated by crisk codeowners generate for %s on %s\nOwners cover at least %.0f%% of each directory's edits",
+		repoName, time.Now().Format("2006-01-02"), opts.MinCoverage*100)
+	content := codeowners.Format(rules, header)
+
+	if outputPath == "" {
+		fmt.Print(content)
+		return nil
+	}
+	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+	fmt.Printf("✓ Wrote %d rules to %s\n", len(rules), outputPath)
+	return nil
+}
+
+func runCodeownersDiff(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	path, _ := cmd.Flags().GetString("file")
+	staleDays, _ := cmd.Flags().GetInt("stale-days")
+	minShare, _ := cmd.Flags().GetFloat64("min-share")
+	format, _ := cmd.Flags().GetString("format")
+	exitCode, _ := cmd.Flags().GetBool("exit-code")
+
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid format %q, must be: table or json", format)
+	}
+
+	if path == "" {
+		root, err := cli.GetRepoRoot()
+		if err != nil {
+			return err
+		}
+		if path = codeowners.Find(root); path == "" {
+			return fmt.Errorf("no CODEOWNERS file found (use --file, or 'crisk codeowners generate' to create one)")
+		}
+	}
+	existing, err := codeowners.ParseFile(path)
+	if err != nil {
+		return err
+	}
+
+	files, ids, _, err := loadCodeownersData(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	opts := codeowners.DefaultDiffOptions()
+	opts.StaleAfter = time.Duration(staleDays) * 24 * time.Hour
+	opts.MinShare = minShare
+	findings := codeowners.Diff(existing, files, ids, opts)
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return err
+		}
+	} else if len(findings) == 0 {
+		fmt.Printf("✓ %s matches actual ownership\n", path)
+	} else {
+		fmt.Printf("%s: %d finding(s)\n\n", path, len(findings))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "LINE\tKIND\tPATTERN\tOWNER\tDETAIL")
+		for _, f := range findings {
+			line := "-"
+			if f.Line > 0 {
+				line = fmt.Sprintf("%d", f.Line)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", line, f.Kind, f.Pattern, f.Owner, f.Detail)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if exitCode && len(findings) > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// loadCodeownersData loads file ownership and the email -> handle mappings
+func loadCodeownersData(ctx context.Context, cmd *cobra.Command) ([]database.FileOwnership, codeowners.Identities, string, error) {
+	var ids codeowners.Identities
+
+	if teamsPath, _ := cmd.Flags().GetString("teams"); teamsPath != "" {
+		teams, err := codeowners.LoadTeams(teamsPath)
+		if err != nil {
+			return nil, ids, "", err
+		}
+		ids.Teams = teams
+	}
+
+	db, err := initPostgresSQLX()
+	if err != nil {
+		return nil, ids, "", fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	repoID, repoName, err := cli.DetectRepoID(ctx, db.DB)
+	if err != nil {
+		return nil, ids, "", err
+	}
+	if err := cli.EnsureRepoInitialized(ctx, db.DB, repoID); err != nil {
+		return nil, ids, "", err
+	}
+
+	files, logins, err := queryCodeownersData(ctx, db, repoID)
+	if err != nil {
+		return nil, ids, "", err
+	}
+	ids.Logins = logins
+	return files, ids, repoName, nil
+}
+
+func queryCodeownersData(ctx context.Context, db *sqlx.DB, repoID int64) ([]database.FileOwnership, map[string]string, error) {
+	files, err := database.GetFileOwnership(ctx, db, repoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load file ownership: %w", err)
+	}
+	logins, err := database.GetLoginsByEmail(ctx, db, repoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load GitHub logins: %w", err)
+	}
+	return files, logins, nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/rohankatakam/coderisk/internal/coverage"
+	"github.com/rohankatakam/coderisk/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Import CI coverage for changed-line risk",
+}
+
+var coverageImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Store a coverage report for a commit",
+	Long: `Parse a coverage report, map its files onto the repository and store per-line
+coverage for a commit, folded onto atomized code blocks.
+
+When a report exists for HEAD, 'crisk check' counts the changed lines no test
+executes instead of estimating coverage from the size of test files. Importing
+the same format for the same commit again replaces the earlier report.
+
+Supported formats (detected from the content when --format is omitted):
+  go         go test -coverprofile
+  lcov       LCOV tracefiles (Istanbul, c8, lcov, grcov)
+  cobertura  Cobertura XML (coverage.py, gcovr, Istanbul)
+  jacoco     JaCoCo XML
+
+Examples:
+  # Import the profile CI produced for the current commit
+  go test -coverprofile=cover.out ./... && crisk coverage import cover.out
+
+  # Import a report downloaded for another commit
+  crisk coverage import coverage.xml --format cobertura --commit 4f2a9c1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCoverageImport,
+}
+
+func init() {
+	coverageImportCmd.Flags().String("format", "", "Report format: go, lcov, cobertura, jacoco (default: detect)")
+	coverageImportCmd.Flags().String("commit", "HEAD", "Commit the report was produced for")
+
+	coverageCmd.AddCommand(coverageImportCmd)
+}
+
+func runCoverageImport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	reportPath := args[0]
+
+	format, _ := cmd.Flags().GetString("format")
+	commitRef, _ := cmd.Flags().GetString("commit")
+
+	if format != "" && !isCoverageFormat(coverage.Format(format)) {
+		return fmt.Errorf("invalid format %q, must be: go, lcov, cobertura or jacoco", format)
+	}
+
+	commitSHA, err := git.ResolveCommitSHA(commitRef)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(reportPath)
+	if err != nil {
+		return fmt.Errorf("failed to open coverage report: %w", err)
+	}
+	defer f.Close()
+
+	report, err := coverage.Parse(f, coverage.Format(format))
+	if err != nil {
+		return err
+	}
+
+	repoFiles, err := git.GetTrackedFiles()
+	if err != nil {
+		return err
+	}
+	modulePath := ""
+	if report.Format == coverage.FormatGo {
+		modulePath = readGoModulePath()
+	}
+	resolved, unmatched := coverage.Resolve(report, repoFiles, modulePath)
+	if len(resolved.Files) == 0 {
+		return fmt.Errorf("none of the %d files in %s match files in this repository", len(report.Files), reportPath)
+	}
+
+	stagingDB, err := initStagingClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	defer stagingDB.Close()
+
+	repoID, repoName, err := cli.DetectRepoID(ctx, stagingDB.DB())
+	if err != nil {
+		return err
+	}
+
+	_, blocks, err := stagingDB.StoreCoverageReport(ctx, repoID, commitSHA, reportPath, resolved)
+	if err != nil {
+		return err
+	}
+
+	covered, total := resolved.Summary()
+	percent := 0.0
+	if total > 0 {
+		percent = float64(covered) / float64(total) * 100
+	}
+	fmt.Printf("✓ Imported %s coverage for %s at %s\n", resolved.Format, repoName, commitSHA[:7])
+	fmt.Printf("  Files:       %d\n", len(resolved.Files))
+	fmt.Printf("  Lines:       %d/%d covered (%.1f%%)\n", covered, total, percent)
+	fmt.Printf("  Code blocks: %d mapped\n", blocks)
+	if len(unmatched) > 0 {
+		fmt.Printf("  Skipped %d report file(s) not found in the repository, e.g. %s\n", len(unmatched), unmatched[0])
+	}
+	return nil
+}
+
+func isCoverageFormat(format coverage.Format) bool {
+	for _, f := range coverage.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// readGoModulePath returns the module path from the repository's root go.mod, if any
+// Go cover profiles name files by import path, which is stripped to find repository paths.
+func readGoModulePath() string {
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return ""
+	}
+	f, err := os.Open(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if modulePath, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(modulePath), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/hotspots"
+	"github.com/spf13/cobra"
+)
+
+var hotspotsCmd = &cobra.Command{
+	Use:   "hotspots [directory]",
+	Short: "Rank files and code blocks that most need refactoring",
+	Long: `Rank every file and code block in the repository by a composite hotspot score:
+
+  churn       Changes over time, each weighted by 0.5^(age / --half-life)
+  complexity  Cyclomatic complexity and size, measured from the working tree
+  incidents   Linked production incidents (counted by crisk-index-incident)
+  ownership   How evenly edits are spread across developers
+  coupling    How often the code changes together with other code
+
+Each factor is normalized across the repository and weighted into a 0-100 score.
+Change counts are also reported per --windows (default 30, 90 and 365 days). Files
+deleted from the working tree are skipped.
+
+Examples:
+  # Top 50 hotspots as a Markdown refactoring plan
+  crisk hotspots -o hotspots.md
+
+  # Go and Python code under services/billing, as CSV for a spreadsheet
+  crisk hotspots services/billing --language go,python --format csv -o hotspots.csv
+
+  # Every block, emphasizing recent churn
+  crisk hotspots --blocks-only --top 0 --half-life 30 --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHotspots,
+}
+
+func init() {
+	defaults := hotspots.DefaultOptions()
+
+	hotspotsCmd.Flags().StringSlice("language", nil, "Only these languages, e.g. go,python,ts (default: all)")
+	hotspotsCmd.Flags().IntSlice("windows", defaults.Windows, "Change-count windows in days")
+	hotspotsCmd.Flags().Float64("half-life", defaults.HalfLifeDays, "Days after which a change counts half as much")
+	hotspotsCmd.Flags().Int("top", defaults.Top, "Hotspots listed per kind (0 = all)")
+	hotspotsCmd.Flags().Bool("files-only", false, "Only rank files")
+	hotspotsCmd.Flags().Bool("blocks-only", false, "Only rank code blocks")
+	hotspotsCmd.Flags().String("as-of", "", "Reference date for windows and decay, YYYY-MM-DD (default: today)")
+	hotspotsCmd.Flags().String("format", "markdown", "Output format: markdown, json, csv")
+	hotspotsCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+}
+
+func runHotspots(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	opts := hotspots.DefaultOptions()
+	opts.Languages, _ = cmd.Flags().GetStringSlice("language")
+	opts.Windows, _ = cmd.Flags().GetIntSlice("windows")
+	opts.HalfLifeDays, _ = cmd.Flags().GetFloat64("half-life")
+	opts.Top, _ = cmd.Flags().GetInt("top")
+	filesOnly, _ := cmd.Flags().GetBool("files-only")
+	blocksOnly, _ := cmd.Flags().GetBool("blocks-only")
+	asOf, _ := cmd.Flags().GetString("as-of")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	if format != "markdown" && format != "json" && format != "csv" {
+		return fmt.Errorf("invalid format %q, must be: markdown, json, or csv", format)
+	}
+	if filesOnly && blocksOnly {
+		return fmt.Errorf("--files-only and --blocks-only are mutually exclusive")
+	}
+	for _, days := range opts.Windows {
+		if days <= 0 {
+			return fmt.Errorf("invalid window %d, must be a positive number of days", days)
+		}
+	}
+	if asOf != "" {
+		t, err := time.Parse("2006-01-02", asOf)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of date %q (expected YYYY-MM-DD): %w", asOf, err)
+		}
+		opts.Now = t
+	}
+
+	db, err := initPostgresSQLX()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	repoID, repoName, err := cli.DetectRepoID(ctx, db.DB)
+	if err != nil {
+		return err
+	}
+	if err := cli.EnsureRepoInitialized(ctx, db.DB, repoID); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		if opts.Dir, err = cli.GetRelativePath(args[0]); err != nil {
+			return err
+		}
+		opts.Dir = strings.Trim(opts.Dir, "/")
+	}
+	repoRoot, err := cli.GetRepoRoot()
+	if err != nil {
+		return err
+	}
+
+	in := hotspots.Input{RepoPath: repoRoot}
+	if in.Blocks, err = database.GetBlocksInDirectory(ctx, db, repoID, opts.Dir); err != nil {
+		return fmt.Errorf("failed to get code blocks: %w", err)
+	}
+	if in.BlockChanges, err = database.GetBlockChangeDates(ctx, db, repoID); err != nil {
+		return err
+	}
+	if in.Commits, err = database.GetCommitFileHistory(ctx, db, repoID); err != nil {
+		return err
+	}
+	if len(in.Blocks) == 0 && len(in.Commits) == 0 {
+		return fmt.Errorf("no history found for %s (run 'crisk init' first)", repoName)
+	}
+
+	report := hotspots.Build(in, opts)
+	if filesOnly {
+		report.Blocks = nil
+	}
+	if blocksOnly {
+		report.Files = nil
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "json":
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	case "csv":
+		err = hotspots.WriteCSV(&buf, report)
+	default:
+		err = hotspots.WriteMarkdown(&buf, repoName, report)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	if outputPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+	fmt.Printf("✓ Wrote %d file and %d block hotspots to %s\n", len(report.Files), len(report.Blocks), outputPath)
+	return nil
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/rohankatakam/coderisk/internal/config"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/graph"
+	"github.com/rohankatakam/coderisk/internal/identity"
+	"github.com/rohankatakam/coderisk/internal/risk"
+	"github.com/spf13/cobra"
+)
+
+var identityCmd = &cobra.Command{
+	Use:   "identity",
+	Short: "Unify developer identities across emails and GitHub logins",
+	Long: `Merge the emails one developer commits under (work, personal, GitHub noreply)
+into a single canonical email, so ownership, familiarity and bus factor are not
+split across several "developers".
+
+Confident matches are accepted automatically: .mailmap entries, a manual alias
+file, and emails GitHub attributes to the same login. Developers who only share a
+name are suggested and must be reviewed. Applying rewrites the author email of
+staged commits (the original is kept), recalculates ownership of the affected
+code blocks and merges the Developer nodes in the graph.
+
+Alias file format (YAML, canonical email -> aliases; entries without @ are GitHub logins):
+  alice@acme.com:
+    - alice@gmail.com
+    - alice-dev
+
+Examples:
+  # Find aliases from .mailmap and GitHub data, then review suggestions
+  crisk identity resolve --aliases identities.yaml
+  crisk identity review
+
+  # Accept one suggestion non-interactively and apply everything accepted
+  crisk identity review alice.s@home.example --accept
+  crisk identity apply`,
+}
+
+var identityResolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Detect aliases from mailmap, alias file, GitHub logins and names",
+	Args:  cobra.NoArgs,
+	RunE:  runIdentityResolve,
+}
+
+var identityListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List known aliases",
+	Args:  cobra.NoArgs,
+	RunE:  runIdentityList,
+}
+
+var identityReviewCmd = &cobra.Command{
+	Use:   "review [alias-email...]",
+	Short: "Accept or reject suggested aliases",
+	RunE:  runIdentityReview,
+}
+
+var identityApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Merge accepted aliases in staging and graph and reindex ownership",
+	Args:  cobra.NoArgs,
+	RunE:  runIdentityApply,
+}
+
+func init() {
+	identityResolveCmd.Flags().String("mailmap", "", "Path to .mailmap (default: .mailmap at the repo root)")
+	identityResolveCmd.Flags().String("aliases", "", "YAML alias file (canonical email -> aliases)")
+	identityResolveCmd.Flags().Bool("apply", false, "Apply accepted aliases after resolving")
+
+	identityListCmd.Flags().String("status", "", "Filter by status: pending, accepted, rejected, applied")
+	identityListCmd.Flags().String("format", "table", "Output format: table, json")
+
+	identityReviewCmd.Flags().Bool("accept", false, "Accept the given aliases")
+	identityReviewCmd.Flags().Bool("reject", false, "Reject the given aliases")
+	identityReviewCmd.MarkFlagsMutuallyExclusive("accept", "reject")
+
+	identityCmd.AddCommand(identityResolveCmd)
+	identityCmd.AddCommand(identityListCmd)
+	identityCmd.AddCommand(identityReviewCmd)
+	identityCmd.AddCommand(identityApplyCmd)
+}
+
+// openIdentityDB connects to PostgreSQL and detects the current repository
+func openIdentityDB(ctx context.Context) (*sqlx.DB, int64, error) {
+	db, err := initPostgresSQLX()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	repoID, _, err := cli.DetectRepoID(ctx, db.DB)
+	if err != nil {
+		db.Close()
+		return nil, 0, err
+	}
+	return db, repoID, nil
+}
+
+func runIdentityResolve(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	mailmapPath, _ := cmd.Flags().GetString("mailmap")
+	aliasesPath, _ := cmd.Flags().GetString("aliases")
+	apply, _ := cmd.Flags().GetBool("apply")
+
+	var rules identity.Rules
+	if mailmapPath == "" {
+		if root, err := cli.GetRepoRoot(); err == nil {
+			mailmapPath = filepath.Join(root, ".mailmap")
+		}
+	}
+	if mailmapPath != "" {
+		entries, err := identity.LoadMailmap(mailmapPath)
+		if err != nil {
+			return err
+		}
+		rules.Mailmap = entries
+	}
+	if aliasesPath != "" {
+		aliases, err := identity.LoadAliases(aliasesPath)
+		if err != nil {
+			return err
+		}
+		rules.Aliases = aliases
+	}
+
+	db, repoID, err := openIdentityDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	authors, err := database.GetCommitAuthors(ctx, db, repoID)
+	if err != nil {
+		return err
+	}
+	if len(authors) == 0 {
+		return fmt.Errorf("no staged commits for this repository (run 'crisk init' first)")
+	}
+
+	result := identity.Resolve(authors, rules)
+	if _, err := database.SaveDeveloperAliases(ctx, db, repoID, result.Merges); err != nil {
+		return err
+	}
+	if _, err := database.SaveDeveloperAliases(ctx, db, repoID, result.Suggestions); err != nil {
+		return err
+	}
+
+	fmt.Printf("Resolved %d author emails (%d mailmap entries, %d alias file entries)\n",
+		len(authors), len(rules.Mailmap), len(rules.Aliases))
+	fmt.Printf("  ✓ %d confident aliases accepted\n", len(result.Merges))
+	fmt.Printf("  ? %d name-based suggestions awaiting review\n", len(result.Suggestions))
+	if len(result.Merges) > 0 {
+		fmt.Println()
+		printAliases(result.Merges)
+	}
+
+	if apply {
+		fmt.Println()
+		return applyIdentities(ctx, db, repoID)
+	}
+	if len(result.Suggestions) > 0 {
+		fmt.Println("\nReview suggestions with 'crisk identity review', then run 'crisk identity apply'.")
+	} else if len(result.Merges) > 0 {
+		fmt.Println("\nRun 'crisk identity apply' to merge them.")
+	}
+	return nil
+}
+
+func runIdentityList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	status, _ := cmd.Flags().GetString("status")
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid format %q, must be: table or json", format)
+	}
+
+	db, repoID, err := openIdentityDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var statuses []string
+	if status != "" {
+		statuses = append(statuses, status)
+	}
+	aliases, err := database.ListDeveloperAliases(ctx, db, repoID, statuses...)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(aliases)
+	}
+	if len(aliases) == 0 {
+		fmt.Println("No aliases (run 'crisk identity resolve')")
+		return nil
+	}
+	printAliases(aliases)
+	return nil
+}
+
+func runIdentityReview(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	accept, _ := cmd.Flags().GetBool("accept")
+	reject, _ := cmd.Flags().GetBool("reject")
+	if len(args) > 0 && !accept && !reject {
+		return fmt.Errorf("--accept or --reject is required when aliases are given")
+	}
+
+	db, repoID, err := openIdentityDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if len(args) > 0 {
+		status := database.AliasAccepted
+		if reject {
+			status = database.AliasRejected
+		}
+		for _, alias := range args {
+			if err := database.SetDeveloperAliasStatus(ctx, db, repoID, alias, status); err != nil {
+				return err
+			}
+			fmt.Printf("✓ %s %s\n", alias, status)
+		}
+		return nil
+	}
+
+	pending, err := database.ListDeveloperAliases(ctx, db, repoID, database.AliasPending)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Println("✓ No suggestions awaiting review")
+		return nil
+	}
+
+	accepted := 0
+	for i, a := range pending {
+		fmt.Printf("\n[%d/%d] Merge %s into %s?\n", i+1, len(pending), a.AliasEmail, a.CanonicalEmail)
+		fmt.Printf("       %s (confidence %.2f)\n", a.Reason, a.Confidence)
+		fmt.Print("       [y]es / [n]o / [s]kip / [q]uit: ")
+
+		var response string
+		fmt.Scanln(&response)
+		switch strings.ToLower(strings.TrimSpace(response)) {
+		case "y", "yes":
+			if err := database.SetDeveloperAliasStatus(ctx, db, repoID, a.AliasEmail, database.AliasAccepted); err != nil {
+				return err
+			}
+			accepted++
+		case "n", "no":
+			if err := database.SetDeveloperAliasStatus(ctx, db, repoID, a.AliasEmail, database.AliasRejected); err != nil {
+				return err
+			}
+		case "q", "quit":
+			fmt.Printf("\n%d accepted; remaining suggestions stay pending\n", accepted)
+			return nil
+		}
+	}
+
+	fmt.Printf("\n%d accepted. Run 'crisk identity apply' to merge them.\n", accepted)
+	return nil
+}
+
+func runIdentityApply(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	db, repoID, err := openIdentityDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return applyIdentities(ctx, db, repoID)
+}
+
+// applyIdentities merges accepted aliases; the graph is updated when Neo4j is reachable
+func applyIdentities(ctx context.Context, db *sqlx.DB, repoID int64) error {
+	var exec identity.GraphExecutor
+	if cfg, err := config.Load(""); err == nil {
+		backend, err := graph.NewNeo4jBackend(ctx, cfg.Neo4j.URI, cfg.Neo4j.User, cfg.Neo4j.Password, cfg.Neo4j.Database)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Neo4j unavailable, merging in PostgreSQL only: %v\n", err)
+		} else {
+			defer backend.Close(ctx)
+			exec = backend
+		}
+	}
+
+	indexer := risk.NewBlockIndexer(db.DB, nil, nil, "", nil)
+	result, err := identity.Apply(ctx, db, repoID, indexer, exec)
+	if err != nil {
+		return fmt.Errorf("failed to apply aliases: %w", err)
+	}
+
+	if result.Aliases == 0 {
+		fmt.Println("✓ No accepted aliases to apply")
+		return nil
+	}
+	fmt.Printf("✓ Merged %d aliases: %d commits reattributed, %d blocks reindexed\n",
+		result.Aliases, result.Commits, len(result.BlockIDs))
+	if result.GraphSynced {
+		fmt.Println("✓ Developer nodes merged in graph")
+	} else {
+		fmt.Println("  Graph not updated; run crisk-sync to rebuild Developer nodes")
+	}
+	return nil
+}
+
+func printAliases(aliases []database.DeveloperAlias) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALIAS\tCANONICAL\tSOURCE\tCONFIDENCE\tSTATUS\tREASON")
+	for _, a := range aliases {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%s\t%s\n", a.AliasEmail, a.CanonicalEmail, a.Source, a.Confidence, a.Status, a.Reason)
+	}
+	w.Flush()
+}
@@ -0,0 +1,657 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohankatakam/coderisk/internal/config"
+	"github.com/rohankatakam/coderisk/internal/graph"
+	"github.com/rohankatakam/coderisk/internal/incidents"
+	"github.com/spf13/cobra"
+)
+
+var incidentCmd = &cobra.Command{
+	Use:   "incident",
+	Short: "Record production incidents and link them to code",
+	Long: `Record production incidents and link them to the files and functions that caused them.
+
+Linked incidents are stored in PostgreSQL and mirrored into the graph as
+CAUSED_BY edges. crisk check uses them to raise the risk of files with a
+recent incident history.
+
+Examples:
+  # Record an incident and link it to a function in one step
+  crisk incident create --title "Refunds double-charged" --severity critical \
+    --link payments/charge.go:ProcessRefund
+
+  # Link an existing incident to a specific line
+  crisk incident link 2b1c... payments/charge.go:142
+
+  # Suggest links from the incident description and apply them
+  crisk incident suggest 2b1c... --apply
+
+  # List incidents touching a file as JSON
+  crisk incident list --file payments/charge.go --format json`,
+}
+
+var incidentCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new incident",
+	Args:  cobra.NoArgs,
+	RunE:  runIncidentCreate,
+}
+
+var incidentShowCmd = &cobra.Command{
+	Use:   "show <incident-id>",
+	Short: "Show an incident and its linked files",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runIncidentShow,
+}
+
+var incidentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List incidents",
+	Args:  cobra.NoArgs,
+	RunE:  runIncidentList,
+}
+
+var incidentLinkCmd = &cobra.Command{
+	Use:   "link <incident-id> <file[:line|:function]>",
+	Short: "Link an incident to a file, line or function",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runIncidentLink,
+}
+
+var incidentUnlinkCmd = &cobra.Command{
+	Use:   "unlink <incident-id> <file>",
+	Short: "Remove the link between an incident and a file",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runIncidentUnlink,
+}
+
+var incidentSuggestCmd = &cobra.Command{
+	Use:   "suggest <incident-id>",
+	Short: "Suggest files to link based on the incident description",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runIncidentSuggest,
+}
+
+var incidentSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text search over incidents",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runIncidentSearch,
+}
+
+func init() {
+	incidentCmd.PersistentFlags().String("format", "table", "Output format: table, json")
+
+	incidentCreateCmd.Flags().String("title", "", "Incident title (required)")
+	incidentCreateCmd.Flags().String("description", "", "Incident description")
+	incidentCreateCmd.Flags().String("severity", "medium", "Severity: critical, high, medium, low")
+	incidentCreateCmd.Flags().String("occurred-at", "", "When the incident occurred (RFC3339 or YYYY-MM-DD, default now)")
+	incidentCreateCmd.Flags().String("resolved-at", "", "When the incident was resolved (RFC3339 or YYYY-MM-DD)")
+	incidentCreateCmd.Flags().String("root-cause", "", "Root cause summary")
+	incidentCreateCmd.Flags().String("impact", "", "User-facing impact")
+	incidentCreateCmd.Flags().StringArray("link", nil, "Link to file[:line|:function] (repeatable)")
+	_ = incidentCreateCmd.MarkFlagRequired("title")
+
+	incidentListCmd.Flags().String("severity", "", "Filter by severity")
+	incidentListCmd.Flags().String("file", "", "Only incidents linked to this file")
+	incidentListCmd.Flags().Int("limit", 20, "Maximum number of incidents")
+
+	incidentSuggestCmd.Flags().Float64("threshold", 0.5, "Minimum confidence for suggestions")
+	incidentSuggestCmd.Flags().Bool("apply", false, "Link all suggestions above the threshold")
+
+	incidentSearchCmd.Flags().Int("limit", 10, "Maximum number of results")
+
+	incidentCmd.AddCommand(incidentCreateCmd)
+	incidentCmd.AddCommand(incidentShowCmd)
+	incidentCmd.AddCommand(incidentListCmd)
+	incidentCmd.AddCommand(incidentLinkCmd)
+	incidentCmd.AddCommand(incidentUnlinkCmd)
+	incidentCmd.AddCommand(incidentSuggestCmd)
+	incidentCmd.AddCommand(incidentSearchCmd)
+}
+
+// incidentView is the JSON representation of an incident
+type incidentView struct {
+	ID          string             `json:"id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description,omitempty"`
+	Severity    string             `json:"severity"`
+	OccurredAt  time.Time          `json:"occurred_at"`
+	ResolvedAt  *time.Time         `json:"resolved_at,omitempty"`
+	RootCause   string             `json:"root_cause,omitempty"`
+	Impact      string             `json:"impact,omitempty"`
+	LinkedFiles []incidentLinkView `json:"linked_files,omitempty"`
+	Rank        *float64           `json:"rank,omitempty"`
+}
+
+type incidentLinkView struct {
+	FilePath   string  `json:"file_path"`
+	LineNumber int     `json:"line_number,omitempty"`
+	Function   string  `json:"function,omitempty"`
+	Confidence float64 `json:"confidence"`
+}
+
+func newIncidentView(inc *incidents.Incident) incidentView {
+	v := incidentView{
+		ID:          inc.ID.String(),
+		Title:       inc.Title,
+		Description: inc.Description,
+		Severity:    string(inc.Severity),
+		OccurredAt:  inc.OccurredAt,
+		ResolvedAt:  inc.ResolvedAt,
+		RootCause:   inc.RootCause,
+		Impact:      inc.Impact,
+	}
+	for _, f := range inc.LinkedFiles {
+		v.LinkedFiles = append(v.LinkedFiles, incidentLinkView{
+			FilePath:   f.FilePath,
+			LineNumber: f.LineNumber,
+			Function:   f.BlamedFunction,
+			Confidence: f.Confidence,
+		})
+	}
+	return v
+}
+
+// incidentGraphAdapter exposes a graph.Backend as an incidents.GraphClient
+type incidentGraphAdapter struct {
+	ctx     context.Context
+	backend graph.Backend
+}
+
+func (a *incidentGraphAdapter) CreateNode(node incidents.GraphNode) (string, error) {
+	return a.backend.CreateNode(a.ctx, graph.GraphNode{
+		Label:      node.Label,
+		ID:         node.ID,
+		Properties: node.Properties,
+	})
+}
+
+func (a *incidentGraphAdapter) CreateEdge(edge incidents.GraphEdge) error {
+	return a.backend.CreateEdge(a.ctx, graph.GraphEdge{
+		Label:      edge.Label,
+		From:       edge.From,
+		To:         edge.To,
+		Properties: edge.Properties,
+	})
+}
+
+func (a *incidentGraphAdapter) QueryWithParams(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	return a.backend.QueryWithParams(a.ctx, query, params)
+}
+
+// incidentSession bundles the connections used by incident subcommands
+type incidentSession struct {
+	db      *incidents.Database
+	linker  *incidents.Linker
+	closeFn func()
+}
+
+// openIncidentSession connects to PostgreSQL and, when available, Neo4j.
+// A missing graph is not fatal: links are kept in PostgreSQL and crisk check
+// reads them from there.
+func openIncidentSession(ctx context.Context, withGraph bool) (*incidentSession, error) {
+	sqlxDB, err := initPostgresSQLX()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	db := incidents.NewDatabase(sqlxDB)
+	session := &incidentSession{
+		db:      db,
+		linker:  incidents.NewLinker(db, nil),
+		closeFn: func() { sqlxDB.Close() },
+	}
+
+	if !withGraph {
+		return session, nil
+	}
+
+	cfg, err := config.Load("")
+	if err != nil {
+		slog.Warn("config load failed, skipping graph update", "error", err)
+		return session, nil
+	}
+
+	backend, err := graph.NewNeo4jBackend(ctx, cfg.Neo4j.URI, cfg.Neo4j.User, cfg.Neo4j.Password, cfg.Neo4j.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Neo4j unavailable, links stored in PostgreSQL only: %v\n", err)
+		return session, nil
+	}
+
+	session.linker = incidents.NewLinker(db, &incidentGraphAdapter{ctx: ctx, backend: backend})
+	session.closeFn = func() {
+		backend.Close(ctx)
+		sqlxDB.Close()
+	}
+	return session, nil
+}
+
+func (s *incidentSession) Close() {
+	s.closeFn()
+}
+
+func incidentFormat(cmd *cobra.Command) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	if format != "table" && format != "json" {
+		return "", fmt.Errorf("invalid format %q, must be: table or json", format)
+	}
+	return format, nil
+}
+
+func printIncidentJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// parseIncidentTime accepts RFC3339 timestamps or plain dates
+func parseIncidentTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (use RFC3339 or YYYY-MM-DD)", value)
+	}
+	return t, nil
+}
+
+func runIncidentCreate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, err := incidentFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	title, _ := cmd.Flags().GetString("title")
+	description, _ := cmd.Flags().GetString("description")
+	severity, _ := cmd.Flags().GetString("severity")
+	occurredAt, _ := cmd.Flags().GetString("occurred-at")
+	resolvedAt, _ := cmd.Flags().GetString("resolved-at")
+	rootCause, _ := cmd.Flags().GetString("root-cause")
+	impact, _ := cmd.Flags().GetString("impact")
+	links, _ := cmd.Flags().GetStringArray("link")
+
+	inc := &incidents.Incident{
+		Title:       title,
+		Description: description,
+		Severity:    incidents.Severity(strings.ToLower(severity)),
+		OccurredAt:  time.Now(),
+		RootCause:   rootCause,
+		Impact:      impact,
+	}
+	if !inc.Severity.Validate() {
+		return fmt.Errorf("invalid severity %q, must be: critical, high, medium, or low", severity)
+	}
+	if occurredAt != "" {
+		if inc.OccurredAt, err = parseIncidentTime(occurredAt); err != nil {
+			return err
+		}
+	}
+	if resolvedAt != "" {
+		t, err := parseIncidentTime(resolvedAt)
+		if err != nil {
+			return err
+		}
+		inc.ResolvedAt = &t
+	}
+
+	// Validate link targets before writing anything
+	type linkTarget struct {
+		path     string
+		line     int
+		function string
+	}
+	var targets []linkTarget
+	for _, l := range links {
+		path, line, function, err := incidents.ParseLinkTarget(l)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, linkTarget{path: path, line: line, function: function})
+	}
+
+	session, err := openIncidentSession(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if err := session.db.CreateIncident(ctx, inc); err != nil {
+		return fmt.Errorf("failed to create incident: %w", err)
+	}
+	if err := session.linker.CreateIncidentNode(ctx, inc); err != nil {
+		slog.Warn("failed to create incident node", "incident", inc.ID, "error", err)
+	}
+
+	for _, t := range targets {
+		if err := session.linker.LinkIncident(ctx, inc.ID.String(), t.path, t.line, t.function); err != nil {
+			return fmt.Errorf("incident %s created but linking %s failed: %w", inc.ID, t.path, err)
+		}
+	}
+
+	created, err := session.db.GetIncident(ctx, inc.ID)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return printIncidentJSON(newIncidentView(created))
+	}
+
+	fmt.Printf("✓ Created incident %s\n", created.ID)
+	for _, f := range created.LinkedFiles {
+		fmt.Printf("  linked → %s\n", formatIncidentLink(f))
+	}
+	return nil
+}
+
+func runIncidentShow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, err := incidentFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid incident ID: %w", err)
+	}
+
+	session, err := openIncidentSession(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	inc, err := session.db.GetIncident(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return printIncidentJSON(newIncidentView(inc))
+	}
+
+	fmt.Printf("Incident %s\n", inc.ID)
+	fmt.Printf("  Title:       %s\n", inc.Title)
+	fmt.Printf("  Severity:    %s\n", inc.Severity)
+	fmt.Printf("  Occurred:    %s\n", inc.OccurredAt.Format(time.RFC3339))
+	if inc.ResolvedAt != nil {
+		fmt.Printf("  Resolved:    %s\n", inc.ResolvedAt.Format(time.RFC3339))
+	}
+	if inc.Description != "" {
+		fmt.Printf("  Description: %s\n", inc.Description)
+	}
+	if inc.RootCause != "" {
+		fmt.Printf("  Root cause:  %s\n", inc.RootCause)
+	}
+	if inc.Impact != "" {
+		fmt.Printf("  Impact:      %s\n", inc.Impact)
+	}
+
+	if len(inc.LinkedFiles) == 0 {
+		fmt.Println("\n  No linked files (try: crisk incident suggest " + inc.ID.String() + ")")
+		return nil
+	}
+
+	fmt.Println("\n  Linked files:")
+	for _, f := range inc.LinkedFiles {
+		fmt.Printf("    %s (confidence %.2f)\n", formatIncidentLink(f), f.Confidence)
+	}
+	return nil
+}
+
+func runIncidentList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, err := incidentFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	severity, _ := cmd.Flags().GetString("severity")
+	file, _ := cmd.Flags().GetString("file")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	session, err := openIncidentSession(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	var list []incidents.Incident
+	if file != "" {
+		list, err = session.db.GetIncidentsByFile(ctx, file)
+		if err != nil {
+			return err
+		}
+		list = filterIncidents(list, incidents.Severity(strings.ToLower(severity)), limit)
+	} else {
+		list, err = session.db.ListIncidents(ctx, incidents.Severity(strings.ToLower(severity)), limit)
+		if err != nil {
+			return err
+		}
+	}
+
+	if format == "json" {
+		views := make([]incidentView, 0, len(list))
+		for i := range list {
+			views = append(views, newIncidentView(&list[i]))
+		}
+		return printIncidentJSON(views)
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No incidents found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSEVERITY\tOCCURRED\tTITLE")
+	for _, inc := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", inc.ID, inc.Severity, inc.OccurredAt.Format("2006-01-02"), inc.Title)
+	}
+	return w.Flush()
+}
+
+// filterIncidents applies severity and limit filters to a file's incidents
+func filterIncidents(list []incidents.Incident, severity incidents.Severity, limit int) []incidents.Incident {
+	var filtered []incidents.Incident
+	for _, inc := range list {
+		if severity != "" && inc.Severity != severity {
+			continue
+		}
+		filtered = append(filtered, inc)
+		if limit > 0 && len(filtered) >= limit {
+			break
+		}
+	}
+	return filtered
+}
+
+func runIncidentLink(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, err := incidentFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	path, line, function, err := incidents.ParseLinkTarget(args[1])
+	if err != nil {
+		return err
+	}
+
+	session, err := openIncidentSession(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if err := session.linker.LinkIncident(ctx, args[0], path, line, function); err != nil {
+		return err
+	}
+
+	link := incidents.IncidentFile{FilePath: path, LineNumber: line, BlamedFunction: function, Confidence: 1.0}
+	if format == "json" {
+		return printIncidentJSON(incidentLinkView{FilePath: path, LineNumber: line, Function: function, Confidence: 1.0})
+	}
+
+	fmt.Printf("✓ Linked incident %s → %s\n", args[0], formatIncidentLink(link))
+	return nil
+}
+
+func runIncidentUnlink(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, err := incidentFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	session, err := openIncidentSession(ctx, true)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if err := session.linker.UnlinkIncident(ctx, args[0], args[1]); err != nil {
+		return err
+	}
+
+	if format == "json" {
+		return printIncidentJSON(struct {
+			IncidentID string `json:"incident_id"`
+			FilePath   string `json:"file_path"`
+			Unlinked   bool   `json:"unlinked"`
+		}{IncidentID: args[0], FilePath: args[1], Unlinked: true})
+	}
+
+	fmt.Printf("✓ Unlinked incident %s from %s\n", args[0], args[1])
+	return nil
+}
+
+func runIncidentSuggest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, err := incidentFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	apply, _ := cmd.Flags().GetBool("apply")
+
+	session, err := openIncidentSession(ctx, apply)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	suggestions, err := session.linker.SuggestLinks(ctx, args[0], threshold)
+	if err != nil {
+		return err
+	}
+
+	if apply {
+		for _, s := range suggestions {
+			if err := session.linker.ApplySuggestion(ctx, args[0], s); err != nil {
+				return fmt.Errorf("failed to link %s: %w", s.FilePath, err)
+			}
+		}
+	}
+
+	if format == "json" {
+		if suggestions == nil {
+			suggestions = []incidents.SuggestionResult{}
+		}
+		return printIncidentJSON(suggestions)
+	}
+
+	if len(suggestions) == 0 {
+		fmt.Printf("No suggestions above confidence %.2f\n", threshold)
+		return nil
+	}
+
+	for _, s := range suggestions {
+		fmt.Printf("  %s (confidence %.2f) - %s\n", s.FilePath, s.Confidence, s.Reason)
+	}
+	if apply {
+		fmt.Printf("✓ Linked %d file(s)\n", len(suggestions))
+	} else {
+		fmt.Println("\nRun with --apply to link these files")
+	}
+	return nil
+}
+
+func runIncidentSearch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	format, err := incidentFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	query := strings.Join(args, " ")
+
+	session, err := openIncidentSession(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	results, err := session.db.SearchIncidents(ctx, query, limit)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		views := make([]incidentView, 0, len(results))
+		for _, r := range results {
+			v := newIncidentView(&r.Incident)
+			rank := r.Rank
+			v.Rank = &rank
+			views = append(views, v)
+		}
+		return printIncidentJSON(views)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No incidents match %q\n", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tRELEVANCE\tSEVERITY\tTITLE")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Incident.ID, r.Relevance, r.Incident.Severity, r.Incident.Title)
+	}
+	return w.Flush()
+}
+
+func formatIncidentLink(f incidents.IncidentFile) string {
+	switch {
+	case f.BlamedFunction != "":
+		return fmt.Sprintf("%s:%s", f.FilePath, f.BlamedFunction)
+	case f.LineNumber > 0:
+		return fmt.Sprintf("%s:%d", f.FilePath, f.LineNumber)
+	default:
+		return f.FilePath
+	}
+}
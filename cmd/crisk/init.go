@@ -3,10 +3,10 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"time"
 
@@ -16,10 +16,13 @@ import (
 	"github.com/rohankatakam/coderisk/internal/config"
 	"github.com/rohankatakam/coderisk/internal/database"
 	"github.com/rohankatakam/coderisk/internal/github"
+	"github.com/rohankatakam/coderisk/internal/gitlab"
+	"github.com/rohankatakam/coderisk/internal/gitonly"
 	"github.com/rohankatakam/coderisk/internal/graph"
 	"github.com/rohankatakam/coderisk/internal/ingestion"
 	"github.com/rohankatakam/coderisk/internal/linking"
 	"github.com/rohankatakam/coderisk/internal/llm"
+	"github.com/rohankatakam/coderisk/internal/source"
 	"github.com/spf13/cobra"
 )
 
@@ -28,7 +31,8 @@ var initCmd = &cobra.Command{
 	Short: "Initialize CodeRisk analysis for current repository",
 	Long: `Initialize CodeRisk by building the 100% confidence knowledge graph from GitHub data.
 
-This command must be run inside a cloned GitHub repository.
+This command must be run inside a cloned GitHub or GitLab repository.
+The provider is detected from the origin remote (override with --provider).
 
 What it does:
   • Fetch GitHub commit history, ownership, and temporal data
@@ -47,10 +51,12 @@ Examples:
   crisk init --days 180       # Last 180 days only (for testing/debugging)
   crisk init --all            # Full repository history (same as default)
   crisk init --llm            # Full history + LLM-based ASSOCIATED_WITH extraction (requires API key)
+  crisk init --provider gitlab --gitlab-url https://git.example.com   # Self-hosted GitLab
+  crisk init --git-only       # Local git history only (no hosting API, works offline)
 
 Requirements:
   • Must be run inside a cloned GitHub repository
-  • GitHub Personal Access Token (for fetching issues, commits)
+  • GitHub Personal Access Token, or GITLAB_TOKEN with read_api scope for GitLab projects
   • LLM API key (optional, only needed with --llm flag)
   • Docker with Neo4j and PostgreSQL running
 
@@ -67,14 +73,19 @@ func init() {
 	initCmd.Flags().Bool("all", false, "Ingest entire repository history (same as --days=0)")
 	initCmd.Flags().Bool("llm", false, "Enable LLM-based ASSOCIATED_WITH edge extraction (requires API key)")
 	initCmd.Flags().Bool("enable-atomization", false, "Enable Pipeline 2 code-block atomization (requires --llm)")
+	initCmd.Flags().String("provider", "auto", "Hosting provider: auto, github, gitlab")
+	initCmd.Flags().String("gitlab-url", config.GetString("GITLAB_URL", gitlab.DefaultBaseURL), "GitLab instance URL (env: GITLAB_URL)")
+	initCmd.Flags().Bool("git-only", false, "Build the graph from local git history only (no hosting API or token; skips issues/PRs)")
 }
 
 // detectCurrentRepo detects the git repository in the current directory
-func detectCurrentRepo() (owner, repo, repoPath string, err error) {
+// provider overrides host-based detection (empty = infer from the remote host)
+// In git-only mode a missing or unrecognised remote falls back to "local/<dir>"
+func detectCurrentRepo(provider source.Provider, gitlabURL string) (remote *source.Remote, repoPath string, err error) {
 	// Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to get current directory: %w", err)
+		return nil, "", fmt.Errorf("failed to get current directory: %w", err)
 	}
 
 	// Find git root directory
@@ -85,7 +96,7 @@ func detectCurrentRepo() (owner, repo, repoPath string, err error) {
 		}
 		parent := filepath.Dir(gitRoot)
 		if parent == gitRoot {
-			return "", "", "", fmt.Errorf("not a git repository\n\nRun this command inside a cloned git repository, or specify:\n  crisk init owner/repo")
+			return nil, "", fmt.Errorf("not a git repository\n\nRun this command inside a cloned git repository, or specify:\n  crisk init owner/repo")
 		}
 		gitRoot = parent
 	}
@@ -94,38 +105,104 @@ func detectCurrentRepo() (owner, repo, repoPath string, err error) {
 	cmd := exec.Command("git", "-C", gitRoot, "remote", "get-url", "origin")
 	output, err := cmd.Output()
 	if err != nil {
-		return "", "", "", fmt.Errorf("failed to get git remote: %w\n\nMake sure the repository has an 'origin' remote set.", err)
+		if provider == source.ProviderGit {
+			return localRemote(gitRoot), gitRoot, nil
+		}
+		return nil, "", fmt.Errorf("failed to get git remote: %w\n\nMake sure the repository has an 'origin' remote set.", err)
 	}
 
 	remoteURL := strings.TrimSpace(string(output))
 
 	// Parse owner/repo from remote URL
-	// Supports: https://github.com/owner/repo.git or git@github.com:owner/repo.git
-	re := regexp.MustCompile(`github\.com[:/]([^/]+)/(.+?)(?:\.git)?$`)
-	matches := re.FindStringSubmatch(remoteURL)
-	if matches == nil || len(matches) < 3 {
-		return "", "", "", fmt.Errorf("could not parse GitHub owner/repo from remote URL: %s\n\nRemote URL must be a GitHub repository.", remoteURL)
+	// Supports: https://host/owner/repo.git or git@host:owner/repo.git (GitLab owners may be nested groups)
+	gitlabHost := ""
+	if u, err := url.Parse(gitlabURL); err == nil {
+		gitlabHost = u.Hostname()
 	}
+	remote, err = source.ParseRemote(remoteURL, gitlabHost)
+	if err != nil {
+		if provider == source.ProviderGit {
+			return localRemote(gitRoot), gitRoot, nil
+		}
+		return nil, "", fmt.Errorf("could not parse owner/repo from remote URL: %s\n\nRemote URL must be a GitHub or GitLab repository.", remoteURL)
+	}
+
+	if provider != "" {
+		remote.Provider = provider
+	}
+	if remote.Provider == "" {
+		return nil, "", fmt.Errorf("could not detect hosting provider for %s\n\nUse --provider github or --provider gitlab.", remote.Host)
+	}
+
+	return remote, gitRoot, nil
+}
 
-	owner = matches[1]
-	repo = matches[2]
-	repoPath = gitRoot
+// localRemote names a repository without a usable remote after its directory
+func localRemote(gitRoot string) *source.Remote {
+	return &source.Remote{Provider: source.ProviderGit, Owner: "local", Repo: filepath.Base(gitRoot)}
+}
 
-	return owner, repo, repoPath, nil
+// newSourceFetcher creates the staging fetcher for the repository's hosting provider
+func newSourceFetcher(remote *source.Remote, gitlabURL string, stagingDB *database.StagingClient) source.Fetcher {
+	switch remote.Provider {
+	case source.ProviderGit:
+		return gitonly.NewFetcher(stagingDB)
+	case source.ProviderGitLab:
+		return gitlab.NewFetcher(gitlabURL, config.MustGetString("GITLAB_TOKEN"), stagingDB)
+	default:
+		return github.NewFetcher(config.MustGetString("GITHUB_TOKEN"), stagingDB)
+	}
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
 	startTime := time.Now()
 	ctx := context.Background()
 
+	providerFlag, _ := cmd.Flags().GetString("provider")
+	provider, err := source.ParseProvider(providerFlag)
+	if err != nil {
+		return err
+	}
+	gitlabURL, _ := cmd.Flags().GetString("gitlab-url")
+	gitOnly, _ := cmd.Flags().GetBool("git-only")
+	if gitOnly {
+		if provider != "" {
+			return fmt.Errorf("--git-only cannot be combined with --provider %s", provider)
+		}
+		provider = source.ProviderGit
+	}
+
+	// Detect current repository (the provider decides which credentials are needed)
+	fmt.Println("📁 Detecting repository from current directory...")
+	remote, repoPath, err := detectCurrentRepo(provider, gitlabURL)
+	if err != nil {
+		return err
+	}
+	owner, repo := remote.Owner, remote.Repo
+	isGitLab := remote.Provider == source.ProviderGitLab
+	fmt.Printf("  ✓ Detected: %s/%s (%s)\n", owner, repo, remote.Provider)
+	fmt.Printf("  ✓ Path: %s\n", repoPath)
+
 	// Detect deployment mode
 	mode := config.DetectMode()
 
 	var githubToken, geminiAPIKey string
 	var authManager *auth.Manager
 
-	// Production mode: REQUIRE cloud authentication
-	if !mode.AllowsDevelopmentDefaults() {
+	if gitOnly {
+		// Git-only mode needs no hosting token or cloud session (air-gapped repositories, mirrors)
+		fmt.Println("🔌 Git-only mode: skipping hosting API authentication")
+		if mode.AllowsDevelopmentDefaults() {
+			envLoader := config.NewEnvLoader()
+			if err := envLoader.Load(); err != nil {
+				return fmt.Errorf("failed to load .env file: %w\n\nCopy .env.example to .env and configure your credentials.", err)
+			}
+			if err := envLoader.Validate(); err != nil {
+				return err
+			}
+		}
+	} else if !mode.AllowsDevelopmentDefaults() {
+		// Production mode: REQUIRE cloud authentication
 		// Running from packaged binary (brew install, etc.)
 		// MUST authenticate with cloud
 		authManager, err := auth.NewManager()
@@ -147,7 +224,13 @@ func runInit(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to fetch cloud credentials: %w\n\nTry running 'crisk logout' then 'crisk login' again.", err)
 		}
 
-		if creds.GitHubToken == "" {
+		if isGitLab {
+			// Cloud credentials only carry a GitHub token; GitLab tokens come from the environment
+			if os.Getenv("GITLAB_TOKEN") == "" {
+				return fmt.Errorf("❌ Missing GitLab token.\n\n" +
+					"Set GITLAB_TOKEN to a token with read_api scope.\n")
+			}
+		} else if creds.GitHubToken == "" {
 			return fmt.Errorf("❌ Missing GitHub token.\n\n" +
 				"Please configure your API keys at:\n" +
 				"  → https://coderisk.dev/dashboard/settings\n")
@@ -174,7 +257,11 @@ func runInit(cmd *cobra.Command, args []string) error {
 			geminiAPIKey = os.Getenv("OPENAI_API_KEY") // Fallback for legacy configs
 		}
 
-		if githubToken == "" {
+		if isGitLab {
+			if os.Getenv("GITLAB_TOKEN") == "" {
+				return fmt.Errorf("GITLAB_TOKEN not set in .env file")
+			}
+		} else if githubToken == "" {
 			return fmt.Errorf("GITHUB_TOKEN not set in .env file")
 		}
 		// LLM API key is optional - Phase 2 will be disabled if not provided
@@ -182,23 +269,20 @@ func runInit(cmd *cobra.Command, args []string) error {
 		fmt.Println("  ✓ Using credentials from .env file")
 
 		// Validate required variables
-		if err := envLoader.ValidateWithGitHub(); err != nil {
+		validate := envLoader.ValidateWithGitHub
+		if isGitLab {
+			validate = envLoader.ValidateWithGitLab
+		}
+		if err := validate(); err != nil {
 			return err
 		}
 	}
 
 	// Set environment variables for downstream code that expects them
-	os.Setenv("GITHUB_TOKEN", githubToken)
-	os.Setenv("GEMINI_API_KEY", geminiAPIKey)
-
-	// Detect current repository
-	fmt.Println("📁 Detecting repository from current directory...")
-	owner, repo, repoPath, err := detectCurrentRepo()
-	if err != nil {
-		return err
+	if !gitOnly {
+		os.Setenv("GITHUB_TOKEN", githubToken)
+		os.Setenv("GEMINI_API_KEY", geminiAPIKey)
 	}
-	fmt.Printf("  ✓ Detected: %s/%s\n", owner, repo)
-	fmt.Printf("  ✓ Path: %s\n", repoPath)
 
 	fmt.Printf("\n🚀 Initializing CodeRisk for %s/%s...\n", owner, repo)
 	fmt.Printf("   Backend: Neo4j (local)\n")
@@ -250,8 +334,15 @@ func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Printf("\n[1/6] Using repository at %s...\n", repoPath)
 	fmt.Printf("  ✓ Skipping clone (using existing repository)\n")
 
-	// Fetch GitHub data → PostgreSQL
-	fmt.Printf("\n[2/6] Fetching GitHub API data...\n")
+	// Fetch GitHub/GitLab data (or local git history) → PostgreSQL
+	switch {
+	case gitOnly:
+		fmt.Printf("\n[2/6] Reading local git history...\n")
+	case isGitLab:
+		fmt.Printf("\n[2/6] Fetching GitLab API data...\n")
+	default:
+		fmt.Printf("\n[2/6] Fetching GitHub API data...\n")
+	}
 	fetchStart := time.Now()
 
 	// Get time window flags
@@ -270,7 +361,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  ℹ️  Fetching last %d days of history\n", days)
 	}
 
-	fetcher := github.NewFetcher(config.MustGetString("GITHUB_TOKEN"), stagingDB)
+	fetcher := newSourceFetcher(remote, gitlabURL, stagingDB)
 	repoID, stats, err := fetcher.FetchAll(ctx, owner, repo, repoPath, days)
 	if err != nil {
 		return fmt.Errorf("fetch failed: %w", err)
@@ -314,7 +405,12 @@ func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Printf("    Traced %d source files across their complete rename history\n", len(identityMap))
 
 	// Check if --llm flag is enabled
+	// Git-only repositories have no issue or PR text to extract from
 	enableLLM, _ := cmd.Flags().GetBool("llm")
+	if enableLLM && gitOnly {
+		fmt.Printf("\n  ⚠️  --llm has no effect with --git-only (no issues or PRs are fetched)\n")
+		enableLLM = false
+	}
 
 	// Stage: Extract issue-commit-PR relationships using LLM (only if --llm flag is set)
 	if enableLLM {
@@ -521,6 +617,7 @@ func runPipeline2(ctx context.Context, stagingDB *database.StagingClient, graphB
 			DiffContent: diff,
 			AuthorEmail: authorEmail,
 			Timestamp:   authorDate,
+			RepoPath:    repoPath,
 		})
 	}
 
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rohankatakam/coderisk/internal/agent"
+	appconfig "github.com/rohankatakam/coderisk/internal/config"
+	"github.com/rohankatakam/coderisk/internal/git"
+	"github.com/rohankatakam/coderisk/internal/graph"
+	"github.com/rohankatakam/coderisk/internal/incidents"
+	"github.com/rohankatakam/coderisk/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+// investigateCmd drives the directive investigation flow end to end
+// 12-factor: Factor 6 (Launch/Pause/Resume) and Factor 7 (Contact humans with tool calls)
+var investigateCmd = &cobra.Command{
+	Use:   "investigate [file...]",
+	Short: "Run a resumable, human-in-the-loop risk investigation",
+	Long: `Runs Phase 1 on each file and pauses at decision points (uncertain risk,
+high risk, missing co-change data) to ask how to proceed. The investigation is
+checkpointed to PostgreSQL after every decision, so it can be resumed later.
+
+Choices can be supplied non-interactively with --answers: one choice per line
+(a = approve, s = skip, e = modify, x = abort; # starts a comment). When the
+answers run out the investigation pauses and prints its resume ID.
+
+Examples:
+  # Investigate changed files interactively
+  crisk investigate
+
+  # Investigate specific files with recorded answers
+  crisk investigate payments/charge.go --answers answers.txt
+
+  # List and resume open investigations
+  crisk investigate --list
+  crisk investigate --resume 9f2c41d0a7e3b5c8`,
+	RunE: runInvestigate,
+}
+
+func init() {
+	investigateCmd.Flags().String("resume", "", "Resume an investigation by ID")
+	investigateCmd.Flags().Bool("list", false, "List resumable investigations")
+	investigateCmd.Flags().String("answers", "", "Read choices from a file instead of prompting")
+	investigateCmd.Flags().Int("limit", 20, "Maximum number of investigations to list")
+
+	investigateCmd.MarkFlagsMutuallyExclusive("resume", "list")
+}
+
+func runInvestigate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	resumeID, _ := cmd.Flags().GetString("resume")
+	list, _ := cmd.Flags().GetBool("list")
+	answersPath, _ := cmd.Flags().GetString("answers")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	if (resumeID != "" || list) && len(args) > 0 {
+		return fmt.Errorf("files cannot be given with --resume or --list")
+	}
+
+	cfg, err := appconfig.Load("")
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store, err := agent.NewCheckpointStoreFromParams(ctx,
+		cfg.Storage.PostgresHost,
+		cfg.Storage.PostgresPort,
+		cfg.Storage.PostgresDB,
+		cfg.Storage.PostgresUser,
+		cfg.Storage.PostgresPassword,
+	)
+	if err != nil {
+		return fmt.Errorf("checkpoint store initialization failed: %w", err)
+	}
+	defer store.Close()
+
+	if list {
+		return listInvestigations(ctx, store, limit)
+	}
+
+	var choices agent.ChoiceSource = agent.PromptChoices{}
+	if answersPath != "" {
+		answers, err := agent.LoadAnswersFile(answersPath)
+		if err != nil {
+			return err
+		}
+		choices = answers
+	}
+
+	var inv *agent.DirectiveInvestigation
+	if resumeID != "" {
+		inv, err = store.Load(ctx, resumeID)
+		if err != nil {
+			return fmt.Errorf("failed to load investigation %s: %w", resumeID, err)
+		}
+		if inv.IsComplete() {
+			return fmt.Errorf("investigation %s is already complete (%s)", inv.ID, *inv.TerminalState)
+		}
+		agent.DisplayPhaseHeader("Resuming investigation",
+			fmt.Sprintf("%d decision(s) recorded, last phase %s", len(inv.Decisions), inv.Phase))
+	} else {
+		files := args
+		if len(files) == 0 {
+			files, err = git.GetChangedFiles()
+			if err != nil {
+				return fmt.Errorf("failed to get changed files: %w", err)
+			}
+			if len(files) == 0 {
+				fmt.Println("✅ No changed files to investigate")
+				return nil
+			}
+		}
+		inv = agent.NewDirectiveInvestigation(files, lookupRepositoryID(ctx))
+		if err := store.Save(ctx, inv); err != nil {
+			return err
+		}
+	}
+
+	agent.DisplayInvestigationHeader(inv.ID, inv.FilePaths)
+
+	neo4jClient, err := initNeo4j(ctx)
+	if err != nil {
+		return fmt.Errorf("neo4j initialization failed: %w", err)
+	}
+	defer neo4jClient.Close(ctx)
+
+	sqlxDB, err := initPostgresSQLX()
+	if err != nil {
+		return fmt.Errorf("sqlx postgres initialization failed: %w", err)
+	}
+	defer sqlxDB.Close()
+
+	assessor, err := newPhase1Assessor(neo4jClient, incidents.NewDatabase(sqlxDB))
+	if err != nil {
+		return err
+	}
+
+	session := &agent.DirectiveSession{
+		Investigation: inv,
+		Assess:        assessor,
+		Choices:       choices,
+		Checkpoints:   store,
+	}
+
+	paused, err := session.Run(ctx)
+	if err != nil {
+		return err
+	}
+	if paused {
+		return nil
+	}
+
+	// Non-zero exit for unresolved escalations, matching check's pre-commit behaviour
+	if inv.TerminalState != nil && *inv.TerminalState == agent.RisksUnresolved {
+		return exitWith(cmd, 1)
+	}
+	return nil
+}
+
+// newPhase1Assessor resolves each file to its historical paths and runs Phase 1
+// A failed metric query leaves that metric nil, reported as missing data, instead of
+// failing the file.
+func newPhase1Assessor(neo4jClient *graph.Client, incidentsDB *incidents.Database) (agent.FileAssessor, error) {
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository root: %w", err)
+	}
+
+	repoID, err := git.GetRepoID()
+	if err != nil {
+		repoID = "local"
+	}
+
+	repoMetadata := collectRepoMetadata()
+	riskConfig, _ := appconfig.SelectConfigWithReason(repoMetadata)
+	resolver := git.NewFileResolver(repoRoot, neo4jClient)
+
+	return func(ctx context.Context, file string) (*agent.RiskAssessment, map[string]bool, error) {
+		agent.DisplayProgressMessage(fmt.Sprintf("Phase 1: %s", file))
+
+		matches, err := resolver.Resolve(ctx, file)
+		if err != nil {
+			slog.Warn("file resolution failed", "file", file, "error", err)
+		}
+
+		queryPaths := []string{file}
+		resolutionConfidence := 0.0
+		if len(matches) > 0 {
+			queryPaths = queryPaths[:0]
+			for _, match := range matches {
+				queryPaths = append(queryPaths, match.HistoricalPath)
+			}
+			resolutionConfidence = matches[0].Confidence
+		}
+
+		result, err := metrics.CalculatePartialPhase1(ctx, neo4jClient, repoID, queryPaths, riskConfig)
+		if err != nil {
+			slog.Warn("phase 1 metrics incomplete", "file", file, "error", err)
+		}
+		applyIncidents(ctx, incidentsDB, queryPaths, result.Phase1Result)
+
+		assessment, missingData := agent.AssessmentFromPhase1(file, result.Phase1Result, resolutionConfidence)
+		return assessment, missingData, nil
+	}, nil
+}
+
+// lookupRepositoryID returns the staging repository ID for the current repo, or 0
+func lookupRepositoryID(ctx context.Context) int64 {
+	fullName, err := git.GetRepoID()
+	if err != nil {
+		return 0
+	}
+
+	stagingClient, err := initStagingClient(ctx)
+	if err != nil {
+		return 0
+	}
+	defer stagingClient.Close()
+
+	id, err := stagingClient.GetRepositoryID(ctx, fullName)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+func listInvestigations(ctx context.Context, store *agent.CheckpointStore, limit int) error {
+	investigations, err := store.ListResumable(ctx, limit)
+	if err != nil {
+		return err
+	}
+
+	if len(investigations) == 0 {
+		fmt.Println("No open investigations")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPHASE\tUPDATED\tFILES")
+	for _, inv := range investigations {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			inv.ID, inv.Phase, inv.UpdatedAt.Format("2006-01-02 15:04"), strings.Join(inv.FilePaths, ", "))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/backtest"
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/git"
+	"github.com/rohankatakam/coderisk/internal/linking/types"
+	"github.com/spf13/cobra"
+)
+
+var linksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "Review issue-PR links and export them as ground truth",
+}
+
+var linksReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Accept or reject the least confident issue-PR links",
+	Long: `Walk the lowest-confidence issue-PR links with the issue and PR, the confidence
+breakdown and the linker's rationale, and record a decision for each:
+
+  a [note]   accept: the link is raised to confidence 1.0
+  r [note]   reject: the link is removed and will not be re-created by the linker
+  s          skip for now
+  q          quit
+
+Decisions take effect in CLQS on the next 'clqs-calculator --recalculate' and in
+the graph on the next 'crisk ingest'. Export them for backtesting with
+'crisk links export'.
+
+Examples:
+  # Review up to 20 links below 0.85 confidence
+  crisk links review
+
+  # Only the weakest links
+  crisk links review --max-confidence 0.7 --limit 10`,
+	Args: cobra.NoArgs,
+	RunE: runLinksReview,
+}
+
+var linksExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export link labels as backtest ground truth",
+	Args:  cobra.NoArgs,
+	RunE:  runLinksExport,
+}
+
+func init() {
+	linksReviewCmd.Flags().Float64("max-confidence", 0.85, "Review links below this confidence")
+	linksReviewCmd.Flags().Int("limit", 20, "Maximum links to review (0 = all)")
+	linksReviewCmd.Flags().String("labeler", "", "Name recorded with decisions (default: git user.email)")
+
+	linksExportCmd.Flags().StringP("output", "o", "link_ground_truth.json", "Ground truth JSON file")
+
+	linksCmd.AddCommand(linksReviewCmd)
+	linksCmd.AddCommand(linksExportCmd)
+}
+
+func runLinksReview(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	maxConfidence, _ := cmd.Flags().GetFloat64("max-confidence")
+	limit, _ := cmd.Flags().GetInt("limit")
+	labeler, _ := cmd.Flags().GetString("labeler")
+	if labeler == "" {
+		labeler, _ = git.GetAuthorEmail()
+	}
+
+	stagingDB, err := initStagingClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	defer stagingDB.Close()
+
+	repoID, repoName, err := cli.DetectRepoID(ctx, stagingDB.DB())
+	if err != nil {
+		return err
+	}
+
+	links, err := stagingDB.GetLinksForReview(ctx, repoID, maxConfidence, limit)
+	if err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		fmt.Printf("✓ No unlabeled links below %.2f confidence in %s\n", maxConfidence, repoName)
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	accepted, rejected := 0, 0
+	for i, link := range links {
+		fmt.Printf("\n════════ [%d/%d] Issue #%d ↔ PR #%d ════════\n", i+1, len(links), link.IssueNumber, link.PRNumber)
+		printLinkForReview(ctx, stagingDB, repoID, link)
+
+		fmt.Print("\n[a]ccept / [r]eject (optionally followed by a note) / [s]kip / [q]uit: ")
+		line, _ := reader.ReadString('\n')
+		action, note, _ := strings.Cut(strings.TrimSpace(line), " ")
+		note = strings.TrimSpace(note)
+
+		var decision string
+		switch strings.ToLower(action) {
+		case "a", "accept":
+			decision = database.LinkLabelAccepted
+		case "r", "reject":
+			decision = database.LinkLabelRejected
+		case "q", "quit":
+			printLinksReviewSummary(accepted, rejected)
+			return nil
+		default:
+			continue
+		}
+
+		if err := stagingDB.SaveLinkLabel(ctx, repoID, link, decision, note, labeler); err != nil {
+			return err
+		}
+		if decision == database.LinkLabelAccepted {
+			accepted++
+		} else {
+			rejected++
+		}
+		fmt.Printf("✓ %s\n", decision)
+	}
+
+	printLinksReviewSummary(accepted, rejected)
+	return nil
+}
+
+func printLinkForReview(ctx context.Context, stagingDB *database.StagingClient, repoID int64, link types.LinkOutput) {
+	if issue, err := stagingDB.GetIssueByNumber(ctx, repoID, link.IssueNumber); err == nil {
+		fmt.Printf("\nISSUE #%d: %s\n", issue.IssueNumber, issue.Title)
+		closed := "open"
+		if issue.ClosedAt != nil {
+			closed = "closed " + issue.ClosedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("  %s, %d comments\n", closed, len(issue.Comments))
+		printIndented(truncateText(issue.Body, 600))
+	} else {
+		fmt.Printf("\nISSUE #%d: (not staged: %v)\n", link.IssueNumber, err)
+	}
+
+	if pr, err := stagingDB.GetPRByNumber(ctx, repoID, link.PRNumber); err == nil {
+		fmt.Printf("\nPR #%d: %s\n", pr.PRNumber, pr.Title)
+		state := pr.State
+		if pr.MergedAt != nil {
+			state = "merged " + pr.MergedAt.Format("2006-01-02 15:04")
+		}
+		fmt.Printf("  %s, %d files\n", state, len(pr.Files))
+		for i, f := range pr.Files {
+			if i == 5 {
+				fmt.Printf("    ... %d more\n", len(pr.Files)-5)
+				break
+			}
+			fmt.Printf("    %s (+%d -%d)\n", f.Filename, f.Additions, f.Deletions)
+		}
+		printIndented(truncateText(pr.Body, 600))
+	} else {
+		fmt.Printf("\nPR #%d: (not staged: %v)\n", link.PRNumber, err)
+	}
+
+	b := link.ConfidenceBreakdown
+	fmt.Printf("\nLINK: confidence %.2f (%s), detected by %s\n", link.FinalConfidence, link.LinkQuality, link.DetectionMethod)
+	fmt.Printf("  Evidence: %s\n", strings.Join(link.EvidenceSources, ", "))
+	fmt.Printf("  Base %.2f  temporal %+.2f  bidirectional %+.2f  semantic %+.2f  file context %+.2f\n",
+		b.BaseConfidence, b.TemporalBoost, b.BidirectionalBoost, b.SemanticBoost, b.FileContextBoost)
+	if b.SharedPRPenalty != 0 || b.NegativeSignalPenalty != 0 {
+		fmt.Printf("  Shared PR penalty %.2f  negative signal penalty %.2f\n", b.SharedPRPenalty, b.NegativeSignalPenalty)
+	}
+	if link.Flags.AmbiguityReason != "" {
+		fmt.Printf("  Ambiguity: %s\n", link.Flags.AmbiguityReason)
+	}
+	if link.ComprehensiveRationale != "" {
+		fmt.Println("  Rationale:")
+		printIndented(truncateText(link.ComprehensiveRationale, 800))
+	}
+}
+
+func printLinksReviewSummary(accepted, rejected int) {
+	fmt.Printf("\n%d accepted, %d rejected\n", accepted, rejected)
+	if accepted+rejected > 0 {
+		fmt.Println("Run 'clqs-calculator --recalculate' to update CLQS and 'crisk ingest' to update the graph.")
+	}
+}
+
+func printIndented(text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Printf("    %s\n", line)
+	}
+}
+
+func truncateText(text string, max int) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= max {
+		return text
+	}
+	return text[:max] + "..."
+}
+
+func runLinksExport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	stagingDB, err := initStagingClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	defer stagingDB.Close()
+
+	repoID, repoName, err := cli.DetectRepoID(ctx, stagingDB.DB())
+	if err != nil {
+		return err
+	}
+
+	labels, err := stagingDB.GetLinkLabels(ctx, repoID)
+	if err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return fmt.Errorf("no link labels for %s (run 'crisk links review' first)", repoName)
+	}
+	links, err := stagingDB.GetIssuePRLinks(ctx, repoID)
+	if err != nil {
+		return err
+	}
+
+	gt := backtest.GroundTruthFromLabels(repoName, labels, links)
+	gt.ValidationDate = time.Now().Format("2006-01-02")
+	if err := backtest.SaveGroundTruth(gt, outputPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Exported %d test cases from %d labels to %s\n", gt.TotalCases, len(labels), outputPath)
+	return nil
+}
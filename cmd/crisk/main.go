@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -23,11 +24,30 @@ var (
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
+		var status exitStatus
+		if errors.As(err, &status) {
+			os.Exit(int(status))
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// exitStatus is returned from RunE to end the process with a non-zero exit code after
+// the command's deferred cleanup has run, without printing an error
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// exitWith silences cobra's error and usage output and returns code as an exitStatus
+func exitWith(cmd *cobra.Command, code int) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return exitStatus(code)
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "crisk",
 	Short: "CodeRisk - Lightning-fast risk assessment for code changes",
@@ -64,10 +84,21 @@ Git commit: ` + GitCommit + `
 `)
 
 	// Add essential commands only
-	rootCmd.AddCommand(initCmd)   // Initialize repo with graph construction
-	rootCmd.AddCommand(ingestCmd) // Build graph from staged data
-	rootCmd.AddCommand(checkCmd)  // Check files for risk
-	rootCmd.AddCommand(logCmd)    // Show function history (git on steroids)
-	rootCmd.AddCommand(blameCmd)  // Show ownership and risk attribution
+	rootCmd.AddCommand(initCmd)        // Initialize repo with graph construction
+	rootCmd.AddCommand(ingestCmd)      // Build graph from staged data
+	rootCmd.AddCommand(checkCmd)       // Check files for risk
+	rootCmd.AddCommand(logCmd)         // Show function history (git on steroids)
+	rootCmd.AddCommand(blameCmd)       // Show ownership and risk attribution
+	rootCmd.AddCommand(incidentCmd)    // Record incidents and link them to code
+	rootCmd.AddCommand(investigateCmd) // Resumable human-in-the-loop investigation
+	rootCmd.AddCommand(reviewersCmd)   // Recommend reviewers for a change
+	rootCmd.AddCommand(codeownersCmd)  // Generate CODEOWNERS and detect drift
+	rootCmd.AddCommand(identityCmd)    // Unify developer identities
+	rootCmd.AddCommand(reportCmd)      // Knowledge and bus-factor reports
+	rootCmd.AddCommand(hotspotsCmd)    // Rank refactoring hotspots
+	rootCmd.AddCommand(simulateCmd)    // What-if simulations (developer departure)
+	rootCmd.AddCommand(linksCmd)       // Review issue-PR links
+	rootCmd.AddCommand(backtestCmd)    // Validate risk verdicts against history
+	rootCmd.AddCommand(coverageCmd)    // Import CI coverage reports
 	// Note: login, logout, whoami are cloud auth commands (added via init())
 }
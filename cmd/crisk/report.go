@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/knowledge"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate repository-wide reports",
+}
+
+var reportKnowledgeCmd = &cobra.Command{
+	Use:   "knowledge [directory]",
+	Short: "Report bus factor and knowledge loss per directory",
+	Long: `Roll block ownership up the directory tree: bus factor, stale ownership,
+single-author critical blocks, and blocks whose only familiar developers have left.
+
+A developer has left when they have no commits within --departed-days. Ownership is
+stale when a block's top owner has no commits within --stale-days. Bus factor is the
+fewest active developers whose departure would leave more than half of a directory's
+blocks with nobody familiar (0 means that has already happened).
+
+Examples:
+  # Markdown report for the quarterly engineering review
+  crisk report knowledge -o knowledge.md
+
+  # Only the billing service, treating six months of silence as departed
+  crisk report knowledge services/billing --departed-days 180
+
+  # JSON for dashboards, as of the end of last quarter
+  crisk report knowledge --format json --as-of 2025-03-31`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReportKnowledge,
+}
+
+func init() {
+	defaults := knowledge.DefaultOptions()
+
+	reportKnowledgeCmd.Flags().Int("stale-days", defaults.StaleDays, "Ownership is stale when the top owner has no commits for this many days")
+	reportKnowledgeCmd.Flags().Int("departed-days", defaults.DepartedDays, "Developers with no commits for this many days have left")
+	reportKnowledgeCmd.Flags().Float64("critical-risk", defaults.CriticalRisk, "Blocks above this risk score (or with incidents) are critical")
+	reportKnowledgeCmd.Flags().Int("max-depth", defaults.MaxDepth, "Deepest directory level reported (0 = unlimited)")
+	reportKnowledgeCmd.Flags().Int("top", defaults.TopBlocks, "Blocks listed per finding (0 = all)")
+	reportKnowledgeCmd.Flags().String("as-of", "", "Reference date for activity windows, YYYY-MM-DD (default: today)")
+	reportKnowledgeCmd.Flags().String("format", "markdown", "Output format: markdown, json")
+	reportKnowledgeCmd.Flags().StringP("output", "o", "", "Write to a file instead of stdout")
+
+	reportCmd.AddCommand(reportKnowledgeCmd)
+}
+
+func runReportKnowledge(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	opts := knowledge.DefaultOptions()
+	opts.StaleDays, _ = cmd.Flags().GetInt("stale-days")
+	opts.DepartedDays, _ = cmd.Flags().GetInt("departed-days")
+	opts.CriticalRisk, _ = cmd.Flags().GetFloat64("critical-risk")
+	opts.MaxDepth, _ = cmd.Flags().GetInt("max-depth")
+	opts.TopBlocks, _ = cmd.Flags().GetInt("top")
+	asOf, _ := cmd.Flags().GetString("as-of")
+	format, _ := cmd.Flags().GetString("format")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	if format != "markdown" && format != "json" {
+		return fmt.Errorf("invalid format %q, must be: markdown or json", format)
+	}
+	if asOf != "" {
+		t, err := time.Parse("2006-01-02", asOf)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of date %q (expected YYYY-MM-DD): %w", asOf, err)
+		}
+		opts.Now = t
+	}
+
+	db, err := initPostgresSQLX()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	repoID, repoName, err := cli.DetectRepoID(ctx, db.DB)
+	if err != nil {
+		return err
+	}
+	if err := cli.EnsureRepoInitialized(ctx, db.DB, repoID); err != nil {
+		return err
+	}
+
+	dir := ""
+	if len(args) > 0 {
+		if dir, err = cli.GetRelativePath(args[0]); err != nil {
+			return err
+		}
+	}
+
+	blocks, err := database.GetBlocksInDirectory(ctx, db, repoID, dir)
+	if err != nil {
+		return fmt.Errorf("failed to get code blocks: %w", err)
+	}
+	if len(blocks) == 0 {
+		return fmt.Errorf("no code blocks found for %s (run 'crisk init' first)", repoName)
+	}
+	lastCommit, err := database.GetDeveloperLastCommit(ctx, db, repoID)
+	if err != nil {
+		return err
+	}
+
+	report := knowledge.Build(blocks, lastCommit, opts)
+
+	var buf bytes.Buffer
+	if format == "json" {
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = knowledge.WriteMarkdown(&buf, repoName, report)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	if outputPath == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+	fmt.Printf("✓ Wrote knowledge report for %d blocks to %s\n", report.Summary.Blocks, outputPath)
+	return nil
+}
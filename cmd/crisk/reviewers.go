@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/rohankatakam/coderisk/internal/git"
+	"github.com/rohankatakam/coderisk/internal/reviewers"
+	"github.com/spf13/cobra"
+)
+
+var reviewersCmd = &cobra.Command{
+	Use:   "reviewers [file...]",
+	Short: "Recommend reviewers for a change",
+	Long: `Ranks candidate reviewers for a change by their familiarity with the touched
+code blocks and the blocks that usually change with them, how recently they worked
+there, and whether they fixed incidents in that code. Developers who were asked for
+many reviews recently are ranked lower, and the change author is excluded.
+
+Without arguments the uncommitted changes are used; --base/--head select a commit
+range instead. Named files are treated as fully touched.
+
+Examples:
+  # Reviewers for uncommitted changes
+  crisk reviewers
+
+  # Reviewers for a branch
+  crisk reviewers --base main --head feature/payments
+
+  # CODEOWNERS-style suggestion per file
+  crisk reviewers --base main --format codeowners
+
+  # JSON for bots
+  crisk reviewers src/auth.ts --format json`,
+	RunE: runReviewers,
+}
+
+func init() {
+	reviewersCmd.Flags().String("base", "", "Base ref to diff from (default: uncommitted changes)")
+	reviewersCmd.Flags().String("head", "", "Head ref to diff to (default: working tree)")
+	reviewersCmd.Flags().String("author", "", "Change author to exclude (default: head commit author or git user.email)")
+	reviewersCmd.Flags().Int("limit", 3, "Maximum number of reviewers")
+	reviewersCmd.Flags().String("format", "table", "Output format: table, codeowners, json")
+	reviewersCmd.Flags().Float64("min-coupling", 0.3, "Minimum co-change rate for related blocks")
+	reviewersCmd.Flags().Int("review-window-days", 14, "Days of review requests counted as current load")
+}
+
+func runReviewers(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	base, _ := cmd.Flags().GetString("base")
+	head, _ := cmd.Flags().GetString("head")
+	author, _ := cmd.Flags().GetString("author")
+	limit, _ := cmd.Flags().GetInt("limit")
+	format, _ := cmd.Flags().GetString("format")
+	minCoupling, _ := cmd.Flags().GetFloat64("min-coupling")
+	windowDays, _ := cmd.Flags().GetInt("review-window-days")
+
+	if format != "table" && format != "codeowners" && format != "json" {
+		return fmt.Errorf("invalid format %q, must be: table, codeowners, or json", format)
+	}
+	if head != "" && base == "" {
+		return fmt.Errorf("--head requires --base")
+	}
+	if len(args) > 0 && base != "" {
+		return fmt.Errorf("files cannot be given with --base/--head")
+	}
+
+	changes, err := reviewerChanges(args, base, head)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("No changed files")
+		return nil
+	}
+
+	if author == "" {
+		if head != "" {
+			author, err = git.GetCommitAuthorEmail(head)
+		} else {
+			author, err = git.GetAuthorEmail()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to determine change author (use --author): %w", err)
+		}
+	}
+
+	db, err := initPostgresSQLX()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	repoID, _, err := cli.DetectRepoID(ctx, db.DB)
+	if err != nil {
+		return err
+	}
+	if err := cli.EnsureRepoInitialized(ctx, db.DB, repoID); err != nil {
+		return err
+	}
+
+	opts := reviewers.DefaultOptions(author)
+	opts.Limit = limit
+	opts.MinCouplingRate = minCoupling
+	opts.ReviewWindow = time.Duration(windowDays) * 24 * time.Hour
+
+	rec, err := reviewers.Recommend(ctx, db, repoID, changes, opts)
+	if err != nil {
+		return fmt.Errorf("failed to recommend reviewers: %w", err)
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rec)
+	case "codeowners":
+		fmt.Print(rec.CODEOWNERS())
+		return nil
+	default:
+		return printReviewers(rec)
+	}
+}
+
+// reviewerChanges returns the changed line ranges per file (nil ranges = whole file)
+func reviewerChanges(files []string, base, head string) (map[string][]git.LineRange, error) {
+	changes := make(map[string][]git.LineRange)
+	if len(files) > 0 {
+		for _, file := range files {
+			relPath, err := cli.GetRelativePath(file)
+			if err != nil {
+				return nil, err
+			}
+			changes[relPath] = nil
+		}
+		return changes, nil
+	}
+
+	ranges, err := git.GetChangedLineRanges(base, head)
+	if err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+func printReviewers(rec *reviewers.Recommendation) error {
+	fmt.Printf("Touched blocks: %d (+%d co-changed)\n\n", rec.TouchedBlocks, rec.CoChangedBlocks)
+
+	if len(rec.Candidates) == 0 {
+		fmt.Println("No reviewers found: the touched code has no ownership history besides the author.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REVIEWER\tSCORE\tWHY")
+	for _, c := range rec.Candidates {
+		fmt.Fprintf(w, "%s\t%.2f\t%s\n", c.Handle(), c.Score, strings.Join(c.Reasons, "; "))
+	}
+	return w.Flush()
+}
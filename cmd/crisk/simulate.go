@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rohankatakam/coderisk/internal/cli"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/knowledge"
+	"github.com/spf13/cobra"
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate changes to the team and their effect on risk",
+}
+
+var simulateDepartureCmd = &cobra.Command{
+	Use:   "departure [directory]",
+	Short: "Show what happens to ownership and risk if developers leave",
+	Long: `Remove developers from every block's familiarity map (in memory only; nothing
+is written) and recompute SME, bus factor and risk level. Reports the blocks and
+files that would have nobody familiar left, blocks whose risk level rises, and who
+would inherit the departing developers' blocks.
+
+Examples:
+  # What if alice leaves?
+  crisk simulate departure --developer alice@corp.com
+
+  # A whole team leaving, limited to one service
+  crisk simulate departure services/billing -d alice@corp.com -d bob@corp.com
+
+  # JSON for planning tools
+  crisk simulate departure -d alice@corp.com --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSimulateDeparture,
+}
+
+func init() {
+	simulateDepartureCmd.Flags().StringSliceP("developer", "d", nil, "Email of a departing developer (repeatable)")
+	simulateDepartureCmd.Flags().Int("limit", 20, "Blocks and files shown in table output (0 = all)")
+	simulateDepartureCmd.Flags().String("format", "table", "Output format: table, json")
+	simulateDepartureCmd.MarkFlagRequired("developer")
+
+	simulateCmd.AddCommand(simulateDepartureCmd)
+}
+
+func runSimulateDeparture(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	developers, _ := cmd.Flags().GetStringSlice("developer")
+	limit, _ := cmd.Flags().GetInt("limit")
+	format, _ := cmd.Flags().GetString("format")
+
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid format %q, must be: table or json", format)
+	}
+
+	db, err := initPostgresSQLX()
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer db.Close()
+
+	repoID, repoName, err := cli.DetectRepoID(ctx, db.DB)
+	if err != nil {
+		return err
+	}
+	if err := cli.EnsureRepoInitialized(ctx, db.DB, repoID); err != nil {
+		return err
+	}
+
+	dir := ""
+	if len(args) > 0 {
+		if dir, err = cli.GetRelativePath(args[0]); err != nil {
+			return err
+		}
+	}
+
+	blocks, err := database.GetBlocksInDirectory(ctx, db, repoID, dir)
+	if err != nil {
+		return fmt.Errorf("failed to get code blocks: %w", err)
+	}
+	if len(blocks) == 0 {
+		return fmt.Errorf("no code blocks found for %s (run 'crisk init' first)", repoName)
+	}
+
+	result := knowledge.SimulateDeparture(blocks, developers)
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if result.AffectedBlocks == 0 {
+		fmt.Printf("No blocks in %s list %v in their familiarity map\n", repoName, result.Developers)
+		return nil
+	}
+
+	fmt.Printf("Departure of %v from %s\n\n", result.Developers, repoName)
+	fmt.Printf("  Blocks affected:      %d of %d\n", result.AffectedBlocks, result.TotalBlocks)
+	fmt.Printf("  New SME needed:       %d\n", result.SMEChanges)
+	fmt.Printf("  Risk level increases: %d\n", result.RiskIncreases)
+	fmt.Printf("  Orphaned blocks:      %d\n", result.OrphanedBlocks)
+	fmt.Printf("  Orphaned files:       %d\n", result.OrphanedFiles)
+
+	fmt.Println("\nBlocks")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BLOCK\tLOCATION\tSME\tBUS FACTOR\tRISK")
+	for i, b := range result.Blocks {
+		if limit > 0 && i == limit {
+			fmt.Fprintf(w, "... %d more\t\t\t\t\n", len(result.Blocks)-limit)
+			break
+		}
+		sme := b.SMEAfter
+		if b.Orphaned {
+			sme = "(nobody)"
+		}
+		fmt.Fprintf(w, "%s\t%s:%d\t%s -> %s\t%s -> %s\t%s -> %s\n",
+			b.Name, b.File, b.StartLine, b.SMEBefore, sme,
+			b.BusFactorBefore, b.BusFactorAfter, b.RiskBefore, b.RiskAfter)
+	}
+	w.Flush()
+
+	fmt.Println("\nFiles")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tAFFECTED\tORPHANED\tRISK UP")
+	for i, f := range result.Files {
+		if limit > 0 && i == limit {
+			fmt.Fprintf(w, "... %d more\t\t\t\n", len(result.Files)-limit)
+			break
+		}
+		name := f.File
+		if f.Orphaned {
+			name += " (orphaned)"
+		}
+		fmt.Fprintf(w, "%s\t%d/%d\t%d\t%d\n", name, f.AffectedBlocks, f.Blocks, f.OrphanedBlocks, f.RiskIncreases)
+	}
+	w.Flush()
+
+	if len(result.Successors) > 0 {
+		fmt.Println("\nSuccessors (new SME of blocks the departing developers owned)")
+		for _, s := range result.Successors {
+			fmt.Printf("  %-40s %d blocks\n", s.Email, s.Blocks)
+		}
+	}
+	return nil
+}
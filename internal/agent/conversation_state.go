@@ -30,7 +30,7 @@ const (
 
 // DirectiveInvestigation represents the complete state of a directive investigation
 // This can be checkpointed and resumed across CLI sessions
-// Distinct from Investigation (types.go) which is for the RiskInvestigator agent
+// Distinct from Investigation (types.go) which is for the tool-calling Investigator
 type DirectiveInvestigation struct {
 	ID            string            `json:"id"`
 	Phase         ConversationPhase `json:"phase"`
@@ -171,7 +171,7 @@ func DisplayErrorMessage(message string) {
 // DisplayCheckpointSaved shows checkpoint save confirmation
 func DisplayCheckpointSaved(investigationID string) {
 	fmt.Println()
-	fmt.Printf("💾 Investigation saved. Resume with: crisk check --resume %s\n", investigationID)
+	fmt.Printf("💾 Investigation saved. Resume with: crisk investigate --resume %s\n", investigationID)
 	fmt.Println()
 }
 
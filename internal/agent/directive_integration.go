@@ -149,6 +149,9 @@ func HandleUserChoice(choice string, directive *DirectiveMessage) (shouldContinu
 }
 
 // CheckpointSaver interface for saving investigation state
+// Implemented by CheckpointStore
 type CheckpointSaver interface {
-	Save(ctx context.Context, investigation *DirectiveInvestigation) (string, error)
+	Save(ctx context.Context, investigation *DirectiveInvestigation) error
 }
+
+var _ CheckpointSaver = (*CheckpointStore)(nil)
@@ -0,0 +1,316 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/metrics"
+)
+
+// Resume data keys for directive sessions
+const (
+	resumeKeyNextFile     = "next_file"
+	resumeKeyFileOutcomes = "file_outcomes"
+)
+
+// FileAssessor produces the risk assessment for one file plus any missing-data flags
+// (e.g. missingData["cochange"]) consumed by CheckForDirectiveNeeded
+type FileAssessor func(ctx context.Context, filePath string) (*RiskAssessment, map[string]bool, error)
+
+// ChoiceSource supplies the user's answer at a decision point
+// ok is false when no answer is available and the session should pause
+type ChoiceSource interface {
+	Choose(directive *DirectiveMessage, defaultChoice string, shortcuts []string) (choice string, ok bool)
+}
+
+// PromptChoices reads choices interactively from the terminal
+type PromptChoices struct{}
+
+// Choose prompts for a choice on stdin
+func (PromptChoices) Choose(directive *DirectiveMessage, defaultChoice string, shortcuts []string) (string, bool) {
+	return PromptForChoice(defaultChoice, shortcuts), true
+}
+
+// AnswersFile replays pre-recorded choices for non-interactive runs
+// One choice per line; blank lines and lines starting with # are ignored.
+// When the answers run out the session pauses and can be resumed later.
+type AnswersFile struct {
+	answers []string
+	next    int
+}
+
+// LoadAnswersFile reads an answers file from disk
+func LoadAnswersFile(path string) (*AnswersFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open answers file: %w", err)
+	}
+	defer f.Close()
+	return ParseAnswers(f)
+}
+
+// ParseAnswers reads answers from r
+func ParseAnswers(r io.Reader) (*AnswersFile, error) {
+	var answers []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		answers = append(answers, strings.ToLower(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read answers: %w", err)
+	}
+	return &AnswersFile{answers: answers}, nil
+}
+
+// Choose returns the next recorded answer
+func (a *AnswersFile) Choose(directive *DirectiveMessage, defaultChoice string, shortcuts []string) (string, bool) {
+	if a.next >= len(a.answers) {
+		return "", false
+	}
+	choice := a.answers[a.next]
+	a.next++
+	fmt.Printf("Choice (from answers file): %s\n", choice)
+	return choice, true
+}
+
+// fileOutcome is the per-file result kept in resume data
+type fileOutcome struct {
+	RiskLevel  RiskLevel `json:"risk_level"`
+	Confidence float64   `json:"confidence"`
+	Unresolved bool      `json:"unresolved"` // Escalation was not approved
+}
+
+// DirectiveSession drives a DirectiveInvestigation file by file: assess, raise a
+// directive when CheckForDirectiveNeeded asks for one, record the user's choice and
+// checkpoint after every step so the investigation can be resumed.
+type DirectiveSession struct {
+	Investigation *DirectiveInvestigation
+	Assess        FileAssessor
+	Choices       ChoiceSource
+	Checkpoints   CheckpointSaver
+}
+
+// Run continues the investigation from its checkpointed position
+// Returns paused=true when the choice source had no answer for a pending directive.
+func (s *DirectiveSession) Run(ctx context.Context) (paused bool, err error) {
+	inv := s.Investigation
+	if inv.IsComplete() {
+		return false, fmt.Errorf("investigation %s is already complete (%s)", inv.ID, *inv.TerminalState)
+	}
+
+	outcomes := map[string]fileOutcome{}
+	if err := inv.decodeResumeData(resumeKeyFileOutcomes, &outcomes); err != nil {
+		return false, err
+	}
+	next := 0
+	if err := inv.decodeResumeData(resumeKeyNextFile, &next); err != nil {
+		return false, err
+	}
+
+	for i := next; i < len(inv.FilePaths); i++ {
+		file := inv.FilePaths[i]
+
+		inv.UpdatePhase(PhasePhase1Running)
+		assessment, missingData, err := s.Assess(ctx, file)
+		if err != nil {
+			return false, fmt.Errorf("assessment failed for %s: %w", file, err)
+		}
+		outcome := fileOutcome{RiskLevel: assessment.RiskLevel, Confidence: assessment.Confidence}
+
+		decision := CheckForDirectiveNeeded(assessment, file, missingData)
+		if decision.ShouldPause {
+			inv.UpdatePhase(PhaseAwaitingHuman)
+			inv.SetResumeData(resumeKeyNextFile, i)
+			if err := s.Checkpoints.Save(ctx, inv); err != nil {
+				return false, err
+			}
+
+			directive := decision.Directive
+			DisplayDirective(directive, len(inv.Decisions)+1)
+
+			shortcuts := make([]string, len(directive.UserOptions))
+			for j, opt := range directive.UserOptions {
+				shortcuts[j] = opt.Shortcut
+			}
+
+			var choice string
+			for {
+				var ok bool
+				choice, ok = s.Choices.Choose(directive, "a", shortcuts)
+				if !ok {
+					DisplayCheckpointSaved(inv.ID)
+					return true, nil
+				}
+
+				shouldContinue, shouldAbort := HandleUserChoice(choice, directive)
+				if shouldAbort {
+					inv.AddDecision(directive, choice, "")
+					inv.SetTerminalState(InvestigationAborted, string(assessment.RiskLevel), assessment.Confidence,
+						fmt.Sprintf("Investigation aborted at %s", file))
+					return false, s.Checkpoints.Save(ctx, inv)
+				}
+				if shouldContinue {
+					break
+				}
+				fmt.Println("Invalid choice. Please try again.")
+			}
+
+			inv.AddDecision(directive, choice, "")
+			outcome.Unresolved = directive.Action.Type == DirectiveTypeEscalate && choice != "a"
+		}
+
+		outcomes[file] = outcome
+		inv.SetResumeData(resumeKeyFileOutcomes, outcomes)
+		inv.SetResumeData(resumeKeyNextFile, i+1)
+		if err := s.Checkpoints.Save(ctx, inv); err != nil {
+			return false, err
+		}
+	}
+
+	state, level, confidence, summary := summarizeOutcomes(inv.FilePaths, outcomes)
+	inv.UpdatePhase(PhaseAssessmentComplete)
+	inv.SetTerminalState(state, string(level), confidence, summary)
+	if err := s.Checkpoints.Save(ctx, inv); err != nil {
+		return false, err
+	}
+
+	DisplayFinalAssessment(inv.FinalRiskLevel, inv.FinalConfidence, inv.FinalSummary)
+	return false, nil
+}
+
+// summarizeOutcomes derives the terminal state from per-file outcomes
+// Overall risk is the highest file risk; confidence is the lowest file confidence.
+func summarizeOutcomes(files []string, outcomes map[string]fileOutcome) (TerminalState, RiskLevel, float64, string) {
+	state := SafeToCommit
+	level := RiskMinimal
+	confidence := 1.0
+	var unresolved []string
+
+	for _, file := range files {
+		o, ok := outcomes[file]
+		if !ok {
+			continue
+		}
+		if riskRank(o.RiskLevel) > riskRank(level) {
+			level = o.RiskLevel
+		}
+		if o.Confidence < confidence {
+			confidence = o.Confidence
+		}
+		if o.Unresolved {
+			unresolved = append(unresolved, file)
+		}
+	}
+
+	summary := fmt.Sprintf("Assessed %d file(s); highest risk %s.", len(outcomes), level)
+	if len(unresolved) > 0 {
+		state = RisksUnresolved
+		summary += fmt.Sprintf(" Escalations not approved for: %s.", strings.Join(unresolved, ", "))
+	}
+	return state, level, confidence, summary
+}
+
+func riskRank(level RiskLevel) int {
+	switch level {
+	case RiskCritical:
+		return 4
+	case RiskHigh:
+		return 3
+	case RiskMedium:
+		return 2
+	case RiskLow:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// decodeResumeData decodes a resume data value into v
+// Values round-trip through JSON when checkpoints are loaded, so they are re-decoded
+// rather than type-asserted.
+func (inv *DirectiveInvestigation) decodeResumeData(key string, v interface{}) error {
+	raw, ok := inv.GetResumeData(key)
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("failed to encode resume data %s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode resume data %s: %w", key, err)
+	}
+	return nil
+}
+
+// AssessmentFromPhase1 builds a RiskAssessment from Phase 1 metrics alone
+// Confidence reflects data availability: it drops for each metric that could not be
+// computed and for files without resolved history (resolutionConfidence = 0).
+// missingData["cochange"] is set when the co-change query returned nothing for a file
+// that has history.
+func AssessmentFromPhase1(filePath string, result *metrics.Phase1Result, resolutionConfidence float64) (*RiskAssessment, map[string]bool) {
+	missingData := map[string]bool{}
+	confidence := 0.9
+
+	var parts []string
+	if result.Coupling != nil {
+		parts = append(parts, fmt.Sprintf("%d structural dependencies", result.Coupling.Count))
+	} else {
+		confidence -= 0.15
+	}
+	if result.CoChange != nil {
+		parts = append(parts, fmt.Sprintf("max co-change frequency %.0f%%", result.CoChange.MaxFrequency*100))
+	} else {
+		confidence -= 0.15
+		if resolutionConfidence > 0 {
+			missingData["cochange"] = true
+		}
+	}
+	if result.TestRatio != nil {
+		parts = append(parts, fmt.Sprintf("test ratio %.2f", result.TestRatio.Ratio))
+	} else {
+		confidence -= 0.15
+	}
+	if result.Incidents != nil && result.Incidents.TotalIncidents > 0 {
+		parts = append(parts, fmt.Sprintf("%d linked incident(s)", result.Incidents.TotalIncidents))
+	}
+	if resolutionConfidence == 0 {
+		// New file: no history to measure against
+		confidence -= 0.2
+	}
+
+	level := RiskLow
+	var recommendations []string
+	switch result.OverallRisk {
+	case metrics.RiskLevelHigh:
+		level = RiskHigh
+		recommendations = append(recommendations, "Review co-changed files and dependents before committing")
+		if result.TestRatio != nil && result.TestRatio.ShouldEscalate() {
+			recommendations = append(recommendations, "Add tests for the changed code")
+		}
+	case metrics.RiskLevelMedium:
+		level = RiskMedium
+	}
+
+	summary := fmt.Sprintf("Phase 1 %s risk", result.OverallRisk)
+	if len(parts) > 0 {
+		summary += ": " + strings.Join(parts, ", ")
+	}
+
+	return &RiskAssessment{
+		FilePath:        filePath,
+		RiskLevel:       level,
+		Confidence:      confidence,
+		Summary:         summary,
+		Recommendations: recommendations,
+	}, missingData
+}
@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/metrics"
+)
+
+// memoryCheckpoints round-trips investigations through JSON like CheckpointStore
+type memoryCheckpoints struct {
+	saved map[string][]byte
+	saves int
+}
+
+func (m *memoryCheckpoints) Save(ctx context.Context, inv *DirectiveInvestigation) error {
+	data, err := json.Marshal(inv)
+	if err != nil {
+		return err
+	}
+	m.saved[inv.ID] = data
+	m.saves++
+	return nil
+}
+
+func (m *memoryCheckpoints) load(t *testing.T, id string) *DirectiveInvestigation {
+	t.Helper()
+	var inv DirectiveInvestigation
+	if err := json.Unmarshal(m.saved[id], &inv); err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	return &inv
+}
+
+func fixedAssessor(levels map[string]RiskLevel) FileAssessor {
+	return func(ctx context.Context, file string) (*RiskAssessment, map[string]bool, error) {
+		return &RiskAssessment{FilePath: file, RiskLevel: levels[file], Confidence: 0.9, Summary: "test"}, nil, nil
+	}
+}
+
+func TestParseAnswers(t *testing.T) {
+	answers, err := ParseAnswers(strings.NewReader("# first decision\nA\n\n  s  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		choice, ok := answers.Choose(nil, "a", nil)
+		if !ok {
+			break
+		}
+		got = append(got, choice)
+	}
+	if strings.Join(got, ",") != "a,s" {
+		t.Errorf("expected [a s], got %v", got)
+	}
+}
+
+func TestDirectiveSession_PauseAndResume(t *testing.T) {
+	ctx := context.Background()
+	checkpoints := &memoryCheckpoints{saved: map[string][]byte{}}
+	assess := fixedAssessor(map[string]RiskLevel{
+		"a.go": RiskLow,
+		"b.go": RiskHigh,
+		"c.go": RiskCritical,
+	})
+
+	inv := NewDirectiveInvestigation([]string{"a.go", "b.go", "c.go"}, 1)
+	noAnswers, _ := ParseAnswers(strings.NewReader(""))
+	session := &DirectiveSession{Investigation: inv, Assess: assess, Choices: noAnswers, Checkpoints: checkpoints}
+
+	paused, err := session.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !paused {
+		t.Fatal("expected session to pause at the first directive")
+	}
+
+	resumed := checkpoints.load(t, inv.ID)
+	if resumed.Phase != PhaseAwaitingHuman || len(resumed.Decisions) != 0 || !resumed.CanResume {
+		t.Fatalf("unexpected paused state: phase=%s decisions=%d", resumed.Phase, len(resumed.Decisions))
+	}
+
+	// Approve b.go, skip the escalation for c.go
+	answers, _ := ParseAnswers(strings.NewReader("a\ns\n"))
+	session = &DirectiveSession{Investigation: resumed, Assess: assess, Choices: answers, Checkpoints: checkpoints}
+	paused, err = session.Run(ctx)
+	if err != nil {
+		t.Fatalf("resumed Run: %v", err)
+	}
+	if paused {
+		t.Fatal("expected resumed session to complete")
+	}
+
+	final := checkpoints.load(t, inv.ID)
+	if len(final.Decisions) != 2 {
+		t.Fatalf("expected 2 decisions, got %d", len(final.Decisions))
+	}
+	if final.TerminalState == nil || *final.TerminalState != RisksUnresolved {
+		t.Fatalf("expected RISKS_UNRESOLVED, got %v", final.TerminalState)
+	}
+	if final.FinalRiskLevel != string(RiskCritical) || final.CanResume {
+		t.Errorf("unexpected final assessment: %s can_resume=%v", final.FinalRiskLevel, final.CanResume)
+	}
+	if !strings.Contains(final.FinalSummary, "c.go") || strings.Contains(final.FinalSummary, "b.go") {
+		t.Errorf("summary should list only c.go as unresolved: %q", final.FinalSummary)
+	}
+}
+
+func TestDirectiveSession_Abort(t *testing.T) {
+	checkpoints := &memoryCheckpoints{saved: map[string][]byte{}}
+	inv := NewDirectiveInvestigation([]string{"a.go"}, 0)
+	answers, _ := ParseAnswers(strings.NewReader("bogus\nx\n"))
+	session := &DirectiveSession{
+		Investigation: inv,
+		Assess:        fixedAssessor(map[string]RiskLevel{"a.go": RiskHigh}),
+		Choices:       answers,
+		Checkpoints:   checkpoints,
+	}
+
+	if _, err := session.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if inv.TerminalState == nil || *inv.TerminalState != InvestigationAborted {
+		t.Fatalf("expected INVESTIGATION_ABORTED, got %v", inv.TerminalState)
+	}
+	if _, err := session.Run(context.Background()); err == nil {
+		t.Error("expected completed investigation to refuse to run again")
+	}
+}
+
+func TestAssessmentFromPhase1(t *testing.T) {
+	result := &metrics.Phase1Result{
+		OverallRisk: metrics.RiskLevelMedium,
+		Coupling:    &metrics.CouplingResult{Count: 4},
+		TestRatio:   &metrics.TestRatioResult{Ratio: 0.5},
+	}
+
+	assessment, missing := AssessmentFromPhase1("svc.go", result, 1.0)
+	if assessment.RiskLevel != RiskMedium {
+		t.Errorf("expected MEDIUM, got %s", assessment.RiskLevel)
+	}
+	if !missing["cochange"] {
+		t.Error("expected missing co-change to be flagged for a file with history")
+	}
+	if assessment.Confidence >= 0.9 {
+		t.Errorf("expected reduced confidence with a missing metric, got %.2f", assessment.Confidence)
+	}
+
+	if _, missing := AssessmentFromPhase1("new.go", result, 0); missing["cochange"] {
+		t.Error("new files have no co-change history to miss")
+	}
+}
@@ -35,7 +35,7 @@ type UserOption struct {
 }
 
 // DirectiveEvidence represents supporting data for a directive
-// Distinct from Evidence (types.go) used by RiskInvestigator
+// Distinct from Evidence (types.go) used by the tool-calling Investigator
 type DirectiveEvidence struct {
 	Type   string `json:"type"`   // "ownership", "incident", "cochange", etc.
 	Data   string `json:"data"`   // Human-readable data
@@ -4,8 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 
-	"google.golang.org/genai"
+	"github.com/rohankatakam/coderisk/internal/llm"
 )
 
 // HistoryManager intelligently prunes conversation history to stay within token budgets
@@ -26,7 +27,10 @@ func NewHistoryManager() *HistoryManager {
 }
 
 // PruneHistory intelligently reduces history while preserving valuable context
-func (hm *HistoryManager) PruneHistory(history []*genai.Content) []*genai.Content {
+// Messages are compressed rather than dropped so every tool call keeps its result
+// (providers reject orphaned calls). The kickoff prompt and the recent window are kept
+// in full.
+func (hm *HistoryManager) PruneHistory(history []llm.Message) []llm.Message {
 	if len(history) == 0 {
 		return history
 	}
@@ -48,114 +52,107 @@ func (hm *HistoryManager) PruneHistory(history []*genai.Content) []*genai.Conten
 		"tokens_before", currentTokens,
 		"max_tokens", hm.maxTokens)
 
-	// Strategy: Keep recent window + high-value older items, compress low-value items
+	// Strategy: Keep kickoff + recent window + high-value older tool results, compress the rest
+	recentBoundary := hm.recentBoundary(history)
 
-	// Calculate recent window boundary (always keep last N hops = 2N items)
-	recentBoundary := len(history) - (hm.recentWindow * 2)
-	if recentBoundary < 0 {
-		recentBoundary = 0
-	}
-
-	// Split history into: old items (candidates for pruning) + recent items (always keep)
-	oldItems := history[:recentBoundary]
-	recentItems := history[recentBoundary:]
+	result := make([]llm.Message, len(history))
+	copy(result, history)
 
-	// Score and rank old items by importance
-	scoredItems := hm.scoreHistoryItems(oldItems)
-
-	// Build pruned history: keep high-value items, compress low-value items
-	prunedOld := []*genai.Content{}
-	tokensUsed := hm.estimateTokens(recentItems) // Start with recent items token count
+	// Start with everything that is never compressed
+	tokensUsed := hm.estimateTokens(history[:1]) + hm.estimateTokens(history[recentBoundary:])
+	for i := 1; i < recentBoundary; i++ {
+		if history[i].Role != llm.RoleTool {
+			tokensUsed += hm.estimateTokens(history[i : i+1])
+		}
+	}
 
-	for _, scored := range scoredItems {
-		itemTokens := hm.estimateTokens([]*genai.Content{scored.item})
+	// Score and rank old tool results by importance
+	for _, scored := range hm.scoreHistoryItems(history, 1, recentBoundary) {
+		item := history[scored.position]
+		itemTokens := hm.estimateTokens([]llm.Message{item})
 
 		// High-value items: keep in full if budget allows
 		if scored.score >= 0.7 && tokensUsed+itemTokens <= hm.maxTokens {
-			prunedOld = append(prunedOld, scored.item)
 			tokensUsed += itemTokens
 			hm.logger.Debug("keeping high-value item",
 				"score", scored.score,
 				"tool", scored.toolName,
 				"tokens", itemTokens)
-		} else if scored.score >= 0.4 {
-			// Medium-value items: try to compress
-			compressed := hm.compressHistoryItem(scored.item, scored.toolName)
-			compressedTokens := hm.estimateTokens([]*genai.Content{compressed})
-
-			if tokensUsed+compressedTokens <= hm.maxTokens {
-				prunedOld = append(prunedOld, compressed)
-				tokensUsed += compressedTokens
-				hm.logger.Debug("compressed medium-value item",
-					"score", scored.score,
-					"tool", scored.toolName,
-					"tokens_before", itemTokens,
-					"tokens_after", compressedTokens)
-			} else {
-				hm.logger.Debug("skipping medium-value item (budget exceeded)",
-					"score", scored.score,
-					"tool", scored.toolName)
-			}
-		} else {
-			// Low-value items: skip entirely
-			hm.logger.Debug("skipping low-value item",
-				"score", scored.score,
-				"tool", scored.toolName)
+			continue
 		}
-	}
 
-	// Combine: pruned old items + recent items
-	result := append(prunedOld, recentItems...)
-	finalTokens := hm.estimateTokens(result)
+		// Medium-value items: summarize; low-value items (or no budget left): stub
+		compressed := item
+		if scored.score >= 0.4 {
+			compressed.Content = hm.summarizeToolResult(item.Content, scored.toolName)
+		}
+		compressedTokens := hm.estimateTokens([]llm.Message{compressed})
+		if scored.score < 0.4 || tokensUsed+compressedTokens > hm.maxTokens {
+			compressed.Content = fmt.Sprintf("[%s result omitted for context efficiency]", scored.toolName)
+			compressedTokens = hm.estimateTokens([]llm.Message{compressed})
+		}
+		result[scored.position] = compressed
+		tokensUsed += compressedTokens
+		hm.logger.Debug("compressed item",
+			"score", scored.score,
+			"tool", scored.toolName,
+			"tokens_before", itemTokens,
+			"tokens_after", compressedTokens)
+	}
 
 	hm.logger.Info("history pruning complete",
-		"items_before", len(history),
-		"items_after", len(result),
+		"items", len(result),
 		"tokens_before", currentTokens,
-		"tokens_after", finalTokens,
-		"items_kept", len(prunedOld),
-		"items_recent", len(recentItems))
+		"tokens_after", hm.estimateTokens(result),
+		"items_recent", len(history)-recentBoundary)
 
 	return result
 }
 
+// recentBoundary returns the index where the last recentWindow hops begin
+// A hop is an assistant message plus the tool results that answer it.
+func (hm *HistoryManager) recentBoundary(history []llm.Message) int {
+	hops := 0
+	for i := len(history) - 1; i > 0; i-- {
+		if history[i].Role == llm.RoleAssistant {
+			hops++
+			if hops == hm.recentWindow {
+				return i
+			}
+		}
+	}
+	return 1
+}
+
 // scoredHistoryItem pairs a history item with its importance score
 type scoredHistoryItem struct {
-	item     *genai.Content
 	score    float64
 	toolName string
 	position int
 }
 
-// scoreHistoryItems assigns importance scores to history items and sorts by score (descending)
-func (hm *HistoryManager) scoreHistoryItems(items []*genai.Content) []scoredHistoryItem {
-	scored := make([]scoredHistoryItem, 0, len(items))
+// scoreHistoryItems scores the tool results in history[from:to] and sorts by score (descending)
+func (hm *HistoryManager) scoreHistoryItems(history []llm.Message, from, to int) []scoredHistoryItem {
+	scored := []scoredHistoryItem{}
 
-	for i, item := range items {
-		toolName := hm.extractToolName(item)
-		score := hm.scoreHistoryItem(item, toolName, i, len(items))
+	for i := from; i < to; i++ {
+		if history[i].Role != llm.RoleTool {
+			continue
+		}
+		toolName := history[i].ToolName
 		scored = append(scored, scoredHistoryItem{
-			item:     item,
-			score:    score,
+			score:    hm.scoreHistoryItem(history[i], toolName, i-from, to-from),
 			toolName: toolName,
 			position: i,
 		})
 	}
 
-	// Sort by score descending (highest value first)
-	for i := 0; i < len(scored); i++ {
-		for j := i + 1; j < len(scored); j++ {
-			if scored[j].score > scored[i].score {
-				scored[i], scored[j] = scored[j], scored[i]
-			}
-		}
-	}
-
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
 	return scored
 }
 
 // scoreHistoryItem assigns an importance score (0.0-1.0) to a history item
-func (hm *HistoryManager) scoreHistoryItem(item *genai.Content, toolName string, position int, totalItems int) float64 {
+func (hm *HistoryManager) scoreHistoryItem(item llm.Message, toolName string, position int, totalItems int) float64 {
 	// Factor 1: Tool result value (semantic importance)
 	toolScore := hm.getToolValueScore(toolName)
 
@@ -176,9 +173,9 @@ func (hm *HistoryManager) scoreHistoryItem(item *genai.Content, toolName string,
 func (hm *HistoryManager) getToolValueScore(toolName string) float64 {
 	switch toolName {
 	// HIGH VALUE: Critical evidence that drives risk assessment
-	case "get_incidents_with_context":
+	case "get_incidents_with_context", "query_incident_history":
 		return 1.0 // Incident history is most critical
-	case "finish_investigation":
+	case ToolFinishInvestigation:
 		return 1.0 // Final assessment reasoning
 
 	// MEDIUM-HIGH VALUE: Important patterns and ownership
@@ -200,6 +197,8 @@ func (hm *HistoryManager) getToolValueScore(toolName string) float64 {
 		return 0.4 // Simple ownership list
 	case "query_blast_radius":
 		return 0.4 // Simple dependency list
+	case ToolGetCommitPatch:
+		return 0.3 // Large and re-fetchable
 
 	default:
 		return 0.5 // Unknown tools get medium score
@@ -207,18 +206,8 @@ func (hm *HistoryManager) getToolValueScore(toolName string) float64 {
 }
 
 // getDataDensityScore estimates value based on content size
-func (hm *HistoryManager) getDataDensityScore(item *genai.Content) float64 {
-	// Count total characters in all parts
-	totalChars := 0
-	for _, part := range item.Parts {
-		if part.Text != "" {
-			totalChars += len(part.Text)
-		}
-		if part.FunctionResponse != nil {
-			// Estimate function response size
-			totalChars += 500 // Average response size
-		}
-	}
+func (hm *HistoryManager) getDataDensityScore(item llm.Message) float64 {
+	totalChars := len(item.Content)
 
 	// Score based on size buckets
 	if totalChars > 2000 {
@@ -232,84 +221,15 @@ func (hm *HistoryManager) getDataDensityScore(item *genai.Content) float64 {
 	}
 }
 
-// extractToolName identifies which tool was called in this history item
-func (hm *HistoryManager) extractToolName(item *genai.Content) string {
-	if item == nil || len(item.Parts) == 0 {
-		return ""
-	}
-
-	// Check for function responses (user messages with tool results)
-	for _, part := range item.Parts {
-		if part.FunctionResponse != nil {
-			return part.FunctionResponse.Name
-		}
-	}
-
-	// Check for function calls (assistant messages requesting tools)
-	for _, part := range item.Parts {
-		if part.FunctionCall != nil {
-			return part.FunctionCall.Name
-		}
-	}
-
-	return ""
-}
-
-// compressHistoryItem reduces the size of a history item by summarizing tool results
-func (hm *HistoryManager) compressHistoryItem(item *genai.Content, toolName string) *genai.Content {
-	if item == nil {
-		return item
-	}
-
-	compressed := &genai.Content{
-		Role:  item.Role,
-		Parts: make([]*genai.Part, 0, len(item.Parts)),
-	}
-
-	for _, part := range item.Parts {
-		// Compress function responses
-		if part.FunctionResponse != nil {
-			summary := hm.summarizeToolResult(part.FunctionResponse, toolName)
-			compressed.Parts = append(compressed.Parts, &genai.Part{
-				FunctionResponse: &genai.FunctionResponse{
-					Name:     part.FunctionResponse.Name,
-					Response: map[string]any{"result": summary},
-				},
-			})
-		} else if part.Text != "" {
-			// Keep text but truncate if very long
-			text := part.Text
-			if len(text) > 500 {
-				text = text[:500] + "... [truncated for context efficiency]"
-			}
-			compressed.Parts = append(compressed.Parts, &genai.Part{Text: text})
-		} else {
-			// Keep other parts as-is (function calls, etc.)
-			compressed.Parts = append(compressed.Parts, part)
-		}
-	}
-
-	return compressed
-}
-
 // summarizeToolResult creates a concise summary of tool results
-func (hm *HistoryManager) summarizeToolResult(funcResp *genai.FunctionResponse, toolName string) string {
-	if funcResp == nil || funcResp.Response == nil {
-		return "No result"
-	}
-
-	// Extract the result field
-	resultRaw, ok := funcResp.Response["result"]
-	if !ok {
+func (hm *HistoryManager) summarizeToolResult(result string, toolName string) string {
+	if result == "" {
 		return "Empty result"
 	}
 
-	// Try to parse as JSON to extract key stats
-	resultStr := fmt.Sprintf("%v", resultRaw)
-
 	// Attempt to parse structured data and summarize
 	var data interface{}
-	if err := json.Unmarshal([]byte(resultStr), &data); err == nil {
+	if err := json.Unmarshal([]byte(result), &data); err == nil {
 		summary := hm.summarizeStructuredData(data, toolName)
 		if summary != "" {
 			return summary
@@ -317,11 +237,11 @@ func (hm *HistoryManager) summarizeToolResult(funcResp *genai.FunctionResponse,
 	}
 
 	// Fallback: truncate the raw result
-	if len(resultStr) > 200 {
-		return resultStr[:200] + fmt.Sprintf("... [%d more chars, compressed for context efficiency]", len(resultStr)-200)
+	if len(result) > 200 {
+		return result[:200] + fmt.Sprintf("... [%d more chars, compressed for context efficiency]", len(result)-200)
 	}
 
-	return resultStr
+	return result
 }
 
 // summarizeStructuredData creates intelligent summaries of JSON data
@@ -334,20 +254,17 @@ func (hm *HistoryManager) summarizeStructuredData(data interface{}, toolName str
 			return fmt.Sprintf("%s: No results found", toolName)
 		}
 
-		// Try to extract meaningful summary from first item
-		if count > 0 {
-			switch toolName {
-			case "query_recent_commits":
-				return fmt.Sprintf("Found %d recent commit(s)", count)
-			case "query_ownership":
-				return fmt.Sprintf("Found %d developer(s)", count)
-			case "query_cochange_partners":
-				return fmt.Sprintf("Found %d co-change partner(s)", count)
-			case "get_incidents_with_context":
-				return fmt.Sprintf("Found %d incident(s) [HIGH VALUE: details preserved in compression]", count)
-			default:
-				return fmt.Sprintf("Found %d result(s)", count)
-			}
+		switch toolName {
+		case "query_recent_commits":
+			return fmt.Sprintf("Found %d recent commit(s)", count)
+		case "query_ownership":
+			return fmt.Sprintf("Found %d developer(s)", count)
+		case "query_cochange_partners":
+			return fmt.Sprintf("Found %d co-change partner(s)", count)
+		case "get_incidents_with_context":
+			return fmt.Sprintf("Found %d incident(s) [HIGH VALUE: details preserved in compression]", count)
+		default:
+			return fmt.Sprintf("Found %d result(s)", count)
 		}
 	case map[string]interface{}:
 		// Single object result
@@ -359,32 +276,16 @@ func (hm *HistoryManager) summarizeStructuredData(data interface{}, toolName str
 
 // estimateTokens approximates token count for history items
 // Uses rough heuristic: 1 token ≈ 4 characters
-func (hm *HistoryManager) estimateTokens(history []*genai.Content) int {
+func (hm *HistoryManager) estimateTokens(history []llm.Message) int {
 	totalChars := 0
 
 	for _, item := range history {
-		for _, part := range item.Parts {
-			// Count text content
-			if part.Text != "" {
-				totalChars += len(part.Text)
-			}
+		totalChars += len(item.Content)
 
-			// Count function calls
-			if part.FunctionCall != nil {
-				// Name + args JSON
-				totalChars += len(part.FunctionCall.Name) + 100 // Estimate args size
-			}
-
-			// Count function responses
-			if part.FunctionResponse != nil {
-				// Name + response JSON
-				totalChars += len(part.FunctionResponse.Name)
-				if part.FunctionResponse.Response != nil {
-					// Estimate response size by marshaling
-					respJSON, _ := json.Marshal(part.FunctionResponse.Response)
-					totalChars += len(respJSON)
-				}
-			}
+		// Count function calls: name + args JSON
+		for _, call := range item.ToolCalls {
+			args, _ := json.Marshal(call.Arguments)
+			totalChars += len(call.Name) + len(args)
 		}
 	}
 
@@ -7,10 +7,11 @@ import (
 	"github.com/rohankatakam/coderisk/internal/config"
 	"github.com/rohankatakam/coderisk/internal/git"
 	"github.com/rohankatakam/coderisk/internal/graph"
+	"github.com/rohankatakam/coderisk/internal/llm"
 	"github.com/rohankatakam/coderisk/internal/metrics"
 )
 
-// IntegrationExample shows how to use KickoffPromptBuilder + RiskInvestigator
+// IntegrationExample shows how to use KickoffPromptBuilder + Investigator
 // This is the complete flow from file changes to risk assessment
 //
 // Usage in check.go:
@@ -31,7 +32,7 @@ func InvestigateFileChanges(
 	resolver *git.FileResolver,
 	graphClient *graph.Client,
 	pgClient PostgresQueryExecutor,
-	llmClient *LLMClient,
+	llmClient llm.ToolCaller,
 	repoID string,
 	riskConfig config.AdaptiveRiskConfig,
 ) (*RiskAssessment, error) {
@@ -88,7 +89,7 @@ func InvestigateFileChanges(
 
 	// Step 4: Create investigator and run investigation
 	// Note: This example doesn't set up hybrid client - would need database.NewHybridClient in real use
-	investigator := NewRiskInvestigator(llmClient, graphClient, pgClient, nil)
+	investigator := NewInvestigator(llmClient, graphClient, pgClient, nil)
 	assessment, err := investigator.Investigate(ctx, kickoffPrompt)
 	if err != nil {
 		return nil, fmt.Errorf("investigation failed: %w", err)
@@ -104,7 +105,7 @@ func InvestigateSingleFile(
 	resolver *git.FileResolver,
 	graphClient *graph.Client,
 	pgClient PostgresQueryExecutor,
-	llmClient *LLMClient,
+	llmClient llm.ToolCaller,
 	repoID string,
 	riskConfig config.AdaptiveRiskConfig,
 ) (*RiskAssessment, error) {
@@ -0,0 +1,536 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/rohankatakam/coderisk/internal/blastradius"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/llm"
+)
+
+// Tool names shared by every investigator
+const (
+	ToolFinishInvestigation = "finish_investigation"
+	ToolGetCommitPatch      = "get_commit_patch"
+)
+
+// GraphQueryExecutor interface for Neo4j queries
+type GraphQueryExecutor interface {
+	ExecuteQuery(ctx context.Context, query string, params map[string]any) ([]map[string]any, error)
+}
+
+// PostgresQueryExecutor interface for Postgres queries
+type PostgresQueryExecutor interface {
+	// For getting commit patches
+	GetCommitPatch(ctx context.Context, commitSHA string) (string, error)
+}
+
+// InvestigationTools is the provider-agnostic tool set for Phase 2 investigations
+// Tools whose backing client is nil are not offered to the model.
+type InvestigationTools struct {
+	graphClient     GraphQueryExecutor
+	postgresAdapter PostgresQueryExecutor
+	hybridClient    *database.HybridClient
+}
+
+// NewInvestigationTools creates the tool set; postgresAdapter and hybridClient may be nil
+func NewInvestigationTools(graphClient GraphQueryExecutor, postgresAdapter PostgresQueryExecutor, hybridClient *database.HybridClient) *InvestigationTools {
+	return &InvestigationTools{
+		graphClient:     graphClient,
+		postgresAdapter: postgresAdapter,
+		hybridClient:    hybridClient,
+	}
+}
+
+// filePathsSchema is the common single-argument schema for file path queries
+func filePathsSchema(description string, extra map[string]any) map[string]any {
+	properties := map[string]any{
+		"file_paths": map[string]any{
+			"type":        "array",
+			"description": description,
+			"items":       map[string]any{"type": "string"},
+		},
+	}
+	for name, schema := range extra {
+		properties[name] = schema
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"file_paths"},
+	}
+}
+
+func filePathSchema(description string, extra map[string]any) map[string]any {
+	properties := map[string]any{
+		"file_path": map[string]any{"type": "string", "description": description},
+	}
+	for name, schema := range extra {
+		properties[name] = schema
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"file_path"},
+	}
+}
+
+func numberSchema(description string) map[string]any {
+	return map[string]any{"type": "number", "description": description}
+}
+
+// Definitions returns the tools available with the configured clients
+func (t *InvestigationTools) Definitions() []llm.ToolDefinition {
+	defs := []llm.ToolDefinition{
+		{
+			Name:        "query_ownership",
+			Description: "Find if code owner is still active (stale ownership = incident risk). Returns developers who have modified the file.",
+			Parameters:  filePathsSchema("Array of file paths to query (current + historical)", nil),
+		},
+		{
+			Name:        "query_cochange_partners",
+			Description: "Find files that usually change together (incomplete changes = incident risk). Useful for detecting forgotten updates.",
+			Parameters: filePathsSchema("Array of file paths to query", map[string]any{
+				"frequency_threshold": numberSchema("Minimum co-change frequency (0.0-1.0). Default: 0.2"),
+			}),
+		},
+		{
+			Name:        "query_incident_history",
+			Description: "Find issues linked to commits that modified the file, with link confidence and detection method.",
+			Parameters: filePathsSchema("Array of file paths to query", map[string]any{
+				"days_back": numberSchema("How many days back to search. Default: 180"),
+			}),
+		},
+		{
+			Name:        "query_blast_radius",
+			Description: "Find files that depend on the target file directly and transitively (imports, calls, co-change), with decaying weights and the HTTP handlers, CLI commands and exported APIs they reach.",
+			Parameters: filePathSchema("File path to check dependencies for", map[string]any{
+				"max_depth": numberSchema("How many hops to follow. Default: 3"),
+			}),
+		},
+		{
+			Name:        "query_recent_commits",
+			Description: "Get recent commits that modified the file. Shows recent activity and ownership trends.",
+			Parameters: filePathsSchema("Array of file paths to query", map[string]any{
+				"limit": numberSchema("Number of commits to return. Default: 5"),
+			}),
+		},
+	}
+
+	if t.hybridClient != nil {
+		defs = append(defs,
+			llm.ToolDefinition{
+				Name:        "get_incidents_with_context",
+				Description: "Get full incident history with issue details, link quality, and author roles. Returns comprehensive incident data including titles, bodies, confidence scores, and whether reporters were team members or external users.",
+				Parameters: filePathsSchema("Array of file paths to query", map[string]any{
+					"days_back": numberSchema("How many days back to search. Default: 180"),
+				}),
+			},
+			llm.ToolDefinition{
+				Name:        "get_ownership_timeline",
+				Description: "Get developer ownership history with activity status. Shows who owns the code, when they last contributed, and whether they're still active.",
+				Parameters:  filePathsSchema("Array of file paths to query", nil),
+			},
+			llm.ToolDefinition{
+				Name:        "get_cochange_with_explanations",
+				Description: "Get co-change partners with commit message context showing WHY files changed together.",
+				Parameters: filePathsSchema("Array of file paths to query", map[string]any{
+					"threshold": numberSchema("Minimum co-change frequency (0.0-1.0). Default: 0.2"),
+				}),
+			},
+			llm.ToolDefinition{
+				Name:        "get_blast_radius_analysis",
+				Description: "Get downstream impact analysis with dependency counts and risk assessment.",
+				Parameters:  filePathSchema("File path to analyze blast radius for", nil),
+			},
+		)
+	}
+
+	if t.postgresAdapter != nil {
+		defs = append(defs, llm.ToolDefinition{
+			Name:        ToolGetCommitPatch,
+			Description: "Retrieve full code diff/patch for a specific commit. Use when you need to see actual code changes. Warning: can be large, only call when necessary.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"commit_sha": map[string]any{"type": "string", "description": "Git commit SHA to retrieve patch for"},
+				},
+				"required": []string{"commit_sha"},
+			},
+		})
+	}
+
+	return append(defs, llm.ToolDefinition{
+		Name:        ToolFinishInvestigation,
+		Description: "Complete the investigation and return final risk assessment with incident-focused reasoning. MUST be called when you have gathered sufficient evidence.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"risk_level": map[string]any{
+					"type":        "string",
+					"description": "Risk level: LOW, MEDIUM, HIGH, or CRITICAL",
+					"enum":        []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"},
+				},
+				"confidence": numberSchema("Confidence score 0.0-1.0"),
+				"reasoning": map[string]any{
+					"type":        "string",
+					"description": "Detailed explanation of the risk assessment focusing on incident risk",
+				},
+				"recommendations": map[string]any{
+					"type":        "array",
+					"description": "List of specific actions to mitigate risk",
+					"items":       map[string]any{"type": "string"},
+				},
+			},
+			"required": []string{"risk_level", "confidence", "reasoning"},
+		},
+	})
+}
+
+// Execute runs a data tool (everything except finish_investigation)
+func (t *InvestigationTools) Execute(ctx context.Context, toolName string, args map[string]any) (any, error) {
+	slog.Info("executing tool", "tool", toolName, "args", args)
+
+	if err := t.checkAvailable(toolName); err != nil {
+		return nil, err
+	}
+
+	switch toolName {
+	case "query_ownership":
+		return t.queryOwnership(ctx, args)
+	case "query_cochange_partners":
+		return t.queryCoChangePartners(ctx, args)
+	case "query_incident_history":
+		return t.queryIncidentHistory(ctx, args)
+	case "query_blast_radius":
+		return t.queryBlastRadius(ctx, args)
+	case "query_recent_commits":
+		return t.queryRecentCommits(ctx, args)
+	case "get_incidents_with_context":
+		return t.getIncidentsWithContext(ctx, args)
+	case "get_ownership_timeline":
+		return t.getOwnershipTimeline(ctx, args)
+	case "get_cochange_with_explanations":
+		return t.getCoChangeWithExplanations(ctx, args)
+	case "get_blast_radius_analysis":
+		return t.getBlastRadiusAnalysis(ctx, args)
+	case ToolGetCommitPatch:
+		return t.getCommitPatch(ctx, args)
+	default:
+		return nil, fmt.Errorf("unknown tool: %s", toolName)
+	}
+}
+
+// checkAvailable rejects calls to tools whose backing client is not configured
+// (a model may still name a tool it was not offered)
+func (t *InvestigationTools) checkAvailable(toolName string) error {
+	switch toolName {
+	case "get_incidents_with_context", "get_ownership_timeline", "get_cochange_with_explanations", "get_blast_radius_analysis":
+		if t.hybridClient == nil {
+			return fmt.Errorf("%s unavailable: no hybrid client configured", toolName)
+		}
+	case ToolGetCommitPatch:
+		if t.postgresAdapter == nil {
+			return fmt.Errorf("%s unavailable: no postgres adapter configured", toolName)
+		}
+	}
+	return nil
+}
+
+// formatToolResult formats tool execution result for LLM
+func formatToolResult(result any, err error) string {
+	if err != nil {
+		return fmt.Sprintf("ERROR: %v", err)
+	}
+
+	// Convert to JSON
+	jsonBytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("ERROR: Failed to format result: %v", err)
+	}
+
+	return string(jsonBytes)
+}
+
+// Tool implementations
+
+func (t *InvestigationTools) queryOwnership(ctx context.Context, args map[string]any) (any, error) {
+	filePaths := extractStringArray(args, "file_paths")
+	if len(filePaths) == 0 {
+		return nil, fmt.Errorf("file_paths is required")
+	}
+
+	query := `
+		MATCH (d:Developer)-[:AUTHORED]->(c:Commit)-[:MODIFIED]->(f:File)
+		WHERE f.path IN $paths
+		WITH d, COUNT(c) as commit_count
+		ORDER BY commit_count DESC
+		LIMIT 5
+		RETURN d.email as developer, commit_count
+	`
+
+	results, err := t.graphClient.ExecuteQuery(ctx, query, map[string]any{
+		"paths": filePaths,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return results, nil
+}
+
+func (t *InvestigationTools) queryCoChangePartners(ctx context.Context, args map[string]any) (any, error) {
+	filePaths := extractStringArray(args, "file_paths")
+	slog.Info("query_cochange_partners called", "file_paths", filePaths)
+
+	threshold := 0.2 // 20% co-change frequency threshold (lowered from 0.5 which was too strict)
+	if v, ok := args["frequency_threshold"].(float64); ok {
+		threshold = v
+	}
+
+	query := `
+		MATCH (f1:File)<-[:MODIFIED]-(c:Commit)-[:MODIFIED]->(f2:File)
+		WHERE f1.path IN $paths AND f1.path <> f2.path
+		WITH f1.path as file1, f2.path as file2, COUNT(c) as cochange_count
+		WITH file1, file2, cochange_count
+		MATCH (f1:File {path: file1})
+		WITH file2 as partner_file, cochange_count, COUNT { (f1)<-[:MODIFIED]-() } as f1_total
+		WITH partner_file, cochange_count, toFloat(cochange_count) / toFloat(f1_total) as frequency
+		WHERE frequency >= $threshold
+		RETURN partner_file, frequency
+		ORDER BY frequency DESC
+		LIMIT 10
+	`
+
+	slog.Info("calling graphClient.ExecuteQuery for co-change", "threshold", threshold)
+	results, err := t.graphClient.ExecuteQuery(ctx, query, map[string]any{
+		"paths":     filePaths,
+		"threshold": threshold,
+	})
+	if err != nil {
+		slog.Error("query_cochange_partners failed", "error", err)
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	slog.Info("query_cochange_partners result", "file_paths", filePaths, "result_count", len(results))
+	if len(results) > 0 {
+		slog.Info("sample result", "partner_file", results[0]["partner_file"], "frequency", results[0]["frequency"])
+	}
+	return results, nil
+}
+
+func (t *InvestigationTools) queryIncidentHistory(ctx context.Context, args map[string]any) (any, error) {
+	filePaths := extractStringArray(args, "file_paths")
+	daysBack := 180
+	if d, ok := args["days_back"].(float64); ok {
+		daysBack = int(d)
+	}
+
+	query := `
+		MATCH (issue:Issue)-[rel]->(pr:PR)<-[:IN_PR]-(c:Commit)-[:MODIFIED]->(f:File)
+		WHERE f.path IN $paths
+		  AND (type(rel) = 'FIXED_BY' OR type(rel) = 'ASSOCIATED_WITH')
+		  AND issue.created_at > datetime() - duration({days: $days_back})
+		RETURN issue.number as incident_number,
+		       issue.title as title,
+		       issue.body as body,
+		       issue.labels as labels,
+		       issue.created_at as created_at,
+		       issue.closed_at as closed_at,
+		       c.sha as fix_commit,
+		       pr.number as pr_number,
+		       type(rel) as link_type,
+		       rel.confidence as confidence,
+		       rel.detection_method as detection_method,
+		       rel.evidence_sources as evidence
+		ORDER BY rel.confidence DESC, issue.created_at DESC
+		LIMIT 10
+	`
+
+	results, err := t.graphClient.ExecuteQuery(ctx, query, map[string]any{
+		"paths":     filePaths,
+		"days_back": daysBack,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return results, nil
+}
+
+func (t *InvestigationTools) queryBlastRadius(ctx context.Context, args map[string]any) (any, error) {
+	filePath, ok := args["file_path"].(string)
+	if !ok {
+		return nil, fmt.Errorf("file_path is required")
+	}
+
+	opts := blastradius.DefaultOptions()
+	if d, ok := args["max_depth"].(float64); ok && d > 0 {
+		opts.MaxDepth = int(d)
+	}
+
+	result, err := blastradius.NewEngine(t.graphClient, nil, "", opts).Compute(ctx, 0, "", []string{filePath})
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return result, nil
+}
+
+func (t *InvestigationTools) queryRecentCommits(ctx context.Context, args map[string]any) (any, error) {
+	filePaths := extractStringArray(args, "file_paths")
+	limit := 5
+	if l, ok := args["limit"].(float64); ok {
+		limit = int(l)
+	}
+
+	query := `
+		MATCH (c:Commit)-[:MODIFIED]->(f:File)
+		WHERE f.path IN $paths
+		MATCH (d:Developer)-[:AUTHORED]->(c)
+		RETURN c.sha as commit_sha,
+		       c.message as message,
+		       c.timestamp as timestamp,
+		       d.email as author
+		ORDER BY c.timestamp DESC
+		LIMIT $limit
+	`
+
+	results, err := t.graphClient.ExecuteQuery(ctx, query, map[string]any{
+		"paths": filePaths,
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return results, nil
+}
+
+func (t *InvestigationTools) getCommitPatch(ctx context.Context, args map[string]any) (any, error) {
+	commitSHA, ok := args["commit_sha"].(string)
+	if !ok || commitSHA == "" {
+		return nil, fmt.Errorf("commit_sha is required")
+	}
+
+	patch, err := t.postgresAdapter.GetCommitPatch(ctx, commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve patch: %w", err)
+	}
+
+	return map[string]any{
+		"commit_sha": commitSHA,
+		"patch":      patch,
+	}, nil
+}
+
+// Hybrid query tool handlers
+
+func (t *InvestigationTools) getIncidentsWithContext(ctx context.Context, args map[string]any) (any, error) {
+	filePaths := extractStringArray(args, "file_paths")
+	slog.Info("get_incidents_with_context called", "file_paths", filePaths)
+
+	if len(filePaths) == 0 {
+		slog.Warn("get_incidents_with_context: no file paths provided")
+		return nil, fmt.Errorf("file_paths is required")
+	}
+
+	daysBack := 180
+	if d, ok := args["days_back"].(float64); ok {
+		daysBack = int(d)
+	}
+
+	slog.Info("calling hybridClient.GetIncidentHistoryForFiles", "days_back", daysBack)
+	incidents, err := t.hybridClient.GetIncidentHistoryForFiles(ctx, filePaths, daysBack)
+	if err != nil {
+		slog.Error("get_incidents_with_context failed", "error", err)
+		return nil, fmt.Errorf("failed to get incidents: %w", err)
+	}
+
+	// Log incident count for debugging
+	slog.Info("get_incidents_with_context result", "file_paths", filePaths, "incident_count", len(incidents), "days_back", daysBack)
+	if len(incidents) > 0 {
+		slog.Info("sample incident", "issue_number", incidents[0].IssueNumber, "issue_title", incidents[0].IssueTitle)
+	}
+
+	return incidents, nil
+}
+
+func (t *InvestigationTools) getOwnershipTimeline(ctx context.Context, args map[string]any) (any, error) {
+	filePaths := extractStringArray(args, "file_paths")
+	if len(filePaths) == 0 {
+		return nil, fmt.Errorf("file_paths is required")
+	}
+
+	ownership, err := t.hybridClient.GetOwnershipHistoryForFiles(ctx, filePaths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ownership: %w", err)
+	}
+
+	return ownership, nil
+}
+
+func (t *InvestigationTools) getCoChangeWithExplanations(ctx context.Context, args map[string]any) (any, error) {
+	filePaths := extractStringArray(args, "file_paths")
+	slog.Info("get_cochange_with_explanations called", "file_paths", filePaths)
+
+	if len(filePaths) == 0 {
+		slog.Warn("get_cochange_with_explanations: no file paths provided")
+		return nil, fmt.Errorf("file_paths is required")
+	}
+
+	threshold := 0.2 // 20% co-change frequency threshold (lowered from 0.5 which was too strict)
+	if v, ok := args["threshold"].(float64); ok {
+		threshold = v
+	}
+
+	slog.Info("calling hybridClient.GetCoChangePartnersWithContext", "threshold", threshold)
+	partners, err := t.hybridClient.GetCoChangePartnersWithContext(ctx, filePaths, threshold)
+	if err != nil {
+		slog.Error("get_cochange_with_explanations failed", "error", err)
+		return nil, fmt.Errorf("failed to get co-change partners: %w", err)
+	}
+
+	slog.Info("get_cochange_with_explanations result", "file_paths", filePaths, "partner_count", len(partners))
+	return partners, nil
+}
+
+func (t *InvestigationTools) getBlastRadiusAnalysis(ctx context.Context, args map[string]any) (any, error) {
+	filePath, ok := args["file_path"].(string)
+	if !ok || filePath == "" {
+		return nil, fmt.Errorf("file_path is required")
+	}
+
+	blastRadius, err := t.hybridClient.GetBlastRadiusWithIncidents(ctx, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blast radius: %w", err)
+	}
+
+	return blastRadius, nil
+}
+
+// Helper functions
+
+func extractStringArray(args map[string]any, key string) []string {
+	val, ok := args[key]
+	if !ok {
+		return nil
+	}
+
+	arr, ok := val.([]any)
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(arr))
+	for _, item := range arr {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+
+	return result
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
-
-	openai "github.com/sashabaranov/go-openai"
 )
 
 // MockGraphClient for testing
@@ -32,80 +30,48 @@ func (m *MockPGClient) GetCommitPatch(ctx context.Context, commitSHA string) (st
 	return "mock patch content", nil
 }
 
-// MockLLMClientForRiskInvestigator for testing
-type MockLLMClientForRiskInvestigator struct {
-	Responses []openai.ChatCompletionResponse
-	CallCount int
-}
-
-func (m *MockLLMClientForRiskInvestigator) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
-	if m.CallCount >= len(m.Responses) {
-		// Return finish response if out of pre-configured responses
-		return openai.ChatCompletionResponse{
-			Choices: []openai.ChatCompletionChoice{
-				{
-					Message: openai.ChatCompletionMessage{
-						Role: openai.ChatMessageRoleAssistant,
-						ToolCalls: []openai.ToolCall{
-							{
-								ID:   "call_finish",
-								Type: openai.ToolTypeFunction,
-								Function: openai.FunctionCall{
-									Name:      "finish_investigation",
-									Arguments: `{"risk_level": "LOW", "confidence": 0.8, "reasoning": "No significant risks found", "recommendations": ["Continue with commit"]}`,
-								},
-							},
-						},
-					},
-					FinishReason: openai.FinishReasonToolCalls,
-				},
-			},
-			Usage: openai.Usage{TotalTokens: 100},
-		}, nil
-	}
-
-	resp := m.Responses[m.CallCount]
-	m.CallCount++
-	return resp, nil
-}
-
-func TestRiskInvestigator_GetToolDefinitions(t *testing.T) {
-	mockGraph := &MockGraphClient{}
-	mockPG := &MockPGClient{}
-	llmClient := &LLMClient{} // Just for type
-
-	investigator := NewRiskInvestigator(llmClient, mockGraph, mockPG)
-	tools := investigator.getToolDefinitions()
-
-	// Verify we have all expected tools
-	expectedTools := map[string]bool{
-		"query_ownership":        false,
-		"query_cochange_partners": false,
-		"query_incident_history":  false,
-		"query_blast_radius":      false,
-		"query_recent_commits":    false,
-		"finish_investigation":    false,
-	}
+func TestInvestigationTools_Definitions(t *testing.T) {
+	tools := NewInvestigationTools(&MockGraphClient{}, &MockPGClient{}, nil)
 
-	for _, tool := range tools {
-		if tool.Function != nil {
-			expectedTools[tool.Function.Name] = true
+	found := map[string]bool{}
+	for _, def := range tools.Definitions() {
+		found[def.Name] = true
+		if def.Parameters["type"] != "object" {
+			t.Errorf("%s: parameters must be a JSON schema object", def.Name)
 		}
 	}
 
-	for name, found := range expectedTools {
-		if !found {
+	for _, name := range []string{
+		"query_ownership",
+		"query_cochange_partners",
+		"query_incident_history",
+		"query_blast_radius",
+		"query_recent_commits",
+		"get_commit_patch",
+		"finish_investigation",
+	} {
+		if !found[name] {
 			t.Errorf("Missing tool: %s", name)
 		}
 	}
 
-	// Verify tool count
-	if len(tools) != 6 {
-		t.Errorf("Expected 6 tools, got %d", len(tools))
+	// Hybrid tools are only offered with a hybrid client
+	if found["get_incidents_with_context"] {
+		t.Error("get_incidents_with_context offered without a hybrid client")
+	}
+	if _, err := tools.Execute(context.Background(), "get_incidents_with_context", map[string]any{}); err == nil {
+		t.Error("expected error calling a hybrid tool without a hybrid client")
+	}
+
+	// No postgres adapter: no patch tool
+	for _, def := range NewInvestigationTools(&MockGraphClient{}, nil, nil).Definitions() {
+		if def.Name == "get_commit_patch" {
+			t.Error("get_commit_patch offered without a postgres adapter")
+		}
 	}
 }
 
-func TestRiskInvestigator_QueryOwnership(t *testing.T) {
+func TestInvestigationTools_QueryOwnership(t *testing.T) {
 	mockGraph := &MockGraphClient{
 		QueryResults: map[string][]map[string]any{
 			"ownership": {
@@ -120,16 +86,13 @@ func TestRiskInvestigator_QueryOwnership(t *testing.T) {
 			},
 		},
 	}
-	mockPG := &MockPGClient{}
-	llmClient := &LLMClient{}
-
-	investigator := NewRiskInvestigator(llmClient, mockGraph, mockPG)
+	tools := NewInvestigationTools(mockGraph, &MockPGClient{}, nil)
 
 	args := map[string]any{
 		"file_paths": []any{"src/auth/login.py", "auth/login.py"},
 	}
 
-	result, err := investigator.queryOwnership(context.Background(), args)
+	result, err := tools.Execute(context.Background(), "query_ownership", args)
 	if err != nil {
 		t.Fatalf("queryOwnership failed: %v", err)
 	}
@@ -149,13 +112,7 @@ func TestRiskInvestigator_QueryOwnership(t *testing.T) {
 	}
 }
 
-func TestRiskInvestigator_HandleFinishInvestigation(t *testing.T) {
-	mockGraph := &MockGraphClient{}
-	mockPG := &MockPGClient{}
-	llmClient := &LLMClient{}
-
-	investigator := NewRiskInvestigator(llmClient, mockGraph, mockPG)
-
+func TestParseFinishInvestigation(t *testing.T) {
 	argsJSON := `{
 		"risk_level": "HIGH",
 		"confidence": 0.85,
@@ -163,10 +120,9 @@ func TestRiskInvestigator_HandleFinishInvestigation(t *testing.T) {
 		"recommendations": ["Review with security team", "Add integration tests"]
 	}`
 
-	investigation := &Investigation{}
-	assessment, err := investigator.handleFinishInvestigation(argsJSON, investigation)
+	assessment, err := parseFinishInvestigation([]byte(argsJSON))
 	if err != nil {
-		t.Fatalf("handleFinishInvestigation failed: %v", err)
+		t.Fatalf("parseFinishInvestigation failed: %v", err)
 	}
 
 	if assessment.RiskLevel != RiskHigh {
@@ -186,7 +142,7 @@ func TestRiskInvestigator_HandleFinishInvestigation(t *testing.T) {
 	}
 }
 
-func TestRiskInvestigator_ExtractStringArray(t *testing.T) {
+func TestExtractStringArray(t *testing.T) {
 	tests := []struct {
 		name     string
 		args     map[string]any
@@ -235,19 +191,13 @@ func TestRiskInvestigator_ExtractStringArray(t *testing.T) {
 	}
 }
 
-func TestRiskInvestigator_FormatToolResult(t *testing.T) {
-	mockGraph := &MockGraphClient{}
-	mockPG := &MockPGClient{}
-	llmClient := &LLMClient{}
-
-	investigator := NewRiskInvestigator(llmClient, mockGraph, mockPG)
-
+func TestFormatToolResult(t *testing.T) {
 	// Test successful result
 	result := []map[string]any{
 		{"file": "test.py", "count": float64(5)},
 	}
 
-	formatted := investigator.formatToolResult(result, nil)
+	formatted := formatToolResult(result, nil)
 
 	// Should be valid JSON
 	var parsed any
@@ -256,24 +206,18 @@ func TestRiskInvestigator_FormatToolResult(t *testing.T) {
 	}
 
 	// Test error result
-	formatted = investigator.formatToolResult(nil, fmt.Errorf("query failed"))
+	formatted = formatToolResult(nil, fmt.Errorf("query failed"))
 	if formatted != "ERROR: query failed" {
 		t.Errorf("Expected error format, got: %s", formatted)
 	}
 }
 
-func TestRiskInvestigator_EmergencyAssessment(t *testing.T) {
-	mockGraph := &MockGraphClient{}
-	mockPG := &MockPGClient{}
-	llmClient := &LLMClient{}
-
-	investigator := NewRiskInvestigator(llmClient, mockGraph, mockPG)
-
+func TestEmergencyAssessment(t *testing.T) {
 	investigation := &Investigation{
 		TotalTokens: 500,
 	}
 
-	assessment := investigator.emergencyAssessment(investigation, "Test failure")
+	assessment := emergencyAssessment(investigation, "Test failure")
 
 	// Should return MEDIUM risk (conservative)
 	if assessment.RiskLevel != RiskMedium {
@@ -296,7 +240,7 @@ func TestRiskInvestigator_EmergencyAssessment(t *testing.T) {
 	}
 }
 
-func TestRiskInvestigator_RiskLevelToScore(t *testing.T) {
+func TestRiskLevelToScore(t *testing.T) {
 	tests := []struct {
 		level    RiskLevel
 		expected float64
@@ -0,0 +1,240 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/llm"
+)
+
+// Investigator runs a Phase 2 investigation from a kickoff prompt
+// Reference: dev_docs/mvp/AGENT_KICKOFF_PROMPT_DESIGN.md
+type Investigator interface {
+	Investigate(ctx context.Context, kickoffPrompt string) (*RiskAssessment, error)
+}
+
+// InvestigationLimits bounds an investigation; zero values disable a limit
+type InvestigationLimits struct {
+	MaxHops   int // Model turns before giving up
+	MaxTokens int // Total tokens across all turns
+}
+
+// DefaultInvestigationLimits returns the production limits
+func DefaultInvestigationLimits() InvestigationLimits {
+	return InvestigationLimits{
+		MaxHops:   30, // High safety limit - agent should call finish_investigation when done (12 Factor Agents principle)
+		MaxTokens: 200000,
+	}
+}
+
+// ToolLoopInvestigator is the provider-agnostic function-calling investigator
+// Any llm.ToolCaller works: llm.Client (OpenAI, Gemini or a local model),
+// llm.GeminiClient directly, or llm.ScriptedToolCaller in tests.
+type ToolLoopInvestigator struct {
+	caller         llm.ToolCaller
+	tools          *InvestigationTools
+	limits         InvestigationLimits
+	historyManager *HistoryManager
+}
+
+var _ Investigator = (*ToolLoopInvestigator)(nil)
+
+// NewToolLoopInvestigator creates an investigator over the given tool set
+func NewToolLoopInvestigator(caller llm.ToolCaller, tools *InvestigationTools, limits InvestigationLimits) *ToolLoopInvestigator {
+	return &ToolLoopInvestigator{
+		caller:         caller,
+		tools:          tools,
+		limits:         limits,
+		historyManager: NewHistoryManager(),
+	}
+}
+
+// NewInvestigator wires the standard tool set with default limits
+func NewInvestigator(
+	caller llm.ToolCaller,
+	graphClient GraphQueryExecutor,
+	postgresAdapter PostgresQueryExecutor,
+	hybridClient *database.HybridClient,
+) *ToolLoopInvestigator {
+	return NewToolLoopInvestigator(caller, NewInvestigationTools(graphClient, postgresAdapter, hybridClient), DefaultInvestigationLimits())
+}
+
+// Investigate performs agent-based risk investigation
+// Provider errors are returned; every other stop (hop or token budget exhausted,
+// unparseable final answer) yields the conservative emergency assessment.
+func (inv *ToolLoopInvestigator) Investigate(ctx context.Context, kickoffPrompt string) (*RiskAssessment, error) {
+	messages := []llm.Message{{Role: llm.RoleUser, Content: kickoffPrompt}}
+	definitions := inv.tools.Definitions()
+
+	var investigation Investigation
+	investigation.Request = InvestigationRequest{
+		StartedAt: time.Now(),
+	}
+
+	for hop := 1; inv.limits.MaxHops <= 0 || hop <= inv.limits.MaxHops; hop++ {
+		if inv.limits.MaxTokens > 0 && investigation.TotalTokens >= inv.limits.MaxTokens {
+			investigation.CompletedAt = time.Now()
+			return emergencyAssessment(&investigation,
+				fmt.Sprintf("Investigation exceeded token budget (%d/%d)", investigation.TotalTokens, inv.limits.MaxTokens)), nil
+		}
+
+		hopStart := time.Now()
+		resp, err := inv.caller.CompleteWithToolCalls(ctx, messages, definitions)
+		if err != nil {
+			return nil, fmt.Errorf("LLM request failed at hop %d: %w", hop, err)
+		}
+
+		tokens := resp.TokensUsed
+		if tokens == 0 {
+			// Not every provider reports usage; estimate from the conversation
+			tokens = inv.historyManager.estimateTokens(messages) + len(resp.Content)/4
+		}
+		investigation.TotalTokens += tokens
+
+		hopResult := HopResult{
+			HopNumber:  hop,
+			Response:   resp.Content,
+			TokensUsed: tokens,
+			Duration:   time.Since(hopStart),
+		}
+
+		// No tool calls: the agent answered in text
+		if len(resp.ToolCalls) == 0 {
+			investigation.Hops = append(investigation.Hops, hopResult)
+			investigation.CompletedAt = time.Now()
+			assessment, err := parseFinishInvestigation([]byte(resp.Content))
+			if err != nil {
+				return emergencyAssessment(&investigation, fmt.Sprintf("Agent stopped without valid assessment: %v", err)), nil
+			}
+			investigation.StoppingReason = "Agent completed investigation"
+			assessment.Investigation = &investigation
+			return assessment, nil
+		}
+
+		// The assistant turn must precede its tool results
+		messages = append(messages, llm.Message{Role: llm.RoleAssistant, Content: resp.Content, ToolCalls: resp.ToolCalls})
+
+		for _, call := range resp.ToolCalls {
+			if call.Name == ToolFinishInvestigation {
+				investigation.Hops = append(investigation.Hops, hopResult)
+				investigation.CompletedAt = time.Now()
+				argsJSON, _ := json.Marshal(call.Arguments)
+				assessment, err := parseFinishInvestigation(argsJSON)
+				if err != nil {
+					return emergencyAssessment(&investigation, fmt.Sprintf("Failed to parse finish_investigation: %v", err)), nil
+				}
+				investigation.StoppingReason = "Agent called finish_investigation"
+				assessment.Investigation = &investigation
+				return assessment, nil
+			}
+
+			result, err := inv.tools.Execute(ctx, call.Name, call.Arguments)
+
+			// Store tool result in hop for transparency
+			toolResult := ToolResult{ToolName: call.Name, Args: call.Arguments, Result: result}
+			if err != nil {
+				toolResult.Error = err.Error()
+			}
+			hopResult.ToolResults = append(hopResult.ToolResults, toolResult)
+			hopResult.NodesVisited = append(hopResult.NodesVisited, call.Name)
+
+			messages = append(messages, llm.Message{
+				Role:       llm.RoleTool,
+				Content:    formatToolResult(result, err),
+				ToolCallID: call.ID,
+				ToolName:   call.Name,
+			})
+		}
+
+		// Prune history to stay within token budget
+		messages = inv.historyManager.PruneHistory(messages)
+		investigation.Hops = append(investigation.Hops, hopResult)
+	}
+
+	investigation.CompletedAt = time.Now()
+	return emergencyAssessment(&investigation, fmt.Sprintf("Investigation exceeded maximum hops (%d)", inv.limits.MaxHops)), nil
+}
+
+// parseFinishInvestigation builds the assessment from finish_investigation arguments
+// (or a JSON text answer of the same shape). "summary" is accepted for "reasoning".
+func parseFinishInvestigation(data []byte) (*RiskAssessment, error) {
+	var args struct {
+		RiskLevel       string   `json:"risk_level"`
+		Confidence      float64  `json:"confidence"`
+		Reasoning       string   `json:"reasoning"`
+		Summary         string   `json:"summary"`
+		Recommendations []string `json:"recommendations"`
+	}
+
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, fmt.Errorf("failed to parse finish_investigation args: %w", err)
+	}
+	if args.RiskLevel == "" {
+		return nil, fmt.Errorf("finish_investigation args missing risk_level")
+	}
+
+	// Parse risk level
+	var riskLevel RiskLevel
+	switch args.RiskLevel {
+	case "CRITICAL":
+		riskLevel = RiskCritical
+	case "HIGH":
+		riskLevel = RiskHigh
+	case "MEDIUM":
+		riskLevel = RiskMedium
+	case "LOW":
+		riskLevel = RiskLow
+	default:
+		riskLevel = RiskMedium
+	}
+
+	summary := args.Reasoning
+	if summary == "" {
+		summary = args.Summary
+	}
+
+	return &RiskAssessment{
+		RiskLevel:       riskLevel,
+		RiskScore:       riskLevelToScore(riskLevel),
+		Confidence:      args.Confidence,
+		Summary:         summary,
+		Recommendations: args.Recommendations,
+	}, nil
+}
+
+// emergencyAssessment returns a conservative assessment when agent fails
+func emergencyAssessment(investigation *Investigation, reason string) *RiskAssessment {
+	investigation.StoppingReason = reason
+
+	return &RiskAssessment{
+		RiskLevel:  RiskMedium,
+		RiskScore:  0.5,
+		Confidence: 0.3,
+		Summary:    fmt.Sprintf("Investigation incomplete: %s. Using conservative MEDIUM risk assessment.", reason),
+		Recommendations: []string{
+			"Review changes manually due to incomplete investigation",
+			"Contact file owner for additional review",
+			"Verify test coverage before committing",
+		},
+		Investigation: investigation,
+	}
+}
+
+// riskLevelToScore maps a risk level to the score reported with an assessment
+func riskLevelToScore(level RiskLevel) float64 {
+	switch level {
+	case RiskCritical:
+		return 0.9
+	case RiskHigh:
+		return 0.7
+	case RiskMedium:
+		return 0.5
+	case RiskLow:
+		return 0.3
+	default:
+		return 0.5
+	}
+}
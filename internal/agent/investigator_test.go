@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/llm"
+)
+
+func newScriptedInvestigator(limits InvestigationLimits, responses ...llm.ToolResponse) (*ToolLoopInvestigator, *llm.ScriptedToolCaller) {
+	caller := llm.NewScriptedToolCaller(responses...)
+	graph := &MockGraphClient{QueryResults: map[string][]map[string]any{
+		"ownership": {{"developer": "alice@example.com", "commit_count": float64(15)}},
+	}}
+	return NewToolLoopInvestigator(caller, NewInvestigationTools(graph, &MockPGClient{}, nil), limits), caller
+}
+
+func TestToolLoopInvestigator_Finish(t *testing.T) {
+	inv, caller := newScriptedInvestigator(DefaultInvestigationLimits(),
+		llm.ToolResponse{
+			ToolCalls: []llm.ToolCall{
+				llm.ScriptedCall("query_ownership", map[string]any{"file_paths": []any{"auth/login.py"}}),
+				llm.ScriptedCall("get_commit_patch", map[string]any{"commit_sha": "abc123"}),
+			},
+			TokensUsed: 100,
+		},
+		llm.ToolResponse{
+			ToolCalls: []llm.ToolCall{llm.ScriptedCall("finish_investigation", map[string]any{
+				"risk_level":      "HIGH",
+				"confidence":      0.8,
+				"reasoning":       "Owner inactive",
+				"recommendations": []any{"Ask bob to review"},
+			})},
+			TokensUsed: 50,
+		},
+	)
+
+	assessment, err := inv.Investigate(context.Background(), "kickoff")
+	if err != nil {
+		t.Fatalf("Investigate: %v", err)
+	}
+	if assessment.RiskLevel != RiskHigh || assessment.Summary != "Owner inactive" || len(assessment.Recommendations) != 1 {
+		t.Errorf("unexpected assessment: %+v", assessment)
+	}
+
+	investigation := assessment.Investigation
+	if investigation.StoppingReason != "Agent called finish_investigation" || investigation.TotalTokens != 150 {
+		t.Errorf("unexpected investigation: reason=%q tokens=%d", investigation.StoppingReason, investigation.TotalTokens)
+	}
+	if len(investigation.Hops) != 2 || len(investigation.Hops[0].ToolResults) != 2 {
+		t.Fatalf("expected 2 hops with 2 tool results on the first, got %+v", investigation.Hops)
+	}
+
+	// Second call sees kickoff, the assistant turn and both tool results, in order
+	second := caller.Calls[1]
+	if len(second) != 4 || second[0].Content != "kickoff" || second[1].Role != llm.RoleAssistant {
+		t.Fatalf("unexpected conversation: %+v", second)
+	}
+	if second[2].ToolName != "query_ownership" || !strings.Contains(second[2].Content, "alice@example.com") {
+		t.Errorf("ownership result not passed back: %+v", second[2])
+	}
+	if second[3].ToolCallID != "call_get_commit_patch" || !strings.Contains(second[3].Content, "mock patch content") {
+		t.Errorf("patch result not passed back: %+v", second[3])
+	}
+}
+
+func TestToolLoopInvestigator_TextAnswer(t *testing.T) {
+	inv, _ := newScriptedInvestigator(DefaultInvestigationLimits(),
+		llm.ToolResponse{Content: `{"risk_level": "LOW", "confidence": 0.9, "summary": "Trivial change"}`},
+	)
+
+	assessment, err := inv.Investigate(context.Background(), "kickoff")
+	if err != nil {
+		t.Fatalf("Investigate: %v", err)
+	}
+	if assessment.RiskLevel != RiskLow || assessment.Summary != "Trivial change" {
+		t.Errorf("unexpected assessment: %+v", assessment)
+	}
+	if assessment.Investigation.TotalTokens == 0 {
+		t.Error("expected token usage to be estimated when the provider reports none")
+	}
+}
+
+func TestToolLoopInvestigator_Limits(t *testing.T) {
+	ownership := llm.ToolResponse{
+		ToolCalls:  []llm.ToolCall{llm.ScriptedCall("query_ownership", map[string]any{"file_paths": []any{"a.go"}})},
+		TokensUsed: 400,
+	}
+
+	tests := []struct {
+		name   string
+		limits InvestigationLimits
+		reason string
+	}{
+		{"max hops", InvestigationLimits{MaxHops: 2}, "maximum hops (2)"},
+		{"token budget", InvestigationLimits{MaxHops: 10, MaxTokens: 1000}, "token budget (1200/1000)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, caller := newScriptedInvestigator(tt.limits, ownership, ownership, ownership, ownership)
+
+			assessment, err := inv.Investigate(context.Background(), "kickoff")
+			if err != nil {
+				t.Fatalf("Investigate: %v", err)
+			}
+			if assessment.RiskLevel != RiskMedium || assessment.Confidence != 0.3 {
+				t.Errorf("expected emergency assessment, got %+v", assessment)
+			}
+			if !strings.Contains(assessment.Investigation.StoppingReason, tt.reason) {
+				t.Errorf("expected stopping reason containing %q, got %q", tt.reason, assessment.Investigation.StoppingReason)
+			}
+			if len(caller.Calls) > 3 {
+				t.Errorf("loop did not stop: %d calls", len(caller.Calls))
+			}
+		})
+	}
+}
+
+func TestToolLoopInvestigator_ProviderError(t *testing.T) {
+	inv, _ := newScriptedInvestigator(DefaultInvestigationLimits()) // empty script fails the first call
+
+	if _, err := inv.Investigate(context.Background(), "kickoff"); err == nil {
+		t.Fatal("expected provider error to be returned")
+	}
+}
+
+func TestHistoryManager_PruneKeepsToolPairs(t *testing.T) {
+	hm := NewHistoryManager()
+	history := []llm.Message{{Role: llm.RoleUser, Content: "kickoff"}}
+	big := strings.Repeat("x", 4000)
+	for i := 0; i < 5; i++ {
+		history = append(history,
+			llm.Message{Role: llm.RoleAssistant, ToolCalls: []llm.ToolCall{llm.ScriptedCall("query_blast_radius", nil)}},
+			llm.Message{Role: llm.RoleTool, ToolName: "query_blast_radius", ToolCallID: "call_query_blast_radius", Content: big},
+		)
+	}
+
+	pruned := hm.PruneHistory(history)
+	if len(pruned) != len(history) {
+		t.Fatalf("pruning must not drop messages: %d -> %d", len(history), len(pruned))
+	}
+	if pruned[2].Content == big {
+		t.Error("expected oldest low-value result to be compressed")
+	}
+	if pruned[len(pruned)-1].Content != big {
+		t.Error("expected most recent result to be kept in full")
+	}
+	if history[2].Content != big {
+		t.Error("PruneHistory must not modify its input")
+	}
+}
@@ -0,0 +1,141 @@
+// Package apisurface detects changes to a repository's API surface: exported Go
+// identifiers and their signatures, and the endpoints, parameters and schemas of OpenAPI
+// (Swagger) and JSON Schema documents. Each change is classified as breaking or
+// compatible, and breaking Go changes list the code that calls the changed symbol.
+//
+// Unlike atomizer.SignaturesMatch, which tolerates small signature differences to keep
+// tracking a block's identity, any difference in an exported signature is a change here.
+package apisurface
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Surface is the kind of API a change touches
+type Surface string
+
+const (
+	GoAPI      Surface = "go"
+	OpenAPI    Surface = "openapi"
+	JSONSchema Surface = "jsonschema"
+)
+
+// maxCallers bounds the callers listed for one change
+const maxCallers = 20
+
+// Change is one difference in a file's API surface
+type Change struct {
+	File        string   `json:"file"`
+	Surface     Surface  `json:"surface"`
+	Kind        string   `json:"kind"`   // func, method, type, field, endpoint, parameter, ...
+	Symbol      string   `json:"symbol"` // billing.Service.Refund, "GET /users/{id}"
+	Breaking    bool     `json:"breaking"`
+	Description string   `json:"description"`
+	Severity    string   `json:"severity"`          // LOW, MEDIUM, HIGH
+	Callers     []string `json:"callers,omitempty"` // file:block of code calling or referencing the symbol
+
+	public   bool   // Reachable from outside the repository
+	lookup   string // Declaration name in the call graph
+	callable bool   // Reached through CALLS edges rather than references
+}
+
+// Report is the API surface analysis of a set of changed files
+type Report struct {
+	Changes  []Change `json:"changes"` // Breaking changes first
+	Breaking int      `json:"breaking"`
+	Risk     string   `json:"risk"` // Highest severity among changes
+}
+
+// ForFile returns the changes declared in one file
+func (r *Report) ForFile(file string) []Change {
+	var changes []Change
+	for _, c := range r.Changes {
+		if c.File == file {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Reader returns a file's content at one side of the change, or nil if it does not exist
+type Reader func(file string) ([]byte, error)
+
+var riskRank = map[string]int{"LOW": 0, "MEDIUM": 1, "HIGH": 2}
+
+// Analyze compares the API surface of the changed files before and after the change
+// sources lists the repository's other source files (depgraph.Result.Files) so Go
+// packages are compared whole; with nil only the changed files are read. callers may be
+// nil, in which case no callers are listed.
+func Analyze(ctx context.Context, files []string, before, after Reader, sources []string, callers *Callers) (*Report, error) {
+	report := &Report{Changes: []Change{}, Risk: "LOW"}
+
+	var goFiles []string
+	for _, f := range files {
+		switch {
+		case isGoSource(f):
+			goFiles = append(goFiles, f)
+		case isSpecCandidate(f):
+			old, err := before(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s before the change: %w", f, err)
+			}
+			cur, err := after(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", f, err)
+			}
+			report.Changes = append(report.Changes, diffSpec(f, old, cur)...)
+		}
+	}
+	if len(goFiles) > 0 {
+		changes, err := diffGo(goFiles, before, after, sources)
+		if err != nil {
+			return nil, err
+		}
+		report.Changes = append(report.Changes, changes...)
+	}
+
+	for i := range report.Changes {
+		c := &report.Changes[i]
+		if c.Breaking && c.Surface == GoAPI && callers != nil {
+			c.Callers = callers.find(ctx, c.File, c.lookup, c.callable)
+		}
+		c.Severity = severity(*c)
+		if c.Breaking {
+			report.Breaking++
+		}
+		if riskRank[c.Severity] > riskRank[report.Risk] {
+			report.Risk = c.Severity
+		}
+	}
+
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		ci, cj := report.Changes[i], report.Changes[j]
+		if riskRank[ci.Severity] != riskRank[cj.Severity] {
+			return riskRank[ci.Severity] > riskRank[cj.Severity]
+		}
+		if ci.File != cj.File {
+			return ci.File < cj.File
+		}
+		return ci.Symbol < cj.Symbol
+	})
+	return report, nil
+}
+
+// severity rates a change by who it can break
+// HIGH: a breaking change to an API other repositories can use (public Go packages,
+// OpenAPI and JSON Schema documents)
+// MEDIUM: a breaking change to an internal Go API with callers in this repository
+// LOW: compatible changes, and internal breaking changes nothing calls
+func severity(c Change) string {
+	switch {
+	case !c.Breaking:
+		return "LOW"
+	case c.public:
+		return "HIGH"
+	case len(c.Callers) > 0:
+		return "MEDIUM"
+	}
+	return "LOW"
+}
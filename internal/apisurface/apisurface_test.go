@@ -0,0 +1,269 @@
+package apisurface
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/depgraph"
+)
+
+// readerOf serves files from a map; absent files read as nil
+func readerOf(files map[string]string) Reader {
+	return func(file string) ([]byte, error) {
+		if content, ok := files[file]; ok {
+			return []byte(content), nil
+		}
+		return nil, nil
+	}
+}
+
+func descriptions(changes []Change) map[string]Change {
+	m := make(map[string]Change, len(changes))
+	for _, c := range changes {
+		m[c.Description] = c
+	}
+	return m
+}
+
+func TestAnalyzeGo(t *testing.T) {
+	before := map[string]string{
+		"internal/billing/charge.go": `package billing
+
+type Service struct {
+	Currency string
+	Retries  int
+	ledger   []int
+}
+
+type Store interface {
+	Save(id string) error
+}
+
+const DefaultCurrency = "usd"
+
+func (s *Service) Refund(id string, amount int) error { return nil }
+
+func Charge(total int, vip bool) int { return total }
+
+func Legacy() {}
+`,
+		"internal/billing/helpers.go":     "package billing\n\nfunc Format(amount int) string { return \"\" }\n",
+		"internal/billing/charge_test.go": "package billing\n\nfunc TestHelper() {}\n",
+	}
+	after := map[string]string{
+		"internal/billing/charge.go": `package billing
+
+type Service struct {
+	Currency string
+	Retries  int64
+	Timeout  int
+}
+
+type Store interface {
+	Save(id string) error
+	Delete(id string) error
+}
+
+const DefaultCurrency = "usd"
+
+// Parameter renames are not a change
+func (s *Service) Refund(refundID string, cents int) error { return nil }
+
+func Charge(total int, vip bool, coupon string) int { return total }
+
+// Moved from helpers.go
+func Format(amount int) string { return "" }
+`,
+		"internal/billing/helpers.go":     "package billing\n",
+		"internal/billing/charge_test.go": "package billing\n",
+	}
+
+	static := &depgraph.Result{
+		Calls: []depgraph.Call{
+			{FromFile: "cmd/app/main.go", FromBlock: "run", ToFile: "internal/billing/charge.go", ToBlock: "Charge"},
+			{FromFile: "internal/billing/charge.go", FromBlock: "Legacy", ToFile: "internal/billing/charge.go", ToBlock: "Charge"},
+		},
+		Imports: []depgraph.Import{{From: "internal/api/handlers.go", To: "internal/billing/charge.go"}},
+	}
+	files := []string{"internal/billing/charge.go", "internal/billing/helpers.go", "internal/billing/charge_test.go"}
+	r, err := Analyze(context.Background(), files, readerOf(before), readerOf(after), nil, &Callers{Static: static})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := descriptions(r.Changes)
+	want := map[string]string{
+		"changes func billing.Charge from `func(int, bool) (int)` to `func(int, bool, string) (int)`": "MEDIUM",
+		"changes field billing.Service.Retries from `int` to `int64`":                                 "MEDIUM",
+		"removes func billing.Legacy": "LOW",
+		"adds method Delete to interface billing.Store; existing implementations no longer satisfy it": "MEDIUM",
+		"adds field billing.Service.Timeout": "LOW",
+	}
+	for desc, severity := range want {
+		c, ok := got[desc]
+		if !ok {
+			t.Errorf("missing change %q in %+v", desc, r.Changes)
+			continue
+		}
+		if c.Severity != severity {
+			t.Errorf("%q severity = %s, want %s", desc, c.Severity, severity)
+		}
+	}
+	if len(r.Changes) != len(want) {
+		t.Errorf("got %d changes, want %d (Format moved, Refund only renamed parameters):\n%+v", len(r.Changes), len(want), r.Changes)
+	}
+
+	charge := got["changes func billing.Charge from `func(int, bool) (int)` to `func(int, bool, string) (int)`"]
+	if len(charge.Callers) != 1 || charge.Callers[0] != "cmd/app/main.go:run" {
+		t.Errorf("Charge callers = %v, want the caller outside the declaring file", charge.Callers)
+	}
+	retries := got["changes field billing.Service.Retries from `int` to `int64`"]
+	if len(retries.Callers) != 1 || retries.Callers[0] != "internal/api/handlers.go" {
+		t.Errorf("Retries callers = %v, want the file referencing the package", retries.Callers)
+	}
+	if r.Breaking != 4 || r.Risk != "MEDIUM" || !r.Changes[0].Breaking {
+		t.Errorf("breaking = %d, risk = %s; breaking changes should sort first", r.Breaking, r.Risk)
+	}
+}
+
+func TestAnalyzeGoPublicPackage(t *testing.T) {
+	before := map[string]string{"pkg/client/client.go": "package client\n\ntype Client struct{}\n\nfunc (c Client) Do() {}\n"}
+	after := map[string]string{"pkg/client/client.go": "package client\n\ntype Client struct{}\n\nfunc (c *Client) Do() {}\n"}
+
+	r, err := Analyze(context.Background(), []string{"pkg/client/client.go"}, readerOf(before), readerOf(after), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Changes) != 1 || r.Changes[0].Severity != "HIGH" || !strings.Contains(r.Changes[0].Description, "(*Client)") {
+		t.Errorf("pointer receiver change in a public package = %+v, want one HIGH change", r.Changes)
+	}
+}
+
+func TestAnalyzeOpenAPI(t *testing.T) {
+	before := `openapi: 3.0.3
+paths:
+  /users/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: string}}
+    get:
+      parameters:
+        - {name: expand, in: query, schema: {type: boolean}}
+      responses:
+        200:
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+    delete:
+      responses:
+        204: {description: deleted}
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [email]
+              properties:
+                email: {type: string}
+                role: {type: string, enum: [admin, member]}
+      responses:
+        201: {description: created}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, email]
+      properties:
+        id: {type: string}
+        email: {type: string}
+        age: {type: integer}
+`
+	after := `openapi: 3.0.3
+paths:
+  /users/{id}:
+    parameters:
+      - {name: id, in: path, required: true, schema: {type: integer}}
+    get:
+      parameters:
+        - {name: expand, in: query, schema: {type: boolean}}
+        - {name: fields, in: query, schema: {type: string}}
+      responses:
+        200:
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [email, name]
+              properties:
+                email: {type: string}
+                name: {type: string}
+                role: {type: string, enum: [member]}
+      responses:
+        201: {description: created}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string}
+        email: {type: string}
+`
+	r, err := Analyze(context.Background(), []string{"api/openapi.yaml"},
+		readerOf(map[string]string{"api/openapi.yaml": before}), readerOf(map[string]string{"api/openapi.yaml": after}), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := descriptions(r.Changes)
+	for _, want := range []string{
+		"removes endpoint DELETE /users/{id}",
+		"changes path parameter id from string to integer",
+		"makes the request body required",
+		"adds required field `name` in the request body",
+		"removes allowed value(s) admin from `role` in the request body",
+		"removes field `age` in the 200 response",
+		"makes field `email` in the 200 response optional; clients may find it missing",
+	} {
+		c, ok := got[want]
+		if !ok {
+			t.Errorf("missing change %q", want)
+			continue
+		}
+		if !c.Breaking || c.Severity != "HIGH" || c.Surface != OpenAPI {
+			t.Errorf("%q = %+v, want a HIGH breaking OpenAPI change", want, c)
+		}
+	}
+	if c, ok := got["adds optional query parameter fields"]; !ok || c.Breaking {
+		t.Errorf("optional parameter should be a compatible change: %+v", r.Changes)
+	}
+	if r.Breaking != 7 {
+		t.Errorf("breaking = %d, want 7:\n%+v", r.Breaking, r.Changes)
+	}
+}
+
+func TestAnalyzeJSONSchema(t *testing.T) {
+	before := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "Event", "type": "object",
+		"properties": {"id": {"type": "string"}, "kind": {"type": "string"}}}`
+	after := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "Event", "type": "object",
+		"required": ["kind"], "properties": {"id": {"type": "string"}, "kind": {"type": "string"}, "note": {"type": "string"}}}`
+
+	r, err := Analyze(context.Background(), []string{"schemas/event.json", "package.json"},
+		readerOf(map[string]string{"schemas/event.json": before, "package.json": `{"name": "app"}`}),
+		readerOf(map[string]string{"schemas/event.json": after, "package.json": `{"name": "app", "private": true}`}), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Changes) != 2 || r.Changes[0].Description != "makes field `kind` required" || r.Changes[0].Symbol != "Event" {
+		t.Errorf("changes = %+v, want kind made required and note added", r.Changes)
+	}
+}
@@ -0,0 +1,96 @@
+package apisurface
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/rohankatakam/coderisk/internal/depgraph"
+)
+
+// QueryExecutor runs read queries against the graph
+// Implemented by graph.Client.
+type QueryExecutor interface {
+	ExecuteQuery(ctx context.Context, query string, params map[string]any) ([]map[string]any, error)
+}
+
+// Callers looks up the code depending on a changed Go symbol
+// The ingested graph describes the base revision, so it still knows the callers of
+// symbols the change removes; the working-tree graph fills in when it is unavailable.
+type Callers struct {
+	Graph  QueryExecutor    // Ingested CALLS and IMPORTS edges; optional
+	RepoID int64            // Graph repository, 0 for any
+	Static *depgraph.Result // Static import and call graph of the working tree; optional
+}
+
+const (
+	callersQuery = `
+		MATCH (b:CodeBlock)<-[:CALLS]-(caller:CodeBlock)
+		WHERE b.canonical_file_path = $path AND b.block_name = $name
+		  AND ($repoID = 0 OR b.repo_id = $repoID)
+		RETURN DISTINCT caller.canonical_file_path AS file, caller.block_name AS block
+	`
+	importersQuery = `
+		MATCH (f:File)<-[:IMPORTS]-(dep:File)
+		WHERE f.path = $path AND ($repoID = 0 OR f.repo_id = $repoID)
+		RETURN DISTINCT dep.path AS file, null AS block
+	`
+)
+
+// find returns "file:block" for each caller of a function or method, or the files
+// referencing the declaring file for types, fields, constants and variables
+// Callers inside the declaring file are left out: they change with it.
+func (c *Callers) find(ctx context.Context, file, name string, callable bool) []string {
+	found := make(map[string]bool)
+	add := func(callerFile, block string) {
+		if callerFile == "" || callerFile == file {
+			return
+		}
+		if block != "" {
+			callerFile += ":" + block
+		}
+		found[callerFile] = true
+	}
+
+	if c.Graph != nil {
+		query := importersQuery
+		if callable {
+			query = callersQuery
+		}
+		rows, err := c.Graph.ExecuteQuery(ctx, query, map[string]any{"path": file, "name": name, "repoID": c.RepoID})
+		if err != nil {
+			slog.Warn("caller lookup failed", "file", file, "symbol", name, "error", err)
+		}
+		for _, row := range rows {
+			f, _ := row["file"].(string)
+			b, _ := row["block"].(string)
+			add(f, b)
+		}
+	}
+
+	if len(found) == 0 && c.Static != nil {
+		if callable {
+			for _, call := range c.Static.Calls {
+				if call.ToFile == file && call.ToBlock == name {
+					add(call.FromFile, call.FromBlock)
+				}
+			}
+		} else {
+			for _, imp := range c.Static.Imports {
+				if imp.To == file {
+					add(imp.From, "")
+				}
+			}
+		}
+	}
+
+	callers := make([]string, 0, len(found))
+	for f := range found {
+		callers = append(callers, f)
+	}
+	sort.Strings(callers)
+	if len(callers) > maxCallers {
+		callers = callers[:maxCallers]
+	}
+	return callers
+}
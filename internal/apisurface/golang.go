@@ -0,0 +1,346 @@
+package apisurface
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+)
+
+// goSymbol is one exported identifier of a Go package
+type goSymbol struct {
+	kind      string // func, method, type, field, interface method, const, var
+	signature string // Normalized type or signature; parameter names are not part of it
+	file      string // Declaring file
+	lookup    string // Name of the declaration in the call graph
+	parent    string // Owning type for fields and methods
+}
+
+// callable reports whether callers reach the symbol through CALLS edges
+func (s goSymbol) callable() bool {
+	return s.kind == "func" || s.kind == "method" || s.kind == "interface method"
+}
+
+// goPackage is the exported surface of one package directory
+type goPackage struct {
+	name    string
+	symbols map[string]goSymbol // Keyed by Name, Type.Method or Type.Field
+}
+
+func newGoPackage() *goPackage {
+	return &goPackage{symbols: make(map[string]goSymbol)}
+}
+
+// add parses a file into the package; files that do not parse contribute nothing
+func (p *goPackage) add(file string, src []byte) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, src, parser.SkipObjectResolution)
+	if err != nil {
+		return
+	}
+	if p.name == "" {
+		p.name = f.Name.Name
+	}
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			p.addFunc(file, d)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					p.addType(file, s)
+				case *ast.ValueSpec:
+					kind := strings.ToLower(d.Tok.String())
+					for _, n := range s.Names {
+						if n.IsExported() {
+							p.put(n.Name, goSymbol{kind: kind, signature: exprString(s.Type), file: file, lookup: n.Name})
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// put records a symbol; with several build-tagged declarations the first one wins
+func (p *goPackage) put(key string, s goSymbol) {
+	if _, ok := p.symbols[key]; !ok {
+		p.symbols[key] = s
+	}
+}
+
+func (p *goPackage) addFunc(file string, d *ast.FuncDecl) {
+	if !d.Name.IsExported() {
+		return
+	}
+	if d.Recv == nil {
+		p.put(d.Name.Name, goSymbol{kind: "func", signature: funcSignature(d.Type), file: file, lookup: d.Name.Name})
+		return
+	}
+	recv, pointer := receiverType(d.Recv)
+	if recv == "" || !ast.IsExported(recv) {
+		return
+	}
+	sig := funcSignature(d.Type)
+	if pointer {
+		sig = "(*" + recv + ") " + sig
+	}
+	p.put(recv+"."+d.Name.Name, goSymbol{kind: "method", signature: sig, file: file, lookup: d.Name.Name, parent: recv})
+}
+
+func (p *goPackage) addType(file string, s *ast.TypeSpec) {
+	if !s.Name.IsExported() {
+		return
+	}
+	name := s.Name.Name
+	params := ""
+	if s.TypeParams != nil {
+		params = "[" + fieldTypes(s.TypeParams) + "]"
+	}
+
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		p.put(name, goSymbol{kind: "type", signature: "struct" + params, file: file, lookup: name})
+		for _, field := range t.Fields.List {
+			for _, fname := range fieldNames(field) {
+				if ast.IsExported(fname) {
+					p.put(name+"."+fname, goSymbol{kind: "field", signature: exprString(field.Type), file: file, lookup: name, parent: name})
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		p.put(name, goSymbol{kind: "type", signature: "interface" + params, file: file, lookup: name})
+		for _, field := range t.Methods.List {
+			if ft, ok := field.Type.(*ast.FuncType); ok && len(field.Names) > 0 {
+				for _, m := range field.Names {
+					if m.IsExported() {
+						p.put(name+"."+m.Name, goSymbol{kind: "interface method", signature: funcSignature(ft), file: file, lookup: m.Name, parent: name})
+					}
+				}
+				continue
+			}
+			// Embedded interface or type-set element: implementations must satisfy it too
+			p.put(name+"."+exprString(field.Type), goSymbol{kind: "interface method", signature: "embedded", file: file, lookup: name, parent: name})
+		}
+	default:
+		sig := exprString(s.Type)
+		if s.Assign.IsValid() {
+			sig = "= " + sig
+		}
+		p.put(name, goSymbol{kind: "type", signature: params + sig, file: file, lookup: name})
+	}
+}
+
+// fieldNames returns a struct field's names; an embedded field is named after its type
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := make([]string, len(field.Names))
+		for i, n := range field.Names {
+			names[i] = n.Name
+		}
+		return names
+	}
+	typ := strings.TrimPrefix(exprString(field.Type), "*")
+	if i := strings.IndexByte(typ, '['); i >= 0 {
+		typ = typ[:i]
+	}
+	return []string{typ[strings.LastIndexByte(typ, '.')+1:]}
+}
+
+// receiverType returns a method's receiver type name and whether it is a pointer
+func receiverType(recv *ast.FieldList) (string, bool) {
+	if len(recv.List) == 0 {
+		return "", false
+	}
+	expr, pointer := recv.List[0].Type, false
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr, pointer = t.X, true
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name, pointer
+		default:
+			return "", false
+		}
+	}
+}
+
+// funcSignature renders a function type without parameter names, so renaming a
+// parameter is not a change
+func funcSignature(ft *ast.FuncType) string {
+	sig := "func"
+	if ft.TypeParams != nil {
+		sig += "[" + fieldTypes(ft.TypeParams) + "]"
+	}
+	sig += "(" + fieldTypes(ft.Params) + ")"
+	if ft.Results != nil && len(ft.Results.List) > 0 {
+		sig += " (" + fieldTypes(ft.Results) + ")"
+	}
+	return sig
+}
+
+// fieldTypes lists the type of every entry in a field list, repeating grouped types
+func fieldTypes(list *ast.FieldList) string {
+	if list == nil {
+		return ""
+	}
+	var parts []string
+	for _, field := range list.List {
+		typ := exprString(field.Type)
+		for i := 0; i < max(1, len(field.Names)); i++ {
+			parts = append(parts, typ)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func exprString(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	return types.ExprString(expr)
+}
+
+// isGoSource reports non-test Go files
+func isGoSource(file string) bool {
+	return strings.HasSuffix(file, ".go") && !strings.HasSuffix(file, "_test.go")
+}
+
+// isPublicGoPackage reports whether code outside the module can import a package
+func isPublicGoPackage(dir, name string) bool {
+	if name == "main" {
+		return false
+	}
+	for _, seg := range strings.Split(dir, "/") {
+		if seg == "internal" {
+			return false
+		}
+	}
+	return true
+}
+
+// diffGo compares the exported surface of every package with a changed file
+// Packages are read whole (changed files plus the sources in the same directory) so a
+// symbol moved between files of a package is not reported.
+func diffGo(files []string, before, after Reader, sources []string) ([]Change, error) {
+	dirs := make(map[string]map[string]bool)
+	for _, f := range files {
+		dir := path.Dir(f)
+		if dirs[dir] == nil {
+			dirs[dir] = make(map[string]bool)
+		}
+		dirs[dir][f] = true
+	}
+	for _, f := range sources {
+		if members := dirs[path.Dir(f)]; members != nil && isGoSource(f) {
+			members[f] = true
+		}
+	}
+
+	var changes []Change
+	for _, dir := range sortedKeys(dirs) {
+		old, cur := newGoPackage(), newGoPackage()
+		for _, f := range sortedKeys(dirs[dir]) {
+			src, err := before(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s before the change: %w", f, err)
+			}
+			if src != nil {
+				old.add(f, src)
+			}
+			if src, err = after(f); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", f, err)
+			}
+			if src != nil {
+				cur.add(f, src)
+			}
+		}
+		changes = append(changes, compareGo(dir, old, cur)...)
+	}
+	return changes, nil
+}
+
+// compareGo classifies the differences between two versions of a package's surface
+// Removing or changing any exported symbol breaks its users, as does adding a method to
+// an interface (implementations stop satisfying it); other additions are compatible.
+// Members of a removed or retyped type are folded into that type's change.
+func compareGo(dir string, old, cur *goPackage) []Change {
+	name := cur.name
+	if name == "" {
+		name = old.name
+	}
+	if strings.HasSuffix(name, "_test") || (name == "" && len(old.symbols) == 0) {
+		return nil
+	}
+	public := isPublicGoPackage(dir, name)
+
+	folded := func(s goSymbol) bool {
+		if s.parent == "" {
+			return false
+		}
+		o, inOld := old.symbols[s.parent]
+		c, inCur := cur.symbols[s.parent]
+		return !inOld || !inCur || o.signature != c.signature
+	}
+	change := func(key string, s goSymbol, breaking bool, desc string) Change {
+		return Change{
+			File:        s.file,
+			Surface:     GoAPI,
+			Kind:        s.kind,
+			Symbol:      name + "." + key,
+			Breaking:    breaking,
+			Description: desc,
+			public:      public,
+			lookup:      s.lookup,
+			callable:    s.callable(),
+		}
+	}
+
+	var changes []Change
+	for key, o := range old.symbols {
+		c, ok := cur.symbols[key]
+		switch {
+		case folded(o):
+			continue
+		case !ok:
+			changes = append(changes, change(key, o, true, fmt.Sprintf("removes %s %s.%s", o.kind, name, key)))
+		case o.signature != c.signature:
+			changes = append(changes, change(key, c, true,
+				fmt.Sprintf("changes %s %s.%s from `%s` to `%s`", o.kind, name, key, o.signature, c.signature)))
+		}
+	}
+	for key, c := range cur.symbols {
+		if _, ok := old.symbols[key]; ok || folded(c) {
+			continue
+		}
+		if c.kind == "interface method" {
+			// Implementations break rather than callers; files using the package stand in for them
+			ch := change(key, c, true, fmt.Sprintf("adds method %s to interface %s.%s; existing implementations no longer satisfy it",
+				strings.TrimPrefix(key, c.parent+"."), name, c.parent))
+			ch.callable = false
+			changes = append(changes, ch)
+			continue
+		}
+		changes = append(changes, change(key, c, false, fmt.Sprintf("adds %s %s.%s", c.kind, name, key)))
+	}
+	return changes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
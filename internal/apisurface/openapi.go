@@ -0,0 +1,460 @@
+package apisurface
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxSchemaDepth bounds schema recursion; $ref cycles stop here
+const maxSchemaDepth = 8
+
+// httpMethods are the operation keys of an OpenAPI path item
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// direction is which side of an API reads a schema
+type direction int
+
+const (
+	request  direction = iota // Sent by clients: new requirements break them
+	response                  // Read by clients: removals break them
+	both                      // Standalone JSON Schema: data is written and read
+)
+
+// isSpecCandidate reports files that may hold an OpenAPI or JSON Schema document
+func isSpecCandidate(file string) bool {
+	switch strings.ToLower(path.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// parseSpec decodes a document and reports which kind of specification it is
+// Returns an empty surface for anything else (configs, fixtures, package.json).
+func parseSpec(file string, src []byte) (map[string]any, Surface) {
+	var doc map[string]any
+	if strings.EqualFold(path.Ext(file), ".json") {
+		if json.Unmarshal(src, &doc) != nil {
+			return nil, ""
+		}
+	} else {
+		var raw map[string]any
+		if yaml.Unmarshal(src, &raw) != nil {
+			return nil, ""
+		}
+		doc, _ = stringKeys(raw).(map[string]any)
+	}
+
+	switch {
+	case doc["openapi"] != nil || doc["swagger"] != nil:
+		if _, ok := doc["paths"].(map[string]any); ok {
+			return doc, OpenAPI
+		}
+	case doc["$schema"] != nil && (doc["properties"] != nil || doc["type"] != nil):
+		return doc, JSONSchema
+	}
+	return nil, ""
+}
+
+// specDiff compares two versions of one specification document
+type specDiff struct {
+	file     string
+	surface  Surface
+	old, cur map[string]any // Whole documents, for resolving $ref
+	changes  []Change
+}
+
+func (d *specDiff) add(symbol, kind string, breaking bool, format string, args ...any) {
+	d.changes = append(d.changes, Change{
+		File:        d.file,
+		Surface:     d.surface,
+		Kind:        kind,
+		Symbol:      symbol,
+		Breaking:    breaking,
+		Description: fmt.Sprintf(format, args...),
+		public:      true,
+	})
+}
+
+// diffSpec compares a specification file before and after the change
+// A document that stops being a specification (deleted or unparseable) is not reported;
+// deleting a spec file is as likely a move as a removal of the API.
+func diffSpec(file string, before, after []byte) []Change {
+	cur, surface := parseSpec(file, after)
+	if surface == "" {
+		return nil
+	}
+	old, oldSurface := parseSpec(file, before)
+	if oldSurface != surface {
+		old = map[string]any{}
+	}
+
+	d := &specDiff{file: file, surface: surface, old: old, cur: cur}
+	if surface == OpenAPI {
+		d.diffOpenAPI()
+	} else {
+		title, _ := cur["title"].(string)
+		if title == "" {
+			title = path.Base(file)
+		}
+		d.diffSchema(title, "", "", old, cur, both, 0)
+	}
+	return d.changes
+}
+
+// operations indexes a document's operations by "METHOD /path"
+// Path-level parameters are merged into each operation's own.
+func operations(doc map[string]any) map[string]map[string]any {
+	ops := make(map[string]map[string]any)
+	paths, _ := doc["paths"].(map[string]any)
+	for p, item := range paths {
+		pathItem, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		shared, _ := pathItem["parameters"].([]any)
+		for _, method := range httpMethods {
+			op, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			merged := make(map[string]any, len(op)+1)
+			for k, v := range op {
+				merged[k] = v
+			}
+			own, _ := op["parameters"].([]any)
+			merged["parameters"] = append(append([]any{}, shared...), own...)
+			ops[strings.ToUpper(method)+" "+p] = merged
+		}
+	}
+	return ops
+}
+
+func (d *specDiff) diffOpenAPI() {
+	oldOps, curOps := operations(d.old), operations(d.cur)
+	for _, key := range sortedKeys(oldOps) {
+		cur, ok := curOps[key]
+		if !ok {
+			d.add(key, "endpoint", true, "removes endpoint %s", key)
+			continue
+		}
+		old := oldOps[key]
+		d.diffParameters(key, old, cur)
+		d.diffRequestBody(key, old, cur)
+		d.diffResponses(key, old, cur)
+	}
+	for _, key := range sortedKeys(curOps) {
+		if _, ok := oldOps[key]; !ok {
+			d.add(key, "endpoint", false, "adds endpoint %s", key)
+		}
+	}
+}
+
+// parameters indexes an operation's parameters by "in:name"
+func parameters(doc, op map[string]any) map[string]map[string]any {
+	params := make(map[string]map[string]any)
+	list, _ := op["parameters"].([]any)
+	for _, raw := range list {
+		p, ok := resolve(doc, raw)
+		if !ok {
+			continue
+		}
+		in, _ := p["in"].(string)
+		name, _ := p["name"].(string)
+		params[in+":"+name] = p // Operation parameters override path-level ones
+	}
+	return params
+}
+
+func (d *specDiff) diffParameters(op string, old, cur map[string]any) {
+	oldParams, curParams := parameters(d.old, old), parameters(d.cur, cur)
+	for _, key := range sortedKeys(oldParams) {
+		in, name, _ := strings.Cut(key, ":")
+		p, ok := curParams[key]
+		if !ok {
+			d.add(op, "parameter", true, "removes %s parameter %s", in, name)
+			continue
+		}
+		o := oldParams[key]
+		if in == "body" { // Swagger 2 request body
+			oldSchema, _ := resolve(d.old, o["schema"])
+			curSchema, _ := resolve(d.cur, p["schema"])
+			d.diffSchema(op, "request body", "", oldSchema, curSchema, request, 0)
+			continue
+		}
+		if !truthy(o["required"]) && truthy(p["required"]) {
+			d.add(op, "parameter", true, "makes %s parameter %s required", in, name)
+		}
+		if ot, ct := d.schemaType(d.old, paramSchema(o)), d.schemaType(d.cur, paramSchema(p)); ot != "" && ct != "" && ot != ct {
+			d.add(op, "parameter", true, "changes %s parameter %s from %s to %s", in, name, ot, ct)
+		}
+	}
+	for _, key := range sortedKeys(curParams) {
+		if _, ok := oldParams[key]; ok {
+			continue
+		}
+		in, name, _ := strings.Cut(key, ":")
+		if truthy(curParams[key]["required"]) {
+			d.add(op, "parameter", true, "adds required %s parameter %s", in, name)
+		} else {
+			d.add(op, "parameter", false, "adds optional %s parameter %s", in, name)
+		}
+	}
+}
+
+// paramSchema returns the schema of an OpenAPI 3 parameter, or the Swagger 2 parameter
+// itself, which carries type and enum inline
+func paramSchema(p map[string]any) any {
+	if s, ok := p["schema"]; ok {
+		return s
+	}
+	return p
+}
+
+func (d *specDiff) diffRequestBody(op string, old, cur map[string]any) {
+	oldBody, hadBody := resolve(d.old, old["requestBody"])
+	curBody, hasBody := resolve(d.cur, cur["requestBody"])
+	switch {
+	case !hasBody:
+		return
+	case !hadBody:
+		if truthy(curBody["required"]) {
+			d.add(op, "request body", true, "adds a required request body")
+		}
+		return
+	}
+	if !truthy(oldBody["required"]) && truthy(curBody["required"]) {
+		d.add(op, "request body", true, "makes the request body required")
+	}
+	d.diffSchema(op, "request body", "", d.contentSchema(d.old, oldBody), d.contentSchema(d.cur, curBody), request, 0)
+}
+
+func (d *specDiff) diffResponses(op string, old, cur map[string]any) {
+	oldResponses, _ := old["responses"].(map[string]any)
+	curResponses, _ := cur["responses"].(map[string]any)
+	for _, code := range sortedKeys(oldResponses) {
+		if !strings.HasPrefix(code, "2") {
+			continue // Clients handle errors generically; only success payloads are a contract
+		}
+		o, _ := resolve(d.old, oldResponses[code])
+		c, ok := resolve(d.cur, curResponses[code])
+		if !ok {
+			d.add(op, "response", true, "removes the %s response", code)
+			continue
+		}
+		d.diffSchema(op, code+" response", "", d.contentSchema(d.old, o), d.contentSchema(d.cur, c), response, 0)
+	}
+}
+
+// contentSchema returns the JSON schema of a request body or response
+// OpenAPI 3 nests it under content by media type; Swagger 2 responses hold it directly.
+func (d *specDiff) contentSchema(doc, obj map[string]any) map[string]any {
+	if s, ok := resolve(doc, obj["schema"]); ok {
+		return s
+	}
+	content, _ := obj["content"].(map[string]any)
+	media := "application/json"
+	if _, ok := content[media]; !ok {
+		keys := sortedKeys(content)
+		if len(keys) == 0 {
+			return nil
+		}
+		media = keys[0]
+	}
+	m, _ := content[media].(map[string]any)
+	s, _ := resolve(doc, m["schema"])
+	return s
+}
+
+// diffSchema compares two versions of a schema read in the given direction
+// where names the location for messages ("request body", "200 response", or empty for a
+// standalone schema) and field is the dotted path within it.
+func (d *specDiff) diffSchema(symbol, where, field string, old, cur map[string]any, dir direction, depth int) {
+	if old == nil || cur == nil || depth > maxSchemaDepth {
+		return
+	}
+	at := func(name string) string {
+		loc := "`" + name + "`"
+		if where != "" {
+			loc += " in the " + where
+		}
+		return loc
+	}
+	join := func(name string) string {
+		if field == "" {
+			return name
+		}
+		return field + "." + name
+	}
+
+	if ot, ct := d.schemaType(d.old, old), d.schemaType(d.cur, cur); ot != "" && ct != "" && ot != ct {
+		name := field
+		if name == "" {
+			name = "(root)"
+		}
+		d.add(symbol, "field", true, "changes the type of %s from %s to %s", at(name), ot, ct)
+		return
+	}
+
+	if oldEnum, curEnum := enumValues(old), enumValues(cur); oldEnum != nil && curEnum != nil {
+		name := field
+		if name == "" {
+			name = "(root)"
+		}
+		if removed := missing(oldEnum, curEnum); len(removed) > 0 && dir != response {
+			d.add(symbol, "enum", true, "removes allowed value(s) %s from %s", strings.Join(removed, ", "), at(name))
+		}
+		if added := missing(curEnum, oldEnum); len(added) > 0 && dir != request {
+			d.add(symbol, "enum", true, "adds value(s) %s to %s; clients may not handle them", strings.Join(added, ", "), at(name))
+		}
+	}
+
+	oldProps, _ := old["properties"].(map[string]any)
+	curProps, _ := cur["properties"].(map[string]any)
+	oldRequired, curRequired := stringSet(old["required"]), stringSet(cur["required"])
+	for _, name := range sortedKeys(oldProps) {
+		c, ok := curProps[name]
+		if !ok {
+			if dir != request {
+				d.add(symbol, "field", true, "removes field %s", at(join(name)))
+			}
+			continue
+		}
+		switch {
+		case !oldRequired[name] && curRequired[name] && dir != response:
+			d.add(symbol, "field", true, "makes field %s required", at(join(name)))
+		case oldRequired[name] && !curRequired[name] && dir != request:
+			d.add(symbol, "field", true, "makes field %s optional; clients may find it missing", at(join(name)))
+		}
+		o, _ := resolve(d.old, oldProps[name])
+		n, _ := resolve(d.cur, c)
+		d.diffSchema(symbol, where, join(name), o, n, dir, depth+1)
+	}
+	for _, name := range sortedKeys(curProps) {
+		if _, ok := oldProps[name]; ok {
+			continue
+		}
+		if curRequired[name] && dir != response {
+			d.add(symbol, "field", true, "adds required field %s", at(join(name)))
+		} else {
+			d.add(symbol, "field", false, "adds field %s", at(join(name)))
+		}
+	}
+
+	oldItems, _ := resolve(d.old, old["items"])
+	curItems, _ := resolve(d.cur, cur["items"])
+	d.diffSchema(symbol, where, join("[]"), oldItems, curItems, dir, depth+1)
+}
+
+// schemaType renders a schema's type, following $ref; a list of types is sorted
+func (d *specDiff) schemaType(doc map[string]any, raw any) string {
+	s, ok := resolve(doc, raw)
+	if !ok {
+		return ""
+	}
+	switch t := s["type"].(type) {
+	case string:
+		if f, ok := s["format"].(string); ok {
+			return t + "(" + f + ")"
+		}
+		return t
+	case []any:
+		var names []string
+		for _, v := range t {
+			names = append(names, fmt.Sprint(v))
+		}
+		sort.Strings(names)
+		return strings.Join(names, "|")
+	}
+	return ""
+}
+
+// resolve follows a local $ref ("#/components/schemas/User") to the object it names
+func resolve(doc map[string]any, raw any) (map[string]any, bool) {
+	obj, ok := raw.(map[string]any)
+	for hops := 0; ok && hops < maxSchemaDepth; hops++ {
+		ref, isRef := obj["$ref"].(string)
+		if !isRef {
+			return obj, true
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, false // External references are not followed
+		}
+		var node any = doc
+		for _, part := range strings.Split(ref[2:], "/") {
+			m, _ := node.(map[string]any)
+			node = m[strings.NewReplacer("~1", "/", "~0", "~").Replace(part)]
+		}
+		obj, ok = node.(map[string]any)
+	}
+	return nil, false
+}
+
+// stringKeys converts the map[any]any YAML produces for non-string keys (unquoted
+// response codes such as 200) to map[string]any, recursively
+func stringKeys(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			t[k] = stringKeys(item)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, item := range t {
+			m[fmt.Sprint(k)] = stringKeys(item)
+		}
+		return m
+	case []any:
+		for i, item := range t {
+			t[i] = stringKeys(item)
+		}
+		return t
+	}
+	return v
+}
+
+func truthy(v any) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+func stringSet(v any) map[string]bool {
+	set := make(map[string]bool)
+	list, _ := v.([]any)
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func enumValues(schema map[string]any) map[string]bool {
+	list, ok := schema["enum"].([]any)
+	if !ok {
+		return nil
+	}
+	values := make(map[string]bool, len(list))
+	for _, v := range list {
+		values[fmt.Sprint(v)] = true
+	}
+	return values
+}
+
+// missing returns the values of a absent from b, sorted
+func missing(a, b map[string]bool) []string {
+	var out []string
+	for v := range a {
+		if !b[v] {
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
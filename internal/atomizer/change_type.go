@@ -0,0 +1,42 @@
+package atomizer
+
+import (
+	"context"
+	"log"
+
+	"github.com/rohankatakam/coderisk/internal/changetype"
+	"github.com/rohankatakam/coderisk/internal/git"
+)
+
+// recordChangeType classifies a commit's diff, checked against its extracted block events,
+// and labels the Commit node with the riskiest kind so history queries can tell refactors
+// and dependency bumps from behavioural changes
+func (p *Processor) recordChangeType(ctx context.Context, commit CommitData, events []ChangeEvent, repoID int64) {
+	blockEvents := make([]changetype.Event, 0, len(events))
+	for _, e := range events {
+		blockEvents = append(blockEvents, changetype.Event{File: e.TargetFile, Behavior: e.Behavior})
+	}
+
+	classes := changetype.RefineWithEvents(changetype.ClassifyDiff(commit.DiffContent, commitSource(commit)), blockEvents)
+	kind := changetype.Dominant(classes)
+	if kind == "" {
+		return
+	}
+	if err := p.graphWriter.SetCommitChangeType(ctx, commit.SHA, repoID, string(kind)); err != nil {
+		log.Printf("  ⚠️  WARNING: %v", err)
+	}
+}
+
+// commitSource reads files at the commit and its parent from the checkout, when there is
+// one; without it only changes the diff alone can prove harmless are labelled as such
+func commitSource(commit CommitData) changetype.Source {
+	if commit.RepoPath == "" {
+		return changetype.Source{}
+	}
+	at := func(rev string) changetype.Reader {
+		return func(path string) ([]byte, error) {
+			return git.ShowFile(commit.RepoPath, rev, path)
+		}
+	}
+	return changetype.Source{Before: at(commit.SHA + "^"), After: at(commit.SHA)}
+}
@@ -0,0 +1,64 @@
+package atomizer
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/complexity"
+	"github.com/rohankatakam/coderisk/internal/git"
+)
+
+// recordComplexity measures a created, modified or renamed block from its file at the
+// commit and stores the metrics in Postgres and on its CodeBlock node
+// LLM-extracted code is elided, so the block is located by name in the real source.
+// Skipped when the commit has no checkout, the language is unsupported, or the block
+// cannot be found; failures are logged rather than failing the event.
+func (p *Processor) recordComplexity(ctx context.Context, event *ChangeEvent, commit CommitData, state *StateTracker, repoID int64, sources map[string][]complexity.Block) {
+	switch event.Behavior {
+	case "CREATE_BLOCK", "MODIFY_BLOCK", "RENAME_BLOCK":
+	default:
+		return
+	}
+	language := complexity.LanguageOf(event.TargetFile)
+	if commit.RepoPath == "" || !complexity.Supported(language) {
+		return
+	}
+	blockID, ok := state.GetBlockID(event.TargetFile, event.TargetBlockName)
+	if !ok {
+		return
+	}
+
+	blocks, cached := sources[event.TargetFile]
+	if !cached {
+		src, err := git.ShowFile(commit.RepoPath, commit.SHA, event.TargetFile)
+		if err != nil {
+			log.Printf("  ⚠️  WARNING: Failed to read %s for complexity: %v", event.TargetFile, err)
+		} else {
+			blocks = complexity.Analyze(src, language)
+		}
+		sources[event.TargetFile] = blocks
+	}
+
+	block, found := complexity.Find(blocks, shortBlockName(event.TargetBlockName))
+	if !found {
+		return
+	}
+	created := event.Behavior == "CREATE_BLOCK"
+	if err := p.dbWriter.UpdateBlockComplexity(ctx, blockID, repoID, commit.SHA, block.Metrics, created); err != nil {
+		log.Printf("  ⚠️  WARNING: %v", err)
+		return
+	}
+	if err := p.graphWriter.SetBlockComplexity(ctx, blockID, repoID, block.Metrics); err != nil {
+		log.Printf("  ⚠️  WARNING: %v", err)
+	}
+}
+
+// shortBlockName strips a qualifying class or receiver ("Service.ProcessRefund",
+// "Refunds::process", "Client#request") down to the definition name
+func shortBlockName(name string) string {
+	if i := strings.LastIndexAny(name, ".:#"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
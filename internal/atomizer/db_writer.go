@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/rohankatakam/coderisk/internal/complexity"
 )
 
 // DBWriter handles all PostgreSQL write operations for code blocks
@@ -344,3 +346,47 @@ func (w *DBWriter) MarkCommitAtomized(ctx context.Context, commitSHA string, rep
 
 	return nil
 }
+
+// UpdateBlockComplexity stores a block's metrics as measured at commitSHA and records how
+// the commit moved its cyclomatic complexity in code_block_changes.complexity_delta
+// A created block's delta is its full complexity; a modified block measured for the first
+// time has no baseline, so its delta is left unset.
+func (w *DBWriter) UpdateBlockComplexity(ctx context.Context, blockID, repoID int64, commitSHA string, m complexity.Metrics, created bool) error {
+	query := `
+		WITH previous AS (
+			SELECT complexity_estimate FROM code_blocks WHERE id = $1
+		)
+		UPDATE code_blocks
+		SET complexity_estimate = $2,
+		    max_nesting_depth = $3,
+		    parameter_count = $4,
+		    line_count = $5,
+		    complexity_measured_sha = $6
+		WHERE id = $1
+		RETURNING (SELECT complexity_estimate FROM previous)
+	`
+
+	var previous sql.NullInt64
+	err := w.db.QueryRowContext(ctx, query, blockID, m.Cyclomatic, m.MaxNesting, m.Parameters, m.Lines, commitSHA).Scan(&previous)
+	if err != nil {
+		return fmt.Errorf("failed to update complexity of block %d: %w", blockID, err)
+	}
+
+	delta := m.Cyclomatic
+	switch {
+	case previous.Valid:
+		delta = m.Cyclomatic - int(previous.Int64)
+	case !created:
+		return nil
+	}
+
+	_, err = w.db.ExecContext(ctx, `
+		UPDATE code_block_changes
+		SET complexity_delta = $1
+		WHERE repo_id = $2 AND commit_sha = $3 AND block_id = $4
+	`, delta, repoID, commitSHA, blockID)
+	if err != nil {
+		return fmt.Errorf("failed to record complexity delta for block %d: %w", blockID, err)
+	}
+	return nil
+}
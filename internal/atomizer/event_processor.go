@@ -7,6 +7,7 @@ import (
 	"log"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/rohankatakam/coderisk/internal/complexity"
 )
 
 // Processor orchestrates the chronological processing of commits
@@ -75,6 +76,7 @@ func (p *Processor) ProcessCommitsChronologically(ctx context.Context, commits [
 
 		// 3b. Process each event
 		commitHasErrors := false
+		sources := make(map[string][]complexity.Block) // Blocks parsed per file at this commit
 		for j, event := range eventLog.ChangeEvents {
 			if err := p.processEvent(ctx, &event, eventLog, commit, state, repoID); err != nil {
 				log.Printf("  ⚠️  WARNING: Failed to process event %d in commit %s: %v", j, commit.SHA[:8], err)
@@ -82,6 +84,7 @@ func (p *Processor) ProcessCommitsChronologically(ctx context.Context, commits [
 				commitHasErrors = true
 			} else {
 				successCount++
+				p.recordComplexity(ctx, &event, commit, state, repoID, sources)
 				// Track event types
 				switch event.Behavior {
 				case "CREATE_BLOCK":
@@ -100,6 +103,8 @@ func (p *Processor) ProcessCommitsChronologically(ctx context.Context, commits [
 			}
 		}
 
+		p.recordChangeType(ctx, commit, eventLog.ChangeEvents, repoID)
+
 		// 3c. Mark commit as atomized (idempotency tracking)
 		// Only mark as atomized if commit processed successfully (even if some events had warnings)
 		if !commitHasErrors {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/rohankatakam/coderisk/internal/complexity"
 )
 
 // GraphWriter handles all Neo4j write operations for code blocks
@@ -321,3 +322,66 @@ func (g *GraphWriter) BatchCreateCodeBlockNodes(ctx context.Context, events []st
 
 	return nil
 }
+
+// SetBlockComplexity stores structural metrics on a CodeBlock node
+func (g *GraphWriter) SetBlockComplexity(ctx context.Context, blockID int64, repoID int64, m complexity.Metrics) error {
+	query := `
+		MATCH (b:CodeBlock {db_id: $block_id, repo_id: $repo_id})
+		SET b.cyclomatic_complexity = $cyclomatic,
+		    b.max_nesting_depth = $max_nesting,
+		    b.parameter_count = $parameters,
+		    b.line_count = $lines
+	`
+
+	session := g.driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: g.database,
+	})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		_, err := tx.Run(ctx, query, map[string]any{
+			"block_id":    blockID,
+			"repo_id":     repoID,
+			"cyclomatic":  m.Cyclomatic,
+			"max_nesting": m.MaxNesting,
+			"parameters":  m.Parameters,
+			"lines":       m.Lines,
+		})
+		return nil, err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set complexity on CodeBlock %d: %w", blockID, err)
+	}
+
+	return nil
+}
+
+// SetCommitChangeType labels a Commit node with the riskiest change type among its files
+// (see internal/changetype)
+func (g *GraphWriter) SetCommitChangeType(ctx context.Context, commitSHA string, repoID int64, kind string) error {
+	query := `
+		MATCH (c:Commit {sha: $commit_sha, repo_id: $repo_id})
+		SET c.change_type = $change_type
+	`
+
+	session := g.driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: g.database,
+	})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		_, err := tx.Run(ctx, query, map[string]any{
+			"commit_sha":  commitSHA,
+			"repo_id":     repoID,
+			"change_type": kind,
+		})
+		return nil, err
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set change type on Commit %s: %w", commitSHA, err)
+	}
+
+	return nil
+}
@@ -65,6 +65,7 @@ type CommitData struct {
 	Timestamp   time.Time
 	RepoID      int64     // Repository ID for database operations
 	AuthorDate  time.Time // Author date for rename tracking
+	RepoPath    string    // Checkout to read sources from for complexity metrics and change classification (optional)
 }
 
 // ValidateEvent checks if a ChangeEvent is valid
@@ -0,0 +1,131 @@
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/linking/types"
+)
+
+// detectionPatterns maps a link's detection method to ground truth linking patterns
+var detectionPatterns = map[string][]string{
+	string(types.DetectionExplicit):       {"explicit"},
+	string(types.DetectionExplicitBidir):  {"explicit", "bidirectional"},
+	string(types.DetectionGitHubTimeline): {"explicit"},
+	string(types.DetectionDeepLinkFinder): {"temporal", "semantic"},
+}
+
+// GroundTruthFromLabels builds ground truth from link labels recorded with `crisk links review`
+// Each issue with an accepted link becomes a positive case expecting the accepted PRs.
+// An issue whose links were all rejected becomes a negative case, unless it still has
+// unlabeled links (links lists the repository's current links), since those may be correct.
+func GroundTruthFromLabels(repository string, labels []database.LinkLabel, links []types.LinkOutput) *GroundTruth {
+	labeled := make(map[[2]int]bool, len(labels))
+	byIssue := make(map[int][]database.LinkLabel)
+	for _, l := range labels {
+		labeled[[2]int{l.IssueNumber, l.PRNumber}] = true
+		byIssue[l.IssueNumber] = append(byIssue[l.IssueNumber], l)
+	}
+	unlabeled := make(map[int]bool)
+	for _, link := range links {
+		if !labeled[[2]int{link.IssueNumber, link.PRNumber}] {
+			unlabeled[link.IssueNumber] = true
+		}
+	}
+
+	gt := &GroundTruth{
+		Repository:          repository,
+		GithubURL:           "https://github.com/" + repository,
+		ValidationDate:      time.Now().Format("2006-01-02"),
+		Validator:           "crisk_links_review",
+		PatternDistribution: map[string]int{},
+		Notes:               "Exported from human link labels; original_confidence is the automated linker's confidence before review.",
+	}
+
+	issues := make([]int, 0, len(byIssue))
+	for issue := range byIssue {
+		issues = append(issues, issue)
+	}
+	sort.Ints(issues)
+
+	for _, issue := range issues {
+		tc := GroundTruthTestCase{
+			IssueNumber:     issue,
+			IssueURL:        fmt.Sprintf("https://github.com/%s/issues/%d", repository, issue),
+			ExpectedLinks:   ExpectedLinks{FixedByCommits: []string{}, AssociatedPRs: []int{}, AssociatedIssues: []int{}},
+			PrimaryEvidence: map[string]interface{}{},
+			LinkQuality:     "high",
+			GithubVerification: GithubVerification{
+				IssueState:       "closed",
+				VerifiedManually: true,
+			},
+		}
+
+		patterns := make(map[string]bool)
+		var rejectedPRs []int
+		var notes []string
+		for _, l := range byIssue[issue] {
+			if l.IssueTitle != "" {
+				tc.Title = l.IssueTitle
+			}
+			if l.Note != "" {
+				notes = append(notes, fmt.Sprintf("PR #%d: %s", l.PRNumber, l.Note))
+			}
+			if l.Decision != database.LinkLabelAccepted {
+				rejectedPRs = append(rejectedPRs, l.PRNumber)
+				continue
+			}
+			tc.ExpectedLinks.AssociatedPRs = append(tc.ExpectedLinks.AssociatedPRs, l.PRNumber)
+			tc.PrimaryEvidence[fmt.Sprintf("pr_%d_original_confidence", l.PRNumber)] = l.OriginalConfidence
+			for _, p := range detectionPatterns[l.DetectionMethod] {
+				patterns[p] = true
+			}
+		}
+
+		switch {
+		case len(tc.ExpectedLinks.AssociatedPRs) > 0:
+			tc.ShouldDetect = true
+			tc.ExpectedConfidence = 1.0
+		case unlabeled[issue]:
+			continue
+		default:
+			tc.ShouldDetect = false
+			patterns["true_negative"] = true
+			gt.ValidationMetrics.ExpectedTrueNegatives++
+		}
+		if tc.ShouldDetect {
+			gt.ValidationMetrics.ExpectedTruePositives++
+		}
+		if len(rejectedPRs) > 0 {
+			tc.PrimaryEvidence["rejected_prs"] = rejectedPRs
+		}
+		for p := range patterns {
+			tc.LinkingPatterns = append(tc.LinkingPatterns, p)
+			gt.PatternDistribution[p]++
+		}
+		sort.Strings(tc.LinkingPatterns)
+		tc.Notes = strings.Join(notes, "; ")
+
+		gt.TestCases = append(gt.TestCases, tc)
+	}
+
+	gt.TotalCases = len(gt.TestCases)
+	return gt
+}
+
+// SaveGroundTruth writes ground truth in the format read by LoadGroundTruth
+func SaveGroundTruth(gt *GroundTruth, path string) error {
+	data, err := json.MarshalIndent(gt, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ground truth: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write ground truth: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package backtest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/linking/types"
+)
+
+func TestGroundTruthFromLabels(t *testing.T) {
+	labels := []database.LinkLabel{
+		{IssueNumber: 10, PRNumber: 11, Decision: database.LinkLabelAccepted, OriginalConfidence: 0.62, DetectionMethod: "deep_link_finder", IssueTitle: "Crash on save"},
+		{IssueNumber: 10, PRNumber: 12, Decision: database.LinkLabelRejected, Note: "only touches docs"},
+		{IssueNumber: 20, PRNumber: 21, Decision: database.LinkLabelRejected},
+		{IssueNumber: 30, PRNumber: 31, Decision: database.LinkLabelRejected},
+	}
+	// Issue 30 still has an unlabeled link, so its rejection says nothing about the issue overall
+	links := []types.LinkOutput{
+		{IssueNumber: 10, PRNumber: 11},
+		{IssueNumber: 30, PRNumber: 32},
+	}
+
+	gt := GroundTruthFromLabels("acme/api", labels, links)
+
+	if gt.TotalCases != 2 || len(gt.TestCases) != 2 {
+		t.Fatalf("expected 2 cases, got %+v", gt.TestCases)
+	}
+	positive := gt.TestCases[0]
+	if positive.IssueNumber != 10 || !positive.ShouldDetect || len(positive.ExpectedLinks.AssociatedPRs) != 1 || positive.ExpectedLinks.AssociatedPRs[0] != 11 {
+		t.Errorf("unexpected positive case: %+v", positive)
+	}
+	if len(positive.LinkingPatterns) != 2 || positive.LinkingPatterns[0] != "semantic" || positive.Title != "Crash on save" {
+		t.Errorf("expected semantic/temporal patterns from deep link finder, got %+v", positive)
+	}
+	if positive.Notes != "PR #12: only touches docs" {
+		t.Errorf("unexpected notes: %q", positive.Notes)
+	}
+	negative := gt.TestCases[1]
+	if negative.IssueNumber != 20 || negative.ShouldDetect {
+		t.Errorf("expected issue 20 as a negative case, got %+v", negative)
+	}
+	if gt.ValidationMetrics.ExpectedTruePositives != 1 || gt.ValidationMetrics.ExpectedTrueNegatives != 1 {
+		t.Errorf("unexpected metrics: %+v", gt.ValidationMetrics)
+	}
+
+	path := filepath.Join(t.TempDir(), "ground_truth.json")
+	if err := SaveGroundTruth(gt, path); err != nil {
+		t.Fatalf("SaveGroundTruth: %v", err)
+	}
+	loaded, err := LoadGroundTruth(path)
+	if err != nil {
+		t.Fatalf("LoadGroundTruth: %v", err)
+	}
+	if loaded.TotalCases != 2 || loaded.TestCases[0].ExpectedLinks.AssociatedPRs[0] != 11 {
+		t.Errorf("round trip lost data: %+v", loaded)
+	}
+}
@@ -0,0 +1,537 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rohankatakam/coderisk/internal/config"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/metrics"
+)
+
+// Risk backtest tuning
+const (
+	// riskMaxFilesPerCommit skips sweeping commits (mass renames, reformatting), as SZZ does
+	riskMaxFilesPerCommit = 50
+
+	// riskCouplingMinCoChanges is how often two files must have changed together to count as coupled
+	riskCouplingMinCoChanges = 2
+
+	// riskIncidentWindow and riskIncidentFlagCount mirror the incident metric: HIGH at 2+ fixes in 90 days
+	riskIncidentWindow    = 90 * 24 * time.Hour
+	riskIncidentFlagCount = 2
+)
+
+// Metric names used in RiskReport.Metrics
+const (
+	RiskMetricCoupling  = "coupling"
+	RiskMetricCoChange  = "co_change"
+	RiskMetricTestRatio = "test_ratio"
+	RiskMetricIncidents = "incidents"
+)
+
+// RiskOptions controls the risk model backtest
+type RiskOptions struct {
+	// MinConfidence is the SZZ confidence at which a commit counts as bug-introducing
+	MinConfidence float64 `json:"min_confidence"`
+
+	// HorizonDays excludes the newest commits from scoring: their bugs may not have been found yet
+	HorizonDays int `json:"horizon_days"`
+
+	// ConfigKeys selects the AdaptiveRiskConfigs to evaluate (empty = all)
+	ConfigKeys []string `json:"config_keys,omitempty"`
+}
+
+// DefaultRiskOptions returns the default backtest options
+func DefaultRiskOptions() RiskOptions {
+	return RiskOptions{
+		MinConfidence: 0.3,
+		HorizonDays:   90,
+	}
+}
+
+// CommitRisk is what `crisk check` would have reported for a commit, next to what happened
+// Metric values are the riskiest across the commit's files, computed from earlier commits only.
+type CommitRisk struct {
+	SHA            string                       `json:"sha"`
+	Date           time.Time                    `json:"date"`
+	BugIntroducing bool                         `json:"bug_introducing"`
+	SZZConfidence  float64                      `json:"szz_confidence,omitempty"`
+	Files          int                          `json:"files"`
+	Coupling       int                          `json:"coupling"`
+	CoChange       float64                      `json:"co_change"`
+	TestRatio      *float64                     `json:"test_ratio,omitempty"` // nil when only tests changed
+	RecentFixes    int                          `json:"recent_fixes"`
+	Levels         map[string]metrics.RiskLevel `json:"levels"` // config key -> overall risk
+}
+
+// MetricPerformance is how well a single metric separates bug-introducing commits
+type MetricPerformance struct {
+	Metric    string             `json:"metric"`
+	AUC       *float64           `json:"auc"` // nil without both outcomes
+	Threshold string             `json:"threshold"`
+	Commits   int                `json:"commits"`
+	Flagged   PerformanceMetrics `json:"flagged"`
+}
+
+// ConfigPerformance is how well a config's HIGH verdict predicts bug-introducing commits
+type ConfigPerformance struct {
+	ConfigKey   string             `json:"config_key"`
+	Description string             `json:"description"`
+	AUC         *float64           `json:"auc"`
+	FlaggedRate float64            `json:"flagged_rate"` // Share of commits the gate would have blocked
+	High        PerformanceMetrics `json:"high"`
+}
+
+// RiskReport is the result of a risk model backtest
+type RiskReport struct {
+	Repository       string              `json:"repository"`
+	TestDate         string              `json:"test_date"`
+	Options          RiskOptions         `json:"options"`
+	CommitsReplayed  int                 `json:"commits_replayed"`
+	CommitsEvaluated int                 `json:"commits_evaluated"`
+	BugIntroducing   int                 `json:"bug_introducing"`
+	Metrics          []MetricPerformance `json:"metrics"`
+	Configs          []ConfigPerformance `json:"configs"`
+	Commits          []CommitRisk        `json:"commits"`
+}
+
+// RunRiskBacktest loads commit history and SZZ results from PostgreSQL and evaluates the risk model
+func RunRiskBacktest(ctx context.Context, db *sqlx.DB, repoID int64, repository string, opts RiskOptions) (*RiskReport, error) {
+	history, err := database.GetCommitFileHistory(ctx, db, repoID)
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := database.GetBugIntroductionPairs(ctx, db, repoID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no bug-introducing commits recorded for %s (run crisk-index-incident first)", repository)
+	}
+	return EvaluateRiskModel(repository, history, pairs, opts)
+}
+
+// EvaluateRiskModel replays history oldest first. Each commit is scored with metrics built from
+// the commits before it, then labelled with SZZ: bug-introducing when a later fix blamed it.
+// Incident history only counts fixes committed before the scored commit.
+//
+// PostgreSQL holds no historical import graph, so coupling is approximated by the number of
+// files that changed together with the file at least twice; the other metrics use the
+// definitions from risk_assessment_methodology.md, with file LOC from cumulative diff stats.
+func EvaluateRiskModel(repository string, history []database.CommitFiles, pairs []database.BugIntroductionPair, opts RiskOptions) (*RiskReport, error) {
+	configs, err := selectRiskConfigs(opts.ConfigKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	introducedBy := make(map[string]float64)
+	fixCommits := make(map[string]bool)
+	for _, p := range pairs {
+		fixCommits[p.FixCommitSHA] = true
+		if p.Confidence > introducedBy[p.IntroducingCommitSHA] {
+			introducedBy[p.IntroducingCommitSHA] = p.Confidence
+		}
+	}
+
+	report := &RiskReport{
+		Repository: repository,
+		TestDate:   time.Now().Format("2006-01-02"),
+		Options:    opts,
+	}
+
+	var cutoff time.Time
+	if len(history) > 0 && opts.HorizonDays > 0 {
+		cutoff = history[len(history)-1].AuthorDate.AddDate(0, 0, -opts.HorizonDays)
+	}
+
+	state := newReplayState()
+	for _, commit := range history {
+		report.CommitsReplayed++
+		sweeping := len(commit.Files) > riskMaxFilesPerCommit
+		if len(commit.Files) > 0 && !sweeping && (cutoff.IsZero() || !commit.AuthorDate.After(cutoff)) {
+			cr := state.score(commit, configs)
+			if confidence, ok := introducedBy[commit.SHA]; ok && confidence >= opts.MinConfidence {
+				cr.BugIntroducing = true
+				cr.SZZConfidence = confidence
+				report.BugIntroducing++
+			}
+			report.Commits = append(report.Commits, cr)
+		}
+		state.apply(commit, fixCommits[commit.SHA], !sweeping)
+	}
+	report.CommitsEvaluated = len(report.Commits)
+
+	report.Metrics = evaluateMetrics(report.Commits)
+	for _, cfg := range configs {
+		report.Configs = append(report.Configs, evaluateConfig(report.Commits, cfg))
+	}
+	return report, nil
+}
+
+// selectRiskConfigs resolves config keys, defaulting to every config in key order
+func selectRiskConfigs(keys []string) ([]config.AdaptiveRiskConfig, error) {
+	if len(keys) == 0 {
+		keys = config.ListConfigKeys()
+		sort.Strings(keys)
+	}
+	configs := make([]config.AdaptiveRiskConfig, 0, len(keys))
+	for _, key := range keys {
+		cfg, err := config.GetConfig(key)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// replayState is what is known about each file after the commits replayed so far
+type replayState struct {
+	changes  map[string]int            // file -> commits that changed it
+	together map[string]map[string]int // file -> partner -> commits that changed both
+	loc      map[string]int            // file -> lines, from cumulative additions - deletions
+	fixes    map[string][]time.Time    // file -> dates of fix commits that changed it
+}
+
+func newReplayState() *replayState {
+	return &replayState{
+		changes:  make(map[string]int),
+		together: make(map[string]map[string]int),
+		loc:      make(map[string]int),
+		fixes:    make(map[string][]time.Time),
+	}
+}
+
+// fileRisk is the Tier 1 evidence for one file at a point in history
+type fileRisk struct {
+	coupling    int
+	coChange    float64
+	testRatio   float64
+	isTest      bool
+	recentFixes int
+}
+
+// score computes the evidence for every file of a commit before the commit is applied
+func (s *replayState) score(commit database.CommitFiles, configs []config.AdaptiveRiskConfig) CommitRisk {
+	cr := CommitRisk{
+		SHA:    commit.SHA,
+		Date:   commit.AuthorDate,
+		Files:  len(commit.Files),
+		Levels: make(map[string]metrics.RiskLevel, len(configs)),
+	}
+
+	for _, f := range commit.Files {
+		file := f.Filename
+		if f.Status == "renamed" && f.PreviousFilename != "" {
+			file = f.PreviousFilename // History is recorded under the old path until applied
+		}
+		fr := s.fileRisk(file, commit.AuthorDate)
+
+		if fr.coupling > cr.Coupling {
+			cr.Coupling = fr.coupling
+		}
+		if fr.coChange > cr.CoChange {
+			cr.CoChange = fr.coChange
+		}
+		if !fr.isTest && (cr.TestRatio == nil || fr.testRatio < *cr.TestRatio) {
+			ratio := fr.testRatio
+			cr.TestRatio = &ratio
+		}
+		if fr.recentFixes > cr.RecentFixes {
+			cr.RecentFixes = fr.recentFixes
+		}
+
+		for _, cfg := range configs {
+			if level := assessFile(file, fr, cfg); levelRank(level) > levelRank(cr.Levels[cfg.ConfigKey]) {
+				cr.Levels[cfg.ConfigKey] = level
+			}
+		}
+	}
+	return cr
+}
+
+func (s *replayState) fileRisk(file string, at time.Time) fileRisk {
+	fr := fileRisk{isTest: metrics.IsTestFile(file)}
+
+	for partner, both := range s.together[file] {
+		if both >= riskCouplingMinCoChanges {
+			fr.coupling++
+		}
+		// Co-change frequency: commits where both changed / commits where either changed
+		either := s.changes[file] + s.changes[partner] - both
+		if either > 0 {
+			if freq := float64(both) / float64(either); freq > fr.coChange {
+				fr.coChange = freq
+			}
+		}
+	}
+
+	// Smoothed test ratio (risk_assessment_methodology.md §2.3); no data reads as 0, not perfect coverage
+	sourceLOC := s.loc[file]
+	testLOC := 0
+	for _, testFile := range metrics.DiscoverTestFiles(file) {
+		testLOC += s.loc[testFile]
+	}
+	if sourceLOC > 0 || testLOC > 0 {
+		fr.testRatio = float64(testLOC+1) / float64(sourceLOC+1)
+	}
+
+	for _, fixedAt := range s.fixes[file] {
+		if at.Sub(fixedAt) <= riskIncidentWindow {
+			fr.recentFixes++
+		}
+	}
+	return fr
+}
+
+// apply records a commit; coChanges is false for sweeping commits, which would couple everything
+func (s *replayState) apply(commit database.CommitFiles, isFix, coChanges bool) {
+	files := make([]string, 0, len(commit.Files))
+	for _, f := range commit.Files {
+		if f.Status == "renamed" && f.PreviousFilename != "" && f.PreviousFilename != f.Filename {
+			s.rename(f.PreviousFilename, f.Filename)
+		}
+		if f.Status == "removed" {
+			delete(s.loc, f.Filename)
+		} else {
+			s.loc[f.Filename] = max(s.loc[f.Filename]+f.Additions-f.Deletions, 0)
+		}
+		s.changes[f.Filename]++
+		if isFix {
+			s.fixes[f.Filename] = append(s.fixes[f.Filename], commit.AuthorDate)
+		}
+		files = append(files, f.Filename)
+	}
+
+	if !coChanges {
+		return
+	}
+	for i, a := range files {
+		for _, b := range files[i+1:] {
+			if a == b {
+				continue
+			}
+			s.pair(a)[b]++
+			s.pair(b)[a]++
+		}
+	}
+}
+
+func (s *replayState) pair(file string) map[string]int {
+	partners, ok := s.together[file]
+	if !ok {
+		partners = make(map[string]int)
+		s.together[file] = partners
+	}
+	return partners
+}
+
+// rename moves a file's history to its new path
+func (s *replayState) rename(from, to string) {
+	s.changes[to] += s.changes[from]
+	delete(s.changes, from)
+	s.loc[to] += s.loc[from]
+	delete(s.loc, from)
+	s.fixes[to] = append(s.fixes[to], s.fixes[from]...)
+	delete(s.fixes, from)
+
+	for partner, n := range s.together[from] {
+		delete(s.together[partner], from)
+		if partner == to {
+			continue
+		}
+		s.together[partner][to] += n
+		s.pair(to)[partner] += n
+	}
+	delete(s.together, from)
+}
+
+// assessFile applies a config the way `crisk check` does (see CalculatePhase1WithMultiplePaths)
+func assessFile(file string, fr fileRisk, cfg config.AdaptiveRiskConfig) metrics.RiskLevel {
+	result := &metrics.Phase1Result{
+		FilePath: file,
+		Coupling: &metrics.CouplingResult{
+			FilePath:  file,
+			Count:     fr.coupling,
+			RiskLevel: metrics.ClassifyCouplingWithThreshold(fr.coupling, cfg.CouplingThreshold),
+		},
+		CoChange: &metrics.CoChangeResult{
+			FilePath:     file,
+			MaxFrequency: fr.coChange,
+			RiskLevel:    metrics.ClassifyCoChangeWithThreshold(fr.coChange, cfg.CoChangeThreshold),
+		},
+	}
+	if !fr.isTest {
+		result.TestRatio = &metrics.TestRatioResult{
+			FilePath:  file,
+			Ratio:     fr.testRatio,
+			RiskLevel: metrics.ClassifyTestRatioWithThreshold(fr.testRatio, cfg.TestRatioThreshold),
+		}
+	}
+
+	result.ShouldEscalate = metrics.ShouldEscalateWithConfig(result, cfg)
+	return metrics.DetermineOverallRiskWithConfig(result)
+}
+
+func levelRank(level metrics.RiskLevel) int {
+	switch level {
+	case metrics.RiskLevelHigh:
+		return 2
+	case metrics.RiskLevelMedium:
+		return 1
+	case metrics.RiskLevelLow:
+		return 0
+	}
+	return -1
+}
+
+// evaluateMetrics scores each metric on its own, flagging at the default config's thresholds
+func evaluateMetrics(commits []CommitRisk) []MetricPerformance {
+	defaults := config.GetDefaultConfig()
+
+	type metricDef struct {
+		name      string
+		threshold string
+		value     func(CommitRisk) (score float64, flagged, ok bool)
+	}
+	defs := []metricDef{
+		{RiskMetricCoupling, fmt.Sprintf("> %d coupled files", defaults.CouplingThreshold), func(c CommitRisk) (float64, bool, bool) {
+			return float64(c.Coupling), c.Coupling > defaults.CouplingThreshold, true
+		}},
+		{RiskMetricCoChange, fmt.Sprintf("> %.2f co-change frequency", defaults.CoChangeThreshold), func(c CommitRisk) (float64, bool, bool) {
+			return c.CoChange, c.CoChange > defaults.CoChangeThreshold, true
+		}},
+		{RiskMetricTestRatio, fmt.Sprintf("< %.2f test ratio", defaults.TestRatioThreshold), func(c CommitRisk) (float64, bool, bool) {
+			if c.TestRatio == nil {
+				return 0, false, false
+			}
+			return -*c.TestRatio, *c.TestRatio < defaults.TestRatioThreshold, true // Lower ratio = riskier
+		}},
+		{RiskMetricIncidents, fmt.Sprintf(">= %d fixes in 90 days", riskIncidentFlagCount), func(c CommitRisk) (float64, bool, bool) {
+			return float64(c.RecentFixes), c.RecentFixes >= riskIncidentFlagCount, true
+		}},
+	}
+
+	results := make([]MetricPerformance, 0, len(defs))
+	for _, def := range defs {
+		var scores []float64
+		var labels, flagged []bool
+		for _, c := range commits {
+			score, flag, ok := def.value(c)
+			if !ok {
+				continue
+			}
+			scores = append(scores, score)
+			labels = append(labels, c.BugIntroducing)
+			flagged = append(flagged, flag)
+		}
+		results = append(results, MetricPerformance{
+			Metric:    def.name,
+			AUC:       computeAUC(scores, labels),
+			Threshold: def.threshold,
+			Commits:   len(scores),
+			Flagged:   classify(flagged, labels),
+		})
+	}
+	return results
+}
+
+// evaluateConfig scores a config's verdicts: HIGH is the gate's positive prediction,
+// and the LOW < MEDIUM < HIGH ordering is ranked for AUC
+func evaluateConfig(commits []CommitRisk, cfg config.AdaptiveRiskConfig) ConfigPerformance {
+	scores := make([]float64, len(commits))
+	labels := make([]bool, len(commits))
+	flagged := make([]bool, len(commits))
+	high := 0
+	for i, c := range commits {
+		level := c.Levels[cfg.ConfigKey]
+		scores[i] = float64(levelRank(level))
+		labels[i] = c.BugIntroducing
+		flagged[i] = level == metrics.RiskLevelHigh
+		if flagged[i] {
+			high++
+		}
+	}
+
+	perf := ConfigPerformance{
+		ConfigKey:   cfg.ConfigKey,
+		Description: cfg.Description,
+		AUC:         computeAUC(scores, labels),
+		High:        classify(flagged, labels),
+	}
+	if len(commits) > 0 {
+		perf.FlaggedRate = float64(high) / float64(len(commits))
+	}
+	return perf
+}
+
+// classify builds the confusion matrix for predictions against labels
+func classify(predicted, actual []bool) PerformanceMetrics {
+	var m PerformanceMetrics
+	for i := range predicted {
+		switch {
+		case predicted[i] && actual[i]:
+			m.TruePositives++
+		case predicted[i]:
+			m.FalsePositives++
+		case actual[i]:
+			m.FalseNegatives++
+		default:
+			m.TrueNegatives++
+		}
+	}
+
+	if total := len(predicted); total > 0 {
+		m.Accuracy = float64(m.TruePositives+m.TrueNegatives) / float64(total)
+	}
+	if m.TruePositives+m.FalsePositives > 0 {
+		m.Precision = float64(m.TruePositives) / float64(m.TruePositives+m.FalsePositives)
+	}
+	if m.TruePositives+m.FalseNegatives > 0 {
+		m.Recall = float64(m.TruePositives) / float64(m.TruePositives+m.FalseNegatives)
+	}
+	if m.Precision+m.Recall > 0 {
+		m.F1Score = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+	}
+	return m
+}
+
+// computeAUC is the ROC AUC of scores against labels (Mann-Whitney U, ties count half)
+// Returns nil when there are no positives or no negatives.
+func computeAUC(scores []float64, labels []bool) *float64 {
+	idx := make([]int, len(scores))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(a, b int) bool { return scores[idx[a]] < scores[idx[b]] })
+
+	positives, negatives := 0, 0
+	rankSum := 0.0
+	for i := 0; i < len(idx); {
+		j := i
+		for j < len(idx) && scores[idx[j]] == scores[idx[i]] {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // Average 1-based rank of the tied run
+		for _, k := range idx[i:j] {
+			if labels[k] {
+				positives++
+				rankSum += rank
+			} else {
+				negatives++
+			}
+		}
+		i = j
+	}
+
+	if positives == 0 || negatives == 0 {
+		return nil
+	}
+	u := rankSum - float64(positives*(positives+1))/2
+	auc := u / float64(positives*negatives)
+	return &auc
+}
@@ -0,0 +1,49 @@
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FormatRiskReport writes a human-readable risk backtest summary
+func FormatRiskReport(w io.Writer, r *RiskReport) error {
+	fmt.Fprintf(w, "Risk Model Backtest: %s\n", r.Repository)
+	fmt.Fprintf(w, "Test Date: %s\n", r.TestDate)
+	fmt.Fprintf(w, "Commits: %d replayed, %d scored, %d bug-introducing (SZZ confidence >= %.2f)\n",
+		r.CommitsReplayed, r.CommitsEvaluated, r.BugIntroducing, r.Options.MinConfidence)
+	if r.Options.HorizonDays > 0 {
+		fmt.Fprintf(w, "Commits from the last %d days are replayed but not scored\n", r.Options.HorizonDays)
+	}
+
+	fmt.Fprintf(w, "\nPer metric (flagged at default thresholds):\n")
+	fmt.Fprintf(w, "  %-12s %6s %9s %7s %6s  %s\n", "METRIC", "AUC", "PRECISION", "RECALL", "F1", "FLAGGED WHEN")
+	for _, m := range r.Metrics {
+		fmt.Fprintf(w, "  %-12s %6s %8.1f%% %6.1f%% %5.1f%%  %s\n",
+			m.Metric, formatAUC(m.AUC), m.Flagged.Precision*100, m.Flagged.Recall*100, m.Flagged.F1Score*100, m.Threshold)
+	}
+
+	fmt.Fprintf(w, "\nPer config (HIGH = gate fails):\n")
+	fmt.Fprintf(w, "  %-20s %6s %9s %7s %6s %8s\n", "CONFIG", "AUC", "PRECISION", "RECALL", "F1", "FLAGGED")
+	for _, c := range r.Configs {
+		fmt.Fprintf(w, "  %-20s %6s %8.1f%% %6.1f%% %5.1f%% %7.1f%%\n",
+			c.ConfigKey, formatAUC(c.AUC), c.High.Precision*100, c.High.Recall*100, c.High.F1Score*100, c.FlaggedRate*100)
+	}
+
+	_, err := fmt.Fprintf(w, "\nAUC 0.50 is chance; FLAGGED is the share of commits the gate would have blocked.\n")
+	return err
+}
+
+// FormatRiskReportJSON writes the full report, including per-commit scores, as JSON
+func FormatRiskReportJSON(w io.Writer, r *RiskReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+func formatAUC(auc *float64) string {
+	if auc == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f", *auc)
+}
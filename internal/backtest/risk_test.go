@@ -0,0 +1,133 @@
+package backtest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/config"
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+func TestEvaluateRiskModel(t *testing.T) {
+	day := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	commit := func(sha string, days int, files ...database.CommitFileChange) database.CommitFiles {
+		return database.CommitFiles{SHA: sha, AuthorDate: day.AddDate(0, 0, days), Files: files}
+	}
+	change := func(name string, additions, deletions int) database.CommitFileChange {
+		return database.CommitFileChange{Filename: name, Status: "modified", Additions: additions, Deletions: deletions}
+	}
+
+	history := []database.CommitFiles{
+		commit("c1", 0, change("pay.go", 100, 0), change("util.go", 10, 0)),
+		commit("c2", 1, change("pay.go", 5, 0), change("util.go", 5, 0)),
+		commit("c3", 2, change("pay.go", 3, 0)),
+		commit("c4", 3, change("docs.go", 50, 0), change("docs_test.go", 80, 0)),
+		commit("c5", 10, change("pay.go", 1, 1)),
+		commit("c6", 11, change("pay.go", 2, 0)),
+		commit("c7", 12, database.CommitFileChange{Filename: "billing/pay.go", PreviousFilename: "pay.go", Status: "renamed"}),
+		commit("c8", 200, change("late.go", 10, 0)),
+	}
+	pairs := []database.BugIntroductionPair{
+		{FixCommitSHA: "c5", IntroducingCommitSHA: "c3", Confidence: 0.8},
+		{FixCommitSHA: "c5", IntroducingCommitSHA: "c1", Confidence: 0.1}, // Below MinConfidence
+	}
+
+	opts := DefaultRiskOptions()
+	opts.ConfigKeys = []string{config.ConfigKeyDefault, config.ConfigKeyGoBackend}
+	report, err := EvaluateRiskModel("acme/api", history, pairs, opts)
+	if err != nil {
+		t.Fatalf("EvaluateRiskModel: %v", err)
+	}
+
+	if report.CommitsReplayed != 8 || report.CommitsEvaluated != 7 {
+		t.Errorf("expected 8 replayed and 7 scored (c8 is inside the horizon), got %d and %d",
+			report.CommitsReplayed, report.CommitsEvaluated)
+	}
+	if report.BugIntroducing != 1 {
+		t.Errorf("expected only c3 to be bug-introducing, got %d", report.BugIntroducing)
+	}
+
+	bySHA := make(map[string]CommitRisk)
+	for _, c := range report.Commits {
+		bySHA[c.SHA] = c
+	}
+
+	c3 := bySHA["c3"]
+	if !c3.BugIntroducing || c3.SZZConfidence != 0.8 {
+		t.Errorf("expected c3 labelled with confidence 0.8, got %+v", c3)
+	}
+	if c3.Coupling != 1 || c3.CoChange != 1.0 {
+		t.Errorf("expected pay.go coupled to util.go before c3, got coupling %d co-change %.2f", c3.Coupling, c3.CoChange)
+	}
+	if c3.TestRatio == nil || *c3.TestRatio >= 0.1 {
+		t.Errorf("expected a low test ratio for untested pay.go, got %v", c3.TestRatio)
+	}
+	if c3.RecentFixes != 0 {
+		t.Errorf("the fix for c3 comes later and must not be visible, got %d", c3.RecentFixes)
+	}
+	if bySHA["c6"].RecentFixes != 1 {
+		t.Errorf("expected c6 to see the c5 fix, got %d", bySHA["c6"].RecentFixes)
+	}
+	if bySHA["c7"].RecentFixes != 1 || bySHA["c7"].Coupling != 1 {
+		t.Errorf("expected the renamed file to keep its history, got %+v", bySHA["c7"])
+	}
+	if bySHA["c4"].TestRatio == nil {
+		t.Error("expected a test ratio for docs.go")
+	}
+
+	for _, c := range report.Commits {
+		if len(c.Levels) != 2 {
+			t.Errorf("expected a verdict per config for %s, got %v", c.SHA, c.Levels)
+		}
+	}
+	if len(report.Metrics) != 4 || len(report.Configs) != 2 {
+		t.Fatalf("expected 4 metrics and 2 configs, got %d and %d", len(report.Metrics), len(report.Configs))
+	}
+	for _, m := range report.Metrics {
+		if m.AUC == nil {
+			t.Errorf("expected an AUC for %s", m.Metric)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := FormatRiskReport(&buf, report); err != nil {
+		t.Fatalf("FormatRiskReport: %v", err)
+	}
+	for _, want := range []string{"acme/api", "co_change", "go_backend"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in report:\n%s", want, buf.String())
+		}
+	}
+
+	if _, err := EvaluateRiskModel("acme/api", history, pairs, RiskOptions{ConfigKeys: []string{"nope"}}); err == nil {
+		t.Error("expected an error for an unknown config")
+	}
+}
+
+func TestComputeAUC(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		labels []bool
+		want   float64
+	}{
+		{"perfect", []float64{0.1, 0.2, 0.8, 0.9}, []bool{false, false, true, true}, 1.0},
+		{"inverted", []float64{0.9, 0.8, 0.2, 0.1}, []bool{false, false, true, true}, 0.0},
+		{"all tied", []float64{1, 1, 1, 1}, []bool{false, true, false, true}, 0.5},
+		{"one swap", []float64{1, 2, 3, 4}, []bool{false, true, false, true}, 0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeAUC(tt.scores, tt.labels)
+			if got == nil || *got != tt.want {
+				t.Errorf("computeAUC() = %v, want %.2f", got, tt.want)
+			}
+		})
+	}
+
+	if got := computeAUC([]float64{1, 2}, []bool{false, false}); got != nil {
+		t.Errorf("expected nil AUC without positives, got %v", *got)
+	}
+}
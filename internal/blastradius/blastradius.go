@@ -0,0 +1,329 @@
+// Package blastradius computes how far a change can propagate through the graph.
+// Starting from the changed files it follows static IMPORTS and CALLS edges backwards
+// (to the code that depends on them) and block-level CO_CHANGES_WITH edges in either
+// direction, hop by hop up to a configurable depth. Every reached file carries a weight that
+// decays with distance and edge strength, and reached code that looks like a public entry
+// point (HTTP handler, CLI command, exported API) is reported separately.
+package blastradius
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Edge types followed from a changed file to the code it affects
+const (
+	ViaImports  = "IMPORTS"
+	ViaCalls    = "CALLS"
+	ViaCoChange = "CO_CHANGES_WITH"
+)
+
+// QueryExecutor runs read queries against the graph
+// Implemented by graph.Client and the MCP server's LocalGraphClient.
+type QueryExecutor interface {
+	ExecuteQuery(ctx context.Context, query string, params map[string]any) ([]map[string]any, error)
+}
+
+// Cache stores computed results per repository and commit
+// Values are opaque JSON; Get reports false on a miss.
+type Cache interface {
+	Get(ctx context.Context, repoID int64, commitSHA, key string) ([]byte, bool, error)
+	Put(ctx context.Context, repoID int64, commitSHA, key string, value []byte) error
+}
+
+// Options control traversal depth and weighting
+type Options struct {
+	MaxDepth    int                // Hops to follow from the changed files
+	Decay       float64            // Weight multiplier applied per hop after the first
+	MinWeight   float64            // Dependents below this weight are dropped and not expanded
+	MinCoupling float64            // Minimum coupling_rate for CO_CHANGES_WITH edges
+	EdgeWeights map[string]float64 // Weight of each edge type
+}
+
+// DefaultOptions follows dependencies three hops out, halving their weight per extra hop
+// Co-change is a weaker signal than a static dependency, so it starts at 0.6 and is further
+// scaled by the edge's coupling rate.
+func DefaultOptions() Options {
+	return Options{
+		MaxDepth:    3,
+		Decay:       0.5,
+		MinWeight:   0.05,
+		MinCoupling: 0.3,
+		EdgeWeights: map[string]float64{
+			ViaImports:  1.0,
+			ViaCalls:    1.0,
+			ViaCoChange: 0.6,
+		},
+	}
+}
+
+// Dependent is a file reached from the change
+type Dependent struct {
+	File   string   `json:"file"`
+	Depth  int      `json:"depth"`            // Hops from the nearest changed file
+	Weight float64  `json:"weight"`           // Strongest path weight, 1.0 for a direct static dependency
+	Via    string   `json:"via"`              // Edge type of the strongest path's last hop
+	From   string   `json:"from"`             // File this one was reached from on that path
+	Blocks []string `json:"blocks,omitempty"` // Calling or co-changing blocks in this file
+}
+
+// Result is the blast radius of a set of changed files at one commit
+type Result struct {
+	CommitSHA   string       `json:"commit_sha,omitempty"`
+	Files       []string     `json:"files"`
+	MaxDepth    int          `json:"max_depth"`
+	Direct      []Dependent  `json:"direct"`
+	Transitive  []Dependent  `json:"transitive"`
+	EntryPoints []EntryPoint `json:"entry_points"`
+	Score       float64      `json:"score"` // Sum of dependent weights
+	Cached      bool         `json:"cached"`
+}
+
+// TotalDependents returns the number of direct and transitive dependents
+func (r *Result) TotalDependents() int {
+	return len(r.Direct) + len(r.Transitive)
+}
+
+// Summary describes the result in one line, e.g. "can reach 14 endpoints (3 direct, 9 transitive dependents)"
+func (r *Result) Summary() string {
+	endpoints := "endpoints"
+	if len(r.EntryPoints) == 1 {
+		endpoints = "endpoint"
+	}
+	return fmt.Sprintf("can reach %d %s (%d direct, %d transitive dependents)",
+		len(r.EntryPoints), endpoints, len(r.Direct), len(r.Transitive))
+}
+
+// Engine computes blast radii, optionally caching them per commit
+type Engine struct {
+	graph    QueryExecutor
+	cache    Cache
+	repoPath string
+	opts     Options
+}
+
+// NewEngine creates an engine; cache may be nil, and repoPath may be empty, in which case
+// entry points are detected from names and paths only (no decorator/annotation scan)
+func NewEngine(graph QueryExecutor, cache Cache, repoPath string, opts Options) *Engine {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = DefaultOptions().MaxDepth
+	}
+	if opts.EdgeWeights == nil {
+		opts.EdgeWeights = DefaultOptions().EdgeWeights
+	}
+	return &Engine{graph: graph, cache: cache, repoPath: repoPath, opts: opts}
+}
+
+// Compute returns the blast radius of files at commitSHA
+// repoID scopes graph queries; 0 matches nodes of any repository. Results are only cached
+// when both repoID and commitSHA are known.
+func (e *Engine) Compute(ctx context.Context, repoID int64, commitSHA string, files []string) (*Result, error) {
+	files = dedupeSorted(files)
+	key := e.cacheKey(files)
+	useCache := e.cache != nil && repoID != 0 && commitSHA != ""
+
+	if useCache {
+		data, ok, err := e.cache.Get(ctx, repoID, commitSHA, key)
+		if err != nil {
+			slog.Warn("blast radius cache read failed", "error", err)
+		} else if ok {
+			var cached Result
+			if err := json.Unmarshal(data, &cached); err == nil {
+				cached.Cached = true
+				return &cached, nil
+			}
+		}
+	}
+
+	result, err := e.traverse(ctx, repoID, files)
+	if err != nil {
+		return nil, err
+	}
+	result.CommitSHA = commitSHA
+
+	if useCache {
+		if data, err := json.Marshal(result); err == nil {
+			if err := e.cache.Put(ctx, repoID, commitSHA, key, data); err != nil {
+				slog.Warn("blast radius cache write failed", "error", err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// edgeQueries find the neighbours of a frontier of files, one query per edge type
+// Each row has source (frontier file), target (reached file), block, block_type and strength.
+var edgeQueries = map[string]string{
+	ViaImports: `
+		MATCH (f:File)<-[:IMPORTS]-(dep:File)
+		WHERE f.path IN $paths AND ($repoID = 0 OR f.repo_id = $repoID)
+		RETURN f.path AS source, dep.path AS target, null AS block, null AS block_type, 1.0 AS strength
+	`,
+	ViaCalls: `
+		MATCH (b:CodeBlock)<-[:CALLS]-(caller:CodeBlock)
+		WHERE b.canonical_file_path IN $paths AND ($repoID = 0 OR b.repo_id = $repoID)
+		RETURN b.canonical_file_path AS source, caller.canonical_file_path AS target,
+		       caller.block_name AS block, caller.block_type AS block_type, 1.0 AS strength
+	`,
+	ViaCoChange: `
+		MATCH (b:CodeBlock)-[r:CO_CHANGES_WITH]-(other:CodeBlock)
+		WHERE b.canonical_file_path IN $paths AND ($repoID = 0 OR b.repo_id = $repoID)
+		  AND r.coupling_rate >= $minCoupling
+		RETURN b.canonical_file_path AS source, other.canonical_file_path AS target,
+		       other.block_name AS block, other.block_type AS block_type, r.coupling_rate AS strength
+	`,
+}
+
+// edgeOrder fixes query order so ties between equally weighted paths resolve the same way
+var edgeOrder = []string{ViaImports, ViaCalls, ViaCoChange}
+
+// traverse runs a breadth-first search outwards from the changed files
+func (e *Engine) traverse(ctx context.Context, repoID int64, files []string) (*Result, error) {
+	changed := make(map[string]bool, len(files))
+	weights := make(map[string]float64, len(files))
+	for _, f := range files {
+		changed[f] = true
+		weights[f] = 1.0
+	}
+
+	reached := make(map[string]*Dependent)
+	blocks := make(map[string]map[string]string) // File -> block name -> block type
+	frontier := files
+
+	for depth := 1; depth <= e.opts.MaxDepth && len(frontier) > 0; depth++ {
+		hopDecay := 1.0
+		if depth > 1 {
+			hopDecay = e.opts.Decay
+		}
+
+		var next []string
+		for _, via := range edgeOrder {
+			rows, err := e.graph.ExecuteQuery(ctx, edgeQueries[via], map[string]any{
+				"paths":       frontier,
+				"repoID":      repoID,
+				"minCoupling": e.opts.MinCoupling,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("blast radius %s query failed: %w", via, err)
+			}
+
+			for _, row := range rows {
+				source, _ := row["source"].(string)
+				target, _ := row["target"].(string)
+				if target == "" || changed[target] {
+					continue
+				}
+				weight := weights[source] * e.opts.EdgeWeights[via] * toFloat(row["strength"]) * hopDecay
+				if weight < e.opts.MinWeight {
+					continue
+				}
+
+				if block, _ := row["block"].(string); block != "" {
+					if blocks[target] == nil {
+						blocks[target] = make(map[string]string)
+					}
+					blockType, _ := row["block_type"].(string)
+					blocks[target][block] = blockType
+				}
+
+				dep, seen := reached[target]
+				if !seen {
+					dep = &Dependent{File: target, Depth: depth}
+					reached[target] = dep
+					next = append(next, target)
+				}
+				// Files keep the depth they were first reached at; longer paths never override it
+				if dep.Depth == depth && weight > dep.Weight {
+					dep.Weight, dep.Via, dep.From = weight, via, source
+					weights[target] = weight
+				}
+			}
+		}
+		frontier = next
+	}
+
+	result := &Result{Files: files, MaxDepth: e.opts.MaxDepth}
+	for _, dep := range reached {
+		dep.Blocks = sortedBlockNames(blocks[dep.File])
+		result.Score += dep.Weight
+		if dep.Depth == 1 {
+			result.Direct = append(result.Direct, *dep)
+		} else {
+			result.Transitive = append(result.Transitive, *dep)
+		}
+	}
+	sortDependents(result.Direct)
+	sortDependents(result.Transitive)
+
+	detector := newEntryPointDetector(e.repoPath)
+	for _, dep := range append(append([]Dependent{}, result.Direct...), result.Transitive...) {
+		result.EntryPoints = append(result.EntryPoints, detector.detect(dep, blocks[dep.File])...)
+	}
+	sort.SliceStable(result.EntryPoints, func(i, j int) bool {
+		return result.EntryPoints[i].Weight > result.EntryPoints[j].Weight
+	})
+	return result, nil
+}
+
+// cacheKey identifies a file set and the options it was computed with
+func (e *Engine) cacheKey(files []string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "d=%d decay=%g min=%g coupling=%g", e.opts.MaxDepth, e.opts.Decay, e.opts.MinWeight, e.opts.MinCoupling)
+	for _, via := range edgeOrder {
+		fmt.Fprintf(h, " %s=%g", via, e.opts.EdgeWeights[via])
+	}
+	h.Write([]byte("\n" + strings.Join(files, "\n")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func sortDependents(deps []Dependent) {
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Weight != deps[j].Weight {
+			return deps[i].Weight > deps[j].Weight
+		}
+		return deps[i].File < deps[j].File
+	})
+}
+
+func sortedBlockNames(blocks map[string]string) []string {
+	if len(blocks) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(blocks))
+	for name := range blocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func dedupeSorted(files []string) []string {
+	seen := make(map[string]bool, len(files))
+	var out []string
+	for _, f := range files {
+		if f != "" && !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	}
+	return 0
+}
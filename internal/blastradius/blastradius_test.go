@@ -0,0 +1,195 @@
+package blastradius
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeGraph answers edge queries from an in-memory edge list
+type fakeGraph struct {
+	edges   map[string][]map[string]any // Edge type -> rows
+	queries int
+}
+
+func (g *fakeGraph) ExecuteQuery(ctx context.Context, query string, params map[string]any) ([]map[string]any, error) {
+	g.queries++
+	frontier := make(map[string]bool)
+	for _, p := range params["paths"].([]string) {
+		frontier[p] = true
+	}
+
+	var via string
+	switch {
+	case strings.Contains(query, ":IMPORTS"):
+		via = ViaImports
+	case strings.Contains(query, ":CALLS"):
+		via = ViaCalls
+	default:
+		via = ViaCoChange
+	}
+
+	var rows []map[string]any
+	for _, row := range g.edges[via] {
+		if frontier[row["source"].(string)] {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func edge(source, target, block string, strength float64) map[string]any {
+	row := map[string]any{"source": source, "target": target, "strength": strength}
+	if block != "" {
+		row["block"] = block
+		row["block_type"] = "function"
+	}
+	return row
+}
+
+// memoryCache is a Cache backed by a map
+type memoryCache map[string][]byte
+
+func (c memoryCache) Get(ctx context.Context, repoID int64, commitSHA, key string) ([]byte, bool, error) {
+	v, ok := c[commitSHA+key]
+	return v, ok, nil
+}
+
+func (c memoryCache) Put(ctx context.Context, repoID int64, commitSHA, key string, value []byte) error {
+	c[commitSHA+key] = value
+	return nil
+}
+
+func newTestGraph() *fakeGraph {
+	// store.go <- service.go <- api/handlers.go <- cmd/server/main.go, plus a co-change partner
+	return &fakeGraph{edges: map[string][]map[string]any{
+		ViaImports: {
+			edge("billing/store.go", "billing/service.go", "", 1),
+			edge("billing/service.go", "api/handlers.go", "", 1),
+			edge("api/handlers.go", "cmd/server/main.go", "", 1),
+		},
+		ViaCalls: {
+			edge("billing/service.go", "api/handlers.go", "CreateInvoiceHandler", 1),
+		},
+		ViaCoChange: {
+			edge("billing/store.go", "billing/migrations.go", "Migrate", 0.5),
+		},
+	}}
+}
+
+func TestComputeDepthAndWeights(t *testing.T) {
+	g := newTestGraph()
+	opts := DefaultOptions()
+	result, err := NewEngine(g, nil, "", opts).Compute(context.Background(), 1, "", []string{"billing/store.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Direct) != 2 || len(result.Transitive) != 2 {
+		t.Fatalf("got %d direct, %d transitive; want 2, 2 (%+v)", len(result.Direct), len(result.Transitive), result)
+	}
+	if result.Direct[0].File != "billing/service.go" || result.Direct[0].Weight != 1.0 {
+		t.Errorf("strongest direct dependent = %+v, want billing/service.go at 1.0", result.Direct[0])
+	}
+	// Co-change: 0.6 edge weight * 0.5 coupling rate
+	if result.Direct[1].File != "billing/migrations.go" || result.Direct[1].Weight != 0.3 || result.Direct[1].Via != ViaCoChange {
+		t.Errorf("co-change dependent = %+v, want billing/migrations.go at 0.3 via %s", result.Direct[1], ViaCoChange)
+	}
+	if dep := result.Transitive[1]; dep.File != "cmd/server/main.go" || dep.Depth != 3 || dep.Weight != 0.25 {
+		t.Errorf("deepest dependent = %+v, want cmd/server/main.go at depth 3, weight 0.25", dep)
+	}
+
+	kinds := make(map[string]EntryPointKind)
+	for _, ep := range result.EntryPoints {
+		kinds[ep.File+":"+ep.Block] = ep.Kind
+	}
+	if kinds["api/handlers.go:CreateInvoiceHandler"] != EntryHTTP {
+		t.Errorf("expected CreateInvoiceHandler as an HTTP entry point, got %+v", result.EntryPoints)
+	}
+	if kinds["cmd/server/main.go:"] != EntryCLI {
+		t.Errorf("expected cmd/server/main.go as a CLI entry point, got %+v", result.EntryPoints)
+	}
+
+	// Depth limit stops before the command
+	opts.MaxDepth = 2
+	shallow, err := NewEngine(g, nil, "", opts).Compute(context.Background(), 1, "", []string{"billing/store.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shallow.TotalDependents() != 3 {
+		t.Errorf("depth 2 reached %d files, want 3", shallow.TotalDependents())
+	}
+}
+
+func TestComputeCachesPerCommit(t *testing.T) {
+	g := newTestGraph()
+	cache := memoryCache{}
+	engine := NewEngine(g, cache, "", DefaultOptions())
+
+	first, err := engine.Compute(context.Background(), 1, "abc123", []string{"billing/store.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	queries := g.queries
+
+	second, err := engine.Compute(context.Background(), 1, "abc123", []string{"billing/store.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.queries != queries || !second.Cached || first.Cached {
+		t.Errorf("expected second computation to be served from cache (queries %d -> %d)", queries, g.queries)
+	}
+	if second.TotalDependents() != first.TotalDependents() || len(second.EntryPoints) != len(first.EntryPoints) {
+		t.Error("cached result differs from computed result")
+	}
+
+	if _, err := engine.Compute(context.Background(), 1, "def456", []string{"billing/store.go"}); err != nil {
+		t.Fatal(err)
+	}
+	if g.queries == queries {
+		t.Error("a new commit should not be served from the cache")
+	}
+}
+
+func TestEntryPointDecorators(t *testing.T) {
+	root := t.TempDir()
+	src := `from flask import Flask
+import click
+
+app = Flask(__name__)
+
+
+@app.post("/invoices")
+def create_invoice():
+    return charge()
+
+
+@click.command()
+def reconcile():
+    charge()
+
+
+def charge():
+    pass
+`
+	if err := os.WriteFile(filepath.Join(root, "app.py"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := newEntryPointDetector(root)
+	dep := Dependent{File: "app.py", Depth: 1, Weight: 1}
+	found := d.detect(dep, map[string]string{"create_invoice": "function", "reconcile": "function", "charge": "function"})
+
+	got := make(map[string]EntryPointKind)
+	for _, ep := range found {
+		got[ep.Block] = ep.Kind
+	}
+	if got["create_invoice"] != EntryHTTP || got["reconcile"] != EntryCLI {
+		t.Errorf("got %v, want create_invoice=http, reconcile=cli", got)
+	}
+	if _, ok := got["charge"]; ok {
+		t.Error("undecorated helper should not be an entry point")
+	}
+}
@@ -0,0 +1,229 @@
+package blastradius
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// EntryPointKind classifies how external callers reach code
+type EntryPointKind string
+
+const (
+	EntryHTTP        EntryPointKind = "http"         // HTTP route or handler
+	EntryCLI         EntryPointKind = "cli"          // Command-line entry point
+	EntryExportedAPI EntryPointKind = "exported_api" // Public API of a library package
+)
+
+// EntryPoint is reached code that external callers use directly
+type EntryPoint struct {
+	File   string         `json:"file"`
+	Block  string         `json:"block,omitempty"` // Empty when the whole file is the entry point
+	Kind   EntryPointKind `json:"kind"`
+	Reason string         `json:"reason"` // Heuristic that matched, e.g. "decorator @app.post"
+	Depth  int            `json:"depth"`
+	Weight float64        `json:"weight"`
+}
+
+var (
+	// Python (Flask, FastAPI, Django REST) and TypeScript (NestJS) route decorators
+	httpDecoratorPattern = regexp.MustCompile(`^@(?:[\w.]+\.)?(route|get|post|put|delete|patch|head|options|websocket|api_view|Get|Post|Put|Delete|Patch|All)\b`)
+	// Java/Kotlin (Spring, JAX-RS) annotations
+	httpAnnotationPattern = regexp.MustCompile(`^@(RequestMapping|GetMapping|PostMapping|PutMapping|DeleteMapping|PatchMapping|GET|POST|PUT|DELETE|PATCH|Path)\b`)
+	// click and typer commands
+	cliDecoratorPattern = regexp.MustCompile(`^@(?:[\w.]+\.)?(command|group)\b`)
+
+	// Handler signatures of net/http, gin, echo, fiber, and express-style (req, res) functions
+	httpSignaturePattern = regexp.MustCompile(`http\.ResponseWriter|\*gin\.Context|echo\.Context|\*fiber\.Ctx|\(\s*req\b[^)]*,\s*res\b`)
+	cliSignaturePattern  = regexp.MustCompile(`\*cobra\.Command|\*cli\.Context|urfave/cli`)
+
+	// Route registration in a file (express, koa, fastify, chi, gorilla, net/http)
+	routeRegistrationPattern = regexp.MustCompile(`\b(?:app|router|r|mux|server|api)\.(?:get|post|put|delete|patch|route|HandleFunc|Handle|Get|Post|Put|Delete|Patch)\(\s*["'\x60]/`)
+
+	// Next.js App Router handlers are named after HTTP methods
+	httpMethodNames = map[string]bool{"GET": true, "POST": true, "PUT": true, "DELETE": true, "PATCH": true, "HEAD": true, "OPTIONS": true}
+)
+
+// entryPointDetector applies naming, path and (when the checkout is available) decorator
+// heuristics, reading each file at most once
+type entryPointDetector struct {
+	repoPath string
+	sources  map[string][]string
+}
+
+func newEntryPointDetector(repoPath string) *entryPointDetector {
+	return &entryPointDetector{repoPath: repoPath, sources: make(map[string][]string)}
+}
+
+// detect returns the entry points in a reached file
+// Reached blocks are classified individually; when none of them is an entry point the file
+// itself may still be one (a routes module, a CLI main package).
+func (d *entryPointDetector) detect(dep Dependent, blocks map[string]string) []EntryPoint {
+	var found []EntryPoint
+	for _, name := range sortedBlockNames(blocks) {
+		if kind, reason := d.classifyBlock(dep.File, name); kind != "" {
+			found = append(found, EntryPoint{
+				File: dep.File, Block: name, Kind: kind, Reason: reason, Depth: dep.Depth, Weight: dep.Weight,
+			})
+		}
+	}
+	if len(found) > 0 {
+		return found
+	}
+
+	if kind, reason := d.classifyFile(dep.File); kind != "" {
+		found = append(found, EntryPoint{
+			File: dep.File, Kind: kind, Reason: reason, Depth: dep.Depth, Weight: dep.Weight,
+		})
+	}
+	return found
+}
+
+// classifyBlock checks a block's decorators, signature and name
+func (d *entryPointDetector) classifyBlock(file, name string) (EntryPointKind, string) {
+	if lines := d.source(file); lines != nil {
+		if def := findDefinition(lines, name); def >= 0 {
+			for i := def - 1; i >= 0; i-- {
+				decorator := strings.TrimSpace(lines[i])
+				if !strings.HasPrefix(decorator, "@") {
+					break
+				}
+				switch {
+				case httpDecoratorPattern.MatchString(decorator), httpAnnotationPattern.MatchString(decorator):
+					return EntryHTTP, "decorator " + firstToken(decorator)
+				case cliDecoratorPattern.MatchString(decorator):
+					return EntryCLI, "decorator " + firstToken(decorator)
+				}
+			}
+			switch {
+			case httpSignaturePattern.MatchString(lines[def]):
+				return EntryHTTP, "handler signature"
+			case cliSignaturePattern.MatchString(lines[def]):
+				return EntryCLI, "command signature"
+			}
+		}
+	}
+
+	lower := strings.ToLower(name)
+	base := path.Base(file)
+	switch {
+	case name == "ServeHTTP":
+		return EntryHTTP, "implements http.Handler"
+	case httpMethodNames[name] && strings.HasPrefix(base, "route."):
+		return EntryHTTP, "route handler " + name
+	case strings.HasSuffix(name, "Handler") || strings.HasPrefix(name, "Handle") || strings.HasPrefix(lower, "handle_"):
+		return EntryHTTP, "handler name"
+	case name == "main" && path.Ext(file) == ".go":
+		return EntryCLI, "main function"
+	case isExportedGo(file, name):
+		return EntryExportedAPI, "exported Go identifier"
+	}
+	return "", ""
+}
+
+// classifyFile checks whether a file as a whole is an entry point
+func (d *entryPointDetector) classifyFile(file string) (EntryPointKind, string) {
+	base := path.Base(file)
+	stem := strings.TrimSuffix(base, path.Ext(base))
+
+	switch {
+	case strings.Contains("/"+file, "/pages/api/"):
+		return EntryHTTP, "Next.js API route"
+	case stem == "route" && isUnder(file, "app"):
+		return EntryHTTP, "Next.js route handler"
+	case base == "views.py" || base == "urls.py":
+		return EntryHTTP, "Django " + stem
+	case isUnder(file, "handlers") || isUnder(file, "controllers") || isUnder(file, "routes"):
+		return EntryHTTP, "handler directory"
+	case isUnder(file, "cmd") || isUnder(file, "bin") || base == "__main__.py" || base == "cli.py" || base == "manage.py":
+		return EntryCLI, "command directory"
+	}
+
+	if lines := d.source(file); lines != nil {
+		for _, line := range lines {
+			if routeRegistrationPattern.MatchString(line) {
+				return EntryHTTP, "registers routes"
+			}
+		}
+	}
+
+	switch {
+	case base == "__init__.py" && !isUnder(file, "tests"):
+		return EntryExportedAPI, "package __init__"
+	case (stem == "index" || stem == "main") && isPackageRoot(file):
+		return EntryExportedAPI, "package entry module"
+	}
+	return "", ""
+}
+
+// source returns a file's lines, or nil when the checkout is unavailable
+func (d *entryPointDetector) source(file string) []string {
+	if d.repoPath == "" {
+		return nil
+	}
+	if lines, ok := d.sources[file]; ok {
+		return lines
+	}
+	var lines []string
+	if content, err := os.ReadFile(filepath.Join(d.repoPath, filepath.FromSlash(file))); err == nil {
+		lines = strings.Split(string(content), "\n")
+	}
+	d.sources[file] = lines
+	return lines
+}
+
+// findDefinition returns the line defining a function, method or class, or -1
+func findDefinition(lines []string, name string) int {
+	quoted := regexp.QuoteMeta(name)
+	def := regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?(?:def|func|function|class)\s+(?:\([^)]*\)\s*)?` + quoted + `\b`)
+	method := regexp.MustCompile(`^\s*(?:(?:public|private|protected|static|async|final|override)\s+)*(?:[\w<>\[\],.?]+\s+)?` + quoted + `\s*\(`)
+
+	for i, line := range lines {
+		if def.MatchString(line) {
+			return i
+		}
+	}
+	// Class methods without a keyword (TypeScript, Java): only trust them when annotated
+	for i, line := range lines {
+		if i > 0 && method.MatchString(line) && strings.HasPrefix(strings.TrimSpace(lines[i-1]), "@") {
+			return i
+		}
+	}
+	return -1
+}
+
+// isExportedGo reports exported Go functions in importable (non-internal, non-command) packages
+func isExportedGo(file, name string) bool {
+	if path.Ext(file) != ".go" || isUnder(file, "internal") || isUnder(file, "cmd") {
+		return false
+	}
+	// Methods are named by their short name; both forms start with the exported identifier
+	first := []rune(name)
+	return len(first) > 0 && unicode.IsUpper(first[0])
+}
+
+// isUnder reports whether any directory of file is named dir
+func isUnder(file, dir string) bool {
+	return strings.HasPrefix(file, dir+"/") || strings.Contains(file, "/"+dir+"/")
+}
+
+// isPackageRoot reports index/main modules at the root of a package or its src directory
+func isPackageRoot(file string) bool {
+	dir := path.Dir(file)
+	if dir == "." || dir == "src" || dir == "lib" {
+		return true
+	}
+	parent := path.Base(dir)
+	return (parent == "src" || parent == "lib") && strings.Count(dir, "/") <= 2
+}
+
+// firstToken returns a decorator without its arguments
+func firstToken(decorator string) string {
+	if i := strings.IndexAny(decorator, "( "); i > 0 {
+		return decorator[:i]
+	}
+	return decorator
+}
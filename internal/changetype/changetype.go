@@ -0,0 +1,190 @@
+// Package changetype classifies the changes in a diff by what they do to a file —
+// formatting, documentation, a pure move, a mechanical refactor, a dependency bump, a
+// config or test change, generated code, or a behavioural change — so risk assessment
+// can skip or scale down changes that cannot alter behaviour.
+package changetype
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Kind is the classification of one file's change
+type Kind string
+
+const (
+	Formatting    Kind = "formatting"    // Whitespace or comments only
+	Documentation Kind = "documentation" // Docs files
+	Rename        Kind = "rename"        // Pure rename or move, possibly with mechanical edits
+	Refactor      Kind = "refactor"      // Token stream identical modulo consistently renamed identifiers
+	Dependency    Kind = "dependency"    // Lockfiles and dependency manifests
+	Config        Kind = "config"        // Configuration, CI and build files
+	Test          Kind = "test"          // Test sources and fixtures
+	Generated     Kind = "generated"     // Generated or vendored code
+	Behavioral    Kind = "behavioral"    // Anything that may change behaviour
+)
+
+// Action is how risk assessment should treat a change
+type Action string
+
+const (
+	ActionSkip   Action = "skip"   // No assessment needed
+	ActionReduce Action = "reduce" // Assess, then lower the risk one level
+	ActionFull   Action = "full"   // Assess normally
+)
+
+// Classification is the verdict for one file
+type Classification struct {
+	Path       string  `json:"path"`
+	OldPath    string  `json:"old_path,omitempty"`
+	Kind       Kind    `json:"kind"`
+	Action     Action  `json:"action"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+}
+
+// String renders the classification for output, e.g. "refactor (renamed 2 identifiers)"
+func (c Classification) String() string {
+	return fmt.Sprintf("%s (%s)", c.Kind, c.Reason)
+}
+
+// Reader returns a file's content, or nil if the file does not exist
+type Reader func(path string) ([]byte, error)
+
+// Source reads whole files on either side of the change, for the checks a diff alone
+// cannot answer: a modified file's generated-code header, and whether a renamed
+// identifier is still used outside the changed lines. Without a reader those checks
+// fail safe and the file is assessed in full.
+type Source struct {
+	Before Reader // Base revision (HEAD, or the commit's parent)
+	After  Reader // Revision with the change applied (working tree, index or commit)
+}
+
+func (r Reader) read(p string) []byte {
+	if r == nil {
+		return nil
+	}
+	content, err := r(p)
+	if err != nil {
+		return nil
+	}
+	return content
+}
+
+// ClassifyDiff classifies every file in a git diff
+func ClassifyDiff(diff string, src Source) []Classification {
+	files := ParseDiff(diff)
+	out := make([]Classification, 0, len(files))
+	for _, fc := range files {
+		out = append(out, Classify(fc, src))
+	}
+	return out
+}
+
+// Classify classifies one file's change
+// Rules run from the most to the least specific: a generated test fixture is generated,
+// and a renamed config file is config.
+func Classify(fc FileChange, src Source) Classification {
+	c := Classification{Path: fc.Path, OldPath: fc.OldPath}
+	set := func(kind Kind, action Action, confidence float64, reason string) Classification {
+		c.Kind, c.Action, c.Confidence, c.Reason = kind, action, confidence, reason
+		return c
+	}
+
+	switch {
+	case isGenerated(fc, src.Before):
+		return set(Generated, ActionSkip, 0.9, "generated or vendored code")
+	case isDependencyFile(fc):
+		return set(Dependency, ActionReduce, 0.95, "dependency versions only")
+	case isDocFile(fc.Path):
+		return set(Documentation, ActionSkip, 0.9, "documentation only")
+	case isTestFile(fc.Path):
+		return set(Test, ActionReduce, 0.9, "test code only")
+	}
+
+	if fc.Status == "renamed" && len(fc.Added) == 0 && len(fc.Removed) == 0 {
+		return set(Rename, ActionSkip, 0.99, fmt.Sprintf("moved from %s without edits", fc.OldPath))
+	}
+	if isConfigFile(fc.Path) {
+		return set(Config, ActionFull, 0.85, "configuration change")
+	}
+	if fc.Binary || fc.Status == "added" || fc.Status == "deleted" {
+		return set(Behavioral, ActionFull, 0.8, fmt.Sprintf("file %s", fc.Status))
+	}
+
+	oldFile, newFile := src.Before.read(fc.BasePath()), src.After.read(fc.Path)
+	syn := syntaxOf(fc.Path, oldFile, newFile)
+	before, after := tokenize(fc.Removed, syn), tokenize(fc.Added, syn)
+	if sameTokens(before, after) {
+		if fc.Status == "renamed" {
+			return set(Rename, ActionSkip, 0.95, fmt.Sprintf("moved from %s with formatting edits", fc.OldPath))
+		}
+		return set(Formatting, ActionSkip, 0.95, "whitespace or comments only")
+	}
+	if renames, ok := renamedTokens(before, after); ok && !persistedNames(fc.Path) &&
+		renamedLocally(renames, oldFile, newFile, syn) {
+		if fc.Status == "renamed" {
+			return set(Rename, ActionReduce, 0.9, fmt.Sprintf("moved from %s, renamed %d identifier(s)", fc.OldPath, len(renames)))
+		}
+		return set(Refactor, ActionReduce, 0.85, fmt.Sprintf("renamed %d identifier(s), logic unchanged", len(renames)))
+	}
+	return set(Behavioral, ActionFull, 0.8, "code logic changed")
+}
+
+// persistedNames reports languages whose identifiers name stored data (tables and
+// columns), where renaming one is a schema change rather than a refactor
+func persistedNames(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".sql", ".ddl", ".prisma":
+		return true
+	}
+	return false
+}
+
+// renamedLocally reports whether each rename is of a name the file owns and covers the
+// whole file: the old name is declared in the file and not exported or reached through
+// a selector (and likewise the new one), the old name is gone after the change, and the
+// new name did not exist before it. Otherwise the change points code at a different,
+// existing identifier, leaves references to the old one, or breaks callers the file
+// cannot show. Without both versions of the file this cannot be shown, and it reports false.
+func renamedLocally(renames map[string]string, oldFile, newFile []byte, syn syntax) bool {
+	if oldFile == nil || newFile == nil {
+		return false
+	}
+	oldTokens := tokenize(strings.Split(string(oldFile), "\n"), syn)
+	newTokens := tokenize(strings.Split(string(newFile), "\n"), syn)
+	oldIDs, newIDs := identifiers(oldTokens), identifiers(newTokens)
+	for from, to := range renames {
+		if newIDs[from] || oldIDs[to] || !localName(from, oldTokens, syn) || !localName(to, newTokens, syn) {
+			return false
+		}
+	}
+	return true
+}
+
+// precedence orders kinds from the riskiest, used to label a change set by its riskiest file
+var precedence = []Kind{Behavioral, Config, Dependency, Refactor, Rename, Test, Generated, Documentation, Formatting}
+
+// Dominant returns the riskiest kind among classifications, or "" for none
+func Dominant(classes []Classification) Kind {
+	present := make(map[Kind]bool, len(classes))
+	for _, c := range classes {
+		present[c.Kind] = true
+	}
+	for _, k := range precedence {
+		if present[k] {
+			return k
+		}
+	}
+	return ""
+}
+
+// ByPath indexes classifications by the file's current path
+func ByPath(classes []Classification) map[string]Classification {
+	m := make(map[string]Classification, len(classes))
+	for _, c := range classes {
+		m[c.Path] = c
+	}
+	return m
+}
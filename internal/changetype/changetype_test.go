@@ -0,0 +1,290 @@
+package changetype
+
+import "testing"
+
+const sampleDiff = `diff --git a/billing/refund.go b/billing/refund.go
+index 1111111..2222222 100644
+--- a/billing/refund.go
++++ b/billing/refund.go
+@@ -10,3 +10,3 @@ func ProcessRefund(id string) error {
+-	total := lookup(id)
+-	return charge(total, id)
++	amount := lookup(id)
++	return charge(amount, id)
+ }
+diff --git a/billing/tax.go b/billing/tax.go
+--- a/billing/tax.go
++++ b/billing/tax.go
+@@ -4 +4 @@
+-	rate := 0.2 // standard
++	rate := 0.2 // standard VAT rate
+diff --git a/pkg/old/util.go b/pkg/new/util.go
+similarity index 100%
+rename from pkg/old/util.go
+rename to pkg/new/util.go
+diff --git a/billing/discount.go b/billing/discount.go
+--- a/billing/discount.go
++++ b/billing/discount.go
+@@ -7 +7 @@
+-	if total > 100 {
++	if total >= 100 {
+diff --git a/go.sum b/go.sum
+--- a/go.sum
++++ b/go.sum
+@@ -1 +1 @@
+-example.com/x v1.0.0 h1:abc=
++example.com/x v1.1.0 h1:def=
+diff --git a/api/v1/service.pb.go b/api/v1/service.pb.go
+new file mode 100644
+--- /dev/null
++++ b/api/v1/service.pb.go
+@@ -0,0 +1 @@
++// Code generated by protoc-gen-go. DO NOT EDIT.
+`
+
+// sourceOf serves file versions from maps; absent files read as nil
+func sourceOf(before, after map[string]string) Source {
+	reader := func(files map[string]string) Reader {
+		return func(p string) ([]byte, error) {
+			if content, ok := files[p]; ok {
+				return []byte(content), nil
+			}
+			return nil, nil
+		}
+	}
+	return Source{Before: reader(before), After: reader(after)}
+}
+
+func TestClassifyDiff(t *testing.T) {
+	src := sourceOf(
+		map[string]string{"billing/refund.go": "package billing\n\nfunc ProcessRefund(id string) error {\n\ttotal := lookup(id)\n\treturn charge(total, id)\n}\n"},
+		map[string]string{"billing/refund.go": "package billing\n\nfunc ProcessRefund(id string) error {\n\tamount := lookup(id)\n\treturn charge(amount, id)\n}\n"},
+	)
+	classes := ClassifyDiff(sampleDiff, src)
+	want := map[string]struct {
+		kind   Kind
+		action Action
+	}{
+		"billing/refund.go":    {Refactor, ActionReduce},
+		"billing/tax.go":       {Formatting, ActionSkip},
+		"pkg/new/util.go":      {Rename, ActionSkip},
+		"billing/discount.go":  {Behavioral, ActionFull},
+		"go.sum":               {Dependency, ActionReduce},
+		"api/v1/service.pb.go": {Generated, ActionSkip},
+	}
+	if len(classes) != len(want) {
+		t.Fatalf("got %d classifications, want %d: %+v", len(classes), len(want), classes)
+	}
+	for _, c := range classes {
+		w, ok := want[c.Path]
+		if !ok {
+			t.Errorf("unexpected path %q", c.Path)
+			continue
+		}
+		if c.Kind != w.kind || c.Action != w.action {
+			t.Errorf("%s = %s/%s (%s), want %s/%s", c.Path, c.Kind, c.Action, c.Reason, w.kind, w.action)
+		}
+	}
+	if byPath := ByPath(classes); byPath["pkg/new/util.go"].OldPath != "pkg/old/util.go" {
+		t.Errorf("rename old path = %q", byPath["pkg/new/util.go"].OldPath)
+	}
+	if got := Dominant(classes); got != Behavioral {
+		t.Errorf("Dominant = %s, want behavioral", got)
+	}
+}
+
+func TestRenamedTokensRequiresConsistentMapping(t *testing.T) {
+	var syn syntax
+	before := tokenize([]string{"a := f(x)", "g(a, b)"}, syn)
+
+	consistent := tokenize([]string{"c := f(x)", "g(c, b)"}, syn)
+	if renames, ok := renamedTokens(before, consistent); !ok || len(renames) != 1 || renames["a"] != "c" {
+		t.Errorf("consistent rename: renames=%v ok=%v, want a->c", renames, ok)
+	}
+	// a becomes c in one place and d in another
+	inconsistent := tokenize([]string{"c := f(x)", "g(d, b)"}, syn)
+	if _, ok := renamedTokens(before, inconsistent); ok {
+		t.Error("inconsistent rename accepted")
+	}
+	// a and b merge into one identifier, which can change behaviour
+	merged := tokenize([]string{"b := f(x)", "g(b, b)"}, syn)
+	if _, ok := renamedTokens(before, merged); ok {
+		t.Error("merging identifiers accepted")
+	}
+	// Swapped arguments map a to b and b to a
+	if _, ok := renamedTokens(tokenize([]string{"foo(a, b)"}, syn), tokenize([]string{"foo(b, a)"}, syn)); ok {
+		t.Error("permuted identifiers accepted")
+	}
+	// Keywords and literals are not renameable
+	if _, ok := renamedTokens(tokenize([]string{`return "a"`}, syn), tokenize([]string{`return "b"`}, syn)); ok {
+		t.Error("string literal change accepted")
+	}
+}
+
+func TestLocalName(t *testing.T) {
+	goSyn, pySyn := syntax{golang: true}, syntax{hashComments: true, indentation: true}
+	goFile := tokenize([]string{
+		"package billing",
+		"func (s *Store) save(ctx context.Context, amount, fee int) error {",
+		"\tnet, err := s.charge(amount - fee)",
+		"\treturn Persist(net, err)",
+		"}",
+	}, goSyn)
+	pyFile := tokenize([]string{
+		"RATE = 0.2",
+		"_cache = {}",
+		"class Store:",
+		"    def save(self, amount):",
+		"        total = amount * RATE",
+		"        return total",
+	}, pySyn)
+	cases := []struct {
+		name   string
+		tokens []token
+		syn    syntax
+		want   bool
+	}{
+		{"amount", goFile, goSyn, true}, // Parameter
+		{"fee", goFile, goSyn, true},    // Grouped parameter
+		{"net", goFile, goSyn, true},    // :=
+		{"err", goFile, goSyn, true},    // Second name of a :=
+		{"ctx", goFile, goSyn, true},
+		{"charge", goFile, goSyn, false},  // Reached through a selector
+		{"save", goFile, goSyn, false},    // Method, declared after a receiver
+		{"Persist", goFile, goSyn, false}, // Exported and declared elsewhere
+		{"context", goFile, goSyn, false}, // Package name
+		{"total", pyFile, pySyn, true},
+		{"amount", pyFile, pySyn, true},
+		{"_cache", pyFile, pySyn, true},
+		{"RATE", pyFile, pySyn, false}, // Module-level name other modules import
+		{"save", pyFile, pySyn, false}, // Method
+		{"Store", pyFile, pySyn, false},
+	}
+	for _, tc := range cases {
+		if got := localName(tc.name, tc.tokens, tc.syn); got != tc.want {
+			t.Errorf("localName(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestClassifyBehaviouralLookalikes(t *testing.T) {
+	cases := []struct {
+		name   string
+		change FileChange
+		src    Source
+	}{
+		{
+			name: "python dedent",
+			change: FileChange{Path: "billing/refund.py", Status: "modified",
+				Removed: []string{"    refund()"}, Added: []string{"refund()"}},
+		},
+		{
+			name: "go build constraint",
+			change: FileChange{Path: "store/sqlite.go", Status: "modified",
+				Removed: []string{"//go:build cgo"}, Added: []string{"//go:build cgo && linux"}},
+		},
+		{
+			name: "shebang",
+			change: FileChange{Path: "scripts/run.sh", Status: "modified",
+				Removed: []string{"#!/bin/sh"}, Added: []string{"#!/bin/bash"}},
+		},
+		{
+			name: "cgo preamble",
+			change: FileChange{Path: "native/add.go", Status: "modified",
+				Removed: []string{"// static int add(int a, int b) { return a + b; }"},
+				Added:   []string{"// static int add(int a, int b) { return a - b; }"}},
+			src: sourceOf(nil, map[string]string{"native/add.go": "package native\n\n// static int add(int a, int b) { return a - b; }\nimport \"C\"\n"}),
+		},
+		{
+			name: "dropped column in a migration",
+			change: FileChange{Path: "migrations/0042_users.sql", Status: "modified",
+				Removed: []string{"ALTER TABLE users DROP COLUMN nickname;"}, Added: []string{"ALTER TABLE users DROP COLUMN email;"}},
+			src: sourceOf(
+				map[string]string{"migrations/0042_users.sql": "ALTER TABLE users DROP COLUMN nickname;\n"},
+				map[string]string{"migrations/0042_users.sql": "ALTER TABLE users DROP COLUMN email;\n"}),
+		},
+		{
+			name: "switch to an existing variable",
+			change: FileChange{Path: "billing/total.go", Status: "modified",
+				Removed: []string{"\treturn net"}, Added: []string{"\treturn gross"}},
+			src: sourceOf(
+				map[string]string{"billing/total.go": "package billing\n\nfunc total(net, gross int) int {\n\treturn net\n}\n"},
+				map[string]string{"billing/total.go": "package billing\n\nfunc total(net, gross int) int {\n\treturn gross\n}\n"}),
+		},
+		{
+			name: "rename without the rest of the file",
+			change: FileChange{Path: "billing/refund.go", Status: "modified",
+				Removed: []string{"\ttotal := lookup(id)"}, Added: []string{"\tamount := lookup(id)"}},
+		},
+		{
+			name: "call to a different function of another package",
+			change: FileChange{Path: "text/shout.go", Status: "modified",
+				Removed: []string{"\treturn strings.ToUpper(s)"}, Added: []string{"\treturn strings.ToLower(s)"}},
+			src: sourceOf(
+				map[string]string{"text/shout.go": "package text\n\nimport \"strings\"\n\nfunc shout(s string) string {\n\treturn strings.ToUpper(s)\n}\n"},
+				map[string]string{"text/shout.go": "package text\n\nimport \"strings\"\n\nfunc shout(s string) string {\n\treturn strings.ToLower(s)\n}\n"}),
+		},
+		{
+			name: "renamed exported function",
+			change: FileChange{Path: "billing/charge.go", Status: "modified",
+				Removed: []string{"func Charge(amount int) error {"}, Added: []string{"func ChargeCard(amount int) error {"}},
+			src: sourceOf(
+				map[string]string{"billing/charge.go": "package billing\n\nfunc Charge(amount int) error {\n\treturn nil\n}\n"},
+				map[string]string{"billing/charge.go": "package billing\n\nfunc ChargeCard(amount int) error {\n\treturn nil\n}\n"}),
+		},
+		{
+			name: "generated marker added to a handwritten file",
+			change: FileChange{Path: "billing/charge.go", Status: "modified",
+				Removed: []string{"\treturn total"}, Added: []string{"\t// The table below is generated; DO NOT EDIT by hand", "\treturn total * 2"}},
+			src: sourceOf(map[string]string{"billing/charge.go": "package billing\n"}, nil),
+		},
+	}
+	for _, tc := range cases {
+		if c := Classify(tc.change, tc.src); c.Action != ActionFull {
+			t.Errorf("%s = %s/%s (%s), want a full assessment", tc.name, c.Kind, c.Action, c.Reason)
+		}
+	}
+
+	// Indentation inside a Go function and plain comments remain formatting
+	reindent := FileChange{Path: "billing/refund.go", Status: "modified",
+		Removed: []string{"\t\trefund() // later"}, Added: []string{"\trefund()"}}
+	if c := Classify(reindent, Source{}); c.Kind != Formatting {
+		t.Errorf("Go reindent = %s (%s), want formatting", c.Kind, c.Reason)
+	}
+	// A file whose header at the base revision marks it generated
+	regenerated := FileChange{Path: "api/client.go", Status: "modified", Removed: []string{"x := 1"}, Added: []string{"x := 2"}}
+	src := sourceOf(map[string]string{"api/client.go": "// Code generated by oapi-codegen. DO NOT EDIT.\npackage api\n"}, nil)
+	if c := Classify(regenerated, src); c.Kind != Generated {
+		t.Errorf("regenerated file = %s, want generated", c.Kind)
+	}
+}
+
+func TestPackageJSONDependencies(t *testing.T) {
+	bump := FileChange{Path: "web/package.json", Status: "modified",
+		Removed: []string{`    "react": "^18.2.0",`}, Added: []string{`    "react": "^18.3.1",`}}
+	if c := Classify(bump, Source{}); c.Kind != Dependency {
+		t.Errorf("version bump = %s, want dependency", c.Kind)
+	}
+	script := FileChange{Path: "web/package.json", Status: "modified",
+		Removed: []string{`    "build": "tsc",`}, Added: []string{`    "build": "tsc && vite build",`}}
+	if c := Classify(script, Source{}); c.Kind != Config {
+		t.Errorf("script change = %s, want config", c.Kind)
+	}
+}
+
+func TestRefineWithEvents(t *testing.T) {
+	classes := []Classification{
+		{Path: "a.go", Kind: Refactor, Action: ActionReduce, Confidence: 0.85},
+		{Path: "b.go", Kind: Refactor, Action: ActionReduce, Confidence: 0.85},
+	}
+	refined := RefineWithEvents(classes, []Event{
+		{File: "a.go", Behavior: "RENAME_BLOCK"},
+		{File: "b.go", Behavior: "CREATE_BLOCK"},
+	})
+	if refined[0].Kind != Refactor || refined[0].Confidence != 0.95 {
+		t.Errorf("a.go = %+v, want refactor with raised confidence", refined[0])
+	}
+	if refined[1].Kind != Behavioral || refined[1].Action != ActionFull {
+		t.Errorf("b.go = %+v, want behavioral", refined[1])
+	}
+}
@@ -0,0 +1,86 @@
+package changetype
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FileChange is one file's section of a git diff
+type FileChange struct {
+	Path       string   // Path after the change (before it, for deletions)
+	OldPath    string   // Path before a rename or move; empty otherwise
+	Status     string   // added, deleted, renamed, modified
+	Similarity int      // Rename similarity percentage reported by git
+	Binary     bool     // git reported binary content
+	Added      []string // Added lines without the leading '+'
+	Removed    []string // Removed lines without the leading '-'
+}
+
+// BasePath is the file's path before the change
+func (fc FileChange) BasePath() string {
+	if fc.OldPath != "" {
+		return fc.OldPath
+	}
+	return fc.Path
+}
+
+// ParseDiff splits a git diff (`git diff`, `git show`) into per-file changes
+// Rename detection must be on (git's default) for moves to be reported as renames.
+func ParseDiff(diff string) []FileChange {
+	var files []FileChange
+	var cur *FileChange
+	inHunk := false
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files = append(files, FileChange{Status: "modified"})
+			cur = &files[len(files)-1]
+			inHunk = false
+			if a, b, ok := parseGitHeader(line); ok {
+				cur.OldPath, cur.Path = a, b
+			}
+		case cur == nil:
+			continue
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case inHunk && strings.HasPrefix(line, "+"):
+			cur.Added = append(cur.Added, line[1:])
+		case inHunk && strings.HasPrefix(line, "-"):
+			cur.Removed = append(cur.Removed, line[1:])
+		case inHunk:
+			// Context line or "\ No newline at end of file"
+		case strings.HasPrefix(line, "new file mode"):
+			cur.Status = "added"
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.Status = "deleted"
+		case strings.HasPrefix(line, "rename from "):
+			cur.Status = "renamed"
+			cur.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			cur.Path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "similarity index "):
+			cur.Similarity, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "similarity index "), "%"))
+		case strings.HasPrefix(line, "Binary files ") || strings.HasPrefix(line, "GIT binary patch"):
+			cur.Binary = true
+		}
+	}
+
+	for i := range files {
+		if files[i].Status != "renamed" {
+			files[i].OldPath = ""
+		}
+	}
+	return files
+}
+
+// parseGitHeader extracts the paths from "diff --git a/<old> b/<new>"
+// Paths with spaces are ambiguous in this header; rename lines, when present, override it.
+func parseGitHeader(line string) (oldPath, newPath string, ok bool) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+	i := strings.Index(rest, " b/")
+	if !strings.HasPrefix(rest, "a/") || i < 0 {
+		return "", "", false
+	}
+	return rest[2:i], rest[i+3:], true
+}
@@ -0,0 +1,41 @@
+package changetype
+
+// Event is a block-level change reported by the atomizer for a file
+type Event struct {
+	File     string
+	Behavior string // CREATE_BLOCK, MODIFY_BLOCK, DELETE_BLOCK, RENAME_BLOCK, ADD_IMPORT, REMOVE_IMPORT
+}
+
+// RefineWithEvents checks token-level verdicts against the atomizer's block events
+// A refactor or formatting change in which blocks were created or deleted is reclassified
+// as behavioural; a refactor whose only events are block renames gains confidence.
+func RefineWithEvents(classes []Classification, events []Event) []Classification {
+	byFile := make(map[string][]string)
+	for _, e := range events {
+		byFile[e.File] = append(byFile[e.File], e.Behavior)
+	}
+
+	out := make([]Classification, len(classes))
+	for i, c := range classes {
+		out[i] = c
+		behaviors := byFile[c.Path]
+		if len(behaviors) == 0 || (c.Kind != Refactor && c.Kind != Formatting) {
+			continue
+		}
+		onlyRenames := true
+		for _, b := range behaviors {
+			switch b {
+			case "CREATE_BLOCK", "DELETE_BLOCK":
+				out[i].Kind, out[i].Action, out[i].Confidence = Behavioral, ActionFull, 0.8
+				out[i].Reason = "atomizer found blocks created or deleted"
+			case "RENAME_BLOCK":
+				continue
+			}
+			onlyRenames = false
+		}
+		if onlyRenames && out[i].Kind == Refactor {
+			out[i].Confidence = 0.95
+		}
+	}
+	return out
+}
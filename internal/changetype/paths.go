@@ -0,0 +1,155 @@
+package changetype
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// dependencyFiles are manifests and lockfiles whose changes pin or bump dependencies
+var dependencyFiles = map[string]bool{
+	"go.mod": true, "go.sum": true, "go.work": true, "go.work.sum": true,
+	"package-lock.json": true, "npm-shrinkwrap.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	"Cargo.lock": true, "poetry.lock": true, "Pipfile.lock": true, "Gemfile.lock": true,
+	"composer.lock": true, "uv.lock": true, "gradle.lockfile": true, "mix.lock": true,
+}
+
+// versionEntryPattern matches one "name": "version" entry of a package.json dependency map
+var versionEntryPattern = regexp.MustCompile(`^\s*"[^"]+"\s*:\s*"(?:[~^<>=v ]*[\dx*][^"]*|latest|workspace:[^"]*)"\s*,?\s*$`)
+
+// requirementPattern matches a pinned Python requirement such as "requests==2.31.0"
+var requirementPattern = regexp.MustCompile(`^\s*[A-Za-z0-9_.\-\[\],]+\s*(?:[<>=!~]=?|===)\s*[\w.*+!\-]+`)
+
+var generatedNamePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\.pb(?:\.gw)?\.go$`),
+	regexp.MustCompile(`_pb2(?:_grpc)?\.pyi?$`),
+	regexp.MustCompile(`(?:^|[._-])(?:generated|gen)\.[a-z]+$`),
+	regexp.MustCompile(`^zz_generated`),
+	regexp.MustCompile(`_string\.go$`),
+	regexp.MustCompile(`\.min\.(?:js|css)$`),
+	regexp.MustCompile(`\.snap$`),
+}
+
+// headerLines is how far into a file a generated-code marker is looked for
+const headerLines = 10
+
+// generatedMarkers are the conventional headers of generated sources
+var generatedMarkers = []string{"Code generated", "DO NOT EDIT", "@generated", "<auto-generated"}
+
+var docExtensions = map[string]bool{".md": true, ".markdown": true, ".rst": true, ".adoc": true, ".txt": true}
+
+var docNames = []string{"README", "LICENSE", "CHANGELOG", "AUTHORS", "CONTRIBUTORS", "NOTICE", "CODEOWNERS"}
+
+var configExtensions = map[string]bool{
+	".yaml": true, ".yml": true, ".toml": true, ".ini": true, ".cfg": true, ".conf": true,
+	".properties": true, ".env": true, ".json": true, ".xml": true, ".tf": true, ".tfvars": true,
+	".hcl": true,
+}
+
+var configNames = map[string]bool{
+	"Dockerfile": true, "Makefile": true, "Procfile": true, ".editorconfig": true, ".gitignore": true,
+	".gitattributes": true, ".dockerignore": true, ".npmrc": true, ".nvmrc": true,
+}
+
+// isDependencyFile reports whether a file only pins dependencies, or for manifests that
+// mix dependencies with other settings, whether every changed line is a version entry
+func isDependencyFile(fc FileChange) bool {
+	name := path.Base(fc.Path)
+	if dependencyFiles[name] {
+		return true
+	}
+	var entry *regexp.Regexp
+	switch {
+	case name == "package.json":
+		entry = versionEntryPattern
+	case strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt"):
+		entry = requirementPattern
+	default:
+		return false
+	}
+	changed := 0
+	for _, line := range append(append([]string{}, fc.Added...), fc.Removed...) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if !entry.MatchString(line) {
+			return false
+		}
+		changed++
+	}
+	return changed > 0
+}
+
+// isGenerated reports whether a file is generated, by its path or by a generated-code
+// marker in its header: the first lines of a new file, or of the file at the base
+// revision, since a marker merely added somewhere in a handwritten file does not make
+// it generated
+func isGenerated(fc FileChange, base Reader) bool {
+	p := fc.Path
+	for _, dir := range []string{"vendor/", "node_modules/", "dist/", "third_party/"} {
+		if strings.HasPrefix(p, dir) || strings.Contains(p, "/"+dir) {
+			return true
+		}
+	}
+	name := path.Base(p)
+	for _, re := range generatedNamePatterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	header := fc.Added
+	if fc.Status != "added" {
+		header = strings.Split(string(base.read(fc.BasePath())), "\n")
+	}
+	for _, line := range header[:min(len(header), headerLines)] {
+		for _, marker := range generatedMarkers {
+			if strings.Contains(line, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isDocFile(p string) bool {
+	if docExtensions[strings.ToLower(path.Ext(p))] && !strings.HasPrefix(path.Base(p), "requirements") {
+		return true
+	}
+	upper := strings.ToUpper(path.Base(p))
+	for _, name := range docNames {
+		if strings.HasPrefix(upper, name) {
+			return true
+		}
+	}
+	return strings.HasPrefix(p, "docs/") || strings.Contains(p, "/docs/")
+}
+
+// isTestFile follows the test naming conventions of Go, Python, JavaScript/TypeScript and Java
+func isTestFile(p string) bool {
+	name := path.Base(p)
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	switch {
+	case strings.HasSuffix(name, "_test.go"),
+		strings.HasPrefix(name, "test_") && ext == ".py",
+		strings.HasSuffix(stem, "_test") && ext == ".py",
+		name == "conftest.py",
+		strings.HasSuffix(stem, ".test"), strings.HasSuffix(stem, ".spec"),
+		(strings.HasSuffix(stem, "Test") || strings.HasSuffix(stem, "Tests")) && ext == ".java":
+		return true
+	}
+	for _, dir := range []string{"__tests__/", "testdata/", "tests/", "test/", "src/test/", "spec/"} {
+		if strings.HasPrefix(p, dir) || strings.Contains(p, "/"+dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func isConfigFile(p string) bool {
+	name := path.Base(p)
+	if configNames[name] || strings.HasPrefix(name, ".env") || strings.HasPrefix(p, ".github/") {
+		return true
+	}
+	return configExtensions[strings.ToLower(path.Ext(name))]
+}
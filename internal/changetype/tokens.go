@@ -0,0 +1,367 @@
+package changetype
+
+import (
+	"bytes"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// token is a lexical unit of changed source; comments and insignificant whitespace are dropped
+type token struct {
+	text     string
+	ident    bool // Identifier that a mechanical refactor may rename
+	first    bool // First token on its line
+	topLevel bool // First token on an unindented line
+}
+
+// keywords are never treated as renameable identifiers (Go, Python, JavaScript/TypeScript, Java)
+var keywords = map[string]bool{
+	"if": true, "else": true, "for": true, "while": true, "do": true, "switch": true, "case": true,
+	"default": true, "break": true, "continue": true, "return": true, "goto": true, "fallthrough": true,
+	"func": true, "function": true, "def": true, "lambda": true, "class": true, "struct": true,
+	"interface": true, "type": true, "enum": true, "package": true, "import": true, "from": true,
+	"export": true, "as": true, "var": true, "let": true, "const": true, "new": true, "delete": true,
+	"try": true, "catch": true, "finally": true, "throw": true, "throws": true, "raise": true,
+	"except": true, "with": true, "yield": true, "async": true, "await": true, "go": true,
+	"defer": true, "select": true, "chan": true, "map": true, "range": true, "in": true, "of": true,
+	"is": true, "not": true, "and": true, "or": true, "pass": true, "global": true, "nonlocal": true,
+	"public": true, "private": true, "protected": true, "static": true, "final": true,
+	"abstract": true, "extends": true, "implements": true, "instanceof": true, "typeof": true,
+	"void": true, "this": true, "self": true, "super": true, "nil": true, "null": true,
+	"undefined": true, "None": true, "true": true, "false": true, "True": true, "False": true,
+}
+
+// hashCommentExtensions are languages where '#' starts a comment
+var hashCommentExtensions = map[string]bool{
+	".py": true, ".pyi": true, ".rb": true, ".sh": true, ".bash": true, ".yaml": true, ".yml": true,
+	".toml": true, ".r": true, ".pl": true, ".ex": true, ".exs": true, ".coffee": true, ".nim": true,
+}
+
+// indentationExtensions are languages where leading whitespace is part of the program
+var indentationExtensions = map[string]bool{
+	".py": true, ".pyi": true, ".pyw": true, ".coffee": true, ".nim": true, ".haml": true,
+	".pug": true, ".sass": true, ".slim": true,
+}
+
+// directivePrefixes start comments that the toolchain or interpreter reads, so editing
+// them changes the build or the program: Go build constraints, embeds, generate and
+// linkname directives, shebangs, source encodings and Ruby magic comments
+var directivePrefixes = []string{
+	"//go:", "//+build", "// +build", "//line ", "//export ", "//extern ",
+	"#!", "# -*-", "# coding", "# vim: set fileencoding", "# frozen_string_literal",
+}
+
+// cgoMarkers identify C code in a Go comment, which cgo compiles as the file's preamble
+// when the rest of the file is not available to show the import of "C"
+var cgoMarkers = []string{"#include", "#cgo", "#define"}
+
+// syntax is what the tokenizer needs to know about a file's language
+type syntax struct {
+	hashComments bool // '#' starts a comment
+	indentation  bool // Leading whitespace is significant
+	golang       bool
+	cgo          bool // Go file importing "C": its comments are C source
+}
+
+// syntaxOf returns the syntax of a file by extension; the file's versions, when
+// available, show whether it uses cgo
+func syntaxOf(p string, versions ...[]byte) syntax {
+	ext := strings.ToLower(path.Ext(p))
+	syn := syntax{
+		hashComments: hashCommentExtensions[ext],
+		indentation:  indentationExtensions[ext],
+		golang:       ext == ".go",
+	}
+	for _, content := range versions {
+		syn.cgo = syn.cgo || (syn.golang && bytes.Contains(content, []byte(`import "C"`)))
+	}
+	return syn
+}
+
+// isDirective reports whether a comment is read by a tool rather than by people
+func (s syntax) isDirective(comment string) bool {
+	if s.cgo {
+		return true
+	}
+	for _, prefix := range directivePrefixes {
+		if strings.HasPrefix(comment, prefix) {
+			return true
+		}
+	}
+	if s.golang {
+		for _, marker := range cgoMarkers {
+			if strings.Contains(comment, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tokenize lexes lines into tokens, skipping comments and insignificant whitespace
+// Block comments may span lines; strings are kept whole so literal changes are visible.
+// Directive comments are kept, and for indentation-sensitive languages each line's
+// leading whitespace is a token, so a dedent is not mistaken for formatting.
+func tokenize(lines []string, syn syntax) []token {
+	src := strings.Join(lines, "\n")
+	var tokens []token
+	lineStart, indent := true, ""
+	emit := func(t token) {
+		if lineStart && syn.indentation {
+			tokens = append(tokens, token{text: indent, first: true, topLevel: indent == ""})
+		} else if lineStart {
+			t.first, t.topLevel = true, indent == ""
+		}
+		lineStart = false
+		tokens = append(tokens, t)
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			lineStart, indent = true, ""
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			if lineStart {
+				indent += string(c)
+			}
+			i++
+		case strings.HasPrefix(src[i:], "//") || (syn.hashComments && c == '#'):
+			j := i
+			for j < len(src) && src[j] != '\n' {
+				j++
+			}
+			if comment := strings.TrimRight(src[i:j], " \t\r"); syn.isDirective(comment) {
+				emit(token{text: comment})
+			}
+			i = j
+		case strings.HasPrefix(src[i:], "/*"):
+			j := len(src)
+			if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+				j = i + end + 4
+			}
+			if comment := src[i:j]; syn.isDirective(comment) {
+				emit(token{text: comment})
+			}
+			i = j
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			for j < len(src) && src[j] != c && (c == '`' || src[j] != '\n') {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(src))
+			emit(token{text: src[i:j]})
+			i = j
+		case isIdentStart(rune(c)):
+			j := i + 1
+			for j < len(src) && isIdentPart(rune(src[j])) {
+				j++
+			}
+			word := src[i:j]
+			emit(token{text: word, ident: !keywords[word]})
+			i = j
+		case c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(src) && (isIdentPart(rune(src[j])) || src[j] == '.') {
+				j++
+			}
+			emit(token{text: src[i:j]})
+			i = j
+		default:
+			emit(token{text: src[i : i+1]})
+			i++
+		}
+	}
+	return tokens
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+// sameTokens reports whether two token streams are identical
+func sameTokens(a, b []token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].text != b[i].text {
+			return false
+		}
+	}
+	return true
+}
+
+// renamedTokens reports whether b is a consistently renamed a: identical except for
+// identifiers, with each old identifier always becoming the same new one, no two old
+// identifiers merging, and no new name being one a already uses (so swapped arguments
+// are not a rename). It returns the identifiers that changed, old name to new.
+func renamedTokens(a, b []token) (map[string]string, bool) {
+	if len(a) != len(b) {
+		return nil, false
+	}
+	forward := make(map[string]string)
+	backward := make(map[string]string)
+	for i := range a {
+		if !a[i].ident || !b[i].ident {
+			if a[i].text != b[i].text {
+				return nil, false
+			}
+			continue
+		}
+		if to, seen := forward[a[i].text]; seen && to != b[i].text {
+			return nil, false
+		}
+		if from, seen := backward[b[i].text]; seen && from != a[i].text {
+			return nil, false
+		}
+		forward[a[i].text] = b[i].text
+		backward[b[i].text] = a[i].text
+	}
+
+	renames := make(map[string]string)
+	for from, to := range forward {
+		if from == to {
+			continue
+		}
+		if _, used := forward[to]; used {
+			return nil, false // Permutation: the new name was already in use
+		}
+		renames[from] = to
+	}
+	return renames, true
+}
+
+// identifiers returns the set of identifiers among tokens
+func identifiers(tokens []token) map[string]bool {
+	ids := make(map[string]bool)
+	for _, t := range tokens {
+		if t.ident {
+			ids[t.text] = true
+		}
+	}
+	return ids
+}
+
+// declarers are keywords that introduce the name following them
+var declarers = map[string]bool{
+	"func": true, "function": true, "def": true, "class": true, "type": true,
+	"var": true, "let": true, "const": true, "as": true,
+}
+
+// localName reports whether a rename of name can be judged from this file alone: the
+// file declares it, it is not exported, and it is never reached through a selector
+// (x.name), which marks a member or another package's name that code elsewhere uses
+func localName(name string, tokens []token, syn syntax) bool {
+	if syn.golang && strings.IndexFunc(name, unicode.IsUpper) == 0 {
+		return false
+	}
+	params := parameters(tokens)
+	declared := false
+	for i, t := range tokens {
+		if !t.ident || t.text != name {
+			continue
+		}
+		prev, next := tokenAt(tokens, i-1), tokenAt(tokens, i+1)
+		if prev.text == "." {
+			return false
+		}
+		switch {
+		case !prev.ident && (declarers[prev.text] || (syn.indentation && prev.text == "for")):
+			if prev.text == "def" && next.text == "(" && method(tokenAt(tokens, i+2).text) {
+				return false
+			}
+		case syn.indentation && prev.first && next.text == "=" && tokenAt(tokens, i+2).text != "=":
+			// Python binds a name by assigning it
+		case shortVarDecl(tokens, i), params[i]:
+		default:
+			continue
+		}
+		if exported(tokens, i, syn) {
+			return false
+		}
+		declared = true
+	}
+	return declared
+}
+
+// method reports whether a Python function's first parameter makes it a method
+func method(firstParam string) bool {
+	return firstParam == "self" || firstParam == "cls"
+}
+
+// exported reports whether the declaration of the name at tokens[i] is visible outside
+// the file: marked export or public, or bound at the top level of a Python module
+func exported(tokens []token, i int, syn syntax) bool {
+	for j := i; j >= 0; j-- {
+		if t := tokens[j]; !t.ident && (t.text == "export" || t.text == "public") {
+			return true
+		}
+		if tokens[j].first {
+			return syn.indentation && tokens[j].topLevel && !strings.HasPrefix(tokens[i].text, "_")
+		}
+	}
+	return false
+}
+
+// shortVarDecl reports whether tokens[i] is among the names a Go := declares
+func shortVarDecl(tokens []token, i int) bool {
+	j := i
+	for tokenAt(tokens, j+1).text == "," && tokenAt(tokens, j+2).ident {
+		j += 2
+	}
+	return tokenAt(tokens, j+1).text == ":" && tokenAt(tokens, j+2).text == "="
+}
+
+// parameters returns the positions of parameter names in function signatures: the
+// identifiers opening each entry of a parenthesised list between a func, function or
+// def keyword and the body
+func parameters(tokens []token) map[int]bool {
+	params := make(map[int]bool)
+	for i, t := range tokens {
+		if t.ident || (t.text != "func" && t.text != "function" && t.text != "def") {
+			continue
+		}
+		depth := 0
+	signature:
+		for j := i + 1; j < len(tokens); j++ {
+			switch tokens[j].text {
+			case "(":
+				depth++
+				continue
+			case ")":
+				depth--
+				continue
+			case "{", ":":
+				if depth == 0 {
+					break signature
+				}
+			}
+			if depth == 0 && tokens[j].first {
+				break
+			}
+			prev := tokens[j-1].text
+			if depth == 1 && tokens[j].ident && (prev == "(" || prev == ",") && tokenAt(tokens, j+1).text != "." {
+				params[j] = true
+			}
+		}
+	}
+	return params
+}
+
+// tokenAt returns tokens[i], or the zero token outside the slice
+func tokenAt(tokens []token, i int) token {
+	if i < 0 || i >= len(tokens) {
+		return token{}
+	}
+	return tokens[i]
+}
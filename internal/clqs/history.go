@@ -0,0 +1,143 @@
+package clqs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// ComponentPoint is one component's score in a run and its change since the previous run
+type ComponentPoint struct {
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+	Delta float64 `json:"delta"`
+}
+
+// HistoryEntry is one stored CLQS run
+type HistoryEntry struct {
+	ComputedAt   time.Time        `json:"computed_at"`
+	CLQS         float64          `json:"clqs"`
+	Grade        string           `json:"grade"`
+	Delta        float64          `json:"delta"` // CLQS change since the previous run (0 for the first)
+	Components   []ComponentPoint `json:"components"`
+	LinkedIssues int              `json:"linked_issues"`
+	TotalLinks   int              `json:"total_links"`
+}
+
+// History is a repository's CLQS runs, oldest first
+type History struct {
+	Repository string         `json:"repository"`
+	Entries    []HistoryEntry `json:"entries"`
+}
+
+// Regression describes a CLQS drop between the two most recent runs
+type Regression struct {
+	Previous HistoryEntry     `json:"previous"`
+	Current  HistoryEntry     `json:"current"`
+	Drop     float64          `json:"drop"`
+	Degraded []ComponentPoint `json:"degraded_components"` // Components that fell, largest drop first
+}
+
+// BuildHistory converts stored scores (newest first, as returned by
+// database.StagingClient.GetCLQSScoreHistory) into a chronological history
+func BuildHistory(fullName string, scores []database.CLQSScore) *History {
+	h := &History{Repository: fullName}
+	for i := len(scores) - 1; i >= 0; i-- {
+		s := scores[i]
+		entry := HistoryEntry{
+			ComputedAt:   s.ComputedAt,
+			CLQS:         s.CLQS,
+			Grade:        s.Grade,
+			LinkedIssues: s.LinkedIssues,
+			TotalLinks:   s.TotalLinks,
+			Components: []ComponentPoint{
+				{Name: "Link Coverage", Score: s.LinkCoverage},
+				{Name: "Confidence Quality", Score: s.ConfidenceQuality},
+				{Name: "Evidence Diversity", Score: s.EvidenceDiversity},
+				{Name: "Temporal Precision", Score: s.TemporalPrecision},
+				{Name: "Semantic Strength", Score: s.SemanticStrength},
+			},
+		}
+		if n := len(h.Entries); n > 0 {
+			prev := h.Entries[n-1]
+			entry.Delta = entry.CLQS - prev.CLQS
+			for j := range entry.Components {
+				entry.Components[j].Delta = entry.Components[j].Score - prev.Components[j].Score
+			}
+		}
+		h.Entries = append(h.Entries, entry)
+	}
+	return h
+}
+
+// CheckDrop compares the latest run against the previous one and returns a
+// Regression when CLQS fell by more than points; nil otherwise
+func (h *History) CheckDrop(points float64) *Regression {
+	n := len(h.Entries)
+	if n < 2 {
+		return nil
+	}
+	current := h.Entries[n-1]
+	if -current.Delta <= points {
+		return nil
+	}
+
+	r := &Regression{Previous: h.Entries[n-2], Current: current, Drop: -current.Delta}
+	for _, c := range current.Components {
+		if c.Delta < 0 {
+			r.Degraded = append(r.Degraded, c)
+		}
+	}
+	sort.SliceStable(r.Degraded, func(i, j int) bool { return r.Degraded[i].Delta < r.Degraded[j].Delta })
+	return r
+}
+
+// FormatHistory generates a human-readable table of CLQS runs
+func FormatHistory(h *History, w io.Writer) error {
+	fmt.Fprintf(w, "═══════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(w, "  CLQS HISTORY: %s\n", h.Repository)
+	fmt.Fprintf(w, "═══════════════════════════════════════════════════════════\n\n")
+
+	if len(h.Entries) == 0 {
+		fmt.Fprintf(w, "No stored CLQS scores (run clqs-calculator --recalculate)\n")
+		return nil
+	}
+
+	fmt.Fprintf(w, "%-16s  %6s %6s %5s  %8s %8s %8s %8s %8s  %6s\n",
+		"COMPUTED", "CLQS", "Δ", "GRADE", "COVERAGE", "CONFID.", "EVIDENCE", "TEMPORAL", "SEMANTIC", "LINKS")
+	for _, e := range h.Entries {
+		fmt.Fprintf(w, "%-16s  %6.1f %6s %5s ", e.ComputedAt.Format("2006-01-02 15:04"), e.CLQS, formatDelta(e.Delta), e.Grade)
+		for _, c := range e.Components {
+			fmt.Fprintf(w, " %8.1f", c.Score)
+		}
+		fmt.Fprintf(w, "  %6d\n", e.TotalLinks)
+	}
+
+	if n := len(h.Entries); n >= 2 {
+		latest := h.Entries[n-1]
+		fmt.Fprintf(w, "\nCHANGE SINCE PREVIOUS RUN\n")
+		fmt.Fprintf(w, "  %-23s %6s\n", "CLQS", formatDelta(latest.Delta))
+		for _, c := range latest.Components {
+			fmt.Fprintf(w, "  %-23s %6s\n", c.Name, formatDelta(c.Delta))
+		}
+	}
+	return nil
+}
+
+// FormatHistoryJSON outputs the history as JSON
+func FormatHistoryJSON(h *History, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(h)
+}
+
+func formatDelta(delta float64) string {
+	if delta > -0.05 && delta < 0.05 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f", delta)
+}
@@ -0,0 +1,54 @@
+package clqs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+func TestHistoryCheckDrop(t *testing.T) {
+	day := time.Date(2025, 5, 1, 2, 0, 0, 0, time.UTC)
+	// Newest first, as returned by GetCLQSScoreHistory
+	scores := []database.CLQSScore{
+		{CLQS: 71.0, Grade: "C", ComputedAt: day.AddDate(0, 0, 2), LinkCoverage: 60, ConfidenceQuality: 80, EvidenceDiversity: 70, TemporalPrecision: 90, SemanticStrength: 50},
+		{CLQS: 78.5, Grade: "C+", ComputedAt: day.AddDate(0, 0, 1), LinkCoverage: 75, ConfidenceQuality: 82, EvidenceDiversity: 70, TemporalPrecision: 90, SemanticStrength: 52},
+		{CLQS: 78.0, Grade: "C+", ComputedAt: day, LinkCoverage: 74, ConfidenceQuality: 82, EvidenceDiversity: 70, TemporalPrecision: 90, SemanticStrength: 52},
+	}
+
+	h := BuildHistory("acme/api", scores)
+	if len(h.Entries) != 3 || !h.Entries[0].ComputedAt.Equal(day) {
+		t.Fatalf("expected chronological entries, got %+v", h.Entries)
+	}
+	if h.Entries[0].Delta != 0 || h.Entries[1].Delta != 0.5 || h.Entries[2].Delta != -7.5 {
+		t.Errorf("unexpected deltas: %v, %v, %v", h.Entries[0].Delta, h.Entries[1].Delta, h.Entries[2].Delta)
+	}
+
+	if r := h.CheckDrop(10); r != nil {
+		t.Errorf("drop of 7.5 should pass a 10 point threshold, got %+v", r)
+	}
+	r := h.CheckDrop(5)
+	if r == nil {
+		t.Fatal("expected regression for a 7.5 point drop")
+	}
+	if r.Drop != 7.5 || r.Previous.CLQS != 78.5 {
+		t.Errorf("unexpected regression: %+v", r)
+	}
+	if len(r.Degraded) != 3 || r.Degraded[0].Name != "Link Coverage" || r.Degraded[0].Delta != -15 {
+		t.Errorf("expected link coverage as the largest degradation, got %+v", r.Degraded)
+	}
+
+	var buf bytes.Buffer
+	if err := FormatHistory(h, &buf); err != nil {
+		t.Fatalf("FormatHistory: %v", err)
+	}
+	if !strings.Contains(buf.String(), "-7.5") || !strings.Contains(buf.String(), "Link Coverage") {
+		t.Errorf("expected latest change in output:\n%s", buf.String())
+	}
+
+	if r := BuildHistory("acme/api", scores[:1]).CheckDrop(1); r != nil {
+		t.Errorf("a single run cannot regress, got %+v", r)
+	}
+}
@@ -0,0 +1,167 @@
+// Package codeowners reads CODEOWNERS files, proposes new ones from block ownership
+// and reports where an existing file disagrees with who actually changes the code.
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+)
+
+// Locations GitHub searches for a CODEOWNERS file, in order
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is one CODEOWNERS line: a path pattern and its owners
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int // 1-based line in the source file (0 for generated rules)
+
+	re *regexp.Regexp
+}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	Rules []Rule
+}
+
+// Find returns the path of the CODEOWNERS file under root, or "" if there is none
+func Find(root string) string {
+	for _, loc := range Locations {
+		path := filepath.Join(root, loc)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// ParseFile reads and parses a CODEOWNERS file
+func ParseFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse parses CODEOWNERS content; blank lines and # comments are ignored
+func Parse(r io.Reader) (*File, error) {
+	file := &File{}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule, err := NewRule(fields[0], fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		rule.Line = lineNo
+		file.Rules = append(file.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CODEOWNERS: %w", err)
+	}
+	return file, nil
+}
+
+// NewRule compiles a pattern into a rule
+func NewRule(pattern string, owners []string) (Rule, error) {
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return Rule{Pattern: pattern, Owners: owners, re: re}, nil
+}
+
+// Matches reports whether the rule applies to a repository-relative path
+func (r *Rule) Matches(path string) bool {
+	return r.re != nil && r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// Match returns the index of the rule that owns path (the last matching one), or -1
+func (f *File) Match(path string) int {
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].Matches(path) {
+			return i
+		}
+	}
+	return -1
+}
+
+// compilePattern translates gitignore-style CODEOWNERS patterns into a regexp
+// Patterns containing a slash (other than a trailing one) are anchored at the root;
+// others match at any depth. A pattern naming a directory owns everything below it.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	p := pattern
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(strings.TrimSuffix(p, "/"), "/")
+	p = strings.TrimPrefix(p, "/")
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '*' && strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(b.String())
+}
+
+// Format renders rules as CODEOWNERS content with aligned owner columns
+func Format(rules []Rule, header string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(header), "\n") {
+		if line != "" {
+			fmt.Fprintf(&b, "# %s\n", line)
+		}
+	}
+	if header != "" {
+		b.WriteString("\n")
+	}
+
+	w := tabwriter.NewWriter(&b, 0, 0, 1, ' ', 0)
+	for _, r := range rules {
+		fmt.Fprintf(w, "%s\t%s\n", r.Pattern, strings.Join(r.Owners, " "))
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+func TestPatternMatching(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "main.go", true},
+		{"*", "internal/api/server.go", true},
+		{"*.js", "web/app/index.js", true},
+		{"*.js", "web/app/index.ts", false},
+		{"/docs/", "docs/guide/intro.md", true},
+		{"/docs/", "internal/docs/readme.md", false},
+		{"docs/", "internal/docs/readme.md", true},
+		{"/internal/api", "internal/api/server.go", true},
+		{"/internal/api", "internal/apiv2/server.go", false},
+		{"internal/*.go", "internal/main.go", true},
+		{"internal/*.go", "internal/api/server.go", false},
+		{"/internal/**/handlers", "internal/api/v1/handlers/user.go", true},
+		{"/internal/**/handlers", "internal/handlers/user.go", true},
+		{"/build/logs/**", "build/logs/2024/app.log", true},
+		{"/Makefile", "Makefile", true},
+		{"/Makefile", "sub/Makefile", false},
+	}
+
+	for _, tt := range tests {
+		rule, err := NewRule(tt.pattern, nil)
+		if err != nil {
+			t.Fatalf("NewRule(%q): %v", tt.pattern, err)
+		}
+		if got := rule.Matches(tt.path); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	content := `# Default owners
+*            @acme/core
+
+/internal/api/ @alice bob@example.com # API team
+/docs/        @acme/docs
+`
+	file, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(file.Rules) != 3 {
+		t.Fatalf("expected 3 rules, got %d", len(file.Rules))
+	}
+
+	api := file.Rules[1]
+	if api.Line != 4 || api.Pattern != "/internal/api/" || strings.Join(api.Owners, ",") != "@alice,bob@example.com" {
+		t.Errorf("unexpected rule: %+v", api)
+	}
+	if i := file.Match("internal/api/server.go"); i != 1 {
+		t.Errorf("expected last matching rule to win, got %d", i)
+	}
+	if i := file.Match("cmd/main.go"); i != 0 {
+		t.Errorf("expected default rule, got %d", i)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	ids := Identities{
+		Logins: map[string]string{"alice@example.com": "alice", "bob@example.com": "bob"},
+		Teams:  map[string]string{"carol@example.com": "@acme/payments", "dan@example.com": "@acme/payments"},
+	}
+	files := []database.FileOwnership{
+		{FilePath: "cmd/main.go", Edits: map[string]int{"alice@example.com": 40, "bob@example.com": 10}},
+		{FilePath: "internal/api/server.go", Edits: map[string]int{"alice@example.com": 20, "bob@example.com": 5}},
+		{FilePath: "internal/payments/charge.go", Edits: map[string]int{"carol@example.com": 20, "dan@example.com": 15, "bob@example.com": 5}},
+		{FilePath: "internal/payments/deep/a/b/refund.go", Edits: map[string]int{"dan@example.com": 12}},
+		{FilePath: "scripts/tiny.sh", Edits: map[string]int{"bob@example.com": 3}},
+	}
+
+	rules := Generate(files, ids, DefaultGenerateOptions())
+
+	got := make([]string, len(rules))
+	for i, r := range rules {
+		got[i] = r.Pattern + " " + strings.Join(r.Owners, " ")
+	}
+	want := []string{
+		"* @alice @acme/payments",
+		"/cmd/ @alice",
+		"/internal/ @acme/payments", // payments/ and the truncated deep/ inherit this
+		"/internal/api/ @alice",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rules:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	out := Format(rules, "Generated by crisk")
+	if !strings.HasPrefix(out, "# Generated by crisk\n\n* ") || !strings.Contains(out, "/internal/api/ @alice\n") {
+		t.Errorf("unexpected formatted output:\n%s", out)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	ids := Identities{Logins: map[string]string{"alice@example.com": "alice", "old@example.com": "old"}}
+	existing, err := Parse(strings.NewReader(`*                @alice
+/internal/api/   @old @alice
+/legacy/         @alice
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	files := []database.FileOwnership{
+		{
+			FilePath:    "internal/api/server.go",
+			Edits:       map[string]int{"alice@example.com": 10, "old@example.com": 2, "new@example.com": 8},
+			LastTouched: map[string]time.Time{"alice@example.com": now.AddDate(0, -1, 0), "old@example.com": now.AddDate(-2, 0, 0), "new@example.com": now},
+		},
+		{
+			FilePath:    "cmd/main.go",
+			Edits:       map[string]int{"alice@example.com": 5},
+			LastTouched: map[string]time.Time{"alice@example.com": now},
+		},
+	}
+
+	opts := DefaultDiffOptions()
+	opts.Now = now
+	findings := Diff(existing, files, ids, opts)
+
+	got := make([]string, len(findings))
+	for i, f := range findings {
+		got[i] = f.Kind + " " + f.Pattern + " " + f.Owner
+	}
+	want := []string{
+		"stale_owner /internal/api/ @old",
+		"missing_owner /internal/api/ new@example.com",
+		"dead_rule /legacy/ ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if !strings.Contains(findings[0].Detail, "2023-06-01") {
+		t.Errorf("expected last change date in detail, got %q", findings[0].Detail)
+	}
+
+	unowned := Diff(&File{}, files, ids, opts)
+	if len(unowned) != 2 || unowned[0].Kind != FindingUnowned || unowned[0].Pattern != "/cmd/" {
+		t.Errorf("expected unowned findings per top-level directory, got %+v", unowned)
+	}
+}
+
+func TestLoadTeams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "teams.yaml")
+	if err := os.WriteFile(path, []byte("Alice@Example.com: acme/payments\nbob@example.com: \"@acme/core\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	teams, err := LoadTeams(path)
+	if err != nil {
+		t.Fatalf("LoadTeams: %v", err)
+	}
+	if teams["alice@example.com"] != "@acme/payments" || teams["bob@example.com"] != "@acme/core" {
+		t.Errorf("unexpected teams: %v", teams)
+	}
+
+	ids := Identities{Teams: teams}
+	if emails := ids.Emails("@ACME/payments", []string{"alice@example.com", "bob@example.com"}); len(emails) != 1 || emails[0] != "alice@example.com" {
+		t.Errorf("expected team to resolve to alice, got %v", emails)
+	}
+}
@@ -0,0 +1,185 @@
+package codeowners
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// Finding kinds reported by Diff
+const (
+	FindingStaleOwner   = "stale_owner"   // Listed owner has not changed the matched paths recently
+	FindingMissingOwner = "missing_owner" // Major contributor to the matched paths is not listed
+	FindingDeadRule     = "dead_rule"     // Rule matches no file with ownership data
+	FindingUnowned      = "unowned"       // Files matched by no rule
+)
+
+// Finding is one disagreement between CODEOWNERS and actual ownership
+type Finding struct {
+	Kind    string `json:"kind"`
+	Line    int    `json:"line,omitempty"` // CODEOWNERS line (0 for unowned paths)
+	Pattern string `json:"pattern"`
+	Owner   string `json:"owner,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+// DiffOptions controls drift detection
+type DiffOptions struct {
+	StaleAfter time.Duration // Listed owners inactive for longer are stale
+	MinShare   float64       // Unlisted contributors above this share of edits are missing owners
+	Now        time.Time
+}
+
+// DefaultDiffOptions returns the standard drift thresholds
+func DefaultDiffOptions() DiffOptions {
+	return DiffOptions{
+		StaleAfter: 365 * 24 * time.Hour,
+		MinShare:   0.3,
+		Now:        time.Now(),
+	}
+}
+
+// Diff compares an existing CODEOWNERS file with actual ownership
+// Each file is attributed to the rule that owns it (last match wins), as GitHub does.
+func Diff(existing *File, files []database.FileOwnership, ids Identities, opts DiffOptions) []Finding {
+	assigned := make([][]database.FileOwnership, len(existing.Rules))
+	unowned := make(map[string]int)
+	for _, file := range files {
+		if i := existing.Match(file.FilePath); i >= 0 {
+			assigned[i] = append(assigned[i], file)
+		} else {
+			unowned[topDir(file.FilePath)]++
+		}
+	}
+
+	var findings []Finding
+	for i, rule := range existing.Rules {
+		if len(assigned[i]) == 0 {
+			findings = append(findings, Finding{
+				Kind:    FindingDeadRule,
+				Line:    rule.Line,
+				Pattern: rule.Pattern,
+				Detail:  "matches no files with ownership data (or is shadowed by later rules)",
+			})
+			continue
+		}
+		findings = append(findings, diffRule(rule, assigned[i], ids, opts)...)
+	}
+
+	dirs := make([]string, 0, len(unowned))
+	for dir := range unowned {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		pattern := "/" + dir + "/"
+		if dir == "" {
+			pattern = "/*"
+		}
+		findings = append(findings, Finding{
+			Kind:    FindingUnowned,
+			Pattern: pattern,
+			Detail:  fmt.Sprintf("%d file(s) have no owner", unowned[dir]),
+		})
+	}
+	return findings
+}
+
+// diffRule checks one rule's owners against the files it owns
+func diffRule(rule Rule, files []database.FileOwnership, ids Identities, opts DiffOptions) []Finding {
+	edits := make(map[string]int)
+	lastTouched := make(map[string]time.Time)
+	total := 0
+	for _, file := range files {
+		for email, n := range file.Edits {
+			edits[email] += n
+			total += n
+		}
+		for email, at := range file.LastTouched {
+			if at.After(lastTouched[email]) {
+				lastTouched[email] = at
+			}
+		}
+	}
+
+	known := make([]string, 0, len(edits))
+	for email := range edits {
+		known = append(known, email)
+	}
+	for email := range lastTouched {
+		if _, ok := edits[email]; !ok {
+			known = append(known, email)
+		}
+	}
+	sort.Strings(known)
+
+	var findings []Finding
+	listed := make(map[string]bool)
+	for _, owner := range rule.Owners {
+		var last time.Time
+		for _, email := range ids.Emails(owner, known) {
+			listed[email] = true
+			if lastTouched[email].After(last) {
+				last = lastTouched[email]
+			}
+		}
+
+		detail := ""
+		switch {
+		case last.IsZero():
+			detail = "has never changed the matched paths"
+		case opts.Now.Sub(last) > opts.StaleAfter:
+			detail = fmt.Sprintf("last changed the matched paths on %s", last.Format("2006-01-02"))
+		default:
+			continue
+		}
+		findings = append(findings, Finding{Kind: FindingStaleOwner, Line: rule.Line, Pattern: rule.Pattern, Owner: owner, Detail: detail})
+	}
+
+	if total == 0 {
+		return findings
+	}
+	missing := make(map[string]int)
+	for email, n := range edits {
+		if !listed[email] {
+			missing[ids.Handle(email)] += n
+		}
+	}
+	handles := make([]string, 0, len(missing))
+	for handle := range missing {
+		handles = append(handles, handle)
+	}
+	sort.Slice(handles, func(i, j int) bool {
+		if missing[handles[i]] != missing[handles[j]] {
+			return missing[handles[i]] > missing[handles[j]]
+		}
+		return handles[i] < handles[j]
+	})
+	for _, handle := range handles {
+		share := float64(missing[handle]) / float64(total)
+		if share < opts.MinShare {
+			break
+		}
+		findings = append(findings, Finding{
+			Kind:    FindingMissingOwner,
+			Line:    rule.Line,
+			Pattern: rule.Pattern,
+			Owner:   handle,
+			Detail:  fmt.Sprintf("made %.0f%% of edits but is not listed", share*100),
+		})
+	}
+	return findings
+}
+
+// topDir returns the first path component of a file's directory ("" for root files)
+func topDir(filePath string) string {
+	dir := path.Dir(strings.TrimPrefix(filePath, "/"))
+	if dir == "." {
+		return ""
+	}
+	return strings.SplitN(dir, "/", 2)[0]
+}
@@ -0,0 +1,170 @@
+package codeowners
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// GenerateOptions controls how ownership is turned into rules
+type GenerateOptions struct {
+	MinCoverage float64 // Owners are added until they account for this share of a directory's edits
+	MaxOwners   int     // Cap on owners per rule
+	MinEdits    int     // Directories with fewer edits inherit their parent's owners
+	MaxDepth    int     // Deepest directory level that gets its own rule (0 = unlimited)
+}
+
+// DefaultGenerateOptions returns the standard generation options
+func DefaultGenerateOptions() GenerateOptions {
+	return GenerateOptions{
+		MinCoverage: 0.6,
+		MaxOwners:   3,
+		MinEdits:    10,
+		MaxDepth:    3,
+	}
+}
+
+// Generate proposes directory-level CODEOWNERS rules from file ownership
+// Each directory gets the smallest set of owners covering MinCoverage of its edits
+// (recursively, with team members pooled under their team). A rule is emitted only
+// where the owners differ from the nearest ancestor's rule; the root rule is "*".
+func Generate(files []database.FileOwnership, ids Identities, opts GenerateOptions) []Rule {
+	dirEdits := make(map[string]map[string]int)
+	for _, file := range files {
+		dir := truncateDir(path.Dir(strings.TrimPrefix(file.FilePath, "/")), opts.MaxDepth)
+		for _, d := range ancestors(dir) {
+			if dirEdits[d] == nil {
+				dirEdits[d] = make(map[string]int)
+			}
+			for email, edits := range file.Edits {
+				dirEdits[d][ids.Handle(email)] += edits
+			}
+		}
+	}
+
+	dirs := make([]string, 0, len(dirEdits))
+	for d := range dirEdits {
+		dirs = append(dirs, d)
+	}
+	// Parents before children so inheritance can be checked against emitted rules
+	sort.Slice(dirs, func(i, j int) bool {
+		di, dj := strings.Count(dirs[i], "/"), strings.Count(dirs[j], "/")
+		if (dirs[i] == "") != (dirs[j] == "") {
+			return dirs[i] == ""
+		}
+		if di != dj {
+			return di < dj
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	emitted := make(map[string][]string)
+	var rules []Rule
+	for _, d := range dirs {
+		owners := selectOwners(dirEdits[d], opts)
+		if owners == nil {
+			continue
+		}
+		if inherited, ok := nearestEmitted(emitted, d); ok && strings.Join(inherited, " ") == strings.Join(owners, " ") {
+			continue
+		}
+		emitted[d] = owners
+
+		pattern := "*"
+		if d != "" {
+			pattern = "/" + d + "/"
+		}
+		rule, _ := NewRule(pattern, owners)
+		rules = append(rules, rule)
+	}
+
+	// CODEOWNERS is last-match-wins, so deeper rules go last
+	sort.SliceStable(rules, func(i, j int) bool {
+		return depth(rules[i].Pattern) < depth(rules[j].Pattern)
+	})
+	return rules
+}
+
+// selectOwners picks the top handles until they cover MinCoverage of the edits
+func selectOwners(edits map[string]int, opts GenerateOptions) []string {
+	total := 0
+	handles := make([]string, 0, len(edits))
+	for handle, n := range edits {
+		if n > 0 {
+			total += n
+			handles = append(handles, handle)
+		}
+	}
+	if total == 0 || total < opts.MinEdits {
+		return nil
+	}
+
+	sort.Slice(handles, func(i, j int) bool {
+		if edits[handles[i]] != edits[handles[j]] {
+			return edits[handles[i]] > edits[handles[j]]
+		}
+		return handles[i] < handles[j]
+	})
+
+	var owners []string
+	covered := 0
+	for _, handle := range handles {
+		if opts.MaxOwners > 0 && len(owners) >= opts.MaxOwners {
+			break
+		}
+		owners = append(owners, handle)
+		covered += edits[handle]
+		if float64(covered)/float64(total) >= opts.MinCoverage {
+			break
+		}
+	}
+	return owners
+}
+
+// ancestors returns dir and every parent directory up to the root ("")
+func ancestors(dir string) []string {
+	if dir == "." || dir == "/" {
+		dir = ""
+	}
+	out := []string{dir}
+	for dir != "" {
+		dir = path.Dir(dir)
+		if dir == "." {
+			dir = ""
+		}
+		out = append(out, dir)
+	}
+	return out
+}
+
+func truncateDir(dir string, maxDepth int) string {
+	if dir == "." {
+		return ""
+	}
+	if maxDepth <= 0 {
+		return dir
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > maxDepth {
+		parts = parts[:maxDepth]
+	}
+	return strings.Join(parts, "/")
+}
+
+func nearestEmitted(emitted map[string][]string, dir string) ([]string, bool) {
+	for _, d := range ancestors(dir)[1:] {
+		if owners, ok := emitted[d]; ok {
+			return owners, true
+		}
+	}
+	return nil, false
+}
+
+func depth(pattern string) int {
+	if pattern == "*" {
+		return 0
+	}
+	return strings.Count(strings.Trim(pattern, "/"), "/") + 1
+}
@@ -0,0 +1,85 @@
+package codeowners
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Identities maps commit emails to the handles CODEOWNERS uses
+type Identities struct {
+	Logins map[string]string // Lowercase email -> GitHub login
+	Teams  map[string]string // Lowercase email -> @org/team
+}
+
+// LoadTeams reads a YAML mapping of email to team handle, e.g.
+//
+//	alice@example.com: "@acme/payments"
+//	bob@example.com: acme/platform
+func LoadTeams(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read team mapping: %w", err)
+	}
+
+	var raw map[string]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse team mapping %s: %w", path, err)
+	}
+
+	teams := make(map[string]string, len(raw))
+	for email, team := range raw {
+		team = strings.TrimSpace(team)
+		if team == "" {
+			continue
+		}
+		if !strings.HasPrefix(team, "@") {
+			team = "@" + team
+		}
+		teams[strings.ToLower(strings.TrimSpace(email))] = team
+	}
+	return teams, nil
+}
+
+// Handle returns the owner handle for an email: its team, @login, or the email itself
+func (id Identities) Handle(email string) string {
+	email = strings.ToLower(email)
+	if team := id.Teams[email]; team != "" {
+		return team
+	}
+	if login := id.Logins[email]; login != "" {
+		return "@" + login
+	}
+	return email
+}
+
+// Emails resolves a CODEOWNERS owner (@team, @login or email) to the known emails behind it
+func (id Identities) Emails(owner string, known []string) []string {
+	var emails []string
+	for _, email := range known {
+		if id.owns(owner, email) {
+			emails = append(emails, email)
+		}
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// owns reports whether an owner entry stands for the given email
+func (id Identities) owns(owner, email string) bool {
+	email = strings.ToLower(email)
+	switch {
+	case strings.EqualFold(owner, email):
+		return true
+	case !strings.HasPrefix(owner, "@"):
+		return false
+	case strings.Contains(owner, "/"):
+		return strings.EqualFold(id.Teams[email], owner)
+	default:
+		login := id.Logins[email]
+		return login != "" && strings.EqualFold("@"+login, owner)
+	}
+}
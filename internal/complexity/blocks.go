@@ -0,0 +1,194 @@
+package complexity
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	pyDefPattern = regexp.MustCompile(`^([ \t]*)(?:async\s+)?def\s+(\w+)\s*\(`)
+
+	jsFunctionPattern = regexp.MustCompile(`^[ \t]*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\*?\s+([\w$]+)\s*(?:<[^>]*>)?\(`)
+	jsArrowPattern    = regexp.MustCompile(`^[ \t]*(?:export\s+)?(?:const|let|var)\s+([\w$]+)\s*(?::[^=]+)?=\s*(?:async\s+)?(?:function\b|\(|[\w$]+\s*=>)`)
+	// Class methods (TypeScript/JavaScript) and Java methods/constructors
+	methodPattern = regexp.MustCompile(`^[ \t]*(?:@\w+(?:\([^)]*\))?\s+)*(?:(?:public|private|protected|static|final|abstract|synchronized|native|async|override|readonly|get|set|default)\s+)*(?:[\w$<>\[\],.?]+\s+)?([\w$]+)\s*(?:<[^>]*>)?\(`)
+	// Text between a method's parameter list and its body: return type or throws clause
+	methodTailPattern = regexp.MustCompile(`^\s*(?::\s*[^{;=]+|throws\s+[\w.,\s]+)?\s*\{`)
+	arrowTailPattern  = regexp.MustCompile(`^\s*(?::\s*[^{;=]+)?=>`)
+)
+
+// notMethodNames are keywords the method pattern would otherwise take for a definition
+var notMethodNames = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "return": true,
+	"new": true, "function": true, "else": true, "do": true, "try": true, "synchronized": true,
+	"super": true, "this": true, "throw": true, "typeof": true, "await": true,
+}
+
+// analyzePython locates def blocks by indentation; methods and nested functions are
+// reported under their own names
+func analyzePython(src string) []Block {
+	masked := strings.Split(mask(src, "python"), "\n")
+	var blocks []Block
+	for i, line := range masked {
+		m := pyDefPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent := len(m[1])
+
+		// The body ends before the next non-blank line indented no deeper than the def
+		end := i
+		for j := i + 1; j < len(masked); j++ {
+			trimmed := strings.TrimSpace(masked[j])
+			if trimmed == "" {
+				continue
+			}
+			if len(masked[j])-len(strings.TrimLeft(masked[j], " \t")) <= indent && !strings.HasPrefix(trimmed, ")") {
+				break
+			}
+			end = j
+		}
+		blocks = append(blocks, measureBlock(m[2], masked, i, end, "python"))
+	}
+	return blocks
+}
+
+// analyzeBraces locates functions and methods whose bodies are brace-delimited
+// (JavaScript, TypeScript, Java, and Go sources that do not parse)
+func analyzeBraces(src, language string) []Block {
+	maskedSrc := mask(src, language)
+	masked := strings.Split(maskedSrc, "\n")
+
+	lineStarts := make([]int, len(masked))
+	offset := 0
+	for i, line := range masked {
+		lineStarts[i] = offset
+		offset += len(line) + 1
+	}
+	lineOf := func(off int) int {
+		lo, hi := 0, len(lineStarts)-1
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if lineStarts[mid] <= off {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		return lo
+	}
+
+	var blocks []Block
+	for i, line := range masked {
+		name, open := "", -1
+		switch {
+		case language == "go" && strings.HasPrefix(line, "func "):
+			name, open = goFuncName(line), strings.Index(line, "(")
+		case language != "go" && jsFunctionPattern.MatchString(line):
+			name = jsFunctionPattern.FindStringSubmatch(line)[1]
+			open = len(jsFunctionPattern.FindString(line)) - 1
+		case language != "go" && jsArrowPattern.MatchString(line):
+			name = jsArrowPattern.FindStringSubmatch(line)[1]
+			open = strings.Index(line, "=")
+		case language != "go" && methodPattern.MatchString(line):
+			m := methodPattern.FindStringSubmatch(line)
+			if notMethodNames[m[1]] {
+				continue
+			}
+			name, open = m[1], len(methodPattern.FindString(line))-1
+		}
+		if name == "" || open < 0 {
+			continue
+		}
+
+		// Body: the first brace after the parameter list, or for an expression-bodied
+		// arrow function, the rest of the line
+		from := lineStarts[i] + open
+		rest := maskedSrc[from:]
+		bodyFrom := from
+		paren := strings.IndexByte(rest, '(')
+		arrow := strings.Index(rest, "=>")
+		brace := strings.IndexByte(rest, '{')
+		switch {
+		case language == "go":
+			if !strings.HasSuffix(strings.TrimSpace(line), "{") {
+				continue // Multi-line signature
+			}
+			bodyFrom = lineStarts[i] + strings.LastIndex(line, "{")
+		case paren >= 0 && (brace < 0 || paren < brace) && (arrow < 0 || paren < arrow):
+			closeParen := matchingParen(maskedSrc, from+paren)
+			if closeParen < 0 {
+				continue
+			}
+			tail := maskedSrc[closeParen+1:]
+			if loc := arrowTailPattern.FindStringIndex(tail); loc != nil {
+				if !strings.HasPrefix(strings.TrimSpace(tail[loc[1]:]), "{") {
+					blocks = append(blocks, measureBlock(name, masked, i, i, language))
+					continue
+				}
+			} else if !methodTailPattern.MatchString(tail) {
+				continue // A call, not a definition
+			}
+			bodyFrom = closeParen
+		case arrow >= 0 && (brace < 0 || arrow < brace):
+			if !strings.HasPrefix(strings.TrimSpace(rest[arrow+2:]), "{") {
+				blocks = append(blocks, measureBlock(name, masked, i, i, language))
+				continue
+			}
+			bodyFrom = from + arrow
+		}
+
+		body := strings.IndexByte(maskedSrc[bodyFrom:], '{')
+		if body < 0 {
+			continue
+		}
+		body += bodyFrom
+		end := matchingBrace(maskedSrc, body)
+		if end < 0 {
+			continue
+		}
+		blocks = append(blocks, measureBlock(name, masked, i, lineOf(end), language))
+	}
+	return blocks
+}
+
+// measureBlock measures masked lines first..last (0-based, inclusive)
+func measureBlock(name string, masked []string, first, last int, language string) Block {
+	lines := masked[first : last+1]
+	m := measureMasked(lines, language)
+	m.Parameters = countParameters(strings.Join(lines[:min(len(lines), 10)], "\n"), language)
+	return Block{Name: name, StartLine: first + 1, EndLine: last + 1, Metrics: m}
+}
+
+// matchingBrace returns the offset of the brace closing the one at open, or -1
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// goFuncName returns the name in a Go func declaration line, skipping any receiver
+func goFuncName(line string) string {
+	rest := strings.TrimSpace(strings.TrimPrefix(line, "func "))
+	if strings.HasPrefix(rest, "(") {
+		end := matchingParen(rest, 0)
+		if end < 0 {
+			return ""
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+	if i := strings.IndexAny(rest, "([ "); i > 0 {
+		return rest[:i]
+	}
+	return ""
+}
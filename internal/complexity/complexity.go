@@ -0,0 +1,268 @@
+// Package complexity measures the structure of code blocks: cyclomatic complexity,
+// nesting depth, parameter count and length.
+//
+// Go is parsed with go/ast. Python, JavaScript/TypeScript and Java blocks are located by
+// their definitions (indentation or brace matching) and measured from their tokens after
+// comments and string literals are masked, which is precise enough to rank blocks and to
+// track how a change moves them.
+package complexity
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Metrics are the structural measures of one block
+type Metrics struct {
+	Cyclomatic int `json:"cyclomatic"`  // Decision points + 1
+	MaxNesting int `json:"max_nesting"` // Deepest nesting of control structures (0 = straight-line code)
+	Parameters int `json:"parameters"`
+	Lines      int `json:"lines"` // Non-blank, non-comment lines
+}
+
+// Block is a function or method and its metrics
+type Block struct {
+	Name      string  `json:"name"`
+	StartLine int     `json:"start_line"` // 1-based, inclusive
+	EndLine   int     `json:"end_line"`
+	Metrics   Metrics `json:"metrics"`
+}
+
+// languages maps file extensions to language names
+var languages = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".mjs":   "javascript",
+	".cjs":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".java":  "java",
+	".kt":    "kotlin",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".php":   "php",
+	".swift": "swift",
+	".scala": "scala",
+}
+
+// LanguageOf returns the language of a file, or "" for non-source files
+func LanguageOf(file string) string {
+	return languages[strings.ToLower(path.Ext(file))]
+}
+
+// LanguageForExtension returns the language of an extension such as "py" or ".ts"
+func LanguageForExtension(ext string) string {
+	return languages["."+strings.TrimPrefix(strings.ToLower(ext), ".")]
+}
+
+// Supported reports whether Analyze can locate blocks in a language
+// Measure works on any language in LanguageOf.
+func Supported(language string) bool {
+	switch language {
+	case "go", "python", "javascript", "typescript", "java":
+		return true
+	}
+	return false
+}
+
+// Analyze returns the functions and methods in a source file with their metrics
+// Unsupported languages return nil.
+func Analyze(src []byte, language string) []Block {
+	switch language {
+	case "go":
+		if blocks, ok := analyzeGo(src); ok {
+			return blocks
+		}
+		return analyzeBraces(string(src), language) // Does not parse: measure what we can
+	case "python":
+		return analyzePython(string(src))
+	case "javascript", "typescript", "java":
+		return analyzeBraces(string(src), language)
+	}
+	return nil
+}
+
+// Find returns the first block with the given name
+func Find(blocks []Block, name string) (Block, bool) {
+	for _, b := range blocks {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return Block{}, false
+}
+
+// Measure computes the metrics of source lines
+// When lines start with a definition, its parameters are counted; a whole file measures
+// its cyclomatic complexity, nesting and length.
+func Measure(lines []string, language string) Metrics {
+	masked := strings.Split(mask(strings.Join(lines, "\n"), language), "\n")
+	m := measureMasked(masked, language)
+	if len(masked) > 0 {
+		m.Parameters = countParameters(strings.Join(masked[:min(len(masked), 10)], "\n"), language)
+	}
+	return m
+}
+
+var (
+	// Branching keywords across languages; "else if" counts once through its "if"
+	decisionKeywords = regexp.MustCompile(`\b(if|elif|elsif|for|foreach|while|until|unless|case|when|catch|except|rescue)\b`)
+	// Short-circuit operators, and their word forms in Python and Ruby
+	decisionOperators = regexp.MustCompile(`&&|\|\|`)
+	wordOperators     = regexp.MustCompile(`\b(and|or)\b`)
+	// Statements that open a nested control structure
+	controlStatement = regexp.MustCompile(`^(?:\}\s*)?(if|else|for|foreach|while|do|switch|select|try|catch|finally)\b`)
+	pythonControl    = regexp.MustCompile(`^(if|elif|else|for|while|try|except|finally|with|match|case)\b`)
+)
+
+// measureMasked computes cyclomatic complexity, nesting and length of masked lines
+func measureMasked(lines []string, language string) Metrics {
+	m := Metrics{Cyclomatic: 1}
+	indentNesting := language == "python"
+
+	var braces []bool // Open braces; true when opened by a control statement
+	var indents []int // Python: indentation of enclosing control statements
+	controlDepth := 0 // Control braces currently open
+
+	for _, line := range lines {
+		code := strings.TrimSpace(line)
+		if code == "" {
+			continue
+		}
+		m.Lines++
+		m.Cyclomatic += len(decisionKeywords.FindAllString(code, -1))
+		m.Cyclomatic += len(decisionOperators.FindAllString(code, -1))
+		if language == "python" || language == "ruby" {
+			m.Cyclomatic += len(wordOperators.FindAllString(code, -1))
+		}
+
+		if indentNesting {
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			for len(indents) > 0 && indents[len(indents)-1] >= indent {
+				indents = indents[:len(indents)-1]
+			}
+			if pythonControl.MatchString(code) && strings.HasSuffix(code, ":") {
+				indents = append(indents, indent)
+				m.MaxNesting = max(m.MaxNesting, len(indents))
+			}
+			continue
+		}
+
+		control := controlStatement.MatchString(code)
+		opened := false
+		for _, c := range code {
+			switch c {
+			case '{':
+				isControl := control && !opened
+				opened = true
+				braces = append(braces, isControl)
+				if isControl {
+					controlDepth++
+					m.MaxNesting = max(m.MaxNesting, controlDepth)
+				}
+			case '}':
+				if n := len(braces); n > 0 {
+					if braces[n-1] {
+						controlDepth--
+					}
+					braces = braces[:n-1]
+				}
+			}
+		}
+		if control && !opened && !strings.HasPrefix(code, "}") {
+			// Braceless body, e.g. `if (done) return;`
+			m.MaxNesting = max(m.MaxNesting, controlDepth+1)
+		}
+	}
+	return m
+}
+
+// countParameters counts the parameters of the definition starting a masked span
+func countParameters(def, language string) int {
+	if language == "go" && strings.HasPrefix(strings.TrimSpace(def), "func (") {
+		// Skip the receiver
+		start := strings.Index(def, "(")
+		end := matchingParen(def, start)
+		if end < 0 {
+			return 0
+		}
+		def = def[end+1:]
+	}
+
+	start := strings.Index(def, "(")
+	if arrow := strings.Index(def, "=>"); arrow >= 0 && (start < 0 || arrow < start) {
+		return 1 // Single bare parameter: x => ...
+	}
+	if start < 0 {
+		return 0
+	}
+	end := matchingParen(def, start)
+	if end < 0 {
+		return 0
+	}
+
+	count := 0
+	for _, param := range splitTopLevel(def[start+1 : end]) {
+		param = strings.TrimSpace(param)
+		if param == "" || param == "*" || param == "/" {
+			continue
+		}
+		name := strings.Trim(strings.Fields(param)[0], "*&.:")
+		if (language == "python" && (name == "self" || name == "cls")) || strings.HasPrefix(param, "this:") {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// matchingParen returns the offset of the parenthesis closing the one at open, or -1
+func matchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits a parameter list on commas outside brackets and generics
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			if depth > 0 && !(s[i] == '>' && i > 0 && s[i-1] == '=') {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
@@ -0,0 +1,190 @@
+package complexity
+
+import (
+	"strings"
+	"testing"
+)
+
+func blockNamed(t *testing.T, blocks []Block, name string) Metrics {
+	t.Helper()
+	b, ok := Find(blocks, name)
+	if !ok {
+		t.Fatalf("block %s not found in %+v", name, blocks)
+	}
+	return b.Metrics
+}
+
+func TestAnalyzeGo(t *testing.T) {
+	src := `package billing
+
+// ProcessRefund is called when "if" appears in a comment or string
+func (s *Service) ProcessRefund(id string, amount int, force bool) error {
+	if amount <= 0 || id == "" {
+		return errInvalid
+	} else if force {
+		return nil
+	}
+	for _, item := range s.items {
+		switch {
+		case item.ID == id && item.Paid:
+			if amount > item.Total {
+				return errTooMuch
+			}
+		case item.ID == id:
+			return errUnpaid
+		}
+	}
+	return nil
+}
+
+func noop() {}
+`
+	blocks := Analyze([]byte(src), "go")
+	if len(blocks) != 2 {
+		t.Fatalf("got %d blocks, want 2: %+v", len(blocks), blocks)
+	}
+
+	// if, ||, else if, range, two cases, &&, nested if
+	got := blockNamed(t, blocks, "ProcessRefund")
+	want := Metrics{Cyclomatic: 9, MaxNesting: 3, Parameters: 3, Lines: 18}
+	if got != want {
+		t.Errorf("ProcessRefund = %+v, want %+v", got, want)
+	}
+	if b, _ := Find(blocks, "ProcessRefund"); b.StartLine != 4 || b.EndLine != 21 {
+		t.Errorf("ProcessRefund spans %d-%d, want 4-21", b.StartLine, b.EndLine)
+	}
+	if got := blockNamed(t, blocks, "noop"); got != (Metrics{Cyclomatic: 1, Lines: 1}) {
+		t.Errorf("noop = %+v", got)
+	}
+
+	// The token-based fallback agrees on decision points for unparseable sources
+	broken := strings.Replace(src, "package billing", "package billing\n\nfunc broken( {", 1)
+	fallback := blockNamed(t, Analyze([]byte(broken), "go"), "ProcessRefund")
+	if fallback.Cyclomatic != 9 || fallback.Parameters != 3 {
+		t.Errorf("fallback ProcessRefund = %+v, want cyclomatic 9, 3 parameters", fallback)
+	}
+}
+
+func TestAnalyzePython(t *testing.T) {
+	src := `class Refunds:
+    def process(self, refund_id, amount=0):
+        """Process a refund; if this mentions while it is not a branch"""
+        # for comments do not count
+        if amount <= 0 or not refund_id:
+            raise ValueError("if")
+        for item in self.items:
+            if item.id == refund_id and item.paid:
+                return item
+        return None
+
+    def noop(self):
+        pass
+
+
+def helper(
+    a,
+    b,
+):
+    return a + b
+`
+	blocks := Analyze([]byte(src), "python")
+	if len(blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3: %+v", len(blocks), blocks)
+	}
+	got := blockNamed(t, blocks, "process")
+	want := Metrics{Cyclomatic: 6, MaxNesting: 2, Parameters: 2, Lines: 8}
+	if got != want {
+		t.Errorf("process = %+v, want %+v", got, want)
+	}
+	if got := blockNamed(t, blocks, "helper"); got.Parameters != 2 || got.Lines != 5 {
+		t.Errorf("helper = %+v, want 2 parameters over 5 lines", got)
+	}
+}
+
+func TestAnalyzeBraces(t *testing.T) {
+	ts := `import { retry } from './retry';
+
+export async function fetchAll(urls: string[], limit: number): Promise<string[]> {
+  const out = [];
+  for (const url of urls) {
+    if (out.length >= limit || !url) break;
+    out.push(await retry(() => fetch(url)));
+  }
+  return out;
+}
+
+export const double = (n: number) => n * 2;
+
+export class Client {
+  request(url: string, { retries = 3 }: Options): Promise<Response> {
+    try {
+      return this.send(url);
+    } catch (e) {
+      return retries > 0 ? this.request(url, { retries: retries - 1 }) : null;
+    }
+  }
+}
+`
+	blocks := Analyze([]byte(ts), "typescript")
+	if got := blockNamed(t, blocks, "fetchAll"); got != (Metrics{Cyclomatic: 4, MaxNesting: 2, Parameters: 2, Lines: 8}) {
+		t.Errorf("fetchAll = %+v", got)
+	}
+	if got := blockNamed(t, blocks, "double"); got.Parameters != 1 || got.Lines != 1 {
+		t.Errorf("double = %+v", got)
+	}
+	if got := blockNamed(t, blocks, "request"); got.Cyclomatic != 2 || got.Parameters != 2 || got.MaxNesting != 1 {
+		t.Errorf("request = %+v, want cyclomatic 2, 2 parameters, nesting 1", got)
+	}
+	for _, b := range blocks {
+		if b.Name == "retry" || b.Name == "fetch" || b.Name == "push" {
+			t.Errorf("call %s mistaken for a definition", b.Name)
+		}
+	}
+
+	java := `public class Refunds {
+    @Override
+    public Refund process(String id, int amount) throws RefundException {
+        while (pending(id)) {
+            if (amount > 0 && id != null) {
+                return submit(id);
+            }
+        }
+        return null;
+    }
+}
+`
+	if got := blockNamed(t, Analyze([]byte(java), "java"), "process"); got != (Metrics{Cyclomatic: 4, MaxNesting: 2, Parameters: 2, Lines: 8}) {
+		t.Errorf("java process = %+v", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := []Block{
+		{Name: "ProcessRefund", Metrics: Metrics{Cyclomatic: 12, MaxNesting: 2, Parameters: 2, Lines: 40}},
+		{Name: "Format", Metrics: Metrics{Cyclomatic: 2, Lines: 5}},
+		{Name: "Legacy", Metrics: Metrics{Cyclomatic: 3, Lines: 9}},
+	}
+	after := []Block{
+		{Name: "ProcessRefund", Metrics: Metrics{Cyclomatic: 21, MaxNesting: 4, Parameters: 2, Lines: 62}},
+		{Name: "Format", Metrics: Metrics{Cyclomatic: 2, Lines: 6}},
+		{Name: "Validate", Metrics: Metrics{Cyclomatic: 5, MaxNesting: 1, Parameters: 1, Lines: 12}},
+	}
+
+	deltas := Compare(before, after)
+	if len(deltas) != 4 {
+		t.Fatalf("got %d deltas, want 4: %+v", len(deltas), deltas)
+	}
+	if got := deltas[0].Describe(); got != "raised ProcessRefund complexity from 12 to 21, nesting 2 → 4" {
+		t.Errorf("Describe() = %q", got)
+	}
+	structural := 0
+	for _, d := range deltas {
+		if d.Structural() {
+			structural++
+		}
+	}
+	// ProcessRefund and the added Validate; Format only grew and Legacy was removed
+	if structural != 2 {
+		t.Errorf("got %d structural deltas, want 2", structural)
+	}
+}
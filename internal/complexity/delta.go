@@ -0,0 +1,105 @@
+package complexity
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Delta is how a change moved one block's metrics
+type Delta struct {
+	Name   string   `json:"name"`
+	Before *Metrics `json:"before,omitempty"` // Nil for an added block
+	After  *Metrics `json:"after,omitempty"`  // Nil for a removed block
+}
+
+// Compare matches blocks by name and returns those whose metrics changed
+// Blocks are matched on their first occurrence; overloads and nested functions sharing
+// a name compare against the first definition.
+func Compare(before, after []Block) []Delta {
+	old := make(map[string]Metrics, len(before))
+	for _, b := range before {
+		if _, seen := old[b.Name]; !seen {
+			old[b.Name] = b.Metrics
+		}
+	}
+
+	var deltas []Delta
+	seen := make(map[string]bool, len(after))
+	for _, b := range after {
+		if seen[b.Name] {
+			continue
+		}
+		seen[b.Name] = true
+		after := b.Metrics
+		if prev, ok := old[b.Name]; !ok {
+			deltas = append(deltas, Delta{Name: b.Name, After: &after})
+		} else if prev != after {
+			deltas = append(deltas, Delta{Name: b.Name, Before: &prev, After: &after})
+		}
+	}
+	for name, prev := range old {
+		if !seen[name] {
+			prev := prev
+			deltas = append(deltas, Delta{Name: name, Before: &prev})
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if ci, cj := deltas[i].CyclomaticChange(), deltas[j].CyclomaticChange(); ci != cj {
+			return ci > cj
+		}
+		return deltas[i].Name < deltas[j].Name
+	})
+	return deltas
+}
+
+// CyclomaticChange is after minus before; an added block counts its full complexity
+func (d Delta) CyclomaticChange() int {
+	var before, after int
+	if d.Before != nil {
+		before = d.Before.Cyclomatic
+	}
+	if d.After != nil {
+		after = d.After.Cyclomatic
+	}
+	return after - before
+}
+
+// Structural reports whether the change moved complexity, nesting or parameters rather
+// than only length
+func (d Delta) Structural() bool {
+	if d.Before == nil || d.After == nil {
+		return d.After != nil
+	}
+	return d.Before.Cyclomatic != d.After.Cyclomatic ||
+		d.Before.MaxNesting != d.After.MaxNesting ||
+		d.Before.Parameters != d.After.Parameters
+}
+
+// Describe summarizes the delta, e.g. "raised ProcessRefund complexity from 12 to 21"
+func (d Delta) Describe() string {
+	switch {
+	case d.Before == nil && d.After != nil:
+		return fmt.Sprintf("added %s with complexity %d (nesting %d, %d parameters)",
+			d.Name, d.After.Cyclomatic, d.After.MaxNesting, d.After.Parameters)
+	case d.After == nil:
+		return fmt.Sprintf("removed %s (complexity %d)", d.Name, d.Before.Cyclomatic)
+	}
+
+	var desc string
+	switch change := d.CyclomaticChange(); {
+	case change > 0:
+		desc = fmt.Sprintf("raised %s complexity from %d to %d", d.Name, d.Before.Cyclomatic, d.After.Cyclomatic)
+	case change < 0:
+		desc = fmt.Sprintf("lowered %s complexity from %d to %d", d.Name, d.Before.Cyclomatic, d.After.Cyclomatic)
+	default:
+		desc = fmt.Sprintf("kept %s complexity at %d", d.Name, d.After.Cyclomatic)
+	}
+	if d.Before.MaxNesting != d.After.MaxNesting {
+		desc += fmt.Sprintf(", nesting %d → %d", d.Before.MaxNesting, d.After.MaxNesting)
+	}
+	if d.Before.Parameters != d.After.Parameters {
+		desc += fmt.Sprintf(", parameters %d → %d", d.Before.Parameters, d.After.Parameters)
+	}
+	return desc
+}
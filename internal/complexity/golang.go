@@ -0,0 +1,96 @@
+package complexity
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// analyzeGo measures every function and method declaration; ok is false when the file
+// does not parse
+func analyzeGo(src []byte) ([]Block, bool) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, false
+	}
+	lines := strings.Split(mask(string(src), "go"), "\n")
+
+	var blocks []Block
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		start := fset.Position(fn.Pos()).Line
+		end := fset.Position(fn.End()).Line
+
+		m := Metrics{Cyclomatic: 1}
+		for _, field := range fn.Type.Params.List {
+			m.Parameters += max(len(field.Names), 1)
+		}
+		for _, line := range lines[start-1 : min(end, len(lines))] {
+			if strings.TrimSpace(line) != "" {
+				m.Lines++
+			}
+		}
+		walkGo(fn.Body, 0, &m)
+
+		blocks = append(blocks, Block{Name: fn.Name.Name, StartLine: start, EndLine: end, Metrics: m})
+	}
+	return blocks, true
+}
+
+// walkGo counts decision points and tracks control-structure nesting below node
+func walkGo(node ast.Node, depth int, m *Metrics) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		if _, isStmt := n.(ast.Stmt); isStmt && n == node {
+			return true // The caller counted this statement
+		}
+		switch s := n.(type) {
+		case *ast.IfStmt:
+			walkIf(s, depth+1, m)
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			m.Cyclomatic++
+			m.MaxNesting = max(m.MaxNesting, depth+1)
+			walkGo(s, depth+1, m)
+			return false
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			m.MaxNesting = max(m.MaxNesting, depth+1)
+			walkGo(s, depth+1, m)
+			return false
+		case *ast.CaseClause:
+			if s.List != nil {
+				m.Cyclomatic++
+			}
+		case *ast.CommClause:
+			if s.Comm != nil {
+				m.Cyclomatic++
+			}
+		case *ast.BinaryExpr:
+			if s.Op == token.LAND || s.Op == token.LOR {
+				m.Cyclomatic++
+			}
+		}
+		return true
+	})
+}
+
+// walkIf handles an if/else-if chain at one nesting level
+func walkIf(s *ast.IfStmt, depth int, m *Metrics) {
+	m.Cyclomatic++
+	m.MaxNesting = max(m.MaxNesting, depth)
+	if s.Init != nil {
+		walkGo(s.Init, depth-1, m)
+	}
+	walkGo(s.Cond, depth-1, m)
+	walkGo(s.Body, depth, m)
+	switch e := s.Else.(type) {
+	case *ast.IfStmt:
+		walkIf(e, depth, m)
+	case *ast.BlockStmt:
+		walkGo(e, depth, m)
+	}
+}
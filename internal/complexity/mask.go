@@ -0,0 +1,95 @@
+package complexity
+
+import "strings"
+
+// mask blanks comments and the contents of string literals, keeping offsets and newlines
+// so masked lines line up with the original source. Quote delimiters are kept so a
+// string-only line still counts as code.
+func mask(src, language string) string {
+	hashComments := language == "python" || language == "ruby"
+	out := []byte(src)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(out); i++ {
+			if out[i] != '\n' {
+				out[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case hashComments && c == '#', !hashComments && strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			blank(i, i+end)
+			i += end
+
+		case !hashComments && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			} else {
+				end += 2
+			}
+			blank(i, i+2+end)
+			i += 2 + end
+
+		case language == "python" && (strings.HasPrefix(src[i:], `"""`) || strings.HasPrefix(src[i:], `'''`)):
+			quote := src[i : i+3]
+			end := strings.Index(src[i+3:], quote)
+			if end < 0 {
+				end = len(src) - i - 3
+			}
+			blank(i+3, i+3+end)
+			i += 3 + end + 3
+
+		case c == '"' || c == '`' || (c == '\'' && isStringQuote(src[i:], language)):
+			end := closingQuote(src, i+1, c)
+			blank(i+1, end)
+			i = end + 1
+
+		default:
+			i++
+		}
+	}
+	return string(out)
+}
+
+// isStringQuote reports whether a single quote starts a literal: always in languages with
+// single-quoted strings, otherwise only for a short char literal (so Rust lifetimes and
+// apostrophes are left alone)
+func isStringQuote(s, language string) bool {
+	switch language {
+	case "python", "ruby", "javascript", "typescript", "php":
+		return true
+	}
+	for n := 2; n <= 4 && n < len(s); n++ {
+		if s[n] == '\'' {
+			return true
+		}
+	}
+	return false
+}
+
+// closingQuote returns the offset of the quote closing a literal opened before from
+// Unterminated literals other than backtick strings end at the line break.
+func closingQuote(src string, from int, quote byte) int {
+	for i := from; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case '\n':
+			if quote != '`' {
+				return i
+			}
+		case quote:
+			return i
+		}
+	}
+	return len(src)
+}
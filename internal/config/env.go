@@ -104,6 +104,19 @@ func (e *EnvLoader) ValidateWithGitHub() error {
 	return nil
 }
 
+// ValidateWithGitLab validates including GitLab token (for init command on GitLab projects)
+func (e *EnvLoader) ValidateWithGitLab() error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	if os.Getenv("GITLAB_TOKEN") == "" {
+		return fmt.Errorf("GITLAB_TOKEN is required for fetching repository data.\nCreate a token with read_api scope under GitLab → Preferences → Access Tokens")
+	}
+
+	return nil
+}
+
 // findEnvFile searches for .env file based on deployment mode
 func findEnvFile() (string, error) {
 	// In development mode, look for .env relative to the binary location
@@ -0,0 +1,220 @@
+// Package coverage parses CI coverage reports (Go cover profiles, LCOV, Cobertura, JaCoCo)
+// into per-line hit counts keyed by repository-relative file path.
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Format is a coverage report format
+type Format string
+
+const (
+	FormatGo        Format = "go"
+	FormatLCOV      Format = "lcov"
+	FormatCobertura Format = "cobertura"
+	FormatJaCoCo    Format = "jacoco"
+)
+
+// Formats lists the supported formats
+var Formats = []Format{FormatGo, FormatLCOV, FormatCobertura, FormatJaCoCo}
+
+// FileCoverage is the line coverage of one file
+// Lines holds executable lines only: line number -> hit count.
+type FileCoverage struct {
+	Path  string
+	Lines map[int]int
+}
+
+// Covered returns the executed lines, ascending
+func (f *FileCoverage) Covered() []int {
+	return f.linesWhere(func(hits int) bool { return hits > 0 })
+}
+
+// Uncovered returns the executable lines no test reached, ascending
+func (f *FileCoverage) Uncovered() []int {
+	return f.linesWhere(func(hits int) bool { return hits == 0 })
+}
+
+// Ratio is the share of executable lines covered (0 when nothing is executable)
+func (f *FileCoverage) Ratio() float64 {
+	if len(f.Lines) == 0 {
+		return 0
+	}
+	return float64(len(f.Covered())) / float64(len(f.Lines))
+}
+
+func (f *FileCoverage) linesWhere(keep func(hits int) bool) []int {
+	lines := make([]int, 0, len(f.Lines))
+	for line, hits := range f.Lines {
+		if keep(hits) {
+			lines = append(lines, line)
+		}
+	}
+	sort.Ints(lines)
+	return lines
+}
+
+// addHits records hits for a line, keeping the highest count when a line is reported twice
+func (f *FileCoverage) addHits(line, hits int) {
+	if line <= 0 {
+		return
+	}
+	if current, ok := f.Lines[line]; !ok || hits > current {
+		f.Lines[line] = hits
+	}
+}
+
+// Report is a parsed coverage report
+type Report struct {
+	Format Format
+	Files  map[string]*FileCoverage
+}
+
+func newReport(format Format) *Report {
+	return &Report{Format: format, Files: make(map[string]*FileCoverage)}
+}
+
+// file returns the coverage for a path, creating it on first use
+func (r *Report) file(filePath string) *FileCoverage {
+	fc, ok := r.Files[filePath]
+	if !ok {
+		fc = &FileCoverage{Path: filePath, Lines: make(map[int]int)}
+		r.Files[filePath] = fc
+	}
+	return fc
+}
+
+// Summary returns covered and executable line counts across all files
+func (r *Report) Summary() (covered, total int) {
+	for _, fc := range r.Files {
+		covered += len(fc.Covered())
+		total += len(fc.Lines)
+	}
+	return covered, total
+}
+
+// BlockCoverage is the coverage of an atomized code block's line range
+type BlockCoverage struct {
+	BlockID    int64
+	Name       string
+	StartLine  int
+	EndLine    int
+	Covered    int // Executed lines in the range
+	Executable int // Executable lines in the range
+}
+
+// Parse reads a report in the given format; an empty format is detected from the content
+func Parse(r io.Reader, format Format) (*Report, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage report: %w", err)
+	}
+	if format == "" {
+		if format = DetectFormat(data); format == "" {
+			return nil, fmt.Errorf("unrecognized coverage report format (use --format)")
+		}
+	}
+
+	switch format {
+	case FormatGo:
+		return parseGoProfile(data)
+	case FormatLCOV:
+		return parseLCOV(data)
+	case FormatCobertura:
+		return parseCobertura(data)
+	case FormatJaCoCo:
+		return parseJaCoCo(data)
+	}
+	return nil, fmt.Errorf("unsupported coverage format %q", format)
+}
+
+// DetectFormat guesses the format from the start of a report; empty when unknown
+func DetectFormat(data []byte) Format {
+	head := data
+	if len(head) > 4096 {
+		head = head[:4096]
+	}
+	trimmed := bytes.TrimSpace(head)
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return FormatGo
+	case bytes.HasPrefix(trimmed, []byte("TN:")), bytes.HasPrefix(trimmed, []byte("SF:")):
+		return FormatLCOV
+	case bytes.Contains(head, []byte("<coverage")):
+		return FormatCobertura
+	case bytes.Contains(head, []byte("<report")), bytes.Contains(head, []byte("JACOCO")):
+		return FormatJaCoCo
+	}
+	return ""
+}
+
+// Resolve maps report paths onto repository files and drops the rest
+// Reports name files differently (Go import paths, absolute CI paths, JaCoCo package paths):
+// a path matches a repository file exactly, after stripping modulePath, or by the longest
+// unambiguous suffix on path boundaries. Returns the unmatched report paths.
+func Resolve(report *Report, repoFiles []string, modulePath string) (*Report, []string) {
+	bySuffix := make(map[string][]string)
+	exact := make(map[string]bool, len(repoFiles))
+	for _, f := range repoFiles {
+		f = path.Clean(f)
+		exact[f] = true
+		parts := strings.Split(f, "/")
+		for i := range parts {
+			suffix := strings.Join(parts[i:], "/")
+			bySuffix[suffix] = append(bySuffix[suffix], f)
+		}
+	}
+
+	resolved := newReport(report.Format)
+	var unmatched []string
+	for reportPath, fc := range report.Files {
+		repoPath := resolvePath(reportPath, modulePath, exact, bySuffix)
+		if repoPath == "" {
+			unmatched = append(unmatched, reportPath)
+			continue
+		}
+		target := resolved.file(repoPath)
+		for line, hits := range fc.Lines {
+			target.addHits(line, hits)
+		}
+	}
+	sort.Strings(unmatched)
+	return resolved, unmatched
+}
+
+func resolvePath(reportPath, modulePath string, exact map[string]bool, bySuffix map[string][]string) string {
+	p := strings.TrimPrefix(path.Clean(strings.ReplaceAll(reportPath, "\\", "/")), "./")
+	if modulePath != "" {
+		p = strings.TrimPrefix(p, modulePath+"/")
+	}
+	if exact[p] {
+		return p
+	}
+
+	parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i := range parts {
+		candidates := bySuffix[strings.Join(parts[i:], "/")]
+		if len(candidates) == 1 {
+			return candidates[0]
+		}
+		if len(candidates) > 1 {
+			return "" // Ambiguous at the longest matching suffix
+		}
+	}
+	return ""
+}
+
+// newLineScanner scans a text report line by line, allowing long lines
+func newLineScanner(data []byte) *bufio.Scanner {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	return scanner
+}
@@ -0,0 +1,163 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGoProfile(t *testing.T) {
+	profile := `mode: set
+github.com/acme/shop/billing/invoice.go:10.30,12.2 2 1
+github.com/acme/shop/billing/invoice.go:12.2,14.3 1 0
+github.com/acme/shop/billing/invoice.go:20.10,20.40 1 0
+`
+	report, err := Parse(strings.NewReader(profile), "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if report.Format != FormatGo {
+		t.Errorf("Format = %q, want go", report.Format)
+	}
+
+	fc := report.Files["github.com/acme/shop/billing/invoice.go"]
+	if fc == nil {
+		t.Fatalf("missing file, got %v", report.Files)
+	}
+	// Line 12 is shared by a covered and an uncovered block: the covered count wins
+	if got, want := fc.Covered(), []int{10, 11, 12}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Covered() = %v, want %v", got, want)
+	}
+	if got, want := fc.Uncovered(), []int{13, 14, 20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Uncovered() = %v, want %v", got, want)
+	}
+
+	if _, err := Parse(strings.NewReader("mode: set\nbroken line\n"), FormatGo); err == nil {
+		t.Error("expected error for malformed profile line")
+	}
+}
+
+func TestParseLCOV(t *testing.T) {
+	lcov := `TN:
+SF:/home/runner/work/shop/src/cart.js
+FN:3,addItem
+DA:3,5
+DA:4,5
+DA:9,0
+end_of_record
+SF:src/util.js
+DA:1,0
+end_of_record
+`
+	report, err := Parse(strings.NewReader(lcov), "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if report.Format != FormatLCOV {
+		t.Errorf("Format = %q, want lcov", report.Format)
+	}
+
+	cart := report.Files["/home/runner/work/shop/src/cart.js"]
+	if cart == nil || !reflect.DeepEqual(cart.Covered(), []int{3, 4}) || !reflect.DeepEqual(cart.Uncovered(), []int{9}) {
+		t.Errorf("unexpected cart.js coverage: %+v", cart)
+	}
+	covered, total := report.Summary()
+	if covered != 2 || total != 4 {
+		t.Errorf("Summary() = %d/%d, want 2/4", covered, total)
+	}
+}
+
+func TestParseCobertura(t *testing.T) {
+	xml := `<?xml version="1.0" ?>
+<coverage line-rate="0.5">
+  <sources><source>/ci/app</source></sources>
+  <packages>
+    <package name="payments">
+      <classes>
+        <class name="gateway.py" filename="payments/gateway.py">
+          <lines>
+            <line number="1" hits="1"/>
+            <line number="2" hits="0"/>
+          </lines>
+        </class>
+      </classes>
+    </package>
+  </packages>
+</coverage>`
+	report, err := Parse(strings.NewReader(xml), "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if report.Format != FormatCobertura {
+		t.Errorf("Format = %q, want cobertura", report.Format)
+	}
+	fc := report.Files["payments/gateway.py"]
+	if fc == nil || fc.Ratio() != 0.5 {
+		t.Errorf("unexpected gateway.py coverage: %+v", fc)
+	}
+}
+
+func TestParseJaCoCo(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<report name="shop">
+  <package name="com/acme/shop">
+    <sourcefile name="Cart.java">
+      <line nr="5" mi="0" ci="3" mb="0" cb="0"/>
+      <line nr="6" mi="2" ci="0" mb="0" cb="0"/>
+      <line nr="7" mi="0" ci="0" mb="0" cb="0"/>
+    </sourcefile>
+  </package>
+</report>`
+	report, err := Parse(strings.NewReader(xml), "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if report.Format != FormatJaCoCo {
+		t.Errorf("Format = %q, want jacoco", report.Format)
+	}
+	fc := report.Files["com/acme/shop/Cart.java"]
+	if fc == nil {
+		t.Fatalf("missing file, got %v", report.Files)
+	}
+	// Line 7 has no instructions, so it is not executable
+	if !reflect.DeepEqual(fc.Covered(), []int{5}) || !reflect.DeepEqual(fc.Uncovered(), []int{6}) {
+		t.Errorf("unexpected Cart.java coverage: %+v", fc.Lines)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	if got := DetectFormat([]byte("no idea")); got != "" {
+		t.Errorf("DetectFormat(unknown) = %q, want empty", got)
+	}
+	if _, err := Parse(strings.NewReader("no idea"), ""); err == nil {
+		t.Error("expected error for unrecognized report")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	report := newReport(FormatGo)
+	report.file("github.com/acme/shop/billing/invoice.go").addHits(1, 1)
+	report.file("/ci/checkout/src/main/java/com/acme/Cart.java").addHits(1, 0)
+	report.file("util.go").addHits(1, 1) // Ambiguous: two util.go files
+	report.file("vendor/lib/other.go").addHits(1, 1)
+
+	repoFiles := []string{
+		"billing/invoice.go",
+		"src/main/java/com/acme/Cart.java",
+		"a/util.go",
+		"b/util.go",
+	}
+
+	resolved, unmatched := Resolve(report, repoFiles, "github.com/acme/shop")
+
+	var got []string
+	for p := range resolved.Files {
+		got = append(got, p)
+	}
+	if len(got) != 2 || resolved.Files["billing/invoice.go"] == nil || resolved.Files["src/main/java/com/acme/Cart.java"] == nil {
+		t.Errorf("resolved files = %v", got)
+	}
+	if want := []string{"util.go", "vendor/lib/other.go"}; !reflect.DeepEqual(unmatched, want) {
+		t.Errorf("unmatched = %v, want %v", unmatched, want)
+	}
+}
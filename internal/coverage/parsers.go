@@ -0,0 +1,171 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseGoProfile parses `go test -coverprofile` output
+// Each line is "file.go:startLine.startCol,endLine.endCol numStmts count"; every line of a
+// block gets the block's count, and a line shared by blocks keeps the highest.
+func parseGoProfile(data []byte) (*Report, error) {
+	report := newReport(FormatGo)
+	scanner := newLineScanner(data)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		colon := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[colon+1:])
+		if colon < 0 || len(fields) != 3 {
+			return nil, fmt.Errorf("invalid Go cover profile line %d: %q", lineNo, line)
+		}
+		start, end, ok := parseGoBlockRange(fields[0])
+		count, err := strconv.Atoi(fields[2])
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid Go cover profile line %d: %q", lineNo, line)
+		}
+
+		fc := report.file(line[:colon])
+		for l := start; l <= end; l++ {
+			fc.addHits(l, count)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Go cover profile: %w", err)
+	}
+	return report, nil
+}
+
+// parseGoBlockRange parses "startLine.startCol,endLine.endCol"
+func parseGoBlockRange(s string) (start, end int, ok bool) {
+	from, to, found := strings.Cut(s, ",")
+	if !found {
+		return 0, 0, false
+	}
+	startStr, _, _ := strings.Cut(from, ".")
+	endStr, _, _ := strings.Cut(to, ".")
+	start, err1 := strconv.Atoi(startStr)
+	end, err2 := strconv.Atoi(endStr)
+	if err1 != nil || err2 != nil || end < start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// parseLCOV parses LCOV tracefiles (SF:, DA:line,hits, end_of_record)
+func parseLCOV(data []byte) (*Report, error) {
+	report := newReport(FormatLCOV)
+	var current *FileCoverage
+
+	scanner := newLineScanner(data)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			current = report.file(strings.TrimPrefix(line, "SF:"))
+		case strings.HasPrefix(line, "DA:"):
+			if current == nil {
+				return nil, fmt.Errorf("LCOV line %d: DA record outside a source file", lineNo)
+			}
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("invalid LCOV line %d: %q", lineNo, line)
+			}
+			l, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid LCOV line %d: %q", lineNo, line)
+			}
+			current.addHits(l, hits)
+		case line == "end_of_record":
+			current = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read LCOV report: %w", err)
+	}
+	return report, nil
+}
+
+// coberturaReport is the subset of Cobertura XML used here
+type coberturaReport struct {
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int `xml:"number,attr"`
+				Hits   int `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// parseCobertura parses Cobertura XML (coverage.py, Istanbul, gcovr)
+// Filenames are relative to one of the <source> roots; Resolve matches them by suffix.
+func parseCobertura(data []byte) (*Report, error) {
+	var doc coberturaReport
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid Cobertura XML: %w", err)
+	}
+
+	report := newReport(FormatCobertura)
+	for _, pkg := range doc.Packages {
+		for _, class := range pkg.Classes {
+			fc := report.file(class.Filename)
+			for _, l := range class.Lines {
+				fc.addHits(l.Number, l.Hits)
+			}
+		}
+	}
+	return report, nil
+}
+
+// jacocoReport is the subset of JaCoCo XML used here
+type jacocoReport struct {
+	Packages []struct {
+		Name        string `xml:"name,attr"`
+		SourceFiles []struct {
+			Name  string `xml:"name,attr"`
+			Lines []struct {
+				Number              int `xml:"nr,attr"`
+				MissedInstructions  int `xml:"mi,attr"`
+				CoveredInstructions int `xml:"ci,attr"`
+			} `xml:"line"`
+		} `xml:"sourcefile"`
+	} `xml:"package"`
+}
+
+// parseJaCoCo parses JaCoCo XML; a line's hits are its covered instruction count
+// Paths are "package/SourceFile.java", resolved against the source root by suffix.
+func parseJaCoCo(data []byte) (*Report, error) {
+	var doc jacocoReport
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JaCoCo XML: %w", err)
+	}
+
+	report := newReport(FormatJaCoCo)
+	for _, pkg := range doc.Packages {
+		for _, source := range pkg.SourceFiles {
+			filePath := source.Name
+			if pkg.Name != "" {
+				filePath = pkg.Name + "/" + source.Name
+			}
+			fc := report.file(filePath)
+			for _, l := range source.Lines {
+				if l.MissedInstructions+l.CoveredInstructions > 0 {
+					fc.addHits(l.Number, l.CoveredInstructions)
+				}
+			}
+		}
+	}
+	return report, nil
+}
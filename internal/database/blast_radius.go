@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// BlastRadiusCache stores blast radius results per commit (implements blastradius.Cache)
+// Takes a plain *sql.DB so that both the lib/pq staging client and pgx pools can back it.
+type BlastRadiusCache struct {
+	db *sql.DB
+}
+
+// NewBlastRadiusCache creates a cache over an open database
+func NewBlastRadiusCache(db *sql.DB) *BlastRadiusCache {
+	return &BlastRadiusCache{db: db}
+}
+
+// Get returns a cached result; a missing table (migration 021 not run) is a miss
+func (c *BlastRadiusCache) Get(ctx context.Context, repoID int64, commitSHA, key string) ([]byte, bool, error) {
+	var result []byte
+	err := c.db.QueryRowContext(ctx, `
+		SELECT result FROM blast_radius_cache
+		WHERE repo_id = $1 AND commit_sha = $2 AND cache_key = $3
+	`, repoID, commitSHA, key).Scan(&result)
+
+	if errors.Is(err, sql.ErrNoRows) || undefinedTable(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read blast radius cache: %w", err)
+	}
+	return result, true, nil
+}
+
+// undefinedTable reports whether err is PostgreSQL's undefined_table (42P01), as raised
+// through either lib/pq or pgx
+func undefinedTable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "42P01"
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "42P01"
+}
+
+// Put stores a result, replacing an earlier one for the same key
+func (c *BlastRadiusCache) Put(ctx context.Context, repoID int64, commitSHA, key string, value []byte) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO blast_radius_cache (repo_id, commit_sha, cache_key, result, computed_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (repo_id, commit_sha, cache_key)
+		DO UPDATE SET result = EXCLUDED.result, computed_at = NOW()
+	`, repoID, commitSHA, key, string(value))
+	if err != nil {
+		return fmt.Errorf("failed to write blast radius cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// GetBlockChangeDates returns the author date of every commit that changed each block
+// Keyed by code_blocks.id; changes not attributed to a block are skipped.
+func GetBlockChangeDates(ctx context.Context, db *sqlx.DB, repoID int64) (map[int64][]time.Time, error) {
+	query := `
+		SELECT cbc.block_id, c.author_date
+		FROM code_block_changes cbc
+		JOIN github_commits c ON c.repo_id = cbc.repo_id AND c.sha = cbc.commit_sha
+		WHERE cbc.repo_id = $1
+			AND cbc.block_id IS NOT NULL
+			AND c.author_date IS NOT NULL
+		ORDER BY cbc.block_id, c.author_date
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block changes: %w", err)
+	}
+	defer rows.Close()
+
+	dates := make(map[int64][]time.Time)
+	for rows.Next() {
+		var blockID int64
+		var at time.Time
+		if err := rows.Scan(&blockID, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan block change: %w", err)
+		}
+		dates[blockID] = append(dates[blockID], at)
+	}
+	return dates, rows.Err()
+}
@@ -496,8 +496,58 @@ func (c *StagingClient) GetLatestCLQSScore(ctx context.Context, repoID int64) (*
 		LIMIT 1
 	`
 
+	score, err := scanCLQSScore(c.db.QueryRowContext(ctx, query, repoID))
+	if err == sql.ErrNoRows {
+		return nil, nil // No cached score
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest CLQS score: %w", err)
+	}
+
+	return score, nil
+}
+
+// GetCLQSScoreHistory retrieves up to limit stored CLQS scores for a repository, newest first (0 = all)
+func (c *StagingClient) GetCLQSScoreHistory(ctx context.Context, repoID int64, limit int) ([]CLQSScore, error) {
+	query := `
+		SELECT
+			repo_id, clqs, clqs_grade, clqs_rank, confidence_multiplier,
+			link_coverage, confidence_quality, evidence_diversity, temporal_precision, semantic_strength,
+			link_coverage_contribution, confidence_quality_contribution, evidence_diversity_contribution,
+			temporal_precision_contribution, semantic_strength_contribution,
+			total_closed_issues, eligible_issues, linked_issues, total_links, avg_confidence,
+			computed_at
+		FROM clqs_scores
+		WHERE repo_id = $1
+		ORDER BY computed_at DESC, id DESC
+		LIMIT NULLIF($2, 0)
+	`
+
+	rows, err := c.db.QueryContext(ctx, query, repoID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CLQS history: %w", err)
+	}
+	defer rows.Close()
+
+	var scores []CLQSScore
+	for rows.Next() {
+		score, err := scanCLQSScore(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan CLQS score: %w", err)
+		}
+		scores = append(scores, *score)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating CLQS history: %w", err)
+	}
+
+	return scores, nil
+}
+
+// scanCLQSScore scans a clqs_scores row selected in the column order used above
+func scanCLQSScore(row interface{ Scan(...any) error }) (*CLQSScore, error) {
 	var score CLQSScore
-	err := c.db.QueryRowContext(ctx, query, repoID).Scan(
+	err := row.Scan(
 		&score.RepoID, &score.CLQS, &score.Grade, &score.Rank, &score.ConfidenceMultiplier,
 		&score.LinkCoverage, &score.ConfidenceQuality, &score.EvidenceDiversity, &score.TemporalPrecision, &score.SemanticStrength,
 		&score.LinkCoverageContribution, &score.ConfidenceQualityContribution, &score.EvidenceDiversityContribution,
@@ -505,13 +555,9 @@ func (c *StagingClient) GetLatestCLQSScore(ctx context.Context, repoID int64) (*
 		&score.TotalClosedIssues, &score.EligibleIssues, &score.LinkedIssues, &score.TotalLinks, &score.AvgConfidence,
 		&score.ComputedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil // No cached score
-	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to query latest CLQS score: %w", err)
+		return nil, err
 	}
-
 	return &score, nil
 }
 
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CommitFileChange is one file entry from a staged commit's GitHub payload
+type CommitFileChange struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+}
+
+// CommitFiles is a commit with the files it changed
+type CommitFiles struct {
+	SHA         string
+	AuthorEmail string
+	AuthorDate  time.Time
+	Files       []CommitFileChange
+}
+
+// BugIntroductionPair is an SZZ result: a fix commit and a commit blamed for the bug it fixed
+type BugIntroductionPair struct {
+	FixCommitSHA         string
+	IntroducingCommitSHA string
+	Confidence           float64
+}
+
+// GetCommitFileHistory returns every staged commit with its changed files, oldest first
+func GetCommitFileHistory(ctx context.Context, db *sqlx.DB, repoID int64) ([]CommitFiles, error) {
+	query := `
+		SELECT sha, LOWER(COALESCE(author_email, '')), author_date,
+			COALESCE(raw_data->'files', '[]'::jsonb)
+		FROM github_commits
+		WHERE repo_id = $1
+			AND author_date IS NOT NULL
+		ORDER BY author_date ASC, sha
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commit history: %w", err)
+	}
+	defer rows.Close()
+
+	var commits []CommitFiles
+	for rows.Next() {
+		var c CommitFiles
+		var filesJSON []byte
+		if err := rows.Scan(&c.SHA, &c.AuthorEmail, &c.AuthorDate, &filesJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan commit: %w", err)
+		}
+		if err := json.Unmarshal(filesJSON, &c.Files); err != nil {
+			return nil, fmt.Errorf("failed to parse files of commit %s: %w", c.SHA, err)
+		}
+		commits = append(commits, c)
+	}
+	return commits, rows.Err()
+}
+
+// GetBugIntroductionPairs returns the SZZ results recorded by crisk-index-incident
+// Confidence is the highest across the files of each (fix, introducing) pair.
+func GetBugIntroductionPairs(ctx context.Context, db *sqlx.DB, repoID int64) ([]BugIntroductionPair, error) {
+	query := `
+		SELECT fix_commit_sha, introducing_commit_sha, MAX(confidence)
+		FROM bug_introducing_commits
+		WHERE repo_id = $1
+		GROUP BY fix_commit_sha, introducing_commit_sha
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bug-introducing commits: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []BugIntroductionPair
+	for rows.Next() {
+		var p BugIntroductionPair
+		if err := rows.Scan(&p.FixCommitSHA, &p.IntroducingCommitSHA, &p.Confidence); err != nil {
+			return nil, fmt.Errorf("failed to scan bug-introducing commit: %w", err)
+		}
+		pairs = append(pairs, p)
+	}
+	return pairs, rows.Err()
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/lib/pq"
+	"github.com/rohankatakam/coderisk/internal/coverage"
+)
+
+// StoreCoverageReport stores a resolved coverage report for a commit, replacing any earlier
+// import of the same format, and folds its lines onto code block ranges
+// Returns the report ID and the number of code blocks with executable lines.
+func (c *StagingClient) StoreCoverageReport(ctx context.Context, repoID int64, commitSHA, sourceFile string, report *coverage.Report) (int64, int, error) {
+	covered, total := report.Summary()
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var reportID int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO coverage_reports (repo_id, commit_sha, format, source_file, files, lines_covered, lines_total, imported_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, NOW())
+		ON CONFLICT (repo_id, commit_sha, format)
+		DO UPDATE SET
+			source_file = EXCLUDED.source_file,
+			files = EXCLUDED.files,
+			lines_covered = EXCLUDED.lines_covered,
+			lines_total = EXCLUDED.lines_total,
+			imported_at = NOW()
+		RETURNING id
+	`, repoID, commitSHA, string(report.Format), sourceFile, len(report.Files), covered, total).Scan(&reportID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to store coverage report: %w", err)
+	}
+
+	for _, table := range []string{"coverage_files", "code_block_coverage"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE report_id = $1`, reportID); err != nil {
+			return 0, 0, fmt.Errorf("failed to clear previous coverage: %w", err)
+		}
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO coverage_files (report_id, file_path, covered_lines, uncovered_lines)
+		VALUES ($1, $2, $3, $4)
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to prepare coverage insert: %w", err)
+	}
+	defer stmt.Close()
+
+	paths := make([]string, 0, len(report.Files))
+	for p := range report.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		fc := report.Files[p]
+		if _, err := stmt.ExecContext(ctx, reportID, p, pq.Array(fc.Covered()), pq.Array(fc.Uncovered())); err != nil {
+			return 0, 0, fmt.Errorf("failed to store coverage for %s: %w", p, err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO code_block_coverage (report_id, block_id, covered_lines, executable_lines)
+		SELECT cf.report_id, cb.id,
+			   (SELECT COUNT(*) FROM unnest(cf.covered_lines) l WHERE l BETWEEN cb.start_line AND cb.end_line),
+			   (SELECT COUNT(*) FROM unnest(cf.covered_lines || cf.uncovered_lines) l WHERE l BETWEEN cb.start_line AND cb.end_line)
+		FROM coverage_files cf
+		JOIN code_blocks cb ON cb.repo_id = $2 AND cb.canonical_file_path = cf.file_path
+		WHERE cf.report_id = $1
+		  AND cb.start_line IS NOT NULL AND cb.end_line IS NOT NULL
+		  AND EXISTS (
+			  SELECT 1 FROM unnest(cf.covered_lines || cf.uncovered_lines) l
+			  WHERE l BETWEEN cb.start_line AND cb.end_line
+		  )
+	`, reportID, repoID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to map coverage onto code blocks: %w", err)
+	}
+	blocks, _ := result.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit coverage report: %w", err)
+	}
+	return reportID, int(blocks), nil
+}
+
+// GetFileCoverage retrieves a file's line coverage at a commit, merged across report formats
+// Returns nil when no report was imported for the commit, or the file is not in any report;
+// callers fall back to the test ratio heuristic then.
+func (c *StagingClient) GetFileCoverage(ctx context.Context, repoID int64, commitSHA, filePath string) (*coverage.FileCoverage, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT cf.covered_lines, cf.uncovered_lines
+		FROM coverage_files cf
+		JOIN coverage_reports r ON r.id = cf.report_id
+		WHERE r.repo_id = $1 AND r.commit_sha = $2 AND cf.file_path = $3
+	`, repoID, commitSHA, filePath)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "42P01" { // undefined_table
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file coverage: %w", err)
+	}
+	defer rows.Close()
+
+	var fc *coverage.FileCoverage
+	for rows.Next() {
+		var covered, uncovered pq.Int64Array
+		if err := rows.Scan(&covered, &uncovered); err != nil {
+			return nil, fmt.Errorf("failed to scan file coverage: %w", err)
+		}
+		if fc == nil {
+			fc = &coverage.FileCoverage{Path: filePath, Lines: make(map[int]int)}
+		}
+		// Hit counts are not stored; a covered line in any report wins
+		for _, l := range uncovered {
+			if _, ok := fc.Lines[int(l)]; !ok {
+				fc.Lines[int(l)] = 0
+			}
+		}
+		for _, l := range covered {
+			fc.Lines[int(l)] = 1
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file coverage: %w", err)
+	}
+	return fc, nil
+}
+
+// GetBlockCoverage retrieves code block coverage for a file at a commit, ordered by start line
+func (c *StagingClient) GetBlockCoverage(ctx context.Context, repoID int64, commitSHA, filePath string) ([]coverage.BlockCoverage, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT cb.id, cb.block_name, cb.start_line, cb.end_line,
+			   MAX(bc.covered_lines), MAX(bc.executable_lines)
+		FROM code_block_coverage bc
+		JOIN coverage_reports r ON r.id = bc.report_id
+		JOIN code_blocks cb ON cb.id = bc.block_id
+		WHERE r.repo_id = $1 AND r.commit_sha = $2 AND cb.canonical_file_path = $3
+		GROUP BY cb.id, cb.block_name, cb.start_line, cb.end_line
+		ORDER BY cb.start_line
+	`, repoID, commitSHA, filePath)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "42P01" { // undefined_table
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block coverage: %w", err)
+	}
+	defer rows.Close()
+
+	var blocks []coverage.BlockCoverage
+	for rows.Next() {
+		var b coverage.BlockCoverage
+		if err := rows.Scan(&b.BlockID, &b.Name, &b.StartLine, &b.EndLine, &b.Covered, &b.Executable); err != nil {
+			return nil, fmt.Errorf("failed to scan block coverage: %w", err)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
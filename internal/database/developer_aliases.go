@@ -0,0 +1,381 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Developer alias statuses (developer_aliases.status)
+// Reference: migrations/017_developer_aliases.sql
+const (
+	AliasPending  = "pending"  // Suggested, awaiting review
+	AliasAccepted = "accepted" // Confirmed, not yet applied to staging
+	AliasRejected = "rejected" // Reviewed and declined
+	AliasApplied  = "applied"  // Commits rewritten to the canonical email
+)
+
+// DeveloperAlias maps an alias email to the developer's canonical email
+type DeveloperAlias struct {
+	ID             int64      `json:"id"`
+	AliasEmail     string     `json:"alias_email"`
+	CanonicalEmail string     `json:"canonical_email"`
+	Source         string     `json:"source"`
+	Confidence     float64    `json:"confidence"`
+	Reason         string     `json:"reason,omitempty"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AppliedAt      *time.Time `json:"applied_at,omitempty"`
+}
+
+// CommitAuthor is one author email as seen in staged commits
+type CommitAuthor struct {
+	Email   string // Lowercase email the commits were authored with
+	Name    string
+	Login   string // GitHub login from the commit payload, when linked
+	Commits int
+}
+
+// AliasApplyResult summarizes an ApplyDeveloperAliases run
+type AliasApplyResult struct {
+	Aliases  int
+	Commits  int64
+	BlockIDs []int64 // Blocks whose ownership referenced an applied alias
+}
+
+// GetCommitAuthors returns every author email in staged commits, using the email the
+// commit was originally authored with even if an alias has since been applied
+func GetCommitAuthors(ctx context.Context, db *sqlx.DB, repoID int64) ([]CommitAuthor, error) {
+	query := `
+		SELECT
+			LOWER(COALESCE(original_author_email, author_email)) AS email,
+			COALESCE(MAX(author_name), '') AS name,
+			COALESCE(MAX(raw_data->'author'->>'login'), '') AS login,
+			COUNT(*) AS commits
+		FROM github_commits
+		WHERE repo_id = $1
+			AND COALESCE(original_author_email, author_email) IS NOT NULL
+		GROUP BY LOWER(COALESCE(original_author_email, author_email))
+		ORDER BY commits DESC, email
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commit authors: %w", err)
+	}
+	defer rows.Close()
+
+	var authors []CommitAuthor
+	for rows.Next() {
+		var a CommitAuthor
+		if err := rows.Scan(&a.Email, &a.Name, &a.Login, &a.Commits); err != nil {
+			return nil, fmt.Errorf("failed to scan commit author: %w", err)
+		}
+		authors = append(authors, a)
+	}
+	return authors, rows.Err()
+}
+
+// SaveDeveloperAliases upserts aliases; reviewed (rejected or applied) rows are left alone
+func SaveDeveloperAliases(ctx context.Context, db *sqlx.DB, repoID int64, aliases []DeveloperAlias) (int, error) {
+	query := `
+		INSERT INTO developer_aliases (repo_id, alias_email, canonical_email, source, confidence, reason, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (repo_id, alias_email) DO UPDATE SET
+			canonical_email = EXCLUDED.canonical_email,
+			source = EXCLUDED.source,
+			confidence = EXCLUDED.confidence,
+			reason = EXCLUDED.reason,
+			status = CASE WHEN developer_aliases.status = 'accepted' THEN 'accepted' ELSE EXCLUDED.status END
+		WHERE developer_aliases.status IN ('pending', 'accepted')
+	`
+
+	saved := 0
+	for _, a := range aliases {
+		result, err := db.ExecContext(ctx, query, repoID,
+			strings.ToLower(a.AliasEmail), strings.ToLower(a.CanonicalEmail),
+			a.Source, a.Confidence, a.Reason, a.Status)
+		if err != nil {
+			return saved, fmt.Errorf("failed to save alias %s: %w", a.AliasEmail, err)
+		}
+		n, _ := result.RowsAffected()
+		saved += int(n)
+	}
+	return saved, nil
+}
+
+// ListDeveloperAliases returns aliases, optionally filtered by status
+func ListDeveloperAliases(ctx context.Context, db *sqlx.DB, repoID int64, statuses ...string) ([]DeveloperAlias, error) {
+	query := `
+		SELECT id, alias_email, canonical_email, source, confidence, COALESCE(reason, ''), status, created_at, applied_at
+		FROM developer_aliases
+		WHERE repo_id = $1 AND (cardinality($2::text[]) = 0 OR status = ANY($2::text[]))
+		ORDER BY canonical_email, alias_email
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID, pq.Array(statuses))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query developer aliases: %w", err)
+	}
+	defer rows.Close()
+
+	var aliases []DeveloperAlias
+	for rows.Next() {
+		var a DeveloperAlias
+		if err := rows.Scan(&a.ID, &a.AliasEmail, &a.CanonicalEmail, &a.Source, &a.Confidence,
+			&a.Reason, &a.Status, &a.CreatedAt, &a.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan developer alias: %w", err)
+		}
+		aliases = append(aliases, a)
+	}
+	return aliases, rows.Err()
+}
+
+// SetDeveloperAliasStatus records a review decision for an alias
+func SetDeveloperAliasStatus(ctx context.Context, db *sqlx.DB, repoID int64, aliasEmail, status string) error {
+	if status != AliasAccepted && status != AliasRejected {
+		return fmt.Errorf("invalid review status %q", status)
+	}
+
+	result, err := db.ExecContext(ctx, `
+		UPDATE developer_aliases
+		SET status = $3, reviewed_at = NOW()
+		WHERE repo_id = $1 AND alias_email = $2 AND status <> 'applied'
+	`, repoID, strings.ToLower(aliasEmail), status)
+	if err != nil {
+		return fmt.Errorf("failed to update alias %s: %w", aliasEmail, err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("no reviewable alias %s", aliasEmail)
+	}
+	return nil
+}
+
+// ApplyDeveloperAliases rewrites the author email of commits by accepted aliases to the
+// canonical email (keeping the original in original_author_email) and marks them applied.
+// The returned block IDs are the blocks whose ownership must be recalculated.
+func ApplyDeveloperAliases(ctx context.Context, db *sqlx.DB, repoID int64) (*AliasApplyResult, error) {
+	accepted, err := ListDeveloperAliases(ctx, db, repoID, AliasAccepted, AliasApplied)
+	if err != nil {
+		return nil, err
+	}
+
+	canonical := make(map[string]string, len(accepted))
+	var pending []string
+	for _, a := range accepted {
+		canonical[a.AliasEmail] = a.CanonicalEmail
+		if a.Status == AliasAccepted {
+			pending = append(pending, a.AliasEmail)
+		}
+	}
+	result := &AliasApplyResult{}
+	if len(pending) == 0 {
+		return result, nil
+	}
+	sort.Strings(pending)
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Collect affected blocks before the emails they reference are rewritten
+	blockRows, err := tx.QueryContext(ctx, `
+		SELECT id
+		FROM code_blocks cb
+		WHERE cb.repo_id = $1 AND (
+			LOWER(cb.original_author_email) = ANY($2::text[])
+			OR LOWER(cb.last_modifier_email) = ANY($2::text[])
+			OR (jsonb_typeof(cb.familiarity_map) = 'array' AND EXISTS (
+				SELECT 1 FROM jsonb_array_elements(cb.familiarity_map) entry
+				WHERE LOWER(entry->>'dev') = ANY($2::text[])
+			))
+		)
+		ORDER BY id
+	`, repoID, pq.Array(pending))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query affected blocks: %w", err)
+	}
+	for blockRows.Next() {
+		var id int64
+		if err := blockRows.Scan(&id); err != nil {
+			blockRows.Close()
+			return nil, fmt.Errorf("failed to scan affected block: %w", err)
+		}
+		result.BlockIDs = append(result.BlockIDs, id)
+	}
+	blockRows.Close()
+	if err := blockRows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, alias := range pending {
+		target, err := followAliases(canonical, alias)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := tx.ExecContext(ctx, `
+			UPDATE github_commits
+			SET original_author_email = COALESCE(original_author_email, author_email),
+				author_email = $3
+			WHERE repo_id = $1 AND LOWER(author_email) = $2
+		`, repoID, alias, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite commits of %s: %w", alias, err)
+		}
+		n, _ := updated.RowsAffected()
+		result.Commits += n
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE developer_aliases
+			SET status = 'applied', canonical_email = $3, applied_at = NOW()
+			WHERE repo_id = $1 AND alias_email = $2
+		`, repoID, alias, target); err != nil {
+			return nil, fmt.Errorf("failed to mark alias %s applied: %w", alias, err)
+		}
+		result.Aliases++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit alias application: %w", err)
+	}
+	aliasApplies.record(repoID)
+	return result, nil
+}
+
+// aliasApplies counts ApplyDeveloperAliases runs per repo in this process, so a
+// StagingClient drops the aliases it cached for a repo before the latest apply
+var aliasApplies = &applyCounter{runs: make(map[int64]int)}
+
+type applyCounter struct {
+	mu   sync.Mutex
+	runs map[int64]int
+}
+
+func (a *applyCounter) record(repoID int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.runs[repoID]++
+}
+
+func (a *applyCounter) count(repoID int64) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.runs[repoID]
+}
+
+// followAliases resolves alias chains (a -> b -> c) to the final canonical email
+func followAliases(canonical map[string]string, email string) (string, error) {
+	seen := map[string]bool{email: true}
+	for {
+		next, ok := canonical[email]
+		if !ok {
+			return email, nil
+		}
+		if seen[next] {
+			return "", fmt.Errorf("alias cycle involving %s", next)
+		}
+		seen[next] = true
+		email = next
+	}
+}
+
+// BlockOwnershipProps are the ownership columns of a block, for syncing to the graph
+type BlockOwnershipProps struct {
+	ID                  int64
+	OriginalAuthorEmail *string
+	LastModifierEmail   *string
+	LastModifiedDate    *time.Time
+	FamiliarityMapJSON  *string
+}
+
+// GetBlockOwnershipProps returns the ownership columns of the given blocks
+func GetBlockOwnershipProps(ctx context.Context, db *sqlx.DB, repoID int64, blockIDs []int64) ([]BlockOwnershipProps, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, original_author_email, last_modifier_email, last_modified_date, familiarity_map::text
+		FROM code_blocks
+		WHERE repo_id = $1 AND id = ANY($2::bigint[])
+		ORDER BY id
+	`, repoID, pq.Array(blockIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block ownership: %w", err)
+	}
+	defer rows.Close()
+
+	var blocks []BlockOwnershipProps
+	for rows.Next() {
+		var b BlockOwnershipProps
+		if err := rows.Scan(&b.ID, &b.OriginalAuthorEmail, &b.LastModifierEmail, &b.LastModifiedDate, &b.FamiliarityMapJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan block ownership: %w", err)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
+
+// canonicalAuthorEmail maps an author email through the repo's applied aliases
+// Aliases are loaded once per repo and reloaded after ApplyDeveloperAliases; a database
+// without migration 017 has none.
+func (c *StagingClient) canonicalAuthorEmail(ctx context.Context, repoID int64, email string) (string, error) {
+	c.aliasMu.Lock()
+	defer c.aliasMu.Unlock()
+
+	if c.aliases == nil {
+		c.aliases = make(map[int64]map[string]string)
+		c.aliasApplies = make(map[int64]int)
+	}
+	if applies := aliasApplies.count(repoID); c.aliasApplies[repoID] != applies {
+		delete(c.aliases, repoID)
+		c.aliasApplies[repoID] = applies
+	}
+	aliases, ok := c.aliases[repoID]
+	if !ok {
+		var err error
+		if aliases, err = c.loadAppliedAliases(ctx, repoID); err != nil {
+			return "", err
+		}
+		c.aliases[repoID] = aliases
+	}
+
+	if canonical, ok := aliases[strings.ToLower(email)]; ok {
+		return canonical, nil
+	}
+	return email, nil
+}
+
+// loadAppliedAliases returns the repo's applied aliases, alias email to canonical email
+func (c *StagingClient) loadAppliedAliases(ctx context.Context, repoID int64) (map[string]string, error) {
+	aliases := make(map[string]string)
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT alias_email, canonical_email
+		FROM developer_aliases
+		WHERE repo_id = $1 AND status = 'applied'
+	`, repoID)
+	if undefinedTable(err) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load developer aliases: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alias, canonical string
+		if err := rows.Scan(&alias, &canonical); err != nil {
+			return nil, fmt.Errorf("failed to scan developer alias: %w", err)
+		}
+		aliases[alias] = canonical
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read developer aliases: %w", err)
+	}
+	return aliases, nil
+}
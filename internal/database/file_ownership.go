@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// FileOwnership is the block ownership data of a file, rolled up per developer
+type FileOwnership struct {
+	FilePath    string
+	Edits       map[string]int       // Lowercase email -> edits summed over the file's blocks
+	LastTouched map[string]time.Time // Lowercase email -> most recent commit on the file's blocks
+}
+
+// GetFileOwnership rolls up the familiarity maps written by risk.OwnershipCalculator per file
+func GetFileOwnership(ctx context.Context, db *sqlx.DB, repoID int64) ([]FileOwnership, error) {
+	familiarityQuery := `
+		SELECT canonical_file_path, COALESCE(familiarity_map, '[]'::jsonb)::text
+		FROM code_blocks
+		WHERE repo_id = $1 AND canonical_file_path IS NOT NULL
+		ORDER BY canonical_file_path
+	`
+
+	rows, err := db.QueryContext(ctx, familiarityQuery, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file familiarity: %w", err)
+	}
+	defer rows.Close()
+
+	byPath := make(map[string]*FileOwnership)
+	var files []*FileOwnership
+	for rows.Next() {
+		var path, familiarityMapJSON string
+		if err := rows.Scan(&path, &familiarityMapJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan file familiarity: %w", err)
+		}
+
+		file, ok := byPath[path]
+		if !ok {
+			file = &FileOwnership{FilePath: path, Edits: make(map[string]int), LastTouched: make(map[string]time.Time)}
+			byPath[path] = file
+			files = append(files, file)
+		}
+		for dev, edits := range parseFamiliarityMap(familiarityMapJSON) {
+			file.Edits[strings.ToLower(dev)] += edits
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	lastTouchQuery := `
+		SELECT cb.canonical_file_path, LOWER(c.author_email), MAX(c.author_date)
+		FROM code_block_changes cbc
+		JOIN code_blocks cb ON cb.id = cbc.block_id
+		JOIN github_commits c ON c.repo_id = cbc.repo_id AND c.sha = cbc.commit_sha
+		WHERE cb.repo_id = $1
+			AND c.author_email IS NOT NULL
+			AND c.author_date IS NOT NULL
+		GROUP BY cb.canonical_file_path, LOWER(c.author_email)
+	`
+
+	touchRows, err := db.QueryContext(ctx, lastTouchQuery, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file changes: %w", err)
+	}
+	defer touchRows.Close()
+
+	for touchRows.Next() {
+		var path, email string
+		var at time.Time
+		if err := touchRows.Scan(&path, &email, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan file change: %w", err)
+		}
+		if file, ok := byPath[path]; ok {
+			file.LastTouched[email] = at
+		}
+	}
+	if err := touchRows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]FileOwnership, len(files))
+	for i, file := range files {
+		result[i] = *file
+	}
+	return result, nil
+}
+
+// GetDeveloperLastCommit returns each author's most recent commit date in the repository (lowercase email)
+func GetDeveloperLastCommit(ctx context.Context, db *sqlx.DB, repoID int64) (map[string]time.Time, error) {
+	query := `
+		SELECT LOWER(author_email), MAX(author_date)
+		FROM github_commits
+		WHERE repo_id = $1
+			AND author_email IS NOT NULL
+			AND author_date IS NOT NULL
+		GROUP BY LOWER(author_email)
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query developer activity: %w", err)
+	}
+	defer rows.Close()
+
+	lastCommit := make(map[string]time.Time)
+	for rows.Next() {
+		var email string
+		var at time.Time
+		if err := rows.Scan(&email, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan developer activity: %w", err)
+		}
+		lastCommit[email] = at
+	}
+	return lastCommit, rows.Err()
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rohankatakam/coderisk/internal/linking/types"
+)
+
+// Link label decisions (github_issue_pr_link_labels.decision)
+const (
+	LinkLabelAccepted = "accepted"
+	LinkLabelRejected = "rejected"
+)
+
+// LinkLabel is a human decision on an issue-PR link
+type LinkLabel struct {
+	IssueNumber        int       `json:"issue_number"`
+	PRNumber           int       `json:"pr_number"`
+	Decision           string    `json:"decision"`
+	Note               string    `json:"note,omitempty"`
+	Labeler            string    `json:"labeler,omitempty"`
+	OriginalConfidence float64   `json:"original_confidence"`
+	DetectionMethod    string    `json:"detection_method,omitempty"`
+	IssueTitle         string    `json:"issue_title,omitempty"`
+	LabeledAt          time.Time `json:"labeled_at"`
+}
+
+// GetLinksForReview retrieves unlabeled links below maxConfidence, least confident first
+func (c *StagingClient) GetLinksForReview(ctx context.Context, repoID int64, maxConfidence float64, limit int) ([]types.LinkOutput, error) {
+	query := `
+		SELECT l.issue_number, l.pr_number, l.detection_method, l.final_confidence,
+			   l.link_quality, l.confidence_breakdown, l.evidence_sources,
+			   l.comprehensive_rationale, l.semantic_analysis, l.temporal_analysis,
+			   l.flags, l.metadata
+		FROM github_issue_pr_links l
+		WHERE l.repo_id = $1
+		  AND l.final_confidence < $2
+		  AND NOT EXISTS (
+			  SELECT 1 FROM github_issue_pr_link_labels lb
+			  WHERE lb.repo_id = l.repo_id
+				AND lb.issue_number = l.issue_number
+				AND lb.pr_number = l.pr_number
+		  )
+		ORDER BY l.final_confidence ASC, l.issue_number, l.pr_number
+		LIMIT NULLIF($3, 0)
+	`
+
+	rows, err := c.db.QueryContext(ctx, query, repoID, maxConfidence, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query links for review: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIssuePRLinks(rows)
+}
+
+// SaveLinkLabel records a decision on a link and writes it through to github_issue_pr_links:
+// accepted links get confidence 1.0, rejected links are deleted
+func (c *StagingClient) SaveLinkLabel(ctx context.Context, repoID int64, link types.LinkOutput, decision, note, labeler string) error {
+	if decision != LinkLabelAccepted && decision != LinkLabelRejected {
+		return fmt.Errorf("invalid link label decision %q", decision)
+	}
+
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO github_issue_pr_link_labels (
+			repo_id, issue_number, pr_number, decision, note, labeler,
+			original_confidence, detection_method, labeled_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8, NOW())
+		ON CONFLICT (repo_id, issue_number, pr_number)
+		DO UPDATE SET
+			decision = EXCLUDED.decision,
+			note = EXCLUDED.note,
+			labeler = EXCLUDED.labeler,
+			labeled_at = NOW()
+	`, repoID, link.IssueNumber, link.PRNumber, decision, note, labeler,
+		link.FinalConfidence, string(link.DetectionMethod))
+	if err != nil {
+		return fmt.Errorf("failed to store link label: %w", err)
+	}
+
+	if decision == LinkLabelAccepted {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE github_issue_pr_links
+			SET final_confidence = 1.0, link_quality = 'high', updated_at = NOW()
+			WHERE repo_id = $1 AND issue_number = $2 AND pr_number = $3
+		`, repoID, link.IssueNumber, link.PRNumber)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM github_issue_pr_links
+			WHERE repo_id = $1 AND issue_number = $2 AND pr_number = $3
+		`, repoID, link.IssueNumber, link.PRNumber)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply link label: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// GetLinkLabels retrieves all link labels for a repository
+// Returns no labels when the labels table has not been migrated yet.
+func (c *StagingClient) GetLinkLabels(ctx context.Context, repoID int64) ([]LinkLabel, error) {
+	query := `
+		SELECT lb.issue_number, lb.pr_number, lb.decision, COALESCE(lb.note, ''), COALESCE(lb.labeler, ''),
+			   COALESCE(lb.original_confidence, 0), COALESCE(lb.detection_method, ''),
+			   COALESCE(i.title, ''), lb.labeled_at
+		FROM github_issue_pr_link_labels lb
+		LEFT JOIN github_issues i ON i.repo_id = lb.repo_id AND i.number = lb.issue_number
+		WHERE lb.repo_id = $1
+		ORDER BY lb.issue_number, lb.pr_number
+	`
+
+	rows, err := c.db.QueryContext(ctx, query, repoID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "42P01" { // undefined_table
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query link labels: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []LinkLabel
+	for rows.Next() {
+		var l LinkLabel
+		if err := rows.Scan(&l.IssueNumber, &l.PRNumber, &l.Decision, &l.Note, &l.Labeler,
+			&l.OriginalConfidence, &l.DetectionMethod, &l.IssueTitle, &l.LabeledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan link label: %w", err)
+		}
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
+
+// isLinkRejected reports whether a human rejected the link; false when the labels table is missing
+func (c *StagingClient) isLinkRejected(ctx context.Context, repoID int64, issueNumber, prNumber int) (bool, error) {
+	var rejected bool
+	err := c.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM github_issue_pr_link_labels
+			WHERE repo_id = $1 AND issue_number = $2 AND pr_number = $3 AND decision = 'rejected'
+		)
+	`, repoID, issueNumber, prNumber).Scan(&rejected)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "42P01" { // undefined_table
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check link label: %w", err)
+	}
+	return rejected, nil
+}
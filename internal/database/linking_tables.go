@@ -12,7 +12,16 @@ import (
 )
 
 // StoreLinkOutput stores a validated issue-PR link
+// Links rejected with `crisk links review` are not re-created.
 func (c *StagingClient) StoreLinkOutput(ctx context.Context, repoID int64, link types.LinkOutput) error {
+	rejected, err := c.isLinkRejected(ctx, repoID, link.IssueNumber, link.PRNumber)
+	if err != nil {
+		return err
+	}
+	if rejected {
+		return nil
+	}
+
 	query := `
 		INSERT INTO github_issue_pr_links (
 			repo_id, issue_number, pr_number, detection_method,
@@ -42,7 +51,7 @@ func (c *StagingClient) StoreLinkOutput(ctx context.Context, repoID int64, link
 	flags, _ := json.Marshal(link.Flags)
 	metadata, _ := json.Marshal(link.Metadata)
 
-	_, err := c.db.ExecContext(ctx, query,
+	_, err = c.db.ExecContext(ctx, query,
 		repoID,
 		link.IssueNumber,
 		link.PRNumber,
@@ -129,6 +138,11 @@ func (c *StagingClient) GetIssuePRLinks(ctx context.Context, repoID int64) ([]ty
 	}
 	defer rows.Close()
 
+	return scanIssuePRLinks(rows)
+}
+
+// scanIssuePRLinks scans link rows selected in the column order of GetIssuePRLinks
+func scanIssuePRLinks(rows *sql.Rows) ([]types.LinkOutput, error) {
 	var links []types.LinkOutput
 	for rows.Next() {
 		var link types.LinkOutput
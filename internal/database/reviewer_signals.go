@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// BlockFamiliarity is a code block's location and per-developer edit counts
+type BlockFamiliarity struct {
+	ID                int64
+	BlockName         string
+	CanonicalFilePath string
+	StartLine         int
+	EndLine           int
+	FamiliarityMap    map[string]int
+}
+
+// BlockCoupling is a co-change edge between two blocks
+type BlockCoupling struct {
+	BlockA       int64
+	BlockB       int64
+	CouplingRate float64
+}
+
+// GetBlockFamiliarityForFiles returns the blocks in the given files with their familiarity maps
+func GetBlockFamiliarityForFiles(ctx context.Context, db *sqlx.DB, repoID int64, filePaths []string) ([]BlockFamiliarity, error) {
+	query := `
+		SELECT id, block_name, canonical_file_path,
+			COALESCE(start_line, 0), COALESCE(end_line, 0),
+			COALESCE(familiarity_map, '[]'::jsonb)::text
+		FROM code_blocks
+		WHERE repo_id = $1 AND canonical_file_path = ANY($2::text[])
+		ORDER BY canonical_file_path, start_line
+	`
+	return queryBlockFamiliarity(ctx, db, query, repoID, pq.Array(filePaths))
+}
+
+// GetBlockFamiliarityByIDs returns the familiarity maps for the given blocks
+func GetBlockFamiliarityByIDs(ctx context.Context, db *sqlx.DB, repoID int64, blockIDs []int64) ([]BlockFamiliarity, error) {
+	query := `
+		SELECT id, block_name, canonical_file_path,
+			COALESCE(start_line, 0), COALESCE(end_line, 0),
+			COALESCE(familiarity_map, '[]'::jsonb)::text
+		FROM code_blocks
+		WHERE repo_id = $1 AND id = ANY($2::bigint[])
+	`
+	return queryBlockFamiliarity(ctx, db, query, repoID, pq.Array(blockIDs))
+}
+
+func queryBlockFamiliarity(ctx context.Context, db *sqlx.DB, query string, args ...interface{}) ([]BlockFamiliarity, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block familiarity: %w", err)
+	}
+	defer rows.Close()
+
+	var blocks []BlockFamiliarity
+	for rows.Next() {
+		var block BlockFamiliarity
+		var familiarityMapJSON string
+		if err := rows.Scan(&block.ID, &block.BlockName, &block.CanonicalFilePath,
+			&block.StartLine, &block.EndLine, &familiarityMapJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan block familiarity: %w", err)
+		}
+		block.FamiliarityMap = parseFamiliarityMap(familiarityMapJSON)
+		blocks = append(blocks, block)
+	}
+	return blocks, rows.Err()
+}
+
+// GetCoupledBlocks returns co-change edges touching any of the given blocks
+func GetCoupledBlocks(ctx context.Context, db *sqlx.DB, repoID int64, blockIDs []int64, minRate float64) ([]BlockCoupling, error) {
+	query := `
+		SELECT block_a_id, block_b_id, coupling_rate
+		FROM code_block_coupling
+		WHERE repo_id = $1
+			AND (block_a_id = ANY($2::bigint[]) OR block_b_id = ANY($2::bigint[]))
+			AND coupling_rate >= $3
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID, pq.Array(blockIDs), minRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block coupling: %w", err)
+	}
+	defer rows.Close()
+
+	var edges []BlockCoupling
+	for rows.Next() {
+		var edge BlockCoupling
+		if err := rows.Scan(&edge.BlockA, &edge.BlockB, &edge.CouplingRate); err != nil {
+			return nil, fmt.Errorf("failed to scan block coupling: %w", err)
+		}
+		edges = append(edges, edge)
+	}
+	return edges, rows.Err()
+}
+
+// GetLastBlockChangeByAuthor returns each author's most recent commit touching the blocks
+func GetLastBlockChangeByAuthor(ctx context.Context, db *sqlx.DB, repoID int64, blockIDs []int64) (map[string]time.Time, error) {
+	query := `
+		SELECT LOWER(c.author_email), MAX(c.author_date)
+		FROM code_block_changes cbc
+		JOIN github_commits c ON c.repo_id = cbc.repo_id AND c.sha = cbc.commit_sha
+		WHERE cbc.repo_id = $1
+			AND cbc.block_id = ANY($2::bigint[])
+			AND c.author_email IS NOT NULL
+			AND c.author_date IS NOT NULL
+		GROUP BY LOWER(c.author_email)
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID, pq.Array(blockIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query block changes: %w", err)
+	}
+	defer rows.Close()
+
+	last := make(map[string]time.Time)
+	for rows.Next() {
+		var email string
+		var at time.Time
+		if err := rows.Scan(&email, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan block change: %w", err)
+		}
+		last[email] = at
+	}
+	return last, rows.Err()
+}
+
+// GetIncidentFixesByAuthor counts, per author, the incidents whose fix commit touched the blocks
+func GetIncidentFixesByAuthor(ctx context.Context, db *sqlx.DB, repoID int64, blockIDs []int64) (map[string]int, error) {
+	query := `
+		SELECT LOWER(c.author_email), COUNT(DISTINCT cbi.issue_id)
+		FROM code_block_incidents cbi
+		JOIN github_commits c ON c.repo_id = cbi.repo_id AND c.sha = cbi.commit_sha
+		WHERE cbi.repo_id = $1
+			AND cbi.block_id = ANY($2::bigint[])
+			AND c.author_email IS NOT NULL
+		GROUP BY LOWER(c.author_email)
+	`
+	return queryCountsByKey(ctx, db, "incident fixes", query, repoID, pq.Array(blockIDs))
+}
+
+// GetRecentReviewRequests counts review requests per GitHub login on PRs opened since
+// the given time (requested_reviewers as staged from the pulls API)
+func GetRecentReviewRequests(ctx context.Context, db *sqlx.DB, repoID int64, since time.Time) (map[string]int, error) {
+	query := `
+		SELECT LOWER(reviewer->>'login'), COUNT(*)
+		FROM github_pull_requests pr,
+			jsonb_array_elements(COALESCE(pr.raw_data->'requested_reviewers', '[]'::jsonb)) AS reviewer
+		WHERE pr.repo_id = $1
+			AND pr.created_at >= $2
+			AND reviewer->>'login' IS NOT NULL
+		GROUP BY LOWER(reviewer->>'login')
+	`
+	return queryCountsByKey(ctx, db, "review requests", query, repoID, since)
+}
+
+// GetLoginsByEmail maps commit author emails to GitHub logins (from staged commit payloads)
+func GetLoginsByEmail(ctx context.Context, db *sqlx.DB, repoID int64) (map[string]string, error) {
+	query := `
+		SELECT DISTINCT LOWER(author_email), raw_data->'author'->>'login'
+		FROM github_commits
+		WHERE repo_id = $1
+			AND author_email IS NOT NULL
+			AND raw_data->'author'->>'login' IS NOT NULL
+	`
+
+	rows, err := db.QueryContext(ctx, query, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commit logins: %w", err)
+	}
+	defer rows.Close()
+
+	logins := make(map[string]string)
+	for rows.Next() {
+		var email, login string
+		if err := rows.Scan(&email, &login); err != nil {
+			return nil, fmt.Errorf("failed to scan commit login: %w", err)
+		}
+		logins[email] = login
+	}
+	return logins, rows.Err()
+}
+
+func queryCountsByKey(ctx context.Context, db *sqlx.DB, what, query string, args ...interface{}) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", what, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var key string
+		var n int
+		if err := rows.Scan(&key, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", what, err)
+		}
+		counts[strings.TrimSpace(key)] = n
+	}
+	return counts, rows.Err()
+}
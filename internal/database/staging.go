@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/lib/pq"
@@ -16,6 +17,10 @@ import (
 // Reference: dev_docs/03-implementation/integration_guides/layers_2_3_github_fetching.md
 type StagingClient struct {
 	db *sql.DB
+
+	aliasMu      sync.Mutex
+	aliases      map[int64]map[string]string // repo_id -> applied alias email -> canonical email
+	aliasApplies map[int64]int               // repo_id -> alias applies seen when its aliases were loaded
 }
 
 // NewStagingClient creates a PostgreSQL client for GitHub staging tables
@@ -92,16 +97,22 @@ func (c *StagingClient) GetDataCounts(ctx context.Context, repoID int64) (*DataC
 
 // StoreRepository stores repository metadata with raw JSON and absolute path
 func (c *StagingClient) StoreRepository(ctx context.Context, githubID int64, owner, name, fullName, absolutePath string, rawData json.RawMessage) (int64, error) {
+	// A re-fetch updates the row only when it comes from the same provider; a same-named
+	// repository from another provider is refused rather than merged into it
 	query := `
 		INSERT INTO github_repositories (github_id, owner, name, full_name, absolute_path, raw_data, fetched_at)
 		VALUES ($1, $2, $3, $4, $5, $6, NOW())
 		ON CONFLICT (full_name)
 		DO UPDATE SET raw_data = EXCLUDED.raw_data, absolute_path = EXCLUDED.absolute_path, fetched_at = NOW(), updated_at = NOW()
+		WHERE ` + repoProviderSQL("github_repositories") + ` = ` + repoProviderSQL("EXCLUDED") + `
 		RETURNING id
 	`
 
 	var repoID int64
 	err := c.db.QueryRowContext(ctx, query, githubID, owner, name, fullName, absolutePath, rawData).Scan(&repoID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to store repository: %s is already staged from another provider", fullName)
+	}
 	if err != nil {
 		return 0, fmt.Errorf("failed to store repository: %w", err)
 	}
@@ -109,6 +120,24 @@ func (c *StagingClient) StoreRepository(ctx context.Context, githubID int64, own
 	return repoID, nil
 }
 
+// repoProviderSQL is the hosting provider of a github_repositories row: the string raw_data
+// "source" the GitLab and git-only fetchers set, otherwise github (GitHub's payload has no
+// such string; forks carry their upstream repository object under "source")
+func repoProviderSQL(table string) string {
+	return fmt.Sprintf(`(CASE jsonb_typeof(%[1]s.raw_data->'source') WHEN 'string' THEN %[1]s.raw_data->>'source' ELSE 'github' END)`, table)
+}
+
+// GetRepositoryProvider returns the hosting provider a repository was staged from
+// ("github", "gitlab" or "git")
+func (c *StagingClient) GetRepositoryProvider(ctx context.Context, repoID int64) (string, error) {
+	var provider string
+	query := "SELECT " + repoProviderSQL("github_repositories") + " FROM github_repositories WHERE id = $1"
+	if err := c.db.QueryRowContext(ctx, query, repoID).Scan(&provider); err != nil {
+		return "", fmt.Errorf("failed to get provider of repository %d: %w", repoID, err)
+	}
+	return provider, nil
+}
+
 // GetRepositoryID returns the internal ID for a repository
 func (c *StagingClient) GetRepositoryID(ctx context.Context, fullName string) (int64, error) {
 	var repoID int64
@@ -125,7 +154,14 @@ func (c *StagingClient) GetRepositoryID(ctx context.Context, fullName string) (i
 // ===================================
 
 // StoreCommit stores commit data with raw JSON
+// Author emails with an applied developer alias are stored under the canonical email.
 func (c *StagingClient) StoreCommit(ctx context.Context, repoID int64, sha string, authorName, authorEmail string, authorDate time.Time, message string, additions, deletions, totalChanges, filesChanged int, rawData json.RawMessage) error {
+	originalEmail := authorEmail
+	authorEmail, err := c.canonicalAuthorEmail(ctx, repoID, authorEmail)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO github_commits (
 			repo_id, sha, author_name, author_email, author_date,
@@ -137,7 +173,7 @@ func (c *StagingClient) StoreCommit(ctx context.Context, repoID int64, sha strin
 		DO UPDATE SET raw_data = EXCLUDED.raw_data, fetched_at = NOW()
 	`
 
-	_, err := c.db.ExecContext(ctx, query,
+	_, err = c.db.ExecContext(ctx, query,
 		repoID, sha, authorName, authorEmail, authorDate,
 		message, additions, deletions, totalChanges, filesChanged,
 		rawData,
@@ -147,6 +183,15 @@ func (c *StagingClient) StoreCommit(ctx context.Context, repoID int64, sha strin
 		return fmt.Errorf("failed to store commit %s: %w", sha, err)
 	}
 
+	if authorEmail != originalEmail {
+		if _, err := c.db.ExecContext(ctx, `
+			UPDATE github_commits SET original_author_email = $3
+			WHERE repo_id = $1 AND sha = $2 AND original_author_email IS NULL
+		`, repoID, sha, originalEmail); err != nil {
+			return fmt.Errorf("failed to record original author of %s: %w", sha, err)
+		}
+	}
+
 	return nil
 }
 
@@ -1163,3 +1208,105 @@ func (c *StagingClient) GetIssuesWithoutComments(ctx context.Context, repoID int
 
 	return issues, rows.Err()
 }
+
+// GetIssueIDsByNumber maps issue numbers to internal IDs for a repository
+// Used by fetchers that attach timeline events and comments after issues are staged
+func (c *StagingClient) GetIssueIDsByNumber(ctx context.Context, repoID int64) (map[int]int64, error) {
+	rows, err := c.db.QueryContext(ctx, `SELECT id, number FROM github_issues WHERE repo_id = $1`, repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue IDs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[int]int64)
+	for rows.Next() {
+		var id int64
+		var number int
+		if err := rows.Scan(&id, &number); err != nil {
+			return nil, fmt.Errorf("failed to scan issue ID: %w", err)
+		}
+		ids[number] = id
+	}
+
+	return ids, rows.Err()
+}
+
+// ===================================
+// Fetch Cursor Operations
+// ===================================
+
+// FetchCursor is the incremental fetch position for one GitHub resource
+// Reference: migrations/016_github_fetch_cursors.sql
+type FetchCursor struct {
+	RepoID        int64
+	Resource      string     // "commits", "issues", "pulls"
+	ETag          string     // ETag of the first page from the last request
+	HighWaterMark *time.Time // Newest commit date / updated_at staged so far
+	LastFetchedAt time.Time
+}
+
+// GetFetchCursor returns the stored cursor for a resource, or nil if none exists
+func (c *StagingClient) GetFetchCursor(ctx context.Context, repoID int64, resource string) (*FetchCursor, error) {
+	query := `
+		SELECT COALESCE(etag, ''), high_water_mark, last_fetched_at
+		FROM github_fetch_cursors
+		WHERE repo_id = $1 AND resource = $2
+	`
+
+	cursor := &FetchCursor{RepoID: repoID, Resource: resource}
+	var mark sql.NullTime
+	err := c.db.QueryRowContext(ctx, query, repoID, resource).Scan(&cursor.ETag, &mark, &cursor.LastFetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fetch cursor for %s: %w", resource, err)
+	}
+	if mark.Valid {
+		cursor.HighWaterMark = &mark.Time
+	}
+
+	return cursor, nil
+}
+
+// SaveFetchCursor upserts the cursor for a resource
+func (c *StagingClient) SaveFetchCursor(ctx context.Context, cursor FetchCursor) error {
+	query := `
+		INSERT INTO github_fetch_cursors (repo_id, resource, etag, high_water_mark, last_fetched_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NOW())
+		ON CONFLICT (repo_id, resource)
+		DO UPDATE SET etag = EXCLUDED.etag, high_water_mark = EXCLUDED.high_water_mark, last_fetched_at = NOW()
+	`
+
+	_, err := c.db.ExecContext(ctx, query, cursor.RepoID, cursor.Resource, cursor.ETag, cursor.HighWaterMark)
+	if err != nil {
+		return fmt.Errorf("failed to save fetch cursor for %s: %w", cursor.Resource, err)
+	}
+	return nil
+}
+
+// GetStagedHighWaterMark returns the newest timestamp already staged for a resource
+// Seeds a missing cursor after a full fetch so the first incremental run does not re-pull
+func (c *StagingClient) GetStagedHighWaterMark(ctx context.Context, repoID int64, resource string) (*time.Time, error) {
+	var query string
+	switch resource {
+	case "commits":
+		query = `SELECT MAX((raw_data->'commit'->'committer'->>'date')::timestamptz) FROM github_commits WHERE repo_id = $1`
+	case "issues":
+		query = `SELECT MAX((raw_data->>'updated_at')::timestamptz) FROM github_issues WHERE repo_id = $1`
+	case "pulls":
+		query = `SELECT MAX((raw_data->>'updated_at')::timestamptz) FROM github_pull_requests WHERE repo_id = $1`
+	default:
+		return nil, fmt.Errorf("unknown fetch resource: %s", resource)
+	}
+
+	var mark sql.NullTime
+	if err := c.db.QueryRowContext(ctx, query, repoID).Scan(&mark); err != nil {
+		return nil, fmt.Errorf("failed to get staged high-water mark for %s: %w", resource, err)
+	}
+	if !mark.Valid {
+		return nil, nil
+	}
+	t := mark.Time.UTC()
+	return &t, nil
+}
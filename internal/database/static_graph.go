@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// StaticGraphState records the commit the static IMPORTS and CALLS edges were derived from
+type StaticGraphState struct {
+	RepoID        int64
+	CommitSHA     string
+	FilesAnalyzed int
+	ImportEdges   int
+	CallEdges     int
+	AnalyzedAt    time.Time
+}
+
+// GetStaticGraphState returns the last static analysis of a repository
+// Returns nil if the repository has not been analyzed or migration 020 has not run.
+func (c *StagingClient) GetStaticGraphState(ctx context.Context, repoID int64) (*StaticGraphState, error) {
+	state := &StaticGraphState{RepoID: repoID}
+	err := c.db.QueryRowContext(ctx, `
+		SELECT commit_sha, files_analyzed, import_edges, call_edges, analyzed_at
+		FROM static_graph_state
+		WHERE repo_id = $1
+	`, repoID).Scan(&state.CommitSHA, &state.FilesAnalyzed, &state.ImportEdges, &state.CallEdges, &state.AnalyzedAt)
+
+	var pqErr *pq.Error
+	if errors.Is(err, sql.ErrNoRows) || (errors.As(err, &pqErr) && pqErr.Code == "42P01") { // undefined_table
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query static graph state: %w", err)
+	}
+	return state, nil
+}
+
+// SaveStaticGraphState records a completed static analysis, replacing the previous one
+func (c *StagingClient) SaveStaticGraphState(ctx context.Context, state *StaticGraphState) error {
+	_, err := c.db.ExecContext(ctx, `
+		INSERT INTO static_graph_state (repo_id, commit_sha, files_analyzed, import_edges, call_edges, analyzed_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (repo_id)
+		DO UPDATE SET
+			commit_sha = EXCLUDED.commit_sha,
+			files_analyzed = EXCLUDED.files_analyzed,
+			import_edges = EXCLUDED.import_edges,
+			call_edges = EXCLUDED.call_edges,
+			analyzed_at = NOW()
+	`, state.RepoID, state.CommitSHA, state.FilesAnalyzed, state.ImportEdges, state.CallEdges)
+	if err != nil {
+		return fmt.Errorf("failed to save static graph state: %w", err)
+	}
+	return nil
+}
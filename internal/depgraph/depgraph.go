@@ -0,0 +1,251 @@
+// Package depgraph derives static dependencies from source code: file-level imports and
+// function-level calls for Go, Python, and JavaScript/TypeScript. Imports and calls are
+// reported between files of the repository; third-party imports are listed separately so
+// dependency changes can be traced to the files using them. The standard library is dropped.
+package depgraph
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/ingestion"
+)
+
+// Import is a file-level dependency: From uses declarations of To
+type Import struct {
+	From string // Importing file, relative to the repository root
+	To   string // Imported file, relative to the repository root
+}
+
+// ExternalImport is a file's import of a third-party package or module
+// Python standard library modules cannot be told apart from third-party ones and are included.
+type ExternalImport struct {
+	File     string // Importing file, relative to the repository root
+	Language string // go, python, javascript
+	Spec     string // Import path, dotted module or bare specifier as written
+}
+
+// Call is a block-level dependency between two functions or methods
+// Blocks are named by their short name, as atomized code blocks are.
+type Call struct {
+	FromFile  string
+	FromBlock string
+	ToFile    string
+	ToBlock   string
+}
+
+// Result is the static dependency graph of a repository
+type Result struct {
+	Files    []string // Analyzed source files
+	Imports  []Import
+	Calls    []Call
+	External []ExternalImport
+}
+
+// Analyze parses every supported source file under repoPath
+// Test files are skipped: tests depending on a file do not make it riskier to change.
+// Files that fail to parse are skipped as well, so one syntax error does not hide the rest.
+func Analyze(repoPath string) (*Result, error) {
+	paths, err := ingestion.WalkSourceFiles(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", repoPath, err)
+	}
+
+	a := newAnalyzer(repoPath)
+	var goFiles, pyFiles, jsFiles []string
+	for abs := range paths {
+		rel, err := filepath.Rel(repoPath, abs)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if isTestFile(rel) {
+			continue
+		}
+
+		switch languageOf(rel) {
+		case "go":
+			goFiles = append(goFiles, rel)
+		case "python":
+			pyFiles = append(pyFiles, rel)
+		case "javascript":
+			jsFiles = append(jsFiles, rel)
+		default:
+			continue
+		}
+		a.files[rel] = true
+	}
+
+	a.analyzeGo(goFiles)
+	a.analyzePython(pyFiles)
+	a.analyzeJS(jsFiles)
+
+	return a.result(), nil
+}
+
+// Dependents returns the files with an import or call into any of the given files, sorted
+func (r *Result) Dependents(files []string) []string {
+	targets := make(map[string]bool, len(files))
+	for _, f := range files {
+		targets[f] = true
+	}
+
+	found := make(map[string]bool)
+	for _, imp := range r.Imports {
+		if targets[imp.To] && !targets[imp.From] {
+			found[imp.From] = true
+		}
+	}
+	for _, c := range r.Calls {
+		if targets[c.ToFile] && !targets[c.FromFile] {
+			found[c.FromFile] = true
+		}
+	}
+	return sortedKeys(found)
+}
+
+// HeadCommit returns the commit checked out in repoPath
+func HeadCommit(repoPath string) (string, error) {
+	out, err := exec.Command("git", "-C", repoPath, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve HEAD in %s: %w", repoPath, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ChangedFiles returns the files added, modified, deleted or renamed between two commits
+// Both sides of a rename are returned, since edges from and to the old path go stale.
+func ChangedFiles(repoPath, fromSHA, toSHA string) ([]string, error) {
+	out, err := exec.Command("git", "-C", repoPath, "diff", "--name-status", "-M", fromSHA, toSHA).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s..%s: %w", fromSHA, toSHA, err)
+	}
+
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Split(line, "\t")
+		for _, f := range fields[1:] {
+			changed[f] = true
+		}
+	}
+	return sortedKeys(changed), nil
+}
+
+// analyzer accumulates edges across languages
+type analyzer struct {
+	root     string
+	files    map[string]bool // Analyzed files (relative paths)
+	imports  map[Import]bool
+	calls    map[Call]bool
+	external map[ExternalImport]bool
+}
+
+func newAnalyzer(root string) *analyzer {
+	return &analyzer{
+		root:     root,
+		files:    make(map[string]bool),
+		imports:  make(map[Import]bool),
+		calls:    make(map[Call]bool),
+		external: make(map[ExternalImport]bool),
+	}
+}
+
+func (a *analyzer) addImport(from, to string) {
+	if from != to {
+		a.imports[Import{From: from, To: to}] = true
+	}
+}
+
+func (a *analyzer) addExternal(file, language, spec string) {
+	a.external[ExternalImport{File: file, Language: language, Spec: spec}] = true
+}
+
+func (a *analyzer) addCall(fromFile, fromBlock, toFile, toBlock string) {
+	if fromFile == toFile && fromBlock == toBlock {
+		return // Recursion
+	}
+	a.calls[Call{FromFile: fromFile, FromBlock: fromBlock, ToFile: toFile, ToBlock: toBlock}] = true
+}
+
+func (a *analyzer) result() *Result {
+	r := &Result{Files: sortedKeys(a.files)}
+	for imp := range a.imports {
+		r.Imports = append(r.Imports, imp)
+	}
+	for c := range a.calls {
+		r.Calls = append(r.Calls, c)
+	}
+	for e := range a.external {
+		r.External = append(r.External, e)
+	}
+
+	sort.Slice(r.Imports, func(i, j int) bool {
+		if r.Imports[i].From != r.Imports[j].From {
+			return r.Imports[i].From < r.Imports[j].From
+		}
+		return r.Imports[i].To < r.Imports[j].To
+	})
+	sort.Slice(r.Calls, func(i, j int) bool {
+		ci, cj := r.Calls[i], r.Calls[j]
+		if ci.FromFile != cj.FromFile {
+			return ci.FromFile < cj.FromFile
+		}
+		if ci.FromBlock != cj.FromBlock {
+			return ci.FromBlock < cj.FromBlock
+		}
+		if ci.ToFile != cj.ToFile {
+			return ci.ToFile < cj.ToFile
+		}
+		return ci.ToBlock < cj.ToBlock
+	})
+	sort.Slice(r.External, func(i, j int) bool {
+		if r.External[i].File != r.External[j].File {
+			return r.External[i].File < r.External[j].File
+		}
+		return r.External[i].Spec < r.External[j].Spec
+	})
+	return r
+}
+
+// languageOf maps a file extension to the analyzer that handles it
+func languageOf(file string) string {
+	switch path.Ext(file) {
+	case ".go":
+		return "go"
+	case ".py", ".pyi", ".pyw":
+		return "python"
+	case ".js", ".jsx", ".ts", ".tsx", ".mjs", ".cjs", ".mts", ".cts":
+		return "javascript"
+	}
+	return ""
+}
+
+// isTestFile reports test files by Go, pytest and Jest conventions
+func isTestFile(file string) bool {
+	base := path.Base(file)
+	name := strings.TrimSuffix(base, path.Ext(base))
+
+	switch languageOf(file) {
+	case "go":
+		return strings.HasSuffix(name, "_test")
+	case "python":
+		return strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test") || name == "conftest"
+	case "javascript":
+		return strings.HasSuffix(name, ".test") || strings.HasSuffix(name, ".spec") ||
+			strings.Contains(file, "__tests__/")
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
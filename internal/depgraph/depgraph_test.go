@@ -0,0 +1,257 @@
+package depgraph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFixture(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func hasImport(r *Result, from, to string) bool {
+	for _, imp := range r.Imports {
+		if imp.From == from && imp.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCall(r *Result, fromFile, fromBlock, toFile, toBlock string) bool {
+	for _, c := range r.Calls {
+		if c == (Call{FromFile: fromFile, FromBlock: fromBlock, ToFile: toFile, ToBlock: toBlock}) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAnalyzeGo(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"cmd/server/main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/shop/billing"
+)
+
+func main() {
+	inv := billing.NewInvoice(3)
+	fmt.Println(inv.Total())
+}
+`,
+		"billing/invoice.go": `package billing
+
+type Invoice struct{ items int }
+
+func NewInvoice(items int) *Invoice { return &Invoice{items: items} }
+
+func (i *Invoice) Total() int { return i.subtotal() + tax(i.items) }
+
+func (i *Invoice) subtotal() int { return i.items * 10 }
+`,
+		"billing/tax.go": `package billing
+
+func tax(n int) int { return n }
+`,
+		"billing/invoice_test.go": `package billing
+
+import "testing"
+
+func TestTotal(t *testing.T) { NewInvoice(1).Total() }
+`,
+	})
+
+	r, err := Analyze(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFiles := []string{"billing/invoice.go", "billing/tax.go", "cmd/server/main.go"}
+	if !reflect.DeepEqual(r.Files, wantFiles) {
+		t.Errorf("Files = %v, want %v", r.Files, wantFiles)
+	}
+	if !hasImport(r, "cmd/server/main.go", "billing/invoice.go") {
+		t.Errorf("missing import main.go -> invoice.go in %v", r.Imports)
+	}
+	if hasImport(r, "cmd/server/main.go", "billing/tax.go") {
+		t.Error("main.go should not depend on tax.go, which it does not use")
+	}
+
+	for _, c := range []Call{
+		{"cmd/server/main.go", "main", "billing/invoice.go", "NewInvoice"},
+		{"billing/invoice.go", "Total", "billing/invoice.go", "subtotal"},
+		{"billing/invoice.go", "Total", "billing/tax.go", "tax"},
+	} {
+		if !hasCall(r, c.FromFile, c.FromBlock, c.ToFile, c.ToBlock) {
+			t.Errorf("missing call %+v in %v", c, r.Calls)
+		}
+	}
+}
+
+func TestAnalyzePython(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"src/shop/__init__.py": "",
+		"src/shop/pricing.py": `"""Pricing rules (see discount() docs)."""
+
+def discount(total):
+    return total * 0.9
+
+
+class Cart:
+    def total(self):
+        return self.subtotal()
+
+    def subtotal(self):
+        return 10
+`,
+		"src/shop/checkout.py": `from .pricing import (
+    discount,
+    Cart,
+)
+import shop.pricing as pricing
+
+
+def checkout(cart):
+    # discount() in a comment is not a call
+    value = discount(cart.total())
+    return pricing.discount(value)
+`,
+		"tests/test_checkout.py": "from shop.checkout import checkout\n",
+	})
+
+	r, err := Analyze(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !hasImport(r, "src/shop/checkout.py", "src/shop/pricing.py") {
+		t.Errorf("missing import checkout.py -> pricing.py in %v", r.Imports)
+	}
+	if !hasCall(r, "src/shop/checkout.py", "checkout", "src/shop/pricing.py", "discount") {
+		t.Errorf("missing call checkout -> discount in %v", r.Calls)
+	}
+	if !hasCall(r, "src/shop/pricing.py", "total", "src/shop/pricing.py", "subtotal") {
+		t.Errorf("missing call total -> subtotal in %v", r.Calls)
+	}
+	for _, f := range r.Files {
+		if f == "tests/test_checkout.py" {
+			t.Error("test files should not be analyzed")
+		}
+	}
+}
+
+func TestAnalyzeJS(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"src/api/client.ts": `import { retry } from '../util/retry';
+import log from "../util/log.js";
+import * as cache from './cache';
+
+export class Client {
+  fetch(url: string): Promise<string> {
+    log("fetching {url}");
+    return retry(() => this.request(url));
+  }
+
+  request(url: string) {
+    return cache.lookup(url);
+  }
+}
+`,
+		"src/api/cache.ts": `export function lookup(key: string) {
+  return null;
+}
+`,
+		"src/util/retry.ts": `export const retry = async (fn: () => Promise<string>) => {
+  return fn();
+};
+`,
+		"src/util/log.ts": `export default function log(msg: string) {
+  console.log(msg);
+}
+`,
+		"src/api/client.test.ts": "import { Client } from './client';\n",
+	})
+
+	r, err := Analyze(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, to := range []string{"src/util/retry.ts", "src/util/log.ts", "src/api/cache.ts"} {
+		if !hasImport(r, "src/api/client.ts", to) {
+			t.Errorf("missing import client.ts -> %s in %v", to, r.Imports)
+		}
+	}
+	for _, c := range []Call{
+		{"src/api/client.ts", "fetch", "src/util/retry.ts", "retry"},
+		{"src/api/client.ts", "fetch", "src/util/log.ts", "log"},
+		{"src/api/client.ts", "fetch", "src/api/client.ts", "request"},
+		{"src/api/client.ts", "request", "src/api/cache.ts", "lookup"},
+	} {
+		if !hasCall(r, c.FromFile, c.FromBlock, c.ToFile, c.ToBlock) {
+			t.Errorf("missing call %+v in %v", c, r.Calls)
+		}
+	}
+
+	deps := r.Dependents([]string{"src/util/retry.ts"})
+	if !reflect.DeepEqual(deps, []string{"src/api/client.ts"}) {
+		t.Errorf("Dependents(retry.ts) = %v, want [src/api/client.ts]", deps)
+	}
+}
+
+func TestExternalImports(t *testing.T) {
+	root := writeFixture(t, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"api/server.go": `package api
+
+import (
+	"net/http"
+
+	"example.com/shop/billing"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+var _ = http.StatusOK
+var _ = middleware.Logger
+var _ = billing.Total
+`,
+		"billing/total.go": "package billing\n\nvar Total = 1\n",
+		"shop/__init__.py": "",
+		"shop/report.py":   "import yaml\nfrom requests.adapters import HTTPAdapter\nfrom shop import total\nimport shop\n",
+		"shop/total.py":    "",
+		"web/app.ts":       "import React from 'react';\nimport { z } from \"@scope/pkg/sub\";\nimport fs from 'node:fs';\nimport './local';\n",
+	})
+
+	r, err := Analyze(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ExternalImport{
+		{File: "api/server.go", Language: "go", Spec: "github.com/go-chi/chi/v5/middleware"},
+		{File: "shop/report.py", Language: "python", Spec: "requests.adapters"},
+		{File: "shop/report.py", Language: "python", Spec: "yaml"},
+		{File: "web/app.ts", Language: "javascript", Spec: "@scope/pkg/sub"},
+		{File: "web/app.ts", Language: "javascript", Spec: "react"},
+	}
+	if !reflect.DeepEqual(r.External, want) {
+		t.Errorf("External = %+v\nwant %+v", r.External, want)
+	}
+}
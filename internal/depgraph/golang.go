@@ -0,0 +1,260 @@
+package depgraph
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// goPackage indexes the top-level declarations of one package directory
+type goPackage struct {
+	name  string
+	decls map[string]string // Declared name ("Foo", or "Type.Method" for methods) -> file
+	funcs map[string]bool   // Names in decls that are functions or methods
+}
+
+// goModule is a go.mod found in the repository
+type goModule struct {
+	dir  string // Directory of go.mod, relative to the repository root ("." at the root)
+	path string // Module path
+}
+
+// analyzeGo resolves Go imports to the files declaring the symbols a file uses
+// Importing a package does not couple a file to every file in it, so each pkg.Name selector
+// is resolved to the file that declares Name. Calls are resolved for package functions,
+// functions of imported repository packages, and methods called on the function's receiver.
+func (a *analyzer) analyzeGo(files []string) {
+	fset := token.NewFileSet()
+	parsed := make(map[string]*ast.File, len(files))
+	packages := make(map[string]*goPackage)
+
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(a.root, file), nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		parsed[file] = f
+
+		dir := path.Dir(file)
+		pkg := packages[dir]
+		if pkg == nil {
+			pkg = &goPackage{name: f.Name.Name, decls: make(map[string]string), funcs: make(map[string]bool)}
+			packages[dir] = pkg
+		}
+		indexGoDecls(pkg, file, f)
+	}
+
+	modules := make(map[string]*goModule)
+	for _, file := range files {
+		f := parsed[file]
+		if f == nil {
+			continue
+		}
+		dir := path.Dir(file)
+		mod := a.goModuleFor(dir, modules)
+
+		// Local package name -> imported repository package directory
+		imported := make(map[string]string)
+		for _, spec := range f.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			target, ok := "", false
+			if mod != nil {
+				target, ok = resolveGoImport(importPath, mod)
+			}
+			if !ok {
+				// Standard library paths have no dot in their first element
+				if first, _, _ := strings.Cut(importPath, "/"); strings.Contains(first, ".") {
+					a.addExternal(file, "go", importPath)
+				}
+				continue
+			}
+			if packages[target] == nil {
+				continue
+			}
+			name := packages[target].name
+			if spec.Name != nil {
+				if spec.Name.Name == "_" || spec.Name.Name == "." {
+					continue
+				}
+				name = spec.Name.Name
+			}
+			imported[name] = target
+		}
+
+		a.goFileImports(file, f, imported, packages)
+		a.goFileCalls(file, f, imported, packages[dir], packages)
+	}
+}
+
+// indexGoDecls records a file's top-level declarations in its package
+func indexGoDecls(pkg *goPackage, file string, f *ast.File) {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv != nil {
+				recv := goReceiverType(d)
+				if recv == "" {
+					continue
+				}
+				name = recv + "." + name
+			}
+			pkg.decls[name] = file
+			pkg.funcs[name] = true
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					pkg.decls[s.Name.Name] = file
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						pkg.decls[n.Name] = file
+					}
+				}
+			}
+		}
+	}
+}
+
+// goFileImports adds an import edge for every repository file whose declarations a file selects
+func (a *analyzer) goFileImports(file string, f *ast.File, imported map[string]string, packages map[string]*goPackage) {
+	if len(imported) == 0 {
+		return
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok {
+			if dir, ok := imported[x.Name]; ok {
+				if target, ok := packages[dir].decls[sel.Sel.Name]; ok {
+					a.addImport(file, target)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// goFileCalls adds call edges from each function and method declared in a file
+func (a *analyzer) goFileCalls(file string, f *ast.File, imported map[string]string, local *goPackage, packages map[string]*goPackage) {
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		recvName, recvType := "", ""
+		if fn.Recv != nil && len(fn.Recv.List) > 0 && len(fn.Recv.List[0].Names) > 0 {
+			recvName = fn.Recv.List[0].Names[0].Name
+			recvType = goReceiverType(fn)
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				if local.funcs[fun.Name] {
+					a.addCall(file, fn.Name.Name, local.decls[fun.Name], fun.Name)
+				}
+			case *ast.SelectorExpr:
+				x, ok := fun.X.(*ast.Ident)
+				if !ok {
+					break
+				}
+				name := fun.Sel.Name
+				if dir, ok := imported[x.Name]; ok && packages[dir].funcs[name] {
+					a.addCall(file, fn.Name.Name, packages[dir].decls[name], name)
+				} else if x.Name == recvName && recvName != "_" && local.funcs[recvType+"."+name] {
+					a.addCall(file, fn.Name.Name, local.decls[recvType+"."+name], name)
+				}
+			}
+			return true
+		})
+	}
+}
+
+// goReceiverType returns the receiver's type name, without pointer or type parameters
+func goReceiverType(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.ParenExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// resolveGoImport maps an import path inside the module to a package directory
+func resolveGoImport(importPath string, mod *goModule) (string, bool) {
+	var rest string
+	switch {
+	case importPath == mod.path:
+		rest = ""
+	case strings.HasPrefix(importPath, mod.path+"/"):
+		rest = strings.TrimPrefix(importPath, mod.path+"/")
+	default:
+		return "", false
+	}
+	return path.Join(mod.dir, rest), true
+}
+
+// goModuleFor finds the nearest go.mod at or above dir, caching lookups per directory
+func (a *analyzer) goModuleFor(dir string, cache map[string]*goModule) *goModule {
+	if mod, ok := cache[dir]; ok {
+		return mod
+	}
+
+	var mod *goModule
+	if modulePath := readModulePath(filepath.Join(a.root, dir, "go.mod")); modulePath != "" {
+		mod = &goModule{dir: dir, path: modulePath}
+	} else if dir != "." {
+		mod = a.goModuleFor(path.Dir(dir), cache)
+	}
+	cache[dir] = mod
+	return mod
+}
+
+// readModulePath returns the module directive of a go.mod file, or "" if there is none
+func readModulePath(goMod string) string {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if modulePath, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(modulePath), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,401 @@
+package depgraph
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	jsImportFromPattern = regexp.MustCompile(`(?m)^\s*import\s+(?:type\s+)?([\w$*{},\s]+?)\s+from\s+['"]([^'"]+)['"]`)
+	jsImportBarePattern = regexp.MustCompile(`(?m)^\s*import\s+['"]([^'"]+)['"]`)
+	jsReexportPattern   = regexp.MustCompile(`(?m)^\s*export\s+(?:type\s+)?(?:\*(?:\s+as\s+[\w$]+)?|\{[^}]*\})\s+from\s+['"]([^'"]+)['"]`)
+	jsRequirePattern    = regexp.MustCompile(`(?:(?:const|let|var)\s+(\{[^}]*\}|[\w$]+)\s*=\s*)?require\(\s*['"]([^'"]+)['"]\s*\)`)
+	jsDynamicPattern    = regexp.MustCompile(`\bimport\(\s*['"]([^'"]+)['"]\s*\)`)
+
+	jsFunctionPattern = regexp.MustCompile(`(?m)^[ \t]*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\*?\s+([\w$]+)`)
+	jsArrowPattern    = regexp.MustCompile(`(?m)^[ \t]*(?:export\s+)?(?:const|let|var)\s+([\w$]+)\s*(?::[^=]+)?=\s*(?:async\s+)?(?:function\b|\([^)]*\)\s*(?::[^=]+)?=>|[\w$]+\s*=>)`)
+	jsClassPattern    = regexp.MustCompile(`(?m)^[ \t]*(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+([\w$]+)`)
+	jsMethodPattern   = regexp.MustCompile(`(?m)^[ \t]+(?:(?:public|private|protected|static|async|override|readonly|get|set)\s+)*([\w$]+)\s*(?:<[^>]*>)?\([^)]*\)\s*(?::[^{;]+)?\{`)
+	jsDefaultPattern  = regexp.MustCompile(`(?m)^\s*export\s+default\s+(?:async\s+)?(?:function\*?\s+|class\s+)?([\w$]+)`)
+	jsCallPattern     = regexp.MustCompile(`([\w$]+(?:\.[\w$]+)?)\s*\(`)
+)
+
+// jsExtensions are tried, in order, for extensionless relative imports
+var jsExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", ".mts", ".cts"}
+
+// jsKeywords are followed by "(" without being calls
+var jsKeywords = map[string]bool{
+	"if": true, "for": true, "while": true, "switch": true, "catch": true, "function": true,
+	"return": true, "typeof": true, "await": true, "super": true, "import": true, "require": true,
+}
+
+// jsBinding is what a local name refers to after an import
+type jsBinding struct {
+	file   string
+	symbol string // Imported name; "default" for default imports, empty for namespaces
+}
+
+// jsBlock is a top-level function or class method and its body's byte range
+type jsBlock struct {
+	name       string
+	start, end int
+}
+
+// jsSource is a parsed JavaScript or TypeScript file
+type jsSource struct {
+	file    string
+	content string // Original source, for import specifiers
+	masked  string // Comments and string literals blanked, offsets preserved
+	blocks  []jsBlock
+	defs    map[string]bool
+	deflt   string // Name exported as default
+	nameAt  map[int]bool
+}
+
+// analyzeJS resolves relative imports and requires between repository modules
+// Bare specifiers (packages, path aliases) are not resolved. Calls are resolved for named,
+// default and namespace imports, this.method, and functions defined in the same file.
+func (a *analyzer) analyzeJS(files []string) {
+	sources := make(map[string]*jsSource, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(a.root, file))
+		if err != nil {
+			continue
+		}
+		sources[file] = parseJSSource(file, string(content))
+	}
+
+	for _, file := range files {
+		src := sources[file]
+		if src == nil {
+			continue
+		}
+		bindings := a.jsImports(src)
+		a.jsCalls(src, bindings, sources)
+	}
+}
+
+// parseJSSource finds the declarations and function bodies of a file
+func parseJSSource(file, content string) *jsSource {
+	src := &jsSource{
+		file:    file,
+		content: content,
+		masked:  maskJS(content),
+		defs:    make(map[string]bool),
+		nameAt:  make(map[int]bool),
+	}
+
+	depth := braceDepths(src.masked)
+	addBlock := func(name string, nameStart, declEnd int) {
+		src.defs[name] = true
+		src.nameAt[nameStart] = true
+		if end := jsBodyEnd(src.masked, declEnd); end > declEnd {
+			src.blocks = append(src.blocks, jsBlock{name: name, start: declEnd, end: end})
+		}
+	}
+
+	for _, pattern := range []*regexp.Regexp{jsFunctionPattern, jsArrowPattern} {
+		for _, m := range pattern.FindAllStringSubmatchIndex(src.masked, -1) {
+			if depth[m[2]] == 0 {
+				addBlock(src.masked[m[2]:m[3]], m[2], m[1])
+			}
+		}
+	}
+
+	for _, m := range jsClassPattern.FindAllStringSubmatchIndex(src.masked, -1) {
+		if depth[m[2]] != 0 {
+			continue
+		}
+		name := src.masked[m[2]:m[3]]
+		src.defs[name] = true
+		src.nameAt[m[2]] = true
+
+		classEnd := jsBodyEnd(src.masked, m[1])
+		if classEnd <= m[1] {
+			continue
+		}
+		for _, mm := range jsMethodPattern.FindAllStringSubmatchIndex(src.masked[m[1]:classEnd], -1) {
+			nameStart := m[1] + mm[2]
+			method := src.masked[nameStart : m[1]+mm[3]]
+			if depth[nameStart] != 1 || jsKeywords[method] {
+				continue
+			}
+			// The pattern ends at the body's opening brace
+			addBlock(method, nameStart, m[1]+mm[1]-1)
+		}
+	}
+
+	if m := jsDefaultPattern.FindStringSubmatch(src.masked); m != nil {
+		src.deflt = m[1]
+	}
+	return src
+}
+
+// jsImports adds import edges for a file and returns its local bindings
+func (a *analyzer) jsImports(src *jsSource) map[string]jsBinding {
+	bindings := make(map[string]jsBinding)
+	resolve := func(spec string) (string, bool) {
+		target, ok := a.resolveJSImport(src.file, spec)
+		if ok {
+			a.addImport(src.file, target)
+		} else if isBareSpecifier(spec) {
+			a.addExternal(src.file, "javascript", spec)
+		}
+		return target, ok
+	}
+
+	for _, m := range jsImportFromPattern.FindAllStringSubmatch(src.content, -1) {
+		if target, ok := resolve(m[2]); ok {
+			jsBindClause(m[1], target, bindings)
+		}
+	}
+	for _, m := range jsRequirePattern.FindAllStringSubmatch(src.content, -1) {
+		if target, ok := resolve(m[2]); ok && m[1] != "" {
+			jsBindClause(m[1], target, bindings)
+		}
+	}
+	for _, pattern := range []*regexp.Regexp{jsImportBarePattern, jsReexportPattern, jsDynamicPattern} {
+		for _, m := range pattern.FindAllStringSubmatch(src.content, -1) {
+			resolve(m[1])
+		}
+	}
+	return bindings
+}
+
+// isBareSpecifier reports package specifiers ("react", "@scope/pkg/sub"), as opposed to
+// relative or absolute paths and Node built-ins ("node:fs")
+func isBareSpecifier(spec string) bool {
+	if spec == "" || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") || strings.HasPrefix(spec, "node:") {
+		return false
+	}
+	return !strings.Contains(spec, "://")
+}
+
+// jsBindClause records the names an import clause binds
+// Handles "def", "* as ns", "{ a, b as c }", and combinations; a bare identifier from
+// require() binds the module like a namespace import.
+func jsBindClause(clause, target string, bindings map[string]jsBinding) {
+	clause = strings.TrimSpace(clause)
+	if open := strings.Index(clause, "{"); open >= 0 {
+		close := strings.Index(clause, "}")
+		if close > open {
+			for _, item := range strings.Split(clause[open+1:close], ",") {
+				fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(item), "type "))
+				switch {
+				case len(fields) == 3 && fields[1] == "as":
+					bindings[fields[2]] = jsBinding{file: target, symbol: fields[0]}
+				case len(fields) == 3 && fields[1] == ":": // const { a: b } = require()
+					bindings[fields[2]] = jsBinding{file: target, symbol: fields[0]}
+				case len(fields) == 1:
+					name, local, found := strings.Cut(fields[0], ":")
+					if !found {
+						local = name
+					}
+					bindings[local] = jsBinding{file: target, symbol: name}
+				}
+			}
+			clause = clause[:open] + clause[close+1:]
+		}
+	}
+
+	for _, part := range strings.Split(clause, ",") {
+		fields := strings.Fields(part)
+		switch {
+		case len(fields) == 3 && fields[0] == "*" && fields[1] == "as":
+			bindings[fields[2]] = jsBinding{file: target}
+		case len(fields) == 1 && fields[0] != "*":
+			bindings[fields[0]] = jsBinding{file: target, symbol: "default"}
+		}
+	}
+}
+
+// jsCalls attributes calls inside each function body to the functions they resolve to
+func (a *analyzer) jsCalls(src *jsSource, bindings map[string]jsBinding, sources map[string]*jsSource) {
+	for _, m := range jsCallPattern.FindAllStringSubmatchIndex(src.masked, -1) {
+		if src.nameAt[m[2]] {
+			continue // The declaration itself
+		}
+		block := jsBlockAt(src.blocks, m[0])
+		if block == "" {
+			continue
+		}
+
+		callee := src.masked[m[2]:m[3]]
+		toFile, toBlock := jsResolveCall(callee, src, bindings, sources)
+		if toFile != "" && sources[toFile] != nil && sources[toFile].defs[toBlock] {
+			a.addCall(src.file, block, toFile, toBlock)
+		}
+	}
+}
+
+// jsResolveCall maps a called name (func, ns.func, this.method) to a file and function
+func jsResolveCall(callee string, src *jsSource, bindings map[string]jsBinding, sources map[string]*jsSource) (string, string) {
+	receiver, name, qualified := strings.Cut(callee, ".")
+	if !qualified {
+		if jsKeywords[callee] {
+			return "", ""
+		}
+		if b, ok := bindings[callee]; ok && b.symbol != "" {
+			if b.symbol == "default" && sources[b.file] != nil {
+				return b.file, sources[b.file].deflt
+			}
+			return b.file, b.symbol
+		}
+		if src.defs[callee] {
+			return src.file, callee
+		}
+		return "", ""
+	}
+
+	if receiver == "this" {
+		return src.file, name
+	}
+	if b, ok := bindings[receiver]; ok && b.symbol == "" {
+		return b.file, name
+	}
+	return "", ""
+}
+
+// resolveJSImport maps a relative specifier to an analyzed file
+func (a *analyzer) resolveJSImport(from, spec string) (string, bool) {
+	if !strings.HasPrefix(spec, "./") && !strings.HasPrefix(spec, "../") {
+		return "", false
+	}
+	base := path.Join(path.Dir(from), spec)
+
+	candidates := []string{base}
+	for _, ext := range jsExtensions {
+		candidates = append(candidates, base+ext)
+	}
+	for _, ext := range jsExtensions {
+		candidates = append(candidates, base+"/index"+ext)
+	}
+	// TypeScript ESM imports name the emitted .js file
+	switch path.Ext(base) {
+	case ".js", ".jsx", ".mjs", ".cjs":
+		stem := strings.TrimSuffix(base, path.Ext(base))
+		candidates = append(candidates, stem+".ts", stem+".tsx", stem+".mts", stem+".cts")
+	}
+
+	for _, c := range candidates {
+		if a.files[c] {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// jsBlockAt returns the innermost block containing offset
+func jsBlockAt(blocks []jsBlock, offset int) string {
+	name, start := "", -1
+	for _, b := range blocks {
+		if offset > b.start && offset < b.end && b.start > start {
+			name, start = b.name, b.start
+		}
+	}
+	return name
+}
+
+// jsBodyEnd returns the offset just past the body that starts after a declaration
+// Block bodies run to their matching brace; expression-bodied arrows to the end of the line.
+func jsBodyEnd(masked string, from int) int {
+	i := from
+	for i < len(masked) && masked[i] != '{' && masked[i] != '\n' && masked[i] != ';' {
+		i++
+	}
+	if i >= len(masked) || masked[i] != '{' {
+		// Signature continues on the next line, or an expression body
+		if next := strings.IndexAny(masked[from:], "{;"); next >= 0 && masked[from+next] == '{' &&
+			!strings.Contains(masked[from:from+next], "=>") {
+			i = from + next
+		} else {
+			end := strings.IndexByte(masked[from:], '\n')
+			if end < 0 {
+				return len(masked)
+			}
+			return from + end
+		}
+	}
+
+	depth := 0
+	for ; i < len(masked); i++ {
+		switch masked[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(masked)
+}
+
+// braceDepths returns the brace nesting depth at every offset
+func braceDepths(masked string) []int {
+	depths := make([]int, len(masked)+1)
+	depth := 0
+	for i := 0; i < len(masked); i++ {
+		depths[i] = depth
+		switch masked[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	depths[len(masked)] = depth
+	return depths
+}
+
+// maskJS blanks comments and string, template and regex-free literal contents, keeping
+// offsets and newlines, so braces and parentheses inside them are not counted
+func maskJS(src string) string {
+	out := []byte(src)
+	blank := func(from, to int) {
+		for k := from; k < to && k < len(out); k++ {
+			if out[k] != '\n' {
+				out[k] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(src); i++ {
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			blank(i, i+end)
+			i += end - 1
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			blank(i, i+2+end+2)
+			i += 2 + end + 1
+		case src[i] == '"' || src[i] == '\'' || src[i] == '`':
+			quote := src[i]
+			j := i + 1
+			for j < len(src) && src[j] != quote {
+				if src[j] == '\\' {
+					j++
+				} else if src[j] == '\n' && quote != '`' {
+					break // Unterminated
+				}
+				j++
+			}
+			blank(i+1, j)
+			i = j
+		}
+	}
+	return string(out)
+}
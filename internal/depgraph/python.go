@@ -0,0 +1,341 @@
+package depgraph
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	pyImportPattern     = regexp.MustCompile(`^import\s+(.+)$`)
+	pyFromImportPattern = regexp.MustCompile(`^from\s+(\.*)([\w.]*)\s+import\s+(.+)$`)
+	pyDefPattern        = regexp.MustCompile(`^(\s*)(?:async\s+)?def\s+(\w+)`)
+	pyClassPattern      = regexp.MustCompile(`^(\s*)class\s+(\w+)`)
+	pyCallPattern       = regexp.MustCompile(`([A-Za-z_][\w.]*)\s*\(`)
+)
+
+// pyBinding is what a local name refers to after an import
+type pyBinding struct {
+	file   string // Module file
+	symbol string // Imported name; empty when the binding is the module itself
+}
+
+// pySource is a Python file split into logical lines
+type pySource struct {
+	file  string
+	lines []string // Comment-free lines, with docstrings blanked and continuations joined
+}
+
+// analyzePython resolves absolute and relative imports between repository modules
+// A module is known by its dotted path from the repository root and, for src/ layouts,
+// from inside src/. Calls are resolved for imported functions, module attributes
+// (module.func), self.method, and functions defined in the same file.
+func (a *analyzer) analyzePython(files []string) {
+	modules := make(map[string]string) // Dotted module name -> file
+	packages := make(map[string]bool)  // Module names and their parent packages
+	sources := make([]pySource, 0, len(files))
+	defs := make(map[string]map[string]bool)
+
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(a.root, file))
+		if err != nil {
+			continue
+		}
+		src := pySource{file: file, lines: pyLogicalLines(string(content))}
+		sources = append(sources, src)
+
+		for _, name := range pyModuleNames(file) {
+			if _, taken := modules[name]; !taken || path.Base(file) == "__init__.py" {
+				modules[name] = file
+			}
+			for i, c := range name {
+				if c == '.' {
+					packages[name[:i]] = true
+				}
+			}
+			packages[name] = true
+		}
+
+		defs[file] = make(map[string]bool)
+		for _, line := range src.lines {
+			if m := pyDefPattern.FindStringSubmatch(line); m != nil {
+				defs[file][m[2]] = true
+			} else if m := pyClassPattern.FindStringSubmatch(line); m != nil {
+				defs[file][m[2]] = true
+			}
+		}
+	}
+
+	for _, src := range sources {
+		bindings := a.pyImports(src, modules, packages)
+		a.pyCalls(src, bindings, defs)
+	}
+}
+
+// pyImports adds import edges for a file and returns its local bindings
+// Absolute imports of modules outside the repository are recorded as external.
+func (a *analyzer) pyImports(src pySource, modules map[string]string, packages map[string]bool) map[string]pyBinding {
+	bindings := make(map[string]pyBinding)
+
+	for _, line := range src.lines {
+		stmt := strings.TrimSpace(line)
+
+		if m := pyImportPattern.FindStringSubmatch(stmt); m != nil {
+			for _, item := range strings.Split(m[1], ",") {
+				name, alias := pySplitAlias(item)
+				target, ok := modules[name]
+				if !ok {
+					if !packages[name] {
+						a.addExternal(src.file, "python", name)
+					}
+					continue
+				}
+				a.addImport(src.file, target)
+				if alias == "" {
+					alias = name // Referenced by its full dotted name
+				}
+				bindings[alias] = pyBinding{file: target}
+			}
+			continue
+		}
+
+		m := pyFromImportPattern.FindStringSubmatch(stmt)
+		if m == nil {
+			continue
+		}
+		base, ok := pyResolveModule(src.file, len(m[1]), m[2])
+		if !ok {
+			continue
+		}
+		if len(m[1]) == 0 && !packages[base] {
+			a.addExternal(src.file, "python", base)
+			continue
+		}
+		names := strings.Trim(strings.TrimSpace(m[3]), "()")
+		for _, item := range strings.Split(names, ",") {
+			name, alias := pySplitAlias(item)
+			if name == "" {
+				continue
+			}
+			if alias == "" {
+				alias = name
+			}
+
+			// from package import submodule
+			if target, ok := modules[pyJoin(base, name)]; ok && name != "*" {
+				a.addImport(src.file, target)
+				bindings[alias] = pyBinding{file: target}
+				continue
+			}
+			if target, ok := modules[base]; ok {
+				a.addImport(src.file, target)
+				if name != "*" {
+					bindings[alias] = pyBinding{file: target, symbol: name}
+				}
+			}
+		}
+	}
+	return bindings
+}
+
+// pyCalls attributes calls inside each def to the functions they resolve to
+func (a *analyzer) pyCalls(src pySource, bindings map[string]pyBinding, defs map[string]map[string]bool) {
+	block, blockIndent := "", -1
+
+	for _, line := range src.lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if m := pyDefPattern.FindStringSubmatch(line); m != nil {
+			// Nested functions belong to the enclosing block
+			if block == "" || indent <= blockIndent {
+				block, blockIndent = m[2], indent
+			}
+			continue
+		}
+		if block != "" && indent <= blockIndent {
+			block, blockIndent = "", -1
+		}
+		if block == "" {
+			continue
+		}
+
+		for _, m := range pyCallPattern.FindAllStringSubmatch(line, -1) {
+			toFile, toBlock := pyResolveCall(m[1], src.file, bindings, defs)
+			if toFile != "" && defs[toFile][toBlock] {
+				a.addCall(src.file, block, toFile, toBlock)
+			}
+		}
+	}
+}
+
+// pyResolveCall maps a called name (func, module.func, self.method) to a file and function
+func pyResolveCall(callee, file string, bindings map[string]pyBinding, defs map[string]map[string]bool) (string, string) {
+	dot := strings.LastIndex(callee, ".")
+	if dot < 0 {
+		if b, ok := bindings[callee]; ok && b.symbol != "" {
+			return b.file, b.symbol
+		}
+		if defs[file][callee] {
+			return file, callee
+		}
+		return "", ""
+	}
+
+	receiver, name := callee[:dot], callee[dot+1:]
+	if receiver == "self" || receiver == "cls" {
+		return file, name
+	}
+	if b, ok := bindings[receiver]; ok && b.symbol == "" {
+		return b.file, name
+	}
+	return "", ""
+}
+
+// pyModuleNames returns the dotted names a file can be imported by
+func pyModuleNames(file string) []string {
+	parts := strings.Split(strings.TrimSuffix(file, path.Ext(file)), "/")
+	if parts[len(parts)-1] == "__init__" {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	names := []string{strings.Join(parts, ".")}
+	if len(parts) > 1 && (parts[0] == "src" || parts[0] == "lib") {
+		names = append(names, strings.Join(parts[1:], "."))
+	}
+	return names
+}
+
+// pyResolveModule resolves a from-import's module, relative to the file when dots > 0
+func pyResolveModule(file string, dots int, module string) (string, bool) {
+	if dots == 0 {
+		return module, module != ""
+	}
+
+	// The package containing the file is one dot; each further dot goes up a level
+	pkg := strings.Split(path.Dir(file), "/")
+	if path.Dir(file) == "." {
+		pkg = nil
+	}
+	up := dots - 1
+	if up > len(pkg) {
+		return "", false
+	}
+	pkg = pkg[:len(pkg)-up]
+	return pyJoin(strings.Join(pkg, "."), module), true
+}
+
+func pyJoin(base, name string) string {
+	switch {
+	case base == "":
+		return name
+	case name == "":
+		return base
+	}
+	return base + "." + name
+}
+
+// pySplitAlias splits "name as alias"
+func pySplitAlias(item string) (name, alias string) {
+	fields := strings.Fields(item)
+	switch {
+	case len(fields) == 3 && fields[1] == "as":
+		return fields[0], fields[2]
+	case len(fields) >= 1:
+		return fields[0], ""
+	}
+	return "", ""
+}
+
+// pyLogicalLines strips comments and triple-quoted strings and joins continued lines, so
+// that parenthesized imports and calls split across lines are matched as one line
+func pyLogicalLines(content string) []string {
+	var lines []string
+	var pending strings.Builder
+	depth := 0
+	inDocstring := ""
+
+	for _, raw := range strings.Split(content, "\n") {
+		line := raw
+		if inDocstring != "" {
+			end := strings.Index(line, inDocstring)
+			if end < 0 {
+				lines = append(lines, "")
+				continue
+			}
+			line = strings.Repeat(" ", end+3) + line[end+3:]
+			inDocstring = ""
+		}
+		line, inDocstring = pyStripStrings(line)
+
+		if pending.Len() > 0 {
+			pending.WriteString(" ")
+			pending.WriteString(strings.TrimSpace(line))
+		} else {
+			pending.WriteString(line)
+		}
+		depth += strings.Count(line, "(") + strings.Count(line, "[") - strings.Count(line, ")") - strings.Count(line, "]")
+
+		if depth > 0 || strings.HasSuffix(strings.TrimRight(line, " \t"), "\\") {
+			continue
+		}
+		lines = append(lines, strings.TrimSuffix(pending.String(), "\\"))
+		pending.Reset()
+		depth = 0
+	}
+	if pending.Len() > 0 {
+		lines = append(lines, pending.String())
+	}
+	return lines
+}
+
+// pyStripStrings blanks string literals and comments on a line
+// Returns the opening delimiter when a triple-quoted string continues past the line.
+func pyStripStrings(line string) (string, string) {
+	var b strings.Builder
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '#':
+			return b.String(), ""
+		case c == '"' || c == '\'':
+			quote := string(c)
+			if strings.HasPrefix(line[i:], quote+quote+quote) {
+				quote = quote + quote + quote
+			}
+			end := indexUnescaped(line[i+len(quote):], quote)
+			if end < 0 {
+				if len(quote) == 3 {
+					return b.String(), quote
+				}
+				return b.String(), ""
+			}
+			b.WriteString(quote + quote)
+			i += len(quote) + end + len(quote) - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), ""
+}
+
+// indexUnescaped returns the index of the first quote not preceded by a backslash, or -1
+func indexUnescaped(s, quote string) int {
+	for i := 0; i+len(quote) <= len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], quote) {
+			return i
+		}
+	}
+	return -1
+}
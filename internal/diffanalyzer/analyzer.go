@@ -119,14 +119,15 @@ func (a *Analyzer) Analyze(ctx context.Context, repoID int64, diff string) (*Ris
 
 			a.logger.Printf("[STEP 4] Querying risk for block_id=%d (%s)", blockID, blockName)
 
-			// Query all 4 dimensions in parallel
+			// Query all 5 dimensions in parallel
 			var innerWg sync.WaitGroup
 			var temporal *TemporalRisk
 			var ownership *OwnershipRisk
 			var coupling *CouplingRisk
 			var history *ChangeHistory
+			var defects *DefectRisk
 
-			innerWg.Add(4)
+			innerWg.Add(5)
 
 			go func() {
 				defer innerWg.Done()
@@ -176,6 +177,18 @@ func (a *Analyzer) Analyze(ctx context.Context, repoID int64, diff string) (*Ris
 				}
 			}()
 
+			go func() {
+				defer innerWg.Done()
+				var err error
+				defects, err = a.neo4jAggregator.QueryDefectIntroduction(ctx, blockID)
+				if err != nil {
+					a.logger.Printf("[STEP 4] WARN: Defect introduction query failed for block_id=%d: %v", blockID, err)
+					mutex.Lock()
+					errorCount++
+					mutex.Unlock()
+				}
+			}()
+
 			innerWg.Wait()
 
 			riskData[idx] = BlockRiskData{
@@ -183,6 +196,7 @@ func (a *Analyzer) Analyze(ctx context.Context, repoID int64, diff string) (*Ris
 				Ownership:     ownership,
 				Coupling:      coupling,
 				ChangeHistory: history,
+				Defects:       defects,
 			}
 
 			a.logger.Printf("[STEP 4] Completed risk queries for block_id=%d", blockID)
@@ -247,6 +261,7 @@ func (a *Analyzer) buildEvidence(blockMatches []BlockMatch, riskData []BlockRisk
 				Ownership:     risk.Ownership,
 				Coupling:      risk.Coupling,
 				ChangeHistory: risk.ChangeHistory,
+				Defects:       risk.Defects,
 			},
 		}
 
@@ -323,6 +338,16 @@ func calculateRiskLevel(risk BlockRiskData) string {
 		}
 	}
 
+	// Defect introduction scoring (bonus): blocks whose changes keep needing fixes
+	// Requires a few changes so a single buggy edit doesn't dominate
+	if risk.Defects != nil && risk.Defects.TotalChanges >= 3 {
+		if risk.Defects.DefectIntroductionRate >= 0.5 {
+			score += 20
+		} else if risk.Defects.DefectIntroductionRate >= 0.25 {
+			score += 10
+		}
+	}
+
 	// Classify risk level
 	if score >= 70 {
 		return "CRITICAL"
@@ -370,3 +370,65 @@ func (a *Neo4jRiskAggregator) QueryHistory(ctx context.Context, blockID int64) (
 		RecentChanges: recentChanges,
 	}, nil
 }
+
+// QueryDefectIntroduction calculates the share of a function's changes that introduced bugs
+// A change counts when a later fix commit that touched the same block blamed it (SZZ INTRODUCED_BY)
+func (a *Neo4jRiskAggregator) QueryDefectIntroduction(ctx context.Context, blockID int64) (*DefectRisk, error) {
+	a.logger.Printf("[Neo4jRiskAggregator] Querying defect introduction rate for block_id=%d", blockID)
+
+	session := a.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	query := `
+		MATCH (cb:CodeBlock)-[:RENAMED_FROM*0..5]->(historical:CodeBlock)
+		WHERE cb.id = $blockId
+		WITH collect(cb) + collect(historical) as all_versions
+		UNWIND all_versions as version
+		MATCH (commit:Commit)-[:CREATED_BLOCK|MODIFIED_BLOCK]->(version)
+		OPTIONAL MATCH (fix:Commit)-[intro:INTRODUCED_BY]->(commit)
+		WHERE (fix)-[:MODIFIED_BLOCK]->(version) AND intro.confidence >= 0.3
+		WITH commit, count(intro) > 0 AS introduced_bug
+		RETURN
+			count(DISTINCT commit) AS total_changes,
+			collect(CASE WHEN introduced_bug THEN commit.sha END) AS introducing_commits
+	`
+
+	result, err := session.Run(ctx, query, map[string]interface{}{
+		"blockId": blockID,
+	})
+	if err != nil {
+		a.logger.Printf("[Neo4jRiskAggregator] ERROR: Defect introduction query failed: %v", err)
+		return nil, fmt.Errorf("defect introduction query failed: %w", err)
+	}
+
+	defects := &DefectRisk{}
+	if result.Next(ctx) {
+		record := result.Record()
+
+		if total, ok := record.Values[0].(int64); ok {
+			defects.TotalChanges = int(total)
+		}
+		if commits, ok := record.Values[1].([]interface{}); ok {
+			for _, c := range commits {
+				if sha, ok := c.(string); ok {
+					defects.IntroducingCommits = append(defects.IntroducingCommits, sha)
+				}
+			}
+		}
+	}
+
+	if err := result.Err(); err != nil {
+		a.logger.Printf("[Neo4jRiskAggregator] ERROR: Result iteration failed: %v", err)
+		return nil, fmt.Errorf("result iteration failed: %w", err)
+	}
+
+	defects.BugIntroducingChanges = len(defects.IntroducingCommits)
+	if defects.TotalChanges > 0 {
+		defects.DefectIntroductionRate = float64(defects.BugIntroducingChanges) / float64(defects.TotalChanges)
+	}
+
+	a.logger.Printf("[Neo4jRiskAggregator] Defect introduction: %d/%d changes introduced bugs (rate=%.2f)",
+		defects.BugIntroducingChanges, defects.TotalChanges, defects.DefectIntroductionRate)
+
+	return defects, nil
+}
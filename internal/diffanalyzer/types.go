@@ -23,6 +23,16 @@ type RiskDimensions struct {
 	Ownership     *OwnershipRisk `json:"ownership,omitempty"`
 	Coupling      *CouplingRisk  `json:"coupling,omitempty"`
 	ChangeHistory *ChangeHistory `json:"change_history,omitempty"`
+	Defects       *DefectRisk    `json:"defects,omitempty"`
+}
+
+// DefectRisk represents how often changes to a code block introduced bugs
+// Derived from SZZ INTRODUCED_BY edges between fixing and bug-introducing commits
+type DefectRisk struct {
+	TotalChanges           int      `json:"total_changes"`
+	BugIntroducingChanges  int      `json:"bug_introducing_changes"`
+	DefectIntroductionRate float64  `json:"defect_introduction_rate"` // 0.0-1.0
+	IntroducingCommits     []string `json:"introducing_commits,omitempty"`
 }
 
 // TemporalRisk represents incident history for a code block
@@ -130,4 +140,5 @@ type BlockRiskData struct {
 	Ownership      *OwnershipRisk
 	Coupling       *CouplingRisk
 	ChangeHistory  *ChangeHistory
+	Defects        *DefectRisk
 }
@@ -0,0 +1,293 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Blamer runs git blame against historical revisions (used for SZZ analysis)
+type Blamer struct {
+	repoPath string
+}
+
+// NewBlamer creates a new blamer for the given repo
+func NewBlamer(repoPath string) *Blamer {
+	return &Blamer{
+		repoPath: repoPath,
+	}
+}
+
+// RemovedLine is a line deleted or modified by a commit, numbered in the parent revision
+type RemovedLine struct {
+	FilePath string
+	Line     int
+	Content  string
+}
+
+// BlameLine attributes a single line to the commit that last touched it
+type BlameLine struct {
+	Line       int
+	CommitSHA  string
+	AuthorMail string
+	CommitTime time.Time
+}
+
+// RemovedLines returns the lines a commit deleted or modified, keyed by file path
+// Lines are numbered against the commit's first parent, ready to be blamed there
+func (b *Blamer) RemovedLines(ctx context.Context, commitSHA string) (map[string][]RemovedLine, error) {
+	// -U0 keeps hunks minimal; -M follows renames so the old path is reported
+	cmd := exec.CommandContext(ctx, "git", "diff", "-U0", "-M", "--no-color", commitSHA+"^", commitSHA)
+	cmd.Dir = b.repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s failed: %w", ShortSHA(commitSHA), err)
+	}
+
+	return ParseRemovedLines(string(output)), nil
+}
+
+// BlameLines blames the given lines of filePath at revision rev
+func (b *Blamer) BlameLines(ctx context.Context, rev, filePath string, lines []int) ([]BlameLine, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	args := []string{"blame", "--porcelain", "-w"}
+	for _, r := range lineRanges(lines) {
+		args = append(args, "-L", fmt.Sprintf("%d,%d", r[0], r[1]))
+	}
+	args = append(args, rev, "--", filePath)
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = b.repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git blame %s@%s failed: %w", filePath, ShortSHA(rev), err)
+	}
+
+	return ParsePorcelainBlame(string(output)), nil
+}
+
+// ParseRemovedLines extracts deleted lines from `git diff -U0` output
+// Added-only hunks contribute nothing: SZZ can only blame lines that existed before the fix
+func ParseRemovedLines(diff string) map[string][]RemovedLine {
+	result := make(map[string][]RemovedLine)
+
+	var currentFile string
+	var hunk HunkBody
+	oldLine := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if hunk.Consume(line) {
+			if strings.HasPrefix(line, "-") {
+				if currentFile != "" && oldLine > 0 {
+					result[currentFile] = append(result[currentFile], RemovedLine{
+						FilePath: currentFile,
+						Line:     oldLine,
+						Content:  line[1:],
+					})
+				}
+				oldLine++
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			path := strings.TrimPrefix(line, "--- ")
+			if path == "/dev/null" {
+				currentFile = "" // New file, nothing to blame
+			} else {
+				currentFile = strings.TrimPrefix(path, "a/")
+			}
+		case strings.HasPrefix(line, "+++ "):
+			// Target path is irrelevant, we blame the parent revision
+		case strings.HasPrefix(line, "@@ "):
+			oldLine = parseHunkOldStart(line)
+			hunk.Enter(line)
+		}
+	}
+
+	return result
+}
+
+// HunkBody tracks the old and new lines left in the current hunk of a unified diff
+// File headers only appear between hunks: inside one, a removed "-- comment" line reads
+// as "--- comment" and an added "++ x" line as "+++ x".
+type HunkBody struct {
+	old, new int
+}
+
+// Enter starts the hunk introduced by an "@@ -12,3 +12,4 @@" header
+func (h *HunkBody) Enter(header string) {
+	h.old, h.new = 0, 0
+	fields := strings.Fields(header)
+	if len(fields) >= 3 {
+		h.old = hunkLineCount(fields[1])
+		h.new = hunkLineCount(fields[2])
+	}
+}
+
+// Consume reports whether line is part of the current hunk's body, counting it off if so
+func (h *HunkBody) Consume(line string) bool {
+	if h.old <= 0 && h.new <= 0 {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(line, "-"):
+		h.old--
+	case strings.HasPrefix(line, "+"):
+		h.new--
+	case line == "" || strings.HasPrefix(line, " "):
+		h.old--
+		h.new--
+	case strings.HasPrefix(line, `\`):
+		// "\ No newline at end of file"
+	default:
+		h.old, h.new = 0, 0 // Truncated hunk: the next file has started
+		return false
+	}
+	return true
+}
+
+// hunkLineCount reads the line count from a "-12,3" or "+12" hunk header field
+func hunkLineCount(field string) int {
+	_, count, found := strings.Cut(field, ",")
+	if !found {
+		return 1
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseHunkOldStart reads the old-file start line from "@@ -12,3 +12,4 @@"
+func parseHunkOldStart(header string) int {
+	fields := strings.Fields(header)
+	if len(fields) < 2 || !strings.HasPrefix(fields[1], "-") {
+		return 0
+	}
+
+	start := strings.TrimPrefix(fields[1], "-")
+	if idx := strings.Index(start, ","); idx >= 0 {
+		start = start[:idx]
+	}
+
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// ParsePorcelainBlame parses `git blame --porcelain` output
+// Commit metadata is only printed the first time a commit appears, so it is cached by SHA
+func ParsePorcelainBlame(output string) []BlameLine {
+	type commitInfo struct {
+		mail string
+		time time.Time
+	}
+
+	commits := make(map[string]*commitInfo)
+	var result []BlameLine
+	var current *BlameLine
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "\t") {
+			// Content line terminates the entry
+			if current != nil {
+				if info, ok := commits[current.CommitSHA]; ok {
+					current.AuthorMail = info.mail
+					current.CommitTime = info.time
+				}
+				result = append(result, *current)
+				current = nil
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && len(fields[0]) == 40 && isHex(fields[0]) {
+			finalLine, err := strconv.Atoi(fields[2])
+			if err != nil {
+				continue
+			}
+			current = &BlameLine{Line: finalLine, CommitSHA: fields[0]}
+			if _, ok := commits[fields[0]]; !ok {
+				commits[fields[0]] = &commitInfo{}
+			}
+			continue
+		}
+
+		if current == nil || len(fields) < 2 {
+			continue
+		}
+
+		info := commits[current.CommitSHA]
+		switch fields[0] {
+		case "author-mail":
+			info.mail = strings.Trim(fields[1], "<>")
+		case "committer-time":
+			if ts, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				info.time = time.Unix(ts, 0).UTC()
+			}
+		}
+	}
+
+	return result
+}
+
+// lineRanges collapses line numbers into contiguous [start, end] ranges
+func lineRanges(lines []int) [][2]int {
+	sorted := append([]int(nil), lines...)
+	sort.Ints(sorted)
+
+	var ranges [][2]int
+	for _, n := range sorted {
+		if len(ranges) > 0 && n <= ranges[len(ranges)-1][1]+1 {
+			if n > ranges[len(ranges)-1][1] {
+				ranges[len(ranges)-1][1] = n
+			}
+			continue
+		}
+		ranges = append(ranges, [2]int{n, n})
+	}
+	return ranges
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// ShortSHA abbreviates a commit SHA for log and error messages
+func ShortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,167 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseRemovedLines(t *testing.T) {
+	diff := `diff --git a/pay/charge.go b/pay/charge.go
+index 1111111..2222222 100644
+--- a/pay/charge.go
++++ b/pay/charge.go
+@@ -10,2 +10,2 @@ func Charge() {
+-	amount := req.Amount
+-	total := amount * 2
++	amount := req.Amount
++	total := amount
+@@ -40,0 +41,3 @@ func Refund() {
++	// new lines only
+diff --git a/pay/new.go b/pay/new.go
+new file mode 100644
+--- /dev/null
++++ b/pay/new.go
+@@ -0,0 +1,2 @@
++package pay
+`
+
+	removed := ParseRemovedLines(diff)
+
+	if len(removed) != 1 {
+		t.Fatalf("expected 1 file with removed lines, got %d: %v", len(removed), removed)
+	}
+
+	lines := removed["pay/charge.go"]
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 removed lines, got %d", len(lines))
+	}
+	if lines[0].Line != 10 || lines[1].Line != 11 {
+		t.Errorf("unexpected line numbers: %d, %d", lines[0].Line, lines[1].Line)
+	}
+	if strings.TrimSpace(lines[1].Content) != "total := amount * 2" {
+		t.Errorf("unexpected content: %q", lines[1].Content)
+	}
+}
+
+func TestParseRemovedLines_HeaderLookalikes(t *testing.T) {
+	// Removed "-- " SQL comments read as "--- " and added "++" lines as "+++ "
+	diff := `diff --git a/db/schema.sql b/db/schema.sql
+--- a/db/schema.sql
++++ b/db/schema.sql
+@@ -3,3 +3,1 @@
+--- legacy column
+-ALTER TABLE users DROP COLUMN nickname;
+--- end
++++counter;
+diff --git a/db/seed.sql b/db/seed.sql
+--- a/db/seed.sql
++++ b/db/seed.sql
+@@ -1 +1 @@
+-INSERT INTO users VALUES (1);
++INSERT INTO users VALUES (2);
+`
+
+	removed := ParseRemovedLines(diff)
+
+	schema := removed["db/schema.sql"]
+	if len(schema) != 3 || schema[0].Line != 3 || schema[2].Line != 5 {
+		t.Fatalf("expected lines 3-5 of db/schema.sql, got %v", schema)
+	}
+	if schema[0].Content != "-- legacy column" {
+		t.Errorf("unexpected content: %q", schema[0].Content)
+	}
+	if seed := removed["db/seed.sql"]; len(seed) != 1 || seed[0].Line != 1 {
+		t.Errorf("expected line 1 of db/seed.sql, got %v", seed)
+	}
+	if len(removed) != 2 {
+		t.Errorf("expected 2 files, got %v", removed)
+	}
+}
+
+func TestLineRanges(t *testing.T) {
+	got := lineRanges([]int{7, 3, 4, 5, 9, 9, 10})
+	want := [][2]int{{3, 5}, {7, 7}, {9, 10}}
+
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("range %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+// TestBlamer_FindsIntroducingCommit builds a tiny repository where a bug is
+// introduced in one commit and fixed in a later one
+func TestBlamer_FindsIntroducingCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Dev", "GIT_AUTHOR_EMAIL=dev@example.com",
+			"GIT_COMMITTER_NAME=Dev", "GIT_COMMITTER_EMAIL=dev@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(dir, "calc.go"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	write("package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "add calc")
+
+	write("package calc\n\nfunc Add(a, b int) int {\n\treturn a - b\n}\n")
+	run("commit", "-q", "-am", "tweak add")
+	buggy := run("rev-parse", "HEAD")
+
+	write("package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n")
+	run("commit", "-q", "-am", "fix add (#1)")
+	fix := run("rev-parse", "HEAD")
+
+	ctx := context.Background()
+	blamer := NewBlamer(dir)
+
+	removed, err := blamer.RemovedLines(ctx, fix)
+	if err != nil {
+		t.Fatalf("RemovedLines: %v", err)
+	}
+	lines := removed["calc.go"]
+	if len(lines) != 1 || lines[0].Line != 4 {
+		t.Fatalf("expected line 4 removed, got %v", lines)
+	}
+
+	blamed, err := blamer.BlameLines(ctx, fix+"^", "calc.go", []int{4})
+	if err != nil {
+		t.Fatalf("BlameLines: %v", err)
+	}
+	if len(blamed) != 1 {
+		t.Fatalf("expected 1 blamed line, got %d", len(blamed))
+	}
+	if blamed[0].CommitSHA != buggy {
+		t.Errorf("expected %s, got %s", buggy, blamed[0].CommitSHA)
+	}
+	if blamed[0].AuthorMail != "dev@example.com" {
+		t.Errorf("unexpected author mail %q", blamed[0].AuthorMail)
+	}
+	if blamed[0].CommitTime.IsZero() {
+		t.Error("expected commit time to be parsed")
+	}
+}
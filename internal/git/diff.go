@@ -3,6 +3,7 @@ package git
 import (
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 )
 
@@ -165,3 +166,165 @@ func TruncateDiffForPrompt(diff string, maxLines int) string {
 
 	return strings.Join(result, "\n")
 }
+
+// LineRange is an inclusive range of line numbers in the new version of a file
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// GetChangedLineRanges returns the changed line ranges per file between base and head
+// With an empty base, uncommitted changes (staged and unstaged) against HEAD are used;
+// with an empty head, base is compared to the working tree.
+func GetChangedLineRanges(base, head string) (map[string][]LineRange, error) {
+	output, err := diffUnified0(base, head)
+	if err != nil {
+		return nil, err
+	}
+	return ParseChangedLineRanges(output), nil
+}
+
+// Hunk is one --unified=0 hunk: the base lines it replaces and the lines it puts in their place
+// A pure insertion has OldCount 0 and OldStart at the base line it follows.
+type Hunk struct {
+	OldStart int
+	OldCount int
+	NewStart int
+	NewCount int
+}
+
+// Lines returns the hunk's line range in the new version of the file
+// A pure deletion is recorded as the single line where the removed code used to start,
+// so the surrounding block still counts as touched.
+func (h Hunk) Lines() LineRange {
+	if h.NewCount == 0 {
+		return LineRange{Start: max(h.NewStart, 1), End: max(h.NewStart, 1)}
+	}
+	return LineRange{Start: h.NewStart, End: h.NewStart + h.NewCount - 1}
+}
+
+// GetDiffHunks returns the hunks per file between base and head (see GetChangedLineRanges)
+func GetDiffHunks(base, head string) (map[string][]Hunk, error) {
+	output, err := diffUnified0(base, head)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiffHunks(output), nil
+}
+
+// GetStagedDiffHunks returns the hunks per file of staged changes against HEAD: what the
+// next commit records, regardless of unstaged edits
+func GetStagedDiffHunks() (map[string][]Hunk, error) {
+	output, err := unified0("--cached", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return ParseDiffHunks(output), nil
+}
+
+// ParseDiffHunks extracts hunks from a --unified=0 diff, keyed by new path; deleted files are skipped
+func ParseDiffHunks(diff string) map[string][]Hunk {
+	hunks := make(map[string][]Hunk)
+	file := ""
+	var body HunkBody
+
+	for _, line := range strings.Split(diff, "\n") {
+		if body.Consume(line) {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			body.Enter(line)
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				continue
+			}
+			oldStart, oldCount, ok1 := parseHunkRange(fields[1], "-")
+			newStart, newCount, ok2 := parseHunkRange(fields[2], "+")
+			if !ok1 || !ok2 {
+				continue
+			}
+			hunks[file] = append(hunks[file], Hunk{OldStart: oldStart, OldCount: oldCount, NewStart: newStart, NewCount: newCount})
+		}
+	}
+
+	return hunks
+}
+
+// GetWorkingTreeDiff returns the diff of staged and unstaged changes against HEAD, with
+// renames detected
+func GetWorkingTreeDiff() (string, error) {
+	return diffAgainstHead("HEAD")
+}
+
+// GetStagedDiff returns the diff of staged changes against HEAD, with renames detected:
+// what the next commit records, regardless of unstaged edits
+func GetStagedDiff() (string, error) {
+	return diffAgainstHead("--cached", "HEAD")
+}
+
+func diffAgainstHead(args ...string) (string, error) {
+	args = append([]string{"diff", "--no-color", "--no-ext-diff", "-M"}, args...)
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(output), nil
+}
+
+func diffUnified0(base, head string) (string, error) {
+	switch {
+	case base == "":
+		return unified0("HEAD")
+	case head == "":
+		return unified0(base)
+	default:
+		return unified0(base + "..." + head)
+	}
+}
+
+func unified0(args ...string) (string, error) {
+	args = append([]string{"diff", "--unified=0", "--no-color", "--no-ext-diff"}, args...)
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %w", err)
+	}
+	return string(output), nil
+}
+
+// ParseChangedLineRanges extracts new-side line ranges from a --unified=0 diff (see Hunk.Lines)
+// Deleted files are skipped.
+func ParseChangedLineRanges(diff string) map[string][]LineRange {
+	ranges := make(map[string][]LineRange)
+	for file, hunks := range ParseDiffHunks(diff) {
+		for _, h := range hunks {
+			ranges[file] = append(ranges[file], h.Lines())
+		}
+	}
+	return ranges
+}
+
+// parseHunkRange parses a "-start[,count]" or "+start[,count]" hunk header field
+func parseHunkRange(field, sign string) (start, count int, ok bool) {
+	if !strings.HasPrefix(field, sign) {
+		return 0, 0, false
+	}
+
+	startStr, countStr, hasCount := strings.Cut(strings.TrimPrefix(field, sign), ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, false
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, count, true
+}
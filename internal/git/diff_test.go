@@ -200,3 +200,111 @@ index 123..456
 		t.Error("Should not include func4 (after maxHunks)")
 	}
 }
+
+func TestParseChangedLineRanges(t *testing.T) {
+	diff := `diff --git a/auth/login.go b/auth/login.go
+index 1111111..2222222 100644
+--- a/auth/login.go
++++ b/auth/login.go
+@@ -10,2 +10,3 @@ func Login() {
++	validate()
+@@ -40 +41 @@ func Logout() {
+-	old()
++	updated()
+@@ -60,3 +60,0 @@ func Refresh() {
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,5 +0,0 @@
+diff --git a/new.go b/new.go
+new file mode 100644
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,4 @@
+`
+
+	ranges := ParseChangedLineRanges(diff)
+
+	login := ranges["auth/login.go"]
+	want := []LineRange{{10, 12}, {41, 41}, {60, 60}}
+	if len(login) != len(want) {
+		t.Fatalf("expected %d ranges for auth/login.go, got %v", len(want), login)
+	}
+	for i := range want {
+		if login[i] != want[i] {
+			t.Errorf("range %d: expected %v, got %v", i, want[i], login[i])
+		}
+	}
+
+	if _, ok := ranges["old.go"]; ok {
+		t.Error("deleted file should have no ranges")
+	}
+	if got := ranges["new.go"]; len(got) != 1 || got[0] != (LineRange{1, 4}) {
+		t.Errorf("unexpected ranges for new.go: %v", got)
+	}
+}
+
+func TestParseChangedLineRanges_HeaderLookalikes(t *testing.T) {
+	// An added "++" line reads as a "+++ " header inside the hunk
+	diff := `diff --git a/count.c b/count.c
+--- a/count.c
++++ b/count.c
+@@ -4,0 +5,2 @@ int main() {
++++ i;
++return i;
+@@ -9 +11 @@ int main() {
+-	return 0;
++	return i;
+`
+
+	ranges := ParseChangedLineRanges(diff)
+
+	if len(ranges) != 1 {
+		t.Fatalf("expected ranges for count.c only, got %v", ranges)
+	}
+	got := ranges["count.c"]
+	if len(got) != 2 || got[0] != (LineRange{5, 6}) || got[1] != (LineRange{11, 11}) {
+		t.Errorf("unexpected ranges for count.c: %v", got)
+	}
+}
+
+func TestParseDiffHunks(t *testing.T) {
+	// "+++ attempts" is an added line inside the first hunk, not a file header
+	diff := `diff --git a/auth/login.go b/auth/login.go
+--- a/auth/login.go
++++ b/auth/login.go
+@@ -10,0 +11,2 @@ func Login() {
++	validate()
++++ attempts
+@@ -40 +42 @@ func Logout() {
+-	old()
++	updated()
+@@ -60,3 +61,0 @@ func Refresh() {
+diff --git a/old.go b/old.go
+--- a/old.go
++++ /dev/null
+@@ -1,5 +0,0 @@
+`
+
+	hunks := ParseDiffHunks(diff)
+
+	want := []Hunk{
+		{OldStart: 10, OldCount: 0, NewStart: 11, NewCount: 2},
+		{OldStart: 40, OldCount: 1, NewStart: 42, NewCount: 1},
+		{OldStart: 60, OldCount: 3, NewStart: 61, NewCount: 0},
+	}
+	login := hunks["auth/login.go"]
+	if len(login) != len(want) {
+		t.Fatalf("expected %d hunks for auth/login.go, got %v", len(want), login)
+	}
+	for i := range want {
+		if login[i] != want[i] {
+			t.Errorf("hunk %d: expected %+v, got %+v", i, want[i], login[i])
+		}
+	}
+
+	if _, ok := hunks["old.go"]; ok {
+		t.Error("deleted file should have no hunks")
+	}
+}
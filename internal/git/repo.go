@@ -2,7 +2,9 @@ package git
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -70,6 +72,23 @@ func GetChangedFiles() ([]string, error) {
 	return result, nil
 }
 
+// GetTrackedFiles returns all files tracked at HEAD, relative to the repository root
+func GetTrackedFiles() ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "--full-name", ":/")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tracked files: %w", err)
+	}
+
+	var result []string
+	for _, f := range strings.Split(string(output), "\n") {
+		if f != "" {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
 // GetCurrentBranch returns the name of the current git branch
 func GetCurrentBranch() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
@@ -103,6 +122,17 @@ func GetCurrentCommitSHA() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// ResolveCommitSHA returns the full SHA a ref (branch, tag, short SHA) points to
+func ResolveCommitSHA(ref string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--verify", ref+"^{commit}")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit %s: %w", ref, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 // GetAuthorEmail returns the configured git user email
 func GetAuthorEmail() (string, error) {
 	cmd := exec.Command("git", "config", "user.email")
@@ -114,6 +144,17 @@ func GetAuthorEmail() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// GetCommitAuthorEmail returns the author email of a commit
+func GetCommitAuthorEmail(ref string) (string, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%ae", ref)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get author of %s: %w", ref, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 // GetRepoRoot returns the absolute path to the git repository root
 func GetRepoRoot() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
@@ -152,3 +193,35 @@ func GetRepoID() (string, error) {
 
 	return "local/unknown", nil
 }
+
+// ShowFile returns a file's content at a revision of the repository at repoPath
+// An empty rev reads the staged version from the index.
+func ShowFile(repoPath, rev, path string) ([]byte, error) {
+	cmd := exec.Command("git", "-C", repoPath, "show", rev+":"+path)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", path, rev, err)
+	}
+	return output, nil
+}
+
+// ReadChangedFile returns a file's content with the change under check applied: the
+// staged version when staged is set (pre-commit checks), the working tree copy otherwise
+// Returns nil without an error when the change deletes the file.
+func ReadChangedFile(repoPath, path string, staged bool) ([]byte, error) {
+	if staged {
+		content, err := ShowFile(repoPath, "", path)
+		if err != nil {
+			return nil, nil // Not in the index: the staged change deletes it
+		}
+		return content, nil
+	}
+	content, err := os.ReadFile(filepath.Join(repoPath, path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return content, nil
+}
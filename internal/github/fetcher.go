@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"time"
 
 	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/source"
 	"github.com/google/go-github/v57/github"
 	"golang.org/x/time/rate"
 )
@@ -18,11 +20,13 @@ type Fetcher struct {
 	client      *github.Client
 	stagingDB   *database.StagingClient
 	rateLimiter *rate.Limiter
+	calls       *callCounter
 }
 
 // NewFetcher creates a GitHub API fetcher with PostgreSQL staging storage
 func NewFetcher(githubToken string, stagingDB *database.StagingClient) *Fetcher {
-	client := github.NewClient(nil).WithAuthToken(githubToken)
+	calls := &callCounter{base: http.DefaultTransport}
+	client := github.NewClient(&http.Client{Transport: calls}).WithAuthToken(githubToken)
 
 	// GitHub allows 5,000 requests/hour with personal access token
 	// Optimal rate: 1.18 req/sec = 4,248 req/hour (86% utilization)
@@ -33,17 +37,14 @@ func NewFetcher(githubToken string, stagingDB *database.StagingClient) *Fetcher
 		client:      client,
 		stagingDB:   stagingDB,
 		rateLimiter: limiter,
+		calls:       calls,
 	}
 }
 
 // FetchStats tracks fetching statistics
-type FetchStats struct {
-	Commits      int
-	Issues       int
-	PRs          int
-	Branches     int
-	Contributors int
-}
+type FetchStats = source.Stats
+
+var _ source.Fetcher = (*Fetcher)(nil)
 
 // FetchAll fetches all GitHub data for a repository and stores in PostgreSQL
 // This is Priority 6A: GitHub API → PostgreSQL staging
@@ -1055,8 +1056,3 @@ func (f *Fetcher) checkExistingData(ctx context.Context, repoID int64) (*FetchSt
 
 	return stats, nil
 }
-
-// hasData returns true if any data exists
-func (s *FetchStats) hasData() bool {
-	return s.Commits > 0 || s.Issues > 0 || s.PRs > 0 || s.Branches > 0
-}
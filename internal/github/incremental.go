@@ -0,0 +1,348 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// Incremental fetch resources (github_fetch_cursors.resource)
+const (
+	ResourceCommits = "commits"
+	ResourceIssues  = "issues"
+	ResourcePulls   = "pulls"
+)
+
+// IncrementalStats reports what an incremental fetch staged and what it cost
+type IncrementalStats struct {
+	FetchStats        // New or updated entities staged
+	Timelines     int // Issues whose timeline was refreshed
+	APICalls      int // Requests that counted against the rate limit
+	NotModified   int // Conditional requests answered with 304 (free)
+	APICallsSaved int // Estimated requests avoided versus re-pulling the staged window
+}
+
+// callCounter counts requests and 304 responses for API call accounting
+type callCounter struct {
+	base        http.RoundTripper
+	total       atomic.Int64
+	notModified atomic.Int64
+}
+
+func (c *callCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := c.base.RoundTrip(req)
+	c.total.Add(1)
+	if err == nil && resp.StatusCode == http.StatusNotModified {
+		c.notModified.Add(1)
+	}
+	return resp, err
+}
+
+// FetchIncremental fetches only commits, issues and PRs created or updated since the
+// last run, using per-resource high-water marks from github_fetch_cursors (plus an ETag
+// for PRs, the only list requested with the same URL every run).
+// Missing cursors are seeded from already-staged data; days bounds the window only
+// when nothing is staged yet (0 = all history).
+// Timelines are refreshed for updated issues and file lists fetched for newly merged PRs.
+func (f *Fetcher) FetchIncremental(ctx context.Context, owner, repo, repoPath string, days int) (int64, *IncrementalStats, error) {
+	log.Printf("🔍 Incremental GitHub fetch for %s/%s...", owner, repo)
+	stats := &IncrementalStats{}
+	startTotal, startNotModified := f.calls.total.Load(), f.calls.notModified.Load()
+
+	repoID, err := f.FetchRepository(ctx, owner, repo, repoPath)
+	if err != nil {
+		return 0, stats, fmt.Errorf("fetch repository failed: %w", err)
+	}
+
+	var windowStart *time.Time
+	if days > 0 {
+		t := time.Now().AddDate(0, 0, -days).UTC()
+		windowStart = &t
+	}
+
+	commits, err := f.fetchCommitsSince(ctx, repoID, owner, repo, windowStart)
+	if err != nil {
+		return repoID, stats, fmt.Errorf("incremental commit fetch failed: %w", err)
+	}
+	stats.Commits = commits
+	log.Printf("  ✓ %d new commits", commits)
+
+	updatedIssues, err := f.fetchIssuesSince(ctx, repoID, owner, repo, windowStart)
+	if err != nil {
+		return repoID, stats, fmt.Errorf("incremental issue fetch failed: %w", err)
+	}
+	stats.Issues = len(updatedIssues)
+	log.Printf("  ✓ %d new or updated issues", stats.Issues)
+
+	prs, err := f.fetchPullRequestsSince(ctx, repoID, owner, repo, windowStart)
+	if err != nil {
+		return repoID, stats, fmt.Errorf("incremental PR fetch failed: %w", err)
+	}
+	stats.PRs = prs
+	log.Printf("  ✓ %d new or updated PRs", prs)
+
+	if len(updatedIssues) > 0 {
+		issueIDs, err := f.stagingDB.GetIssueIDsByNumber(ctx, repoID)
+		if err != nil {
+			return repoID, stats, err
+		}
+		for _, number := range updatedIssues {
+			issueID, ok := issueIDs[number]
+			if !ok {
+				continue
+			}
+			if err := f.fetchIssueTimeline(ctx, issueID, owner, repo, number); err != nil {
+				log.Printf("  ⚠️  Failed to refresh timeline for issue #%d: %v", number, err)
+				continue
+			}
+			stats.Timelines++
+		}
+	}
+
+	if prs > 0 {
+		if _, err := f.FetchPRFiles(ctx, repoID, owner, repo, days); err != nil {
+			log.Printf("  ⚠️  PR file fetch failed: %v", err)
+		}
+	}
+
+	notModified := int(f.calls.notModified.Load() - startNotModified)
+	stats.NotModified = notModified
+	stats.APICalls = int(f.calls.total.Load()-startTotal) - notModified
+
+	counts, err := f.stagingDB.GetDataCounts(ctx, repoID)
+	if err != nil {
+		log.Printf("  ⚠️  Could not estimate API calls saved: %v", err)
+	} else {
+		stats.APICallsSaved = estimateCallsSaved(counts, stats.APICalls)
+	}
+
+	return repoID, stats, nil
+}
+
+// estimateCallsSaved compares the calls made with what re-pulling everything staged
+// would cost: list pages for commits, issues (which include PRs) and PRs, plus one
+// detail request per commit and one timeline request per issue.
+func estimateCallsSaved(counts *database.DataCounts, made int) int {
+	pages := func(n int) int { return (n + 99) / 100 }
+
+	full := 1 + // repository
+		pages(counts.Commits) + counts.Commits +
+		pages(counts.Issues+counts.PRs) + counts.Issues +
+		pages(counts.PRs)
+
+	if saved := full - made; saved > 0 {
+		return saved
+	}
+	return 0
+}
+
+// fetchCommitsSince stages commits newer than the commits cursor
+// GitHub filters by commit date, so commits pushed later with an older date are not seen
+func (f *Fetcher) fetchCommitsSince(ctx context.Context, repoID int64, owner, repo string, windowStart *time.Time) (int, error) {
+	cursor, err := f.loadCursor(ctx, repoID, ResourceCommits, windowStart)
+	if err != nil {
+		return 0, err
+	}
+
+	query := url.Values{"per_page": {"100"}}
+	if cursor.HighWaterMark != nil {
+		// since is inclusive; skip the newest commit already staged
+		query.Set("since", cursor.HighWaterMark.Add(time.Second).UTC().Format(time.RFC3339))
+	}
+
+	// No ETag: it belongs to the previous run's URL, and since moves with every run
+	count := 0
+	mark := cursor.HighWaterMark
+	_, err = f.listConditional(ctx, fmt.Sprintf("repos/%s/%s/commits", owner, repo), query, "",
+		func() interface{} { return &[]*github.RepositoryCommit{} },
+		func(page interface{}) bool {
+			for _, commit := range *page.(*[]*github.RepositoryCommit) {
+				if err := f.fetchFullCommit(ctx, repoID, owner, repo, commit.GetSHA()); err != nil {
+					log.Printf("  ⚠️  Failed to fetch commit %s: %v", commit.GetSHA(), err)
+					continue
+				}
+				count++
+				mark = laterOf(mark, commit.GetCommit().GetCommitter().GetDate().Time)
+			}
+			return true
+		})
+	if err != nil {
+		return count, err
+	}
+
+	return count, f.saveCursor(ctx, repoID, ResourceCommits, "", mark)
+}
+
+// fetchIssuesSince stages issues updated since the issues cursor
+// Returns the numbers of the issues staged
+func (f *Fetcher) fetchIssuesSince(ctx context.Context, repoID int64, owner, repo string, windowStart *time.Time) ([]int, error) {
+	cursor, err := f.loadCursor(ctx, repoID, ResourceIssues, windowStart)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"state":     {"all"},
+		"sort":      {"updated"},
+		"direction": {"asc"},
+		"per_page":  {"100"},
+	}
+	if cursor.HighWaterMark != nil {
+		query.Set("since", cursor.HighWaterMark.UTC().Format(time.RFC3339))
+	}
+
+	// No ETag, as for commits: since changes the URL every run
+	var numbers []int
+	mark := cursor.HighWaterMark
+	_, err = f.listConditional(ctx, fmt.Sprintf("repos/%s/%s/issues", owner, repo), query, "",
+		func() interface{} { return &[]*github.Issue{} },
+		func(page interface{}) bool {
+			for _, issue := range *page.(*[]*github.Issue) {
+				mark = laterOf(mark, issue.GetUpdatedAt().Time)
+				if issue.IsPullRequest() {
+					continue
+				}
+				if err := f.storeIssue(ctx, repoID, issue); err != nil {
+					log.Printf("  ⚠️  Failed to store issue #%d: %v", issue.GetNumber(), err)
+					continue
+				}
+				numbers = append(numbers, issue.GetNumber())
+			}
+			return true
+		})
+	if err != nil {
+		return numbers, err
+	}
+
+	return numbers, f.saveCursor(ctx, repoID, ResourceIssues, "", mark)
+}
+
+// fetchPullRequestsSince stages PRs updated since the pulls cursor
+// The pulls endpoint has no since parameter; results are sorted by most recently
+// updated and paging stops at the first PR older than the cursor.
+func (f *Fetcher) fetchPullRequestsSince(ctx context.Context, repoID int64, owner, repo string, windowStart *time.Time) (int, error) {
+	cursor, err := f.loadCursor(ctx, repoID, ResourcePulls, windowStart)
+	if err != nil {
+		return 0, err
+	}
+
+	query := url.Values{
+		"state":     {"all"},
+		"sort":      {"updated"},
+		"direction": {"desc"},
+		"per_page":  {"100"},
+	}
+
+	count := 0
+	mark := cursor.HighWaterMark
+	etag, err := f.listConditional(ctx, fmt.Sprintf("repos/%s/%s/pulls", owner, repo), query, cursor.ETag,
+		func() interface{} { return &[]*github.PullRequest{} },
+		func(page interface{}) bool {
+			for _, pr := range *page.(*[]*github.PullRequest) {
+				updated := pr.GetUpdatedAt().Time
+				if cursor.HighWaterMark != nil && updated.Before(*cursor.HighWaterMark) {
+					return false
+				}
+				if err := f.storePR(ctx, repoID, pr); err != nil {
+					log.Printf("  ⚠️  Failed to store PR #%d: %v", pr.GetNumber(), err)
+					continue
+				}
+				count++
+				mark = laterOf(mark, updated)
+			}
+			return true
+		})
+	if err != nil {
+		return count, err
+	}
+
+	return count, f.saveCursor(ctx, repoID, ResourcePulls, etag, mark)
+}
+
+// listConditional pages through a list endpoint, sending If-None-Match on the first page
+// newPage allocates the decode target; handle returns false to stop paging early.
+// Returns the first page's ETag (the previous one when the server answered 304).
+func (f *Fetcher) listConditional(ctx context.Context, path string, query url.Values, etag string, newPage func() interface{}, handle func(page interface{}) bool) (string, error) {
+	page := 1
+	for {
+		if err := f.rateLimiter.Wait(ctx); err != nil {
+			return etag, err
+		}
+
+		if page > 1 {
+			query.Set("page", fmt.Sprint(page))
+		}
+		req, err := f.client.NewRequest(http.MethodGet, path+"?"+query.Encode(), nil)
+		if err != nil {
+			return etag, err
+		}
+		if page == 1 && etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		v := newPage()
+		resp, err := f.client.Do(ctx, req, v)
+		if resp != nil && resp.StatusCode == http.StatusNotModified {
+			log.Printf("  ℹ️  %s not modified since last fetch", path)
+			return etag, nil
+		}
+		if err != nil {
+			return etag, fmt.Errorf("GET %s failed: %w", path, err)
+		}
+		f.logRateLimit(resp)
+
+		if page == 1 {
+			etag = resp.Header.Get("ETag")
+		}
+		if !handle(v) || resp.NextPage == 0 {
+			return etag, nil
+		}
+		page = resp.NextPage
+	}
+}
+
+// loadCursor returns the stored cursor, seeding the high-water mark from staged data
+// (or the days window) when no cursor exists yet
+func (f *Fetcher) loadCursor(ctx context.Context, repoID int64, resource string, windowStart *time.Time) (*database.FetchCursor, error) {
+	cursor, err := f.stagingDB.GetFetchCursor(ctx, repoID, resource)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil {
+		return cursor, nil
+	}
+
+	mark, err := f.stagingDB.GetStagedHighWaterMark(ctx, repoID, resource)
+	if err != nil {
+		return nil, err
+	}
+	if mark == nil {
+		mark = windowStart
+	}
+	return &database.FetchCursor{RepoID: repoID, Resource: resource, HighWaterMark: mark}, nil
+}
+
+func (f *Fetcher) saveCursor(ctx context.Context, repoID int64, resource, etag string, mark *time.Time) error {
+	return f.stagingDB.SaveFetchCursor(ctx, database.FetchCursor{
+		RepoID:        repoID,
+		Resource:      resource,
+		ETag:          etag,
+		HighWaterMark: mark,
+	})
+}
+
+// laterOf returns the later of mark and t, ignoring zero times
+func laterOf(mark *time.Time, t time.Time) *time.Time {
+	if t.IsZero() || (mark != nil && !t.After(*mark)) {
+		return mark
+	}
+	t = t.UTC()
+	return &t
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"golang.org/x/time/rate"
+)
+
+func newTestFetcher(t *testing.T, handler http.HandlerFunc) *Fetcher {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	calls := &callCounter{base: http.DefaultTransport}
+	client := github.NewClient(&http.Client{Transport: calls})
+	base, _ := url.Parse(server.URL + "/")
+	client.BaseURL = base
+
+	return &Fetcher{client: client, rateLimiter: rate.NewLimiter(rate.Inf, 1), calls: calls}
+}
+
+func TestListConditional_NotModified(t *testing.T) {
+	f := newTestFetcher(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`[{"number": 1}, {"number": 2}]`))
+	})
+
+	var seen int
+	list := func(etag string) string {
+		t.Helper()
+		newETag, err := f.listConditional(context.Background(), "repos/o/r/issues", url.Values{}, etag,
+			func() interface{} { return &[]*github.Issue{} },
+			func(page interface{}) bool {
+				seen += len(*page.(*[]*github.Issue))
+				return true
+			})
+		if err != nil {
+			t.Fatalf("listConditional: %v", err)
+		}
+		return newETag
+	}
+
+	etag := list("")
+	if etag != `"v1"` || seen != 2 {
+		t.Fatalf("first fetch: etag=%q seen=%d", etag, seen)
+	}
+
+	if etag = list(etag); etag != `"v1"` || seen != 2 {
+		t.Errorf("conditional fetch should keep etag and skip handler: etag=%q seen=%d", etag, seen)
+	}
+	if got := f.calls.notModified.Load(); got != 1 {
+		t.Errorf("expected 1 not-modified response, got %d", got)
+	}
+}
+
+func TestListConditional_StopsEarly(t *testing.T) {
+	pages := 0
+	var server string
+	f := newTestFetcher(t, func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		w.Header().Set("Link", `<`+server+`/repos/o/r/pulls?page=2>; rel="next"`)
+		w.Write([]byte(`[{"number": 1}]`))
+	})
+	server = f.client.BaseURL.String()
+
+	_, err := f.listConditional(context.Background(), "repos/o/r/pulls", url.Values{}, "",
+		func() interface{} { return &[]*github.PullRequest{} },
+		func(page interface{}) bool { return false })
+	if err != nil {
+		t.Fatalf("listConditional: %v", err)
+	}
+	if pages != 1 {
+		t.Errorf("expected paging to stop after 1 page, got %d", pages)
+	}
+}
+
+func TestEstimateCallsSaved(t *testing.T) {
+	counts := &database.DataCounts{Commits: 250, Issues: 40, PRs: 80}
+	// 1 + (3 + 250) + (2 + 40) + 1 = 297
+	if got := estimateCallsSaved(counts, 7); got != 290 {
+		t.Errorf("expected 290 calls saved, got %d", got)
+	}
+	if got := estimateCallsSaved(&database.DataCounts{}, 5); got != 0 {
+		t.Errorf("expected no savings on an empty repo, got %d", got)
+	}
+}
+
+func TestLaterOf(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
+	if got := laterOf(nil, early); got == nil || !got.Equal(early) {
+		t.Errorf("expected %v, got %v", early, got)
+	}
+	if got := laterOf(&late, early); !got.Equal(late) {
+		t.Errorf("expected mark to be kept, got %v", got)
+	}
+	if got := laterOf(&early, time.Time{}); !got.Equal(early) {
+		t.Errorf("zero time should be ignored, got %v", got)
+	}
+}
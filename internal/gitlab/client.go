@@ -0,0 +1,123 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultBaseURL is used when no self-hosted instance is configured
+const DefaultBaseURL = "https://gitlab.com"
+
+// Client is a minimal GitLab REST API v4 client
+// Only the read endpoints needed for staging are implemented.
+type Client struct {
+	baseURL     string
+	token       string
+	httpClient  *http.Client
+	rateLimiter *rate.Limiter
+}
+
+// NewClient creates a GitLab API client for baseURL (e.g. https://gitlab.com)
+// token is a personal or project access token with read_api scope
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	// GitLab.com allows 2,000 authenticated API requests/minute
+	// 10 req/sec keeps well under the limit and leaves room for self-hosted defaults
+	limiter := rate.NewLimiter(rate.Every(100*time.Millisecond), 1)
+
+	return &Client{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		token:       token,
+		httpClient:  &http.Client{Timeout: 60 * time.Second},
+		rateLimiter: limiter,
+	}
+}
+
+// projectPath builds the API path for a project resource
+// GitLab accepts the URL-encoded full path ("group/subgroup/project") in place of the numeric ID
+func projectPath(fullPath string, parts ...string) string {
+	p := "projects/" + url.PathEscape(fullPath)
+	for _, part := range parts {
+		p += "/" + part
+	}
+	return p
+}
+
+// get performs a GET request and decodes the JSON body into out
+// Returns the next page number from the X-Next-Page header (0 when there are no more pages)
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) (int, error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return 0, err
+	}
+
+	endpoint := c.baseURL + "/api/v4/" + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("GitLab API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return 0, fmt.Errorf("GitLab API error: GET %s: %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return 0, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	next, _ := strconv.Atoi(resp.Header.Get("X-Next-Page"))
+	return next, nil
+}
+
+// getPages walks every page of a list endpoint, calling fn with the raw items of each page
+// Items are passed undecoded so callers can keep the original payload for raw_data
+func (c *Client) getPages(ctx context.Context, path string, query url.Values, fn func([]json.RawMessage) error) error {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("per_page", "100")
+
+	page := 1
+	for page > 0 {
+		q.Set("page", strconv.Itoa(page))
+
+		var items []json.RawMessage
+		next, err := c.get(ctx, path, q, &items)
+		if err != nil {
+			return err
+		}
+		if err := fn(items); err != nil {
+			return err
+		}
+		page = next
+	}
+
+	return nil
+}
@@ -0,0 +1,792 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/source"
+)
+
+// Fetcher handles GitLab API data fetching and storage in PostgreSQL staging tables
+// GitLab payloads are normalised to the GitHub shapes stored in raw_data
+// (files[]/stats on commits, user/head/base on pull requests, default_branch on the repository)
+// so linking, graph construction and CLQS run unchanged on GitLab projects.
+// Merge requests are staged as pull requests; MR and issue numbers use their project IIDs.
+type Fetcher struct {
+	client *Client
+	store  source.Store
+
+	// mergeRequests caches MRs staged in this run so cross-reference events can carry the
+	// source title/body the linker uses to classify references
+	mergeRequests map[int]*apiMergeRequest
+}
+
+var _ source.Fetcher = (*Fetcher)(nil)
+
+// NewFetcher creates a GitLab API fetcher with PostgreSQL staging storage
+// baseURL is the instance root (https://gitlab.com or a self-hosted URL)
+func NewFetcher(baseURL, token string, store source.Store) *Fetcher {
+	return &Fetcher{
+		client:        NewClient(baseURL, token),
+		store:         store,
+		mergeRequests: make(map[int]*apiMergeRequest),
+	}
+}
+
+// FetchAll fetches all GitLab data for a project and stores it in PostgreSQL
+// owner is the project namespace and may contain subgroups ("group/subgroup")
+// Smart checkpointing: skips fetching if data already exists
+// days: number of days to fetch (0 = all history)
+func (f *Fetcher) FetchAll(ctx context.Context, owner, repo, repoPath string, days int) (int64, *source.Stats, error) {
+	fullPath := owner + "/" + repo
+	log.Printf("🔍 Fetching GitLab data for %s...", fullPath)
+	stats := &source.Stats{}
+
+	// 1. Fetch project metadata (always needed to get repoID)
+	repoID, err := f.FetchRepository(ctx, owner, repo, repoPath)
+	if err != nil {
+		return 0, stats, fmt.Errorf("fetch repository failed: %w", err)
+	}
+	log.Printf("  ✓ Repository ID: %d", repoID)
+
+	existing := &source.Stats{}
+	if counts, err := f.store.GetDataCounts(ctx, repoID); err != nil {
+		log.Printf("  ⚠️  Could not check existing data: %v", err)
+	} else {
+		existing = &source.Stats{
+			Commits:      counts.Commits,
+			Issues:       counts.Issues,
+			PRs:          counts.PRs,
+			Branches:     counts.Branches,
+			Contributors: counts.Contributors,
+		}
+	}
+
+	// 2. Fetch commits - only if missing
+	if existing.Commits > 0 {
+		log.Printf("  ℹ️  Commits already exist (%d), skipping fetch", existing.Commits)
+		stats.Commits = existing.Commits
+	} else {
+		commitCount, err := f.FetchCommits(ctx, repoID, fullPath, days)
+		if err != nil {
+			return repoID, stats, fmt.Errorf("fetch commits failed: %w", err)
+		}
+		stats.Commits = commitCount
+		log.Printf("  ✓ Fetched %d commits", commitCount)
+	}
+
+	// 3. Fetch merge requests before issues so note cross-references can be enriched
+	if existing.PRs > 0 {
+		log.Printf("  ℹ️  Merge requests already exist (%d), skipping fetch", existing.PRs)
+		stats.PRs = existing.PRs
+	} else {
+		mrCount, err := f.FetchMergeRequests(ctx, repoID, fullPath, days)
+		if err != nil {
+			return repoID, stats, fmt.Errorf("fetch merge requests failed: %w", err)
+		}
+		stats.PRs = mrCount
+		log.Printf("  ✓ Fetched %d merge requests", mrCount)
+	}
+
+	// 4. Fetch issues - only if missing
+	var fetchedIssues []int
+	if existing.Issues > 0 {
+		log.Printf("  ℹ️  Issues already exist (%d), skipping fetch", existing.Issues)
+		stats.Issues = existing.Issues
+	} else {
+		fetchedIssues, err = f.FetchIssues(ctx, repoID, fullPath, days)
+		if err != nil {
+			return repoID, stats, fmt.Errorf("fetch issues failed: %w", err)
+		}
+		stats.Issues = len(fetchedIssues)
+		log.Printf("  ✓ Fetched %d issues", len(fetchedIssues))
+	}
+
+	// 5. Fetch branches - only if missing
+	if existing.Branches > 0 {
+		log.Printf("  ℹ️  Branches already exist (%d), skipping fetch", existing.Branches)
+		stats.Branches = existing.Branches
+	} else {
+		branchCount, err := f.FetchBranches(ctx, repoID, fullPath)
+		if err != nil {
+			return repoID, stats, fmt.Errorf("fetch branches failed: %w", err)
+		}
+		stats.Branches = branchCount
+		log.Printf("  ✓ Fetched %d branches", branchCount)
+	}
+
+	// 6. Fetch languages
+	if err := f.FetchLanguages(ctx, repoID, fullPath); err != nil {
+		log.Printf("  ⚠️  Failed to fetch languages: %v", err)
+	}
+
+	// 7. Fetch contributors
+	contributorCount, err := f.FetchContributors(ctx, repoID, fullPath)
+	if err != nil {
+		log.Printf("  ⚠️  Failed to fetch contributors: %v", err)
+	} else {
+		stats.Contributors = contributorCount
+		log.Printf("  ✓ Fetched %d contributors", contributorCount)
+	}
+
+	// 8. Fetch issue notes (system notes → timeline events, user notes → comments)
+	if len(fetchedIssues) > 0 {
+		eventCount, commentCount, err := f.FetchIssueNotes(ctx, repoID, fullPath, fetchedIssues)
+		if err != nil {
+			log.Printf("  ⚠️  Failed to fetch issue notes: %v", err)
+		} else {
+			log.Printf("  ✓ Fetched %d timeline events and %d comments from issue notes", eventCount, commentCount)
+		}
+	}
+
+	// 9. Fetch merge request file changes (for temporal-semantic linking)
+	mrFileCount, err := f.FetchMergeRequestFiles(ctx, repoID, fullPath, days)
+	if err != nil {
+		log.Printf("  ⚠️  Failed to fetch merge request files: %v", err)
+	} else if mrFileCount > 0 {
+		log.Printf("  ✓ Fetched %d merge request file changes", mrFileCount)
+	}
+
+	return repoID, stats, nil
+}
+
+// FetchRepository fetches project metadata and stores it in PostgreSQL
+func (f *Fetcher) FetchRepository(ctx context.Context, owner, repo, repoPath string) (int64, error) {
+	var project apiProject
+	if _, err := f.client.get(ctx, projectPath(owner+"/"+repo), nil, &project); err != nil {
+		return 0, err
+	}
+
+	rawData, err := json.Marshal(map[string]interface{}{
+		"id":               project.ID,
+		"name":             project.Path,
+		"full_name":        project.PathWithNamespace,
+		"description":      project.Description,
+		"default_branch":   project.DefaultBranch,
+		"html_url":         project.WebURL,
+		"private":          project.Visibility != "public",
+		"stargazers_count": project.StarCount,
+		"forks_count":      project.ForksCount,
+		"created_at":       project.CreatedAt,
+		"pushed_at":        project.LastActivityAt,
+		"source":           string(source.ProviderGitLab),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal repository: %w", err)
+	}
+
+	fullName := project.PathWithNamespace
+	if fullName == "" {
+		fullName = owner + "/" + repo
+	}
+
+	// github_id is unique across providers, so the GitLab project ID (kept in raw_data) is
+	// mapped to a synthetic one that cannot collide with a GitHub repository
+	githubID := source.SyntheticID(fmt.Sprintf("gitlab:%s/projects/%d", f.client.baseURL, project.ID))
+	return f.store.StoreRepository(ctx, githubID, owner, repo, fullName, repoPath, rawData)
+}
+
+// FetchCommits fetches commits on the default branch from the specified time window
+// days: number of days to fetch (0 = all history)
+func (f *Fetcher) FetchCommits(ctx context.Context, repoID int64, fullPath string, days int) (int, error) {
+	query := url.Values{}
+	query.Set("with_stats", "true")
+	if days > 0 {
+		query.Set("since", time.Now().AddDate(0, 0, -days).Format(time.RFC3339))
+	}
+
+	count := 0
+	err := f.client.getPages(ctx, projectPath(fullPath, "repository", "commits"), query, func(items []json.RawMessage) error {
+		for _, item := range items {
+			var commit apiCommit
+			if err := json.Unmarshal(item, &commit); err != nil {
+				log.Printf("  ⚠️  Failed to decode commit: %v", err)
+				continue
+			}
+			if err := f.storeCommit(ctx, repoID, fullPath, &commit); err != nil {
+				log.Printf("  ⚠️  Failed to fetch commit %s: %v", commit.ID, err)
+				continue
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// storeCommit fetches the commit's file list and stores it in the GitHub commit shape
+func (f *Fetcher) storeCommit(ctx context.Context, repoID int64, fullPath string, commit *apiCommit) error {
+	var diffs []apiDiff
+	err := f.client.getPages(ctx, projectPath(fullPath, "repository", "commits", commit.ID, "diff"), nil, func(items []json.RawMessage) error {
+		for _, item := range items {
+			var d apiDiff
+			if err := json.Unmarshal(item, &d); err != nil {
+				return fmt.Errorf("failed to decode diff: %w", err)
+			}
+			diffs = append(diffs, d)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("get commit diff failed: %w", err)
+	}
+
+	files := make([]map[string]interface{}, 0, len(diffs))
+	for _, d := range diffs {
+		additions, deletions := countDiffLines(d.Diff)
+		file := map[string]interface{}{
+			"filename":  d.NewPath,
+			"status":    diffStatus(d),
+			"additions": additions,
+			"deletions": deletions,
+			"changes":   additions + deletions,
+			"patch":     d.Diff,
+		}
+		if d.RenamedFile {
+			file["previous_filename"] = d.OldPath
+		}
+		files = append(files, file)
+	}
+
+	parents := make([]map[string]string, 0, len(commit.ParentIDs))
+	for _, p := range commit.ParentIDs {
+		parents = append(parents, map[string]string{"sha": p})
+	}
+
+	rawData, err := json.Marshal(map[string]interface{}{
+		"sha":      commit.ID,
+		"html_url": commit.WebURL,
+		"commit": map[string]interface{}{
+			"message": commit.Message,
+			"author": map[string]interface{}{
+				"name":  commit.AuthorName,
+				"email": commit.AuthorEmail,
+				"date":  commit.AuthoredDate,
+			},
+			"committer": map[string]interface{}{
+				"name":  commit.CommitterName,
+				"email": commit.CommitterEmail,
+				"date":  commit.CommittedDate,
+			},
+		},
+		"parents": parents,
+		"stats": map[string]int{
+			"additions": commit.Stats.Additions,
+			"deletions": commit.Stats.Deletions,
+			"total":     commit.Stats.Total,
+		},
+		"files": files,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal commit: %w", err)
+	}
+
+	return f.store.StoreCommit(
+		ctx,
+		repoID,
+		commit.ID,
+		commit.AuthorName,
+		commit.AuthorEmail,
+		commit.AuthoredDate,
+		commit.Message,
+		commit.Stats.Additions,
+		commit.Stats.Deletions,
+		commit.Stats.Total,
+		len(diffs),
+		rawData,
+	)
+}
+
+// FetchIssues fetches issues with time filtering
+// Returns the IIDs of the issues stored so their notes can be fetched
+// days: number of days to fetch (0 = all history)
+func (f *Fetcher) FetchIssues(ctx context.Context, repoID int64, fullPath string, days int) ([]int, error) {
+	query := url.Values{}
+	query.Set("scope", "all")
+	query.Set("state", "all")
+
+	var cutoff time.Time
+	if days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -days)
+		query.Set("updated_after", cutoff.Format(time.RFC3339))
+	}
+
+	var stored []int
+	err := f.client.getPages(ctx, projectPath(fullPath, "issues"), query, func(items []json.RawMessage) error {
+		for _, item := range items {
+			var issue apiIssue
+			if err := json.Unmarshal(item, &issue); err != nil {
+				log.Printf("  ⚠️  Failed to decode issue: %v", err)
+				continue
+			}
+
+			// Apply time filter: open OR closed within specified days (same as GitHub)
+			if days > 0 && issue.State == "closed" && (issue.ClosedAt == nil || issue.ClosedAt.Before(cutoff)) {
+				continue
+			}
+
+			if err := f.storeIssue(ctx, repoID, &issue); err != nil {
+				log.Printf("  ⚠️  Failed to store issue #%d: %v", issue.IID, err)
+				continue
+			}
+			stored = append(stored, issue.IID)
+		}
+		return nil
+	})
+
+	return stored, err
+}
+
+// storeIssue stores a single issue in PostgreSQL
+func (f *Fetcher) storeIssue(ctx context.Context, repoID int64, issue *apiIssue) error {
+	state := normalizeState(issue.State)
+	labels := labelsOrEmpty(issue.Labels)
+	labelsJSON, _ := json.Marshal(labels)
+
+	rawData, err := json.Marshal(map[string]interface{}{
+		"id":         issue.ID,
+		"number":     issue.IID,
+		"title":      issue.Title,
+		"body":       issue.Description,
+		"state":      state,
+		"user":       githubUser(issue.Author),
+		"labels":     labelObjects(labels),
+		"created_at": issue.CreatedAt,
+		"updated_at": issue.UpdatedAt,
+		"closed_at":  issue.ClosedAt,
+		"html_url":   issue.WebURL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue: %w", err)
+	}
+
+	return f.store.StoreIssue(
+		ctx,
+		repoID,
+		issue.ID,
+		issue.IID,
+		issue.Title,
+		issue.Description,
+		state,
+		issue.Author.Username,
+		issue.Author.ID,
+		labelsJSON,
+		issue.CreatedAt,
+		issue.ClosedAt,
+		rawData,
+	)
+}
+
+// FetchMergeRequests fetches merge requests with time filtering and stages them as pull requests
+// days: number of days to fetch (0 = all history)
+func (f *Fetcher) FetchMergeRequests(ctx context.Context, repoID int64, fullPath string, days int) (int, error) {
+	query := url.Values{}
+	query.Set("scope", "all")
+	query.Set("state", "all")
+
+	var cutoff time.Time
+	if days > 0 {
+		cutoff = time.Now().AddDate(0, 0, -days)
+		query.Set("updated_after", cutoff.Format(time.RFC3339))
+	}
+
+	count := 0
+	err := f.client.getPages(ctx, projectPath(fullPath, "merge_requests"), query, func(items []json.RawMessage) error {
+		for _, item := range items {
+			mr := &apiMergeRequest{}
+			if err := json.Unmarshal(item, mr); err != nil {
+				log.Printf("  ⚠️  Failed to decode merge request: %v", err)
+				continue
+			}
+
+			// Apply time filter: open OR merged/closed within specified days (same as GitHub)
+			if days > 0 && mr.State != "opened" && mr.State != "locked" {
+				recent := (mr.MergedAt != nil && mr.MergedAt.After(cutoff)) ||
+					(mr.ClosedAt != nil && mr.ClosedAt.After(cutoff))
+				if !recent {
+					continue
+				}
+			}
+
+			if err := f.storeMergeRequest(ctx, repoID, mr); err != nil {
+				log.Printf("  ⚠️  Failed to store merge request !%d: %v", mr.IID, err)
+				continue
+			}
+			f.mergeRequests[mr.IID] = mr
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// storeMergeRequest stores a merge request in the pull request table
+func (f *Fetcher) storeMergeRequest(ctx context.Context, repoID int64, mr *apiMergeRequest) error {
+	merged := mr.State == "merged"
+	state := normalizeState(mr.State)
+	labels := labelsOrEmpty(mr.Labels)
+	labelsJSON, _ := json.Marshal(labels)
+
+	// Squash and fast-forward merges have no merge commit; the landed commit is the
+	// squash commit or the source branch head respectively
+	var mergeCommitSHA *string
+	if merged {
+		switch {
+		case mr.MergeCommitSHA != nil && *mr.MergeCommitSHA != "":
+			mergeCommitSHA = mr.MergeCommitSHA
+		case mr.SquashCommitSHA != nil && *mr.SquashCommitSHA != "":
+			mergeCommitSHA = mr.SquashCommitSHA
+		case mr.SHA != "":
+			sha := mr.SHA
+			mergeCommitSHA = &sha
+		}
+	}
+
+	closedAt := mr.ClosedAt
+	if closedAt == nil && merged {
+		closedAt = mr.MergedAt
+	}
+
+	rawData, err := json.Marshal(map[string]interface{}{
+		"id":               mr.ID,
+		"number":           mr.IID,
+		"title":            mr.Title,
+		"body":             mr.Description,
+		"state":            state,
+		"user":             githubUser(mr.Author),
+		"labels":           labelObjects(labels),
+		"head":             map[string]string{"ref": mr.SourceBranch, "sha": mr.SHA},
+		"base":             map[string]string{"ref": mr.TargetBranch, "sha": mr.DiffRefs.BaseSHA},
+		"merged":           merged,
+		"merged_at":        mr.MergedAt,
+		"merge_commit_sha": mergeCommitSHA,
+		"created_at":       mr.CreatedAt,
+		"updated_at":       mr.UpdatedAt,
+		"closed_at":        closedAt,
+		"html_url":         mr.WebURL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal merge request: %w", err)
+	}
+
+	return f.store.StorePullRequest(
+		ctx,
+		repoID,
+		mr.ID,
+		mr.IID,
+		mr.Title,
+		mr.Description,
+		state,
+		mr.Author.Username,
+		mr.Author.ID,
+		mr.SourceBranch,
+		mr.SHA,
+		mr.TargetBranch,
+		mr.DiffRefs.BaseSHA,
+		merged,
+		mr.MergedAt,
+		mergeCommitSHA,
+		labelsJSON,
+		mr.CreatedAt,
+		closedAt,
+		rawData,
+	)
+}
+
+// FetchBranches fetches all branches
+func (f *Fetcher) FetchBranches(ctx context.Context, repoID int64, fullPath string) (int, error) {
+	count := 0
+	err := f.client.getPages(ctx, projectPath(fullPath, "repository", "branches"), nil, func(items []json.RawMessage) error {
+		for _, item := range items {
+			var branch apiBranch
+			if err := json.Unmarshal(item, &branch); err != nil {
+				continue
+			}
+			if err := f.store.StoreBranch(ctx, repoID, branch.Name, branch.Commit.ID, branch.Protected, item); err != nil {
+				log.Printf("  ⚠️  Failed to store branch %s: %v", branch.Name, err)
+				continue
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// FetchLanguages fetches the project language breakdown
+// GitLab reports percentages rather than byte counts; they are stored scaled to
+// hundredths so relative weights match what consumers of the GitHub data expect
+func (f *Fetcher) FetchLanguages(ctx context.Context, repoID int64, fullPath string) error {
+	var percentages map[string]float64
+	if _, err := f.client.get(ctx, projectPath(fullPath, "languages"), nil, &percentages); err != nil {
+		return err
+	}
+
+	languages := make(map[string]int64, len(percentages))
+	for lang, pct := range percentages {
+		languages[lang] = int64(pct * 100)
+	}
+
+	languagesJSON, err := json.Marshal(languages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal languages: %w", err)
+	}
+
+	return f.store.StoreLanguages(ctx, repoID, languagesJSON)
+}
+
+// FetchContributors fetches repository contributors
+// GitLab contributors are keyed by commit author name and email, not account;
+// the email is used as the login so it lines up with commit authors
+func (f *Fetcher) FetchContributors(ctx context.Context, repoID int64, fullPath string) (int, error) {
+	count := 0
+	err := f.client.getPages(ctx, projectPath(fullPath, "repository", "contributors"), nil, func(items []json.RawMessage) error {
+		for _, item := range items {
+			var contributor apiContributor
+			if err := json.Unmarshal(item, &contributor); err != nil {
+				continue
+			}
+
+			login := contributor.Email
+			if login == "" {
+				login = contributor.Name
+			}
+			if err := f.store.StoreContributor(ctx, repoID, source.SyntheticID(login), login, contributor.Commits, item); err != nil {
+				log.Printf("  ⚠️  Failed to store contributor %s: %v", login, err)
+				continue
+			}
+			count++
+		}
+		return nil
+	})
+
+	return count, err
+}
+
+// FetchIssueNotes fetches notes for the given issues
+// System notes become timeline events (closed/referenced/cross-referenced);
+// user notes are stored as issue comments.
+func (f *Fetcher) FetchIssueNotes(ctx context.Context, repoID int64, fullPath string, issueIIDs []int) (int, int, error) {
+	issueIDs, err := f.store.GetIssueIDsByNumber(ctx, repoID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	query := url.Values{}
+	query.Set("sort", "asc")
+	query.Set("order_by", "created_at")
+
+	events, comments := 0, 0
+	for _, iid := range issueIIDs {
+		issueID, ok := issueIDs[iid]
+		if !ok {
+			continue
+		}
+
+		path := projectPath(fullPath, "issues", strconv.Itoa(iid), "notes")
+		err := f.client.getPages(ctx, path, query, func(items []json.RawMessage) error {
+			for _, item := range items {
+				var note apiNote
+				if err := json.Unmarshal(item, &note); err != nil {
+					continue
+				}
+
+				if note.System {
+					stored, err := f.storeSystemNote(ctx, issueID, &note, item)
+					if err != nil {
+						log.Printf("  ⚠️  Failed to store timeline event for issue #%d: %v", iid, err)
+					}
+					events += stored
+					continue
+				}
+
+				if err := f.store.StoreIssueComment(
+					ctx,
+					repoID,
+					issueID,
+					note.ID,
+					note.Body,
+					note.Author.Username,
+					note.Author.ID,
+					"NONE",
+					note.CreatedAt,
+					&note.UpdatedAt,
+					item,
+				); err != nil {
+					log.Printf("  ⚠️  Failed to store comment on issue #%d: %v", iid, err)
+					continue
+				}
+				comments++
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("  ⚠️  Failed to fetch notes for issue #%d: %v", iid, err)
+		}
+	}
+
+	return events, comments, nil
+}
+
+// storeSystemNote translates a system note into timeline events
+// Returns the number of events stored
+func (f *Fetcher) storeSystemNote(ctx context.Context, issueID int64, note *apiNote, rawData json.RawMessage) (int, error) {
+	refs := parseSystemNote(note.Body)
+
+	stored := 0
+	for _, ref := range refs {
+		login := note.Author.Username
+		actorID := note.Author.ID
+		event := database.TimelineEventData{
+			IssueID:    issueID,
+			EventType:  ref.EventType,
+			CreatedAt:  note.CreatedAt,
+			ActorLogin: &login,
+			ActorID:    &actorID,
+			RawData:    rawData,
+		}
+
+		if ref.SourceType != "" {
+			sourceType := ref.SourceType
+			event.SourceType = &sourceType
+		}
+		if ref.SourceSHA != "" {
+			sha := ref.SourceSHA
+			event.SourceSHA = &sha
+		}
+		if ref.SourceNumber > 0 {
+			number := ref.SourceNumber
+			event.SourceNumber = &number
+
+			if mr, ok := f.mergeRequests[number]; ok && ref.SourceType == "pull_request" {
+				title, body, state := mr.Title, mr.Description, normalizeState(mr.State)
+				event.SourceTitle = &title
+				event.SourceBody = &body
+				event.SourceState = &state
+				event.SourceMergedAt = mr.MergedAt
+			}
+		}
+
+		if err := f.store.StoreTimelineEvent(ctx, event); err != nil {
+			return stored, err
+		}
+		stored++
+	}
+
+	return stored, nil
+}
+
+// FetchMergeRequestFiles fetches file changes for merged MRs that don't have them yet
+// days: number of days to fetch (0 = all history)
+func (f *Fetcher) FetchMergeRequestFiles(ctx context.Context, repoID int64, fullPath string, days int) (int, error) {
+	prs, err := f.store.GetPRsWithoutFiles(ctx, repoID, days)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, pr := range prs {
+		var changes struct {
+			Changes []apiDiff `json:"changes"`
+		}
+		path := projectPath(fullPath, "merge_requests", strconv.Itoa(pr.Number), "changes")
+		if _, err := f.client.get(ctx, path, nil, &changes); err != nil {
+			log.Printf("  ⚠️  Failed to fetch changes for merge request !%d: %v", pr.Number, err)
+			continue
+		}
+
+		for _, d := range changes.Changes {
+			additions, deletions := countDiffLines(d.Diff)
+
+			var previous *string
+			if d.RenamedFile {
+				old := d.OldPath
+				previous = &old
+			}
+			patch := d.Diff
+
+			rawData, _ := json.Marshal(d)
+			if err := f.store.StorePRFile(ctx, repoID, pr.ID, d.NewPath, diffStatus(d),
+				additions, deletions, additions+deletions, previous, &patch, rawData); err != nil {
+				log.Printf("  ⚠️  Failed to store file %s for merge request !%d: %v", d.NewPath, pr.Number, err)
+				continue
+			}
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// normalizeState maps GitLab states onto GitHub's open/closed
+func normalizeState(state string) string {
+	switch state {
+	case "opened", "locked":
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// diffStatus maps GitLab diff flags onto GitHub file statuses
+func diffStatus(d apiDiff) string {
+	switch {
+	case d.NewFile:
+		return "added"
+	case d.DeletedFile:
+		return "removed"
+	case d.RenamedFile:
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
+// countDiffLines counts added and deleted lines in a unified diff body
+// GitLab diffs start at the first hunk header, so there are no ---/+++ file headers to skip
+func countDiffLines(diff string) (int, int) {
+	additions, deletions := 0, 0
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// githubUser builds the GitHub-style user object stored in raw_data
+func githubUser(u apiUser) map[string]interface{} {
+	return map[string]interface{}{
+		"login": u.Username,
+		"id":    u.ID,
+		"name":  u.Name,
+	}
+}
+
+func labelsOrEmpty(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
+
+func labelObjects(labels []string) []map[string]string {
+	objects := make([]map[string]string, 0, len(labels))
+	for _, l := range labels {
+		objects = append(objects, map[string]string{"name": l})
+	}
+	return objects
+}
@@ -0,0 +1,248 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/source/sourcetest"
+)
+
+// recordedGitLab serves the JSON fixtures in testdata, keyed by escaped request path
+func recordedGitLab(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	const project = "/api/v4/projects/acme%2Fplatform%2Fapi"
+	routes := map[string]string{
+		project: "project.json",
+		project + "/repository/commits/9f2c1d7e4b5a6c8d0e1f2a3b4c5d6e7f8a9b0c1d/diff": "commit_diff_fix.json",
+		project + "/repository/commits/1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b/diff": "commit_diff_initial.json",
+		project + "/merge_requests":            "merge_requests.json",
+		project + "/merge_requests/12/changes": "merge_request_12_changes.json",
+		project + "/issues":                    "issues.json",
+		project + "/issues/7/notes":            "issue_7_notes.json",
+		project + "/issues/8/notes":            "issue_8_notes.json",
+		project + "/repository/branches":       "branches.json",
+		project + "/languages":                 "languages.json",
+		project + "/repository/contributors":   "contributors.json",
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "test-token" {
+			http.Error(w, `{"message":"401 Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+
+		path := r.URL.EscapedPath()
+		fixture, ok := routes[path]
+
+		// Commits are split across two pages to exercise X-Next-Page handling
+		if path == project+"/repository/commits" {
+			if r.URL.Query().Get("with_stats") != "true" {
+				t.Errorf("commits requested without stats")
+			}
+			ok = true
+			fixture = "commits_page1.json"
+			if r.URL.Query().Get("page") == "2" {
+				fixture = "commits_page2.json"
+			} else {
+				w.Header().Set("X-Next-Page", "2")
+			}
+		}
+
+		if !ok {
+			t.Errorf("unexpected request: %s", path)
+			http.NotFound(w, r)
+			return
+		}
+
+		data, err := os.ReadFile(filepath.Join("testdata", fixture))
+		if err != nil {
+			t.Fatalf("read fixture %s: %v", fixture, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+}
+
+func TestFetchAll_RecordedProject(t *testing.T) {
+	server := recordedGitLab(t)
+	defer server.Close()
+
+	store := sourcetest.NewMemoryStore()
+	fetcher := NewFetcher(server.URL, "test-token", store)
+	fetcher.client.rateLimiter.SetLimit(1e6)
+
+	repoID, stats, err := fetcher.FetchAll(context.Background(), "acme/platform", "api", "/src/api", 0)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if repoID != 1 {
+		t.Errorf("expected repo ID 1, got %d", repoID)
+	}
+	if stats.Commits != 2 || stats.PRs != 2 || stats.Issues != 2 || stats.Branches != 2 || stats.Contributors != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// Repository carries default_branch for GetDefaultBranchName
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+		FullName      string `json:"full_name"`
+	}
+	json.Unmarshal(store.Repo, &repo)
+	if repo.DefaultBranch != "main" || repo.FullName != "acme/platform/api" {
+		t.Errorf("unexpected repository raw data: %s", store.Repo)
+	}
+
+	// Commits carry GitHub-shaped files[] and stats for graph.Builder
+	var commit struct {
+		Files []struct {
+			Filename  string `json:"filename"`
+			Status    string `json:"status"`
+			Additions int    `json:"additions"`
+			Deletions int    `json:"deletions"`
+		} `json:"files"`
+		Stats struct {
+			Total int `json:"total"`
+		} `json:"stats"`
+	}
+	json.Unmarshal(store.Commits["9f2c1d7e4b5a6c8d0e1f2a3b4c5d6e7f8a9b0c1d"], &commit)
+	if len(commit.Files) != 1 || commit.Files[0].Filename != "pay/refund.go" || commit.Files[0].Status != "modified" {
+		t.Fatalf("unexpected commit files: %+v", commit.Files)
+	}
+	if commit.Files[0].Additions != 2 || commit.Files[0].Deletions != 1 || commit.Stats.Total != 3 {
+		t.Errorf("unexpected line counts: %+v stats=%+v", commit.Files[0], commit.Stats)
+	}
+
+	// Merge requests become pull requests with user/head/base
+	var pr struct {
+		State string `json:"state"`
+		User  struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		Head struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+		MergeCommitSHA string `json:"merge_commit_sha"`
+	}
+	json.Unmarshal(store.PRs[12], &pr)
+	if pr.State != "closed" || !store.PRMerged[12] {
+		t.Errorf("expected !12 to be staged as a merged, closed PR: %s", store.PRs[12])
+	}
+	if pr.User.Login != "dlee" || pr.Head.Ref != "fix/refund-rounding" || pr.Base.Ref != "main" {
+		t.Errorf("unexpected PR shape: %+v", pr)
+	}
+	// Fast-forward merge: the landed commit is the source branch head
+	if pr.MergeCommitSHA != "9f2c1d7e4b5a6c8d0e1f2a3b4c5d6e7f8a9b0c1d" {
+		t.Errorf("unexpected merge commit %q", pr.MergeCommitSHA)
+	}
+	if store.PRMerged[13] {
+		t.Error("open MR !13 must not be marked merged")
+	}
+
+	if got := strings.Join(store.PRFiles[12], ","); got != "pay/refund.go:modified,pay/money.go:renamed" {
+		t.Errorf("unexpected PR files: %s", got)
+	}
+
+	if store.Issues[7] != "closed" || store.Issues[8] != "open" {
+		t.Errorf("unexpected issue states: %v", store.Issues)
+	}
+
+	// User notes become comments; system notes become timeline events
+	if len(store.Comments) != 1 {
+		t.Errorf("expected 1 comment, got %v", store.Comments)
+	}
+
+	var closed, referenced, crossRef *database.TimelineEventData
+	for i := range store.Timeline {
+		e := &store.Timeline[i]
+		switch e.EventType {
+		case "closed":
+			closed = e
+		case "referenced":
+			referenced = e
+		case "cross-referenced":
+			crossRef = e
+		}
+	}
+	if len(store.Timeline) != 3 {
+		t.Fatalf("expected 3 timeline events, got %d", len(store.Timeline))
+	}
+	if closed == nil || closed.SourceSHA == nil || *closed.SourceSHA != "9f2c1d7e4b5a6c8d0e1f2a3b4c5d6e7f8a9b0c1d" {
+		t.Errorf("expected closed event with commit SHA, got %+v", closed)
+	}
+	if referenced == nil || referenced.SourceType == nil || *referenced.SourceType != "commit" {
+		t.Errorf("expected referenced commit event, got %+v", referenced)
+	}
+	if crossRef == nil || crossRef.SourceNumber == nil || *crossRef.SourceNumber != 12 ||
+		*crossRef.SourceType != "pull_request" || crossRef.SourceBody == nil || *crossRef.SourceBody != "Closes #7" {
+		t.Errorf("expected cross-reference to !12 with MR body, got %+v", crossRef)
+	}
+	if crossRef != nil && crossRef.IssueID != 1007 {
+		t.Errorf("expected event on issue 7, got issue ID %d", crossRef.IssueID)
+	}
+
+	if string(store.Languages) != `{"Go":9250,"Shell":750}` {
+		t.Errorf("unexpected languages: %s", store.Languages)
+	}
+	if strings.Join(store.Logins, ",") != "dana@example.com,sam@example.com" {
+		t.Errorf("unexpected contributors: %v", store.Logins)
+	}
+	// Repository and contributors get distinct negative IDs: github_id is unique per
+	// repository and per contributor, and positive IDs belong to GitHub
+	seen := make(map[int64]bool)
+	for _, id := range store.GitHubIDs {
+		if id >= 0 || seen[id] {
+			t.Errorf("github_id %d is not a distinct synthetic ID: %v", id, store.GitHubIDs)
+		}
+		seen[id] = true
+	}
+}
+
+func TestFetchAll_Unauthorized(t *testing.T) {
+	server := recordedGitLab(t)
+	defer server.Close()
+
+	fetcher := NewFetcher(server.URL, "wrong", sourcetest.NewMemoryStore())
+	_, _, err := fetcher.FetchAll(context.Background(), "acme/platform", "api", "/src/api", 0)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected 401 error, got %v", err)
+	}
+}
+
+func TestParseSystemNote(t *testing.T) {
+	tests := []struct {
+		body   string
+		events []string
+	}{
+		{"closed via commit 9f2c1d7e", []string{"closed:commit:0:9f2c1d7e"}},
+		{"closed via merge request !12", []string{"closed::0:", "cross-referenced:pull_request:12:"}},
+		{"mentioned in commit 1a2b3c4d5e6f", []string{"referenced:commit:0:1a2b3c4d5e6f"}},
+		{"mentioned in merge request !3", []string{"cross-referenced:pull_request:3:"}},
+		{"mentioned in issue #44", []string{"cross-referenced:issue:44:"}},
+		{"mentioned in merge request other/project!3", nil},
+		{"closed", []string{"closed::0:"}},
+		{"reopened", []string{"reopened::0:"}},
+		{"changed the description", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, ref := range parseSystemNote(tt.body) {
+			got = append(got, strings.Join([]string{ref.EventType, ref.SourceType, strconv.Itoa(ref.SourceNumber), ref.SourceSHA}, ":"))
+		}
+		if strings.Join(got, "|") != strings.Join(tt.events, "|") {
+			t.Errorf("parseSystemNote(%q) = %v, want %v", tt.body, got, tt.events)
+		}
+	}
+}
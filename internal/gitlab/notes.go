@@ -0,0 +1,70 @@
+package gitlab
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GitLab has no timeline API; cross-references and closing commits are recorded as
+// system notes on the issue ("mentioned in commit abc123", "closed via merge request !12").
+// These are translated into the GitHub timeline events the linker already understands.
+
+// noteReference is a timeline event derived from a system note
+type noteReference struct {
+	EventType    string // "closed", "reopened", "referenced", "cross-referenced"
+	SourceType   string // "commit", "pull_request", "issue" or empty
+	SourceNumber int
+	SourceSHA    string
+}
+
+var (
+	closedViaCommitPattern = regexp.MustCompile(`^closed via commit ([0-9a-f]{7,40})\b`)
+	closedViaMRPattern     = regexp.MustCompile(`^closed via merge request !(\d+)\b`)
+	mentionedCommitPattern = regexp.MustCompile(`^mentioned in commit ([0-9a-f]{7,40})\b`)
+	mentionedMRPattern     = regexp.MustCompile(`^mentioned in merge request !(\d+)\b`)
+	mentionedIssuePattern  = regexp.MustCompile(`^mentioned in issue #(\d+)\b`)
+)
+
+// parseSystemNote converts a system note body into timeline events
+// References to other projects ("group/project!12", "group/project@sha") are ignored,
+// matching the same-repository scope of the GitHub fetcher.
+func parseSystemNote(body string) []noteReference {
+	body = strings.TrimSpace(body)
+
+	if m := closedViaCommitPattern.FindStringSubmatch(body); m != nil {
+		return []noteReference{{EventType: "closed", SourceType: "commit", SourceSHA: m[1]}}
+	}
+
+	if m := closedViaMRPattern.FindStringSubmatch(body); m != nil {
+		// GitHub records a plain close plus a cross-reference from the PR
+		n, _ := strconv.Atoi(m[1])
+		return []noteReference{
+			{EventType: "closed"},
+			{EventType: "cross-referenced", SourceType: "pull_request", SourceNumber: n},
+		}
+	}
+
+	if m := mentionedCommitPattern.FindStringSubmatch(body); m != nil {
+		return []noteReference{{EventType: "referenced", SourceType: "commit", SourceSHA: m[1]}}
+	}
+
+	if m := mentionedMRPattern.FindStringSubmatch(body); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return []noteReference{{EventType: "cross-referenced", SourceType: "pull_request", SourceNumber: n}}
+	}
+
+	if m := mentionedIssuePattern.FindStringSubmatch(body); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return []noteReference{{EventType: "cross-referenced", SourceType: "issue", SourceNumber: n}}
+	}
+
+	switch body {
+	case "closed":
+		return []noteReference{{EventType: "closed"}}
+	case "reopened":
+		return []noteReference{{EventType: "reopened"}}
+	}
+
+	return nil
+}
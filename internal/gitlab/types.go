@@ -0,0 +1,120 @@
+package gitlab
+
+import "time"
+
+// GitLab API v4 payloads (only the fields used for staging)
+
+type apiUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+type apiProject struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"`
+	Path              string    `json:"path"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	Description       string    `json:"description"`
+	DefaultBranch     string    `json:"default_branch"`
+	WebURL            string    `json:"web_url"`
+	Visibility        string    `json:"visibility"`
+	StarCount         int       `json:"star_count"`
+	ForksCount        int       `json:"forks_count"`
+	CreatedAt         time.Time `json:"created_at"`
+	LastActivityAt    time.Time `json:"last_activity_at"`
+}
+
+type apiCommit struct {
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Message        string    `json:"message"`
+	AuthorName     string    `json:"author_name"`
+	AuthorEmail    string    `json:"author_email"`
+	AuthoredDate   time.Time `json:"authored_date"`
+	CommitterName  string    `json:"committer_name"`
+	CommitterEmail string    `json:"committer_email"`
+	CommittedDate  time.Time `json:"committed_date"`
+	ParentIDs      []string  `json:"parent_ids"`
+	WebURL         string    `json:"web_url"`
+	Stats          struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+		Total     int `json:"total"`
+	} `json:"stats"`
+}
+
+// apiDiff is one file in a commit diff or merge request changes payload
+type apiDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+}
+
+type apiIssue struct {
+	ID          int64      `json:"id"`
+	IID         int        `json:"iid"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"` // "opened", "closed"
+	Labels      []string   `json:"labels"`
+	Author      apiUser    `json:"author"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+	WebURL      string     `json:"web_url"`
+}
+
+type apiMergeRequest struct {
+	ID              int64      `json:"id"`
+	IID             int        `json:"iid"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	State           string     `json:"state"` // "opened", "closed", "merged", "locked"
+	Labels          []string   `json:"labels"`
+	Author          apiUser    `json:"author"`
+	SourceBranch    string     `json:"source_branch"`
+	TargetBranch    string     `json:"target_branch"`
+	SHA             string     `json:"sha"`
+	MergeCommitSHA  *string    `json:"merge_commit_sha"`
+	SquashCommitSHA *string    `json:"squash_commit_sha"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	MergedAt        *time.Time `json:"merged_at"`
+	ClosedAt        *time.Time `json:"closed_at"`
+	WebURL          string     `json:"web_url"`
+	DiffRefs        struct {
+		BaseSHA  string `json:"base_sha"`
+		HeadSHA  string `json:"head_sha"`
+		StartSHA string `json:"start_sha"`
+	} `json:"diff_refs"`
+}
+
+type apiBranch struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+	Default   bool   `json:"default"`
+	Commit    struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+type apiContributor struct {
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Commits   int    `json:"commits"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
+type apiNote struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	Author    apiUser   `json:"author"`
+	System    bool      `json:"system"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
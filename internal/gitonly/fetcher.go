@@ -0,0 +1,437 @@
+// Package gitonly stages repository data from the local git history alone.
+// It is used by `crisk init --git-only` for air-gapped repositories and mirrors
+// where no hosting API is reachable. Commits, developers and file changes come
+// from git log; issue links are derived from "Fixes #123" style commit messages.
+package gitonly
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/graph"
+	"github.com/rohankatakam/coderisk/internal/source"
+	"github.com/rohankatakam/coderisk/internal/temporal"
+)
+
+// Fetcher stages local git history into the PostgreSQL staging tables
+// Data is written in the same GitHub-shaped raw_data the API fetchers produce,
+// so file identity mapping and graph construction run unchanged.
+type Fetcher struct {
+	store source.Store
+}
+
+var _ source.Fetcher = (*Fetcher)(nil)
+
+// NewFetcher creates a git-only fetcher
+func NewFetcher(store source.Store) *Fetcher {
+	return &Fetcher{store: store}
+}
+
+// FetchAll stages commits, branches, contributors and commit-referenced issues
+// owner/repo name the repository (from the origin remote, or "local"/<dir> without one)
+// days: number of days to stage (0 = all history)
+func (f *Fetcher) FetchAll(ctx context.Context, owner, repo, repoPath string, days int) (int64, *source.Stats, error) {
+	log.Printf("🔍 Reading local git history for %s/%s...", owner, repo)
+	stats := &source.Stats{}
+
+	branch, headSHA := currentBranch(ctx, repoPath)
+
+	repoID, err := f.storeRepository(ctx, owner, repo, repoPath, branch)
+	if err != nil {
+		return 0, stats, fmt.Errorf("store repository failed: %w", err)
+	}
+	log.Printf("  ✓ Repository ID: %d", repoID)
+
+	commits, err := temporal.ParseGitHistory(repoPath, days)
+	if err != nil {
+		return repoID, stats, err
+	}
+
+	// git log --numstat only carries subjects; issue references usually live in the body
+	messages, err := fullMessages(ctx, repoPath, days)
+	if err != nil {
+		log.Printf("  ⚠️  Failed to read full commit messages, using subjects only: %v", err)
+		messages = map[string]string{}
+	}
+
+	// Nor does it tell added, removed and renamed files apart
+	files, err := commitFiles(ctx, repoPath, days)
+	if err != nil {
+		log.Printf("  ⚠️  Failed to read file statuses, staging every file as modified: %v", err)
+		files = map[string][]fileChange{}
+	}
+
+	for _, c := range commits {
+		message := c.Message
+		if full, ok := messages[c.SHA]; ok {
+			message = full
+		}
+		changes, ok := files[c.SHA]
+		if !ok {
+			changes = numstatChanges(c.FilesChanged)
+		}
+		if err := f.storeCommit(ctx, repoID, c, message, changes); err != nil {
+			log.Printf("  ⚠️  Failed to store commit %s: %v", c.SHA, err)
+			continue
+		}
+		stats.Commits++
+	}
+	log.Printf("  ✓ Staged %d commits", stats.Commits)
+
+	if branch != "" && headSHA != "" {
+		raw, _ := json.Marshal(map[string]interface{}{"name": branch, "commit": map[string]string{"sha": headSHA}})
+		if err := f.store.StoreBranch(ctx, repoID, branch, headSHA, false, raw); err != nil {
+			log.Printf("  ⚠️  Failed to store branch %s: %v", branch, err)
+		} else {
+			stats.Branches = 1
+		}
+	}
+
+	for _, dev := range temporal.ExtractDevelopers(commits) {
+		raw, _ := json.Marshal(map[string]interface{}{
+			"login":         dev.Email,
+			"name":          dev.Name,
+			"email":         dev.Email,
+			"contributions": dev.TotalCommits,
+		})
+		if err := f.store.StoreContributor(ctx, repoID, source.SyntheticID(dev.Email), dev.Email, dev.TotalCommits, raw); err != nil {
+			log.Printf("  ⚠️  Failed to store contributor %s: %v", dev.Email, err)
+			continue
+		}
+		stats.Contributors++
+	}
+	log.Printf("  ✓ Staged %d contributors", stats.Contributors)
+
+	refs := issueReferences(commits, messages)
+	issueCount, err := f.storeIssueReferences(ctx, repoID, refs)
+	if err != nil {
+		log.Printf("  ⚠️  Failed to store commit issue references: %v", err)
+	}
+	stats.Issues = issueCount
+	log.Printf("  ✓ Derived %d issues from commit message references", issueCount)
+
+	return repoID, stats, nil
+}
+
+// storeRepository stores the repository row with a synthetic ID
+func (f *Fetcher) storeRepository(ctx context.Context, owner, repo, repoPath, branch string) (int64, error) {
+	fullName := owner + "/" + repo
+	rawData, err := json.Marshal(map[string]interface{}{
+		"name":           repo,
+		"full_name":      fullName,
+		"default_branch": branch,
+		"source":         string(source.ProviderGit),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal repository: %w", err)
+	}
+
+	absPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		absPath = repoPath
+	}
+
+	return f.store.StoreRepository(ctx, source.SyntheticID(absPath), owner, repo, fullName, repoPath, rawData)
+}
+
+// storeCommit stores a parsed commit in the GitHub commit shape (files[] and stats)
+func (f *Fetcher) storeCommit(ctx context.Context, repoID int64, c temporal.Commit, message string, changes []fileChange) error {
+	additions, deletions := 0, 0
+	files := make([]map[string]interface{}, 0, len(changes))
+	for _, fc := range changes {
+		additions += fc.Additions
+		deletions += fc.Deletions
+		file := map[string]interface{}{
+			"filename":  fc.Path,
+			"status":    fc.Status,
+			"additions": fc.Additions,
+			"deletions": fc.Deletions,
+			"changes":   fc.Additions + fc.Deletions,
+		}
+		if fc.PreviousPath != "" {
+			file["previous_filename"] = fc.PreviousPath
+		}
+		files = append(files, file)
+	}
+
+	rawData, err := json.Marshal(map[string]interface{}{
+		"sha": c.SHA,
+		"commit": map[string]interface{}{
+			"message": message,
+			"author": map[string]interface{}{
+				"name":  c.Author,
+				"email": c.Email,
+				"date":  c.Timestamp,
+			},
+		},
+		"stats": map[string]int{
+			"additions": additions,
+			"deletions": deletions,
+			"total":     additions + deletions,
+		},
+		"files": files,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal commit: %w", err)
+	}
+
+	return f.store.StoreCommit(ctx, repoID, c.SHA, c.Author, c.Email, c.Timestamp, message,
+		additions, deletions, additions+deletions, len(files), rawData)
+}
+
+// issueReference is an issue closed by one or more commits
+type issueReference struct {
+	Number  int
+	Commits []temporal.Commit // oldest first
+}
+
+// issueReferences collects "Fixes #N" references from commit messages
+func issueReferences(commits []temporal.Commit, messages map[string]string) []issueReference {
+	byNumber := make(map[int]*issueReference)
+	for _, c := range commits {
+		message := c.Message
+		if full, ok := messages[c.SHA]; ok {
+			message = full
+		}
+		for _, n := range graph.ExtractIssueReferences(message, "") {
+			ref, ok := byNumber[n]
+			if !ok {
+				ref = &issueReference{Number: n}
+				byNumber[n] = ref
+			}
+			ref.Commits = append(ref.Commits, c)
+		}
+	}
+
+	refs := make([]issueReference, 0, len(byNumber))
+	for _, ref := range byNumber {
+		sort.Slice(ref.Commits, func(i, j int) bool {
+			return ref.Commits[i].Timestamp.Before(ref.Commits[j].Timestamp)
+		})
+		refs = append(refs, *ref)
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Number < refs[j].Number })
+	return refs
+}
+
+// storeIssueReferences stores a placeholder issue per referenced number plus a
+// "closed" timeline event for each closing commit, the same evidence the GitHub
+// timeline provides for CLOSED_BY edges and SZZ fix commits.
+// Placeholder issues have no title or body; created_at is the first closing commit.
+func (f *Fetcher) storeIssueReferences(ctx context.Context, repoID int64, refs []issueReference) (int, error) {
+	if len(refs) == 0 {
+		return 0, nil
+	}
+
+	stored := 0
+	for _, ref := range refs {
+		first := ref.Commits[0].Timestamp
+		last := ref.Commits[len(ref.Commits)-1].Timestamp
+
+		rawData, _ := json.Marshal(map[string]interface{}{
+			"number":      ref.Number,
+			"state":       "closed",
+			"created_at":  first,
+			"closed_at":   last,
+			"source":      string(source.ProviderGit),
+			"placeholder": true,
+		})
+
+		closedAt := last
+		err := f.store.StoreIssue(ctx, repoID, source.SyntheticID(fmt.Sprintf("%d#%d", repoID, ref.Number)),
+			ref.Number, fmt.Sprintf("#%d", ref.Number), "", "closed", "", 0,
+			json.RawMessage(`[]`), first, &closedAt, rawData)
+		if err != nil {
+			log.Printf("  ⚠️  Failed to store issue #%d: %v", ref.Number, err)
+			continue
+		}
+		stored++
+	}
+
+	issueIDs, err := f.store.GetIssueIDsByNumber(ctx, repoID)
+	if err != nil {
+		return stored, err
+	}
+
+	for _, ref := range refs {
+		issueID, ok := issueIDs[ref.Number]
+		if !ok {
+			continue
+		}
+		for _, c := range ref.Commits {
+			sha := c.SHA
+			sourceType := "commit"
+			login := c.Email
+			rawData, _ := json.Marshal(map[string]interface{}{
+				"event":     "closed",
+				"commit_id": sha,
+				"source":    string(source.ProviderGit),
+			})
+			event := database.TimelineEventData{
+				IssueID:    issueID,
+				EventType:  "closed",
+				CreatedAt:  c.Timestamp,
+				SourceType: &sourceType,
+				SourceSHA:  &sha,
+				ActorLogin: &login,
+				RawData:    rawData,
+			}
+			if err := f.store.StoreTimelineEvent(ctx, event); err != nil {
+				log.Printf("  ⚠️  Failed to store closing event for issue #%d: %v", ref.Number, err)
+			}
+		}
+	}
+
+	return stored, nil
+}
+
+// currentBranch returns the checked-out branch name and HEAD SHA
+// A detached HEAD yields an empty branch name
+func currentBranch(ctx context.Context, repoPath string) (string, string) {
+	branch, _ := gitOutput(ctx, repoPath, "symbolic-ref", "--short", "HEAD")
+	sha, _ := gitOutput(ctx, repoPath, "rev-parse", "HEAD")
+	return branch, sha
+}
+
+// fullMessages returns complete commit messages keyed by SHA
+func fullMessages(ctx context.Context, repoPath string, days int) (map[string]string, error) {
+	args := []string{"log", "--format=%H%x00%B%x1e"}
+	if days > 0 {
+		args = append(args, fmt.Sprintf("--since=%d days ago", days))
+	}
+
+	out, err := gitOutput(ctx, repoPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseFullMessages(out), nil
+}
+
+// fileChange is a file touched by a commit, with its GitHub file status
+type fileChange struct {
+	Path         string
+	PreviousPath string // Set for renames and copies
+	Status       string // added, removed, modified, renamed, copied or changed
+	Additions    int
+	Deletions    int
+}
+
+// commitFiles returns the files each commit touched keyed by SHA, with renames detected
+func commitFiles(ctx context.Context, repoPath string, days int) (map[string][]fileChange, error) {
+	args := []string{"log", "-M", "--raw", "--numstat", "--format=%x1e%H"}
+	if days > 0 {
+		args = append(args, fmt.Sprintf("--since=%d days ago", days))
+	}
+
+	out, err := gitOutput(ctx, repoPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitFiles(out), nil
+}
+
+// parseCommitFiles parses "%x1e%H" records followed by --raw and --numstat lines
+// git prints the numstat lines in the same order as the raw lines, so the Nth numstat
+// line carries the line counts of the Nth raw entry.
+func parseCommitFiles(out string) map[string][]fileChange {
+	files := make(map[string][]fileChange)
+	for _, record := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		sha := lines[0]
+		if sha == "" {
+			continue
+		}
+
+		changes := []fileChange{}
+		counted := 0
+		for _, line := range lines[1:] {
+			if strings.HasPrefix(line, ":") {
+				// :100644 100644 9405325 0fdf397 R083<TAB>old<TAB>new
+				fields := strings.Split(line, "\t")
+				meta := strings.Fields(fields[0])
+				if len(meta) < 5 || len(fields) < 2 {
+					continue
+				}
+				fc := fileChange{Path: fields[len(fields)-1], Status: fileStatus(meta[4])}
+				if len(fields) == 3 {
+					fc.PreviousPath = fields[1]
+				}
+				changes = append(changes, fc)
+				continue
+			}
+
+			// 1<TAB>0<TAB>path; binary files report "-" and count as 0
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) < 3 || counted >= len(changes) {
+				continue
+			}
+			changes[counted].Additions, _ = strconv.Atoi(fields[0])
+			changes[counted].Deletions, _ = strconv.Atoi(fields[1])
+			counted++
+		}
+		files[sha] = changes
+	}
+	return files
+}
+
+// fileStatus maps a git status letter (R083 for a rename) to a GitHub file status
+func fileStatus(status string) string {
+	switch status[0] {
+	case 'A':
+		return "added"
+	case 'D':
+		return "removed"
+	case 'R':
+		return "renamed"
+	case 'C':
+		return "copied"
+	case 'T':
+		return "changed"
+	default:
+		return "modified"
+	}
+}
+
+// numstatChanges falls back to the numstat files, which carry no status
+func numstatChanges(files []temporal.FileChange) []fileChange {
+	changes := make([]fileChange, 0, len(files))
+	for _, fc := range files {
+		changes = append(changes, fileChange{Path: fc.Path, Status: "modified", Additions: fc.Additions, Deletions: fc.Deletions})
+	}
+	return changes
+}
+
+// parseFullMessages parses "%H%x00%B%x1e" records
+func parseFullMessages(out string) map[string]string {
+	messages := make(map[string]string)
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		sha, body, ok := strings.Cut(record, "\x00")
+		if !ok || sha == "" {
+			continue
+		}
+		messages[sha] = strings.TrimSpace(body)
+	}
+	return messages
+}
+
+func gitOutput(ctx context.Context, repoPath string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repoPath}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,168 @@
+package gitonly
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/source/sourcetest"
+)
+
+func TestFetchAll_LocalRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Dev", "GIT_AUTHOR_EMAIL=dev@example.com",
+			"GIT_COMMITTER_NAME=Dev", "GIT_COMMITTER_EMAIL=dev@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(dir, "calc.go"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+	write("package calc\n\nfunc Add(a, b int) int {\n\treturn a - b\n}\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "add calc")
+
+	write("package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n")
+	run("commit", "-q", "-am", "Correct Add\n\nThe sign was flipped.\n\nFixes #3")
+	fix := run("rev-parse", "HEAD")
+
+	run("mv", "calc.go", "add.go")
+	if err := os.WriteFile(filepath.Join(dir, "sub.go"), []byte("package calc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "Split calc")
+	splitSHA := run("rev-parse", "HEAD")
+
+	store := sourcetest.NewMemoryStore()
+	repoID, stats, err := NewFetcher(store).FetchAll(context.Background(), "local", "calc", dir, 0)
+	if err != nil {
+		t.Fatalf("FetchAll: %v", err)
+	}
+	if repoID != 1 {
+		t.Errorf("expected repo ID 1, got %d", repoID)
+	}
+	if stats.Commits != 3 || stats.Branches != 1 || stats.Contributors != 1 || stats.Issues != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	for _, id := range store.GitHubIDs {
+		if id >= 0 {
+			t.Errorf("expected synthetic (negative) github_id values, got %v", store.GitHubIDs)
+		}
+	}
+
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	json.Unmarshal(store.Repo, &repo)
+	if repo.DefaultBranch != "main" {
+		t.Errorf("expected default branch main, got %q", repo.DefaultBranch)
+	}
+
+	var commit struct {
+		Commit struct {
+			Message string `json:"message"`
+		} `json:"commit"`
+		Files []struct {
+			Filename  string `json:"filename"`
+			Status    string `json:"status"`
+			Additions int    `json:"additions"`
+			Deletions int    `json:"deletions"`
+		} `json:"files"`
+	}
+	json.Unmarshal(store.Commits[fix], &commit)
+	if len(commit.Files) != 1 || commit.Files[0].Filename != "calc.go" || commit.Files[0].Status != "modified" ||
+		commit.Files[0].Additions != 1 || commit.Files[0].Deletions != 1 {
+		t.Errorf("unexpected files for fix commit: %+v", commit.Files)
+	}
+	if !strings.Contains(commit.Commit.Message, "Fixes #3") {
+		t.Errorf("expected full message to be staged, got %q", commit.Commit.Message)
+	}
+
+	// Renames keep the previous path; new files are added rather than modified
+	var split struct {
+		Files []struct {
+			Filename         string `json:"filename"`
+			PreviousFilename string `json:"previous_filename"`
+			Status           string `json:"status"`
+		} `json:"files"`
+	}
+	json.Unmarshal(store.Commits[splitSHA], &split)
+	var statuses []string
+	for _, f := range split.Files {
+		statuses = append(statuses, f.Status+":"+f.PreviousFilename+">"+f.Filename)
+	}
+	if got := strings.Join(statuses, ","); got != "renamed:calc.go>add.go,added:>sub.go" {
+		t.Errorf("unexpected files for split commit: %s", got)
+	}
+
+	if store.Issues[3] != "closed" {
+		t.Fatalf("expected issue #3 to be derived as closed, got %v", store.Issues)
+	}
+	if len(store.Timeline) != 1 {
+		t.Fatalf("expected 1 timeline event, got %d", len(store.Timeline))
+	}
+	event := store.Timeline[0]
+	if event.EventType != "closed" || event.IssueID != 1003 || event.SourceSHA == nil || *event.SourceSHA != fix {
+		t.Errorf("unexpected closing event: %+v", event)
+	}
+}
+
+func TestParseFullMessages(t *testing.T) {
+	out := "aaa\x00Subject\n\nFixes #1\n\x1e\nbbb\x00Second\x1e"
+	messages := parseFullMessages(out)
+	if messages["aaa"] != "Subject\n\nFixes #1" || messages["bbb"] != "Second" {
+		t.Errorf("unexpected messages: %q", messages)
+	}
+}
+
+func TestParseCommitFiles(t *testing.T) {
+	out := "\x1ebbb\n\n" +
+		":100644 000000 b68fde2 0000000 D\tdel.go\n" +
+		":100644 100644 9405325 0fdf397 R083\tx.go\ty.go\n" +
+		":000000 100644 0000000 1a2b3c4 A\tlogo.png\n" +
+		"0\t4\tdel.go\n" +
+		"1\t0\tx.go => y.go\n" +
+		"-\t-\tlogo.png\n" +
+		"\x1eaaa\n"
+
+	files := parseCommitFiles(out)
+
+	want := []fileChange{
+		{Path: "del.go", Status: "removed", Deletions: 4},
+		{Path: "y.go", PreviousPath: "x.go", Status: "renamed", Additions: 1},
+		{Path: "logo.png", Status: "added"},
+	}
+	got := files["bbb"]
+	if len(got) != len(want) {
+		t.Fatalf("expected %d files, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("file %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+	if changes, ok := files["aaa"]; !ok || len(changes) != 0 {
+		t.Errorf("expected an empty file list for a commit without changes, got %+v", changes)
+	}
+}
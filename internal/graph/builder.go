@@ -136,6 +136,16 @@ func (b *Builder) BuildGraph(ctx context.Context, repoID int64, repoPath string)
 	stats.Edges += commitStats.Edges
 	log.Printf("  ✓ Processed commits: %d nodes, %d edges", commitStats.Nodes, commitStats.Edges)
 
+	// Derive IMPORTS and CALLS edges from source (needs the File nodes created above)
+	staticStats, err := b.buildStaticEdges(ctx, repoID, repoFullName, repoPath)
+	if err != nil {
+		log.Printf("  ⚠️  Warning: Failed to build static dependency edges: %v", err)
+	} else {
+		stats.Nodes += staticStats.Nodes
+		stats.Edges += staticStats.Edges
+		log.Printf("  ✓ Built static dependency edges: %d edges", staticStats.Edges)
+	}
+
 	// Process PRs (creates PR nodes + CREATED, MERGED_AS edges)
 	prStats, err := b.processPRs(ctx, repoID, repoFullName)
 	if err != nil {
@@ -302,6 +312,21 @@ func (b *Builder) loadIssuePRLinksIntoGraph(ctx context.Context, repoID int64, r
 		return stats, fmt.Errorf("failed to get issue-PR links: %w", err)
 	}
 
+	// Human decisions from `crisk links review`: accepted links are marked, rejected ones removed
+	labels, err := b.stagingDB.GetLinkLabels(ctx, repoID)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get link labels: %w", err)
+	}
+	if err := b.removeRejectedLinks(ctx, repoID, labels); err != nil {
+		log.Printf("  ⚠️  Warning: Failed to remove rejected links: %v", err)
+	}
+	accepted := make(map[[2]int]bool)
+	for _, label := range labels {
+		if label.Decision == database.LinkLabelAccepted {
+			accepted[[2]int{label.IssueNumber, label.PRNumber}] = true
+		}
+	}
+
 	if len(links) == 0 {
 		log.Printf("  No issue-PR links found")
 		return stats, nil
@@ -349,6 +374,9 @@ func (b *Builder) loadIssuePRLinksIntoGraph(ctx context.Context, repoID int64, r
 				"created_from":        "validated_link", // Distinguish from old system
 			},
 		}
+		if accepted[[2]int{link.IssueNumber, link.PRNumber}] {
+			edge.Properties["human_label"] = database.LinkLabelAccepted
+		}
 
 		edges = append(edges, edge)
 	}
@@ -384,6 +412,25 @@ func (b *Builder) loadIssuePRLinksIntoGraph(ctx context.Context, repoID int64, r
 	return stats, nil
 }
 
+// removeRejectedLinks deletes Issue-PR edges that a human rejected
+func (b *Builder) removeRejectedLinks(ctx context.Context, repoID int64, labels []database.LinkLabel) error {
+	var queries []string
+	for _, label := range labels {
+		if label.Decision != database.LinkLabelRejected {
+			continue
+		}
+		queries = append(queries, fmt.Sprintf(`
+			MATCH (i:Issue {repo_id: %d, number: %d})-[r]->(pr:PR {repo_id: %d, number: %d})
+			DELETE r
+		`, repoID, label.IssueNumber, repoID, label.PRNumber))
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+	log.Printf("  Removing %d rejected Issue-PR links...", len(queries))
+	return b.backend.ExecuteBatch(ctx, queries)
+}
+
 // determineEdgeTypeMultiSignal uses multi-dimensional ground truth signals
 // to determine if a link should be FIXED_BY or ASSOCIATED_WITH
 // Reference: Multi-Signal Ground Truth Classification strategy
@@ -917,8 +964,9 @@ func (b *Builder) transformIssue(issue database.IssueData, repoID int64, repoFul
 	return node, nil
 }
 
-// extractIssueReferences parses text for "Fixes #123", "Closes #456" patterns
-func extractIssueReferences(title, body string) []int {
+// ExtractIssueReferences parses text for "Fixes #123", "Closes #456" patterns
+// Used by git-only staging to link commits to the issues their messages close
+func ExtractIssueReferences(title, body string) []int {
 	text := strings.ToLower(title + " " + body)
 	re := regexp.MustCompile(`(?:fix|fixes|fixed|close|closes|closed|resolve|resolves|resolved)\s+#(\d+)`)
 	matches := re.FindAllStringSubmatch(text, -1)
@@ -339,6 +339,9 @@ func parseNodeID(nodeID string) (label string, id interface{}) {
 
 	// Handle special uppercase labels
 	switch strings.ToLower(prefix) {
+	case "codeblock":
+		// CodeBlock nodes are keyed by the full composite ID (<repo_id>:codeblock:<file>:<block>)
+		return "CodeBlock", nodeID
 	case "pr":
 		label = "PR"
 	case "issue":
@@ -373,6 +376,7 @@ func getUniqueKey(label string) string {
 		"commit":    "sha",
 		"PR":        "number", // PRIMARY KEY for PRs
 		"pr":        "number",
+		"CodeBlock": "id",     // Composite ID, see atomizer.CreateCodeBlockNode
 
 		// Deprecated node types (kept for backwards compatibility)
 		"Issue":       "number",
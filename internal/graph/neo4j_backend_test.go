@@ -88,6 +88,14 @@ func TestParseNodeID_IntegerConversion(t *testing.T) {
 			expectedID:    "src/utils/helper.ts",
 			expectedType:  "string",
 		},
+		// CodeBlocks are matched on their full composite ID
+		{
+			name:          "CodeBlock with composite ID",
+			input:         "7:codeblock:internal/auth/session.go:Refresh",
+			expectedLabel: "CodeBlock",
+			expectedID:    "7:codeblock:internal/auth/session.go:Refresh",
+			expectedType:  "string",
+		},
 		{
 			name:          "PR with invalid number falls back to string",
 			input:         "pr:invalid",
@@ -165,6 +173,7 @@ func TestGetUniqueKey(t *testing.T) {
 		{"commit", "sha"},
 		{"PR", "number"},
 		{"pr", "number"},
+		{"CodeBlock", "id"},
 		{"Issue", "number"},
 		{"issue", "number"},
 		{"Unknown", ""},
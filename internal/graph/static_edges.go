@@ -0,0 +1,232 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/depgraph"
+)
+
+// staticEdgeSource marks IMPORTS and CALLS edges derived from source code, so that
+// re-analysis only replaces its own edges and never those written by the atomizer
+const staticEdgeSource = "static_analysis"
+
+// paramBatchExecutor is implemented by backends that run parameterized write batches
+type paramBatchExecutor interface {
+	ExecuteBatchWithParams(ctx context.Context, queries []QueryWithParams) error
+}
+
+// buildStaticEdges derives File→File IMPORTS and CodeBlock→CodeBlock CALLS edges from the
+// checkout at repoPath
+// The first run writes every edge. Later runs re-analyze the tree but only rewrite edges
+// of files changed since the recorded commit and of the files depending on them.
+// CALLS edges attach to existing CodeBlock nodes, so they require atomized code blocks.
+func (b *Builder) buildStaticEdges(ctx context.Context, repoID int64, repoFullName, repoPath string) (*BuildStats, error) {
+	stats := &BuildStats{}
+
+	executor, ok := b.backend.(paramBatchExecutor)
+	if !ok {
+		return stats, fmt.Errorf("graph backend does not support parameterized batches")
+	}
+
+	headSHA, err := depgraph.HeadCommit(repoPath)
+	if err != nil {
+		return stats, err
+	}
+	state, err := b.stagingDB.GetStaticGraphState(ctx, repoID)
+	if err != nil {
+		return stats, err
+	}
+	if state != nil && state.CommitSHA == headSHA {
+		log.Printf("  Static edges up to date at %s", shortSHA(headSHA))
+		return stats, nil
+	}
+
+	// nil changed means a full rebuild
+	var changed []string
+	if state != nil {
+		changed, err = depgraph.ChangedFiles(repoPath, state.CommitSHA, headSHA)
+		if err != nil {
+			log.Printf("  ⚠️  Warning: %v; rebuilding all static edges", err)
+			changed = nil
+		} else if changed == nil {
+			changed = []string{}
+		}
+	}
+
+	result, err := depgraph.Analyze(repoPath)
+	if err != nil {
+		return stats, err
+	}
+
+	// Files whose outgoing edges are rewritten
+	var dirty map[string]bool
+	if changed != nil {
+		dirty = make(map[string]bool)
+		for _, f := range changed {
+			dirty[f] = true
+		}
+		for _, f := range result.Dependents(changed) {
+			dirty[f] = true
+		}
+	}
+
+	if err := executor.ExecuteBatchWithParams(ctx, staticEdgeDeletes(repoID, changed, dirty)); err != nil {
+		return stats, fmt.Errorf("failed to remove stale static edges: %w", err)
+	}
+
+	nodes, edges := staticGraphElements(repoID, repoFullName, headSHA, result, dirty)
+	if len(nodes) > 0 {
+		if _, err := b.backend.CreateNodes(ctx, nodes); err != nil {
+			return stats, fmt.Errorf("failed to create file nodes: %w", err)
+		}
+		stats.Nodes += len(nodes)
+	}
+	if len(edges) > 0 {
+		if err := b.backend.CreateEdges(ctx, edges); err != nil {
+			return stats, fmt.Errorf("failed to create static edges: %w", err)
+		}
+		stats.Edges += len(edges)
+	}
+
+	err = b.stagingDB.SaveStaticGraphState(ctx, &database.StaticGraphState{
+		RepoID:        repoID,
+		CommitSHA:     headSHA,
+		FilesAnalyzed: len(result.Files),
+		ImportEdges:   len(result.Imports),
+		CallEdges:     len(result.Calls),
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	if changed != nil {
+		log.Printf("  Static analysis: %d files changed since %s, %d files re-linked",
+			len(changed), shortSHA(state.CommitSHA), len(dirty))
+	}
+	return stats, nil
+}
+
+// staticEdgeDeletes removes the static edges that are about to be rewritten
+// With dirty nil every static edge of the repository is removed. Otherwise edges leaving a
+// dirty file go, along with edges into changed files, which may have been deleted or renamed.
+func staticEdgeDeletes(repoID int64, changed []string, dirty map[string]bool) []QueryWithParams {
+	if dirty == nil {
+		return []QueryWithParams{
+			{
+				Query: `
+					MATCH (:File {repo_id: $repoID})-[r:IMPORTS]->(:File)
+					WHERE r.source = $source
+					DELETE r
+				`,
+				Params: map[string]any{"repoID": repoID, "source": staticEdgeSource},
+			},
+			{
+				Query: `
+					MATCH (:CodeBlock {repo_id: $repoID})-[r:CALLS]->(:CodeBlock)
+					WHERE r.source = $source
+					DELETE r
+				`,
+				Params: map[string]any{"repoID": repoID, "source": staticEdgeSource},
+			},
+		}
+	}
+
+	dirtyFiles := make([]string, 0, len(dirty))
+	for f := range dirty {
+		dirtyFiles = append(dirtyFiles, f)
+	}
+	params := map[string]any{
+		"repoID":  repoID,
+		"source":  staticEdgeSource,
+		"dirty":   dirtyFiles,
+		"changed": changed,
+	}
+	return []QueryWithParams{
+		{
+			Query: `
+				MATCH (a:File {repo_id: $repoID})-[r:IMPORTS]->(b:File)
+				WHERE r.source = $source AND (a.path IN $dirty OR b.path IN $changed)
+				DELETE r
+			`,
+			Params: params,
+		},
+		{
+			Query: `
+				MATCH (:CodeBlock {repo_id: $repoID})-[r:CALLS]->(:CodeBlock)
+				WHERE r.source = $source AND (r.from_file IN $dirty OR r.to_file IN $changed)
+				DELETE r
+			`,
+			Params: params,
+		},
+	}
+}
+
+// staticGraphElements converts analysis results to File nodes and IMPORTS/CALLS edges
+// Only edges leaving dirty files are returned, or all of them when dirty is nil.
+func staticGraphElements(repoID int64, repoFullName, commitSHA string, result *depgraph.Result, dirty map[string]bool) ([]GraphNode, []GraphEdge) {
+	var nodes []GraphNode
+	var edges []GraphEdge
+	seen := make(map[string]bool)
+
+	fileNode := func(path string) string {
+		id := buildCompositeNodeID(repoID, "file", path)
+		if !seen[path] {
+			seen[path] = true
+			nodes = append(nodes, GraphNode{
+				Label: "File",
+				ID:    id,
+				Properties: map[string]interface{}{
+					"repo_id":        repoID,
+					"repo_full_name": repoFullName,
+					"path":           path,
+					"canonical_path": path,
+				},
+			})
+		}
+		return id
+	}
+
+	for _, imp := range result.Imports {
+		if dirty != nil && !dirty[imp.From] {
+			continue
+		}
+		edges = append(edges, GraphEdge{
+			Label: "IMPORTS",
+			From:  fileNode(imp.From),
+			To:    fileNode(imp.To),
+			Properties: map[string]interface{}{
+				"source":     staticEdgeSource,
+				"commit_sha": commitSHA,
+			},
+		})
+	}
+
+	for _, c := range result.Calls {
+		if dirty != nil && !dirty[c.FromFile] {
+			continue
+		}
+		edges = append(edges, GraphEdge{
+			Label: "CALLS",
+			From:  fmt.Sprintf("%d:codeblock:%s:%s", repoID, c.FromFile, c.FromBlock),
+			To:    fmt.Sprintf("%d:codeblock:%s:%s", repoID, c.ToFile, c.ToBlock),
+			Properties: map[string]interface{}{
+				"source":     staticEdgeSource,
+				"commit_sha": commitSHA,
+				"from_file":  c.FromFile,
+				"to_file":    c.ToFile,
+			},
+		})
+	}
+
+	return nodes, edges
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
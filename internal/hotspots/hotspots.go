@@ -0,0 +1,390 @@
+// Package hotspots ranks every file and code block in a repository by how much
+// refactoring attention it deserves. The composite score combines time-decayed change
+// frequency, complexity measured from the working tree, production incidents,
+// ownership dispersion and co-change coupling, so code that is complicated, changes
+// often, breaks, and is edited by many hands rises to the top.
+package hotspots
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/complexity"
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// Weights are the contribution of each normalized factor to the composite score
+type Weights struct {
+	Churn      float64 `json:"churn"`
+	Complexity float64 `json:"complexity"`
+	Incidents  float64 `json:"incidents"`
+	Ownership  float64 `json:"ownership"`
+	Coupling   float64 `json:"coupling"`
+}
+
+// Options controls ranking and filtering
+type Options struct {
+	Windows      []int     `json:"windows"`        // Change-count windows in days, e.g. 30, 90, 365
+	HalfLifeDays float64   `json:"half_life_days"` // A change this old counts half as much as one made today
+	Weights      Weights   `json:"weights"`
+	Dir          string    `json:"dir,omitempty"`       // Only paths under this directory
+	Languages    []string  `json:"languages,omitempty"` // Only these languages (names or extensions, empty = all)
+	Top          int       `json:"top"`                 // Hotspots listed per kind (0 = all)
+	Now          time.Time `json:"as_of"`               // Reference time for windows and decay (zero = time.Now)
+}
+
+// DefaultOptions weights churn highest: complexity that never changes is rarely worth refactoring
+func DefaultOptions() Options {
+	return Options{
+		Windows:      []int{30, 90, 365},
+		HalfLifeDays: 90,
+		Weights: Weights{
+			Churn:      0.35,
+			Complexity: 0.25,
+			Incidents:  0.20,
+			Ownership:  0.10,
+			Coupling:   0.10,
+		},
+		Top: 50,
+	}
+}
+
+// Factors are the normalized (0-1) inputs of a hotspot's score
+type Factors struct {
+	Churn      float64 `json:"churn"`
+	Complexity float64 `json:"complexity"`
+	Incidents  float64 `json:"incidents"`
+	Ownership  float64 `json:"ownership"`
+	Coupling   float64 `json:"coupling"`
+}
+
+// Hotspot is a ranked file or code block
+type Hotspot struct {
+	Kind          string         `json:"kind"` // "file" or "block"
+	File          string         `json:"file"`
+	Block         string         `json:"block,omitempty"`
+	BlockType     string         `json:"block_type,omitempty"`
+	StartLine     int            `json:"start_line,omitempty"`
+	EndLine       int            `json:"end_line,omitempty"`
+	Language      string         `json:"language"`
+	Score         float64        `json:"score"`   // 0-100
+	Changes       map[string]int `json:"changes"` // Changes per window, keyed like "90d"
+	DecayedChurn  float64        `json:"decayed_churn"`
+	Cyclomatic    int            `json:"cyclomatic"`
+	LOC           int            `json:"loc"`
+	Incidents     int            `json:"incidents"`
+	Developers    int            `json:"developers"`
+	TopOwner      string         `json:"top_owner,omitempty"`
+	TopOwnerShare float64        `json:"top_owner_share"`
+	Dispersion    float64        `json:"ownership_dispersion"` // Normalized entropy of edits across developers
+	Coupling      float64        `json:"coupling"`             // Co-change count weighted by coupling rate
+	Factors       Factors        `json:"factors"`
+}
+
+// Report is the ranked hotspot list for a repository
+type Report struct {
+	GeneratedAt  time.Time `json:"generated_at"`
+	Options      Options   `json:"options"`
+	FilesScored  int       `json:"files_scored"`
+	BlocksScored int       `json:"blocks_scored"`
+	Files        []Hotspot `json:"files"`
+	Blocks       []Hotspot `json:"blocks"`
+}
+
+// Input is the repository history a report is built from
+type Input struct {
+	Blocks       []database.BlockWithOwnership
+	BlockChanges map[int64][]time.Time // Commit dates per block ID
+	Commits      []database.CommitFiles
+	RepoPath     string // Checkout to measure complexity from; files missing from it are skipped
+}
+
+// candidate accumulates raw metrics before normalization
+type candidate struct {
+	Hotspot
+	dates       []time.Time
+	familiarity map[string]int
+}
+
+// Build ranks the files and blocks of in
+func Build(in Input, opts Options) *Report {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	report := &Report{GeneratedAt: now, Options: opts}
+	src := &sourceCache{root: in.RepoPath, lines: make(map[string][]string)}
+
+	files := make(map[string]*candidate)
+	fileOf := func(path string) *candidate {
+		f, ok := files[path]
+		if !ok {
+			f = &candidate{Hotspot: Hotspot{Kind: "file", File: path, Language: complexity.LanguageOf(path)}, familiarity: map[string]int{}}
+			files[path] = f
+		}
+		return f
+	}
+
+	// File churn comes from commit history, following renames to the current path
+	renamedTo := make(map[string]string)
+	for i := len(in.Commits) - 1; i >= 0; i-- {
+		c := in.Commits[i]
+		for _, change := range c.Files {
+			current := change.Filename
+			if to, ok := renamedTo[current]; ok {
+				current = to
+			}
+			if change.PreviousFilename != "" {
+				renamedTo[change.PreviousFilename] = current
+			}
+			if opts.includes(current) {
+				f := fileOf(current)
+				f.dates = append(f.dates, c.AuthorDate)
+			}
+		}
+	}
+
+	var blocks []*candidate
+	for _, b := range in.Blocks {
+		path := strings.TrimPrefix(b.CanonicalFilePath, "/")
+		if !opts.includes(path) {
+			continue
+		}
+		block := &candidate{
+			Hotspot: Hotspot{
+				Kind:      "block",
+				File:      path,
+				Block:     b.BlockName,
+				BlockType: b.BlockType,
+				StartLine: b.StartLine,
+				EndLine:   b.EndLine,
+				Language:  complexity.LanguageOf(path),
+				Incidents: b.IncidentCount,
+				Coupling:  float64(b.CoChangeCount) * b.AvgCouplingRate,
+			},
+			dates:       in.BlockChanges[b.ID],
+			familiarity: make(map[string]int, len(b.FamiliarityMap)),
+		}
+		for email, edits := range b.FamiliarityMap {
+			if edits > 0 {
+				block.familiarity[strings.ToLower(email)] += edits
+			}
+		}
+
+		f := fileOf(path)
+		f.Incidents += b.IncidentCount
+		f.Coupling = math.Max(f.Coupling, block.Coupling)
+		for email, edits := range block.familiarity {
+			f.familiarity[email] += edits
+		}
+		blocks = append(blocks, block)
+	}
+
+	var scoredFiles, scoredBlocks []*candidate
+	for _, f := range files {
+		lines, ok := src.file(f.File)
+		if in.RepoPath != "" && !ok {
+			continue // Deleted since
+		}
+		if ok {
+			m := complexity.Measure(lines, f.Language)
+			f.Cyclomatic, f.LOC = m.Cyclomatic, m.Lines
+		}
+		scoredFiles = append(scoredFiles, f)
+	}
+	for _, b := range blocks {
+		lines, ok := src.file(b.File)
+		if in.RepoPath != "" && !ok {
+			continue
+		}
+		if ok && b.StartLine > 0 && b.EndLine >= b.StartLine && b.StartLine <= len(lines) {
+			m := complexity.Measure(lines[b.StartLine-1:min(b.EndLine, len(lines))], b.Language)
+			b.Cyclomatic, b.LOC = m.Cyclomatic, m.Lines
+		}
+		scoredBlocks = append(scoredBlocks, b)
+	}
+
+	report.FilesScored = len(scoredFiles)
+	report.BlocksScored = len(scoredBlocks)
+	report.Files = rank(scoredFiles, now, opts)
+	report.Blocks = rank(scoredBlocks, now, opts)
+	return report
+}
+
+// rank fills in churn and ownership, normalizes every factor against the highest value
+// among the candidates, and returns the top hotspots by composite score
+func rank(cands []*candidate, now time.Time, opts Options) []Hotspot {
+	var maxChurn, maxCyclomatic, maxLOC, maxIncidents, maxCoupling float64
+	for _, c := range cands {
+		c.Changes = make(map[string]int, len(opts.Windows))
+		for _, days := range opts.Windows {
+			since := now.AddDate(0, 0, -days)
+			n := 0
+			for _, at := range c.dates {
+				if !at.Before(since) && !at.After(now) {
+					n++
+				}
+			}
+			c.Changes[windowKey(days)] = n
+		}
+		c.DecayedChurn = decayedCount(c.dates, now, opts.HalfLifeDays)
+		c.TopOwner, c.TopOwnerShare, c.Dispersion = dispersion(c.familiarity)
+		c.Developers = len(c.familiarity)
+
+		maxChurn = math.Max(maxChurn, c.DecayedChurn)
+		maxCyclomatic = math.Max(maxCyclomatic, float64(c.Cyclomatic))
+		maxLOC = math.Max(maxLOC, float64(c.LOC))
+		maxIncidents = math.Max(maxIncidents, float64(c.Incidents))
+		maxCoupling = math.Max(maxCoupling, c.Coupling)
+	}
+
+	w := opts.Weights
+	total := w.Churn + w.Complexity + w.Incidents + w.Ownership + w.Coupling
+	if total <= 0 {
+		total = 1
+	}
+
+	out := make([]Hotspot, 0, len(cands))
+	for _, c := range cands {
+		c.Factors = Factors{
+			Churn:      logScale(c.DecayedChurn, maxChurn),
+			Complexity: (logScale(float64(c.Cyclomatic), maxCyclomatic) + logScale(float64(c.LOC), maxLOC)) / 2,
+			Incidents:  logScale(float64(c.Incidents), maxIncidents),
+			Ownership:  c.Dispersion,
+			Coupling:   logScale(c.Coupling, maxCoupling),
+		}
+		score := w.Churn*c.Factors.Churn + w.Complexity*c.Factors.Complexity + w.Incidents*c.Factors.Incidents +
+			w.Ownership*c.Factors.Ownership + w.Coupling*c.Factors.Coupling
+		c.Score = round(100*score/total, 1)
+		c.DecayedChurn = round(c.DecayedChurn, 2)
+		c.Coupling = round(c.Coupling, 2)
+		c.Dispersion = round(c.Dispersion, 2)
+		c.TopOwnerShare = round(c.TopOwnerShare, 2)
+		out = append(out, c.Hotspot)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].File != out[j].File {
+			return out[i].File < out[j].File
+		}
+		return out[i].StartLine < out[j].StartLine
+	})
+	if opts.Top > 0 && len(out) > opts.Top {
+		out = out[:opts.Top]
+	}
+	return out
+}
+
+// includes applies the directory and language filters
+func (o Options) includes(path string) bool {
+	if o.Dir != "" && o.Dir != "." && path != o.Dir && !strings.HasPrefix(path, strings.TrimSuffix(o.Dir, "/")+"/") {
+		return false
+	}
+	language := complexity.LanguageOf(path)
+	if language == "" {
+		return false
+	}
+	if len(o.Languages) == 0 {
+		return true
+	}
+	for _, want := range o.Languages {
+		want = strings.ToLower(strings.TrimSpace(want))
+		if want == language || complexity.LanguageForExtension(want) == language {
+			return true
+		}
+	}
+	return false
+}
+
+// decayedCount weights each change by 0.5^(age / half-life)
+func decayedCount(dates []time.Time, now time.Time, halfLifeDays float64) float64 {
+	if halfLifeDays <= 0 {
+		return float64(len(dates))
+	}
+	var sum float64
+	for _, at := range dates {
+		age := now.Sub(at).Hours() / 24
+		if age < 0 {
+			age = 0
+		}
+		sum += math.Pow(0.5, age/halfLifeDays)
+	}
+	return sum
+}
+
+// dispersion returns the top owner, their share of edits, and the Shannon entropy of
+// edits normalized to 0-1 (0 = one developer, 1 = evenly spread across all of them)
+func dispersion(familiarity map[string]int) (string, float64, float64) {
+	total := 0
+	owner := ""
+	for email, edits := range familiarity {
+		total += edits
+		if edits > familiarity[owner] || (edits == familiarity[owner] && email < owner) {
+			owner = email
+		}
+	}
+	if total == 0 {
+		return "", 0, 0
+	}
+	share := float64(familiarity[owner]) / float64(total)
+	if len(familiarity) < 2 {
+		return owner, share, 0
+	}
+
+	var entropy float64
+	for _, edits := range familiarity {
+		if edits > 0 {
+			p := float64(edits) / float64(total)
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return owner, share, entropy / math.Log2(float64(len(familiarity)))
+}
+
+// logScale maps v onto 0-1 relative to max, compressing long-tailed metrics
+func logScale(v, max float64) float64 {
+	if max <= 0 || v <= 0 {
+		return 0
+	}
+	return math.Log1p(v) / math.Log1p(max)
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+func windowKey(days int) string {
+	return fmt.Sprintf("%dd", days)
+}
+
+// sourceCache reads each file of the checkout at most once
+type sourceCache struct {
+	root  string
+	lines map[string][]string
+}
+
+// file returns a file's lines and whether it exists in the checkout
+func (s *sourceCache) file(path string) ([]string, bool) {
+	if s.root == "" {
+		return nil, false
+	}
+	if lines, ok := s.lines[path]; ok {
+		return lines, lines != nil
+	}
+	content, err := os.ReadFile(filepath.Join(s.root, filepath.FromSlash(path)))
+	var lines []string
+	if err == nil {
+		lines = strings.Split(string(content), "\n")
+	}
+	s.lines[path] = lines
+	return lines, lines != nil
+}
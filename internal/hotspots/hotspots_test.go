@@ -0,0 +1,185 @@
+package hotspots
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+var now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func daysAgo(days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
+
+func writeRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func commit(at time.Time, files ...database.CommitFileChange) database.CommitFiles {
+	return database.CommitFiles{SHA: at.String(), AuthorDate: at, Files: files}
+}
+
+func change(name string) database.CommitFileChange {
+	return database.CommitFileChange{Filename: name, Status: "modified"}
+}
+
+const billingSource = `package billing
+
+// Charge applies discounts && taxes
+func Charge(total int, vip bool) int {
+	if total <= 0 || vip {
+		return 0
+	}
+	for i := 0; i < 3; i++ {
+		switch {
+		case total > 100:
+			total -= 10
+		case total > 50:
+			total -= 5
+		}
+	}
+	return total
+}
+
+func Refund(total int) int {
+	return -total
+}
+`
+
+func TestBuildRanksAndFilters(t *testing.T) {
+	root := writeRepo(t, map[string]string{
+		"billing/charge.go": billingSource,
+		"billing/util.go":   "package billing\n\nfunc noop() {}\n",
+		"web/app.py":        "def index():\n    return 'ok'\n",
+	})
+
+	in := Input{
+		RepoPath: root,
+		Commits: []database.CommitFiles{
+			commit(daysAgo(400), database.CommitFileChange{Filename: "billing/legacy.go", Status: "added"}),
+			commit(daysAgo(200), database.CommitFileChange{Filename: "billing/charge.go", PreviousFilename: "billing/legacy.go", Status: "renamed"}),
+			commit(daysAgo(60), change("billing/charge.go"), change("billing/util.go")),
+			commit(daysAgo(10), change("billing/charge.go"), change("README.md")),
+			commit(daysAgo(5), change("billing/charge.go"), change("web/app.py")),
+			commit(daysAgo(3), change("billing/removed.go")),
+		},
+		Blocks: []database.BlockWithOwnership{
+			{
+				ID: 1, BlockName: "Charge", BlockType: "function", CanonicalFilePath: "billing/charge.go",
+				StartLine: 4, EndLine: 17, IncidentCount: 2, CoChangeCount: 4, AvgCouplingRate: 0.5,
+				FamiliarityMap: map[string]int{"Alice@example.com": 3, "bob@example.com": 3},
+			},
+			{
+				ID: 2, BlockName: "Refund", BlockType: "function", CanonicalFilePath: "billing/charge.go",
+				StartLine: 19, EndLine: 21, FamiliarityMap: map[string]int{"alice@example.com": 1},
+			},
+		},
+		BlockChanges: map[int64][]time.Time{
+			1: {daysAgo(60), daysAgo(10), daysAgo(5)},
+			2: {daysAgo(200)},
+		},
+	}
+
+	opts := DefaultOptions()
+	opts.Now = now
+	r := Build(in, opts)
+
+	if r.FilesScored != 3 {
+		t.Fatalf("scored %d files, want 3 (README is not source, removed.go is deleted): %+v", r.FilesScored, r.Files)
+	}
+	top := r.Files[0]
+	if top.File != "billing/charge.go" {
+		t.Fatalf("top file = %s, want billing/charge.go", top.File)
+	}
+	// The rename carries legacy.go's history over
+	if top.Changes["30d"] != 2 || top.Changes["90d"] != 3 || top.Changes["365d"] != 4 {
+		t.Errorf("charge.go changes = %v, want 30d:2 90d:3 365d:4", top.Changes)
+	}
+	if top.Incidents != 2 || top.Developers != 2 || top.TopOwner != "alice@example.com" {
+		t.Errorf("charge.go = %+v, want 2 incidents, 2 developers, alice as top owner", top)
+	}
+
+	if len(r.Blocks) != 2 || r.Blocks[0].Block != "Charge" {
+		t.Fatalf("blocks = %+v, want Charge ranked first", r.Blocks)
+	}
+	charge := r.Blocks[0]
+	if charge.Cyclomatic != 6 || charge.Dispersion != 1 || charge.Coupling != 2 {
+		t.Errorf("Charge = %+v, want cyclomatic 6, dispersion 1, coupling 2", charge)
+	}
+	if charge.Factors.Churn != 1 || charge.Score <= r.Blocks[1].Score {
+		t.Errorf("Charge should have the highest churn and score: %+v vs %+v", charge, r.Blocks[1])
+	}
+
+	opts.Languages = []string{"py"}
+	if r := Build(in, opts); len(r.Files) != 1 || r.Files[0].File != "web/app.py" || len(r.Blocks) != 0 {
+		t.Errorf("language filter: files %+v, blocks %+v", r.Files, r.Blocks)
+	}
+
+	opts.Languages = nil
+	opts.Dir = "web"
+	if r := Build(in, opts); len(r.Files) != 1 || r.Files[0].File != "web/app.py" {
+		t.Errorf("directory filter: files %+v", r.Files)
+	}
+}
+
+func TestDecayedCount(t *testing.T) {
+	got := decayedCount([]time.Time{now, daysAgo(90), daysAgo(180)}, now, 90)
+	if got != 1.75 {
+		t.Errorf("decayedCount = %v, want 1.75", got)
+	}
+}
+
+func TestWriters(t *testing.T) {
+	r := &Report{
+		GeneratedAt: now,
+		Options:     DefaultOptions(),
+		Files: []Hotspot{{
+			Kind: "file", File: "billing/charge.go", Language: "go", Score: 81.5,
+			Changes: map[string]int{"30d": 2, "90d": 3, "365d": 4}, Cyclomatic: 7, LOC: 20,
+		}},
+		Blocks: []Hotspot{{
+			Kind: "block", File: "billing/charge.go", Block: "Charge", BlockType: "function", StartLine: 4, EndLine: 17,
+			Language: "go", Score: 90, Changes: map[string]int{"30d": 2}, TopOwner: "alice@example.com", TopOwnerShare: 0.5,
+		}},
+	}
+
+	var md bytes.Buffer
+	if err := WriteMarkdown(&md, "acme/shop", r); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Hotspots: acme/shop", "| 1 | billing/charge.go | 81.5 | 2 | 3 | 4 |", "`Charge` billing/charge.go:4", "alice@example.com (50%)"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := WriteCSV(&out, r); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][8] != "changes_30d" || rows[2][2] != "Charge" || rows[2][8] != "2" {
+		t.Errorf("unexpected CSV: %v", rows)
+	}
+}
@@ -0,0 +1,115 @@
+package hotspots
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteMarkdown renders the report for refactoring planning
+func WriteMarkdown(w io.Writer, repo string, r *Report) error {
+	var b strings.Builder
+	o := r.Options
+
+	fmt.Fprintf(&b, "# Hotspots: %s\n\n", repo)
+	fmt.Fprintf(&b, "Generated %s from %d files and %d code blocks", r.GeneratedAt.Format("2006-01-02"), r.FilesScored, r.BlocksScored)
+	if o.Dir != "" {
+		fmt.Fprintf(&b, " under `%s/`", strings.TrimSuffix(o.Dir, "/"))
+	}
+	if len(o.Languages) > 0 {
+		fmt.Fprintf(&b, " (%s)", strings.Join(o.Languages, ", "))
+	}
+	fmt.Fprintf(&b, ". Changes are decayed with a %.0f-day half-life; the score weights churn %.0f%%, "+
+		"complexity %.0f%%, incidents %.0f%%, ownership dispersion %.0f%% and coupling %.0f%%.\n\n",
+		o.HalfLifeDays, o.Weights.Churn*100, o.Weights.Complexity*100, o.Weights.Incidents*100,
+		o.Weights.Ownership*100, o.Weights.Coupling*100)
+
+	windows := make([]string, len(o.Windows))
+	for i, days := range o.Windows {
+		windows[i] = windowKey(days)
+	}
+
+	writeTable(&b, "Files", windows, r.Files)
+	writeTable(&b, "Code Blocks", windows, r.Blocks)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTable(b *strings.Builder, title string, windows []string, hotspots []Hotspot) {
+	fmt.Fprintf(b, "## %s\n\n", title)
+	if len(hotspots) == 0 {
+		b.WriteString("None.\n\n")
+		return
+	}
+
+	b.WriteString("| # | Location | Score |")
+	for _, w := range windows {
+		fmt.Fprintf(b, " Changes %s |", w)
+	}
+	b.WriteString(" Complexity | LOC | Incidents | Developers | Top owner | Coupling |\n|---:|---|---:|")
+	b.WriteString(strings.Repeat("---:|", len(windows)))
+	b.WriteString("---:|---:|---:|---:|---|---:|\n")
+
+	for i, h := range hotspots {
+		location := h.File
+		if h.Kind == "block" {
+			location = fmt.Sprintf("`%s` %s:%d", h.Block, h.File, h.StartLine)
+		}
+		fmt.Fprintf(b, "| %d | %s | %.1f |", i+1, location, h.Score)
+		for _, w := range windows {
+			fmt.Fprintf(b, " %d |", h.Changes[w])
+		}
+		owner := "-"
+		if h.TopOwner != "" {
+			owner = fmt.Sprintf("%s (%.0f%%)", h.TopOwner, h.TopOwnerShare*100)
+		}
+		fmt.Fprintf(b, " %d | %d | %d | %d | %s | %.1f |\n",
+			h.Cyclomatic, h.LOC, h.Incidents, h.Developers, owner, h.Coupling)
+	}
+	b.WriteString("\n")
+}
+
+// WriteCSV writes files then blocks as one table, one row per hotspot
+func WriteCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"kind", "file", "block", "block_type", "start_line", "end_line", "language", "score"}
+	for _, days := range r.Options.Windows {
+		header = append(header, "changes_"+windowKey(days))
+	}
+	header = append(header, "decayed_churn", "cyclomatic", "loc", "incidents", "developers",
+		"top_owner", "top_owner_share", "ownership_dispersion", "coupling")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, h := range append(append([]Hotspot{}, r.Files...), r.Blocks...) {
+		row := []string{h.Kind, h.File, h.Block, h.BlockType, lineCell(h.StartLine), lineCell(h.EndLine),
+			h.Language, formatFloat(h.Score)}
+		for _, days := range r.Options.Windows {
+			row = append(row, strconv.Itoa(h.Changes[windowKey(days)]))
+		}
+		row = append(row, formatFloat(h.DecayedChurn), strconv.Itoa(h.Cyclomatic), strconv.Itoa(h.LOC),
+			strconv.Itoa(h.Incidents), strconv.Itoa(h.Developers), h.TopOwner, formatFloat(h.TopOwnerShare),
+			formatFloat(h.Dispersion), formatFloat(h.Coupling))
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func lineCell(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
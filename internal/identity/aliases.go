@@ -0,0 +1,41 @@
+package identity
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadAliases reads a manual alias file: canonical email -> aliases.
+// An alias containing "@" is an email; anything else is a GitHub login.
+//
+//	alice@acme.com:
+//	  - alice@gmail.com
+//	  - alice-dev        # every email committed by GitHub user alice-dev
+func LoadAliases(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alias file: %w", err)
+	}
+
+	var raw map[string][]string
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse alias file %s: %w", path, err)
+	}
+
+	aliases := make(map[string][]string, len(raw))
+	for canonical, list := range raw {
+		canonical = strings.ToLower(strings.TrimSpace(canonical))
+		if !strings.Contains(canonical, "@") {
+			return nil, fmt.Errorf("alias file %s: key %q must be an email", path, canonical)
+		}
+		for _, alias := range list {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				aliases[canonical] = append(aliases[canonical], strings.ToLower(strings.TrimPrefix(alias, "@")))
+			}
+		}
+	}
+	return aliases, nil
+}
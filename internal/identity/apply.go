@@ -0,0 +1,74 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// Reindexer recalculates ownership for specific blocks (implemented by risk.BlockIndexer)
+type Reindexer interface {
+	IndexOwnershipIncremental(ctx context.Context, repoID int64, blockIDs []int64) error
+}
+
+// ApplyResult summarizes an Apply run
+type ApplyResult struct {
+	database.AliasApplyResult
+	Applied     []database.DeveloperAlias // Aliases applied in this run, with final canonical emails
+	GraphSynced bool
+}
+
+// Apply merges accepted aliases in staging, recalculates ownership of the affected
+// blocks and, when a graph is given, merges the Developer nodes and refreshes the blocks
+func Apply(ctx context.Context, db *sqlx.DB, repoID int64, reindexer Reindexer, exec GraphExecutor) (*ApplyResult, error) {
+	accepted, err := database.ListDeveloperAliases(ctx, db, repoID, database.AliasAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := database.ApplyDeveloperAliases(ctx, db, repoID)
+	if err != nil {
+		return nil, err
+	}
+	result := &ApplyResult{AliasApplyResult: *applied}
+	if applied.Aliases == 0 {
+		return result, nil
+	}
+
+	// Re-read to pick up canonical emails resolved through alias chains
+	all, err := database.ListDeveloperAliases(ctx, db, repoID, database.AliasApplied)
+	if err != nil {
+		return nil, err
+	}
+	justApplied := make(map[string]bool, len(accepted))
+	for _, a := range accepted {
+		justApplied[a.AliasEmail] = true
+	}
+	for _, a := range all {
+		if justApplied[a.AliasEmail] {
+			result.Applied = append(result.Applied, a)
+		}
+	}
+
+	if err := reindexer.IndexOwnershipIncremental(ctx, repoID, applied.BlockIDs); err != nil {
+		return nil, fmt.Errorf("failed to reindex ownership: %w", err)
+	}
+
+	if exec == nil {
+		return result, nil
+	}
+	if err := MergeGraphDevelopers(ctx, exec, repoID, result.Applied); err != nil {
+		return result, err
+	}
+	blocks, err := database.GetBlockOwnershipProps(ctx, db, repoID, applied.BlockIDs)
+	if err != nil {
+		return result, err
+	}
+	if err := SyncBlockOwnership(ctx, exec, repoID, blocks); err != nil {
+		return result, err
+	}
+	result.GraphSynced = true
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/graph"
+)
+
+// GraphExecutor runs parameterized write queries (implemented by graph.Neo4jBackend)
+type GraphExecutor interface {
+	ExecuteBatchWithParams(ctx context.Context, queries []graph.QueryWithParams) error
+}
+
+// developerRelationships are the edge types that start at a Developer node
+var developerRelationships = []string{"AUTHORED", "CREATED"}
+
+// MergeGraphDevelopers folds each alias Developer node into its canonical node for one
+// repository: the repository's edges are moved and author_email properties rewritten.
+// Developer nodes are shared across repositories, so the alias node is only deleted
+// once no other repository's edges remain on it.
+func MergeGraphDevelopers(ctx context.Context, exec GraphExecutor, repoID int64, aliases []database.DeveloperAlias) error {
+	for _, a := range aliases {
+		params := map[string]any{
+			"repoID":    repoID,
+			"aliases":   graphEmails(a.AliasEmail),
+			"canonical": a.CanonicalEmail,
+		}
+
+		queries := []graph.QueryWithParams{{
+			Query: `
+				MATCH (a:Developer)
+				WHERE toLower(a.email) IN $aliases
+				WITH a LIMIT 1
+				MERGE (c:Developer {email: $canonical})
+				ON CREATE SET c.repo_id = $repoID, c.name = a.name`,
+			Params: params,
+		}}
+		for _, rel := range developerRelationships {
+			queries = append(queries, graph.QueryWithParams{
+				Query: fmt.Sprintf(`
+					MATCH (a:Developer)-[r:%[1]s]->(x)
+					WHERE toLower(a.email) IN $aliases AND x.repo_id = $repoID
+					MATCH (c:Developer {email: $canonical})
+					MERGE (c)-[n:%[1]s]->(x)
+					SET n += properties(r)
+					DELETE r`, rel),
+				Params: params,
+			})
+		}
+		queries = append(queries,
+			graph.QueryWithParams{
+				Query: `
+					MATCH (n)
+					WHERE (n:Commit OR n:PR) AND n.repo_id = $repoID AND toLower(n.author_email) IN $aliases
+					SET n.author_email = $canonical`,
+				Params: params,
+			},
+			graph.QueryWithParams{
+				Query: `
+					MATCH (a:Developer)
+					WHERE toLower(a.email) IN $aliases AND a.email <> $canonical AND NOT (a)--()
+					DELETE a`,
+				Params: params,
+			},
+		)
+
+		if err := exec.ExecuteBatchWithParams(ctx, queries); err != nil {
+			return fmt.Errorf("failed to merge developer %s into %s: %w", a.AliasEmail, a.CanonicalEmail, err)
+		}
+	}
+	return nil
+}
+
+// SyncBlockOwnership copies recalculated ownership columns to CodeBlock nodes
+// Same properties as sync.OwnershipPropSyncer, limited to the given blocks.
+func SyncBlockOwnership(ctx context.Context, exec GraphExecutor, repoID int64, blocks []database.BlockOwnershipProps) error {
+	queries := make([]graph.QueryWithParams, 0, len(blocks))
+	for _, b := range blocks {
+		var lastModified any
+		if b.LastModifiedDate != nil {
+			lastModified = b.LastModifiedDate.Format(time.RFC3339)
+		}
+		queries = append(queries, graph.QueryWithParams{
+			Query: `
+				MATCH (b:CodeBlock {db_id: $blockID, repo_id: $repoID})
+				SET b.original_author_email = $originalAuthor,
+				    b.last_modifier_email = $lastModifier,
+				    b.last_modified_date = CASE WHEN $lastModifiedDate IS NOT NULL THEN datetime($lastModifiedDate) ELSE NULL END,
+				    b.familiarity_map_json = $familiarityMapJSON`,
+			Params: map[string]any{
+				"blockID":            b.ID,
+				"repoID":             repoID,
+				"originalAuthor":     b.OriginalAuthorEmail,
+				"lastModifier":       b.LastModifierEmail,
+				"lastModifiedDate":   lastModified,
+				"familiarityMapJSON": b.FamiliarityMapJSON,
+			},
+		})
+	}
+	if len(queries) == 0 {
+		return nil
+	}
+	if err := exec.ExecuteBatchWithParams(ctx, queries); err != nil {
+		return fmt.Errorf("failed to sync block ownership to graph: %w", err)
+	}
+	return nil
+}
+
+// graphEmails returns the forms an email may have in the graph; the graph builder
+// strips the numeric ID from noreply addresses (graph.normalizeGitHubEmail)
+func graphEmails(email string) []string {
+	email = strings.ToLower(email)
+	forms := []string{email}
+	if login := NoreplyLogin(email); login != "" && strings.Contains(email, "+") {
+		forms = append(forms, login+"@users.noreply.github.com")
+	}
+	return forms
+}
@@ -0,0 +1,360 @@
+// Package identity unifies the emails one developer commits under (work, personal,
+// GitHub noreply) into a canonical email, so familiarity maps and bus factor are not
+// split across what looks like several people.
+//
+// Confident evidence is merged directly: .mailmap entries, a manual alias file, and
+// emails that GitHub attributes to the same login. Developers who merely share a
+// name are only suggested, and must be accepted with `crisk identity review`.
+package identity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// Alias sources (developer_aliases.source)
+const (
+	SourceAliasFile   = "alias_file"
+	SourceMailmap     = "mailmap"
+	SourceGitHubLogin = "github_login"
+	SourceNoreply     = "noreply"
+	SourceFuzzyName   = "fuzzy_name"
+)
+
+// Confidence per source; fuzzy matches below 1 are suggestions only
+var sourceConfidence = map[string]float64{
+	SourceAliasFile:   1.0,
+	SourceMailmap:     1.0,
+	SourceGitHubLogin: 0.95,
+	SourceNoreply:     0.9,
+}
+
+const (
+	sameNameConfidence      = 0.7
+	nameLikeEmailConfidence = 0.6
+)
+
+// Rules are explicit identity assertions from the repository
+type Rules struct {
+	Mailmap []MailmapEntry
+	Aliases map[string][]string // Canonical email -> alias emails or GitHub logins
+}
+
+// Result is the outcome of resolving a repository's commit authors
+type Result struct {
+	Merges      []database.DeveloperAlias // Confident, status accepted
+	Suggestions []database.DeveloperAlias // Fuzzy name matches, status pending
+}
+
+type link struct {
+	source     string
+	confidence float64
+	reason     string
+}
+
+// resolver is a union-find over lowercase emails
+type resolver struct {
+	parent map[string]string
+	pinned map[string]bool // Emails named as canonical by the alias file or mailmap
+	links  map[string]link // Strongest evidence each email belongs to its group
+}
+
+func newResolver() *resolver {
+	return &resolver{parent: map[string]string{}, pinned: map[string]bool{}, links: map[string]link{}}
+}
+
+func (r *resolver) find(email string) string {
+	if _, ok := r.parent[email]; !ok {
+		r.parent[email] = email
+	}
+	for r.parent[email] != email {
+		r.parent[email] = r.parent[r.parent[email]]
+		email = r.parent[email]
+	}
+	return email
+}
+
+func (r *resolver) union(a, b string, l link) {
+	if a == b {
+		return
+	}
+	r.parent[r.find(a)] = r.find(b)
+	for _, email := range []string{a, b} {
+		if l.confidence > r.links[email].confidence {
+			r.links[email] = l
+		}
+	}
+}
+
+// Resolve groups commit authors into developers
+func Resolve(authors []database.CommitAuthor, rules Rules) Result {
+	r := newResolver()
+	byEmail := make(map[string]database.CommitAuthor, len(authors))
+	byLogin := make(map[string][]string)
+	for _, a := range authors {
+		a.Email = strings.ToLower(a.Email)
+		byEmail[a.Email] = a
+		r.find(a.Email)
+		if login := loginOf(a); login != "" {
+			byLogin[login] = append(byLogin[login], a.Email)
+		}
+	}
+
+	// Manual alias file
+	canonicals := make([]string, 0, len(rules.Aliases))
+	for canonical := range rules.Aliases {
+		canonicals = append(canonicals, canonical)
+	}
+	sort.Strings(canonicals)
+	for _, canonical := range canonicals {
+		r.pinned[canonical] = true
+		for _, alias := range rules.Aliases[canonical] {
+			reason := fmt.Sprintf("listed under %s in alias file", canonical)
+			if strings.Contains(alias, "@") {
+				r.union(alias, canonical, link{SourceAliasFile, sourceConfidence[SourceAliasFile], reason})
+				continue
+			}
+			for _, email := range byLogin[alias] {
+				r.union(email, canonical, link{SourceAliasFile, sourceConfidence[SourceAliasFile], reason + " as @" + alias})
+			}
+		}
+	}
+
+	// .mailmap
+	for _, e := range rules.Mailmap {
+		if e.ProperEmail == "" || e.ProperEmail == e.CommitEmail {
+			continue
+		}
+		r.pinned[e.ProperEmail] = true
+		r.union(e.CommitEmail, e.ProperEmail, link{SourceMailmap, sourceConfidence[SourceMailmap],
+			fmt.Sprintf("mailmap maps <%s> to <%s>", e.CommitEmail, e.ProperEmail)})
+	}
+
+	// Emails GitHub attributes to the same login (commit author data or noreply address)
+	logins := make([]string, 0, len(byLogin))
+	for login := range byLogin {
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	for _, login := range logins {
+		emails := byLogin[login]
+		// Anchor on an email GitHub linked to the login, so noreply addresses are reported as such
+		sort.Slice(emails, func(i, j int) bool {
+			if (byEmail[emails[i]].Login != "") != (byEmail[emails[j]].Login != "") {
+				return byEmail[emails[i]].Login != ""
+			}
+			return emails[i] < emails[j]
+		})
+		for _, email := range emails[1:] {
+			source := SourceGitHubLogin
+			if byEmail[email].Login == "" {
+				source = SourceNoreply
+			}
+			r.union(email, emails[0], link{source, sourceConfidence[source], "same GitHub login @" + login})
+		}
+	}
+
+	groups := make(map[string][]string)
+	for email := range r.parent {
+		root := r.find(email)
+		groups[root] = append(groups[root], email)
+	}
+
+	var result Result
+	canonicalOf := make(map[string]string, len(groups))
+	for root, members := range groups {
+		canonical := r.pickCanonical(members, byEmail)
+		canonicalOf[root] = canonical
+		for _, email := range members {
+			if email == canonical {
+				continue
+			}
+			l := r.links[email]
+			result.Merges = append(result.Merges, database.DeveloperAlias{
+				AliasEmail:     email,
+				CanonicalEmail: canonical,
+				Source:         l.source,
+				Confidence:     l.confidence,
+				Reason:         l.reason,
+				Status:         database.AliasAccepted,
+			})
+		}
+	}
+
+	result.Suggestions = suggestByName(groups, canonicalOf, byEmail)
+	sortAliases(result.Merges)
+	sortAliases(result.Suggestions)
+	return result
+}
+
+// pickCanonical prefers a pinned email, then a real (non-noreply) address, then the most commits
+func (r *resolver) pickCanonical(members []string, byEmail map[string]database.CommitAuthor) string {
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if r.pinned[a] != r.pinned[b] {
+			return r.pinned[a]
+		}
+		if isNoreply(a) != isNoreply(b) {
+			return !isNoreply(a)
+		}
+		if byEmail[a].Commits != byEmail[b].Commits {
+			return byEmail[a].Commits > byEmail[b].Commits
+		}
+		return a < b
+	})
+	return members[0]
+}
+
+// suggestByName proposes merging developers whose names match but who share no other evidence
+func suggestByName(groups map[string][]string, canonicalOf map[string]string, byEmail map[string]database.CommitAuthor) []database.DeveloperAlias {
+	type developer struct {
+		canonical string
+		commits   int
+		names     map[string]bool // Normalized full names
+		locals    map[string]bool // Normalized email local parts
+	}
+
+	var devs []*developer
+	for root, members := range groups {
+		d := &developer{canonical: canonicalOf[root], names: map[string]bool{}, locals: map[string]bool{}}
+		bot := false
+		for _, email := range members {
+			a := byEmail[email]
+			if isBot(email, a.Name) {
+				bot = true
+				break
+			}
+			d.commits += a.Commits
+			if key := nameKey(a.Name); key != "" {
+				d.names[key] = true
+			}
+			if !isNoreply(email) {
+				d.locals[lettersOnly(strings.SplitN(email, "@", 2)[0])] = true
+			}
+		}
+		if !bot {
+			devs = append(devs, d)
+		}
+	}
+	// Larger developers first, so the smaller one is merged into the larger
+	sort.Slice(devs, func(i, j int) bool {
+		if devs[i].commits != devs[j].commits {
+			return devs[i].commits > devs[j].commits
+		}
+		return devs[i].canonical < devs[j].canonical
+	})
+
+	var suggestions []database.DeveloperAlias
+	suggested := make(map[string]bool)
+	for i, big := range devs {
+		for _, small := range devs[i+1:] {
+			if suggested[small.canonical] {
+				continue
+			}
+			confidence, reason := nameMatch(big.names, small.names, big.locals, small.locals)
+			if confidence == 0 {
+				continue
+			}
+			suggested[small.canonical] = true
+			suggestions = append(suggestions, database.DeveloperAlias{
+				AliasEmail:     small.canonical,
+				CanonicalEmail: big.canonical,
+				Source:         SourceFuzzyName,
+				Confidence:     confidence,
+				Reason:         reason,
+				Status:         database.AliasPending,
+			})
+		}
+	}
+	return suggestions
+}
+
+// nameMatch compares two developers by full name, then name against email local part
+func nameMatch(namesA, namesB, localsA, localsB map[string]bool) (float64, string) {
+	for name := range namesA {
+		if namesB[name] {
+			return sameNameConfidence, fmt.Sprintf("same name %q", name)
+		}
+	}
+	for _, pair := range []struct{ names, locals map[string]bool }{{namesA, localsB}, {namesB, localsA}} {
+		for name := range pair.names {
+			for _, form := range nameForms(name) {
+				if pair.locals[form] {
+					return nameLikeEmailConfidence, fmt.Sprintf("email %q matches name %q", form, name)
+				}
+			}
+		}
+	}
+	return 0, ""
+}
+
+// nameKey normalizes a full name to sorted lowercase tokens; single-word names return ""
+func nameKey(name string) string {
+	tokens := strings.FieldsFunc(strings.ToLower(name), func(c rune) bool { return !unicode.IsLetter(c) })
+	if len(tokens) < 2 {
+		return ""
+	}
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+// nameForms returns the email local parts a name commonly produces (alicesmith, smithalice, asmith)
+func nameForms(key string) []string {
+	tokens := strings.Fields(key)
+	if len(tokens) != 2 {
+		return nil
+	}
+	a, b := tokens[0], tokens[1]
+	return []string{a + b, b + a, a[:1] + b, b[:1] + a}
+}
+
+func lettersOnly(s string) string {
+	return strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) {
+			return unicode.ToLower(c)
+		}
+		return -1
+	}, s)
+}
+
+// loginOf returns the GitHub login for an author from commit data or a noreply address
+func loginOf(a database.CommitAuthor) string {
+	if a.Login != "" {
+		return strings.ToLower(a.Login)
+	}
+	return NoreplyLogin(a.Email)
+}
+
+// NoreplyLogin extracts the login from "[id+]login@users.noreply.github.com"
+func NoreplyLogin(email string) string {
+	local, ok := strings.CutSuffix(strings.ToLower(email), "@users.noreply.github.com")
+	if !ok {
+		return ""
+	}
+	if i := strings.Index(local, "+"); i >= 0 {
+		local = local[i+1:]
+	}
+	return local
+}
+
+func isNoreply(email string) bool {
+	return strings.HasSuffix(email, "noreply.github.com")
+}
+
+func isBot(email, name string) bool {
+	return strings.Contains(email, "[bot]") || strings.Contains(strings.ToLower(name), "[bot]") ||
+		email == "noreply@github.com"
+}
+
+func sortAliases(aliases []database.DeveloperAlias) {
+	sort.Slice(aliases, func(i, j int) bool {
+		if aliases[i].CanonicalEmail != aliases[j].CanonicalEmail {
+			return aliases[i].CanonicalEmail < aliases[j].CanonicalEmail
+		}
+		return aliases[i].AliasEmail < aliases[j].AliasEmail
+	})
+}
@@ -0,0 +1,159 @@
+package identity
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/graph"
+)
+
+func TestParseMailmap(t *testing.T) {
+	content := `# comment
+Alice Smith <alice@acme.com>
+<bob@acme.com> <bob@old.example>
+Carol Jones <carol@acme.com> <CJ@home.example>
+Dan Brown <dan@acme.com> Danny <dan@laptop.local> # trailing
+`
+	entries, err := ParseMailmap(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseMailmap: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+
+	want := []MailmapEntry{
+		{ProperName: "Alice Smith", CommitEmail: "alice@acme.com"},
+		{ProperEmail: "bob@acme.com", CommitEmail: "bob@old.example"},
+		{ProperName: "Carol Jones", ProperEmail: "carol@acme.com", CommitEmail: "cj@home.example"},
+		{ProperName: "Dan Brown", ProperEmail: "dan@acme.com", CommitName: "Danny", CommitEmail: "dan@laptop.local"},
+	}
+	for i, w := range want {
+		if entries[i] != w {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], w)
+		}
+	}
+
+	if _, err := ParseMailmap(strings.NewReader("Broken <nope\n")); err == nil {
+		t.Error("expected error for unterminated email")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	authors := []database.CommitAuthor{
+		{Email: "alice@acme.com", Name: "Alice Smith", Login: "alice", Commits: 50},
+		{Email: "alice@gmail.com", Name: "Alice Smith", Login: "alice", Commits: 5},
+		{Email: "123+alice@users.noreply.github.com", Name: "Alice Smith", Commits: 3},
+		{Email: "bob@old.example", Name: "Bob", Commits: 20},
+		{Email: "bob@acme.com", Name: "Bob", Commits: 2},
+		{Email: "carol@home.example", Name: "Carol", Login: "carol-dev", Commits: 4},
+		{Email: "carol@acme.com", Name: "Carol", Commits: 9},
+		{Email: "dan.brown@acme.com", Name: "Dan Brown", Commits: 30},
+		{Email: "dbrown@personal.example", Name: "dbrown", Commits: 2},
+		{Email: "erin@acme.com", Name: "Erin Stone", Commits: 8},
+		{Email: "erin.s@home.example", Name: "Erin Stone", Commits: 1},
+		{Email: "dependabot[bot]@users.noreply.github.com", Name: "dependabot[bot]", Login: "dependabot[bot]", Commits: 40},
+	}
+	rules := Rules{
+		Mailmap: []MailmapEntry{{ProperEmail: "bob@acme.com", CommitEmail: "bob@old.example"}},
+		Aliases: map[string][]string{"carol@acme.com": {"carol-dev"}},
+	}
+
+	result := Resolve(authors, rules)
+
+	merges := make(map[string]database.DeveloperAlias)
+	for _, m := range result.Merges {
+		merges[m.AliasEmail] = m
+	}
+	tests := []struct {
+		alias, canonical, source string
+	}{
+		{"alice@gmail.com", "alice@acme.com", SourceGitHubLogin},
+		{"123+alice@users.noreply.github.com", "alice@acme.com", SourceNoreply},
+		{"bob@old.example", "bob@acme.com", SourceMailmap}, // pinned despite fewer commits
+		{"carol@home.example", "carol@acme.com", SourceAliasFile},
+	}
+	for _, tt := range tests {
+		m, ok := merges[tt.alias]
+		if !ok {
+			t.Errorf("missing merge for %s", tt.alias)
+			continue
+		}
+		if m.CanonicalEmail != tt.canonical || m.Source != tt.source || m.Status != database.AliasAccepted {
+			t.Errorf("merge for %s = %+v, want canonical %s from %s", tt.alias, m, tt.canonical, tt.source)
+		}
+	}
+	if len(result.Merges) != len(tests) {
+		t.Errorf("expected %d merges, got %+v", len(tests), result.Merges)
+	}
+
+	suggestions := make(map[string]database.DeveloperAlias)
+	for _, s := range result.Suggestions {
+		suggestions[s.AliasEmail] = s
+		if s.Status != database.AliasPending || s.Source != SourceFuzzyName {
+			t.Errorf("suggestion must be a pending fuzzy match: %+v", s)
+		}
+	}
+	if s := suggestions["erin.s@home.example"]; s.CanonicalEmail != "erin@acme.com" || s.Confidence != sameNameConfidence {
+		t.Errorf("expected same-name suggestion for erin, got %+v", s)
+	}
+	if s := suggestions["dbrown@personal.example"]; s.CanonicalEmail != "dan.brown@acme.com" || s.Confidence != nameLikeEmailConfidence {
+		t.Errorf("expected name-like-email suggestion for dan, got %+v", s)
+	}
+	if len(result.Suggestions) != 2 {
+		t.Errorf("expected 2 suggestions, got %+v", result.Suggestions)
+	}
+}
+
+func TestNoreplyLogin(t *testing.T) {
+	tests := map[string]string{
+		"38349974+EddyDavies@users.noreply.github.com": "eddydavies",
+		"eddydavies@users.noreply.github.com":          "eddydavies",
+		"eddy@example.com":                             "",
+	}
+	for email, want := range tests {
+		if got := NoreplyLogin(email); got != want {
+			t.Errorf("NoreplyLogin(%q) = %q, want %q", email, got, want)
+		}
+	}
+}
+
+type recordingGraph struct {
+	queries []graph.QueryWithParams
+}
+
+func (g *recordingGraph) ExecuteBatchWithParams(ctx context.Context, queries []graph.QueryWithParams) error {
+	g.queries = append(g.queries, queries...)
+	return nil
+}
+
+func TestMergeGraphDevelopers(t *testing.T) {
+	g := &recordingGraph{}
+	aliases := []database.DeveloperAlias{{AliasEmail: "38349974+eddy@users.noreply.github.com", CanonicalEmail: "eddy@acme.com"}}
+
+	if err := MergeGraphDevelopers(context.Background(), g, 7, aliases); err != nil {
+		t.Fatalf("MergeGraphDevelopers: %v", err)
+	}
+
+	// Ensure canonical, move AUTHORED and CREATED, rewrite author_email, delete alias
+	if len(g.queries) != 5 {
+		t.Fatalf("expected 5 queries, got %d", len(g.queries))
+	}
+	emails := g.queries[0].Params["aliases"].([]string)
+	if len(emails) != 2 || emails[1] != "eddy@users.noreply.github.com" {
+		t.Errorf("expected normalized noreply form to be matched, got %v", emails)
+	}
+	if !strings.Contains(g.queries[1].Query, "[r:AUTHORED]") || !strings.Contains(g.queries[2].Query, "[r:CREATED]") {
+		t.Error("expected AUTHORED and CREATED edges to be moved")
+	}
+	for _, q := range g.queries[1:3] {
+		if !strings.Contains(q.Query, "x.repo_id = $repoID") {
+			t.Errorf("expected edge move to be limited to the repository:%s", q.Query)
+		}
+	}
+	if q := g.queries[4].Query; !strings.Contains(q, "NOT (a)--()") || strings.Contains(q, "DETACH") {
+		t.Errorf("expected alias node to be deleted last, only once it has no edges:%s", q)
+	}
+}
@@ -0,0 +1,95 @@
+package identity
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// MailmapEntry is one .mailmap line
+// Forms: "Proper Name <commit>", "<proper> <commit>", "Proper Name <proper> <commit>"
+// and "Proper Name <proper> Commit Name <commit>".
+type MailmapEntry struct {
+	ProperName  string
+	ProperEmail string // Empty when the entry only fixes the name
+	CommitName  string
+	CommitEmail string
+}
+
+// LoadMailmap reads a .mailmap file; a missing file yields no entries
+func LoadMailmap(path string) ([]MailmapEntry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mailmap: %w", err)
+	}
+	defer f.Close()
+	return ParseMailmap(f)
+}
+
+// ParseMailmap parses git's .mailmap format
+func ParseMailmap(r io.Reader) ([]MailmapEntry, error) {
+	var entries []MailmapEntry
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		names, emails, err := splitMailmapLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("mailmap line %d: %w", lineNo, err)
+		}
+
+		entry := MailmapEntry{ProperName: names[0]}
+		switch len(emails) {
+		case 1:
+			entry.CommitEmail = emails[0]
+		case 2:
+			entry.ProperEmail = emails[0]
+			entry.CommitName = names[1]
+			entry.CommitEmail = emails[1]
+		default:
+			return nil, fmt.Errorf("mailmap line %d: expected one or two emails, got %d", lineNo, len(emails))
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mailmap: %w", err)
+	}
+	return entries, nil
+}
+
+// splitMailmapLine returns the name before each <email> and the lowercased emails
+func splitMailmapLine(line string) (names []string, emails []string, err error) {
+	rest := line
+	for {
+		open := strings.Index(rest, "<")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(rest[open:], ">")
+		if end < 0 {
+			return nil, nil, fmt.Errorf("unterminated email in %q", strings.TrimSpace(line))
+		}
+		names = append(names, strings.TrimSpace(rest[:open]))
+		emails = append(emails, strings.ToLower(strings.TrimSpace(rest[open+1:open+end])))
+		rest = rest[open+end+1:]
+	}
+	if len(emails) == 0 {
+		return nil, nil, fmt.Errorf("no email in %q", strings.TrimSpace(line))
+	}
+	return names, emails, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 type GraphClient interface {
 	CreateNode(node GraphNode) (string, error)
 	CreateEdge(edge GraphEdge) error
+	QueryWithParams(query string, params map[string]interface{}) ([]map[string]interface{}, error)
 }
 
 // GraphNode represents a node in the graph
@@ -39,6 +41,7 @@ type Linker struct {
 }
 
 // NewLinker creates a new incident linker
+// graph may be nil when Neo4j is unavailable; links are then stored in PostgreSQL only
 func NewLinker(db *Database, graph GraphClient) *Linker {
 	return &Linker{
 		db:    db,
@@ -48,6 +51,16 @@ func NewLinker(db *Database, graph GraphClient) *Linker {
 
 // LinkIncident creates link between incident and file (CLI command)
 func (l *Linker) LinkIncident(ctx context.Context, incidentID string, filePath string, lineNumber int, function string) error {
+	return l.linkIncident(ctx, incidentID, filePath, lineNumber, function, 1.0) // Manual link = 100% confidence
+}
+
+// ApplySuggestion links an incident to a suggested file, keeping the suggestion's confidence
+func (l *Linker) ApplySuggestion(ctx context.Context, incidentID string, suggestion SuggestionResult) error {
+	return l.linkIncident(ctx, incidentID, suggestion.FilePath, 0, "", suggestion.Confidence)
+}
+
+// linkIncident writes the incident_files row and mirrors it as a CAUSED_BY edge in the graph
+func (l *Linker) linkIncident(ctx context.Context, incidentID string, filePath string, lineNumber int, function string, confidence float64) error {
 	// Parse incident ID
 	id, err := uuid.Parse(incidentID)
 	if err != nil {
@@ -66,13 +79,19 @@ func (l *Linker) LinkIncident(ctx context.Context, incidentID string, filePath s
 		FilePath:       filePath,
 		LineNumber:     lineNumber,
 		BlamedFunction: function,
-		Confidence:     1.0, // Manual link = 100% confidence
+		Confidence:     confidence,
 	}
 
 	if err := l.db.LinkIncidentToFile(ctx, link); err != nil {
 		return fmt.Errorf("create database link: %w", err)
 	}
 
+	// Graph is optional: PostgreSQL is the source of truth for crisk check,
+	// the CAUSED_BY edge is rebuilt on the next full graph build otherwise
+	if l.graph == nil {
+		return nil
+	}
+
 	// Create Incident node in Neo4j if it doesn't exist
 	incidentNode := GraphNode{
 		Label: "Incident",
@@ -148,9 +167,17 @@ func (l *Linker) UnlinkIncident(ctx context.Context, incidentID string, filePath
 		return fmt.Errorf("remove database link: %w", err)
 	}
 
-	// Note: We don't remove the Neo4j edge here as it requires a more complex query
-	// The edge will be cleaned up during next full graph rebuild
-	// For manual cleanup, use: MATCH (i:Incident {id: $id})-[r:CAUSED_BY]->(f:File {path: $path}) DELETE r
+	// Skip graph if not available
+	if l.graph == nil {
+		return nil
+	}
+
+	// Delete CAUSED_BY edge in Neo4j, so risk queries stop counting the incident against the file
+	query := `MATCH (i:Incident {id: $id})-[r:CAUSED_BY]->(f:File {path: $path}) DELETE r`
+	params := map[string]interface{}{"id": id.String(), "path": filePath}
+	if _, err := l.graph.QueryWithParams(query, params); err != nil {
+		return fmt.Errorf("delete CAUSED_BY edge: incident=%s file=%s: %w", id, filePath, err)
+	}
 
 	return nil
 }
@@ -231,13 +258,46 @@ func (l *Linker) extractFilePaths(text string) []string {
 
 // SuggestionResult represents a suggested file link
 type SuggestionResult struct {
-	FilePath   string
-	Confidence float64
-	Reason     string
+	FilePath   string  `json:"file_path"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+}
+
+// ParseLinkTarget splits a link target into file path, line number and function
+// Accepted forms: "path/file.go", "path/file.go:42", "path/file.go:ProcessRefund"
+func ParseLinkTarget(target string) (filePath string, lineNumber int, function string, err error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return "", 0, "", fmt.Errorf("link target cannot be empty")
+	}
+
+	idx := strings.LastIndex(target, ":")
+	if idx < 0 {
+		return target, 0, "", nil
+	}
+
+	filePath = target[:idx]
+	suffix := target[idx+1:]
+	if filePath == "" || suffix == "" {
+		return "", 0, "", fmt.Errorf("invalid link target %q (expected path[:line|:function])", target)
+	}
+
+	if n, convErr := strconv.Atoi(suffix); convErr == nil {
+		if n <= 0 {
+			return "", 0, "", fmt.Errorf("invalid line number in link target %q", target)
+		}
+		return filePath, n, "", nil
+	}
+
+	return filePath, 0, suffix, nil
 }
 
 // CreateIncidentNode creates an Incident node in Neo4j
 func (l *Linker) CreateIncidentNode(ctx context.Context, incident *Incident) error {
+	if l.graph == nil {
+		return nil
+	}
+
 	node := GraphNode{
 		Label: "Incident",
 		ID:    incident.ID.String(),
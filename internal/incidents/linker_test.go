@@ -0,0 +1,157 @@
+package incidents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingGraph captures graph writes made by the linker
+type recordingGraph struct {
+	nodes   []GraphNode
+	edges   []GraphEdge
+	queries []map[string]interface{}
+}
+
+func (g *recordingGraph) CreateNode(node GraphNode) (string, error) {
+	g.nodes = append(g.nodes, node)
+	return node.ID, nil
+}
+
+func (g *recordingGraph) CreateEdge(edge GraphEdge) error {
+	g.edges = append(g.edges, edge)
+	return nil
+}
+
+func (g *recordingGraph) QueryWithParams(query string, params map[string]interface{}) ([]map[string]interface{}, error) {
+	g.queries = append(g.queries, params)
+	return nil, nil
+}
+
+func TestParseLinkTarget(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		wantPath     string
+		wantLine     int
+		wantFunction string
+		wantError    bool
+	}{
+		{"file only", "payments/charge.go", "payments/charge.go", 0, "", false},
+		{"file and function", "payments/charge.go:ProcessRefund", "payments/charge.go", 0, "ProcessRefund", false},
+		{"file and line", "payments/charge.go:42", "payments/charge.go", 42, "", false},
+		{"surrounding whitespace", "  api/handler.py:handle  ", "api/handler.py", 0, "handle", false},
+		{"empty", "", "", 0, "", true},
+		{"missing suffix", "payments/charge.go:", "", 0, "", true},
+		{"missing path", ":ProcessRefund", "", 0, "", true},
+		{"zero line", "payments/charge.go:0", "", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, line, function, err := ParseLinkTarget(tt.target)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, path)
+			assert.Equal(t, tt.wantLine, line)
+			assert.Equal(t, tt.wantFunction, function)
+		})
+	}
+}
+
+func TestLinkIncident_WithoutGraph(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	incDB := NewDatabase(db)
+	ctx := context.Background()
+
+	incident := &Incident{
+		Title:       "Refund double-charge",
+		Description: "Refunds were applied twice",
+		Severity:    SeverityCritical,
+		OccurredAt:  time.Now(),
+	}
+	require.NoError(t, incDB.CreateIncident(ctx, incident))
+
+	linker := NewLinker(incDB, nil)
+	require.NoError(t, linker.CreateIncidentNode(ctx, incident))
+	require.NoError(t, linker.LinkIncident(ctx, incident.ID.String(), "payments/charge.go", 0, "ProcessRefund"))
+
+	got, err := incDB.GetIncident(ctx, incident.ID)
+	require.NoError(t, err)
+	require.Len(t, got.LinkedFiles, 1)
+	assert.Equal(t, "payments/charge.go", got.LinkedFiles[0].FilePath)
+	assert.Equal(t, "ProcessRefund", got.LinkedFiles[0].BlamedFunction)
+	assert.Equal(t, 1.0, got.LinkedFiles[0].Confidence)
+}
+
+func TestApplySuggestion_KeepsConfidence(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	incDB := NewDatabase(db)
+	ctx := context.Background()
+
+	incident := &Incident{
+		Title:       "Checkout timeout",
+		Description: "Timeouts in checkout.go",
+		Severity:    SeverityHigh,
+		OccurredAt:  time.Now(),
+	}
+	require.NoError(t, incDB.CreateIncident(ctx, incident))
+
+	graph := &recordingGraph{}
+	linker := NewLinker(incDB, graph)
+
+	err := linker.ApplySuggestion(ctx, incident.ID.String(), SuggestionResult{
+		FilePath:   "checkout.go",
+		Confidence: 0.6,
+	})
+	require.NoError(t, err)
+
+	got, err := incDB.GetIncident(ctx, incident.ID)
+	require.NoError(t, err)
+	require.Len(t, got.LinkedFiles, 1)
+	assert.Equal(t, 0.6, got.LinkedFiles[0].Confidence)
+
+	require.Len(t, graph.edges, 1)
+	assert.Equal(t, "CAUSED_BY", graph.edges[0].Label)
+	assert.Equal(t, "file:checkout.go", graph.edges[0].To)
+	assert.Equal(t, 0.6, graph.edges[0].Properties["confidence"])
+}
+
+func TestUnlinkIncident_DeletesGraphEdge(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	incDB := NewDatabase(db)
+	ctx := context.Background()
+
+	incident := &Incident{
+		Title:       "Refund double-charge",
+		Description: "Refunds were applied twice",
+		Severity:    SeverityCritical,
+		OccurredAt:  time.Now(),
+	}
+	require.NoError(t, incDB.CreateIncident(ctx, incident))
+
+	graph := &recordingGraph{}
+	linker := NewLinker(incDB, graph)
+	require.NoError(t, linker.LinkIncident(ctx, incident.ID.String(), "payments/charge.go", 0, ""))
+	require.NoError(t, linker.UnlinkIncident(ctx, incident.ID.String(), "payments/charge.go"))
+
+	got, err := incDB.GetIncident(ctx, incident.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.LinkedFiles)
+
+	require.Len(t, graph.queries, 1)
+	assert.Equal(t, incident.ID.String(), graph.queries[0]["id"])
+	assert.Equal(t, "payments/charge.go", graph.queries[0]["path"])
+}
@@ -65,11 +65,13 @@ func shouldSkipDir(name string) bool {
 	}
 
 	for _, exclude := range excludeDirs {
-		if name == exclude || strings.HasPrefix(name, exclude) {
+		if name == exclude {
 			return true
 		}
 	}
-	return false
+	// Named virtualenvs (venv311, .venv-dev); other names match exactly so that
+	// source directories like "output" or "builder" are not skipped
+	return strings.HasPrefix(name, "venv") || strings.HasPrefix(name, ".venv")
 }
 
 // isSupportedFile returns true if file should be parsed
@@ -82,6 +84,7 @@ func isSupportedFile(path string) bool {
 		".mjs", ".cjs",
 		".mts", ".cts",
 		".py", ".pyi", ".pyw",
+		".go",
 	}
 
 	isSupported := false
@@ -121,6 +124,7 @@ func isGeneratedFile(path string) bool {
 		".d.ts",         // TypeScript declarations (optional: could include)
 		"_pb.js",        // Protocol buffers
 		"_pb.ts",        // Protocol buffers
+		".pb.go",        // Protocol buffers
 	}
 
 	for _, pattern := range generatedPatterns {
@@ -0,0 +1,238 @@
+package knowledge
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// Risk levels, in increasing order
+var riskLevels = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// BlockImpact is how one block changes when the departing developers are removed
+type BlockImpact struct {
+	ID                  int64   `json:"id"`
+	File                string  `json:"file"`
+	Name                string  `json:"name"`
+	Type                string  `json:"type"`
+	StartLine           int     `json:"start_line"`
+	RiskScore           float64 `json:"risk_score"`
+	SMEBefore           string  `json:"sme_before"`
+	SMEAfter            string  `json:"sme_after,omitempty"`
+	BusFactorBefore     string  `json:"bus_factor_before"`
+	BusFactorAfter      string  `json:"bus_factor_after"`
+	RiskBefore          string  `json:"risk_before"`
+	RiskAfter           string  `json:"risk_after"`
+	LostEdits           int     `json:"lost_edits"` // Edits by the departing developers
+	RemainingDevelopers int     `json:"remaining_developers"`
+	Orphaned            bool    `json:"orphaned"`
+}
+
+// RiskIncreased reports whether the block's risk level goes up
+func (b BlockImpact) RiskIncreased() bool {
+	return levelIndex(b.RiskAfter) > levelIndex(b.RiskBefore)
+}
+
+// FileImpact summarizes the affected blocks of one file
+type FileImpact struct {
+	File           string `json:"file"`
+	Blocks         int    `json:"blocks"`
+	AffectedBlocks int    `json:"affected_blocks"`
+	OrphanedBlocks int    `json:"orphaned_blocks"`
+	RiskIncreases  int    `json:"risk_increases"`
+	Orphaned       bool   `json:"orphaned"` // No remaining developer knows any block in the file
+}
+
+// Successor is a remaining developer who becomes SME of blocks the departing developers owned
+type Successor struct {
+	Email  string `json:"email"`
+	Blocks int    `json:"blocks"`
+}
+
+// Departure is the simulated effect of developers leaving
+type Departure struct {
+	Developers     []string      `json:"developers"`
+	TotalBlocks    int           `json:"total_blocks"`
+	AffectedBlocks int           `json:"affected_blocks"` // Blocks a departing developer is familiar with
+	SMEChanges     int           `json:"sme_changes"`
+	OrphanedBlocks int           `json:"orphaned_blocks"`
+	RiskIncreases  int           `json:"risk_increases"`
+	OrphanedFiles  int           `json:"orphaned_files"`
+	Blocks         []BlockImpact `json:"blocks"`
+	Files          []FileImpact  `json:"files"`
+	Successors     []Successor   `json:"successors"`
+}
+
+// SimulateDeparture removes developers from the blocks' familiarity maps in memory and
+// recomputes SME, bus factor and risk level for every block they were familiar with.
+//
+// The stored risk_score does not depend on ownership, so the risk level combines it with
+// ownership concentration: the higher of the score level (as in database.OwnershipStats)
+// and the ownership level (orphaned = CRITICAL, CRITICAL bus factor = HIGH, HIGH = MEDIUM).
+func SimulateDeparture(blocks []database.BlockWithOwnership, developers []string) *Departure {
+	leaving := make(map[string]bool, len(developers))
+	d := &Departure{TotalBlocks: len(blocks)}
+	for _, email := range developers {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email != "" && !leaving[email] {
+			leaving[email] = true
+			d.Developers = append(d.Developers, email)
+		}
+	}
+
+	files := make(map[string]*FileImpact)
+	successors := make(map[string]int)
+	for _, b := range blocks {
+		path := strings.TrimPrefix(b.CanonicalFilePath, "/")
+		file, ok := files[path]
+		if !ok {
+			file = &FileImpact{File: path}
+			files[path] = file
+		}
+		file.Blocks++
+
+		before := make(map[string]int, len(b.FamiliarityMap))
+		after := make(map[string]int, len(b.FamiliarityMap))
+		lost := 0
+		for email, edits := range b.FamiliarityMap {
+			if edits <= 0 {
+				continue
+			}
+			email = strings.ToLower(email)
+			before[email] += edits
+			if leaving[email] {
+				lost += edits
+				continue
+			}
+			after[email] += edits
+		}
+		if lost == 0 {
+			continue
+		}
+
+		smeBefore, busBefore, _ := database.CalculateSME(before)
+		smeAfter, busAfter, _ := database.CalculateSME(after)
+		impact := BlockImpact{
+			ID:                  b.ID,
+			File:                path,
+			Name:                b.BlockName,
+			Type:                b.BlockType,
+			StartLine:           b.StartLine,
+			RiskScore:           b.RiskScore,
+			SMEBefore:           smeBefore,
+			SMEAfter:            smeAfter,
+			BusFactorBefore:     busBefore,
+			BusFactorAfter:      busAfter,
+			RiskBefore:          RiskLevel(b.RiskScore, busBefore, false),
+			RiskAfter:           RiskLevel(b.RiskScore, busAfter, len(after) == 0),
+			LostEdits:           lost,
+			RemainingDevelopers: len(after),
+			Orphaned:            len(after) == 0,
+		}
+
+		d.AffectedBlocks++
+		file.AffectedBlocks++
+		if smeAfter != smeBefore {
+			d.SMEChanges++
+			if smeAfter != "" && leaving[smeBefore] {
+				successors[smeAfter]++
+			}
+		}
+		if impact.Orphaned {
+			d.OrphanedBlocks++
+			file.OrphanedBlocks++
+		}
+		if impact.RiskIncreased() {
+			d.RiskIncreases++
+			file.RiskIncreases++
+		}
+		d.Blocks = append(d.Blocks, impact)
+	}
+
+	for _, f := range files {
+		if f.AffectedBlocks == 0 {
+			continue
+		}
+		f.Orphaned = f.OrphanedBlocks == f.Blocks
+		if f.Orphaned {
+			d.OrphanedFiles++
+		}
+		d.Files = append(d.Files, *f)
+	}
+	for email, n := range successors {
+		d.Successors = append(d.Successors, Successor{Email: email, Blocks: n})
+	}
+
+	sort.Slice(d.Blocks, func(i, j int) bool {
+		a, b := d.Blocks[i], d.Blocks[j]
+		if a.Orphaned != b.Orphaned {
+			return a.Orphaned
+		}
+		if ai, bi := levelIndex(a.RiskAfter)-levelIndex(a.RiskBefore), levelIndex(b.RiskAfter)-levelIndex(b.RiskBefore); ai != bi {
+			return ai > bi
+		}
+		if a.RiskScore != b.RiskScore {
+			return a.RiskScore > b.RiskScore
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartLine < b.StartLine
+	})
+	sort.Slice(d.Files, func(i, j int) bool {
+		a, b := d.Files[i], d.Files[j]
+		if a.Orphaned != b.Orphaned {
+			return a.Orphaned
+		}
+		if a.OrphanedBlocks != b.OrphanedBlocks {
+			return a.OrphanedBlocks > b.OrphanedBlocks
+		}
+		if a.AffectedBlocks != b.AffectedBlocks {
+			return a.AffectedBlocks > b.AffectedBlocks
+		}
+		return a.File < b.File
+	})
+	sort.Slice(d.Successors, func(i, j int) bool {
+		if d.Successors[i].Blocks != d.Successors[j].Blocks {
+			return d.Successors[i].Blocks > d.Successors[j].Blocks
+		}
+		return d.Successors[i].Email < d.Successors[j].Email
+	})
+	return d
+}
+
+// RiskLevel combines a block's risk score with its ownership concentration
+func RiskLevel(riskScore float64, busFactor string, orphaned bool) string {
+	score := "LOW"
+	switch {
+	case riskScore > 70:
+		score = "CRITICAL"
+	case riskScore >= 40:
+		score = "MEDIUM"
+	}
+
+	ownership := "LOW"
+	switch {
+	case orphaned:
+		ownership = "CRITICAL"
+	case busFactor == "CRITICAL":
+		ownership = "HIGH"
+	case busFactor == "HIGH":
+		ownership = "MEDIUM"
+	}
+
+	if levelIndex(ownership) > levelIndex(score) {
+		return ownership
+	}
+	return score
+}
+
+func levelIndex(level string) int {
+	for i, l := range riskLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
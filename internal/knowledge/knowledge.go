@@ -0,0 +1,341 @@
+// Package knowledge rolls block ownership up the directory tree to show where
+// knowledge is concentrated in few developers or has already left the team.
+//
+// A developer has departed when they have no commits in the repository within
+// Options.DepartedDays. Bus factor follows the truck-factor definition: the fewest
+// active developers whose departure would leave more than half of a directory's
+// blocks with no active familiar developer (0 = that has already happened).
+package knowledge
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// Options controls the report thresholds
+type Options struct {
+	StaleDays    int       `json:"stale_days"`    // Ownership is stale when the block's top owner has no commits for this long
+	DepartedDays int       `json:"departed_days"` // Developers with no commits for this long have left
+	CriticalRisk float64   `json:"critical_risk"` // Blocks above this risk score (or with incidents) are critical
+	MaxDepth     int       `json:"max_depth"`     // Deepest directory level reported (0 = unlimited)
+	TopBlocks    int       `json:"top_blocks"`    // Cap on blocks listed per finding (0 = all)
+	Now          time.Time `json:"as_of"`         // Reference time for activity windows (zero = time.Now)
+}
+
+// DefaultOptions returns the standard report thresholds
+func DefaultOptions() Options {
+	return Options{
+		StaleDays:    90,
+		DepartedDays: 180,
+		CriticalRisk: 70,
+		MaxDepth:     3,
+		TopBlocks:    25,
+	}
+}
+
+// DirectoryStats is the knowledge distribution of one directory, including subdirectories
+type DirectoryStats struct {
+	Path                 string  `json:"path"`
+	Files                int     `json:"files"`
+	Blocks               int     `json:"blocks"`
+	BusFactor            int     `json:"bus_factor"`
+	TopOwner             string  `json:"top_owner,omitempty"`
+	TopOwnerShare        float64 `json:"top_owner_share"`     // Share of the directory's edits
+	ConcentratedBlocks   int     `json:"concentrated_blocks"` // CRITICAL or HIGH bus factor per database.CalculateSME
+	StaleBlocks          int     `json:"stale_blocks"`
+	SingleAuthorCritical int     `json:"single_author_critical"`
+	OrphanedBlocks       int     `json:"orphaned_blocks"`
+}
+
+// BlockFinding is a block flagged by the report
+type BlockFinding struct {
+	ID              int64      `json:"id"`
+	File            string     `json:"file"`
+	Name            string     `json:"name"`
+	Type            string     `json:"type"`
+	StartLine       int        `json:"start_line"`
+	Owner           string     `json:"owner"`
+	OwnerLastCommit *time.Time `json:"owner_last_commit,omitempty"`
+	Developers      int        `json:"developers"`
+	RiskScore       float64    `json:"risk_score"`
+	IncidentCount   int        `json:"incident_count"`
+}
+
+// Developer is a departed developer and the knowledge they took with them
+type Developer struct {
+	Email      string     `json:"email"`
+	LastCommit *time.Time `json:"last_commit,omitempty"`
+	Blocks     int        `json:"blocks"`      // Blocks they are familiar with
+	SoleBlocks int        `json:"sole_blocks"` // Blocks no active developer is familiar with
+}
+
+// Report is the knowledge-loss report for a repository
+type Report struct {
+	GeneratedAt          time.Time        `json:"generated_at"`
+	Options              Options          `json:"options"`
+	Summary              DirectoryStats   `json:"summary"`
+	Directories          []DirectoryStats `json:"directories"`
+	Orphaned             []BlockFinding   `json:"orphaned"` // Every familiar developer has departed
+	SingleAuthorCritical []BlockFinding   `json:"single_author_critical"`
+	StaleOwnership       []BlockFinding   `json:"stale_ownership"`
+	Departed             []Developer      `json:"departed"`
+}
+
+// directory accumulates the blocks under one path
+type directory struct {
+	stats  DirectoryStats
+	files  map[string]bool
+	edits  map[string]int
+	blocks []map[string]bool // Active familiar developers per block
+}
+
+// Build computes the report from block ownership and each developer's last commit
+func Build(blocks []database.BlockWithOwnership, lastCommit map[string]time.Time, opts Options) *Report {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	departedBefore := now.AddDate(0, 0, -opts.DepartedDays)
+	staleBefore := now.AddDate(0, 0, -opts.StaleDays)
+	departed := func(email string) bool {
+		at, ok := lastCommit[email]
+		return !ok || at.Before(departedBefore)
+	}
+
+	report := &Report{GeneratedAt: now, Options: opts}
+	dirs := make(map[string]*directory)
+	leavers := make(map[string]*Developer)
+
+	for _, b := range blocks {
+		file := strings.TrimPrefix(b.CanonicalFilePath, "/")
+		familiarity := make(map[string]int, len(b.FamiliarityMap))
+		for email, edits := range b.FamiliarityMap {
+			if edits > 0 {
+				familiarity[strings.ToLower(email)] += edits
+			}
+		}
+		owner, busFactor, _ := database.CalculateSME(familiarity)
+
+		active := make(map[string]bool, len(familiarity))
+		for email := range familiarity {
+			if !departed(email) {
+				active[email] = true
+			}
+		}
+		orphaned := len(familiarity) > 0 && len(active) == 0
+		critical := b.RiskScore > opts.CriticalRisk || b.IncidentCount > 0
+		singleAuthorCritical := len(familiarity) == 1 && critical
+		stale := false
+		if owner != "" {
+			at, ok := lastCommit[owner]
+			stale = !ok || at.Before(staleBefore)
+		}
+
+		for email := range familiarity {
+			if !departed(email) {
+				continue
+			}
+			d, ok := leavers[email]
+			if !ok {
+				d = &Developer{Email: email}
+				if at, ok := lastCommit[email]; ok {
+					d.LastCommit = &at
+				}
+				leavers[email] = d
+			}
+			d.Blocks++
+			if orphaned {
+				d.SoleBlocks++
+			}
+		}
+
+		finding := BlockFinding{
+			ID:            b.ID,
+			File:          file,
+			Name:          b.BlockName,
+			Type:          b.BlockType,
+			StartLine:     b.StartLine,
+			Owner:         owner,
+			Developers:    len(familiarity),
+			RiskScore:     b.RiskScore,
+			IncidentCount: b.IncidentCount,
+		}
+		if at, ok := lastCommit[owner]; ok {
+			finding.OwnerLastCommit = &at
+		}
+		if orphaned {
+			report.Orphaned = append(report.Orphaned, finding)
+		}
+		if singleAuthorCritical {
+			report.SingleAuthorCritical = append(report.SingleAuthorCritical, finding)
+		}
+		if stale && !orphaned {
+			report.StaleOwnership = append(report.StaleOwnership, finding)
+		}
+
+		for _, dirPath := range ancestors(truncateDir(path.Dir(file), opts.MaxDepth)) {
+			d, ok := dirs[dirPath]
+			if !ok {
+				d = &directory{stats: DirectoryStats{Path: dirPath}, files: map[string]bool{}, edits: map[string]int{}}
+				dirs[dirPath] = d
+			}
+			d.files[file] = true
+			d.blocks = append(d.blocks, active)
+			for email, edits := range familiarity {
+				d.edits[email] += edits
+			}
+			d.stats.Blocks++
+			if busFactor == "CRITICAL" || busFactor == "HIGH" {
+				d.stats.ConcentratedBlocks++
+			}
+			if stale {
+				d.stats.StaleBlocks++
+			}
+			if singleAuthorCritical {
+				d.stats.SingleAuthorCritical++
+			}
+			if orphaned {
+				d.stats.OrphanedBlocks++
+			}
+		}
+	}
+
+	for _, d := range dirs {
+		d.stats.Files = len(d.files)
+		d.stats.BusFactor = truckFactor(d.blocks)
+		d.stats.TopOwner, d.stats.TopOwnerShare = topOwner(d.edits)
+		if d.stats.Path == "." {
+			report.Summary = d.stats
+			continue
+		}
+		report.Directories = append(report.Directories, d.stats)
+	}
+	if report.Summary.Path == "" {
+		report.Summary.Path = "."
+	}
+	sort.Slice(report.Directories, func(i, j int) bool {
+		return report.Directories[i].Path < report.Directories[j].Path
+	})
+
+	for _, d := range leavers {
+		report.Departed = append(report.Departed, *d)
+	}
+	sort.Slice(report.Departed, func(i, j int) bool {
+		a, b := report.Departed[i], report.Departed[j]
+		if a.SoleBlocks != b.SoleBlocks {
+			return a.SoleBlocks > b.SoleBlocks
+		}
+		if a.Blocks != b.Blocks {
+			return a.Blocks > b.Blocks
+		}
+		return a.Email < b.Email
+	})
+
+	report.Orphaned = topFindings(report.Orphaned, opts.TopBlocks)
+	report.SingleAuthorCritical = topFindings(report.SingleAuthorCritical, opts.TopBlocks)
+	report.StaleOwnership = topFindings(report.StaleOwnership, opts.TopBlocks)
+	return report
+}
+
+// truckFactor greedily removes the developer familiar with the most blocks until
+// more than half of the blocks have no familiar developer left
+func truckFactor(blocks []map[string]bool) int {
+	remaining := make([]map[string]bool, len(blocks))
+	for i, devs := range blocks {
+		remaining[i] = make(map[string]bool, len(devs))
+		for email := range devs {
+			remaining[i][email] = true
+		}
+	}
+
+	removed := 0
+	for {
+		uncovered := 0
+		coverage := make(map[string]int)
+		for _, devs := range remaining {
+			if len(devs) == 0 {
+				uncovered++
+			}
+			for email := range devs {
+				coverage[email]++
+			}
+		}
+		if uncovered*2 > len(remaining) || len(coverage) == 0 {
+			return removed
+		}
+
+		next := ""
+		for email, n := range coverage {
+			if next == "" || n > coverage[next] || (n == coverage[next] && email < next) {
+				next = email
+			}
+		}
+		for _, devs := range remaining {
+			delete(devs, next)
+		}
+		removed++
+	}
+}
+
+func topOwner(edits map[string]int) (string, float64) {
+	owner, max, total := "", 0, 0
+	for email, n := range edits {
+		total += n
+		if n > max || (n == max && email < owner) {
+			owner, max = email, n
+		}
+	}
+	if total == 0 {
+		return "", 0
+	}
+	return owner, float64(max) / float64(total)
+}
+
+// topFindings orders findings by risk, then incidents, and keeps the first limit
+func topFindings(findings []BlockFinding, limit int) []BlockFinding {
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.RiskScore != b.RiskScore {
+			return a.RiskScore > b.RiskScore
+		}
+		if a.IncidentCount != b.IncidentCount {
+			return a.IncidentCount > b.IncidentCount
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.StartLine < b.StartLine
+	})
+	if limit > 0 && len(findings) > limit {
+		findings = findings[:limit]
+	}
+	return findings
+}
+
+// ancestors returns dir and every parent up to the root "."
+func ancestors(dir string) []string {
+	dirs := []string{"."}
+	if dir == "." || dir == "" {
+		return dirs
+	}
+	parts := strings.Split(dir, "/")
+	for i := range parts {
+		dirs = append(dirs, strings.Join(parts[:i+1], "/"))
+	}
+	return dirs
+}
+
+func truncateDir(dir string, depth int) string {
+	if depth <= 0 || dir == "." {
+		return dir
+	}
+	parts := strings.Split(dir, "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
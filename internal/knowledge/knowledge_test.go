@@ -0,0 +1,140 @@
+package knowledge
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	lastCommit := map[string]time.Time{
+		"alice@acme.com": now.AddDate(0, 0, -5),
+		"bob@acme.com":   now.AddDate(0, 0, -120), // Quiet, not yet departed
+		"carol@acme.com": now.AddDate(-1, 0, 0),   // Departed
+	}
+	blocks := []database.BlockWithOwnership{
+		{ID: 1, BlockName: "Charge", CanonicalFilePath: "/internal/billing/charge.go", RiskScore: 85,
+			FamiliarityMap: map[string]int{"Carol@acme.com": 12}},
+		{ID: 2, BlockName: "Refund", CanonicalFilePath: "internal/billing/refund.go",
+			FamiliarityMap: map[string]int{"carol@acme.com": 3, "bob@acme.com": 6}},
+		{ID: 3, BlockName: "Login", CanonicalFilePath: "internal/auth/login.go", IncidentCount: 2,
+			FamiliarityMap: map[string]int{"alice@acme.com": 9}},
+		{ID: 4, BlockName: "main", CanonicalFilePath: "main.go",
+			FamiliarityMap: map[string]int{"alice@acme.com": 2, "bob@acme.com": 2}},
+	}
+
+	opts := DefaultOptions()
+	opts.Now = now
+	r := Build(blocks, lastCommit, opts)
+
+	if r.Summary.Path != "." || r.Summary.Blocks != 4 || r.Summary.Files != 4 {
+		t.Errorf("unexpected summary: %+v", r.Summary)
+	}
+	if len(r.Orphaned) != 1 || r.Orphaned[0].ID != 1 {
+		t.Errorf("expected Charge to be orphaned, got %+v", r.Orphaned)
+	}
+	if len(r.SingleAuthorCritical) != 2 || r.SingleAuthorCritical[0].ID != 1 || r.SingleAuthorCritical[1].ID != 3 {
+		t.Errorf("expected Charge then Login as single-author critical, got %+v", r.SingleAuthorCritical)
+	}
+	if len(r.StaleOwnership) != 1 || r.StaleOwnership[0].ID != 2 {
+		t.Errorf("expected Refund (owned by quiet bob) as stale, got %+v", r.StaleOwnership)
+	}
+
+	dirs := make(map[string]DirectoryStats)
+	for _, d := range r.Directories {
+		dirs[d.Path] = d
+	}
+	billing := dirs["internal/billing"]
+	if billing.Blocks != 2 || billing.OrphanedBlocks != 1 || billing.TopOwner != "carol@acme.com" {
+		t.Errorf("unexpected billing stats: %+v", billing)
+	}
+	// Half the billing blocks are already orphaned; losing bob orphans the rest
+	if billing.BusFactor != 1 {
+		t.Errorf("expected billing bus factor 1, got %d", billing.BusFactor)
+	}
+	if _, ok := dirs["internal"]; !ok {
+		t.Error("expected parent directory to be rolled up")
+	}
+
+	if len(r.Departed) != 1 || r.Departed[0].Email != "carol@acme.com" || r.Departed[0].Blocks != 2 || r.Departed[0].SoleBlocks != 1 {
+		t.Errorf("unexpected departed developers: %+v", r.Departed)
+	}
+}
+
+func TestTruckFactor(t *testing.T) {
+	tests := []struct {
+		name   string
+		blocks []map[string]bool
+		want   int
+	}{
+		{"no knowledge", []map[string]bool{{}, {}}, 0},
+		{"single owner", []map[string]bool{{"a": true}, {"a": true}}, 1},
+		{"shared", []map[string]bool{{"a": true, "b": true}, {"a": true, "b": true}}, 2},
+		{"split", []map[string]bool{{"a": true}, {"b": true}, {"c": true}}, 2},
+	}
+	for _, tt := range tests {
+		if got := truckFactor(tt.blocks); got != tt.want {
+			t.Errorf("%s: truckFactor = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	opts := DefaultOptions()
+	opts.Now = now
+	r := Build([]database.BlockWithOwnership{
+		{ID: 1, BlockName: "Charge", CanonicalFilePath: "billing/charge.go", RiskScore: 90,
+			FamiliarityMap: map[string]int{"carol@acme.com": 4}},
+	}, nil, opts)
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, "acme/api", r); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"# Knowledge Report: acme/api", "| `billing/` | 1 | **0** |", "`Charge` | billing/charge.go:0 | carol@acme.com | never"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in markdown:\n%s", want, out)
+		}
+	}
+}
+
+func TestSimulateDeparture(t *testing.T) {
+	blocks := []database.BlockWithOwnership{
+		{ID: 1, BlockName: "Charge", CanonicalFilePath: "billing/charge.go",
+			FamiliarityMap: map[string]int{"Alice@acme.com": 10}},
+		{ID: 2, BlockName: "Refund", CanonicalFilePath: "billing/refund.go", StartLine: 1,
+			FamiliarityMap: map[string]int{"alice@acme.com": 6, "bob@acme.com": 4}},
+		{ID: 3, BlockName: "Void", CanonicalFilePath: "billing/refund.go", StartLine: 40, RiskScore: 80,
+			FamiliarityMap: map[string]int{"bob@acme.com": 5}},
+		{ID: 4, BlockName: "Login", CanonicalFilePath: "auth/login.go",
+			FamiliarityMap: map[string]int{"carol@acme.com": 3}},
+	}
+
+	d := SimulateDeparture(blocks, []string{"alice@acme.com"})
+
+	if d.TotalBlocks != 4 || d.AffectedBlocks != 2 || d.OrphanedBlocks != 1 || d.OrphanedFiles != 1 {
+		t.Errorf("unexpected totals: %+v", d)
+	}
+	if len(d.Blocks) != 2 || d.Blocks[0].ID != 1 || !d.Blocks[0].Orphaned || d.Blocks[0].RiskAfter != "CRITICAL" {
+		t.Fatalf("expected orphaned Charge first, got %+v", d.Blocks)
+	}
+	refund := d.Blocks[1]
+	if refund.SMEBefore != "alice@acme.com" || refund.SMEAfter != "bob@acme.com" || refund.BusFactorAfter != "CRITICAL" {
+		t.Errorf("expected bob to take over Refund alone, got %+v", refund)
+	}
+	if refund.RiskBefore != "LOW" || refund.RiskAfter != "HIGH" || !refund.RiskIncreased() || d.RiskIncreases != 2 {
+		t.Errorf("expected Refund risk to rise from LOW to HIGH, got %+v", refund)
+	}
+	if len(d.Files) != 2 || d.Files[0].File != "billing/charge.go" || !d.Files[0].Orphaned || d.Files[1].Orphaned {
+		t.Errorf("unexpected file impacts: %+v", d.Files)
+	}
+	if len(d.Successors) != 1 || d.Successors[0] != (Successor{Email: "bob@acme.com", Blocks: 1}) {
+		t.Errorf("unexpected successors: %+v", d.Successors)
+	}
+}
@@ -0,0 +1,95 @@
+package knowledge
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteMarkdown renders the report for an engineering review document
+func WriteMarkdown(w io.Writer, repo string, r *Report) error {
+	var b strings.Builder
+	s := r.Summary
+
+	fmt.Fprintf(&b, "# Knowledge Report: %s\n\n", repo)
+	fmt.Fprintf(&b, "Generated %s. Developers with no commits in %d days are treated as departed; "+
+		"ownership is stale when the top owner has no commits in %d days.\n\n",
+		r.GeneratedAt.Format("2006-01-02"), r.Options.DepartedDays, r.Options.StaleDays)
+
+	b.WriteString("## Summary\n\n")
+	fmt.Fprintf(&b, "| Metric | Value |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Code blocks | %d in %d files |\n", s.Blocks, s.Files)
+	fmt.Fprintf(&b, "| Bus factor | %d |\n", s.BusFactor)
+	if s.TopOwner != "" {
+		fmt.Fprintf(&b, "| Top owner | %s (%.0f%% of edits) |\n", s.TopOwner, s.TopOwnerShare*100)
+	}
+	fmt.Fprintf(&b, "| Concentrated blocks | %d |\n", s.ConcentratedBlocks)
+	fmt.Fprintf(&b, "| Stale ownership | %d |\n", s.StaleBlocks)
+	fmt.Fprintf(&b, "| Single-author critical blocks | %d |\n", s.SingleAuthorCritical)
+	fmt.Fprintf(&b, "| Blocks whose familiar developers all left | %d |\n", s.OrphanedBlocks)
+	fmt.Fprintf(&b, "| Departed developers | %d |\n\n", len(r.Departed))
+
+	if len(r.Directories) > 0 {
+		b.WriteString("## Directories\n\n")
+		b.WriteString("| Directory | Blocks | Bus factor | Top owner | Concentrated | Stale | Single-author critical | Orphaned |\n")
+		b.WriteString("|---|---:|---:|---|---:|---:|---:|---:|\n")
+		for _, d := range r.Directories {
+			owner := "-"
+			if d.TopOwner != "" {
+				owner = fmt.Sprintf("%s (%.0f%%)", d.TopOwner, d.TopOwnerShare*100)
+			}
+			fmt.Fprintf(&b, "| `%s/` | %d | %s | %s | %d | %d | %d | %d |\n",
+				d.Path, d.Blocks, busFactorCell(d.BusFactor), owner,
+				d.ConcentratedBlocks, d.StaleBlocks, d.SingleAuthorCritical, d.OrphanedBlocks)
+		}
+		b.WriteString("\n")
+	}
+
+	writeFindings(&b, "Knowledge Lost", "Blocks whose every familiar developer has departed.", r.Orphaned)
+	writeFindings(&b, "Single-Author Critical Blocks", fmt.Sprintf(
+		"Blocks with risk above %.0f or past incidents that only one developer has touched.", r.Options.CriticalRisk),
+		r.SingleAuthorCritical)
+	writeFindings(&b, "Stale Ownership", "Blocks whose top owner has gone quiet but other familiar developers remain.", r.StaleOwnership)
+
+	if len(r.Departed) > 0 {
+		b.WriteString("## Departed Developers\n\n")
+		b.WriteString("| Developer | Last commit | Blocks | Sole remaining knowledge |\n|---|---|---:|---:|\n")
+		for _, d := range r.Departed {
+			fmt.Fprintf(&b, "| %s | %s | %d | %d |\n", d.Email, formatDate(d.LastCommit), d.Blocks, d.SoleBlocks)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeFindings(b *strings.Builder, title, description string, findings []BlockFinding) {
+	fmt.Fprintf(b, "## %s\n\n%s\n\n", title, description)
+	if len(findings) == 0 {
+		b.WriteString("None.\n\n")
+		return
+	}
+	b.WriteString("| Block | Location | Owner | Owner last commit | Developers | Risk | Incidents |\n")
+	b.WriteString("|---|---|---|---|---:|---:|---:|\n")
+	for _, f := range findings {
+		fmt.Fprintf(b, "| `%s` | %s:%d | %s | %s | %d | %.0f | %d |\n",
+			f.Name, f.File, f.StartLine, f.Owner, formatDate(f.OwnerLastCommit), f.Developers, f.RiskScore, f.IncidentCount)
+	}
+	b.WriteString("\n")
+}
+
+func busFactorCell(n int) string {
+	if n <= 1 {
+		return fmt.Sprintf("**%d**", n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
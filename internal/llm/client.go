@@ -2,6 +2,7 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -17,6 +18,7 @@ type Provider string
 const (
 	ProviderOpenAI Provider = "openai"
 	ProviderGemini Provider = "gemini"
+	ProviderLocal  Provider = "local" // OpenAI-compatible local server (Ollama, llama.cpp, vLLM)
 	ProviderNone   Provider = "none" // Phase 2 disabled
 )
 
@@ -55,15 +57,79 @@ func NewClient(ctx context.Context, cfg *config.Config) (*Client, error) {
 		}, nil
 	}
 
-	// GEMINI ONLY: OpenAI support temporarily disabled
-	// Only Gemini provider is active. OpenAI code remains for future use.
-	provider := os.Getenv("LLM_PROVIDER")
-	if provider != "" && provider != "gemini" {
-		logger.Warn("only gemini provider supported currently, ignoring LLM_PROVIDER", "requested_provider", provider)
+	// Gemini is the default; LLM_PROVIDER selects OpenAI or a local model
+	return newProviderClient(ctx, cfg, Provider(os.Getenv("LLM_PROVIDER")), logger)
+}
+
+// ErrNoAPIKey is returned when the selected provider needs an API key and none is configured
+var ErrNoAPIKey = errors.New("no LLM API key configured")
+
+// NewInvestigationClient creates the function-calling client for Phase 2 investigations,
+// for the provider LLM_PROVIDER or the config file selects (Gemini by default)
+// Unlike NewClient it does not consult PHASE2_ENABLED; the caller decides when to
+// investigate. geminiKey, when set, overrides the configured Gemini key (cloud
+// credentials). Returns an error wrapping ErrNoAPIKey when the provider has no key.
+func NewInvestigationClient(ctx context.Context, cfg *config.Config, geminiKey string) (*Client, error) {
+	logger := slog.Default().With("component", "llm")
+	if geminiKey != "" {
+		withKey := *cfg
+		withKey.API.GeminiKey = geminiKey
+		cfg = &withKey
+	}
+
+	provider := Provider(cfg.API.Provider)
+	client, err := newProviderClient(ctx, cfg, provider, logger)
+	if err != nil {
+		return nil, err
 	}
+	if !client.enabled {
+		if provider == "" {
+			provider = ProviderGemini
+		}
+		return nil, fmt.Errorf("%w for %s", ErrNoAPIKey, provider)
+	}
+	return client, nil
+}
 
-	// Always use Gemini
-	return newGeminiClient(ctx, cfg, logger)
+// newProviderClient initializes the client for a provider; "" selects Gemini
+func newProviderClient(ctx context.Context, cfg *config.Config, provider Provider, logger *slog.Logger) (*Client, error) {
+	switch provider {
+	case "", ProviderGemini:
+		return newGeminiClient(ctx, cfg, logger)
+	case ProviderOpenAI:
+		return newOpenAIClient(ctx, cfg, logger)
+	case ProviderLocal:
+		return newLocalClient(logger)
+	default:
+		logger.Warn("unknown LLM_PROVIDER, using gemini", "requested_provider", provider)
+		return newGeminiClient(ctx, cfg, logger)
+	}
+}
+
+// newLocalClient initializes a client for an OpenAI-compatible local model server
+// LLM_BASE_URL defaults to Ollama's endpoint; LLM_MODEL names the served model.
+func newLocalClient(logger *slog.Logger) (*Client, error) {
+	baseURL := os.Getenv("LLM_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:11434/v1"
+	}
+	model := os.Getenv("LLM_MODEL")
+	if model == "" {
+		model = "llama3.1"
+	}
+
+	openaiCfg := openai.DefaultConfig(os.Getenv("LLM_API_KEY")) // Most local servers ignore the key
+	openaiCfg.BaseURL = baseURL
+
+	logger.Info("local model client initialized", "base_url", baseURL, "model", model)
+	return &Client{
+		provider:     ProviderLocal,
+		openaiClient: openai.NewClientWithConfig(openaiCfg),
+		logger:       logger,
+		enabled:      true,
+		fastModel:    model,
+		deepModel:    model,
+	}, nil
 }
 
 // newGeminiClient initializes a Gemini provider client
@@ -208,7 +274,7 @@ func (c *Client) Complete(ctx context.Context, systemPrompt, userPrompt string)
 	switch c.provider {
 	case ProviderGemini:
 		return c.geminiClient.Complete(ctx, systemPrompt, userPrompt)
-	case ProviderOpenAI:
+	case ProviderOpenAI, ProviderLocal:
 		return c.completeOpenAI(ctx, systemPrompt, userPrompt, c.fastModel)
 	default:
 		return "", fmt.Errorf("no provider configured")
@@ -236,7 +302,7 @@ func (c *Client) CompleteWithDeepModel(ctx context.Context, systemPrompt, userPr
 		}
 		defer geminiDeep.Close()
 		return geminiDeep.Complete(ctx, systemPrompt, userPrompt)
-	case ProviderOpenAI:
+	case ProviderOpenAI, ProviderLocal:
 		return c.completeOpenAI(ctx, systemPrompt, userPrompt, c.deepModel)
 	default:
 		return "", fmt.Errorf("no provider configured")
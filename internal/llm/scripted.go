@@ -0,0 +1,41 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ScriptedToolCaller replays canned responses in order, for deterministic agent tests
+// and offline demos. Every call records the conversation it was given.
+type ScriptedToolCaller struct {
+	mu        sync.Mutex
+	responses []ToolResponse
+	Calls     [][]Message // Conversation received on each call
+}
+
+var _ ToolCaller = (*ScriptedToolCaller)(nil)
+
+// NewScriptedToolCaller creates a caller that returns responses one per call
+func NewScriptedToolCaller(responses ...ToolResponse) *ScriptedToolCaller {
+	return &ScriptedToolCaller{responses: responses}
+}
+
+// CompleteWithToolCalls returns the next scripted response
+// It errors once the script is exhausted so runaway loops fail loudly.
+func (s *ScriptedToolCaller) CompleteWithToolCalls(ctx context.Context, messages []Message, tools []ToolDefinition) (*ToolResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.Calls = append(s.Calls, append([]Message(nil), messages...))
+	if len(s.Calls) > len(s.responses) {
+		return nil, fmt.Errorf("scripted caller exhausted after %d responses", len(s.responses))
+	}
+	resp := s.responses[len(s.Calls)-1]
+	return &resp, nil
+}
+
+// ScriptedCall builds a tool call for a scripted response
+func ScriptedCall(name string, args map[string]any) ToolCall {
+	return ToolCall{ID: "call_" + name, Name: name, Arguments: args}
+}
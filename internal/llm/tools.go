@@ -0,0 +1,222 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/genai"
+)
+
+// Message roles for tool-calling conversations
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// ToolDefinition describes a function the model may call
+// Parameters is a JSON Schema object, passed through to each provider unchanged.
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+}
+
+// ToolCall is a function call requested by the model
+type ToolCall struct {
+	ID        string // Provider call ID (synthesized when the provider has none)
+	Name      string
+	Arguments map[string]any
+}
+
+// Message is one turn of a provider-agnostic tool-calling conversation
+// Assistant messages carry ToolCalls; tool messages answer one call by ToolCallID.
+type Message struct {
+	Role       string
+	Content    string
+	ToolCalls  []ToolCall // assistant only
+	ToolCallID string     // tool only
+	ToolName   string     // tool only
+}
+
+// ToolResponse is one model turn: text, tool calls, or both
+type ToolResponse struct {
+	Content    string
+	ToolCalls  []ToolCall
+	TokensUsed int
+}
+
+// ToolCaller runs one step of a function-calling conversation
+// Implemented by Client (any configured provider), GeminiClient and ScriptedToolCaller.
+type ToolCaller interface {
+	CompleteWithToolCalls(ctx context.Context, messages []Message, tools []ToolDefinition) (*ToolResponse, error)
+}
+
+var (
+	_ ToolCaller = (*Client)(nil)
+	_ ToolCaller = (*GeminiClient)(nil)
+)
+
+// CompleteWithToolCalls sends the conversation with tools enabled using the active provider
+func (c *Client) CompleteWithToolCalls(ctx context.Context, messages []Message, tools []ToolDefinition) (*ToolResponse, error) {
+	if !c.enabled {
+		return nil, fmt.Errorf("llm client not enabled (check PHASE2_ENABLED and API key)")
+	}
+
+	switch c.provider {
+	case ProviderGemini:
+		return c.geminiClient.CompleteWithToolCalls(ctx, messages, tools)
+	case ProviderOpenAI, ProviderLocal:
+		return c.completeOpenAIWithTools(ctx, messages, tools)
+	default:
+		return nil, fmt.Errorf("no provider configured")
+	}
+}
+
+// completeOpenAIWithTools handles OpenAI-compatible chat completion with function calling
+func (c *Client) completeOpenAIWithTools(ctx context.Context, messages []Message, tools []ToolDefinition) (*ToolResponse, error) {
+	req := openai.ChatCompletionRequest{
+		Model:       c.deepModel, // Investigations need the stronger model
+		Messages:    toOpenAIMessages(messages),
+		Tools:       toOpenAITools(tools),
+		Temperature: 0.1,
+	}
+
+	resp, err := c.openaiClient.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%s tool completion failed: %w", c.provider, err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("%s returned no choices", c.provider)
+	}
+
+	msg := resp.Choices[0].Message
+	out := &ToolResponse{Content: msg.Content, TokensUsed: resp.Usage.TotalTokens}
+	for _, call := range msg.ToolCalls {
+		args := map[string]any{}
+		if call.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+				return nil, fmt.Errorf("invalid arguments for %s: %w", call.Function.Name, err)
+			}
+		}
+		out.ToolCalls = append(out.ToolCalls, ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: args})
+	}
+
+	c.logger.Debug("openai tool completion",
+		"model", c.deepModel,
+		"messages", len(messages),
+		"tool_calls", len(out.ToolCalls),
+		"tokens_used", out.TokensUsed,
+	)
+	return out, nil
+}
+
+func toOpenAITools(tools []ToolDefinition) []openai.Tool {
+	out := make([]openai.Tool, len(tools))
+	for i, t := range tools {
+		out[i] = openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		}
+	}
+	return out
+}
+
+func toOpenAIMessages(messages []Message) []openai.ChatCompletionMessage {
+	out := make([]openai.ChatCompletionMessage, 0, len(messages))
+	for _, m := range messages {
+		msg := openai.ChatCompletionMessage{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+		if m.Role == RoleTool {
+			msg.Name = m.ToolName
+		}
+		for _, call := range m.ToolCalls {
+			args, _ := json.Marshal(call.Arguments)
+			msg.ToolCalls = append(msg.ToolCalls, openai.ToolCall{
+				ID:       call.ID,
+				Type:     openai.ToolTypeFunction,
+				Function: openai.FunctionCall{Name: call.Name, Arguments: string(args)},
+			})
+		}
+		out = append(out, msg)
+	}
+	return out
+}
+
+// CompleteWithToolCalls adapts a provider-agnostic conversation to Gemini function calling
+// System messages become the system instruction; tool results are sent as function responses.
+func (c *GeminiClient) CompleteWithToolCalls(ctx context.Context, messages []Message, tools []ToolDefinition) (*ToolResponse, error) {
+	systemPrompt := ""
+	history := make([]*genai.Content, 0, len(messages))
+	for _, m := range messages {
+		switch m.Role {
+		case RoleSystem:
+			systemPrompt += m.Content
+		case RoleAssistant:
+			content := &genai.Content{Role: genai.RoleModel}
+			if m.Content != "" {
+				content.Parts = append(content.Parts, &genai.Part{Text: m.Content})
+			}
+			for _, call := range m.ToolCalls {
+				content.Parts = append(content.Parts, &genai.Part{
+					FunctionCall: &genai.FunctionCall{Name: call.Name, Args: call.Arguments},
+				})
+			}
+			history = append(history, content)
+		case RoleTool:
+			part := &genai.Part{FunctionResponse: &genai.FunctionResponse{
+				Name:     m.ToolName,
+				Response: map[string]any{"result": m.Content},
+			}}
+			// Consecutive tool results share one user turn, as Gemini expects
+			if last := len(history) - 1; last >= 0 && history[last].Role == genai.RoleUser && history[last].Parts[0].FunctionResponse != nil {
+				history[last].Parts = append(history[last].Parts, part)
+				continue
+			}
+			history = append(history, &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{part}})
+		default:
+			history = append(history, genai.NewContentFromText(m.Content, genai.RoleUser))
+		}
+	}
+
+	declarations := make([]*genai.FunctionDeclaration, len(tools))
+	for i, t := range tools {
+		declarations[i] = &genai.FunctionDeclaration{
+			Name:                 t.Name,
+			Description:          t.Description,
+			ParametersJsonSchema: t.Parameters,
+		}
+	}
+
+	resp, err := c.CompleteWithToolsAndHistory(ctx, systemPrompt, history, []*genai.Tool{{FunctionDeclarations: declarations}})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return nil, fmt.Errorf("no response from gemini")
+	}
+
+	out := &ToolResponse{}
+	if resp.UsageMetadata != nil {
+		out.TokensUsed = int(resp.UsageMetadata.TotalTokenCount)
+	}
+	for i, part := range resp.Candidates[0].Content.Parts {
+		if part.Text != "" {
+			out.Content += part.Text
+		}
+		if part.FunctionCall != nil {
+			id := part.FunctionCall.ID
+			if id == "" {
+				id = fmt.Sprintf("call_%d", i)
+			}
+			out.ToolCalls = append(out.ToolCalls, ToolCall{ID: id, Name: part.FunctionCall.Name, Arguments: part.FunctionCall.Args})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package manifest
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/depgraph"
+)
+
+// pyImportNames maps distributions whose import name differs from the package name
+var pyImportNames = map[string]string{
+	"pyyaml":          "yaml",
+	"beautifulsoup4":  "bs4",
+	"pillow":          "PIL",
+	"scikit-learn":    "sklearn",
+	"python-dateutil": "dateutil",
+	"opencv-python":   "cv2",
+	"protobuf":        "google.protobuf",
+	"attrs":           "attr",
+	"pyjwt":           "jwt",
+	"python-dotenv":   "dotenv",
+	"psycopg2-binary": "psycopg2",
+}
+
+// importers returns the source files under the manifest's directory importing a package
+// Rust sources are not analyzed, so Cargo packages have none.
+func importers(c Change, graph *depgraph.Result) []string {
+	dir := path.Dir(c.Source)
+	found := make(map[string]bool)
+	for _, imp := range graph.External {
+		if dir != "." && !strings.HasPrefix(imp.File, dir+"/") {
+			continue
+		}
+		if imports(c, imp) {
+			found[imp.File] = true
+		}
+	}
+
+	files := make([]string, 0, len(found))
+	for f := range found {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// imports reports whether an import specifier refers to the changed package
+func imports(c Change, imp depgraph.ExternalImport) bool {
+	switch c.Ecosystem {
+	case Go:
+		return imp.Language == "go" && (imp.Spec == c.Name || strings.HasPrefix(imp.Spec, c.Name+"/"))
+	case NPM:
+		return imp.Language == "javascript" && npmPackageOf(imp.Spec) == c.Name
+	case PyPI:
+		if imp.Language != "python" {
+			return false
+		}
+		module, ok := pyImportNames[c.Name]
+		if !ok {
+			module = strings.ReplaceAll(c.Name, "-", "_")
+		}
+		spec, module := strings.ToLower(imp.Spec), strings.ToLower(module)
+		return spec == module || strings.HasPrefix(spec, module+".")
+	}
+	return false
+}
+
+// npmPackageOf returns the package of a bare specifier: "lodash/fp" -> "lodash",
+// "@scope/pkg/sub" -> "@scope/pkg"
+func npmPackageOf(spec string) string {
+	parts := strings.SplitN(spec, "/", 3)
+	if strings.HasPrefix(spec, "@") && len(parts) >= 2 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
@@ -0,0 +1,338 @@
+// Package manifest analyzes changes to dependency manifests and lockfiles (go.mod, go.sum,
+// package.json, package-lock.json, yarn.lock, requirements.txt, poetry.lock, Cargo.toml,
+// Cargo.lock): which packages were added, removed, upgraded or downgraded, how large each
+// version jump is, and which source files import the changed packages.
+package manifest
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/depgraph"
+)
+
+// Ecosystem is a package registry
+type Ecosystem string
+
+const (
+	Go    Ecosystem = "go"
+	NPM   Ecosystem = "npm"
+	PyPI  Ecosystem = "pypi"
+	Cargo Ecosystem = "cargo"
+)
+
+// format describes a supported file name
+type format struct {
+	ecosystem Ecosystem
+	lockfile  bool
+}
+
+var formats = map[string]format{
+	"go.mod":              {Go, false},
+	"go.sum":              {Go, true},
+	"package.json":        {NPM, false},
+	"package-lock.json":   {NPM, true},
+	"npm-shrinkwrap.json": {NPM, true},
+	"yarn.lock":           {NPM, true},
+	"poetry.lock":         {PyPI, true},
+	"Cargo.toml":          {Cargo, false},
+	"Cargo.lock":          {Cargo, true},
+}
+
+// Package is one dependency entry
+type Package struct {
+	Name     string
+	Version  string // Constraint in manifests, resolved version in lockfiles
+	Dev      bool
+	Indirect bool // Marked indirect in go.mod
+}
+
+// Manifest is a parsed manifest or lockfile
+type Manifest struct {
+	Path      string
+	Ecosystem Ecosystem
+	Lockfile  bool
+	Packages  map[string]Package
+}
+
+// add records a package, keeping the highest version when a lockfile lists several
+func (m *Manifest) add(p Package) {
+	if prev, ok := m.Packages[p.Name]; ok {
+		pv, pok := parseVersion(prev.Version)
+		nv, nok := parseVersion(p.Version)
+		if !pok || !nok || nv.compare(pv) <= 0 {
+			return
+		}
+	}
+	m.Packages[p.Name] = p
+}
+
+// IsManifest reports whether a path is a supported manifest or lockfile
+func IsManifest(file string) bool {
+	_, ok := formats[path.Base(file)]
+	return ok || isRequirementsFile(file)
+}
+
+func isRequirementsFile(file string) bool {
+	name := path.Base(file)
+	return strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt")
+}
+
+// ChangeKind is what happened to a package
+type ChangeKind string
+
+const (
+	Added      ChangeKind = "added"
+	Removed    ChangeKind = "removed"
+	Upgraded   ChangeKind = "upgraded"
+	Downgraded ChangeKind = "downgraded"
+	Changed    ChangeKind = "changed" // Versions that are not semantic (git refs, tags, ranges)
+)
+
+// Change is one package's change between two versions of a manifest
+type Change struct {
+	Ecosystem Ecosystem  `json:"ecosystem"`
+	Name      string     `json:"name"`
+	Kind      ChangeKind `json:"kind"`
+	From      string     `json:"from,omitempty"`
+	To        string     `json:"to,omitempty"`
+	Bump      Bump       `json:"bump,omitempty"`
+	Direct    bool       `json:"direct"` // Declared in the manifest rather than only resolved in a lockfile
+	Dev       bool       `json:"dev,omitempty"`
+	Source    string     `json:"source"`              // Manifest or lockfile the change was read from
+	Importers []string   `json:"importers,omitempty"` // Source files importing the package
+	Risk      string     `json:"risk"`                // LOW, MEDIUM, HIGH
+}
+
+// Diff compares two parses of the same file
+func Diff(before, after *Manifest) []Change {
+	var changes []Change
+	for name, b := range before.Packages {
+		a, ok := after.Packages[name]
+		if !ok {
+			changes = append(changes, Change{Name: name, Kind: Removed, From: b.Version, Dev: b.Dev})
+			continue
+		}
+		if a.Version == b.Version {
+			continue
+		}
+		c := Change{Name: name, Kind: Changed, From: b.Version, To: a.Version, Dev: a.Dev}
+		bv, bok := parseVersion(b.Version)
+		av, aok := parseVersion(a.Version)
+		if bok && aok {
+			switch cmp := av.compare(bv); {
+			case cmp > 0:
+				c.Kind = Upgraded
+			case cmp < 0:
+				c.Kind = Downgraded
+			default:
+				continue // Same version, different constraint syntax
+			}
+			c.Bump = bumpBetween(bv, av)
+		}
+		changes = append(changes, c)
+	}
+	for name, a := range after.Packages {
+		if _, ok := before.Packages[name]; !ok {
+			changes = append(changes, Change{Name: name, Kind: Added, To: a.Version, Dev: a.Dev})
+		}
+	}
+
+	for i := range changes {
+		changes[i].Ecosystem = after.Ecosystem
+		changes[i].Source = after.Path
+		changes[i].Direct = !after.Lockfile
+		if p, ok := after.Packages[changes[i].Name]; ok && p.Indirect {
+			changes[i].Direct = false
+		}
+	}
+	sortChanges(changes)
+	return changes
+}
+
+// Report is the dependency analysis of a change set
+type Report struct {
+	Manifests []string `json:"manifests"` // Changed manifests and lockfiles
+	Changes   []Change `json:"changes"`
+	Risk      string   `json:"risk"` // Highest risk among changes
+}
+
+// Reader returns a file's content in one tree; a missing file returns nil content and no error
+type Reader func(file string) ([]byte, error)
+
+// Analyze compares the changed manifests and lockfiles among files between two trees
+// A package changed in both a manifest and its lockfile is reported once, from the
+// manifest. Lockfile-only changes count as direct when the package is declared in the
+// manifest beside the lockfile. graph, when given, supplies the importing source files.
+func Analyze(files []string, before, after Reader, graph *depgraph.Result) (*Report, error) {
+	report := &Report{Risk: "LOW"}
+	type key struct {
+		eco       Ecosystem
+		dir, name string
+	}
+	fromManifest := make(map[key]bool)
+	var manifestChanges, lockChanges []Change
+
+	for _, file := range files {
+		if !IsManifest(file) {
+			continue
+		}
+		oldContent, err := before(file)
+		if err != nil {
+			return nil, err
+		}
+		newContent, err := after(file)
+		if err != nil {
+			return nil, err
+		}
+		oldManifest, err := parse(file, oldContent)
+		if err != nil {
+			return nil, err
+		}
+		newManifest, err := parse(file, newContent)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Manifests = append(report.Manifests, file)
+		changes := Diff(oldManifest, newManifest)
+		if !newManifest.Lockfile {
+			for _, c := range changes {
+				fromManifest[key{c.Ecosystem, path.Dir(file), c.Name}] = true
+			}
+			manifestChanges = append(manifestChanges, changes...)
+			continue
+		}
+
+		direct := declared(path.Dir(file), newManifest.Ecosystem, after)
+		for _, c := range changes {
+			c.Direct = direct[c.Name]
+			lockChanges = append(lockChanges, c)
+		}
+	}
+
+	report.Changes = manifestChanges
+	for _, c := range lockChanges {
+		if !fromManifest[key{c.Ecosystem, path.Dir(c.Source), c.Name}] {
+			report.Changes = append(report.Changes, c)
+		}
+	}
+
+	for i := range report.Changes {
+		c := &report.Changes[i]
+		if graph != nil {
+			c.Importers = importers(*c, graph)
+		}
+		c.Risk = classify(*c)
+		if riskRank[c.Risk] > riskRank[report.Risk] {
+			report.Risk = c.Risk
+		}
+	}
+	sortChanges(report.Changes)
+	return report, nil
+}
+
+// declared returns the direct dependencies of the manifest in dir for an ecosystem
+func declared(dir string, eco Ecosystem, read Reader) map[string]bool {
+	names := make(map[string]bool)
+	for name, f := range formats {
+		if f.ecosystem != eco || f.lockfile {
+			continue
+		}
+		file := path.Join(dir, name)
+		content, err := read(file)
+		if err != nil || content == nil {
+			continue
+		}
+		m, err := parse(file, content)
+		if err != nil {
+			continue
+		}
+		for pkg, p := range m.Packages {
+			if !p.Indirect {
+				names[pkg] = true
+			}
+		}
+	}
+	if eco == PyPI {
+		if content, err := read(path.Join(dir, "requirements.txt")); err == nil && content != nil {
+			if m, err := parse(path.Join(dir, "requirements.txt"), content); err == nil {
+				for pkg := range m.Packages {
+					names[pkg] = true
+				}
+			}
+		}
+	}
+	return names
+}
+
+var riskRank = map[string]int{"LOW": 0, "MEDIUM": 1, "HIGH": 2}
+
+// classify rates one change
+// HIGH: a direct dependency jumps a major version, or a removed package is still imported
+// MEDIUM: a transitive major jump, a direct downgrade, a non-semantic version change of a
+// direct dependency, or a direct minor upgrade of a package the code imports
+func classify(c Change) string {
+	switch {
+	case c.Kind == Removed && len(c.Importers) > 0:
+		return "HIGH"
+	case c.Bump == BumpMajor && c.Direct && !c.Dev:
+		return "HIGH"
+	case c.Bump == BumpMajor:
+		return "MEDIUM"
+	case c.Direct && (c.Kind == Downgraded || c.Kind == Changed):
+		return "MEDIUM"
+	case c.Direct && c.Bump == BumpMinor && len(c.Importers) > 0:
+		return "MEDIUM"
+	}
+	return "LOW"
+}
+
+// sortChanges orders by risk, then directness, then name
+func sortChanges(changes []Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		ci, cj := changes[i], changes[j]
+		if riskRank[ci.Risk] != riskRank[cj.Risk] {
+			return riskRank[ci.Risk] > riskRank[cj.Risk]
+		}
+		if ci.Direct != cj.Direct {
+			return ci.Direct
+		}
+		if ci.Ecosystem != cj.Ecosystem {
+			return ci.Ecosystem < cj.Ecosystem
+		}
+		return ci.Name < cj.Name
+	})
+}
+
+// Describe summarizes the change, e.g. "github.com/go-chi/chi v1.5.4 → v5.0.10 (major, direct)"
+func (c Change) Describe() string {
+	var desc string
+	switch c.Kind {
+	case Added:
+		desc = fmt.Sprintf("%s %s added", c.Name, c.To)
+	case Removed:
+		desc = fmt.Sprintf("%s %s removed", c.Name, c.From)
+	default:
+		desc = fmt.Sprintf("%s %s → %s", c.Name, c.From, c.To)
+	}
+
+	var notes []string
+	if c.Bump != "" {
+		notes = append(notes, string(c.Bump))
+	}
+	if c.Kind == Downgraded {
+		notes = append(notes, "downgrade")
+	}
+	if c.Direct {
+		notes = append(notes, "direct")
+	} else {
+		notes = append(notes, "transitive")
+	}
+	if c.Dev {
+		notes = append(notes, "dev")
+	}
+	return fmt.Sprintf("%s (%s)", strings.TrimSpace(desc), strings.Join(notes, ", "))
+}
@@ -0,0 +1,166 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/depgraph"
+)
+
+func tree(files map[string]string) Reader {
+	return func(file string) ([]byte, error) {
+		content, ok := files[file]
+		if !ok {
+			return nil, nil
+		}
+		return []byte(content), nil
+	}
+}
+
+func findChange(t *testing.T, r *Report, name string) Change {
+	t.Helper()
+	for _, c := range r.Changes {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("no change for %s in %+v", name, r.Changes)
+	return Change{}
+}
+
+func TestAnalyzeGoModules(t *testing.T) {
+	before := tree(map[string]string{
+		"go.mod": `module example.com/shop
+
+go 1.22
+
+require (
+	github.com/go-chi/chi v1.5.4
+	github.com/stretchr/testify v1.8.0
+	golang.org/x/text v0.13.0 // indirect
+)
+
+require github.com/google/uuid v1.3.0
+`,
+		"go.sum": "github.com/go-chi/chi v1.5.4 h1:a=\ngithub.com/go-chi/chi v1.5.4/go.mod h1:b=\ngolang.org/x/net v0.10.0 h1:c=\n",
+	})
+	after := tree(map[string]string{
+		"go.mod": `module example.com/shop
+
+go 1.22
+
+require (
+	github.com/go-chi/chi v5.0.10+incompatible
+	github.com/stretchr/testify v1.8.4
+	golang.org/x/text v0.14.0 // indirect
+	github.com/rs/zerolog v1.31.0
+)
+`,
+		"go.sum": "github.com/go-chi/chi v5.0.10+incompatible h1:d=\ngolang.org/x/net v0.17.0 h1:e=\n",
+	})
+	graph := &depgraph.Result{External: []depgraph.ExternalImport{
+		{File: "api/router.go", Language: "go", Spec: "github.com/go-chi/chi/middleware"},
+		{File: "api/ids.go", Language: "go", Spec: "github.com/google/uuid"},
+		{File: "web/app.ts", Language: "javascript", Spec: "github.com/google/uuid"},
+	}}
+
+	r, err := Analyze([]string{"go.mod", "go.sum", "main.go"}, before, after, graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Manifests, []string{"go.mod", "go.sum"}) || r.Risk != "HIGH" {
+		t.Errorf("manifests %v, risk %s", r.Manifests, r.Risk)
+	}
+
+	chi := findChange(t, r, "github.com/go-chi/chi")
+	if chi.Kind != Upgraded || chi.Bump != BumpMajor || !chi.Direct || chi.Source != "go.mod" || chi.Risk != "HIGH" {
+		t.Errorf("chi = %+v, want direct major upgrade from go.mod rated HIGH", chi)
+	}
+	if !reflect.DeepEqual(chi.Importers, []string{"api/router.go"}) {
+		t.Errorf("chi importers = %v", chi.Importers)
+	}
+	if c := findChange(t, r, "github.com/stretchr/testify"); c.Bump != BumpPatch || c.Risk != "LOW" {
+		t.Errorf("testify = %+v, want LOW patch", c)
+	}
+	// 0.x minor jumps may break, but the module is indirect
+	if c := findChange(t, r, "golang.org/x/text"); c.Bump != BumpMajor || c.Direct || c.Risk != "MEDIUM" {
+		t.Errorf("x/text = %+v, want indirect 0.x jump rated MEDIUM", c)
+	}
+	if c := findChange(t, r, "github.com/google/uuid"); c.Kind != Removed || c.Risk != "HIGH" || len(c.Importers) != 1 {
+		t.Errorf("uuid = %+v, want removed while imported by api/ids.go", c)
+	}
+	if c := findChange(t, r, "github.com/rs/zerolog"); c.Kind != Added || c.Risk != "LOW" {
+		t.Errorf("zerolog = %+v, want LOW addition", c)
+	}
+	// Lockfile-only change of a transitive module
+	if c := findChange(t, r, "golang.org/x/net"); c.Source != "go.sum" || c.Direct {
+		t.Errorf("x/net = %+v, want indirect change from go.sum", c)
+	}
+	for _, c := range r.Changes {
+		if c.Name == "github.com/go-chi/chi" && c.Source == "go.sum" {
+			t.Error("chi reported from both go.mod and go.sum")
+		}
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    map[string]string
+	}{
+		{"web/package.json", `{"dependencies": {"react": "^18.2.0"}, "devDependencies": {"@types/node": "~20.1.0"}}`,
+			map[string]string{"react": "^18.2.0", "@types/node": "~20.1.0"}},
+		{"web/package-lock.json", `{"lockfileVersion": 3, "packages": {"": {}, "node_modules/react": {"version": "18.2.0"}, "node_modules/a/node_modules/react": {"version": "17.0.0"}, "node_modules/@scope/pkg": {"version": "1.0.0"}}}`,
+			map[string]string{"react": "18.2.0", "@scope/pkg": "1.0.0"}},
+		{"yarn.lock", "# yarn lockfile v1\n\n\"@babel/core@^7.0.0\", \"@babel/core@^7.1.0\":\n  version \"7.22.5\"\n  dependencies:\n    debug \"^4.1.0\"\n\nlodash@^4.17.20:\n  version \"4.17.21\"\n",
+			map[string]string{"@babel/core": "7.22.5", "lodash": "4.17.21"}},
+		{"requirements-dev.txt", "# tools\nRequests[socks]==2.31.0 ; python_version > '3.8'\nDjango>=4.2,<5\n-r base.txt\nflake8\n",
+			map[string]string{"requests": "==2.31.0", "django": ">=4.2,<5", "flake8": ""}},
+		{"Cargo.toml", "[package]\nname = \"shop\"\nversion = \"0.1.0\"\n\n[dependencies]\nserde = { version = \"1.0\", features = [\"derive\"] }\ntokio = \"1.32\"\n\n[dev-dependencies.criterion]\nversion = \"0.5\"\n",
+			map[string]string{"serde": "1.0", "tokio": "1.32", "criterion": "0.5"}},
+		{"poetry.lock", "[[package]]\nname = \"PyYAML\"\nversion = \"6.0.1\"\n\n[package.dependencies]\nfoo = \"*\"\n\n[[package]]\nname = \"attrs\"\nversion = \"23.1.0\"\n",
+			map[string]string{"pyyaml": "6.0.1", "attrs": "23.1.0"}},
+	}
+	for _, tt := range tests {
+		m, err := parse(tt.file, []byte(tt.content))
+		if err != nil {
+			t.Errorf("%s: %v", tt.file, err)
+			continue
+		}
+		got := make(map[string]string, len(m.Packages))
+		for name, p := range m.Packages {
+			got[name] = p.Version
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestPythonAndNPMImporters(t *testing.T) {
+	before := tree(map[string]string{
+		"requirements.txt": "PyYAML==5.4.1\n",
+		"web/package.json": `{"dependencies": {"@scope/pkg": "^1.2.0"}}`,
+	})
+	after := tree(map[string]string{
+		"requirements.txt": "PyYAML==6.0.1\n",
+		"web/package.json": `{"dependencies": {"@scope/pkg": "^1.4.0"}}`,
+	})
+	graph := &depgraph.Result{External: []depgraph.ExternalImport{
+		{File: "shop/config.py", Language: "python", Spec: "yaml"},
+		{File: "web/src/app.ts", Language: "javascript", Spec: "@scope/pkg/sub"},
+		{File: "admin/app.ts", Language: "javascript", Spec: "@scope/pkg"}, // Outside web/
+	}}
+
+	r, err := Analyze([]string{"requirements.txt", "web/package.json"}, before, after, graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := findChange(t, r, "pyyaml"); c.Bump != BumpMajor || !reflect.DeepEqual(c.Importers, []string{"shop/config.py"}) {
+		t.Errorf("pyyaml = %+v", c)
+	}
+	if c := findChange(t, r, "@scope/pkg"); c.Bump != BumpMinor || c.Risk != "MEDIUM" || !reflect.DeepEqual(c.Importers, []string{"web/src/app.ts"}) {
+		t.Errorf("@scope/pkg = %+v, want MEDIUM minor bump imported by web/src/app.ts", c)
+	}
+}
@@ -0,0 +1,275 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	goRequirePattern  = regexp.MustCompile(`^(\S+)\s+(v\S+)(.*)$`)
+	requirementLine   = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._\-]*)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+	pyNameSeparators  = regexp.MustCompile(`[-_.]+`)
+	tomlKeyValue      = regexp.MustCompile(`^([A-Za-z0-9_\-"']+)\s*=\s*(.+)$`)
+	tomlInlineVersion = regexp.MustCompile(`\bversion\s*=\s*"([^"]*)"`)
+)
+
+func parseGoMod(content string, m *Manifest) {
+	inRequire := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "require (":
+			inRequire = true
+			continue
+		case inRequire && line == ")":
+			inRequire = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		case !inRequire:
+			continue
+		}
+		if match := goRequirePattern.FindStringSubmatch(line); match != nil {
+			m.add(Package{Name: match[1], Version: match[2], Indirect: strings.Contains(match[3], "// indirect")})
+		}
+	}
+}
+
+func parseGoSum(content string, m *Manifest) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		m.add(Package{Name: fields[0], Version: fields[1]})
+	}
+}
+
+func parsePackageJSON(content []byte, m *Manifest) error {
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return err
+	}
+	for _, deps := range []map[string]string{pkg.Dependencies, pkg.PeerDependencies, pkg.OptionalDependencies} {
+		for name, v := range deps {
+			m.add(Package{Name: name, Version: v})
+		}
+	}
+	for name, v := range pkg.DevDependencies {
+		m.add(Package{Name: name, Version: v, Dev: true})
+	}
+	return nil
+}
+
+// parsePackageLock reads top-level resolved versions from lockfile v2/v3 "packages" or v1
+// "dependencies"; nested node_modules copies are skipped
+func parsePackageLock(content []byte, m *Manifest) error {
+	type entry struct {
+		Version string `json:"version"`
+		Dev     bool   `json:"dev"`
+	}
+	var lock struct {
+		Packages     map[string]entry `json:"packages"`
+		Dependencies map[string]entry `json:"dependencies"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return err
+	}
+	for key, e := range lock.Packages {
+		name, ok := strings.CutPrefix(key, "node_modules/")
+		if !ok || strings.Contains(name, "node_modules/") {
+			continue
+		}
+		m.add(Package{Name: name, Version: e.Version, Dev: e.Dev})
+	}
+	if len(lock.Packages) == 0 {
+		for name, e := range lock.Dependencies {
+			m.add(Package{Name: name, Version: e.Version, Dev: e.Dev})
+		}
+	}
+	return nil
+}
+
+// parseYarnLock handles classic (v1) and Berry lockfiles
+func parseYarnLock(content string, m *Manifest) {
+	var names []string
+	for _, line := range strings.Split(content, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") && strings.HasSuffix(line, ":") {
+			names = names[:0]
+			for _, spec := range strings.Split(strings.TrimSuffix(line, ":"), ",") {
+				if name := yarnSpecName(strings.Trim(strings.TrimSpace(spec), `"`)); name != "" {
+					names = append(names, name)
+				}
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if v, ok := strings.CutPrefix(trimmed, "version"); ok && len(names) > 0 && strings.HasPrefix(line, "  ") && !strings.HasPrefix(line, "   ") {
+			v = strings.Trim(strings.TrimSpace(strings.TrimPrefix(v, ":")), `"`)
+			for _, name := range names {
+				m.add(Package{Name: name, Version: v})
+			}
+			names = names[:0]
+		}
+	}
+}
+
+// yarnSpecName extracts the package from "lodash@^4.17.21" or "@babel/core@npm:^7.0.0"
+func yarnSpecName(spec string) string {
+	at := strings.LastIndex(spec, "@")
+	if at <= 0 {
+		return ""
+	}
+	name := spec[:at]
+	if name == "__metadata" {
+		return ""
+	}
+	return name
+}
+
+func parseRequirements(content string, m *Manifest) {
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if i := strings.Index(line, ";"); i >= 0 {
+			line = line[:i] // Environment marker
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+		if match := requirementLine.FindStringSubmatch(line); match != nil {
+			m.add(Package{Name: normalizePyName(match[1]), Version: strings.TrimSpace(match[2])})
+		}
+	}
+}
+
+// normalizePyName applies PEP 503 normalization
+func normalizePyName(name string) string {
+	return strings.ToLower(pyNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// parseTOMLPackages reads the [[package]] name/version pairs of Cargo.lock and poetry.lock
+func parseTOMLPackages(content string, m *Manifest, normalize func(string) string) {
+	var name, ver string
+	flush := func() {
+		if name != "" {
+			m.add(Package{Name: normalize(name), Version: ver})
+		}
+		name, ver = "", ""
+	}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			flush()
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "name = "); ok {
+			name = strings.Trim(v, `"`)
+		} else if v, ok := strings.CutPrefix(line, "version = "); ok {
+			ver = strings.Trim(v, `"`)
+		}
+	}
+	flush()
+}
+
+// parseCargoToml reads [dependencies], [dev-dependencies], [build-dependencies] and their
+// target-specific variants, in inline ("serde = { version = "1" }") and table
+// ([dependencies.serde]) forms
+func parseCargoToml(content string, m *Manifest) {
+	section, table := "", ""
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if strings.HasPrefix(line, "[") {
+			header := strings.Trim(line, "[] ")
+			section, table = "", ""
+			for _, kind := range []string{"dependencies", "dev-dependencies", "build-dependencies"} {
+				switch {
+				case header == kind || strings.HasSuffix(header, "."+kind):
+					section = kind
+				case strings.Contains(header, kind+"."):
+					section = kind
+					table = header[strings.LastIndex(header, kind+".")+len(kind)+1:]
+				}
+			}
+			continue
+		}
+		match := tomlKeyValue.FindStringSubmatch(line)
+		if section == "" || match == nil {
+			continue
+		}
+		dev := section == "dev-dependencies"
+		key, value := strings.Trim(match[1], `"'`), strings.TrimSpace(match[2])
+		switch {
+		case table != "":
+			if key == "version" {
+				m.add(Package{Name: table, Version: strings.Trim(value, `"`), Dev: dev})
+			}
+		case strings.HasPrefix(value, `"`):
+			m.add(Package{Name: key, Version: strings.Trim(value, `"`), Dev: dev})
+		case strings.HasPrefix(value, "{"):
+			v := ""
+			if vm := tomlInlineVersion.FindStringSubmatch(value); vm != nil {
+				v = vm[1]
+			}
+			m.add(Package{Name: key, Version: v, Dev: dev})
+		}
+	}
+}
+
+// parse dispatches on the file name; content may be empty for a missing file
+func parse(file string, content []byte) (*Manifest, error) {
+	kind, ok := formats[path.Base(file)]
+	if !ok && isRequirementsFile(file) {
+		kind, ok = format{ecosystem: PyPI}, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s is not a supported manifest", file)
+	}
+
+	m := &Manifest{Path: file, Ecosystem: kind.ecosystem, Lockfile: kind.lockfile, Packages: make(map[string]Package)}
+	if len(strings.TrimSpace(string(content))) == 0 {
+		return m, nil
+	}
+	text := string(content)
+	var err error
+	switch path.Base(file) {
+	case "go.mod":
+		parseGoMod(text, m)
+	case "go.sum":
+		parseGoSum(text, m)
+	case "package.json":
+		err = parsePackageJSON(content, m)
+	case "package-lock.json", "npm-shrinkwrap.json":
+		err = parsePackageLock(content, m)
+	case "yarn.lock":
+		parseYarnLock(text, m)
+	case "Cargo.toml":
+		parseCargoToml(text, m)
+	case "Cargo.lock":
+		parseTOMLPackages(text, m, func(s string) string { return s })
+	case "poetry.lock":
+		parseTOMLPackages(text, m, normalizePyName)
+	default:
+		parseRequirements(text, m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return m, nil
+}
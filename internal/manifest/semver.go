@@ -0,0 +1,94 @@
+package manifest
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Bump is the size of a version jump
+type Bump string
+
+const (
+	BumpMajor      Bump = "major"
+	BumpMinor      Bump = "minor"
+	BumpPatch      Bump = "patch"
+	BumpPrerelease Bump = "prerelease"
+)
+
+// version is a parsed semantic version; pre holds the prerelease suffix
+type version struct {
+	major, minor, patch int
+	pre                 string
+}
+
+// parseVersion reads the version in a constraint or pinned version ("v1.2.3", "^1.2",
+// "~=2.0", ">=1.0,<2", "v0.0.0-20240101000000-abcdef"), using its first bound
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimLeft(s, "^~=<>!v ")
+	if i := strings.IndexAny(s, ", |"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i] // Build metadata, including Go's +incompatible
+	}
+
+	var v version
+	core := s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		core, v.pre = s[:i], s[i+1:]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return version{}, false
+	}
+	nums := [3]int{}
+	for i, p := range parts {
+		if p == "x" || p == "*" {
+			break
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return version{}, false
+		}
+		nums[i] = n
+	}
+	v.major, v.minor, v.patch = nums[0], nums[1], nums[2]
+	return v, true
+}
+
+// compare orders versions; a release sorts after its prereleases
+func (v version) compare(o version) int {
+	for _, d := range []int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			return d
+		}
+	}
+	switch {
+	case v.pre == o.pre:
+		return 0
+	case v.pre == "":
+		return 1
+	case o.pre == "":
+		return -1
+	}
+	return strings.Compare(v.pre, o.pre)
+}
+
+// bumpBetween reports the jump between two versions
+// Below 1.0.0 a minor jump may break compatibility, so it counts as major.
+func bumpBetween(a, b version) Bump {
+	switch {
+	case a.major != b.major:
+		return BumpMajor
+	case a.minor != b.minor && a.major == 0:
+		return BumpMajor
+	case a.minor != b.minor:
+		return BumpMinor
+	case a.patch != b.patch:
+		return BumpPatch
+	case a.pre != b.pre:
+		return BumpPrerelease
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+
+	"github.com/rohankatakam/coderisk/internal/blastradius"
+)
+
+// GetBlastRadiusTool implements the crisk.get_blast_radius tool
+type GetBlastRadiusTool struct {
+	graph        blastradius.QueryExecutor
+	cache        blastradius.Cache // Optional: per-commit result cache
+	repoResolver RepoResolver      // Optional: for dynamic repo_id resolution
+}
+
+// NewGetBlastRadiusTool creates a new GetBlastRadiusTool
+func NewGetBlastRadiusTool(graph blastradius.QueryExecutor, cache blastradius.Cache, repoResolver RepoResolver) *GetBlastRadiusTool {
+	return &GetBlastRadiusTool{
+		graph:        graph,
+		cache:        cache,
+		repoResolver: repoResolver,
+	}
+}
+
+// Execute executes the get_blast_radius tool
+// Analyzes file_paths when given, otherwise every uncommitted change under repo_root.
+func (t *GetBlastRadiusTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	repoRoot, _ := args["repo_root"].(string)
+	var files []string
+	if raw, ok := args["file_paths"].([]string); ok {
+		files = raw
+	} else if raw, ok := args["file_paths"].([]interface{}); ok {
+		for _, f := range raw {
+			if s, ok := f.(string); ok {
+				files = append(files, s)
+			}
+		}
+	}
+
+	if len(files) == 0 {
+		changed, err := getUncommittedFiles(repoRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get uncommitted changes (repo_root=%q): %w", repoRoot, err)
+		}
+		if len(changed) == 0 {
+			return map[string]interface{}{
+				"scope":   "all uncommitted changes",
+				"message": "No uncommitted changes found in repository",
+			}, nil
+		}
+		files = changed
+	}
+
+	opts := blastradius.DefaultOptions()
+	if depth, ok := args["max_depth"].(int); ok && depth > 0 {
+		opts.MaxDepth = depth
+	}
+
+	// Unresolved repositories are still analyzed, but without repo scoping or caching
+	var repoID int
+	if repoRoot != "" && t.repoResolver != nil {
+		var err error
+		if repoID, err = t.repoResolver.ResolveRepoID(ctx, repoRoot); err != nil {
+			log.Printf("⚠️  Failed to resolve repo_id from repo_root=%s: %v (querying all repositories)", repoRoot, err)
+			repoID = 0
+		}
+	}
+	headSHA, _ := gitHeadSHA(repoRoot)
+
+	result, err := blastradius.NewEngine(t.graph, t.cache, repoRoot, opts).Compute(ctx, int64(repoID), headSHA, files)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("✅ Blast radius: %s (cached=%v)", result.Summary(), result.Cached)
+
+	return map[string]interface{}{
+		"summary":      "This change " + result.Summary(),
+		"blast_radius": result,
+	}, nil
+}
+
+// GetSchema returns the JSON schema for the tool
+func (t *GetBlastRadiusTool) GetSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Get the transitive blast radius of changed files: direct and transitive dependents and the public entry points they reach",
+		"inputSchema": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"file_paths": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Changed files, relative to the repository root (defaults to uncommitted changes)",
+				},
+				"repo_root": map[string]interface{}{
+					"type":        "string",
+					"description": "Repository root path",
+				},
+				"max_depth": map[string]interface{}{
+					"type":        "integer",
+					"description": "Hops to follow (default: 3)",
+				},
+			},
+		},
+	}
+}
+
+// getUncommittedFiles lists files changed relative to HEAD
+func getUncommittedFiles(repoRoot string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "HEAD", "--name-only")
+	if repoRoot != "" {
+		cmd.Dir = repoRoot
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// gitHeadSHA returns the commit checked out in repoRoot
+func gitHeadSHA(repoRoot string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	if repoRoot != "" {
+		cmd.Dir = repoRoot
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/rohankatakam/coderisk/internal/config"
@@ -24,33 +25,48 @@ func CalculatePhase1WithMultiplePaths(
 	filePaths []string,
 	riskConfig config.AdaptiveRiskConfig,
 ) (*AdaptivePhase1Result, error) {
-	// Calculate baseline metrics across ALL historical paths (handles renames)
-	coupling, err := CalculateCouplingMultiple(ctx, neo4j, repoID, filePaths)
+	result, err := CalculatePartialPhase1(ctx, neo4j, repoID, filePaths, riskConfig)
 	if err != nil {
-		return nil, fmt.Errorf("coupling calculation failed: %w", err)
+		return nil, err
 	}
+	return result, nil
+}
 
-	coChange, err := CalculateCoChangeMultiple(ctx, neo4j, repoID, filePaths)
-	if err != nil {
-		return nil, fmt.Errorf("co-change calculation failed: %w", err)
+// CalculatePartialPhase1 performs the same assessment, but a failed metric query leaves
+// that metric nil (missing data) instead of failing the file
+// The result is always returned; the error joins the failures of individual metrics.
+func CalculatePartialPhase1(
+	ctx context.Context,
+	neo4j *graph.Client,
+	repoID string,
+	filePaths []string,
+	riskConfig config.AdaptiveRiskConfig,
+) (*AdaptivePhase1Result, error) {
+	// Build standard Phase1Result (use first path for display)
+	result := &Phase1Result{FilePath: filePaths[0]}
+	var errs []error
+
+	// Calculate baseline metrics across ALL historical paths (handles renames), overriding
+	// risk classifications using adaptive thresholds
+	if coupling, err := CalculateCouplingMultiple(ctx, neo4j, repoID, filePaths); err != nil {
+		errs = append(errs, fmt.Errorf("coupling calculation failed: %w", err))
+	} else {
+		coupling.RiskLevel = ClassifyCouplingWithThreshold(coupling.Count, riskConfig.CouplingThreshold)
+		result.Coupling = coupling
 	}
 
-	testRatio, err := CalculateTestRatioMultiple(ctx, neo4j, repoID, filePaths)
-	if err != nil {
-		return nil, fmt.Errorf("test ratio calculation failed: %w", err)
+	if coChange, err := CalculateCoChangeMultiple(ctx, neo4j, repoID, filePaths); err != nil {
+		errs = append(errs, fmt.Errorf("co-change calculation failed: %w", err))
+	} else {
+		coChange.RiskLevel = ClassifyCoChangeWithThreshold(coChange.MaxFrequency, riskConfig.CoChangeThreshold)
+		result.CoChange = coChange
 	}
 
-	// Override risk classifications using adaptive thresholds
-	coupling.RiskLevel = ClassifyCouplingWithThreshold(coupling.Count, riskConfig.CouplingThreshold)
-	coChange.RiskLevel = ClassifyCoChangeWithThreshold(coChange.MaxFrequency, riskConfig.CoChangeThreshold)
-	testRatio.RiskLevel = ClassifyTestRatioWithThreshold(testRatio.Ratio, riskConfig.TestRatioThreshold)
-
-	// Build standard Phase1Result (use first path for display)
-	result := &Phase1Result{
-		FilePath:  filePaths[0],
-		Coupling:  coupling,
-		CoChange:  coChange,
-		TestRatio: testRatio,
+	if testRatio, err := CalculateTestRatioMultiple(ctx, neo4j, repoID, filePaths); err != nil {
+		errs = append(errs, fmt.Errorf("test ratio calculation failed: %w", err))
+	} else {
+		testRatio.RiskLevel = ClassifyTestRatioWithThreshold(testRatio.Ratio, riskConfig.TestRatioThreshold)
+		result.TestRatio = testRatio
 	}
 
 	// Apply adaptive escalation logic
@@ -63,7 +79,7 @@ func CalculatePhase1WithMultiplePaths(
 		SelectedConfig: riskConfig,
 	}
 
-	return adaptiveResult, nil
+	return adaptiveResult, errors.Join(errs...)
 }
 
 // CalculatePhase1WithConfig performs Phase 1 assessment using adaptive thresholds (single path - DEPRECATED)
@@ -138,6 +154,21 @@ func ShouldEscalateWithConfig(result *Phase1Result, riskConfig config.AdaptiveRi
 	if result.TestRatio != nil && result.TestRatio.Ratio < riskConfig.TestRatioThreshold {
 		return true
 	}
+	if result.Coverage != nil && result.Coverage.ShouldEscalate() {
+		return true
+	}
+	if result.Incidents != nil && result.Incidents.ShouldEscalate() {
+		return true
+	}
+	if result.Complexity != nil && result.Complexity.ShouldEscalate() {
+		return true
+	}
+	if result.Migration != nil && result.Migration.ShouldEscalate() {
+		return true
+	}
+	if result.APIChanges != nil && result.APIChanges.ShouldEscalate() {
+		return true
+	}
 
 	// Strategy 2: Escalate if we have INSUFFICIENT DATA (lack of confidence)
 	// Missing data should trigger investigation, not give false confidence
@@ -151,6 +182,12 @@ func ShouldEscalateWithConfig(result *Phase1Result, riskConfig config.AdaptiveRi
 	if result.TestRatio != nil && result.TestRatio.Ratio > 0 {
 		hasNoData = false
 	}
+	if result.Coverage != nil {
+		hasNoData = false // Measured coverage is data even when nothing changed
+	}
+	if result.Incidents != nil && result.Incidents.TotalIncidents > 0 {
+		hasNoData = false
+	}
 
 	// If ALL metrics are zero/missing, we have no confidence - escalate
 	if hasNoData {
@@ -217,6 +254,26 @@ func (a *AdaptivePhase1Result) FormatSummaryWithConfig() string {
 		summary += "\n"
 	}
 
+	if a.Coverage != nil {
+		summary += fmt.Sprintf("  • Changed-Line Coverage: %s\n", a.Coverage.FormatEvidence())
+	}
+
+	if a.Incidents != nil {
+		summary += fmt.Sprintf("  • Incidents: %s\n", a.Incidents.FormatEvidence())
+	}
+
+	if a.Complexity != nil {
+		summary += fmt.Sprintf("  • Complexity: %s\n", a.Complexity.FormatEvidence())
+	}
+
+	if a.Migration != nil {
+		summary += fmt.Sprintf("  • Migration: %s\n", a.Migration.FormatEvidence())
+	}
+
+	if a.APIChanges != nil {
+		summary += fmt.Sprintf("  • API Surface: %s\n", a.APIChanges.FormatEvidence())
+	}
+
 	if a.ConfigReason != "" {
 		summary += fmt.Sprintf("\nConfig Selection: %s\n", a.ConfigReason)
 	}
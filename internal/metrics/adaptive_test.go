@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/rohankatakam/coderisk/internal/config"
@@ -107,14 +108,14 @@ func TestShouldEscalateWithConfig(t *testing.T) {
 	tests := []struct {
 		name       string
 		result     *Phase1Result
-		config     config.RiskConfig
+		config     config.AdaptiveRiskConfig
 		shouldEscalate bool
 	}{
 		{
 			name: "Python web - normal file (no escalation)",
 			result: &Phase1Result{
-				Coupling:  &CouplingResult{Count: 10},   // ≤ 15 threshold
-				CoChange:  &CoChangeResult{MaxFrequency: 0.5}, // ≤ 0.75 threshold
+				Coupling:  &CouplingResult{Count: 5},   // ≤ 15 threshold and the risk-signal floor of 5
+				CoChange:  &CoChangeResult{MaxFrequency: 0.4}, // ≤ 0.75 threshold and the floor of 0.4
 				TestRatio: &TestRatioResult{Ratio: 0.45}, // ≥ 0.4 threshold
 			},
 			config: config.RiskConfigs[config.ConfigKeyPythonWeb],
@@ -133,7 +134,7 @@ func TestShouldEscalateWithConfig(t *testing.T) {
 		{
 			name: "Go backend - normal file (no escalation)",
 			result: &Phase1Result{
-				Coupling:  &CouplingResult{Count: 6},    // ≤ 8 threshold
+				Coupling:  &CouplingResult{Count: 5},    // ≤ 8 threshold and the risk-signal floor
 				CoChange:  &CoChangeResult{MaxFrequency: 0.4}, // ≤ 0.6 threshold
 				TestRatio: &TestRatioResult{Ratio: 0.55}, // ≥ 0.5 threshold
 			},
@@ -151,15 +152,25 @@ func TestShouldEscalateWithConfig(t *testing.T) {
 			shouldEscalate: true,
 		},
 		{
-			name: "TypeScript frontend - normal high coupling (no escalation)",
+			name: "TypeScript frontend - normal file (no escalation)",
 			result: &Phase1Result{
-				Coupling:  &CouplingResult{Count: 18},   // ≤ 20 threshold (normal for frontend)
-				CoChange:  &CoChangeResult{MaxFrequency: 0.7},
+				Coupling:  &CouplingResult{Count: 5},
+				CoChange:  &CoChangeResult{MaxFrequency: 0.4},
 				TestRatio: &TestRatioResult{Ratio: 0.35}, // ≥ 0.3 threshold
 			},
 			config: config.RiskConfigs[config.ConfigKeyTypeScriptFrontend],
 			shouldEscalate: false,
 		},
+		{
+			name: "TypeScript frontend - coupling within threshold shows risk signals (escalate)",
+			result: &Phase1Result{
+				Coupling:  &CouplingResult{Count: 18},   // ≤ 20 threshold, but above the floor of 5
+				CoChange:  &CoChangeResult{MaxFrequency: 0.7},
+				TestRatio: &TestRatioResult{Ratio: 0.35},
+			},
+			config: config.RiskConfigs[config.ConfigKeyTypeScriptFrontend],
+			shouldEscalate: true,
+		},
 		{
 			name: "TypeScript frontend - extremely high coupling (escalate)",
 			result: &Phase1Result{
@@ -173,8 +184,8 @@ func TestShouldEscalateWithConfig(t *testing.T) {
 		{
 			name: "ML project - low test coverage is acceptable (no escalation)",
 			result: &Phase1Result{
-				Coupling:  &CouplingResult{Count: 8},
-				CoChange:  &CoChangeResult{MaxFrequency: 0.5},
+				Coupling:  &CouplingResult{Count: 5},
+				CoChange:  &CoChangeResult{MaxFrequency: 0.4},
 				TestRatio: &TestRatioResult{Ratio: 0.3}, // ≥ 0.25 threshold (acceptable for ML)
 			},
 			config: config.RiskConfigs[config.ConfigKeyMLProject],
@@ -190,6 +201,17 @@ func TestShouldEscalateWithConfig(t *testing.T) {
 			config: config.RiskConfigs[config.ConfigKeyMLProject],
 			shouldEscalate: true,
 		},
+		{
+			name: "Go backend - recorded incidents (escalate)",
+			result: &Phase1Result{
+				Coupling:  &CouplingResult{Count: 5},
+				CoChange:  &CoChangeResult{MaxFrequency: 0.4},
+				TestRatio: &TestRatioResult{Ratio: 0.55},
+				Incidents: &IncidentResult{TotalIncidents: 4, Last90Days: 3, CriticalCount: 1, RiskLevel: RiskLevelHigh},
+			},
+			config: config.RiskConfigs[config.ConfigKeyGoBackend],
+			shouldEscalate: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -216,7 +238,7 @@ func TestAdaptiveThresholds_CompareConfigs(t *testing.T) {
 
 	configs := []struct {
 		name            string
-		config          config.RiskConfig
+		config          config.AdaptiveRiskConfig
 		expectedEscalate bool
 		reason          string
 	}{
@@ -235,8 +257,8 @@ func TestAdaptiveThresholds_CompareConfigs(t *testing.T) {
 		{
 			name:            "TypeScript Frontend",
 			config:          config.RiskConfigs[config.ConfigKeyTypeScriptFrontend],
-			expectedEscalate: false, // 12 ≤ 20 (frontend allows very high coupling)
-			reason:          "TypeScript frontend allows highest coupling (threshold 20)",
+			expectedEscalate: true, // 12 ≤ 20, but above the risk-signal floor of 5
+			reason:          "TypeScript frontend allows highest coupling (threshold 20), but 12 is still a risk signal",
 		},
 	}
 
@@ -289,6 +311,12 @@ func TestFormatSummaryWithConfig(t *testing.T) {
 				Ratio:     0.35,
 				RiskLevel: RiskLevelHigh,
 			},
+			Incidents: &IncidentResult{
+				FilePath:       "app/models/user.py",
+				TotalIncidents: 2,
+				Last90Days:     1,
+				RiskLevel:      RiskLevelMedium,
+			},
 			OverallRisk:    RiskLevelHigh,
 			ShouldEscalate: false,
 			DurationMS:     125,
@@ -306,4 +334,7 @@ func TestFormatSummaryWithConfig(t *testing.T) {
 	if summary == "" {
 		t.Error("Summary should not be empty")
 	}
+	if !strings.Contains(summary, "Incidents: 2 incident") {
+		t.Errorf("Summary should include incident evidence:\n%s", summary)
+	}
 }
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/rohankatakam/coderisk/internal/apisurface"
+)
+
+// APIChangeResult represents the API surface change metric result
+// Source: internal/apisurface comparison of exported Go symbols and OpenAPI/JSON Schema
+// documents before and after the change
+type APIChangeResult struct {
+	FilePath  string              `json:"file_path"`
+	Changes   []apisurface.Change `json:"changes"`    // Breaking changes first
+	Breaking  int                 `json:"breaking"`   // Changes that can break callers or clients
+	RiskLevel RiskLevel           `json:"risk_level"` // LOW, MEDIUM, HIGH
+}
+
+// NewAPIChangeResult builds the metric from one file's API surface changes
+// Returns nil when the file's surface did not change.
+func NewAPIChangeResult(filePath string, changes []apisurface.Change) *APIChangeResult {
+	if len(changes) == 0 {
+		return nil
+	}
+	result := &APIChangeResult{FilePath: filePath, Changes: changes, RiskLevel: RiskLevelLow}
+	for _, c := range changes {
+		if c.Breaking {
+			result.Breaking++
+		}
+		switch RiskLevel(c.Severity) {
+		case RiskLevelHigh:
+			result.RiskLevel = RiskLevelHigh
+		case RiskLevelMedium:
+			if result.RiskLevel == RiskLevelLow {
+				result.RiskLevel = RiskLevelMedium
+			}
+		}
+	}
+	return result
+}
+
+// ShouldEscalate returns true for breaking changes to an API used outside the repository
+func (r *APIChangeResult) ShouldEscalate() bool {
+	return r.RiskLevel == RiskLevelHigh
+}
+
+// FormatEvidence generates human-readable evidence string
+func (r *APIChangeResult) FormatEvidence() string {
+	if r.Breaking == 0 {
+		return fmt.Sprintf("No breaking API changes (%d compatible change(s))", len(r.Changes))
+	}
+	first := r.Changes[0]
+	evidence := "This change " + first.Description
+	if n := len(first.Callers); n > 0 {
+		evidence += fmt.Sprintf(" (%d caller(s))", n)
+	}
+	if more := r.Breaking - 1; more > 0 {
+		evidence += fmt.Sprintf(" (+%d more breaking change(s))", more)
+	}
+	return evidence
+}
+
+// ApplyAPIChanges adds the API surface metric and re-evaluates risk
+func ApplyAPIChanges(result *Phase1Result, api *APIChangeResult) {
+	result.APIChanges = api
+	if api.ShouldEscalate() {
+		result.ShouldEscalate = true
+	}
+	result.OverallRisk = DetermineOverallRiskWithConfig(result)
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/apisurface"
+	"github.com/rohankatakam/coderisk/internal/changetype"
+)
+
+func TestApplyAPIChanges(t *testing.T) {
+	if NewAPIChangeResult("pkg/client/client.go", nil) != nil {
+		t.Error("a file without API changes should have no metric")
+	}
+
+	api := NewAPIChangeResult("pkg/client/client.go", []apisurface.Change{
+		{Symbol: "client.Do", Breaking: true, Severity: "HIGH", Description: "removes func client.Do", Callers: []string{"cmd/app/main.go:run"}},
+		{Symbol: "client.Timeout", Severity: "LOW", Description: "adds const client.Timeout"},
+	})
+	if api.Breaking != 1 || api.RiskLevel != RiskLevelHigh {
+		t.Fatalf("breaking=%d risk=%s, want 1 HIGH", api.Breaking, api.RiskLevel)
+	}
+	if got := api.FormatEvidence(); got != "This change removes func client.Do (1 caller(s))" {
+		t.Errorf("FormatEvidence() = %q", got)
+	}
+
+	result := &Phase1Result{OverallRisk: RiskLevelLow}
+	ApplyAPIChanges(result, api)
+	if result.OverallRisk != RiskLevelHigh || !result.ShouldEscalate {
+		t.Errorf("risk=%s escalate=%v, want HIGH with escalation", result.OverallRisk, result.ShouldEscalate)
+	}
+
+	// A rename refactor that breaks the API is not scaled down
+	ApplyChangeType(result, &changetype.Classification{Kind: changetype.Refactor, Action: changetype.ActionReduce})
+	if result.OverallRisk != RiskLevelHigh || !result.ShouldEscalate {
+		t.Errorf("after refactor classification: risk=%s escalate=%v, want unchanged", result.OverallRisk, result.ShouldEscalate)
+	}
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import "github.com/rohankatakam/coderisk/internal/changetype"
+
+// ApplyChangeType records how the file's change was classified and scales risk for
+// changes that cannot alter behaviour on their own
+// Mechanical refactors, moves with renamed identifiers, test-only and dependency-only
+// changes are lowered one level and not escalated. Other kinds keep their assessment, as
+// do changes for which any metric escalates on its own (a HIGH migration finding, an
+// incident-prone file) and mechanical changes that break an exported API (renaming an
+// exported function).
+func ApplyChangeType(result *Phase1Result, c *changetype.Classification) {
+	result.ChangeType = c
+	if c.Action != changetype.ActionReduce || result.anyMetricEscalates() {
+		return
+	}
+	if result.APIChanges != nil && result.APIChanges.Breaking > 0 {
+		return
+	}
+	result.ShouldEscalate = false
+	switch result.OverallRisk {
+	case RiskLevelHigh:
+		result.OverallRisk = RiskLevelMedium
+	case RiskLevelMedium:
+		result.OverallRisk = RiskLevelLow
+	}
+}
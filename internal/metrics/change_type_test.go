@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/changetype"
+)
+
+func TestApplyChangeType(t *testing.T) {
+	refactor := &changetype.Classification{Kind: changetype.Refactor, Action: changetype.ActionReduce}
+	result := &Phase1Result{OverallRisk: RiskLevelHigh, ShouldEscalate: true}
+	ApplyChangeType(result, refactor)
+	if result.OverallRisk != RiskLevelMedium || result.ShouldEscalate || result.ChangeType != refactor {
+		t.Errorf("refactor: risk=%s escalate=%v, want MEDIUM without escalation", result.OverallRisk, result.ShouldEscalate)
+	}
+
+	// A metric escalating on its own is not overridden by the classification
+	result = &Phase1Result{Migration: &MigrationResult{RiskLevel: RiskLevelHigh}}
+	result.DetermineOverallRisk()
+	ApplyChangeType(result, refactor)
+	if result.OverallRisk != RiskLevelHigh || !result.ShouldEscalate {
+		t.Errorf("refactor with a HIGH migration finding: risk=%s escalate=%v, want unchanged", result.OverallRisk, result.ShouldEscalate)
+	}
+
+	config := &changetype.Classification{Kind: changetype.Config, Action: changetype.ActionFull}
+	result = &Phase1Result{OverallRisk: RiskLevelHigh, ShouldEscalate: true}
+	ApplyChangeType(result, config)
+	if result.OverallRisk != RiskLevelHigh || !result.ShouldEscalate {
+		t.Errorf("config: risk=%s escalate=%v, want unchanged", result.OverallRisk, result.ShouldEscalate)
+	}
+}
@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/rohankatakam/coderisk/internal/complexity"
+	"github.com/rohankatakam/coderisk/internal/git"
+)
+
+// Complexity thresholds for blocks a change makes more complex
+const (
+	complexityHighIncrease  = 10 // Cyclomatic points added to one block
+	complexityHighCeiling   = 20 // Complexity of a grown block
+	complexityMediumCeiling = 10
+	nestingMediumDepth      = 4 // Nesting of a block whose nesting grew
+)
+
+// ComplexityResult represents the block complexity delta metric result
+// Source: internal/complexity measurements of the file before and after the change
+type ComplexityResult struct {
+	FilePath      string             `json:"file_path"`
+	Deltas        []complexity.Delta `json:"deltas"`         // Structural changes, largest increase first
+	MaxCyclomatic int                `json:"max_cyclomatic"` // Most complex block after the change
+	RiskLevel     RiskLevel          `json:"risk_level"`     // LOW, MEDIUM, HIGH
+}
+
+// CalculateComplexity compares the blocks of a file at baseRev with the changed version:
+// the staged one when staged is set, the working tree otherwise
+// Returns nil for languages the analyzer does not support. A file missing at baseRev is
+// new, so each of its blocks counts as added.
+func CalculateComplexity(repoPath, baseRev, filePath string, staged bool) (*ComplexityResult, error) {
+	language := complexity.LanguageOf(filePath)
+	if !complexity.Supported(language) {
+		return nil, nil
+	}
+
+	var before, after []complexity.Block
+	if src, err := git.ShowFile(repoPath, baseRev, filePath); err == nil {
+		before = complexity.Analyze(src, language)
+	}
+	src, err := git.ReadChangedFile(repoPath, filePath, staged)
+	if err != nil {
+		return nil, err
+	}
+	if src != nil {
+		after = complexity.Analyze(src, language)
+	}
+
+	return CompareComplexity(filePath, before, after), nil
+}
+
+// CompareComplexity builds the metric from block measurements before and after a change
+// Changes that only moved a block's length are left out.
+func CompareComplexity(filePath string, before, after []complexity.Block) *ComplexityResult {
+	result := &ComplexityResult{FilePath: filePath}
+	for _, b := range after {
+		result.MaxCyclomatic = max(result.MaxCyclomatic, b.Metrics.Cyclomatic)
+	}
+	for _, d := range complexity.Compare(before, after) {
+		if d.Structural() {
+			result.Deltas = append(result.Deltas, d)
+		}
+	}
+	result.RiskLevel = classifyComplexityRisk(result)
+	return result
+}
+
+// classifyComplexityRisk maps the worst block change to a risk level
+// HIGH: a block gains 10+ decision points, or grows past complexity 20
+// MEDIUM: a block grows past complexity 10, or its nesting grows past 4 levels
+func classifyComplexityRisk(r *ComplexityResult) RiskLevel {
+	level := RiskLevelLow
+	for _, d := range r.Deltas {
+		if d.After == nil {
+			continue
+		}
+		change := d.CyclomaticChange()
+		if change >= complexityHighIncrease || (change > 0 && d.After.Cyclomatic > complexityHighCeiling) {
+			return RiskLevelHigh
+		}
+		deeper := d.Before == nil || d.After.MaxNesting > d.Before.MaxNesting
+		if (change > 0 && d.After.Cyclomatic > complexityMediumCeiling) || (deeper && d.After.MaxNesting > nestingMediumDepth) {
+			level = RiskLevelMedium
+		}
+	}
+	return level
+}
+
+// ShouldEscalate returns true if the change made a block substantially more complex
+func (r *ComplexityResult) ShouldEscalate() bool {
+	return r.RiskLevel == RiskLevelHigh
+}
+
+// FormatEvidence generates human-readable evidence string
+func (r *ComplexityResult) FormatEvidence() string {
+	if len(r.Deltas) == 0 {
+		return fmt.Sprintf("No structural complexity change (most complex block: %d)", r.MaxCyclomatic)
+	}
+	evidence := "This change " + r.Deltas[0].Describe()
+	if more := len(r.Deltas) - 1; more > 0 {
+		evidence += fmt.Sprintf(" (+%d more block(s) changed)", more)
+	}
+	return evidence
+}
+
+// ApplyComplexity adds the complexity metric and re-evaluates risk
+func ApplyComplexity(result *Phase1Result, cx *ComplexityResult) {
+	result.Complexity = cx
+	if cx.ShouldEscalate() {
+		result.ShouldEscalate = true
+	}
+	result.OverallRisk = DetermineOverallRiskWithConfig(result)
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/complexity"
+)
+
+func block(name string, cyclomatic, nesting int) complexity.Block {
+	return complexity.Block{Name: name, Metrics: complexity.Metrics{Cyclomatic: cyclomatic, MaxNesting: nesting, Lines: 10}}
+}
+
+func TestCompareComplexity(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []complexity.Block
+		after  []complexity.Block
+		want   RiskLevel
+	}{
+		{"unchanged", []complexity.Block{block("a", 4, 1)}, []complexity.Block{block("a", 4, 1)}, RiskLevelLow},
+		{"large increase", []complexity.Block{block("a", 12, 2)}, []complexity.Block{block("a", 22, 4)}, RiskLevelHigh},
+		{"grown past ceiling", []complexity.Block{block("a", 20, 2)}, []complexity.Block{block("a", 21, 2)}, RiskLevelHigh},
+		{"grown past medium", []complexity.Block{block("a", 9, 2)}, []complexity.Block{block("a", 12, 2)}, RiskLevelMedium},
+		{"deeper nesting", []complexity.Block{block("a", 5, 4)}, []complexity.Block{block("a", 5, 5)}, RiskLevelMedium},
+		{"simplified", []complexity.Block{block("a", 30, 6)}, []complexity.Block{block("a", 25, 6)}, RiskLevelLow},
+		{"complex new block", nil, []complexity.Block{block("a", 15, 2)}, RiskLevelHigh},
+		{"removed block", []complexity.Block{block("a", 30, 6)}, nil, RiskLevelLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := CompareComplexity("billing.go", tt.before, tt.after)
+			if r.RiskLevel != tt.want {
+				t.Errorf("risk = %s, want %s (deltas %+v)", r.RiskLevel, tt.want, r.Deltas)
+			}
+		})
+	}
+}
+
+func TestComplexityEvidenceAndApply(t *testing.T) {
+	r := CompareComplexity("refunds.go",
+		[]complexity.Block{block("ProcessRefund", 12, 2), block("Format", 2, 0)},
+		[]complexity.Block{block("ProcessRefund", 21, 2), block("Format", 3, 0)})
+	if r.MaxCyclomatic != 21 || len(r.Deltas) != 2 {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+	if got := r.FormatEvidence(); !strings.HasPrefix(got, "This change raised ProcessRefund complexity from 12 to 21") {
+		t.Errorf("FormatEvidence() = %q", got)
+	}
+
+	result := &Phase1Result{FilePath: "refunds.go", OverallRisk: RiskLevelLow}
+	ApplyComplexity(result, r)
+	if !result.ShouldEscalate || result.OverallRisk != RiskLevelHigh {
+		t.Errorf("after ApplyComplexity: escalate=%v risk=%s, want escalation to HIGH", result.ShouldEscalate, result.OverallRisk)
+	}
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/rohankatakam/coderisk/internal/config"
+	"github.com/rohankatakam/coderisk/internal/coverage"
+	"github.com/rohankatakam/coderisk/internal/git"
+)
+
+// uncoveredHighRatio is the share of uncovered changed lines at which coverage is HIGH risk
+const uncoveredHighRatio = 0.5
+
+// CoverageResult represents the changed-line coverage metric result
+// Source: CI coverage imported with `crisk coverage import` for the base commit. When a
+// report exists it replaces the test ratio heuristic for the file.
+type CoverageResult struct {
+	FilePath       string    `json:"file_path"`
+	CommitSHA      string    `json:"commit_sha"`      // Commit the coverage report describes
+	ChangedLines   int       `json:"changed_lines"`   // Changed lines that are executable
+	UncoveredLines int       `json:"uncovered_lines"` // Of those, lines no test executed
+	FileCoverage   float64   `json:"file_coverage"`   // Covered share of the whole file
+	RiskLevel      RiskLevel `json:"risk_level"`      // LOW, MEDIUM, HIGH
+}
+
+// CalculateChangedLineCoverage counts changed lines that no test executes
+// The report describes the base commit, so hunks are read on their base side: modified and
+// deleted lines count by their own hits. Pure insertions have no base lines; they take the
+// coverage of the code block they are inserted into, or of the base lines around them when
+// no block encloses them. Lines the report does not consider executable are skipped.
+func CalculateChangedLineCoverage(filePath, commitSHA string, fc *coverage.FileCoverage, blocks []coverage.BlockCoverage, hunks []git.Hunk) *CoverageResult {
+	result := &CoverageResult{
+		FilePath:     filePath,
+		CommitSHA:    commitSHA,
+		FileCoverage: fc.Ratio(),
+	}
+
+	for _, h := range hunks {
+		if h.OldCount > 0 {
+			for line := h.OldStart; line < h.OldStart+h.OldCount; line++ {
+				hits, executable := fc.Lines[line]
+				if !executable {
+					continue
+				}
+				result.ChangedLines++
+				if hits == 0 {
+					result.UncoveredLines++
+				}
+			}
+			continue
+		}
+
+		if h.NewCount == 0 {
+			continue
+		}
+		covered, known := insertionCoverage(fc, blocks, h.OldStart)
+		if !known {
+			continue
+		}
+		result.ChangedLines += h.NewCount
+		if !covered {
+			result.UncoveredLines += h.NewCount
+		}
+	}
+
+	result.RiskLevel = classifyCoverageRisk(result)
+	return result
+}
+
+// insertionCoverage reports whether lines inserted after base line `after` run under test
+// known is false when neither an enclosing block nor a neighbouring line is executable.
+func insertionCoverage(fc *coverage.FileCoverage, blocks []coverage.BlockCoverage, after int) (covered, known bool) {
+	for _, b := range blocks {
+		if b.StartLine <= after && after < b.EndLine && b.Executable > 0 {
+			return b.Covered > 0, true
+		}
+	}
+
+	for _, line := range []int{after, after + 1} {
+		if hits, ok := fc.Lines[line]; ok {
+			known = true
+			if hits > 0 {
+				return true, true
+			}
+		}
+	}
+	return false, known
+}
+
+// classifyCoverageRisk maps uncovered changed lines to a risk level
+// HIGH: at least half the changed executable lines are untested
+// MEDIUM: some changed lines are untested
+func classifyCoverageRisk(r *CoverageResult) RiskLevel {
+	if r.UncoveredLines == 0 {
+		return RiskLevelLow
+	}
+	if float64(r.UncoveredLines)/float64(r.ChangedLines) >= uncoveredHighRatio {
+		return RiskLevelHigh
+	}
+	return RiskLevelMedium
+}
+
+// ShouldEscalate returns true if most changed lines are not covered by tests
+func (r *CoverageResult) ShouldEscalate() bool {
+	return r.RiskLevel == RiskLevelHigh
+}
+
+// FormatEvidence generates human-readable evidence string
+func (r *CoverageResult) FormatEvidence() string {
+	commit := r.CommitSHA
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	if r.ChangedLines == 0 {
+		return fmt.Sprintf("No executable lines changed (file coverage %.0f%% at %s)", r.FileCoverage*100, commit)
+	}
+	return fmt.Sprintf("%d of %d changed lines not covered by tests (file coverage %.0f%% at %s)",
+		r.UncoveredLines, r.ChangedLines, r.FileCoverage*100, commit)
+}
+
+// ApplyCoverage replaces the test ratio heuristic with measured coverage and re-evaluates risk
+func ApplyCoverage(result *Phase1Result, cov *CoverageResult, riskConfig config.AdaptiveRiskConfig) {
+	result.Coverage = cov
+	result.TestRatio = nil
+	result.ShouldEscalate = ShouldEscalateWithConfig(result, riskConfig)
+	result.OverallRisk = DetermineOverallRiskWithConfig(result)
+}
@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/config"
+	"github.com/rohankatakam/coderisk/internal/coverage"
+	"github.com/rohankatakam/coderisk/internal/git"
+)
+
+func TestCalculateChangedLineCoverage(t *testing.T) {
+	// Lines 10-14 executable in a covered function; 20-24 in an untested one; 30 covered outside blocks
+	fc := &coverage.FileCoverage{Path: "billing/invoice.go", Lines: map[int]int{
+		10: 3, 11: 3, 12: 0, 13: 3, 14: 3,
+		20: 0, 21: 0, 22: 0, 23: 0, 24: 0,
+		30: 1,
+	}}
+	blocks := []coverage.BlockCoverage{
+		{Name: "ComputeTotal", StartLine: 9, EndLine: 15, Covered: 4, Executable: 5},
+		{Name: "ApplyDiscount", StartLine: 19, EndLine: 25, Covered: 0, Executable: 5},
+	}
+
+	tests := []struct {
+		name      string
+		hunks     []git.Hunk
+		changed   int
+		uncovered int
+		risk      RiskLevel
+	}{
+		{"Modified covered lines", []git.Hunk{{OldStart: 10, OldCount: 2, NewStart: 10, NewCount: 2}}, 2, 0, RiskLevelLow},
+		{"Modified lines, one uncovered", []git.Hunk{{OldStart: 11, OldCount: 4, NewStart: 11, NewCount: 4}}, 4, 1, RiskLevelMedium},
+		{"Insertion into untested block", []git.Hunk{{OldStart: 21, OldCount: 0, NewStart: 22, NewCount: 3}}, 3, 3, RiskLevelHigh},
+		{"Insertion into covered block", []git.Hunk{{OldStart: 12, OldCount: 0, NewStart: 13, NewCount: 2}}, 2, 0, RiskLevelLow},
+		{"Insertion next to covered line", []git.Hunk{{OldStart: 30, OldCount: 0, NewStart: 31, NewCount: 1}}, 1, 0, RiskLevelLow},
+		{"Comment-only change", []git.Hunk{{OldStart: 2, OldCount: 3, NewStart: 2, NewCount: 3}}, 0, 0, RiskLevelLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CalculateChangedLineCoverage(fc.Path, "abc1234def", fc, blocks, tt.hunks)
+			if result.ChangedLines != tt.changed || result.UncoveredLines != tt.uncovered {
+				t.Errorf("changed/uncovered = %d/%d, want %d/%d",
+					result.ChangedLines, result.UncoveredLines, tt.changed, tt.uncovered)
+			}
+			if result.RiskLevel != tt.risk {
+				t.Errorf("RiskLevel = %v, want %v", result.RiskLevel, tt.risk)
+			}
+		})
+	}
+}
+
+func TestApplyCoverage(t *testing.T) {
+	result := &Phase1Result{
+		FilePath:  "billing/invoice.go",
+		Coupling:  &CouplingResult{Count: 1, RiskLevel: RiskLevelLow},
+		CoChange:  &CoChangeResult{MaxFrequency: 0.1, RiskLevel: RiskLevelLow},
+		TestRatio: &TestRatioResult{Ratio: 0.0, RiskLevel: RiskLevelHigh},
+	}
+	cfg := config.AdaptiveRiskConfig{CouplingThreshold: 10, CoChangeThreshold: 0.7, TestRatioThreshold: 0.3}
+
+	// Measured coverage of the change overrides a low heuristic test ratio
+	ApplyCoverage(result, &CoverageResult{ChangedLines: 4, UncoveredLines: 0, RiskLevel: RiskLevelLow}, cfg)
+	if result.TestRatio != nil {
+		t.Error("expected test ratio heuristic to be replaced")
+	}
+	if result.ShouldEscalate || result.OverallRisk != RiskLevelLow {
+		t.Errorf("got escalate=%v risk=%v, want false LOW", result.ShouldEscalate, result.OverallRisk)
+	}
+
+	ApplyCoverage(result, &CoverageResult{ChangedLines: 4, UncoveredLines: 3, RiskLevel: RiskLevelHigh}, cfg)
+	if !result.ShouldEscalate || result.OverallRisk != RiskLevelHigh {
+		t.Errorf("got escalate=%v risk=%v, want true HIGH", result.ShouldEscalate, result.OverallRisk)
+	}
+}
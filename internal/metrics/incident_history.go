@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/incidents"
+)
+
+// IncidentResult represents the incident history metric result
+// Source: manually recorded incidents (incident_files) linked via `crisk incident link`
+type IncidentResult struct {
+	FilePath       string     `json:"file_path"`
+	TotalIncidents int        `json:"total_incidents"`
+	Last30Days     int        `json:"last_30_days"`
+	Last90Days     int        `json:"last_90_days"`
+	CriticalCount  int        `json:"critical_count"`
+	HighCount      int        `json:"high_count"`
+	LastIncident   *time.Time `json:"last_incident,omitempty"`
+	RiskLevel      RiskLevel  `json:"risk_level"` // LOW, MEDIUM, HIGH
+}
+
+// CalculateIncidentHistoryMultiple aggregates incident stats across all historical paths of a file
+func CalculateIncidentHistoryMultiple(ctx context.Context, db *incidents.Database, filePaths []string) (*IncidentResult, error) {
+	if len(filePaths) == 0 {
+		return &IncidentResult{RiskLevel: RiskLevelLow}, nil
+	}
+
+	result := &IncidentResult{FilePath: filePaths[0]}
+
+	for _, path := range filePaths {
+		stats, err := db.GetIncidentStats(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get incident stats for %s: %w", path, err)
+		}
+
+		result.TotalIncidents += stats.TotalIncidents
+		result.Last30Days += stats.Last30Days
+		result.Last90Days += stats.Last90Days
+		result.CriticalCount += stats.CriticalCount
+		result.HighCount += stats.HighCount
+
+		if stats.LastIncident != nil && (result.LastIncident == nil || stats.LastIncident.After(*result.LastIncident)) {
+			result.LastIncident = stats.LastIncident
+		}
+	}
+
+	result.RiskLevel = classifyIncidentRisk(result)
+	return result, nil
+}
+
+// classifyIncidentRisk maps incident history to a risk level
+// HIGH: repeated recent incidents, or a recent critical/high incident
+// MEDIUM: any incident on record
+func classifyIncidentRisk(r *IncidentResult) RiskLevel {
+	if r.Last90Days >= 2 {
+		return RiskLevelHigh
+	}
+	if r.Last90Days > 0 && r.CriticalCount+r.HighCount > 0 {
+		return RiskLevelHigh
+	}
+	if r.TotalIncidents > 0 {
+		return RiskLevelMedium
+	}
+	return RiskLevelLow
+}
+
+// FormatEvidence generates human-readable evidence string
+func (r *IncidentResult) FormatEvidence() string {
+	if r.TotalIncidents == 0 {
+		return "No recorded incidents"
+	}
+	evidence := fmt.Sprintf("%d incident(s) on record, %d in last 90 days (%d critical, %d high)",
+		r.TotalIncidents, r.Last90Days, r.CriticalCount, r.HighCount)
+	if r.LastIncident != nil {
+		evidence += fmt.Sprintf(", last on %s", r.LastIncident.Format("2006-01-02"))
+	}
+	return evidence
+}
+
+// ShouldEscalate returns true if this metric triggers Phase 2
+func (r *IncidentResult) ShouldEscalate() bool {
+	return r.RiskLevel == RiskLevelHigh
+}
+
+// ApplyIncidents adds the incident history metric and re-evaluates risk
+func ApplyIncidents(result *Phase1Result, r *IncidentResult) {
+	result.Incidents = r
+	if r.ShouldEscalate() {
+		result.ShouldEscalate = true
+	}
+	result.OverallRisk = DetermineOverallRiskWithConfig(result)
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClassifyIncidentRisk(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   IncidentResult
+		expected RiskLevel
+	}{
+		{"No incidents", IncidentResult{}, RiskLevelLow},
+		{"Old low-severity incident", IncidentResult{TotalIncidents: 1}, RiskLevelMedium},
+		{"Old critical incident", IncidentResult{TotalIncidents: 1, CriticalCount: 1}, RiskLevelMedium},
+		{"Recent medium incident", IncidentResult{TotalIncidents: 1, Last90Days: 1}, RiskLevelMedium},
+		{"Recent critical incident", IncidentResult{TotalIncidents: 1, Last90Days: 1, CriticalCount: 1}, RiskLevelHigh},
+		{"Repeated recent incidents", IncidentResult{TotalIncidents: 2, Last90Days: 2}, RiskLevelHigh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyIncidentRisk(&tt.result); got != tt.expected {
+				t.Errorf("classifyIncidentRisk() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIncidentResult_FormatEvidence(t *testing.T) {
+	last := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	result := &IncidentResult{TotalIncidents: 3, Last90Days: 1, CriticalCount: 1, LastIncident: &last}
+
+	evidence := result.FormatEvidence()
+	if !strings.Contains(evidence, "3 incident(s)") || !strings.Contains(evidence, "2025-03-14") {
+		t.Errorf("FormatEvidence() = %q, missing totals or date", evidence)
+	}
+
+	empty := &IncidentResult{}
+	if got := empty.FormatEvidence(); got != "No recorded incidents" {
+		t.Errorf("FormatEvidence() for empty result = %q", got)
+	}
+}
+
+func TestPhase1Result_IncidentEscalation(t *testing.T) {
+	result := &Phase1Result{
+		Coupling:  &CouplingResult{Count: 1, RiskLevel: RiskLevelLow},
+		Incidents: &IncidentResult{TotalIncidents: 2, Last90Days: 2, RiskLevel: RiskLevelHigh},
+	}
+	result.DetermineOverallRisk()
+
+	if !result.ShouldEscalate {
+		t.Error("recent repeated incidents should escalate")
+	}
+	if result.OverallRisk != RiskLevelHigh {
+		t.Errorf("OverallRisk = %v, want HIGH", result.OverallRisk)
+	}
+}
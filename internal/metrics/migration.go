@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/rohankatakam/coderisk/internal/git"
+	"github.com/rohankatakam/coderisk/internal/migration"
+)
+
+// MigrationResult represents the SQL migration safety metric result
+// Source: internal/migration analysis of the statements a change adds to a migration
+type MigrationResult struct {
+	FilePath   string              `json:"file_path"`
+	Statements int                 `json:"statements"`
+	Findings   []migration.Finding `json:"findings"`   // Destructive or locking operations, in file order
+	RiskLevel  RiskLevel           `json:"risk_level"` // LOW, MEDIUM, HIGH
+}
+
+// CalculateMigration analyzes the statements a change adds to a migration: the staged
+// change when staged is set, the working-tree change otherwise
+// Returns nil for files that are not SQL migrations or no longer exist. Statements
+// already present at baseRev are not reported.
+func CalculateMigration(repoPath, baseRev, filePath string, staged bool, opts migration.Options) (*MigrationResult, error) {
+	after, err := git.ReadChangedFile(repoPath, filePath, staged)
+	if err != nil {
+		return nil, err
+	}
+	if after == nil || !migration.IsMigration(filePath, after) {
+		return nil, nil
+	}
+
+	before, _ := git.ShowFile(repoPath, baseRev, filePath) // Missing at baseRev: a new migration
+	return NewMigrationResult(migration.AnalyzeChange(filePath, before, after, opts)), nil
+}
+
+// NewMigrationResult builds the metric from a migration report
+func NewMigrationResult(report *migration.Report) *MigrationResult {
+	return &MigrationResult{
+		FilePath:   report.File,
+		Statements: report.Statements,
+		Findings:   report.Findings,
+		RiskLevel:  RiskLevel(report.Risk),
+	}
+}
+
+// ShouldEscalate returns true if the migration drops data, rewrites a table, or can
+// fail against existing rows
+func (r *MigrationResult) ShouldEscalate() bool {
+	return r.RiskLevel == RiskLevelHigh
+}
+
+// FormatEvidence generates human-readable evidence string
+func (r *MigrationResult) FormatEvidence() string {
+	if len(r.Findings) == 0 {
+		return fmt.Sprintf("No destructive or locking operations in %d statement(s)", r.Statements)
+	}
+	worst := r.Findings[0]
+	for _, f := range r.Findings[1:] {
+		if RiskLevel(f.Severity) == RiskLevelHigh && RiskLevel(worst.Severity) != RiskLevelHigh {
+			worst = f
+		}
+	}
+	evidence := fmt.Sprintf("This migration %s (line %d)", worst.Message, worst.Line)
+	if more := len(r.Findings) - 1; more > 0 {
+		evidence += fmt.Sprintf(" (+%d more risky operation(s))", more)
+	}
+	return evidence
+}
+
+// ApplyMigration adds the migration metric and re-evaluates risk
+func ApplyMigration(result *Phase1Result, m *MigrationResult) {
+	result.Migration = m
+	if m.ShouldEscalate() {
+		result.ShouldEscalate = true
+	}
+	result.OverallRisk = DetermineOverallRiskWithConfig(result)
+}
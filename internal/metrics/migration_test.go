@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rohankatakam/coderisk/internal/migration"
+)
+
+func TestApplyMigration(t *testing.T) {
+	src := []byte("CREATE INDEX idx_users_email ON users(email);\nALTER TABLE users DROP COLUMN legacy;\n")
+	m := NewMigrationResult(migration.Analyze("migrations/023_users.sql", src, migration.Options{}))
+
+	result := &Phase1Result{FilePath: "migrations/023_users.sql", OverallRisk: RiskLevelLow}
+	ApplyMigration(result, m)
+	if result.OverallRisk != RiskLevelHigh || !result.ShouldEscalate || result.Migration != m {
+		t.Errorf("risk=%s escalate=%v, want HIGH with escalation", result.OverallRisk, result.ShouldEscalate)
+	}
+
+	evidence := m.FormatEvidence()
+	if !strings.HasPrefix(evidence, "This migration drops column users.legacy") || !strings.Contains(evidence, "(line 2) (+1 more") {
+		t.Errorf("FormatEvidence() = %q, want the drop first", evidence)
+	}
+}
@@ -16,6 +16,7 @@ type Registry struct {
 	neo4j    *graph.Client
 	postgres *database.Client
 	logger   *slog.Logger
+	repoPath string // Checkout for the complexity metric; empty disables it
 }
 
 // NewRegistry creates a new metric registry
@@ -27,6 +28,13 @@ func NewRegistry(neo4j *graph.Client, postgres *database.Client) *Registry {
 	}
 }
 
+// WithComplexity enables the block complexity metric, comparing files in the checkout at
+// repoPath against HEAD
+func (r *Registry) WithComplexity(repoPath string) *Registry {
+	r.repoPath = repoPath
+	return r
+}
+
 // CalculatePhase1 executes all Tier 1 metrics for a file
 // Reference: risk_assessment_methodology.md §2.4 - Phase 1 Heuristic
 // 12-factor: Factor 8 - Own your control flow (explicit metric orchestration)
@@ -45,10 +53,11 @@ func (r *Registry) CalculatePhase1(ctx context.Context, repoID, filePath string)
 
 	// Channel for collecting metric results
 	type metricResult struct {
-		coupling  *CouplingResult
-		coChange  *CoChangeResult
-		testRatio *TestRatioResult
-		err       error
+		coupling   *CouplingResult
+		coChange   *CoChangeResult
+		testRatio  *TestRatioResult
+		complexity *ComplexityResult
+		err        error
 	}
 
 	resultChan := make(chan metricResult, 1)
@@ -92,6 +101,19 @@ func (r *Registry) CalculatePhase1(ctx context.Context, repoID, filePath string)
 			}
 		}
 
+		// Calculate block complexity deltas (optional)
+		if r.repoPath != "" {
+			cx, err := CalculateComplexity(r.repoPath, "HEAD", filePath, false)
+			if err != nil {
+				r.logger.Warn("complexity calculation failed", "error", err)
+			} else if cx != nil {
+				mr.complexity = cx
+				if _, err := r.postgres.RecordMetricUse(ctx, "complexity", filePath, cx); err != nil {
+					r.logger.Warn("failed to record complexity metric", "error", err)
+				}
+			}
+		}
+
 		resultChan <- mr
 	}()
 
@@ -105,6 +127,7 @@ func (r *Registry) CalculatePhase1(ctx context.Context, repoID, filePath string)
 	result.Coupling = mr.coupling
 	result.CoChange = mr.coChange
 	result.TestRatio = mr.testRatio
+	result.Complexity = mr.complexity
 
 	// Determine overall risk and escalation decision
 	// Reference: risk_assessment_methodology.md §2.4 - Decision tree
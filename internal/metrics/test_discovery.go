@@ -0,0 +1,227 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxReferenceScanFiles bounds how many nearby test files are read for symbol references
+const maxReferenceScanFiles = 200
+
+// minSymbolLength skips short names that would match unrelated identifiers
+const minSymbolLength = 4
+
+// Top-level declarations whose names tests would reference, per language
+// Go methods are left out: their names (Close, String) are too generic to attribute.
+var (
+	goSymbolPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^func\s+([A-Za-z_]\w*)\s*[\[(]`),
+		regexp.MustCompile(`(?m)^type\s+([A-Za-z_]\w*)`),
+	}
+	pythonSymbolPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^(?:async\s+)?def\s+([A-Za-z_]\w*)`),
+		regexp.MustCompile(`(?m)^class\s+([A-Za-z_]\w*)`),
+	}
+	jsSymbolPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^export\s+(?:default\s+)?(?:async\s+)?(?:function\*?|class|const|let|var|interface|type|enum)\s+([A-Za-z_$][\w$]*)`),
+		regexp.MustCompile(`(?m)^(?:async\s+)?function\*?\s+([A-Za-z_$][\w$]*)`),
+		regexp.MustCompile(`(?m)^class\s+([A-Za-z_$][\w$]*)`),
+	}
+)
+
+// IsTestFile reports whether a path is a test file by Go, pytest, and Jest conventions
+func IsTestFile(path string) bool {
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+
+	switch ext {
+	case ".go":
+		return strings.HasSuffix(name, "_test")
+	case ".py":
+		return strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test")
+	case ".js", ".ts", ".jsx", ".tsx":
+		return strings.HasSuffix(name, ".test") || strings.HasSuffix(name, ".spec") ||
+			strings.Contains(filepath.ToSlash(path), "__tests__/")
+	}
+	return false
+}
+
+// FindTestFiles returns the test files covering a source file, relative to root
+// A test counts when it sits at a conventional path (DiscoverTestFiles) or when a nearby
+// test file references one of the source file's top-level symbols: for Go that is any
+// in-package _test.go file, for Python the tests/ directories, for Jest __tests__.
+func FindTestFiles(root, sourceFile string, source []byte) ([]string, error) {
+	found := make(map[string]bool)
+	for _, candidate := range DiscoverTestFiles(sourceFile) {
+		if fileExists(filepath.Join(root, candidate)) {
+			found[filepath.Clean(candidate)] = true
+		}
+	}
+
+	if symbols := extractSymbols(sourceFile, source); len(symbols) > 0 {
+		references := symbolReferencePattern(symbols)
+
+		nearby, err := nearbyTestFiles(root, sourceFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, testFile := range nearby {
+			if found[testFile] {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(root, testFile))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", testFile, err)
+			}
+			if references.Match(content) {
+				found[testFile] = true
+			}
+		}
+	}
+
+	testFiles := make([]string, 0, len(found))
+	for testFile := range found {
+		testFiles = append(testFiles, testFile)
+	}
+	sort.Strings(testFiles)
+	return testFiles, nil
+}
+
+// extractSymbols returns the top-level names declared in a source file
+func extractSymbols(sourceFile string, source []byte) []string {
+	var patterns []*regexp.Regexp
+	switch filepath.Ext(sourceFile) {
+	case ".go":
+		patterns = goSymbolPatterns
+	case ".py":
+		patterns = pythonSymbolPatterns
+	case ".js", ".ts", ".jsx", ".tsx":
+		patterns = jsSymbolPatterns
+	default:
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, pattern := range patterns {
+		for _, match := range pattern.FindAllSubmatch(source, -1) {
+			name := string(match[1])
+			if len(name) < minSymbolLength || seen[name] || isGenericSymbol(name) {
+				continue
+			}
+			seen[name] = true
+			symbols = append(symbols, name)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// isGenericSymbol reports entry points and dunder names every file of a language declares
+func isGenericSymbol(name string) bool {
+	switch name {
+	case "main", "init", "setup", "default":
+		return true
+	}
+	return strings.HasPrefix(name, "__")
+}
+
+// symbolReferencePattern matches any of the symbols as a whole identifier
+func symbolReferencePattern(symbols []string) *regexp.Regexp {
+	quoted := make([]string, len(symbols))
+	for i, s := range symbols {
+		quoted[i] = regexp.QuoteMeta(s)
+	}
+	return regexp.MustCompile(`(?:^|[^\w$])(?:` + strings.Join(quoted, "|") + `)(?:[^\w$]|$)`)
+}
+
+// nearbyTestFiles lists the test files that could test a source file without following
+// naming conventions, relative to root and capped at maxReferenceScanFiles
+func nearbyTestFiles(root, sourceFile string) ([]string, error) {
+	dir := filepath.Dir(sourceFile)
+
+	var dirs []string
+	recursive := false
+	switch filepath.Ext(sourceFile) {
+	case ".go":
+		dirs = []string{dir} // In-package tests only
+	case ".py":
+		dirs = []string{dir, filepath.Join(dir, "tests"), filepath.Join(filepath.Dir(dir), "tests"), "tests"}
+		recursive = true
+	case ".js", ".ts", ".jsx", ".tsx":
+		dirs = []string{dir, filepath.Join(dir, "__tests__")}
+	default:
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	var testFiles []string
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] && len(testFiles) < maxReferenceScanFiles && IsTestFile(path) {
+			seen[path] = true
+			testFiles = append(testFiles, path)
+		}
+	}
+
+	for _, d := range dirs {
+		abs := filepath.Join(root, d)
+		if recursive && d != dir {
+			err := filepath.WalkDir(abs, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !entry.IsDir() {
+					rel, err := filepath.Rel(root, path)
+					if err != nil {
+						return err
+					}
+					add(rel)
+				}
+				return nil
+			})
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("failed to scan %s for tests: %w", d, err)
+			}
+			continue
+		}
+
+		entries, err := os.ReadDir(abs)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s for tests: %w", d, err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				add(filepath.Join(d, entry.Name()))
+			}
+		}
+	}
+	return testFiles, nil
+}
+
+// countLOC counts non-blank lines
+func countLOC(content []byte) int {
+	loc := 0
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			loc++
+		}
+	}
+	return loc
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -2,10 +2,14 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/rohankatakam/coderisk/internal/git"
 	"github.com/rohankatakam/coderisk/internal/graph"
 )
 
@@ -23,46 +27,83 @@ type TestRatioResult struct {
 // CalculateTestRatio computes test coverage ratio for a file
 // Reference: risk_assessment_methodology.md §2.3
 // Formula: test_ratio = SUM(test_file.loc) / source_file.loc
-// Test file discovery: naming conventions (*_test.py, *.test.js) or graph relationship
+// LOC is measured in the working tree of the current repository (File nodes carry no LOC),
+// so a file that is not checked out, or a run outside a repository, reports no data.
 func CalculateTestRatio(ctx context.Context, neo4j *graph.Client, repoID, filePath string) (*TestRatioResult, error) {
-	// Query Neo4j for source file LOC
-	// TODO: Once graph construction is complete, use actual LOC from File nodes
-	// For now, if no graph data exists, return 0 to indicate insufficient data
-	sourceLOC := 0 // Default: no data
-	testLOC := 0   // Default: no data
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return noTestRatioData(filePath), nil
+	}
+	return CalculateTestRatioInTree(root, filePath)
+}
+
+// CalculateTestRatioInTree computes test coverage ratio for a file relative to a repository root
+// Tests are found by naming conventions (FindTestFiles) and by scanning nearby test files for
+// references to the file's top-level symbols. A test file is its own coverage.
+func CalculateTestRatioInTree(root, filePath string) (*TestRatioResult, error) {
+	source, err := os.ReadFile(filepath.Join(root, filePath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return noTestRatioData(filePath), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
 
-	// Try to query graph for actual file data
-	// If file doesn't exist in graph, sourceLOC and testLOC remain 0
-	// This signals lack of confidence and should trigger escalation
+	sourceLOC := countLOC(source)
+	if IsTestFile(filePath) {
+		return &TestRatioResult{
+			FilePath:  filePath,
+			SourceLOC: sourceLOC,
+			TestLOC:   sourceLOC,
+			Ratio:     1.0,
+			RiskLevel: RiskLevelLow,
+			TestFiles: []string{filePath},
+		}, nil
+	}
+
+	testFiles, err := FindTestFiles(root, filePath, source)
+	if err != nil {
+		return nil, err
+	}
+
+	testLOC := 0
+	for _, testFile := range testFiles {
+		content, err := os.ReadFile(filepath.Join(root, testFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", testFile, err)
+		}
+		testLOC += countLOC(content)
+	}
 
-	// Calculate ratio with special handling for no data
 	// If both are 0, we return 0 to indicate "unknown" (not "perfect coverage")
 	var ratio float64
-	if sourceLOC == 0 && testLOC == 0 {
-		ratio = 0.0 // No data = unknown, not 1.0
-	} else {
-		// Normal smoothing formula when we have data
+	if sourceLOC > 0 || testLOC > 0 {
 		ratio = calculateSmoothedRatio(testLOC, sourceLOC)
 	}
 
-	// Determine risk level
-	riskLevel := classifyTestRatioRisk(ratio)
-
-	result := &TestRatioResult{
+	return &TestRatioResult{
 		FilePath:  filePath,
 		SourceLOC: sourceLOC,
 		TestLOC:   testLOC,
 		Ratio:     ratio,
-		RiskLevel: riskLevel,
-		TestFiles: []string{}, // Empty until we query graph
-	}
+		RiskLevel: classifyTestRatioRisk(ratio),
+		TestFiles: testFiles,
+	}, nil
+}
 
-	return result, nil
+// noTestRatioData is the result for a file that cannot be measured
+// Ratio 0 signals lack of confidence and should trigger escalation
+func noTestRatioData(filePath string) *TestRatioResult {
+	return &TestRatioResult{
+		FilePath:  filePath,
+		RiskLevel: classifyTestRatioRisk(0),
+		TestFiles: []string{},
+	}
 }
 
-// discoverTestFiles finds test files using naming conventions
+// DiscoverTestFiles returns the conventional test file paths for a source file
 // Reference: risk_assessment_methodology.md §2.3 - Naming conventions
-func discoverTestFiles(sourceFile string) []string {
+func DiscoverTestFiles(sourceFile string) []string {
 	base := filepath.Base(sourceFile)
 	dir := filepath.Dir(sourceFile)
 	ext := filepath.Ext(base)
@@ -70,21 +111,27 @@ func discoverTestFiles(sourceFile string) []string {
 
 	var testFiles []string
 
-	// Python: test_*.py, *_test.py
+	// Python: test_*.py, *_test.py, and pytest tests/ directories
+	// (next to the module, beside its package, or mirroring the package under the root)
 	if ext == ".py" {
 		testFiles = append(testFiles,
 			filepath.Join(dir, "test_"+base),
 			filepath.Join(dir, nameWithoutExt+"_test.py"),
 			filepath.Join(dir, "tests", "test_"+base),
+			filepath.Join(filepath.Dir(dir), "tests", "test_"+base),
+			filepath.Join("tests", dir, "test_"+base),
+			filepath.Join("tests", "test_"+base),
 		)
 	}
 
-	// JavaScript/TypeScript: *.test.js, *.spec.js
+	// JavaScript/TypeScript: *.test.js, *.spec.js, and Jest __tests__ directories
 	if ext == ".js" || ext == ".ts" || ext == ".jsx" || ext == ".tsx" {
 		testFiles = append(testFiles,
 			filepath.Join(dir, nameWithoutExt+".test"+ext),
 			filepath.Join(dir, nameWithoutExt+".spec"+ext),
 			filepath.Join(dir, "__tests__", base),
+			filepath.Join(dir, "__tests__", nameWithoutExt+".test"+ext),
+			filepath.Join(dir, "__tests__", nameWithoutExt+".spec"+ext),
 		)
 	}
 
@@ -98,16 +145,6 @@ func discoverTestFiles(sourceFile string) []string {
 	return testFiles
 }
 
-// estimateTestLOC estimates test LOC based on file count
-// Temporary heuristic until graph has actual LOC data
-func estimateTestLOC(testFileCount int) int {
-	if testFileCount == 0 {
-		return 0
-	}
-	// Heuristic: assume 50 LOC per test file
-	return testFileCount * 50
-}
-
 // calculateSmoothedRatio applies smoothing formula from risk_assessment_methodology.md §2.3
 func calculateSmoothedRatio(testLOC, sourceLOC int) float64 {
 	// smoothed_ratio = (test_loc + 1) / (source_loc + 1)
@@ -145,8 +182,17 @@ func CalculateTestRatioMultiple(ctx context.Context, neo4j *graph.Client, repoID
 	if len(filePaths) == 0 {
 		return &TestRatioResult{}, nil
 	}
-	// TODO: Implement proper multi-path query
-	// For now, use first path as proxy
-	return CalculateTestRatio(ctx, neo4j, repoID, filePaths[0])
+
+	// Historical paths are not in the working tree; measure the one that is checked out
+	root, err := git.GetRepoRoot()
+	if err != nil {
+		return noTestRatioData(filePaths[0]), nil
+	}
+	for _, path := range filePaths {
+		if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+			return CalculateTestRatioInTree(root, path)
+		}
+	}
+	return noTestRatioData(filePaths[0]), nil
 }
 
@@ -1,6 +1,10 @@
 package metrics
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -98,10 +102,10 @@ func TestDiscoverTestFiles(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			testFiles := discoverTestFiles(tt.sourceFile)
+			testFiles := DiscoverTestFiles(tt.sourceFile)
 
 			if len(testFiles) == 0 {
-				t.Errorf("discoverTestFiles(%q) returned empty array", tt.sourceFile)
+				t.Errorf("DiscoverTestFiles(%q) returned empty array", tt.sourceFile)
 			}
 
 			// Check that at least one test file path contains the expected pattern
@@ -114,37 +118,13 @@ func TestDiscoverTestFiles(t *testing.T) {
 			}
 
 			if !found {
-				t.Errorf("discoverTestFiles(%q) = %v, should contain path with %q",
+				t.Errorf("DiscoverTestFiles(%q) = %v, should contain path with %q",
 					tt.sourceFile, testFiles, tt.wantContains)
 			}
 		})
 	}
 }
 
-func TestEstimateTestLOC(t *testing.T) {
-	tests := []struct {
-		name          string
-		testFileCount int
-		wantMin       int
-		wantMax       int
-	}{
-		{"No test files", 0, 0, 0},
-		{"One test file", 1, 40, 60},
-		{"Multiple test files", 3, 120, 180},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			loc := estimateTestLOC(tt.testFileCount)
-
-			if loc < tt.wantMin || loc > tt.wantMax {
-				t.Errorf("estimateTestLOC(%d) = %d, want range [%d, %d]",
-					tt.testFileCount, loc, tt.wantMin, tt.wantMax)
-			}
-		})
-	}
-}
-
 func TestTestRatioResult_FormatEvidence(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -188,3 +168,83 @@ func TestTestRatioResult_FormatEvidence(t *testing.T) {
 		})
 	}
 }
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"pkg/foo/bar_test.go", true},
+		{"pkg/foo/bar.go", false},
+		{"tests/test_auth.py", true},
+		{"src/auth_test.py", true},
+		{"src/testing.py", false},
+		{"components/Button.test.tsx", true},
+		{"components/Button.spec.js", true},
+		{"components/__tests__/Button.js", true},
+		{"components/Button.js", false},
+		{"docs/test_plan.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsTestFile(tt.path); got != tt.want {
+			t.Errorf("IsTestFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateTestRatioInTree(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Go: conventional test plus an in-package test that calls a symbol from the file
+	write("pkg/billing/invoice.go", "package billing\n\ntype Invoice struct{}\n\nfunc ComputeTotal(i Invoice) int {\n\treturn 0\n}\n")
+	write("pkg/billing/invoice_test.go", "package billing\n\nfunc TestInvoice(t *testing.T) {\n}\n")
+	write("pkg/billing/rounding_test.go", "package billing\n\nfunc TestRounding(t *testing.T) {\n\tComputeTotal(Invoice{})\n}\n")
+	write("pkg/billing/tax_test.go", "package billing\n\nfunc TestTax(t *testing.T) {\n}\n")
+
+	// Python: pytest tests/ directory that imports the module's function
+	write("app/payments.py", "def charge_card(amount):\n    return amount\n")
+	write("tests/unit/test_checkout.py", "from app.payments import charge_card\n\ndef test_checkout():\n    assert charge_card(1) == 1\n")
+
+	// Untested file
+	write("app/untested.py", "def lonely_function(x):\n"+strings.Repeat("    x += 1\n", 10)+"    return x\n")
+
+	tests := []struct {
+		name      string
+		file      string
+		testFiles []string
+		risk      RiskLevel
+	}{
+		{"Go conventional and in-package", "pkg/billing/invoice.go", []string{"pkg/billing/invoice_test.go", "pkg/billing/rounding_test.go"}, RiskLevelLow},
+		{"Python tests directory", "app/payments.py", []string{"tests/unit/test_checkout.py"}, RiskLevelLow},
+		{"No tests", "app/untested.py", []string{}, RiskLevelHigh},
+		{"Test file covers itself", "pkg/billing/tax_test.go", []string{"pkg/billing/tax_test.go"}, RiskLevelLow},
+		{"Not in working tree", "pkg/billing/deleted.go", []string{}, RiskLevelHigh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateTestRatioInTree(root, tt.file)
+			if err != nil {
+				t.Fatalf("CalculateTestRatioInTree(%q): %v", tt.file, err)
+			}
+			if !reflect.DeepEqual(result.TestFiles, tt.testFiles) {
+				t.Errorf("TestFiles = %v, want %v", result.TestFiles, tt.testFiles)
+			}
+			if result.RiskLevel != tt.risk {
+				t.Errorf("RiskLevel = %v (ratio %.2f, %d/%d LOC), want %v",
+					result.RiskLevel, result.Ratio, result.TestLOC, result.SourceLOC, tt.risk)
+			}
+		})
+	}
+}
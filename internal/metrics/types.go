@@ -1,6 +1,10 @@
 package metrics
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/rohankatakam/coderisk/internal/changetype"
+)
 
 // RiskLevel represents the risk classification for a metric
 // Reference: risk_assessment_methodology.md - Threshold logic across all metrics
@@ -20,13 +24,19 @@ func (r RiskLevel) String() string {
 // Phase1Result aggregates all Tier 1 metric results
 // Reference: risk_assessment_methodology.md §2.4 - Phase 1 Heuristic
 type Phase1Result struct {
-	FilePath       string           `json:"file_path"`
-	OverallRisk    RiskLevel        `json:"overall_risk"`
-	ShouldEscalate bool             `json:"should_escalate"`
-	Coupling       *CouplingResult  `json:"coupling"`
-	CoChange       *CoChangeResult  `json:"co_change"`
-	TestRatio      *TestRatioResult `json:"test_ratio"`
-	DurationMS     int64            `json:"duration_ms"`
+	FilePath       string                     `json:"file_path"`
+	OverallRisk    RiskLevel                  `json:"overall_risk"`
+	ShouldEscalate bool                       `json:"should_escalate"`
+	Coupling       *CouplingResult            `json:"coupling"`
+	CoChange       *CoChangeResult            `json:"co_change"`
+	TestRatio      *TestRatioResult           `json:"test_ratio"`
+	Coverage       *CoverageResult            `json:"coverage,omitempty"`
+	Incidents      *IncidentResult            `json:"incidents,omitempty"`
+	Complexity     *ComplexityResult          `json:"complexity,omitempty"`
+	Migration      *MigrationResult           `json:"migration,omitempty"`   // Set for SQL migration files
+	APIChanges     *APIChangeResult           `json:"api_changes,omitempty"` // Set when the file's API surface changed
+	ChangeType     *changetype.Classification `json:"change_type,omitempty"` // Set when the diff was classified
+	DurationMS     int64                      `json:"duration_ms"`
 }
 
 // DetermineOverallRisk applies Phase 1 heuristic logic
@@ -34,17 +44,7 @@ type Phase1Result struct {
 // Logic: IF (coupling > 10) OR (co_change > 0.7) OR (test_ratio < 0.3) THEN HIGH
 func (p *Phase1Result) DetermineOverallRisk() {
 	// Check escalation conditions (OR logic)
-	shouldEscalate := false
-
-	if p.Coupling != nil && p.Coupling.ShouldEscalate() {
-		shouldEscalate = true
-	}
-	if p.CoChange != nil && p.CoChange.ShouldEscalate() {
-		shouldEscalate = true
-	}
-	if p.TestRatio != nil && p.TestRatio.ShouldEscalate() {
-		shouldEscalate = true
-	}
+	shouldEscalate := p.anyMetricEscalates()
 
 	p.ShouldEscalate = shouldEscalate
 
@@ -56,6 +56,18 @@ func (p *Phase1Result) DetermineOverallRisk() {
 	}
 }
 
+// anyMetricEscalates reports whether any computed metric calls for escalation on its own
+func (p *Phase1Result) anyMetricEscalates() bool {
+	return (p.Coupling != nil && p.Coupling.ShouldEscalate()) ||
+		(p.CoChange != nil && p.CoChange.ShouldEscalate()) ||
+		(p.TestRatio != nil && p.TestRatio.ShouldEscalate()) ||
+		(p.Coverage != nil && p.Coverage.ShouldEscalate()) ||
+		(p.Incidents != nil && p.Incidents.ShouldEscalate()) ||
+		(p.Complexity != nil && p.Complexity.ShouldEscalate()) ||
+		(p.Migration != nil && p.Migration.ShouldEscalate()) ||
+		(p.APIChanges != nil && p.APIChanges.ShouldEscalate())
+}
+
 // aggregateRiskLevel finds the highest risk level among metrics
 func (p *Phase1Result) aggregateRiskLevel() RiskLevel {
 	highest := RiskLevelLow
@@ -69,6 +81,21 @@ func (p *Phase1Result) aggregateRiskLevel() RiskLevel {
 	if p.TestRatio != nil && p.TestRatio.RiskLevel == RiskLevelHigh {
 		return RiskLevelHigh
 	}
+	if p.Coverage != nil && p.Coverage.RiskLevel == RiskLevelHigh {
+		return RiskLevelHigh
+	}
+	if p.Incidents != nil && p.Incidents.RiskLevel == RiskLevelHigh {
+		return RiskLevelHigh
+	}
+	if p.Complexity != nil && p.Complexity.RiskLevel == RiskLevelHigh {
+		return RiskLevelHigh
+	}
+	if p.Migration != nil && p.Migration.RiskLevel == RiskLevelHigh {
+		return RiskLevelHigh
+	}
+	if p.APIChanges != nil && p.APIChanges.RiskLevel == RiskLevelHigh {
+		return RiskLevelHigh
+	}
 
 	if p.Coupling != nil && p.Coupling.RiskLevel == RiskLevelMedium {
 		highest = RiskLevelMedium
@@ -79,6 +106,21 @@ func (p *Phase1Result) aggregateRiskLevel() RiskLevel {
 	if p.TestRatio != nil && p.TestRatio.RiskLevel == RiskLevelMedium {
 		highest = RiskLevelMedium
 	}
+	if p.Coverage != nil && p.Coverage.RiskLevel == RiskLevelMedium {
+		highest = RiskLevelMedium
+	}
+	if p.Incidents != nil && p.Incidents.RiskLevel == RiskLevelMedium {
+		highest = RiskLevelMedium
+	}
+	if p.Complexity != nil && p.Complexity.RiskLevel == RiskLevelMedium {
+		highest = RiskLevelMedium
+	}
+	if p.Migration != nil && p.Migration.RiskLevel == RiskLevelMedium {
+		highest = RiskLevelMedium
+	}
+	if p.APIChanges != nil && p.APIChanges.RiskLevel == RiskLevelMedium {
+		highest = RiskLevelMedium
+	}
 
 	return highest
 }
@@ -87,8 +129,11 @@ func (p *Phase1Result) aggregateRiskLevel() RiskLevel {
 func (p *Phase1Result) FormatSummary() string {
 	summary := fmt.Sprintf("File: %s\n", p.FilePath)
 	summary += fmt.Sprintf("Overall Risk: %s\n", p.OverallRisk)
-	summary += fmt.Sprintf("Phase 2 Escalation: %v\n\n", p.ShouldEscalate)
-	summary += "Evidence (Tier 1 Metrics):\n"
+	summary += fmt.Sprintf("Phase 2 Escalation: %v\n", p.ShouldEscalate)
+	if p.ChangeType != nil {
+		summary += fmt.Sprintf("Change Type: %s\n", p.ChangeType)
+	}
+	summary += "\nEvidence (Tier 1 Metrics):\n"
 
 	if p.Coupling != nil {
 		summary += fmt.Sprintf("  • Coupling: %s\n", p.Coupling.FormatEvidence())
@@ -99,6 +144,21 @@ func (p *Phase1Result) FormatSummary() string {
 	if p.TestRatio != nil {
 		summary += fmt.Sprintf("  • Test Coverage: %s\n", p.TestRatio.FormatEvidence())
 	}
+	if p.Coverage != nil {
+		summary += fmt.Sprintf("  • Changed-Line Coverage: %s\n", p.Coverage.FormatEvidence())
+	}
+	if p.Incidents != nil {
+		summary += fmt.Sprintf("  • Incidents: %s\n", p.Incidents.FormatEvidence())
+	}
+	if p.Complexity != nil {
+		summary += fmt.Sprintf("  • Complexity: %s\n", p.Complexity.FormatEvidence())
+	}
+	if p.Migration != nil {
+		summary += fmt.Sprintf("  • Migration: %s\n", p.Migration.FormatEvidence())
+	}
+	if p.APIChanges != nil {
+		summary += fmt.Sprintf("  • API Surface: %s\n", p.APIChanges.FormatEvidence())
+	}
 
 	summary += fmt.Sprintf("\nDuration: %dms\n", p.DurationMS)
 	return summary
@@ -0,0 +1,57 @@
+package migration
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// migrationDirs are directory names migration tools keep their files in
+var migrationDirs = map[string]bool{
+	"migrations":        true,
+	"migration":         true,
+	"migrate":           true,
+	"schema_migrations": true,
+	"changelog":         true, // liquibase
+	"changesets":        true,
+}
+
+var (
+	// Flyway (V2__add_users.sql, R__views.sql, U2__undo.sql) and numbered or timestamped
+	// names (001_init.sql, 20240101120000_add_index.up.sql)
+	flywayPattern   = regexp.MustCompile(`^(?:[VU]\d+(?:[._]\d+)*|R)__\w+\.sql$`)
+	numberedPattern = regexp.MustCompile(`^\d{3,}[_-][\w.-]+\.sql$`)
+
+	// A schema-changing statement anywhere in the file
+	ddlPattern = regexp.MustCompile(`(?im)^\s*(?:CREATE|ALTER|DROP)\s+(?:UNIQUE\s+)?(?:TABLE|INDEX|COLUMN|SCHEMA|TYPE|VIEW)\b|^\s*TRUNCATE\b`)
+
+	// Markers opening the rollback half of single-file migrations (goose, dbmate,
+	// sql-migrate)
+	downMarkerPattern = regexp.MustCompile(`(?i)^\s*(?:\+goose\s+down|migrate:down|\+migrate\s+down)\b`)
+)
+
+// IsMigration reports whether a file is a SQL migration
+// Files in a migration directory or named like a Flyway or numbered migration qualify by
+// path alone; other .sql files qualify when content (which may be nil) changes schema.
+func IsMigration(file string, content []byte) bool {
+	if !strings.EqualFold(path.Ext(file), ".sql") {
+		return false
+	}
+	name := path.Base(file)
+	if flywayPattern.MatchString(name) || numberedPattern.MatchString(name) ||
+		strings.HasSuffix(name, ".up.sql") || strings.HasSuffix(name, ".down.sql") {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if migrationDirs[strings.ToLower(dir)] {
+			return true
+		}
+	}
+	return content != nil && ddlPattern.Match(content)
+}
+
+// IsRollback reports whether a migration file only runs when rolling back
+func IsRollback(file string) bool {
+	name := path.Base(file)
+	return strings.HasSuffix(name, ".down.sql") || strings.HasPrefix(name, "U") && flywayPattern.MatchString(name)
+}
@@ -0,0 +1,141 @@
+// Package migration analyzes SQL schema migrations for operations that lose data, hold
+// long table locks, or break code still deployed against the old schema: dropped tables
+// and columns, column type changes, NOT NULL columns without a default, index builds and
+// constraint validations that block writes, renames, and unbounded deletes.
+package migration
+
+import "strings"
+
+// Operation names a risky migration operation
+type Operation string
+
+const (
+	DropTable       Operation = "drop_table"
+	DropSchema      Operation = "drop_schema"
+	DropColumn      Operation = "drop_column"
+	AlterType       Operation = "alter_column_type"
+	AddNotNull      Operation = "add_not_null_column"
+	SetNotNull      Operation = "set_not_null"
+	BlockingIndex   Operation = "blocking_index"
+	AddConstraint   Operation = "add_constraint"
+	Rename          Operation = "rename"
+	Truncate        Operation = "truncate"
+	UnboundedDelete Operation = "unbounded_delete"
+	UnboundedUpdate Operation = "unbounded_update"
+)
+
+// Finding is one risky operation in a migration
+type Finding struct {
+	Line      int       `json:"line"`
+	Operation Operation `json:"operation"`
+	Table     string    `json:"table,omitempty"`
+	Column    string    `json:"column,omitempty"`
+	Severity  string    `json:"severity"` // LOW, MEDIUM, HIGH
+	Message   string    `json:"message"`
+	Statement string    `json:"statement"`
+}
+
+// Report is the analysis of one migration file
+type Report struct {
+	File       string    `json:"file"`
+	Statements int       `json:"statements"` // Statements analyzed
+	Rollback   bool      `json:"rollback,omitempty"`
+	Findings   []Finding `json:"findings"`
+	Risk       string    `json:"risk"` // Highest severity among findings
+}
+
+// Options tunes the analysis
+type Options struct {
+	// LargeTables are tables big enough that blocking index builds, validations and
+	// rewrites cause outages; operations on them are raised to HIGH
+	LargeTables []string
+}
+
+var riskRank = map[string]int{"LOW": 0, "MEDIUM": 1, "HIGH": 2}
+
+// maxStatementLength bounds the statement excerpt kept on a finding
+const maxStatementLength = 200
+
+// Analyze reports the risky operations in a migration file
+func Analyze(file string, src []byte, opts Options) *Report {
+	return AnalyzeChange(file, nil, src, opts)
+}
+
+// AnalyzeChange reports the risky operations a change adds to a migration file
+// Statements already present in before (nil for a new file) are not reported, so editing
+// an applied migration only flags what the edit introduced. Rollback files and the
+// rollback sections of single-file migrations are parsed but not flagged.
+func AnalyzeChange(file string, before, after []byte, opts Options) *Report {
+	report := &Report{File: file, Rollback: IsRollback(file), Findings: []Finding{}, Risk: "LOW"}
+
+	existing := make(map[string]bool)
+	for _, s := range Split(string(before)) {
+		existing[strings.ToLower(s.Text)] = true
+	}
+
+	a := &analyzer{large: tableSet(opts.LargeTables), created: make(map[string]bool)}
+	for _, s := range Split(string(after)) {
+		if s.Down {
+			continue
+		}
+		report.Statements++
+		findings := a.statement(s)
+		if report.Rollback || existing[strings.ToLower(s.Text)] {
+			continue
+		}
+		for _, f := range findings {
+			f.Line = s.Line
+			f.Statement = excerpt(s.Text)
+			report.Findings = append(report.Findings, f)
+			if riskRank[f.Severity] > riskRank[report.Risk] {
+				report.Risk = f.Severity
+			}
+		}
+	}
+	return report
+}
+
+// Count returns the number of findings at a severity
+func (r *Report) Count(severity string) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// tableSet builds a lookup of unquoted, lowercased table names
+func tableSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		if n = unquote(strings.TrimSpace(n)); n != "" {
+			set[n] = true
+		}
+	}
+	return set
+}
+
+// has matches a table with or without its schema qualifier
+func has(set map[string]bool, table string) bool {
+	if set[table] {
+		return true
+	}
+	if i := strings.LastIndexByte(table, '.'); i >= 0 {
+		return set[table[i+1:]]
+	}
+	return false
+}
+
+// unquote lowercases an identifier and strips its quoting
+func unquote(ident string) string {
+	return strings.ToLower(strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "").Replace(ident))
+}
+
+func excerpt(text string) string {
+	if len(text) <= maxStatementLength {
+		return text
+	}
+	return text[:maxStatementLength-3] + "..."
+}
@@ -0,0 +1,143 @@
+package migration
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsMigration(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    bool
+	}{
+		{"migrations/022_code_block_complexity.sql", "", true},
+		{"db/migrate/20240101120000_add_index.up.sql", "", true},
+		{"sql/V2__add_users.sql", "", true},
+		{"sql/R__views.sql", "", true},
+		{"schema/001_init.sql", "", true},
+		{"scripts/report.sql", "SELECT count(*) FROM users;", false},
+		{"scripts/fix.sql", "-- one-off\nALTER TABLE users DROP COLUMN legacy;", true},
+		{"migrations/README.md", "", false},
+	}
+	for _, tt := range tests {
+		var content []byte
+		if tt.content != "" {
+			content = []byte(tt.content)
+		}
+		if got := IsMigration(tt.file, content); got != tt.want {
+			t.Errorf("IsMigration(%s) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	src := `-- Migration: add audit trigger; semicolons in comments don't count
+/* block; comment */
+INSERT INTO notes (body) VALUES ('a;b', 'it''s; fine');
+
+CREATE FUNCTION audit() RETURNS trigger AS $body$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql;
+
+-- +goose Down
+DROP FUNCTION audit`
+
+	got := Split(src)
+	if len(got) != 3 {
+		t.Fatalf("got %d statements, want 3: %+v", len(got), got)
+	}
+	if got[0].Line != 3 || got[0].Text != `INSERT INTO notes (body) VALUES ('a;b', 'it''s; fine')` {
+		t.Errorf("statement 1 = %+v", got[0])
+	}
+	if got[1].Line != 5 || !strings.HasSuffix(got[1].Text, "$body$ LANGUAGE plpgsql") || got[1].Down {
+		t.Errorf("statement 2 = %+v", got[1])
+	}
+	if got[2].Line != 13 || !got[2].Down {
+		t.Errorf("statement 3 = %+v, want a rollback statement on line 13", got[2])
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	src := `CREATE TABLE invoices (id BIGSERIAL PRIMARY KEY, total INT NOT NULL);
+CREATE INDEX idx_invoices_total ON invoices(total);
+ALTER TABLE invoices ADD COLUMN customer_id BIGINT NOT NULL;
+
+ALTER TABLE users
+    DROP COLUMN legacy_name,
+    ADD COLUMN status TEXT NOT NULL,
+    ADD COLUMN region TEXT NOT NULL DEFAULT 'us',
+    ALTER COLUMN email TYPE VARCHAR(320),
+    DROP CONSTRAINT users_legacy_check;
+ALTER TABLE users ALTER COLUMN status SET NOT NULL;
+CREATE INDEX idx_users_status ON users(status);
+CREATE INDEX CONCURRENTLY idx_users_region ON users(region);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_ref ON public.orders (ref);
+ALTER TABLE orders ADD CONSTRAINT orders_user_fk FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE orders ADD CONSTRAINT orders_total_check CHECK (total > 0) NOT VALID;
+ALTER TABLE orders RENAME COLUMN ref TO reference;
+DELETE FROM sessions;
+DELETE FROM sessions WHERE expires_at < now();
+DROP TABLE IF EXISTS old_orders, "Archive" CASCADE;`
+
+	r := Analyze("migrations/023_users.sql", []byte(src), Options{LargeTables: []string{"orders"}})
+
+	type key struct {
+		op       Operation
+		target   string
+		severity string
+		line     int
+	}
+	var got []key
+	for _, f := range r.Findings {
+		target := f.Table
+		if f.Column != "" {
+			target += "." + f.Column
+		}
+		got = append(got, key{f.Operation, target, f.Severity, f.Line})
+	}
+	want := []key{
+		{DropColumn, "users.legacy_name", "HIGH", 5},
+		{AddNotNull, "users.status", "HIGH", 5},
+		{AlterType, "users.email", "HIGH", 5},
+		{SetNotNull, "users.status", "MEDIUM", 11},
+		{BlockingIndex, "users", "MEDIUM", 12},
+		{BlockingIndex, "public.orders", "HIGH", 14},
+		{AddConstraint, "orders", "HIGH", 15},
+		{Rename, "orders.ref", "MEDIUM", 17},
+		{UnboundedDelete, "sessions", "HIGH", 18},
+		{DropTable, "old_orders", "HIGH", 20},
+		{DropTable, "archive", "HIGH", 20},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d findings, want %d:\n%+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("finding %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if r.Risk != "HIGH" || r.Statements != 14 || r.Count("MEDIUM") != 3 {
+		t.Errorf("report risk %s, %d statements, %d medium", r.Risk, r.Statements, r.Count("MEDIUM"))
+	}
+}
+
+func TestAnalyzeChange(t *testing.T) {
+	before := []byte("ALTER TABLE users DROP COLUMN legacy;\n")
+	after := []byte("ALTER TABLE users DROP COLUMN legacy;\nALTER TABLE users RENAME TO accounts;\n")
+
+	r := AnalyzeChange("migrations/005_users.sql", before, after, Options{})
+	if len(r.Findings) != 1 || r.Findings[0].Operation != Rename || r.Findings[0].Line != 2 {
+		t.Fatalf("findings = %+v, want only the added rename", r.Findings)
+	}
+	if r.Risk != "MEDIUM" {
+		t.Errorf("risk = %s, want MEDIUM", r.Risk)
+	}
+
+	if r := Analyze("migrations/005_users.down.sql", after, Options{}); len(r.Findings) != 0 || !r.Rollback {
+		t.Errorf("rollback migration flagged: %+v", r)
+	}
+}
@@ -0,0 +1,112 @@
+package migration
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Statement is one SQL statement of a migration
+type Statement struct {
+	Line int    // 1-based line the statement starts on
+	Text string // Statement with comments removed, whitespace collapsed and no trailing semicolon
+	Down bool   // After a rollback marker (-- +goose Down, -- migrate:down)
+}
+
+var dollarTagPattern = regexp.MustCompile(`^\$(?:[A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// Split breaks SQL source into statements at top-level semicolons
+// Semicolons inside quoted strings and identifiers, comments, and dollar-quoted bodies
+// (PL/pgSQL functions, DO blocks) do not end a statement.
+func Split(src string) []Statement {
+	var (
+		statements []Statement
+		buf        strings.Builder
+		line       = 1
+		start      = 0
+		down       = false
+	)
+	flush := func() {
+		if text := strings.Join(strings.Fields(buf.String()), " "); text != "" {
+			statements = append(statements, Statement{Line: start, Text: text, Down: down})
+		}
+		buf.Reset()
+		start = 0
+	}
+	write := func(s string) {
+		if start == 0 && strings.TrimSpace(s) != "" {
+			start = line
+		}
+		buf.WriteString(s)
+		line += strings.Count(s, "\n")
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '-' && strings.HasPrefix(src[i:], "--"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			if downMarkerPattern.MatchString(src[i+2 : i+end]) {
+				flush()
+				down = true
+			}
+			buf.WriteByte(' ')
+			i += end
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			} else {
+				end += 2
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			buf.WriteByte(' ')
+			i += 2 + end
+		case c == '\'' || c == '"' || c == '`':
+			end := closingQuote(src, i)
+			write(src[i:end])
+			i = end
+		case c == '$' && dollarTagPattern.MatchString(src[i:]):
+			tag := dollarTagPattern.FindString(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				end = len(src) - i
+			} else {
+				end += 2 * len(tag)
+			}
+			write(src[i : i+end])
+			i += end
+		case c == ';':
+			flush()
+			i++
+		default:
+			write(src[i : i+1])
+			i++
+		}
+	}
+	flush()
+	return statements
+}
+
+// closingQuote returns the offset just past the quote closing the one at open
+// A doubled quote character is an escaped quote, as is a backslash inside a string.
+func closingQuote(src string, open int) int {
+	q := src[open]
+	for i := open + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if q == '\'' {
+				i++
+			}
+		case q:
+			if i+1 < len(src) && src[i+1] == q {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(src)
+}
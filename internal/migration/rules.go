@@ -0,0 +1,215 @@
+package migration
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// identExpr matches a possibly schema-qualified, possibly quoted identifier
+const identExpr = "(?:\"[^\"]+\"|`[^`]+`|[\\w$]+)(?:\\.(?:\"[^\"]+\"|`[^`]+`|[\\w$]+))*"
+
+const ident = "(" + identExpr + ")"
+
+var (
+	createTablePattern = regexp.MustCompile(`(?i)^CREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?` + ident)
+	dropTablePattern   = regexp.MustCompile(`(?i)^DROP\s+TABLE\s+(?:IF\s+EXISTS\s+)?(.+?)(?:\s+(?:CASCADE|RESTRICT))?$`)
+	dropSchemaPattern  = regexp.MustCompile(`(?i)^DROP\s+(SCHEMA|DATABASE)\s+(?:IF\s+EXISTS\s+)?` + ident)
+	alterTablePattern  = regexp.MustCompile(`(?i)^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?` + ident + `\s+(.+)$`)
+	renameTablePattern = regexp.MustCompile(`(?i)^RENAME\s+TABLE\s+` + ident + `\s+TO\s+` + ident)
+	createIndexPattern = regexp.MustCompile(`(?i)^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?(?:IF\s+NOT\s+EXISTS\s+)?(?:` + identExpr + `\s+)?ON\s+(?:ONLY\s+)?` + ident)
+	truncatePattern    = regexp.MustCompile(`(?i)^TRUNCATE\s+(?:TABLE\s+)?(?:ONLY\s+)?` + ident)
+	deletePattern      = regexp.MustCompile(`(?i)^DELETE\s+FROM\s+(?:ONLY\s+)?` + ident)
+	updatePattern      = regexp.MustCompile(`(?i)^UPDATE\s+(?:ONLY\s+)?` + ident + `\s`)
+	wherePattern       = regexp.MustCompile(`(?i)\bWHERE\b`)
+
+	// ALTER TABLE actions
+	dropColumnAction    = regexp.MustCompile(`(?i)^DROP\s+(?:(COLUMN)\s+)?(?:IF\s+EXISTS\s+)?` + ident)
+	renameToAction      = regexp.MustCompile(`(?i)^RENAME\s+(?:TO|AS)\s+` + ident)
+	renameColumnAction  = regexp.MustCompile(`(?i)^RENAME\s+(?:(COLUMN)\s+)?` + ident + `\s+TO\s+` + ident)
+	alterTypeAction     = regexp.MustCompile(`(?i)^ALTER\s+(?:COLUMN\s+)?` + ident + `\s+(?:SET\s+DATA\s+)?TYPE\b`)
+	setNotNullAction    = regexp.MustCompile(`(?i)^ALTER\s+(?:COLUMN\s+)?` + ident + `\s+SET\s+NOT\s+NULL\b`)
+	modifyAction        = regexp.MustCompile(`(?i)^(?:MODIFY|CHANGE)\s+(?:COLUMN\s+)?` + ident)
+	addConstraintAction = regexp.MustCompile(`(?i)^ADD\s+(?:CONSTRAINT\s+` + identExpr + `\s+)?(FOREIGN\s+KEY|CHECK|PRIMARY\s+KEY|UNIQUE|EXCLUDE)\b`)
+	addColumnAction     = regexp.MustCompile(`(?i)^ADD\s+(?:(COLUMN)\s+)?(?:IF\s+NOT\s+EXISTS\s+)?` + ident + `\s+(.+)$`)
+
+	notNullPattern    = regexp.MustCompile(`(?i)\bNOT\s+NULL\b`)
+	defaultPattern    = regexp.MustCompile(`(?i)\b(?:DEFAULT|GENERATED|AUTO_INCREMENT|(?:SMALL|BIG)?SERIAL[248]?)\b`)
+	notValidPattern   = regexp.MustCompile(`(?i)\bNOT\s+VALID\b`)
+	usingIndexPattern = regexp.MustCompile(`(?i)\bUSING\s+INDEX\b`)
+)
+
+// notColumns are words that follow ADD, DROP or RENAME in an ALTER TABLE action when it
+// targets something other than a column
+var notColumns = map[string]bool{
+	"constraint": true, "index": true, "key": true, "primary": true, "foreign": true,
+	"unique": true, "check": true, "exclude": true, "partition": true, "trigger": true,
+	"default": true, "fulltext": true, "spatial": true,
+}
+
+// analyzer carries state across the statements of one migration
+type analyzer struct {
+	large   map[string]bool
+	created map[string]bool // Tables created earlier in this migration; they hold no rows yet
+}
+
+// statement returns the findings for one statement
+func (a *analyzer) statement(s Statement) []Finding {
+	text := s.Text
+	switch {
+	case createTablePattern.MatchString(text):
+		a.created[unquote(createTablePattern.FindStringSubmatch(text)[1])] = true
+		return nil
+	case dropTablePattern.MatchString(text):
+		var findings []Finding
+		for _, t := range splitTopLevel(dropTablePattern.FindStringSubmatch(text)[1]) {
+			if table := unquote(t); !has(a.created, table) {
+				findings = append(findings, Finding{Operation: DropTable, Table: table, Severity: "HIGH",
+					Message: fmt.Sprintf("drops table %s: its data is lost and code still reading it fails", table)})
+			}
+		}
+		return findings
+	case dropSchemaPattern.MatchString(text):
+		m := dropSchemaPattern.FindStringSubmatch(text)
+		return []Finding{{Operation: DropSchema, Severity: "HIGH",
+			Message: fmt.Sprintf("drops %s %s and every table in it", strings.ToLower(m[1]), unquote(m[2]))}}
+	case alterTablePattern.MatchString(text):
+		m := alterTablePattern.FindStringSubmatch(text)
+		table := unquote(m[1])
+		if has(a.created, table) {
+			return nil
+		}
+		var findings []Finding
+		for _, action := range splitTopLevel(m[2]) {
+			if f, ok := a.alterAction(table, action); ok {
+				findings = append(findings, f)
+			}
+		}
+		return findings
+	case renameTablePattern.MatchString(text):
+		m := renameTablePattern.FindStringSubmatch(text)
+		return []Finding{renamed(unquote(m[1]), "", unquote(m[2]))}
+	case createIndexPattern.MatchString(text):
+		m := createIndexPattern.FindStringSubmatch(text)
+		table := unquote(m[2])
+		if m[1] != "" || has(a.created, table) {
+			return nil
+		}
+		return []Finding{a.locking(Finding{Operation: BlockingIndex, Table: table, Severity: "MEDIUM",
+			Message: fmt.Sprintf("builds an index on %s without CONCURRENTLY, blocking writes until it finishes", table)})}
+	case truncatePattern.MatchString(text):
+		table := unquote(truncatePattern.FindStringSubmatch(text)[1])
+		return []Finding{{Operation: Truncate, Table: table, Severity: "HIGH",
+			Message: fmt.Sprintf("truncates %s, deleting every row", table)}}
+	case deletePattern.MatchString(text) && !wherePattern.MatchString(text):
+		table := unquote(deletePattern.FindStringSubmatch(text)[1])
+		return []Finding{{Operation: UnboundedDelete, Table: table, Severity: "HIGH",
+			Message: fmt.Sprintf("deletes from %s without a WHERE clause", table)}}
+	case updatePattern.MatchString(text) && !wherePattern.MatchString(text):
+		table := unquote(updatePattern.FindStringSubmatch(text)[1])
+		if has(a.created, table) {
+			return nil
+		}
+		return []Finding{a.locking(Finding{Operation: UnboundedUpdate, Table: table, Severity: "MEDIUM",
+			Message: fmt.Sprintf("updates every row of %s in one transaction, locking them until it commits", table)})}
+	}
+	return nil
+}
+
+// alterAction classifies one comma-separated action of an ALTER TABLE statement
+func (a *analyzer) alterAction(table, action string) (Finding, bool) {
+	if m := dropColumnAction.FindStringSubmatch(action); m != nil && (m[1] != "" || !notColumns[strings.ToLower(m[2])]) {
+		column := unquote(m[2])
+		return Finding{Operation: DropColumn, Table: table, Column: column, Severity: "HIGH",
+			Message: fmt.Sprintf("drops column %s.%s: its data is lost and code still selecting it fails", table, column)}, true
+	}
+	if m := renameToAction.FindStringSubmatch(action); m != nil {
+		return renamed(table, "", unquote(m[1])), true
+	}
+	if m := renameColumnAction.FindStringSubmatch(action); m != nil && (m[1] != "" || !notColumns[strings.ToLower(m[2])]) {
+		return renamed(table, unquote(m[2]), unquote(m[3])), true
+	}
+	if m := setNotNullAction.FindStringSubmatch(action); m != nil {
+		column := unquote(m[1])
+		return a.locking(Finding{Operation: SetNotNull, Table: table, Column: column, Severity: "MEDIUM",
+			Message: fmt.Sprintf("sets %s.%s NOT NULL, scanning the whole table under an exclusive lock", table, column)}), true
+	}
+	if m := alterTypeAction.FindStringSubmatch(action); m != nil {
+		return typeChange(table, unquote(m[1])), true
+	}
+	if m := modifyAction.FindStringSubmatch(action); m != nil {
+		return typeChange(table, unquote(m[1])), true
+	}
+	if m := addConstraintAction.FindStringSubmatch(action); m != nil {
+		kind := strings.ToUpper(strings.Join(strings.Fields(m[1]), " "))
+		switch kind {
+		case "FOREIGN KEY", "CHECK":
+			if notValidPattern.MatchString(action) {
+				return Finding{}, false
+			}
+			return a.locking(Finding{Operation: AddConstraint, Table: table, Severity: "MEDIUM",
+				Message: fmt.Sprintf("adds a %s constraint on %s without NOT VALID, validating every row under lock", kind, table)}), true
+		default:
+			if usingIndexPattern.MatchString(action) {
+				return Finding{}, false
+			}
+			return a.locking(Finding{Operation: AddConstraint, Table: table, Severity: "MEDIUM",
+				Message: fmt.Sprintf("adds a %s constraint on %s, building its index while blocking writes", kind, table)}), true
+		}
+	}
+	if m := addColumnAction.FindStringSubmatch(action); m != nil && (m[1] != "" || !notColumns[strings.ToLower(m[2])]) {
+		if notNullPattern.MatchString(m[3]) && !defaultPattern.MatchString(m[3]) {
+			column := unquote(m[2])
+			return Finding{Operation: AddNotNull, Table: table, Column: column, Severity: "HIGH",
+				Message: fmt.Sprintf("adds NOT NULL column %s.%s without a default, which fails on a table that has rows", table, column)}, true
+		}
+	}
+	return Finding{}, false
+}
+
+// locking raises an operation that holds a lock for the length of a table scan to HIGH
+// when the table is known to be large
+func (a *analyzer) locking(f Finding) Finding {
+	if has(a.large, f.Table) {
+		f.Severity = "HIGH"
+		f.Message += " (large table)"
+	}
+	return f
+}
+
+func typeChange(table, column string) Finding {
+	return Finding{Operation: AlterType, Table: table, Column: column, Severity: "HIGH",
+		Message: fmt.Sprintf("changes the type of %s.%s, rewriting the table under an exclusive lock", table, column)}
+}
+
+// renamed reports a table rename (column empty) or a column rename
+func renamed(table, column, to string) Finding {
+	from := table
+	if column != "" {
+		from = table + "." + column
+	}
+	return Finding{Operation: Rename, Table: table, Column: column, Severity: "MEDIUM",
+		Message: fmt.Sprintf("renames %s to %s; code deployed against the old name breaks", from, to)}
+}
+
+// splitTopLevel splits on commas outside parentheses and quotes
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"', '`':
+			i = closingQuote(s, i) - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
@@ -479,6 +479,8 @@ func convertToFileAnalysis(phase1 *metrics.Phase1Result, result *types.RiskResul
 		RiskScore:    fileRisk.RiskScore,
 		Metrics:      metricsMap,
 		Issues:       issues,
+		Migration:    phase1.Migration,
+		APIChanges:   phase1.APIChanges,
 	}
 }
 
@@ -607,6 +609,12 @@ func determineBlockReason(phase1 *metrics.Phase1Result, result *types.RiskResult
 	if phase1.CoChange != nil && phase1.CoChange.ShouldEscalate() {
 		reasons = append(reasons, "High temporal coupling detected")
 	}
+	if phase1.Migration != nil && phase1.Migration.ShouldEscalate() {
+		reasons = append(reasons, "Destructive or table-rewriting schema migration")
+	}
+	if phase1.APIChanges != nil && phase1.APIChanges.ShouldEscalate() {
+		reasons = append(reasons, "Breaking change to a public API")
+	}
 
 	if len(reasons) == 0 {
 		return "High risk detected"
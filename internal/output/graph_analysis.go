@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	"github.com/rohankatakam/coderisk/internal/blastradius"
 	"github.com/rohankatakam/coderisk/internal/graph"
 	"github.com/rohankatakam/coderisk/internal/metrics"
 	"github.com/rohankatakam/coderisk/internal/types"
@@ -12,63 +13,30 @@ import (
 // Note: Helper functions minFloat64/maxFloat64 are defined in ai_converter.go
 
 // CalculateBlastRadius determines impact of changing a file
+// Follows IMPORTS, CALLS and co-change edges transitively (see internal/blastradius);
+// CriticalPaths lists the files holding the public entry points the change can reach.
 // 12-factor: Factor 4 - Tools are structured outputs
 func CalculateBlastRadius(ctx context.Context, filePath string, graphClient *graph.Client) BlastRadius {
 	radius := BlastRadius{}
 
-	// Query 1: Direct dependents (1-hop)
-	directQuery := `
-		MATCH (f:File {path: $filePath})<-[:IMPORTS]-(dep:File)
-		RETURN count(DISTINCT dep) as count
-	`
-
-	result, err := graphClient.ExecuteQuery(ctx, directQuery, map[string]any{
-		"filePath": filePath,
-	})
+	engine := blastradius.NewEngine(graphClient, nil, "", blastradius.DefaultOptions())
+	result, err := engine.Compute(ctx, 0, "", []string{filePath})
 	if err != nil {
 		slog.Warn("blast radius query failed", "error", err, "file", filePath)
 		return radius
 	}
 
-	if len(result) > 0 {
-		if count, ok := result[0]["count"].(int64); ok {
-			radius.DirectDependents = int(count)
-		}
-	}
-
-	// Query 2: Transitive dependents (2-3 hops)
-	transitiveQuery := `
-		MATCH (f:File {path: $filePath})<-[:IMPORTS*1..3]-(dep:File)
-		RETURN count(DISTINCT dep) as count
-	`
-
-	result, err = graphClient.ExecuteQuery(ctx, transitiveQuery, map[string]any{
-		"filePath": filePath,
-	})
-	if err == nil && len(result) > 0 {
-		if count, ok := result[0]["count"].(int64); ok {
-			radius.TransitiveDependents = int(count)
-			radius.TotalAffectedFiles = radius.TransitiveDependents
-		}
-	}
-
-	// Query 3: Critical paths (files on critical execution paths)
-	// Heuristic: Files imported by >10 other files
-	criticalQuery := `
-		MATCH (f:File {path: $filePath})<-[:IMPORTS*1..2]-(dep:File)
-		WHERE size((dep)<-[:IMPORTS]-()) > 10
-		RETURN DISTINCT dep.path as path
-		LIMIT 5
-	`
-
-	result, err = graphClient.ExecuteQuery(ctx, criticalQuery, map[string]any{
-		"filePath": filePath,
-	})
-	if err == nil {
-		for _, row := range result {
-			if path, ok := row["path"].(string); ok {
-				radius.CriticalPaths = append(radius.CriticalPaths, path)
-			}
+	radius.DirectDependents = len(result.Direct)
+	radius.TransitiveDependents = len(result.Transitive)
+	radius.TotalAffectedFiles = result.TotalDependents()
+	radius.ImpactScore = result.Score
+	radius.EntryPoints = result.EntryPoints
+
+	seen := make(map[string]bool)
+	for _, ep := range result.EntryPoints {
+		if !seen[ep.File] {
+			seen[ep.File] = true
+			radius.CriticalPaths = append(radius.CriticalPaths, ep.File)
 		}
 	}
 
@@ -22,6 +22,9 @@ func (f *StandardFormatter) Format(result *types.RiskResult, w io.Writer) error
 		fmt.Fprintf(w, "Branch: %s\n", result.Branch)
 	}
 	fmt.Fprintf(w, "Files changed: %d\n", result.FilesChanged)
+	if result.ChangeType != "" {
+		fmt.Fprintf(w, "Change type: %s\n", result.ChangeType)
+	}
 	fmt.Fprintf(w, "Risk level: %s\n\n", result.RiskLevel)
 
 	// Issues
@@ -1,8 +1,10 @@
 package output
 
 import (
+	"strings"
 	"time"
 
+	"github.com/rohankatakam/coderisk/internal/blastradius"
 	"github.com/rohankatakam/coderisk/internal/git"
 	"github.com/rohankatakam/coderisk/internal/metrics"
 	"github.com/rohankatakam/coderisk/internal/types"
@@ -42,12 +44,14 @@ type AIAssistantAction struct {
 
 // FileAnalysis provides detailed analysis for a single file
 type FileAnalysis struct {
-	Path         string                 `json:"path"`
-	Language     string                 `json:"language"`
-	LinesChanged int                    `json:"lines_changed"`
-	RiskScore    float64                `json:"risk_score"`
-	Metrics      map[string]interface{} `json:"metrics"`
-	Issues       []Issue                `json:"issues"`
+	Path         string                   `json:"path"`
+	Language     string                   `json:"language"`
+	LinesChanged int                      `json:"lines_changed"`
+	RiskScore    float64                  `json:"risk_score"`
+	Metrics      map[string]interface{}   `json:"metrics"`
+	Issues       []Issue                  `json:"issues"`
+	Migration    *metrics.MigrationResult `json:"migration,omitempty"`   // Set for SQL migration files
+	APIChanges   *metrics.APIChangeResult `json:"api_changes,omitempty"` // Set when the file's API surface changed
 }
 
 // Issue represents a detected risk issue in a file
@@ -69,10 +73,12 @@ type GraphAnalysis struct {
 
 // BlastRadius shows impact of changes
 type BlastRadius struct {
-	DirectDependents     int      `json:"direct_dependents"`
-	TotalAffectedFiles   int      `json:"total_affected_files"`
-	CriticalPaths        []string `json:"critical_paths,omitempty"` // Files on critical execution paths
-	TransitiveDependents int      `json:"transitive_dependents"`
+	DirectDependents     int                      `json:"direct_dependents"`
+	TotalAffectedFiles   int                      `json:"total_affected_files"`
+	CriticalPaths        []string                 `json:"critical_paths,omitempty"` // Files on critical execution paths
+	TransitiveDependents int                      `json:"transitive_dependents"`
+	ImpactScore          float64                  `json:"impact_score"`           // Sum of distance-decayed dependent weights
+	EntryPoints          []blastradius.EntryPoint `json:"entry_points,omitempty"` // HTTP handlers, CLI commands, exported APIs reached
 }
 
 // Hotspot identifies risky areas in codebase
@@ -190,6 +196,10 @@ func ConvertPhase1ToRiskResult(phase1 *metrics.Phase1Result) *types.RiskResult {
 		},
 	}
 
+	if phase1.ChangeType != nil {
+		result.ChangeType = phase1.ChangeType.String()
+	}
+
 	// Convert to issues
 	result.Issues = convertToIssues(phase1)
 
@@ -242,6 +252,55 @@ func convertMetrics(phase1 *metrics.Phase1Result) map[string]types.Metric {
 		}
 	}
 
+	if phase1.Coverage != nil {
+		threshold := 0.5
+		uncovered := 0.0
+		if phase1.Coverage.ChangedLines > 0 {
+			uncovered = float64(phase1.Coverage.UncoveredLines) / float64(phase1.Coverage.ChangedLines)
+		}
+		metrics["changed_line_coverage"] = types.Metric{
+			Name:      "Changed Lines Uncovered",
+			Value:     uncovered,
+			Threshold: &threshold,
+		}
+	}
+
+	if phase1.Incidents != nil {
+		threshold := 2.0
+		metrics["incidents"] = types.Metric{
+			Name:      "Incident History (90d)",
+			Value:     float64(phase1.Incidents.Last90Days),
+			Threshold: &threshold,
+		}
+	}
+
+	if phase1.Complexity != nil {
+		threshold := 20.0
+		metrics["complexity"] = types.Metric{
+			Name:      "Max Block Complexity",
+			Value:     float64(phase1.Complexity.MaxCyclomatic),
+			Threshold: &threshold,
+		}
+	}
+
+	if phase1.Migration != nil {
+		threshold := 0.0
+		metrics["migration"] = types.Metric{
+			Name:      "Risky Migration Operations",
+			Value:     float64(len(phase1.Migration.Findings)),
+			Threshold: &threshold,
+		}
+	}
+
+	if phase1.APIChanges != nil {
+		threshold := 0.0
+		metrics["api_changes"] = types.Metric{
+			Name:      "Breaking API Changes",
+			Value:     float64(phase1.APIChanges.Breaking),
+			Threshold: &threshold,
+		}
+	}
+
 	return metrics
 }
 
@@ -278,6 +337,65 @@ func convertToIssues(phase1 *metrics.Phase1Result) []types.RiskIssue {
 		})
 	}
 
+	if phase1.Coverage != nil && phase1.Coverage.RiskLevel != metrics.RiskLevelLow {
+		issues = append(issues, types.RiskIssue{
+			ID:       "CHANGED_LINES_UNCOVERED",
+			Severity: string(phase1.Coverage.RiskLevel),
+			Category: "quality",
+			File:     phase1.FilePath,
+			Message:  phase1.Coverage.FormatEvidence(),
+		})
+	}
+
+	if phase1.Incidents != nil && phase1.Incidents.ShouldEscalate() {
+		issues = append(issues, types.RiskIssue{
+			ID:       "INCIDENT_HISTORY",
+			Severity: string(phase1.Incidents.RiskLevel),
+			Category: "incidents",
+			File:     phase1.FilePath,
+			Message:  phase1.Incidents.FormatEvidence(),
+		})
+	}
+
+	if phase1.Complexity != nil && phase1.Complexity.RiskLevel != metrics.RiskLevelLow {
+		issues = append(issues, types.RiskIssue{
+			ID:       "COMPLEXITY_INCREASE",
+			Severity: string(phase1.Complexity.RiskLevel),
+			Category: "quality",
+			File:     phase1.FilePath,
+			Message:  phase1.Complexity.FormatEvidence(),
+		})
+	}
+
+	if phase1.Migration != nil {
+		for _, f := range phase1.Migration.Findings {
+			issues = append(issues, types.RiskIssue{
+				ID:        "MIGRATION_" + strings.ToUpper(string(f.Operation)),
+				Severity:  f.Severity,
+				Category:  "migration",
+				File:      phase1.FilePath,
+				Message:   f.Message,
+				LineStart: f.Line,
+			})
+		}
+	}
+
+	if phase1.APIChanges != nil {
+		for _, c := range phase1.APIChanges.Changes {
+			if !c.Breaking {
+				continue
+			}
+			issues = append(issues, types.RiskIssue{
+				ID:       "API_BREAKING_CHANGE",
+				Severity: c.Severity,
+				Category: "api",
+				File:     phase1.FilePath,
+				Message:  c.Description,
+				Function: c.Symbol,
+			})
+		}
+	}
+
 	return issues
 }
 
@@ -296,5 +414,25 @@ func generateRecommendations(phase1 *metrics.Phase1Result) []string {
 		recs = append(recs, "Add test coverage for this file")
 	}
 
+	if phase1.Coverage != nil && phase1.Coverage.UncoveredLines > 0 {
+		recs = append(recs, "Add tests that exercise the changed lines")
+	}
+
+	if phase1.Incidents != nil && phase1.Incidents.ShouldEscalate() {
+		recs = append(recs, "Review linked incidents (crisk incident list --file) before merging")
+	}
+
+	if phase1.Complexity != nil && phase1.Complexity.ShouldEscalate() {
+		recs = append(recs, "Split the blocks this change made more complex or cover their new branches with tests")
+	}
+
+	if phase1.Migration != nil && len(phase1.Migration.Findings) > 0 {
+		recs = append(recs, "Make the migration backward compatible: add columns nullable or with a default, build indexes CONCURRENTLY, and drop or rename only after deployed code stops using the old schema")
+	}
+
+	if phase1.APIChanges != nil && phase1.APIChanges.Breaking > 0 {
+		recs = append(recs, "Update the listed callers, or keep the old API alongside the new one and deprecate it before removal")
+	}
+
 	return recs
 }
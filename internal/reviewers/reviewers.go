@@ -0,0 +1,393 @@
+// Package reviewers recommends reviewers for a change from block-level ownership.
+// Candidates are ranked by familiarity with the touched blocks (and, at a discount,
+// the blocks that usually change with them), how recently they worked there, whether
+// they fixed incidents in that code, and how many reviews they were asked for lately.
+package reviewers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/git"
+)
+
+// Weights of the ranking signals (familiarity + recency + incidents = 1)
+const (
+	familiarityWeight = 0.5
+	recencyWeight     = 0.2
+	incidentWeight    = 0.3
+
+	recencyHalfLifeDays = 90.0 // Familiarity from 90 days ago counts half
+	reviewLoadPenalty   = 0.1  // Each recent review request divides the score by (1 + 0.1)
+)
+
+// Options controls candidate selection
+type Options struct {
+	Author          string        // Change author email (excluded)
+	Limit           int           // Maximum candidates (0 = all)
+	MinCouplingRate float64       // Co-change edges below this rate are ignored
+	ReviewWindow    time.Duration // How far back review requests count as load
+	Now             time.Time
+}
+
+// DefaultOptions returns the standard options for an author
+func DefaultOptions(author string) Options {
+	return Options{
+		Author:          author,
+		Limit:           5,
+		MinCouplingRate: 0.3,
+		ReviewWindow:    14 * 24 * time.Hour,
+		Now:             time.Now(),
+	}
+}
+
+// Signals are the raw per-developer inputs to the ranking, keyed by lowercase email
+type Signals struct {
+	Familiarity   map[string]float64   // Sum over blocks of weight × share of the block's edits
+	BlocksKnown   map[string]int       // Touched blocks the developer has edited
+	LastTouched   map[string]time.Time // Most recent commit on the touched or co-changed blocks
+	IncidentFixes map[string]int       // Incidents fixed in those blocks
+	RecentReviews map[string]int       // Review requests within the window
+	Logins        map[string]string    // GitHub login per email, when known
+
+	TouchedBlocks   int
+	CoChangedBlocks int
+}
+
+// Candidate is a ranked reviewer suggestion
+type Candidate struct {
+	Email         string     `json:"email"`
+	Login         string     `json:"login,omitempty"`
+	Score         float64    `json:"score"`
+	Familiarity   float64    `json:"familiarity"` // Normalized 0-1
+	BlocksKnown   int        `json:"blocks_known"`
+	LastTouched   *time.Time `json:"last_touched,omitempty"`
+	IncidentFixes int        `json:"incident_fixes"`
+	RecentReviews int        `json:"recent_reviews"`
+	Reasons       []string   `json:"reasons"`
+}
+
+// Handle returns the CODEOWNERS handle: @login when known, otherwise the email
+func (c Candidate) Handle() string {
+	if c.Login != "" {
+		return "@" + c.Login
+	}
+	return c.Email
+}
+
+// Rank scores every developer with familiarity or incident fixes, excluding the author
+func Rank(s *Signals, opts Options) []Candidate {
+	author := strings.ToLower(strings.TrimSpace(opts.Author))
+	authorLogin := strings.ToLower(s.Logins[author])
+
+	devs := make(map[string]bool)
+	for dev, fam := range s.Familiarity {
+		if fam > 0 {
+			devs[dev] = true
+		}
+	}
+	for dev, n := range s.IncidentFixes {
+		if n > 0 {
+			devs[dev] = true
+		}
+	}
+	delete(devs, author)
+	if authorLogin != "" {
+		for dev := range devs {
+			if strings.EqualFold(s.Logins[dev], authorLogin) {
+				delete(devs, dev)
+			}
+		}
+	}
+
+	maxFamiliarity, maxFixes := 0.0, 0
+	for dev := range devs {
+		maxFamiliarity = math.Max(maxFamiliarity, s.Familiarity[dev])
+		if s.IncidentFixes[dev] > maxFixes {
+			maxFixes = s.IncidentFixes[dev]
+		}
+	}
+
+	candidates := make([]Candidate, 0, len(devs))
+	for dev := range devs {
+		c := Candidate{
+			Email:         dev,
+			Login:         s.Logins[dev],
+			BlocksKnown:   s.BlocksKnown[dev],
+			IncidentFixes: s.IncidentFixes[dev],
+			RecentReviews: s.RecentReviews[dev],
+		}
+		if maxFamiliarity > 0 {
+			c.Familiarity = s.Familiarity[dev] / maxFamiliarity
+		}
+
+		recency := 0.0
+		if last, ok := s.LastTouched[dev]; ok {
+			last := last
+			c.LastTouched = &last
+			days := math.Max(0, opts.Now.Sub(last).Hours()/24)
+			recency = math.Pow(0.5, days/recencyHalfLifeDays)
+		}
+
+		incidents := 0.0
+		if maxFixes > 0 {
+			incidents = float64(c.IncidentFixes) / float64(maxFixes)
+		}
+
+		score := familiarityWeight*c.Familiarity + recencyWeight*recency + incidentWeight*incidents
+		c.Score = round2(score / (1 + reviewLoadPenalty*float64(c.RecentReviews)))
+		c.Familiarity = round2(c.Familiarity)
+		c.Reasons = reasons(c, s, opts.Now)
+
+		candidates = append(candidates, c)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Email < candidates[j].Email
+	})
+
+	if opts.Limit > 0 && len(candidates) > opts.Limit {
+		candidates = candidates[:opts.Limit]
+	}
+	return candidates
+}
+
+func reasons(c Candidate, s *Signals, now time.Time) []string {
+	var out []string
+	if c.BlocksKnown > 0 {
+		out = append(out, fmt.Sprintf("edited %d of %d touched blocks", c.BlocksKnown, s.TouchedBlocks))
+	} else if c.Familiarity > 0 {
+		out = append(out, "edited co-changed blocks")
+	}
+	if c.LastTouched != nil {
+		out = append(out, fmt.Sprintf("last change %d days ago", int(now.Sub(*c.LastTouched).Hours()/24)))
+	}
+	if c.IncidentFixes > 0 {
+		out = append(out, fmt.Sprintf("fixed %d incident(s) here", c.IncidentFixes))
+	}
+	if c.RecentReviews > 0 {
+		out = append(out, fmt.Sprintf("%d recent review request(s)", c.RecentReviews))
+	}
+	return out
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Recommendation is the result for a change: an overall ranking plus per-file owners
+type Recommendation struct {
+	Author          string          `json:"author,omitempty"`
+	TouchedBlocks   int             `json:"touched_blocks"`
+	CoChangedBlocks int             `json:"co_changed_blocks"`
+	Candidates      []Candidate     `json:"candidates"`
+	Files           []FileReviewers `json:"files"`
+}
+
+// FileReviewers is the suggestion for one file
+type FileReviewers struct {
+	Path       string      `json:"path"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// Recommend ranks reviewers for the changed line ranges of each file
+// A file with no ranges counts as fully touched.
+func Recommend(ctx context.Context, db *sqlx.DB, repoID int64, changes map[string][]git.LineRange, opts Options) (*Recommendation, error) {
+	files := make([]string, 0, len(changes))
+	for file := range changes {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	blocks, err := database.GetBlockFamiliarityForFiles(ctx, db, repoID, files)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, logins, err := loadReviewLoad(ctx, db, repoID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	byFile := make(map[string][]database.BlockFamiliarity)
+	var touched []database.BlockFamiliarity
+	for _, block := range blocks {
+		if overlaps(block, changes[block.CanonicalFilePath]) {
+			byFile[block.CanonicalFilePath] = append(byFile[block.CanonicalFilePath], block)
+			touched = append(touched, block)
+		}
+	}
+
+	signals, err := loadSignals(ctx, db, repoID, touched, opts, reviews, logins)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Recommendation{
+		Author:          opts.Author,
+		TouchedBlocks:   signals.TouchedBlocks,
+		CoChangedBlocks: signals.CoChangedBlocks,
+		Candidates:      Rank(signals, opts),
+	}
+
+	fileOpts := opts
+	fileOpts.Limit = 2 // CODEOWNERS-style: a primary and a backup
+	for _, file := range files {
+		fr := FileReviewers{Path: file, Candidates: []Candidate{}}
+		if fileBlocks := byFile[file]; len(fileBlocks) > 0 {
+			fileSignals, err := loadSignals(ctx, db, repoID, fileBlocks, opts, reviews, logins)
+			if err != nil {
+				return nil, err
+			}
+			fr.Candidates = Rank(fileSignals, fileOpts)
+		}
+		rec.Files = append(rec.Files, fr)
+	}
+
+	return rec, nil
+}
+
+// overlaps reports whether a block intersects any changed range (no ranges = whole file)
+func overlaps(block database.BlockFamiliarity, ranges []git.LineRange) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if r.Start <= block.EndLine && r.End >= block.StartLine {
+			return true
+		}
+	}
+	return false
+}
+
+// loadReviewLoad returns recent review requests keyed by email, and the login map
+func loadReviewLoad(ctx context.Context, db *sqlx.DB, repoID int64, opts Options) (map[string]int, map[string]string, error) {
+	logins, err := database.GetLoginsByEmail(ctx, db, repoID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byLogin, err := database.GetRecentReviewRequests(ctx, db, repoID, opts.Now.Add(-opts.ReviewWindow))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reviews := make(map[string]int)
+	for email, login := range logins {
+		if n := byLogin[strings.ToLower(login)]; n > 0 {
+			reviews[email] = n
+		}
+	}
+	return reviews, logins, nil
+}
+
+// loadSignals gathers the ranking inputs for a set of touched blocks
+func loadSignals(ctx context.Context, db *sqlx.DB, repoID int64, touched []database.BlockFamiliarity, opts Options, reviews map[string]int, logins map[string]string) (*Signals, error) {
+	s := &Signals{
+		Familiarity:   make(map[string]float64),
+		BlocksKnown:   make(map[string]int),
+		RecentReviews: reviews,
+		Logins:        logins,
+		TouchedBlocks: len(touched),
+	}
+	if len(touched) == 0 {
+		s.LastTouched = map[string]time.Time{}
+		s.IncidentFixes = map[string]int{}
+		return s, nil
+	}
+
+	touchedIDs := make([]int64, len(touched))
+	isTouched := make(map[int64]bool, len(touched))
+	for i, block := range touched {
+		touchedIDs[i] = block.ID
+		isTouched[block.ID] = true
+		addFamiliarity(s, block.FamiliarityMap, 1.0, true)
+	}
+
+	// Co-changed blocks count in proportion to their strongest coupling rate
+	edges, err := database.GetCoupledBlocks(ctx, db, repoID, touchedIDs, opts.MinCouplingRate)
+	if err != nil {
+		return nil, err
+	}
+	partnerRate := make(map[int64]float64)
+	for _, e := range edges {
+		for _, partner := range []int64{e.BlockA, e.BlockB} {
+			if !isTouched[partner] && e.CouplingRate > partnerRate[partner] {
+				partnerRate[partner] = e.CouplingRate
+			}
+		}
+	}
+
+	allIDs := append([]int64(nil), touchedIDs...)
+	if len(partnerRate) > 0 {
+		partnerIDs := make([]int64, 0, len(partnerRate))
+		for id := range partnerRate {
+			partnerIDs = append(partnerIDs, id)
+		}
+		partners, err := database.GetBlockFamiliarityByIDs(ctx, db, repoID, partnerIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range partners {
+			addFamiliarity(s, block.FamiliarityMap, partnerRate[block.ID], false)
+		}
+		s.CoChangedBlocks = len(partners)
+		allIDs = append(allIDs, partnerIDs...)
+	}
+
+	if s.LastTouched, err = database.GetLastBlockChangeByAuthor(ctx, db, repoID, allIDs); err != nil {
+		return nil, err
+	}
+	if s.IncidentFixes, err = database.GetIncidentFixesByAuthor(ctx, db, repoID, allIDs); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// addFamiliarity adds weight × each developer's share of a block's edits
+func addFamiliarity(s *Signals, familiarity map[string]int, weight float64, touched bool) {
+	total := 0
+	for _, edits := range familiarity {
+		total += edits
+	}
+	if total == 0 {
+		return
+	}
+	for dev, edits := range familiarity {
+		if edits <= 0 {
+			continue
+		}
+		dev = strings.ToLower(strings.TrimSpace(dev))
+		s.Familiarity[dev] += weight * float64(edits) / float64(total)
+		if touched {
+			s.BlocksKnown[dev]++
+		}
+	}
+}
+
+// CODEOWNERS renders the per-file suggestions as CODEOWNERS lines
+func (r *Recommendation) CODEOWNERS() string {
+	var b strings.Builder
+	b.WriteString("# Suggested reviewers (crisk reviewers)\n")
+	for _, f := range r.Files {
+		if len(f.Candidates) == 0 {
+			fmt.Fprintf(&b, "# %s: no ownership history\n", f.Path)
+			continue
+		}
+		handles := make([]string, len(f.Candidates))
+		for i, c := range f.Candidates {
+			handles[i] = c.Handle()
+		}
+		fmt.Fprintf(&b, "/%s %s\n", f.Path, strings.Join(handles, " "))
+	}
+	return b.String()
+}
@@ -0,0 +1,100 @@
+package reviewers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/git"
+)
+
+func TestRank(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := &Signals{Familiarity: map[string]float64{}, BlocksKnown: map[string]int{}}
+	addFamiliarity(s, map[string]int{"Alice@example.com": 8, "bob@example.com": 2}, 1.0, true)
+	addFamiliarity(s, map[string]int{"author@example.com": 5, "bob@example.com": 5}, 1.0, true)
+	addFamiliarity(s, map[string]int{"carol@example.com": 10}, 0.5, false) // co-changed block
+	s.TouchedBlocks, s.CoChangedBlocks = 2, 1
+	s.LastTouched = map[string]time.Time{
+		"alice@example.com": now.AddDate(0, 0, -360),
+		"bob@example.com":   now.AddDate(0, 0, -3),
+	}
+	s.IncidentFixes = map[string]int{"bob@example.com": 2, "dave@example.com": 1}
+	s.RecentReviews = map[string]int{"alice@example.com": 5}
+	s.Logins = map[string]string{"bob@example.com": "bob", "author@example.com": "author"}
+
+	got := Rank(s, Options{Author: "AUTHOR@example.com", Now: now})
+
+	order := make([]string, len(got))
+	for i, c := range got {
+		order[i] = c.Email
+	}
+	want := []string{"bob@example.com", "alice@example.com", "carol@example.com", "dave@example.com"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Fatalf("ranking = %v, want %v", order, want)
+	}
+
+	bob := got[0]
+	if bob.Login != "bob" || bob.Handle() != "@bob" || bob.BlocksKnown != 2 || bob.IncidentFixes != 2 {
+		t.Errorf("unexpected top candidate: %+v", bob)
+	}
+	if alice := got[1]; alice.Familiarity != 1 || alice.RecentReviews != 5 {
+		t.Errorf("expected alice to be most familiar but loaded with reviews, got %+v", alice)
+	}
+	if got[2].Handle() != "carol@example.com" || got[2].Reasons[0] != "edited co-changed blocks" {
+		t.Errorf("unexpected co-change candidate: %+v", got[2])
+	}
+
+	if limited := Rank(s, Options{Author: "author@example.com", Limit: 1, Now: now}); len(limited) != 1 {
+		t.Errorf("expected limit to apply, got %d candidates", len(limited))
+	}
+}
+
+func TestRank_ExcludesAuthorByLogin(t *testing.T) {
+	s := &Signals{
+		Familiarity: map[string]float64{"me@work.com": 1, "me@home.com": 1, "other@work.com": 0.5},
+		Logins:      map[string]string{"me@work.com": "me", "me@home.com": "Me"},
+	}
+
+	got := Rank(s, Options{Author: "me@work.com", Now: time.Now()})
+	if len(got) != 1 || got[0].Email != "other@work.com" {
+		t.Errorf("expected only other@work.com, got %+v", got)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	block := database.BlockFamiliarity{StartLine: 10, EndLine: 20}
+
+	tests := []struct {
+		name   string
+		ranges []git.LineRange
+		want   bool
+	}{
+		{"whole file", nil, true},
+		{"inside", []git.LineRange{{Start: 12, End: 13}}, true},
+		{"edge", []git.LineRange{{Start: 20, End: 25}}, true},
+		{"before", []git.LineRange{{Start: 1, End: 9}}, false},
+		{"after", []git.LineRange{{Start: 21, End: 30}}, false},
+	}
+	for _, tt := range tests {
+		if got := overlaps(block, tt.ranges); got != tt.want {
+			t.Errorf("%s: overlaps = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCODEOWNERS(t *testing.T) {
+	rec := &Recommendation{Files: []FileReviewers{
+		{Path: "internal/auth/login.go", Candidates: []Candidate{{Email: "bob@example.com", Login: "bob"}, {Email: "carol@example.com"}}},
+		{Path: "README.md", Candidates: []Candidate{}},
+	}}
+
+	out := rec.CODEOWNERS()
+	if !strings.Contains(out, "/internal/auth/login.go @bob carol@example.com\n") {
+		t.Errorf("missing owner line:\n%s", out)
+	}
+	if !strings.Contains(out, "# README.md: no ownership history\n") {
+		t.Errorf("missing note for unowned file:\n%s", out)
+	}
+}
@@ -139,16 +139,13 @@ func (idx *BlockIndexer) IndexOwnershipWithSemanticImportance(ctx context.Contex
 }
 
 // IndexOwnershipIncremental updates ownership properties for specific blocks
-// Useful for incremental updates after new commits are processed
+// Useful for incremental updates after new commits are processed or identities are merged
 func (idx *BlockIndexer) IndexOwnershipIncremental(ctx context.Context, repoID int64, blockIDs []int64) error {
 	idx.logger.Info("starting incremental ownership indexing",
 		"repo_id", repoID,
 		"block_count", len(blockIDs))
 
-	// For incremental updates, we just re-run the full calculation
-	// The queries are idempotent and will update only the blocks that need it
-	_, err := idx.IndexOwnership(ctx, repoID)
-	if err != nil {
+	if _, err := idx.calculator.RecalculateBlocks(ctx, repoID, blockIDs); err != nil {
 		return fmt.Errorf("incremental indexing failed: %w", err)
 	}
 
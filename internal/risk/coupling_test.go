@@ -117,7 +117,7 @@ func TestCouplingCalculator_CalculateCoChanges(t *testing.T) {
 	createTestModification(t, db, repoID, blockC, "commit4", "charlie@example.com", baseTime.Add(3*time.Hour))
 
 	// Create coupling calculator (without LLM for testing)
-	calc := NewCouplingCalculator(db, nil, repoID)
+	calc := NewCouplingCalculator(db, nil, nil, "", nil, repoID)
 
 	// Calculate co-changes
 	edgesCreated, err := calc.CalculateCoChanges(ctx)
@@ -187,7 +187,7 @@ func TestCouplingCalculator_GetCoChangeStatistics(t *testing.T) {
 	createTestModification(t, db, repoID, blockC, "commit2", "bob@example.com", baseTime.Add(1*time.Hour))
 
 	// Calculate co-changes
-	calc := NewCouplingCalculator(db, nil, repoID)
+	calc := NewCouplingCalculator(db, nil, nil, "", nil, repoID)
 	_, err := calc.CalculateCoChanges(ctx)
 	require.NoError(t, err)
 
@@ -238,7 +238,7 @@ func TestCouplingCalculator_GetTopCoupledBlocks(t *testing.T) {
 	}
 
 	// Calculate co-changes
-	calc := NewCouplingCalculator(db, nil, repoID)
+	calc := NewCouplingCalculator(db, nil, nil, "", nil, repoID)
 	_, err := calc.CalculateCoChanges(ctx)
 	require.NoError(t, err)
 
@@ -272,7 +272,7 @@ func TestCouplingCalculator_ExplainCoupling(t *testing.T) {
 	llmClient, err := llm.NewClient(ctx, cfg)
 	require.NoError(t, err)
 
-	calc := NewCouplingCalculator(nil, llmClient, 1)
+	calc := NewCouplingCalculator(nil, nil, nil, "", llmClient, 1)
 
 	pair := CoChangePair{
 		BlockA: CodeBlockInfo{
@@ -308,7 +308,7 @@ func TestCouplingCalculator_EdgeCases(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("no code blocks", func(t *testing.T) {
-		calc := NewCouplingCalculator(db, nil, repoID)
+		calc := NewCouplingCalculator(db, nil, nil, "", nil, repoID)
 		edgesCreated, err := calc.CalculateCoChanges(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, 0, edgesCreated, "should create 0 edges when no blocks exist")
@@ -318,7 +318,7 @@ func TestCouplingCalculator_EdgeCases(t *testing.T) {
 		blockA := createTestCodeBlock(t, db, repoID, "src/single.go", "funcSingle", "function")
 		createTestModification(t, db, repoID, blockA, "commit1", "alice@example.com", time.Now())
 
-		calc := NewCouplingCalculator(db, nil, repoID)
+		calc := NewCouplingCalculator(db, nil, nil, "", nil, repoID)
 		edgesCreated, err := calc.CalculateCoChanges(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, 0, edgesCreated, "should create 0 edges with only 1 block")
@@ -333,7 +333,7 @@ func TestCouplingCalculator_EdgeCases(t *testing.T) {
 		createTestModification(t, db, repoID, blockA, "commit-same-1", "alice@example.com", baseTime)
 		createTestModification(t, db, repoID, blockB, "commit-same-1", "alice@example.com", baseTime)
 
-		calc := NewCouplingCalculator(db, nil, repoID)
+		calc := NewCouplingCalculator(db, nil, nil, "", nil, repoID)
 		edgesCreated, err := calc.CalculateCoChanges(ctx)
 		require.NoError(t, err)
 		assert.Equal(t, 1, edgesCreated, "should create edge even for blocks in same file")
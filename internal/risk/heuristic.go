@@ -4,6 +4,8 @@ import (
 	"context"
 	"strings"
 	"time"
+
+	"github.com/rohankatakam/coderisk/internal/changetype"
 )
 
 // HeuristicFilter implements Tier 0 filtering for trivial changes
@@ -68,7 +70,21 @@ func (h *HeuristicFilter) Filter(ctx context.Context, req *AnalysisRequest) (*He
 		return result, nil
 	}
 
-	// Rule 4: Very small changes (< 10 lines, < 3 files)
+	// Rule 4: Every file classified as needing no assessment (pure moves, formatting,
+	// generated code; see internal/changetype). Only the diff is available here, so files
+	// are not read for generated-code headers.
+	classes := changetype.ClassifyDiff(req.GitDiff, changetype.Source{})
+	if skippable(classes) {
+		result.IsTrivial = true
+		result.Reason = "Change contains only moves, formatting or generated code"
+		result.ChangeType = string(changetype.Dominant(classes))
+		result.Confidence = minConfidence(classes)
+		result.MatchedRules = append(result.MatchedRules, "change_type_skip")
+		result.DurationMS = time.Since(startTime).Milliseconds()
+		return result, nil
+	}
+
+	// Rule 5: Very small changes (< 10 lines, < 3 files)
 	if linesChanged < h.maxLinesForTrivial && len(req.FilePaths) <= h.maxFilesForTrivial {
 		result.IsTrivial = true
 		result.Reason = "Very small change (low line/file count)"
@@ -83,6 +99,9 @@ func (h *HeuristicFilter) Filter(ctx context.Context, req *AnalysisRequest) (*He
 	result.IsTrivial = false
 	result.Reason = "Change requires full analysis"
 	result.ChangeType = "complex"
+	if kind := changetype.Dominant(classes); kind != "" {
+		result.ChangeType = string(kind)
+	}
 	result.Confidence = 0.80
 	result.DurationMS = time.Since(startTime).Milliseconds()
 
@@ -91,6 +110,28 @@ func (h *HeuristicFilter) Filter(ctx context.Context, req *AnalysisRequest) (*He
 
 // Helper functions
 
+func skippable(classes []changetype.Classification) bool {
+	if len(classes) == 0 {
+		return false
+	}
+	for _, c := range classes {
+		if c.Action != changetype.ActionSkip {
+			return false
+		}
+	}
+	return true
+}
+
+func minConfidence(classes []changetype.Classification) float64 {
+	lowest := 1.0
+	for _, c := range classes {
+		if c.Confidence < lowest {
+			lowest = c.Confidence
+		}
+	}
+	return lowest
+}
+
 func countLinesInDiff(diff string) int {
 	lines := strings.Split(diff, "\n")
 	count := 0
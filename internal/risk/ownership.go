@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"github.com/rohankatakam/coderisk/internal/graph"
 	"github.com/rohankatakam/coderisk/internal/llm"
@@ -661,3 +662,128 @@ func (o *OwnershipCalculator) MarkOwnershipIndexed(ctx context.Context, repoID i
 	log.Printf("    → Marked %d blocks as ownership-indexed", rows)
 	return nil
 }
+
+// RecalculateBlocks recomputes original author, last modifier and familiarity map for
+// specific blocks, e.g. after developer aliases were merged into one identity
+// Same rules as the repo-wide calculations, including the first_seen_sha fallbacks.
+func (o *OwnershipCalculator) RecalculateBlocks(ctx context.Context, repoID int64, blockIDs []int64) (*OwnershipResult, error) {
+	start := time.Now()
+	if len(blockIDs) == 0 {
+		return &OwnershipResult{Duration: time.Since(start), Phase: "recalculate_blocks"}, nil
+	}
+
+	queries := []struct {
+		name  string
+		query string
+	}{
+		{"original authors", `
+			UPDATE code_blocks cb
+			SET original_author_email = gc.author_email
+			FROM github_commits gc
+			WHERE cb.repo_id = $1
+			  AND cb.id = ANY($2::bigint[])
+			  AND gc.repo_id = cb.repo_id
+			  AND gc.sha = cb.first_seen_sha
+			  AND gc.author_email IS NOT NULL
+		`},
+		{"last modifiers", `
+			WITH last_changes AS (
+				SELECT DISTINCT ON (cbc.block_id)
+					cbc.block_id,
+					c.author_email AS last_modifier,
+					c.author_date AS last_modified_date
+				FROM code_block_changes cbc
+				JOIN github_commits c ON c.sha = cbc.commit_sha AND c.repo_id = cbc.repo_id
+				WHERE cbc.repo_id = $1
+				  AND cbc.block_id = ANY($2::bigint[])
+				ORDER BY cbc.block_id, c.author_date DESC
+			)
+			UPDATE code_blocks cb
+			SET
+				last_modifier_email = lc.last_modifier,
+				last_modified_date = lc.last_modified_date
+			FROM last_changes lc
+			WHERE cb.id = lc.block_id
+			  AND cb.repo_id = $1
+		`},
+		{"unmodified last modifiers", `
+			UPDATE code_blocks cb
+			SET
+				last_modifier_email = gc.author_email,
+				last_modified_date = gc.author_date
+			FROM github_commits gc
+			WHERE cb.repo_id = $1
+			  AND cb.id = ANY($2::bigint[])
+			  AND gc.repo_id = cb.repo_id
+			  AND gc.sha = cb.first_seen_sha
+			  AND gc.author_email IS NOT NULL
+			  AND NOT EXISTS (SELECT 1 FROM code_block_changes cbc WHERE cbc.block_id = cb.id)
+		`},
+		{"familiarity maps", `
+			WITH developer_edits AS (
+				SELECT
+					cbc.block_id AS block_id,
+					c.author_email AS email,
+					COUNT(DISTINCT cbc.commit_sha) AS edit_count
+				FROM code_block_changes cbc
+				JOIN github_commits c ON c.sha = cbc.commit_sha AND c.repo_id = cbc.repo_id
+				WHERE cbc.repo_id = $1
+				  AND cbc.block_id = ANY($2::bigint[])
+				  AND c.author_email IS NOT NULL
+				GROUP BY cbc.block_id, c.author_email
+			),
+			ranked_developers AS (
+				SELECT
+					block_id,
+					email,
+					edit_count,
+					ROW_NUMBER() OVER (PARTITION BY block_id ORDER BY edit_count DESC) AS rank
+				FROM developer_edits
+			),
+			familiarity_json AS (
+				SELECT
+					block_id,
+					jsonb_agg(
+						jsonb_build_object('dev', email, 'edits', edit_count)
+						ORDER BY edit_count DESC
+					) AS familiarity_map
+				FROM ranked_developers
+				WHERE rank <= 10
+				GROUP BY block_id
+			)
+			UPDATE code_blocks cb
+			SET familiarity_map = fj.familiarity_map
+			FROM familiarity_json fj
+			WHERE cb.id = fj.block_id
+		`},
+		{"fallback familiarity maps", `
+			UPDATE code_blocks cb
+			SET familiarity_map = jsonb_build_array(jsonb_build_object('dev', gc.author_email, 'edits', 1))
+			FROM github_commits gc
+			WHERE cb.repo_id = $1
+			  AND cb.id = ANY($2::bigint[])
+			  AND gc.repo_id = cb.repo_id
+			  AND gc.sha = cb.first_seen_sha
+			  AND gc.author_email IS NOT NULL
+			  AND NOT EXISTS (SELECT 1 FROM code_block_changes cbc WHERE cbc.block_id = cb.id)
+		`},
+	}
+
+	for _, q := range queries {
+		if _, err := o.db.ExecContext(ctx, q.query, repoID, pq.Array(blockIDs)); err != nil {
+			err = fmt.Errorf("failed to recalculate %s: %w", q.name, err)
+			return &OwnershipResult{Error: err, Duration: time.Since(start), Phase: "recalculate_blocks"}, err
+		}
+	}
+
+	o.logger.Info("recalculated block ownership",
+		"repo_id", repoID,
+		"blocks", len(blockIDs),
+		"duration_ms", time.Since(start).Milliseconds())
+
+	return &OwnershipResult{
+		BlocksUpdated: len(blockIDs),
+		Duration:      time.Since(start),
+		Phase:         "recalculate_blocks",
+	}, nil
+}
@@ -26,7 +26,7 @@ func TestOwnershipCalculator_Integration(t *testing.T) {
 	}
 	defer driver.Close(ctx)
 
-	calculator := NewOwnershipCalculator(driver, "neo4j", nil)
+	calculator := NewOwnershipCalculator(nil, nil, driver, "neo4j", nil)
 
 	// Test 1: Original Author calculation
 	t.Run("CalculateOriginalAuthor", func(t *testing.T) {
@@ -41,7 +41,7 @@ func TestOwnershipCalculator_Integration(t *testing.T) {
 		assert.Equal(t, "original_author", result.Phase)
 
 		// Cleanup
-		cleanupTestData(t, driver, repoID)
+		cleanupTestGraph(t, driver, repoID)
 	})
 
 	// Test 2: Last Modifier and Staleness calculation
@@ -55,7 +55,7 @@ func TestOwnershipCalculator_Integration(t *testing.T) {
 		assert.True(t, result.BlocksUpdated >= 0)
 		assert.Equal(t, "last_modifier_staleness", result.Phase)
 
-		cleanupTestData(t, driver, repoID)
+		cleanupTestGraph(t, driver, repoID)
 	})
 
 	// Test 3: Familiarity Map calculation
@@ -68,7 +68,7 @@ func TestOwnershipCalculator_Integration(t *testing.T) {
 		assert.NotNil(t, result)
 		assert.True(t, result.BlocksUpdated >= 0)
 
-		cleanupTestData(t, driver, repoID)
+		cleanupTestGraph(t, driver, repoID)
 	})
 
 	// Test 4: Verification
@@ -89,7 +89,7 @@ func TestOwnershipCalculator_Integration(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 0, incompleteBlocks, "All blocks should have ownership properties")
 
-		cleanupTestData(t, driver, repoID)
+		cleanupTestGraph(t, driver, repoID)
 	})
 }
 
@@ -107,7 +107,7 @@ func TestBlockIndexer_Integration(t *testing.T) {
 	}
 	defer driver.Close(ctx)
 
-	indexer := NewBlockIndexer(driver, "neo4j", nil)
+	indexer := NewBlockIndexer(nil, nil, driver, "neo4j", nil)
 
 	t.Run("IndexOwnership", func(t *testing.T) {
 		repoID := int64(999)
@@ -121,7 +121,7 @@ func TestBlockIndexer_Integration(t *testing.T) {
 		assert.Equal(t, 3, len(result.PhaseResults), "Should have 3 phases")
 		assert.True(t, result.TotalDuration > 0)
 
-		cleanupTestData(t, driver, repoID)
+		cleanupTestGraph(t, driver, repoID)
 	})
 
 	t.Run("GetIndexingStats", func(t *testing.T) {
@@ -141,14 +141,14 @@ func TestBlockIndexer_Integration(t *testing.T) {
 		assert.Contains(t, stats, "with_last_modifier")
 		assert.Contains(t, stats, "with_familiarity_map")
 
-		cleanupTestData(t, driver, repoID)
+		cleanupTestGraph(t, driver, repoID)
 	})
 }
 
 // TestSemanticImportance tests the LLM-based importance classification
 func TestSemanticImportance(t *testing.T) {
 	// This is a unit test - doesn't require LLM to be configured
-	calculator := NewOwnershipCalculator(nil, "", nil)
+	calculator := NewOwnershipCalculator(nil, nil, nil, "", nil)
 
 	t.Run("NoLLM_ReturnsDefault", func(t *testing.T) {
 		block := CodeBlock{
@@ -177,7 +177,7 @@ func TestEdgeCases(t *testing.T) {
 	}
 	defer driver.Close(ctx)
 
-	calculator := NewOwnershipCalculator(driver, "neo4j", nil)
+	calculator := NewOwnershipCalculator(nil, nil, driver, "neo4j", nil)
 
 	t.Run("BlockWithNoModifications", func(t *testing.T) {
 		repoID := int64(999)
@@ -247,7 +247,7 @@ func TestEdgeCases(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, result.BlocksUpdated >= 1, "Should handle block with no modifications")
 
-		cleanupTestData(t, driver, repoID)
+		cleanupTestGraph(t, driver, repoID)
 	})
 
 	t.Run("EmptyRepository", func(t *testing.T) {
@@ -378,7 +378,7 @@ func setupTestData(t *testing.T, driver neo4j.DriverWithContext, repoID int64) {
 }
 
 // cleanupTestData removes test data from Neo4j
-func cleanupTestData(t *testing.T, driver neo4j.DriverWithContext, repoID int64) {
+func cleanupTestGraph(t *testing.T, driver neo4j.DriverWithContext, repoID int64) {
 	ctx := context.Background()
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
@@ -0,0 +1,316 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/git"
+	"github.com/rohankatakam/coderisk/internal/graph"
+)
+
+// SZZ tuning
+const (
+	// szzMaxFilesPerFix skips sweeping fixes (mass renames, reformatting) that blame everything
+	szzMaxFilesPerFix = 50
+
+	// szzMinConfidence drops candidates that own only a sliver of the fix
+	szzMinConfidence = 0.1
+)
+
+// SZZAnalyzer finds bug-introducing commits for issue-fixing commits (SZZ algorithm)
+// For each fix commit, the lines it deleted or modified are blamed in the parent revision;
+// the commits that last touched those lines are the bug-introducing candidates.
+type SZZAnalyzer struct {
+	db     *sql.DB
+	neo4j  *graph.Neo4jBackend
+	blamer *git.Blamer
+	repoID int64
+}
+
+// NewSZZAnalyzer creates a new SZZ analyzer for a local clone at repoPath
+// neo4j may be nil, in which case results are stored in PostgreSQL only
+func NewSZZAnalyzer(db *sql.DB, neo4j *graph.Neo4jBackend, repoPath string, repoID int64) *SZZAnalyzer {
+	return &SZZAnalyzer{
+		db:     db,
+		neo4j:  neo4j,
+		blamer: git.NewBlamer(repoPath),
+		repoID: repoID,
+	}
+}
+
+// FixCommit is a commit that closed an issue; a commit closing several issues is one
+// FixCommit per issue
+type FixCommit struct {
+	SHA            string
+	IssueID        int64
+	IssueNumber    int
+	IssueCreatedAt time.Time
+}
+
+// BugIntroduction is a commit blamed for lines a fix commit had to change
+type BugIntroduction struct {
+	FixCommitSHA         string
+	IntroducingCommitSHA string
+	IssueID              int64
+	IssueNumber          int
+	Files                map[string]int // file path -> blamed lines
+	BlamedLines          int
+	Confidence           float64
+	CommittedAt          time.Time
+}
+
+// DetectBugIntroducingCommits runs SZZ over every issue-linked fix commit
+// Returns the number of (fix, introducing) pairs recorded
+func (s *SZZAnalyzer) DetectBugIntroducingCommits(ctx context.Context) (int, error) {
+	log.Printf("  🔍 Querying issue-fixing commits...")
+
+	fixes, err := s.getFixCommits(ctx)
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("    → Found %d fix commits", len(fixes))
+
+	recorded := 0
+	skipped := 0
+
+	var blamed []blamedFileLine
+	var blameErr error
+	for i, fix := range fixes {
+		// Fixes are ordered by SHA: a commit closing several issues is blamed once and
+		// scored against each issue's report date
+		if i == 0 || fix.SHA != fixes[i-1].SHA {
+			blamed, blameErr = s.blameFix(ctx, fix.SHA)
+		}
+		if blameErr != nil {
+			// Commits missing from the local clone (force-pushed, shallow) are expected
+			log.Printf("    ⚠️  Skipping fix %s (issue #%d): %v", git.ShortSHA(fix.SHA), fix.IssueNumber, blameErr)
+			skipped++
+			continue
+		}
+
+		for _, intro := range scoreIntroductions(fix, blamed) {
+			if err := s.storeIntroduction(ctx, intro); err != nil {
+				return recorded, err
+			}
+			recorded++
+		}
+
+		if (i+1)%25 == 0 {
+			log.Printf("    → Analyzed %d/%d fix commits (%d introductions)", i+1, len(fixes), recorded)
+		}
+	}
+
+	log.Printf("  ✓ Recorded %d bug-introducing commits (%d fixes skipped)", recorded, skipped)
+	return recorded, nil
+}
+
+// getFixCommits returns the commits that closed an issue, one per (commit, issue)
+// Only closed events count: a commit merely referencing an issue ("see #42", "part of
+// #42") need not fix anything, and blaming its lines would accuse unrelated commits.
+func (s *SZZAnalyzer) getFixCommits(ctx context.Context) ([]FixCommit, error) {
+	query := `
+		SELECT DISTINCT
+			git.source_sha,
+			gi.id,
+			gi.number,
+			gi.created_at
+		FROM github_issue_timeline git
+		JOIN github_issues gi ON gi.id = git.issue_id
+		WHERE git.event_type = 'closed'
+		  AND git.source_sha IS NOT NULL
+		  AND gi.repo_id = $1
+		ORDER BY git.source_sha, gi.id
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, s.repoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fix commits: %w", err)
+	}
+	defer rows.Close()
+
+	var fixes []FixCommit
+	for rows.Next() {
+		var fix FixCommit
+		if err := rows.Scan(&fix.SHA, &fix.IssueID, &fix.IssueNumber, &fix.IssueCreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan fix commit: %w", err)
+		}
+		fixes = append(fixes, fix)
+	}
+
+	return fixes, rows.Err()
+}
+
+// blameFix blames the lines removed by a fix commit in its parent revision
+func (s *SZZAnalyzer) blameFix(ctx context.Context, sha string) ([]blamedFileLine, error) {
+	removed, err := s.blamer.RemovedLines(ctx, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(removed) > szzMaxFilesPerFix {
+		return nil, fmt.Errorf("touches %d files (limit %d)", len(removed), szzMaxFilesPerFix)
+	}
+
+	var blamed []blamedFileLine
+	for filePath, lines := range removed {
+		var lineNumbers []int
+		for _, l := range lines {
+			if isTrivialLine(l.Content) {
+				continue
+			}
+			lineNumbers = append(lineNumbers, l.Line)
+		}
+		if len(lineNumbers) == 0 {
+			continue
+		}
+
+		result, err := s.blamer.BlameLines(ctx, sha+"^", filePath, lineNumbers)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range result {
+			blamed = append(blamed, blamedFileLine{FilePath: filePath, BlameLine: b})
+		}
+	}
+
+	return blamed, nil
+}
+
+// blamedFileLine is a blame result tagged with the file it came from
+type blamedFileLine struct {
+	FilePath string
+	git.BlameLine
+}
+
+// scoreIntroductions groups blamed lines by commit and assigns confidence
+// Lines last changed after the issue was reported are dropped, as in standard SZZ: the
+// reported bug already existed without them. Confidence is the share of the remaining
+// blamed lines owned by the candidate.
+func scoreIntroductions(fix FixCommit, blamed []blamedFileLine) []BugIntroduction {
+	byCommit := make(map[string]*BugIntroduction)
+	candidateLines := 0
+	for _, b := range blamed {
+		if !fix.IssueCreatedAt.IsZero() && b.CommitTime.After(fix.IssueCreatedAt) {
+			continue
+		}
+		candidateLines++
+		intro, ok := byCommit[b.CommitSHA]
+		if !ok {
+			intro = &BugIntroduction{
+				FixCommitSHA:         fix.SHA,
+				IntroducingCommitSHA: b.CommitSHA,
+				IssueID:              fix.IssueID,
+				IssueNumber:          fix.IssueNumber,
+				Files:                make(map[string]int),
+				CommittedAt:          b.CommitTime,
+			}
+			byCommit[b.CommitSHA] = intro
+		}
+		intro.Files[b.FilePath]++
+		intro.BlamedLines++
+	}
+
+	var result []BugIntroduction
+	for _, intro := range byCommit {
+		confidence := float64(intro.BlamedLines) / float64(candidateLines)
+		if confidence < szzMinConfidence {
+			continue
+		}
+		intro.Confidence = confidence
+		result = append(result, *intro)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Confidence != result[j].Confidence {
+			return result[i].Confidence > result[j].Confidence
+		}
+		return result[i].IntroducingCommitSHA < result[j].IntroducingCommitSHA
+	})
+
+	return result
+}
+
+// isTrivialLine reports lines SZZ should not blame (blank lines, comments, lone braces)
+func isTrivialLine(content string) bool {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" || trimmed == "{" || trimmed == "}" || trimmed == ")" || trimmed == "*" {
+		return true
+	}
+	// "* " rather than "*" so pointer dereferences are still blamed
+	for _, prefix := range []string{"//", "#", "/*", "* ", "*/", "-- "} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// storeIntroduction persists an introduction to PostgreSQL and mirrors it to Neo4j
+func (s *SZZAnalyzer) storeIntroduction(ctx context.Context, intro BugIntroduction) error {
+	insertQuery := `
+		INSERT INTO bug_introducing_commits (
+			repo_id, fix_commit_sha, introducing_commit_sha, issue_id,
+			file_path, blamed_lines, confidence, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (repo_id, fix_commit_sha, introducing_commit_sha, issue_id, file_path)
+		DO UPDATE SET
+			blamed_lines = EXCLUDED.blamed_lines,
+			confidence = EXCLUDED.confidence
+	`
+
+	for filePath, lines := range intro.Files {
+		_, err := s.db.ExecContext(ctx, insertQuery,
+			s.repoID,
+			intro.FixCommitSHA,
+			intro.IntroducingCommitSHA,
+			intro.IssueID,
+			filePath,
+			lines,
+			intro.Confidence,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert bug introduction %s -> %s: %w",
+				git.ShortSHA(intro.FixCommitSHA), git.ShortSHA(intro.IntroducingCommitSHA), err)
+		}
+	}
+
+	if s.neo4j == nil {
+		return nil
+	}
+
+	// (fix:Commit)-[:INTRODUCED_BY]->(bug:Commit), keeping the highest confidence across the
+	// issues the fix closed
+	// Block-level attribution is derived at query time: a block's defect is attributed to
+	// the introducing commit when both commits modified that block
+	neo4jQuery := `
+		MATCH (fix:Commit {sha: $fixSHA, repo_id: $repoID})
+		MATCH (bug:Commit {sha: $introducingSHA, repo_id: $repoID})
+		MERGE (fix)-[r:INTRODUCED_BY]->(bug)
+		SET r.confidence = CASE WHEN coalesce(r.confidence, 0) > $confidence THEN r.confidence ELSE $confidence END,
+		    r.blamed_lines = $blamedLines,
+		    r.issue_number = $issueNumber,
+		    r.detected_by = 'szz',
+		    r.created_at = datetime()
+	`
+	params := map[string]interface{}{
+		"fixSHA":         intro.FixCommitSHA,
+		"introducingSHA": intro.IntroducingCommitSHA,
+		"repoID":         s.repoID,
+		"confidence":     intro.Confidence,
+		"blamedLines":    intro.BlamedLines,
+		"issueNumber":    intro.IssueNumber,
+	}
+	queries := []graph.QueryWithParams{{Query: neo4jQuery, Params: params}}
+	if err := s.neo4j.ExecuteBatchWithParams(ctx, queries); err != nil {
+		log.Printf("    ⚠️  Warning: Failed to create INTRODUCED_BY edge %s -> %s: %v",
+			git.ShortSHA(intro.FixCommitSHA), git.ShortSHA(intro.IntroducingCommitSHA), err)
+		// Continue processing - Neo4j failure shouldn't block PostgreSQL updates
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package risk
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/git"
+)
+
+func TestScoreIntroductions(t *testing.T) {
+	reported := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := reported.Add(-30 * 24 * time.Hour)
+	after := reported.Add(24 * time.Hour)
+
+	fix := FixCommit{SHA: "fix", IssueID: 7, IssueNumber: 42, IssueCreatedAt: reported}
+	line := func(file, sha string, at time.Time) blamedFileLine {
+		return blamedFileLine{FilePath: file, BlameLine: git.BlameLine{CommitSHA: sha, CommitTime: at}}
+	}
+
+	blamed := []blamedFileLine{
+		line("pay/charge.go", "aaa", before),
+		line("pay/charge.go", "aaa", before),
+		line("pay/refund.go", "aaa", before),
+		line("pay/charge.go", "ccc", before),
+	}
+	for i := 0; i < 10; i++ {
+		blamed = append(blamed, line("pay/charge.go", "aaa", before))
+	}
+	for i := 0; i < 6; i++ {
+		blamed = append(blamed, line("pay/charge.go", "bbb", after))
+	}
+
+	intros := scoreIntroductions(fix, blamed)
+	if len(intros) != 1 {
+		t.Fatalf("expected 1 introduction, got %d: %+v", len(intros), intros)
+	}
+
+	// bbb landed after the issue was reported and is not a candidate; aaa owns 13 of the
+	// 14 remaining lines, and ccc's 1/14 share is below the minimum confidence
+	top := intros[0]
+	if top.IntroducingCommitSHA != "aaa" {
+		t.Fatalf("expected aaa, got %s", top.IntroducingCommitSHA)
+	}
+	if top.BlamedLines != 13 || top.Files["pay/refund.go"] != 1 {
+		t.Errorf("unexpected attribution: %+v", top)
+	}
+	if math.Abs(top.Confidence-13.0/14) > 1e-9 {
+		t.Errorf("expected confidence %.3f, got %.3f", 13.0/14, top.Confidence)
+	}
+
+	// Only late lines: nothing to blame
+	if late := scoreIntroductions(fix, []blamedFileLine{line("pay/charge.go", "bbb", after)}); len(late) != 0 {
+		t.Errorf("expected no introductions from late lines, got %+v", late)
+	}
+}
+
+func TestIsTrivialLine(t *testing.T) {
+	trivial := []string{"", "   ", "\t}", "// comment", "# python comment", " * doc", "-- sql"}
+	for _, l := range trivial {
+		if !isTrivialLine(l) {
+			t.Errorf("expected %q to be trivial", l)
+		}
+	}
+
+	for _, l := range []string{"\treturn a - b", "*p = value"} {
+		if isTrivialLine(l) {
+			t.Errorf("expected %q to be non-trivial", l)
+		}
+	}
+}
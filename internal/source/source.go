@@ -0,0 +1,132 @@
+// Package source defines the hosting-provider abstraction for the staging fetchers.
+// Every provider (GitHub, GitLab) writes into the same github_* staging tables with
+// GitHub-shaped raw_data, so linking, graph construction and CLQS stay provider-agnostic.
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+)
+
+// Provider identifies a code hosting platform
+type Provider string
+
+const (
+	ProviderGitHub Provider = "github"
+	ProviderGitLab Provider = "gitlab"
+	ProviderGit    Provider = "git" // Local history only, no hosting API
+)
+
+// SyntheticID derives a stable ID for entities without a GitHub ID (git-only
+// repositories, GitLab projects and contributors). IDs are negative so they never
+// collide with GitHub's positive IDs in the github_id columns.
+func SyntheticID(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return -int64(h.Sum64()>>1) - 1
+}
+
+// Stats tracks fetching statistics
+type Stats struct {
+	Commits      int
+	Issues       int
+	PRs          int
+	Branches     int
+	Contributors int
+}
+
+// HasData returns true if any data exists
+func (s *Stats) HasData() bool {
+	return s.Commits > 0 || s.Issues > 0 || s.PRs > 0 || s.Branches > 0
+}
+
+// Fetcher downloads repository data from a hosting provider into the staging tables
+// owner is the namespace (GitHub owner or GitLab group path, which may be nested)
+// days: number of days to fetch (0 = all history)
+type Fetcher interface {
+	FetchAll(ctx context.Context, owner, repo, repoPath string, days int) (int64, *Stats, error)
+}
+
+// Store is the subset of database.StagingClient used by provider fetchers
+type Store interface {
+	GetDataCounts(ctx context.Context, repoID int64) (*database.DataCounts, error)
+	StoreRepository(ctx context.Context, githubID int64, owner, name, fullName, absolutePath string, rawData json.RawMessage) (int64, error)
+	StoreCommit(ctx context.Context, repoID int64, sha string, authorName, authorEmail string, authorDate time.Time, message string, additions, deletions, totalChanges, filesChanged int, rawData json.RawMessage) error
+	StoreIssue(ctx context.Context, repoID int64, githubID int64, number int, title, body, state, userLogin string, userID int64, labels json.RawMessage, createdAt time.Time, closedAt *time.Time, rawData json.RawMessage) error
+	StorePullRequest(ctx context.Context, repoID int64, githubID int64, number int, title, body, state, userLogin string, userID int64, headRef, headSHA, baseRef, baseSHA string, merged bool, mergedAt *time.Time, mergeCommitSHA *string, labels json.RawMessage, createdAt time.Time, closedAt *time.Time, rawData json.RawMessage) error
+	StoreBranch(ctx context.Context, repoID int64, name, commitSHA string, protected bool, rawData json.RawMessage) error
+	StoreLanguages(ctx context.Context, repoID int64, languages json.RawMessage) error
+	StoreContributor(ctx context.Context, repoID int64, githubID int64, login string, contributions int, rawData json.RawMessage) error
+	StoreTimelineEvent(ctx context.Context, event database.TimelineEventData) error
+	StorePRFile(ctx context.Context, repoID, prID int64, filename, status string, additions, deletions, changes int, previousFilename, patch *string, rawData json.RawMessage) error
+	StoreIssueComment(ctx context.Context, repoID, issueID, githubID int64, body, userLogin string, userID int64, authorAssociation string, createdAt time.Time, updatedAt *time.Time, rawData json.RawMessage) error
+	GetIssueIDsByNumber(ctx context.Context, repoID int64) (map[int]int64, error)
+	GetPRsWithoutFiles(ctx context.Context, repoID int64, days int) ([]database.PRData, error)
+}
+
+var _ Store = (*database.StagingClient)(nil)
+
+// ParseProvider validates a provider name; "" and "auto" mean detect from the remote
+func ParseProvider(name string) (Provider, error) {
+	switch strings.ToLower(name) {
+	case "", "auto":
+		return "", nil
+	case string(ProviderGitHub):
+		return ProviderGitHub, nil
+	case string(ProviderGitLab):
+		return ProviderGitLab, nil
+	default:
+		return "", fmt.Errorf("unknown provider %q (expected auto, github or gitlab)", name)
+	}
+}
+
+// Remote is a parsed git remote URL
+type Remote struct {
+	Provider Provider
+	Host     string
+	Owner    string // Namespace; GitLab subgroups are kept as "group/subgroup"
+	Repo     string
+}
+
+// remotePattern matches https://host/path.git and git@host:path.git remotes
+var remotePattern = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?([^/:]+)(?::\d+)?[:/](.+?)(?:\.git)?/?$`)
+
+// ParseRemote parses a git remote URL and infers the hosting provider from the host
+// Self-hosted GitLab instances are recognised when the host contains "gitlab";
+// pass a non-empty gitlabHost to match a custom domain.
+// Provider is left empty for unrecognised hosts so callers can apply an explicit override.
+func ParseRemote(remoteURL, gitlabHost string) (*Remote, error) {
+	m := remotePattern.FindStringSubmatch(strings.TrimSpace(remoteURL))
+	if m == nil {
+		return nil, fmt.Errorf("unrecognised remote URL: %s", remoteURL)
+	}
+
+	host := strings.ToLower(m[1])
+	path := strings.Trim(m[2], "/")
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 {
+		return nil, fmt.Errorf("remote URL has no owner/repo path: %s", remoteURL)
+	}
+
+	remote := &Remote{
+		Host:  host,
+		Owner: path[:idx],
+		Repo:  path[idx+1:],
+	}
+
+	switch {
+	case host == "github.com":
+		remote.Provider = ProviderGitHub
+	case strings.Contains(host, "gitlab") || (gitlabHost != "" && host == strings.ToLower(gitlabHost)):
+		remote.Provider = ProviderGitLab
+	}
+
+	return remote, nil
+}
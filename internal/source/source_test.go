@@ -0,0 +1,40 @@
+package source
+
+import "testing"
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		url      string
+		provider Provider
+		host     string
+		owner    string
+		repo     string
+	}{
+		{"https://github.com/rohankatakam/coderisk.git", ProviderGitHub, "github.com", "rohankatakam", "coderisk"},
+		{"git@github.com:rohankatakam/coderisk.git", ProviderGitHub, "github.com", "rohankatakam", "coderisk"},
+		{"https://gitlab.com/acme/platform/api.git", ProviderGitLab, "gitlab.com", "acme/platform", "api"},
+		{"git@gitlab.example.com:acme/api.git", ProviderGitLab, "gitlab.example.com", "acme", "api"},
+		{"ssh://git@gitlab.com:2222/acme/api", ProviderGitLab, "gitlab.com", "acme", "api"},
+		{"https://code.internal/acme/api.git", "", "code.internal", "acme", "api"},
+	}
+
+	for _, tt := range tests {
+		remote, err := ParseRemote(tt.url, "")
+		if err != nil {
+			t.Errorf("ParseRemote(%q): %v", tt.url, err)
+			continue
+		}
+		if remote.Provider != tt.provider || remote.Host != tt.host || remote.Owner != tt.owner || remote.Repo != tt.repo {
+			t.Errorf("ParseRemote(%q) = %+v", tt.url, remote)
+		}
+	}
+
+	remote, err := ParseRemote("https://code.internal/acme/api.git", "code.internal")
+	if err != nil || remote.Provider != ProviderGitLab {
+		t.Errorf("expected custom GitLab host to be recognised, got %+v, %v", remote, err)
+	}
+
+	if _, err := ParseRemote("not a remote", ""); err == nil {
+		t.Error("expected error for invalid remote")
+	}
+}
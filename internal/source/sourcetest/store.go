@@ -0,0 +1,117 @@
+// Package sourcetest provides an in-memory source.Store for provider fetcher tests.
+package sourcetest
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rohankatakam/coderisk/internal/database"
+	"github.com/rohankatakam/coderisk/internal/source"
+)
+
+// MemoryStore is an in-memory source.Store that records what a fetcher staged
+// Issue IDs are 1000 + number and PR IDs mirror numbers, so rows stored by ID can be
+// traced back to the issue or PR they belong to.
+type MemoryStore struct {
+	Repo      json.RawMessage
+	GitHubIDs []int64 // Repository and contributor github_id values
+	Commits   map[string]json.RawMessage
+	Issues    map[int]string // number -> state
+	PRs       map[int]json.RawMessage
+	PRMerged  map[int]bool
+	PRFiles   map[int][]string // PR ID -> "filename:status"
+	Branches  []string
+	Languages json.RawMessage
+	Logins    []string
+	Timeline  []database.TimelineEventData
+	Comments  []string
+}
+
+var _ source.Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		Commits:  make(map[string]json.RawMessage),
+		Issues:   make(map[int]string),
+		PRs:      make(map[int]json.RawMessage),
+		PRMerged: make(map[int]bool),
+		PRFiles:  make(map[int][]string),
+	}
+}
+
+func (m *MemoryStore) GetDataCounts(ctx context.Context, repoID int64) (*database.DataCounts, error) {
+	return &database.DataCounts{Commits: len(m.Commits), Issues: len(m.Issues), PRs: len(m.PRs), Branches: len(m.Branches)}, nil
+}
+
+func (m *MemoryStore) StoreRepository(ctx context.Context, githubID int64, owner, name, fullName, absolutePath string, rawData json.RawMessage) (int64, error) {
+	m.Repo = rawData
+	m.GitHubIDs = append(m.GitHubIDs, githubID)
+	return 1, nil
+}
+
+func (m *MemoryStore) StoreCommit(ctx context.Context, repoID int64, sha string, authorName, authorEmail string, authorDate time.Time, message string, additions, deletions, totalChanges, filesChanged int, rawData json.RawMessage) error {
+	m.Commits[sha] = rawData
+	return nil
+}
+
+func (m *MemoryStore) StoreIssue(ctx context.Context, repoID int64, githubID int64, number int, title, body, state, userLogin string, userID int64, labels json.RawMessage, createdAt time.Time, closedAt *time.Time, rawData json.RawMessage) error {
+	m.Issues[number] = state
+	return nil
+}
+
+func (m *MemoryStore) StorePullRequest(ctx context.Context, repoID int64, githubID int64, number int, title, body, state, userLogin string, userID int64, headRef, headSHA, baseRef, baseSHA string, merged bool, mergedAt *time.Time, mergeCommitSHA *string, labels json.RawMessage, createdAt time.Time, closedAt *time.Time, rawData json.RawMessage) error {
+	m.PRs[number] = rawData
+	m.PRMerged[number] = merged
+	return nil
+}
+
+func (m *MemoryStore) StoreBranch(ctx context.Context, repoID int64, name, commitSHA string, protected bool, rawData json.RawMessage) error {
+	m.Branches = append(m.Branches, name)
+	return nil
+}
+
+func (m *MemoryStore) StoreLanguages(ctx context.Context, repoID int64, languages json.RawMessage) error {
+	m.Languages = languages
+	return nil
+}
+
+func (m *MemoryStore) StoreContributor(ctx context.Context, repoID int64, githubID int64, login string, contributions int, rawData json.RawMessage) error {
+	m.Logins = append(m.Logins, login)
+	m.GitHubIDs = append(m.GitHubIDs, githubID)
+	return nil
+}
+
+func (m *MemoryStore) StoreTimelineEvent(ctx context.Context, event database.TimelineEventData) error {
+	m.Timeline = append(m.Timeline, event)
+	return nil
+}
+
+func (m *MemoryStore) StorePRFile(ctx context.Context, repoID, prID int64, filename, status string, additions, deletions, changes int, previousFilename, patch *string, rawData json.RawMessage) error {
+	m.PRFiles[int(prID)] = append(m.PRFiles[int(prID)], filename+":"+status)
+	return nil
+}
+
+func (m *MemoryStore) StoreIssueComment(ctx context.Context, repoID, issueID, githubID int64, body, userLogin string, userID int64, authorAssociation string, createdAt time.Time, updatedAt *time.Time, rawData json.RawMessage) error {
+	m.Comments = append(m.Comments, body)
+	return nil
+}
+
+func (m *MemoryStore) GetIssueIDsByNumber(ctx context.Context, repoID int64) (map[int]int64, error) {
+	ids := make(map[int]int64)
+	for number := range m.Issues {
+		ids[number] = int64(1000 + number)
+	}
+	return ids, nil
+}
+
+func (m *MemoryStore) GetPRsWithoutFiles(ctx context.Context, repoID int64, days int) ([]database.PRData, error) {
+	var prs []database.PRData
+	for number, merged := range m.PRMerged {
+		if merged && len(m.PRFiles[number]) == 0 {
+			prs = append(prs, database.PRData{ID: int64(number), Number: number, Merged: true})
+		}
+	}
+	return prs, nil
+}
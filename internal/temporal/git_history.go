@@ -12,13 +12,18 @@ import (
 )
 
 // ParseGitHistory executes git log and parses output
+// days: number of days to parse (0 = all history)
 func ParseGitHistory(repoPath string, days int) ([]Commit, error) {
 	// Execute git log with numstat to get file changes
-	cmd := exec.Command("git", "log",
-		fmt.Sprintf("--since=%d days ago", days),
+	args := []string{"log"}
+	if days > 0 {
+		args = append(args, fmt.Sprintf("--since=%d days ago", days))
+	}
+	args = append(args,
 		"--numstat",
 		"--pretty=format:%H|%an|%ae|%ad|%s",
 		"--date=iso-strict")
+	cmd := exec.Command("git", args...)
 
 	cmd.Dir = repoPath
 	output, err := cmd.CombinedOutput()
@@ -145,6 +145,7 @@ type RiskResult struct {
 	EndTime      time.Time     `json:"end_time"`
 	Duration     time.Duration `json:"duration"`
 	CacheHit     bool          `json:"cache_hit"`
+	ChangeType   string        `json:"change_type,omitempty"` // Change classification, e.g. "refactor (renamed 1 identifier(s), logic unchanged)"
 
 	// Analysis results
 	Files              []FileRisk         `json:"files"`
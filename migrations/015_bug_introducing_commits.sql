@@ -0,0 +1,34 @@
+-- Migration 015: Bug-introducing commits (SZZ)
+-- Stores the commits blamed for the lines each fixing commit deleted or modified.
+-- One row per (fix commit, introducing commit, file) so reruns are idempotent.
+-- Populated by crisk-index-incident; mirrored to Neo4j as
+-- (fix:Commit)-[:INTRODUCED_BY]->(bug:Commit) edges.
+
+CREATE TABLE IF NOT EXISTS bug_introducing_commits (
+    id BIGSERIAL PRIMARY KEY,
+    repo_id BIGINT NOT NULL REFERENCES github_repositories(id) ON DELETE CASCADE,
+
+    fix_commit_sha VARCHAR(40) NOT NULL,
+    introducing_commit_sha VARCHAR(40) NOT NULL,
+    issue_id BIGINT REFERENCES github_issues(id) ON DELETE SET NULL,
+    file_path TEXT NOT NULL,
+
+    blamed_lines INTEGER NOT NULL DEFAULT 0,      -- Removed lines in file_path attributed to the introducing commit
+    confidence DOUBLE PRECISION NOT NULL,         -- 0.0-1.0 share of the fix's blamed lines from before the issue
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT bug_introducing_commits_unique
+        UNIQUE (repo_id, fix_commit_sha, introducing_commit_sha, file_path)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bug_introducing_commits_introducing
+    ON bug_introducing_commits(repo_id, introducing_commit_sha);
+
+CREATE INDEX IF NOT EXISTS idx_bug_introducing_commits_fix
+    ON bug_introducing_commits(repo_id, fix_commit_sha);
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 015 complete: bug_introducing_commits table created';
+END $$;
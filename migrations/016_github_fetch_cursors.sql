@@ -0,0 +1,24 @@
+-- Migration 016: GitHub fetch cursors
+-- Persists a per-resource high-water mark and ETag so incremental fetches
+-- (crisk-sync --mode incremental) only pull entities created or updated since
+-- the previous run. A 304 Not Modified answer to a conditional request does not
+-- count against the GitHub rate limit.
+
+CREATE TABLE IF NOT EXISTS github_fetch_cursors (
+    id BIGSERIAL PRIMARY KEY,
+    repo_id BIGINT NOT NULL REFERENCES github_repositories(id) ON DELETE CASCADE,
+
+    resource VARCHAR(32) NOT NULL,       -- 'commits', 'issues', 'pulls'
+    etag TEXT,                           -- ETag of the first page from the last request
+    high_water_mark TIMESTAMP,           -- Newest commit date / updated_at staged so far
+
+    last_fetched_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT github_fetch_cursors_unique UNIQUE (repo_id, resource)
+);
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 016 complete: github_fetch_cursors table created';
+END $$;
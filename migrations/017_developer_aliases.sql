@@ -0,0 +1,37 @@
+-- Migration 017: Developer identity unification
+-- Maps the extra emails a developer commits under (personal address, GitHub
+-- noreply, old work address) to one canonical email. Confident matches (mailmap,
+-- alias file, shared GitHub login) are stored as 'accepted'; fuzzy name matches
+-- start 'pending' until reviewed with `crisk identity review`.
+--
+-- Applying an alias rewrites github_commits.author_email to the canonical email
+-- and keeps the original in original_author_email, so it can be audited or undone.
+-- StoreCommit maps new commits through applied aliases at staging time.
+
+CREATE TABLE IF NOT EXISTS developer_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    repo_id BIGINT NOT NULL REFERENCES github_repositories(id) ON DELETE CASCADE,
+
+    alias_email TEXT NOT NULL,           -- Lowercase email being merged away
+    canonical_email TEXT NOT NULL,       -- Lowercase email it merges into
+    source VARCHAR(32) NOT NULL,         -- 'mailmap', 'alias_file', 'github_login', 'noreply', 'fuzzy_name'
+    confidence REAL NOT NULL DEFAULT 1.0,
+    reason TEXT,
+
+    status VARCHAR(16) NOT NULL DEFAULT 'pending', -- 'pending', 'accepted', 'rejected', 'applied'
+    reviewed_at TIMESTAMP,
+    applied_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT developer_aliases_unique UNIQUE (repo_id, alias_email),
+    CONSTRAINT developer_aliases_status_check CHECK (status IN ('pending', 'accepted', 'rejected', 'applied'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_developer_aliases_status ON developer_aliases(repo_id, status);
+
+ALTER TABLE github_commits ADD COLUMN IF NOT EXISTS original_author_email TEXT;
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 017 complete: developer_aliases table created';
+END $$;
@@ -0,0 +1,35 @@
+-- Migration 018: Human labels for issue-PR links
+-- Records accept/reject decisions made with `crisk links review`. Decisions are
+-- written through to github_issue_pr_links so CLQS and the graph see them:
+--   accepted: final_confidence is raised to 1.0 and link_quality set to 'high'
+--   rejected: the link row is deleted, and StoreLinkOutput will not re-create it
+-- The automated confidence and detection method are kept here, so labels can be
+-- exported as backtest ground truth and compared against what the linker found.
+
+CREATE TABLE IF NOT EXISTS github_issue_pr_link_labels (
+    id BIGSERIAL PRIMARY KEY,
+    repo_id BIGINT NOT NULL REFERENCES github_repositories(id) ON DELETE CASCADE,
+
+    issue_number INTEGER NOT NULL,
+    pr_number INTEGER NOT NULL,
+
+    decision VARCHAR(16) NOT NULL,        -- 'accepted', 'rejected'
+    note TEXT,
+    labeler TEXT,
+
+    -- Automated link at the time of labeling
+    original_confidence NUMERIC(4,3),
+    detection_method VARCHAR(50),
+
+    labeled_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT issue_pr_link_labels_unique UNIQUE (repo_id, issue_number, pr_number),
+    CONSTRAINT issue_pr_link_labels_decision_check CHECK (decision IN ('accepted', 'rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_issue_pr_link_labels_repo ON github_issue_pr_link_labels(repo_id, decision);
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 018 complete: github_issue_pr_link_labels table created';
+END $$;
@@ -0,0 +1,52 @@
+-- Migration 019: CI coverage reports
+-- Stores line coverage imported with `crisk coverage import` for a commit, so
+-- `crisk check` can count changed lines that no test executes instead of
+-- estimating coverage from test file size.
+--   coverage_reports:    one row per (repo, commit, format); re-importing replaces it
+--   coverage_files:      executed and never-executed lines per file
+--   code_block_coverage: the same lines folded onto atomized code block ranges
+
+CREATE TABLE IF NOT EXISTS coverage_reports (
+    id BIGSERIAL PRIMARY KEY,
+    repo_id BIGINT NOT NULL REFERENCES github_repositories(id) ON DELETE CASCADE,
+
+    commit_sha VARCHAR(40) NOT NULL,
+    format VARCHAR(16) NOT NULL,          -- 'go', 'lcov', 'cobertura', 'jacoco'
+    source_file TEXT,                     -- Report path as given on import
+
+    files INTEGER NOT NULL DEFAULT 0,
+    lines_covered INTEGER NOT NULL DEFAULT 0,
+    lines_total INTEGER NOT NULL DEFAULT 0,
+
+    imported_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT coverage_reports_unique UNIQUE (repo_id, commit_sha, format)
+);
+
+CREATE TABLE IF NOT EXISTS coverage_files (
+    report_id BIGINT NOT NULL REFERENCES coverage_reports(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+
+    covered_lines INTEGER[] NOT NULL DEFAULT '{}',
+    uncovered_lines INTEGER[] NOT NULL DEFAULT '{}',
+
+    PRIMARY KEY (report_id, file_path)
+);
+
+CREATE TABLE IF NOT EXISTS code_block_coverage (
+    report_id BIGINT NOT NULL REFERENCES coverage_reports(id) ON DELETE CASCADE,
+    block_id BIGINT NOT NULL REFERENCES code_blocks(id) ON DELETE CASCADE,
+
+    covered_lines INTEGER NOT NULL DEFAULT 0,
+    executable_lines INTEGER NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (report_id, block_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_coverage_reports_commit ON coverage_reports(repo_id, commit_sha);
+CREATE INDEX IF NOT EXISTS idx_code_block_coverage_block ON code_block_coverage(block_id);
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 019 complete: coverage_reports, coverage_files, code_block_coverage tables created';
+END $$;
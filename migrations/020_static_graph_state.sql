@@ -0,0 +1,20 @@
+-- Migration 020: Static dependency graph state
+-- Records the commit the IMPORTS and CALLS edges were last derived from, so
+-- graph builds re-analyze only the files changed since then (and the files that
+-- depend on them) instead of rebuilding every static edge on each ingest.
+
+CREATE TABLE IF NOT EXISTS static_graph_state (
+    repo_id BIGINT PRIMARY KEY REFERENCES github_repositories(id) ON DELETE CASCADE,
+
+    commit_sha VARCHAR(40) NOT NULL,      -- HEAD of the checkout that was analyzed
+    files_analyzed INTEGER NOT NULL DEFAULT 0,
+    import_edges INTEGER NOT NULL DEFAULT 0,
+    call_edges INTEGER NOT NULL DEFAULT 0,
+
+    analyzed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 020 complete: static_graph_state table created';
+END $$;
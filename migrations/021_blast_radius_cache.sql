@@ -0,0 +1,23 @@
+-- Migration 021: Blast radius cache
+-- Stores transitive blast radius results (internal/blastradius) per commit, so
+-- `crisk check` and the MCP server do not re-walk the graph for the same change.
+-- cache_key hashes the changed file set and traversal options; results go stale
+-- only when the graph is rebuilt, which happens at a new commit.
+
+CREATE TABLE IF NOT EXISTS blast_radius_cache (
+    repo_id BIGINT NOT NULL REFERENCES github_repositories(id) ON DELETE CASCADE,
+    commit_sha VARCHAR(40) NOT NULL,
+    cache_key VARCHAR(64) NOT NULL,       -- SHA-256 of files + options
+
+    result JSONB NOT NULL,
+    computed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (repo_id, commit_sha, cache_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blast_radius_cache_computed ON blast_radius_cache(computed_at);
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 021 complete: blast_radius_cache table created';
+END $$;
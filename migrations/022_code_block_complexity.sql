@@ -0,0 +1,19 @@
+-- Migration 022: Code block complexity metrics
+-- Structural metrics (internal/complexity) measured from each block's source at the
+-- commit that last created or modified it during atomization. complexity_estimate
+-- (migration 004) holds cyclomatic complexity; code_block_changes.complexity_delta
+-- records how each change moved it.
+
+ALTER TABLE code_blocks ADD COLUMN IF NOT EXISTS max_nesting_depth INTEGER;
+ALTER TABLE code_blocks ADD COLUMN IF NOT EXISTS parameter_count INTEGER;
+ALTER TABLE code_blocks ADD COLUMN IF NOT EXISTS line_count INTEGER;
+ALTER TABLE code_blocks ADD COLUMN IF NOT EXISTS complexity_measured_sha VARCHAR(40);
+
+CREATE INDEX IF NOT EXISTS idx_code_blocks_complexity
+    ON code_blocks(repo_id, complexity_estimate DESC)
+    WHERE complexity_estimate IS NOT NULL;
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 022 complete: code_blocks complexity columns added';
+END $$;
@@ -0,0 +1,13 @@
+-- Migration 023: Bug-introducing commits per issue
+-- A fix commit can close several issues, and each is analyzed against its own report
+-- date (candidates committed after it are excluded), so SZZ results are kept per issue.
+-- One row per (fix commit, introducing commit, issue, file).
+
+ALTER TABLE bug_introducing_commits DROP CONSTRAINT IF EXISTS bug_introducing_commits_unique;
+ALTER TABLE bug_introducing_commits ADD CONSTRAINT bug_introducing_commits_unique
+    UNIQUE (repo_id, fix_commit_sha, introducing_commit_sha, issue_id, file_path);
+
+DO $$
+BEGIN
+    RAISE NOTICE 'Migration 023 complete: bug_introducing_commits keyed by issue';
+END $$;